@@ -0,0 +1,18 @@
+-- Saved syndication feeds select by several stories, sources, languages,
+-- story categories and NEWS formats, with an optional minimum intelligence
+-- value and explicit exclusion lists. Empty arrays mean "no constraint", so
+-- existing feeds keep their single story_id/content_type behaviour unchanged.
+ALTER TABLE rss_feeds
+  ADD COLUMN IF NOT EXISTS story_ids TEXT[] NOT NULL DEFAULT '{}',
+  ADD COLUMN IF NOT EXISTS content_source_ids TEXT[] NOT NULL DEFAULT '{}',
+  ADD COLUMN IF NOT EXISTS content_languages TEXT[] NOT NULL DEFAULT '{}',
+  ADD COLUMN IF NOT EXISTS story_categories TEXT[] NOT NULL DEFAULT '{}',
+  ADD COLUMN IF NOT EXISTS formats TEXT[] NOT NULL DEFAULT '{}',
+  ADD COLUMN IF NOT EXISTS min_quality DOUBLE PRECISION,
+  ADD COLUMN IF NOT EXISTS exclude_content_ids TEXT[] NOT NULL DEFAULT '{}',
+  ADD COLUMN IF NOT EXISTS exclude_source_ids TEXT[] NOT NULL DEFAULT '{}',
+  ADD COLUMN IF NOT EXISTS exclude_story_ids TEXT[] NOT NULL DEFAULT '{}';
+
+ALTER TABLE rss_feeds DROP CONSTRAINT IF EXISTS rss_feeds_min_quality_range;
+ALTER TABLE rss_feeds ADD CONSTRAINT rss_feeds_min_quality_range
+  CHECK (min_quality IS NULL OR (min_quality >= 0 AND min_quality <= 1));
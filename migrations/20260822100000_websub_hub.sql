@@ -0,0 +1,61 @@
+-- WebSub hub for saved syndication feeds. Subscriptions are keyed by the
+-- canonical topic URL + subscriber callback; intents stay pending until the
+-- callback echoes the hub challenge. Deliveries are a durable outbox written
+-- alongside the READY transition and drained with exponential backoff.
+CREATE TABLE IF NOT EXISTS websub_subscriptions (
+  id BIGSERIAL PRIMARY KEY,
+  public_id UUID NOT NULL DEFAULT gen_random_uuid(),
+  tenant_id VARCHAR(64) NOT NULL,
+  rss_feed_id UUID NOT NULL,
+  topic TEXT NOT NULL,
+  callback TEXT NOT NULL,
+  format VARCHAR(16) NOT NULL,
+  secret TEXT,
+  lease_seconds INTEGER NOT NULL DEFAULT 0,
+  expires_at TIMESTAMPTZ,
+  state VARCHAR(20) NOT NULL DEFAULT 'pending',
+  verified_at TIMESTAMPTZ,
+  pending_mode VARCHAR(16),
+  pending_lease_seconds INTEGER NOT NULL DEFAULT 0,
+  pending_secret TEXT,
+  verify_after TIMESTAMPTZ,
+  verify_attempts INTEGER NOT NULL DEFAULT 0,
+  last_delivery_at TIMESTAMPTZ,
+  consecutive_failures INTEGER NOT NULL DEFAULT 0,
+  last_error TEXT,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  CONSTRAINT websub_subscriptions_state_check
+    CHECK (state IN ('pending', 'active', 'denied', 'expired', 'unsubscribed')),
+  CONSTRAINT websub_subscriptions_format_check
+    CHECK (format IN ('rss', 'atom', 'json', 'podcast'))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_websub_subscriptions_public_id ON websub_subscriptions (public_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_websub_subscriptions_topic_callback ON websub_subscriptions (topic, callback);
+CREATE INDEX IF NOT EXISTS idx_websub_subscriptions_tenant ON websub_subscriptions (tenant_id);
+CREATE INDEX IF NOT EXISTS idx_websub_subscriptions_feed ON websub_subscriptions (rss_feed_id, state);
+CREATE INDEX IF NOT EXISTS idx_websub_subscriptions_verify_due
+  ON websub_subscriptions (verify_after) WHERE pending_mode IS NOT NULL AND pending_mode <> '';
+
+CREATE TABLE IF NOT EXISTS websub_deliveries (
+  id BIGSERIAL PRIMARY KEY,
+  public_id UUID NOT NULL DEFAULT gen_random_uuid(),
+  tenant_id VARCHAR(64) NOT NULL,
+  subscription_id UUID NOT NULL,
+  content_item_id UUID NOT NULL,
+  state VARCHAR(16) NOT NULL DEFAULT 'pending',
+  attempts INTEGER NOT NULL DEFAULT 0,
+  next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  last_status INTEGER,
+  last_error TEXT,
+  delivered_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  CONSTRAINT websub_deliveries_state_check
+    CHECK (state IN ('pending', 'delivered', 'failed', 'dropped'))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_websub_deliveries_subscription_item ON websub_deliveries (subscription_id, content_item_id);
+CREATE INDEX IF NOT EXISTS idx_websub_deliveries_due
+  ON websub_deliveries (next_attempt_at) WHERE state = 'pending';
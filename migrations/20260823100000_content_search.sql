@@ -0,0 +1,127 @@
+-- Full-text search over content items, transcripts and stories.
+--
+-- cms_search_normalize folds Arabic orthographic variants so spelling noise
+-- does not split matches: diacritics (tashkeel, superscript alef) and tatweel
+-- are stripped, alef forms (أ إ آ ٱ) fold to ا, taa marbuta to ha, alef
+-- maqsura and yeh-hamza to yeh, waw-hamza to waw, and a leading definite
+-- article (ال / وال) is dropped from words of 3+ letters. The CMS mirrors the
+-- same rules in Go (normalizeSearchText) for queries and snippet highlighting;
+-- both sides must change together.
+CREATE OR REPLACE FUNCTION cms_search_normalize(input TEXT) RETURNS TEXT
+LANGUAGE sql IMMUTABLE PARALLEL SAFE AS $$
+  SELECT regexp_replace(
+    translate(
+      regexp_replace(lower(coalesce(input, '')), '[\u064B-\u065F\u0670\u0640]', '', 'g'),
+      'أإآٱةىؤئ',
+      'ااااهيوي'
+    ),
+    '(^|\s)و?ال(\S{2,})', '\1\2', 'g'
+  )
+$$;
+
+-- The 'simple' configuration keeps Arabic and English tokens verbatim (no
+-- stemming) after normalization. Bodies are capped so a pathological
+-- document cannot exceed the 1MB tsvector limit.
+CREATE OR REPLACE FUNCTION cms_content_item_search_vector(title TEXT, excerpt TEXT, body_text TEXT, author TEXT, source_name TEXT)
+RETURNS TSVECTOR LANGUAGE sql IMMUTABLE PARALLEL SAFE AS $$
+  SELECT setweight(to_tsvector('simple'::regconfig, cms_search_normalize(title)), 'A') ||
+         setweight(to_tsvector('simple'::regconfig, cms_search_normalize(excerpt)), 'B') ||
+         setweight(to_tsvector('simple'::regconfig, cms_search_normalize(left(body_text, 200000))), 'C') ||
+         setweight(to_tsvector('simple'::regconfig, cms_search_normalize(coalesce(author, '') || ' ' || coalesce(source_name, ''))), 'D')
+$$;
+
+CREATE OR REPLACE FUNCTION cms_transcript_search_vector(full_text TEXT)
+RETURNS TSVECTOR LANGUAGE sql IMMUTABLE PARALLEL SAFE AS $$
+  SELECT to_tsvector('simple'::regconfig, cms_search_normalize(left(full_text, 400000)))
+$$;
+
+CREATE OR REPLACE FUNCTION cms_story_search_vector(label TEXT, summary TEXT)
+RETURNS TSVECTOR LANGUAGE sql IMMUTABLE PARALLEL SAFE AS $$
+  SELECT setweight(to_tsvector('simple'::regconfig, cms_search_normalize(label)), 'A') ||
+         setweight(to_tsvector('simple'::regconfig, cms_search_normalize(summary)), 'B')
+$$;
+
+-- search_vector is a plain nullable column kept current by triggers, not a
+-- STORED generated column: adding one of those rewrites every row of the live
+-- content_items/stories tables under an exclusive lock. New and edited rows
+-- are indexed on write; existing rows are filled by cms_backfill_search_vectors.
+ALTER TABLE content_items ADD COLUMN IF NOT EXISTS search_vector TSVECTOR;
+ALTER TABLE transcripts ADD COLUMN IF NOT EXISTS search_vector TSVECTOR;
+ALTER TABLE stories ADD COLUMN IF NOT EXISTS search_vector TSVECTOR;
+
+CREATE OR REPLACE FUNCTION cms_content_items_search_vector_trigger() RETURNS TRIGGER
+LANGUAGE plpgsql AS $$
+BEGIN
+  NEW.search_vector := cms_content_item_search_vector(NEW.title, NEW.excerpt, NEW.body_text, NEW.author, NEW.source_name);
+  RETURN NEW;
+END
+$$;
+
+CREATE OR REPLACE FUNCTION cms_transcripts_search_vector_trigger() RETURNS TRIGGER
+LANGUAGE plpgsql AS $$
+BEGIN
+  NEW.search_vector := cms_transcript_search_vector(NEW.full_text);
+  RETURN NEW;
+END
+$$;
+
+CREATE OR REPLACE FUNCTION cms_stories_search_vector_trigger() RETURNS TRIGGER
+LANGUAGE plpgsql AS $$
+BEGIN
+  NEW.search_vector := cms_story_search_vector(NEW.label, NEW.summary);
+  RETURN NEW;
+END
+$$;
+
+DROP TRIGGER IF EXISTS trg_content_items_search_vector ON content_items;
+CREATE TRIGGER trg_content_items_search_vector
+  BEFORE INSERT OR UPDATE OF title, excerpt, body_text, author, source_name ON content_items
+  FOR EACH ROW EXECUTE FUNCTION cms_content_items_search_vector_trigger();
+
+DROP TRIGGER IF EXISTS trg_transcripts_search_vector ON transcripts;
+CREATE TRIGGER trg_transcripts_search_vector
+  BEFORE INSERT OR UPDATE OF full_text ON transcripts
+  FOR EACH ROW EXECUTE FUNCTION cms_transcripts_search_vector_trigger();
+
+DROP TRIGGER IF EXISTS trg_stories_search_vector ON stories;
+CREATE TRIGGER trg_stories_search_vector
+  BEFORE INSERT OR UPDATE OF label, summary ON stories
+  FOR EACH ROW EXECUTE FUNCTION cms_stories_search_vector_trigger();
+
+CREATE INDEX IF NOT EXISTS idx_content_items_search_vector ON content_items USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_transcripts_search_vector ON transcripts USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_stories_search_vector ON stories USING GIN (search_vector);
+
+-- Partial indexes make each backfill batch a seek; they empty out (and can be
+-- dropped) once the backfill reports zero.
+CREATE INDEX IF NOT EXISTS idx_content_items_search_vector_pending ON content_items (id) WHERE search_vector IS NULL;
+CREATE INDEX IF NOT EXISTS idx_transcripts_search_vector_pending ON transcripts (id) WHERE search_vector IS NULL;
+CREATE INDEX IF NOT EXISTS idx_stories_search_vector_pending ON stories (id) WHERE search_vector IS NULL;
+
+-- wahb:large-table-backfill: operator-maintenance
+-- Fills at most batch_size rows per table and returns how many it touched.
+-- Operators run it in its own short transactions until it returns 0:
+--   SELECT cms_backfill_search_vectors(5000);
+-- Rows not yet backfilled are simply absent from search results meanwhile.
+CREATE OR REPLACE FUNCTION cms_backfill_search_vectors(batch_size INTEGER DEFAULT 5000) RETURNS INTEGER
+LANGUAGE plpgsql AS $$
+DECLARE
+  touched INTEGER := 0;
+  n INTEGER;
+BEGIN
+  UPDATE content_items SET search_vector = cms_content_item_search_vector(title, excerpt, body_text, author, source_name)
+  WHERE id IN (SELECT id FROM content_items WHERE search_vector IS NULL ORDER BY id LIMIT batch_size);
+  GET DIAGNOSTICS n = ROW_COUNT;
+  touched := touched + n;
+
+  UPDATE transcripts SET search_vector = cms_transcript_search_vector(full_text)
+  WHERE id IN (SELECT id FROM transcripts WHERE search_vector IS NULL ORDER BY id LIMIT batch_size);
+  GET DIAGNOSTICS n = ROW_COUNT;
+  touched := touched + n;
+
+  UPDATE stories SET search_vector = cms_story_search_vector(label, summary)
+  WHERE id IN (SELECT id FROM stories WHERE search_vector IS NULL ORDER BY id LIMIT batch_size);
+  GET DIAGNOSTICS n = ROW_COUNT;
+  RETURN touched + n;
+END
+$$;
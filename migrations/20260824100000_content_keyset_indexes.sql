@@ -0,0 +1,8 @@
+-- Keyset pagination for admin content listings orders by a timestamp plus
+-- public_id as the unique tie-breaker; these composite indexes let the
+-- "after cursor" predicate seek instead of scanning/sorting per page.
+CREATE INDEX IF NOT EXISTS idx_content_items_tenant_published_keyset
+  ON content_items (tenant_id, published_at DESC, public_id DESC);
+
+CREATE INDEX IF NOT EXISTS idx_content_items_tenant_created_keyset
+  ON content_items (tenant_id, created_at DESC, public_id DESC);
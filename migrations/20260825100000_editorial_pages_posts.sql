@@ -0,0 +1,90 @@
+-- Editorial model for pages and posts: tenant scope, per-tenant slugs, a
+-- draft/scheduled/published/archived lifecycle and immutable revisions.
+-- The row's title/content stay the working copy; public reads resolve the
+-- revision named by published_revision.
+ALTER TABLE pages
+  ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+  ADD COLUMN IF NOT EXISTS slug TEXT,
+  ADD COLUMN IF NOT EXISTS status VARCHAR(16) NOT NULL DEFAULT 'draft',
+  ADD COLUMN IF NOT EXISTS author_id VARCHAR(128),
+  ADD COLUMN IF NOT EXISTS current_revision INTEGER NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS published_revision INTEGER,
+  ADD COLUMN IF NOT EXISTS scheduled_revision INTEGER,
+  ADD COLUMN IF NOT EXISTS publish_at TIMESTAMPTZ,
+  ADD COLUMN IF NOT EXISTS unpublish_at TIMESTAMPTZ,
+  ADD COLUMN IF NOT EXISTS published_at TIMESTAMPTZ,
+  ADD COLUMN IF NOT EXISTS archived_at TIMESTAMPTZ;
+
+ALTER TABLE posts
+  ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+  ADD COLUMN IF NOT EXISTS slug TEXT,
+  ADD COLUMN IF NOT EXISTS status VARCHAR(16) NOT NULL DEFAULT 'draft',
+  ADD COLUMN IF NOT EXISTS author_id VARCHAR(128),
+  ADD COLUMN IF NOT EXISTS current_revision INTEGER NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS published_revision INTEGER,
+  ADD COLUMN IF NOT EXISTS scheduled_revision INTEGER,
+  ADD COLUMN IF NOT EXISTS publish_at TIMESTAMPTZ,
+  ADD COLUMN IF NOT EXISTS unpublish_at TIMESTAMPTZ,
+  ADD COLUMN IF NOT EXISTS published_at TIMESTAMPTZ,
+  ADD COLUMN IF NOT EXISTS archived_at TIMESTAMPTZ;
+
+CREATE TABLE IF NOT EXISTS editorial_revisions (
+  id BIGSERIAL PRIMARY KEY,
+  tenant_id VARCHAR(64) NOT NULL,
+  entity_type VARCHAR(16) NOT NULL CHECK (entity_type IN ('page', 'post')),
+  entity_id INTEGER NOT NULL,
+  revision INTEGER NOT NULL,
+  title VARCHAR(255) NOT NULL,
+  content TEXT NOT NULL,
+  author VARCHAR(255),
+  media_ids TEXT[],
+  note TEXT,
+  created_by VARCHAR(128),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_editorial_revisions_entity
+  ON editorial_revisions (entity_type, entity_id, revision);
+
+-- Existing rows were publicly readable before this change, so they become
+-- published at revision 1 with a slug derived from the title plus row id.
+UPDATE pages
+   SET slug = COALESCE(NULLIF(trim(BOTH '-' FROM regexp_replace(lower(title), '[^[:alnum:]]+', '-', 'g')), ''), 'page') || '-' || id
+ WHERE slug IS NULL;
+UPDATE posts
+   SET slug = COALESCE(NULLIF(trim(BOTH '-' FROM regexp_replace(lower(title), '[^[:alnum:]]+', '-', 'g')), ''), 'post') || '-' || id
+ WHERE slug IS NULL;
+
+INSERT INTO editorial_revisions (tenant_id, entity_type, entity_id, revision, title, content, created_at)
+SELECT p.tenant_id, 'page', p.id, 1, p.title, p.content, COALESCE(p.updated_at, now())
+  FROM pages p
+ WHERE p.current_revision = 0
+ON CONFLICT DO NOTHING;
+INSERT INTO editorial_revisions (tenant_id, entity_type, entity_id, revision, title, content, author, media_ids, created_at)
+SELECT p.tenant_id, 'post', p.id, 1, p.title, p.content, p.author,
+       ARRAY(SELECT m.public_id::text FROM post_media pm JOIN media m ON m.id = pm.media_id WHERE pm.post_id = p.id),
+       COALESCE(p.updated_at, now())
+  FROM posts p
+ WHERE p.current_revision = 0
+ON CONFLICT DO NOTHING;
+
+UPDATE pages
+   SET status = 'published', current_revision = 1, published_revision = 1,
+       published_at = COALESCE(updated_at, now())
+ WHERE current_revision = 0;
+UPDATE posts
+   SET status = 'published', current_revision = 1, published_revision = 1,
+       published_at = COALESCE(updated_at, now())
+ WHERE current_revision = 0;
+
+ALTER TABLE pages ALTER COLUMN slug SET NOT NULL;
+ALTER TABLE posts ALTER COLUMN slug SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_pages_tenant_slug ON pages (tenant_id, slug);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_posts_tenant_slug ON posts (tenant_id, slug);
+
+-- The scheduler scans only rows with a pending transition.
+CREATE INDEX IF NOT EXISTS idx_pages_publish_at ON pages (publish_at) WHERE scheduled_revision IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_pages_unpublish_at ON pages (unpublish_at) WHERE unpublish_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_posts_publish_at ON posts (publish_at) WHERE scheduled_revision IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_posts_unpublish_at ON posts (unpublish_at) WHERE unpublish_at IS NOT NULL;
@@ -0,0 +1,26 @@
+-- Server-side content rendering for pages and posts. content stays the
+-- editor's source (Markdown or HTML); content_html is the sanitized output
+-- readers get and excerpt its plain-text lead. Rows written before this
+-- change are HTML with no stored rendering; public reads sanitize them on
+-- the fly until their next save.
+ALTER TABLE pages
+  ADD COLUMN IF NOT EXISTS content_format VARCHAR(16) NOT NULL DEFAULT 'html',
+  ADD COLUMN IF NOT EXISTS content_html TEXT,
+  ADD COLUMN IF NOT EXISTS excerpt TEXT;
+
+ALTER TABLE posts
+  ADD COLUMN IF NOT EXISTS content_format VARCHAR(16) NOT NULL DEFAULT 'html',
+  ADD COLUMN IF NOT EXISTS content_html TEXT,
+  ADD COLUMN IF NOT EXISTS excerpt TEXT,
+  -- Media attached explicitly (media_ids); post_media additionally holds the
+  -- media referenced from the content.
+  ADD COLUMN IF NOT EXISTS attached_media_ids TEXT[];
+
+ALTER TABLE editorial_revisions
+  ADD COLUMN IF NOT EXISTS content_format VARCHAR(16) NOT NULL DEFAULT 'html',
+  ADD COLUMN IF NOT EXISTS content_html TEXT,
+  ADD COLUMN IF NOT EXISTS excerpt TEXT;
+
+UPDATE posts p
+   SET attached_media_ids = ARRAY(SELECT m.public_id::text FROM post_media pm JOIN media m ON m.id = pm.media_id WHERE pm.post_id = p.id ORDER BY m.id)
+ WHERE p.attached_media_ids IS NULL;
@@ -0,0 +1,30 @@
+-- Comment-toxicity classifier stage. Each tenant tunes hold/reject
+-- thresholds per category; moderator outcomes on held comments are counted
+-- so hold thresholds can follow the observed false-positive rate.
+ALTER TABLE user_interactions
+  ADD COLUMN IF NOT EXISTS comment_moderation_signals JSONB;
+
+CREATE TABLE IF NOT EXISTS comment_classifier_configs (
+  id BIGSERIAL PRIMARY KEY,
+  tenant_id VARCHAR(64) NOT NULL,
+  hold_thresholds JSONB,
+  reject_thresholds JSONB,
+  auto_allow_below DOUBLE PRECISION NOT NULL DEFAULT 0,
+  scorer_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+  learning_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+  updated_by VARCHAR(255),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_comment_classifier_configs_tenant_id
+  ON comment_classifier_configs (tenant_id);
+
+CREATE TABLE IF NOT EXISTS comment_classifier_stats (
+  tenant_id VARCHAR(64) NOT NULL,
+  category VARCHAR(32) NOT NULL,
+  confirmed BIGINT NOT NULL DEFAULT 0,
+  overturned BIGINT NOT NULL DEFAULT 0,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (tenant_id, category)
+);
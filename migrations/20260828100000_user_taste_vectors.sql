@@ -0,0 +1,13 @@
+-- Personalized similarity for Pods ranking. One taste vector per identity
+-- ("user:<uuid>" or "session:<id>") in the text embedding space, folded
+-- forward on every like/bookmark/meaningful/complete interaction.
+CREATE TABLE IF NOT EXISTS user_taste_vectors (
+  tenant_id VARCHAR(64) NOT NULL,
+  identity VARCHAR(300) NOT NULL,
+  embedding vector(1024),
+  embedding_space_id CHAR(64) NOT NULL,
+  weight DOUBLE PRECISION NOT NULL,
+  interactions INTEGER NOT NULL,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (tenant_id, identity)
+);
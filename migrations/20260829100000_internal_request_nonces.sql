@@ -0,0 +1,12 @@
+-- Replay cache for HMAC-signed /internal requests. A row lives only until its
+-- request timestamp leaves the clock-skew window; the middleware purges
+-- expired rows opportunistically.
+CREATE TABLE IF NOT EXISTS internal_request_nonces (
+  credential_id VARCHAR(128) NOT NULL,
+  nonce VARCHAR(128) NOT NULL,
+  expires_at TIMESTAMPTZ NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (credential_id, nonce)
+);
+
+CREATE INDEX IF NOT EXISTS idx_internal_request_nonces_expires_at ON internal_request_nonces (expires_at);
@@ -0,0 +1,35 @@
+-- First-class comments: reply threading, author edits with retained history,
+-- tombstones for removed comments that have replies, and per-comment
+-- reactions. Comments remain user_interactions rows of type 'comment'.
+ALTER TABLE user_interactions
+  ADD COLUMN IF NOT EXISTS comment_parent_id UUID,
+  ADD COLUMN IF NOT EXISTS comment_root_id UUID,
+  ADD COLUMN IF NOT EXISTS comment_depth INTEGER NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS comment_score INTEGER NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS comment_edited_at TIMESTAMPTZ,
+  ADD COLUMN IF NOT EXISTS comment_deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_user_interactions_comment_parent_id ON user_interactions (comment_parent_id);
+CREATE INDEX IF NOT EXISTS idx_user_interactions_comment_root_id ON user_interactions (comment_root_id);
+CREATE INDEX IF NOT EXISTS idx_user_interactions_comment_deleted_at ON user_interactions (comment_deleted_at);
+
+CREATE TABLE IF NOT EXISTS comment_revisions (
+  id BIGSERIAL PRIMARY KEY,
+  comment_id UUID NOT NULL,
+  text TEXT NOT NULL,
+  edited_by UUID NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_comment_revisions_comment_id ON comment_revisions (comment_id);
+
+CREATE TABLE IF NOT EXISTS comment_reactions (
+  comment_id UUID NOT NULL,
+  user_id UUID NOT NULL,
+  kind VARCHAR(16) NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (comment_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_comment_reactions_user_id ON comment_reactions (user_id);
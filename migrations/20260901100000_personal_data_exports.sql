@@ -0,0 +1,25 @@
+-- Asynchronous personal data exports. The archive is kept in the row until
+-- expires_at, then cleared by the export worker.
+CREATE TABLE IF NOT EXISTS personal_data_exports (
+  id BIGSERIAL PRIMARY KEY,
+  public_id UUID NOT NULL DEFAULT gen_random_uuid(),
+  tenant_id VARCHAR(64) NOT NULL,
+  user_id UUID NOT NULL,
+  status VARCHAR(16) NOT NULL DEFAULT 'queued',
+  attempts INTEGER NOT NULL DEFAULT 0,
+  lease_until TIMESTAMPTZ,
+  last_error TEXT,
+  archive BYTEA,
+  archive_sha256 CHAR(64),
+  archive_bytes BIGINT NOT NULL DEFAULT 0,
+  completed_at TIMESTAMPTZ,
+  expires_at TIMESTAMPTZ,
+  downloads INTEGER NOT NULL DEFAULT 0,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_personal_data_exports_public_id ON personal_data_exports (public_id);
+CREATE INDEX IF NOT EXISTS idx_personal_data_exports_user ON personal_data_exports (tenant_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_personal_data_exports_status ON personal_data_exports (status);
+CREATE INDEX IF NOT EXISTS idx_personal_data_exports_expires_at ON personal_data_exports (expires_at);
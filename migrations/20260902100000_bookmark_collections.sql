@@ -0,0 +1,34 @@
+-- Named, ordered bookmark collections with per-item notes and optional
+-- public share links.
+CREATE TABLE IF NOT EXISTS bookmark_collections (
+  id BIGSERIAL PRIMARY KEY,
+  public_id UUID NOT NULL DEFAULT gen_random_uuid(),
+  tenant_id VARCHAR(64) NOT NULL,
+  user_id UUID NOT NULL,
+  name VARCHAR(80) NOT NULL,
+  description TEXT NOT NULL DEFAULT '',
+  item_count INTEGER NOT NULL DEFAULT 0,
+  share_token VARCHAR(64),
+  shared_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bookmark_collections_public_id ON bookmark_collections (public_id);
+CREATE INDEX IF NOT EXISTS idx_bookmark_collections_user ON bookmark_collections (tenant_id, user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bookmark_collections_share_token ON bookmark_collections (share_token);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bookmark_collections_user_name ON bookmark_collections (tenant_id, user_id, lower(name));
+
+CREATE TABLE IF NOT EXISTS bookmark_collection_items (
+  id BIGSERIAL PRIMARY KEY,
+  collection_id UUID NOT NULL REFERENCES bookmark_collections (public_id) ON DELETE CASCADE,
+  content_item_id UUID NOT NULL,
+  position INTEGER NOT NULL DEFAULT 0,
+  note TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bookmark_collection_items_content ON bookmark_collection_items (collection_id, content_item_id);
+CREATE INDEX IF NOT EXISTS idx_bookmark_collection_items_position ON bookmark_collection_items (collection_id, position);
+CREATE INDEX IF NOT EXISTS idx_bookmark_collection_items_content_item_id ON bookmark_collection_items (content_item_id);
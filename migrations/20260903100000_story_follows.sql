@@ -0,0 +1,26 @@
+-- Per-reader story follows with a "last looked" mark, and a history of
+-- story digests for the timeline.
+CREATE TABLE IF NOT EXISTS story_follows (
+  id BIGSERIAL PRIMARY KEY,
+  tenant_id VARCHAR(64) NOT NULL,
+  user_id UUID NOT NULL,
+  story_id UUID NOT NULL,
+  last_seen_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_story_follows_user_story ON story_follows (tenant_id, user_id, story_id);
+CREATE INDEX IF NOT EXISTS idx_story_follows_story_id ON story_follows (story_id);
+
+CREATE TABLE IF NOT EXISTS story_summary_revisions (
+  id BIGSERIAL PRIMARY KEY,
+  story_id UUID NOT NULL,
+  summary TEXT,
+  bullets JSONB,
+  category TEXT,
+  member_count INTEGER NOT NULL DEFAULT 0,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_story_summary_revisions_story ON story_summary_revisions (story_id, created_at);
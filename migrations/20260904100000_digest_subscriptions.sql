@@ -0,0 +1,49 @@
+-- Scheduled reader digests: one subscription per reader and a per-period
+-- send ledger.
+CREATE TABLE IF NOT EXISTS digest_subscriptions (
+  id BIGSERIAL PRIMARY KEY,
+  tenant_id VARCHAR(64) NOT NULL,
+  user_id UUID NOT NULL,
+  enabled BOOLEAN NOT NULL DEFAULT FALSE,
+  cadence VARCHAR(8) NOT NULL DEFAULT 'daily',
+  language VARCHAR(4) NOT NULL DEFAULT 'ar',
+  email VARCHAR(255),
+  timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+  send_hour INTEGER NOT NULL DEFAULT 7,
+  weekly_day INTEGER NOT NULL DEFAULT 0,
+  unsubscribe_token VARCHAR(64) NOT NULL,
+  next_due_at TIMESTAMPTZ,
+  last_sent_at TIMESTAMPTZ,
+  lease_until TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_digest_subscriptions_user ON digest_subscriptions (tenant_id, user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_digest_subscriptions_unsubscribe_token ON digest_subscriptions (unsubscribe_token);
+CREATE INDEX IF NOT EXISTS idx_digest_subscriptions_next_due_at ON digest_subscriptions (next_due_at);
+
+CREATE TABLE IF NOT EXISTS digest_deliveries (
+  id BIGSERIAL PRIMARY KEY,
+  public_id UUID NOT NULL DEFAULT gen_random_uuid(),
+  subscription_id BIGINT NOT NULL,
+  period_key VARCHAR(32) NOT NULL,
+  tenant_id VARCHAR(64) NOT NULL,
+  user_id UUID NOT NULL,
+  cadence VARCHAR(8) NOT NULL,
+  sender VARCHAR(16) NOT NULL,
+  status VARCHAR(16) NOT NULL DEFAULT 'pending',
+  attempts INTEGER NOT NULL DEFAULT 0,
+  subject TEXT,
+  story_count INTEGER NOT NULL DEFAULT 0,
+  pod_count INTEGER NOT NULL DEFAULT 0,
+  content_ids JSONB,
+  last_error TEXT,
+  sent_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_digest_deliveries_public_id ON digest_deliveries (public_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_digest_deliveries_period ON digest_deliveries (subscription_id, period_key);
+CREATE INDEX IF NOT EXISTS idx_digest_deliveries_user ON digest_deliveries (tenant_id, user_id);
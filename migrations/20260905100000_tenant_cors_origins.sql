@@ -0,0 +1,12 @@
+-- Browser origins a tenant admits for one CORS route group, on top of the
+-- deployment's CORS_*_ORIGINS allowlist.
+CREATE TABLE IF NOT EXISTS tenant_cors_origins (
+  id BIGSERIAL PRIMARY KEY,
+  tenant_id VARCHAR(64) NOT NULL,
+  route_group VARCHAR(16) NOT NULL CHECK (route_group IN ('public', 'consumer', 'admin')),
+  origin VARCHAR(255) NOT NULL,
+  created_by VARCHAR(255),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tenant_cors_origins_origin ON tenant_cors_origins (tenant_id, route_group, origin);
@@ -0,0 +1,14 @@
+-- Media rows gain an owning tenant so editorial writes can only attach or
+-- reference their own tenant's media. New rows are stamped from the admin
+-- principal; existing rows linked to exactly one tenant's posts adopt that
+-- tenant. Rows that stay NULL predate tenancy and remain a shared library.
+ALTER TABLE media ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64);
+CREATE INDEX IF NOT EXISTS idx_media_tenant ON media (tenant_id);
+
+UPDATE media m
+   SET tenant_id = owners.tenant_id
+  FROM (SELECT pm.media_id, MIN(p.tenant_id) AS tenant_id
+          FROM post_media pm JOIN posts p ON p.id = pm.post_id
+         GROUP BY pm.media_id
+        HAVING COUNT(DISTINCT p.tenant_id) = 1) owners
+ WHERE m.id = owners.media_id AND m.tenant_id IS NULL;
@@ -0,0 +1,14 @@
+-- Digests are only mailed to an address the reader confirmed by following a
+-- link sent to it. Addresses saved before this change are queued for
+-- confirmation too: until then their digests are skipped as having no
+-- address.
+ALTER TABLE digest_subscriptions
+  ADD COLUMN IF NOT EXISTS email_confirmed_at TIMESTAMPTZ,
+  ADD COLUMN IF NOT EXISTS email_confirm_token VARCHAR(64),
+  ADD COLUMN IF NOT EXISTS email_confirm_sent_at TIMESTAMPTZ;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_digest_subscriptions_email_confirm_token ON digest_subscriptions (email_confirm_token);
+
+UPDATE digest_subscriptions
+SET email_confirm_token = replace(gen_random_uuid()::text, '-', '') || replace(gen_random_uuid()::text, '-', '')
+WHERE COALESCE(email, '') <> '' AND email_confirmed_at IS NULL AND email_confirm_token IS NULL;
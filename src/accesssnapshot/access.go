@@ -15,6 +15,8 @@ import (
 	"sort"
 	"strings"
 	"time"
+
+	"content-management-system/src/tracing"
 )
 
 var ErrUnavailable = errors.New("current IAM access is unavailable")
@@ -76,7 +78,7 @@ func NewIAMClient(baseURL, token string, client *http.Client) (*IAMClient, error
 		return nil, fmt.Errorf("%w: CMS machine identity is not configured", ErrUnavailable)
 	}
 	if client == nil {
-		client = &http.Client{Timeout: 5 * time.Second}
+		client = &http.Client{Transport: tracing.Transport(nil), Timeout: 5 * time.Second}
 	}
 	return &IAMClient{baseURL: strings.TrimRight(parsed.String(), "/"), token: token, client: client}, nil
 }
@@ -887,7 +887,10 @@ func reduceReadiness(tx *gorm.DB, tenantID string, contentID uuid.UUID, generati
 	if err := feedstate.AttachReadyNewsStory(tx, item); err != nil {
 		return err
 	}
-	return feedstate.SyncMediaMembership(tx, item)
+	if err := feedstate.SyncMediaMembership(tx, item); err != nil {
+		return err
+	}
+	return feedstate.CommitReady(tx, item)
 }
 
 func laneForType(kind models.ContentType) string {
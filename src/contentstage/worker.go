@@ -1,10 +1,12 @@
 package contentstage
 
 import (
+	"context"
 	"log"
 	"sync/atomic"
 	"time"
 
+	"content-management-system/src/lifecycle"
 	"content-management-system/src/models"
 
 	"github.com/google/uuid"
@@ -25,17 +27,17 @@ type ClassifyFunc func(*gorm.DB, uuid.UUID)
 // StartWorker performs deterministic CMS-owned classification, lease recovery,
 // and artifact verification. It never invokes an external model directly and
 // never dispatches Aggregation/Media effects.
-func StartWorker(db *gorm.DB, classify ClassifyFunc) {
+func StartWorker(ctx context.Context, db *gorm.DB, classify ClassifyFunc) {
 	runWorkerOnce(db, nil)
-	go func() {
+	lifecycle.Go(ctx, "content-stage", func(ctx context.Context) {
 		ticker := time.NewTicker(2 * time.Second)
 		defer ticker.Stop()
-		for range ticker.C {
+		for lifecycle.Wait(ctx, ticker.C) {
 			runWorkerOnce(db, nil)
 		}
-	}()
+	})
 	if classify != nil {
-		go func() {
+		lifecycle.Go(ctx, "content-stage-classifier", func(ctx context.Context) {
 			ticker := time.NewTicker(2 * time.Second)
 			defer ticker.Stop()
 			for {
@@ -44,9 +46,11 @@ func StartWorker(db *gorm.DB, classify ClassifyFunc) {
 				} else if _, err := runCMSStageOne(db, classify); err != nil {
 					log.Printf("content-stage CMS execution failed: %v", err)
 				}
-				<-ticker.C
+				if !lifecycle.Wait(ctx, ticker.C) {
+					return
+				}
 			}
-		}()
+		})
 	}
 }
 
@@ -77,6 +81,10 @@ func WorkerHealthy(now time.Time) bool {
 	return at > 0 && now.UTC().Sub(time.Unix(0, at).UTC()) <= 90*time.Second
 }
 
+func WorkerLastHeartbeat() time.Time {
+	return lifecycle.HeartbeatTime(workerHeartbeat.Load())
+}
+
 func runWorkerOnce(db *gorm.DB, classify ClassifyFunc) {
 	if err := RecoverExpired(db); err != nil {
 		log.Printf("content-stage lease recovery failed: %v", err)
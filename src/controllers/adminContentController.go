@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"content-management-system/src/feedstate"
 	"content-management-system/src/models"
 	"content-management-system/src/utils"
 	"encoding/json"
@@ -14,14 +15,19 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type adminContentListResponse struct {
-	Data       []adminContentItemResponse `json:"data"`
-	Total      int64                      `json:"total"`
-	Page       int                        `json:"page"`
-	Limit      int                        `json:"limit"`
-	TotalPages int                        `json:"total_pages"`
+	Data           []adminContentItemResponse `json:"data"`
+	Total          int64                      `json:"total"`
+	Page           int                        `json:"page"`
+	Limit          int                        `json:"limit"`
+	TotalPages     int                        `json:"total_pages"`
+	CountMode      utils.CountMode            `json:"count_mode"`
+	TotalEstimated bool                       `json:"total_estimated,omitempty"`
+	HasNext        bool                       `json:"has_next"`
+	NextCursor     *string                    `json:"next_cursor,omitempty"`
 }
 
 type mediaSizeAggregateResponse struct {
@@ -175,7 +181,20 @@ var contentAdminQueryConfig = utils.QueryConfig{
 		"like_count":      "content_items.like_count",
 		"view_count":      "content_items.view_count",
 		"share_count":     "content_items.share_count",
+		"id":              "content_items.public_id",
 	},
+	// Time-ordered listings page by cursor (?cursor=) instead of OFFSET; pair
+	// with ?count=estimated|none to skip the full COUNT(*) on content_items.
+	// Casts match the canonical column types (all three timestamps are
+	// TIMESTAMPTZ), so cursor comparisons do not depend on the session TimeZone.
+	KeysetFields: map[string]string{
+		"created_at":   "timestamptz",
+		"updated_at":   "timestamptz",
+		"published_at": "timestamptz",
+		"id":           "uuid",
+	},
+	KeysetTieBreaker: "id",
+	EstimateTable:    "content_items",
 	FilterableFields: map[string]string{
 		"status":        "content_items.status",
 		"type":          "content_items.type",
@@ -183,8 +202,14 @@ var contentAdminQueryConfig = utils.QueryConfig{
 		"source_id":     "content_items.source_feed_url",
 		"source_name":   "content_items.source_name",
 		"created_at":    "content_items.created_at",
+		"updated_at":    "content_items.updated_at",
 		"published_at":  "content_items.published_at",
 	},
+	// filter= expressions additionally reach the legacy topic tags and the
+	// free-form metadata document.
+	TimeFields:  map[string]bool{"created_at": true, "updated_at": true, "published_at": true},
+	ArrayFields: map[string]string{"topic_tags": "content_items.topic_tags"},
+	JSONFields:  map[string]string{"metadata": "content_items.metadata"},
 	SearchableFields: map[string]string{
 		"title":   "content_items.title",
 		"excerpt": "content_items.excerpt",
@@ -245,11 +270,15 @@ func ListContentItems(c *gin.Context) {
 	populateAdminContentTranscriptionBatch(db, items, data)
 
 	c.JSON(http.StatusOK, adminContentListResponse{
-		Data:       data,
-		Total:      meta.Total,
-		Page:       meta.Page,
-		Limit:      meta.Limit,
-		TotalPages: meta.TotalPages,
+		Data:           data,
+		Total:          meta.Total,
+		Page:           meta.Page,
+		Limit:          meta.Limit,
+		TotalPages:     meta.TotalPages,
+		CountMode:      meta.CountMode,
+		TotalEstimated: meta.TotalEstimated,
+		HasNext:        meta.HasNext,
+		NextCursor:     meta.NextCursor,
 	})
 }
 
@@ -1200,6 +1229,34 @@ var validContentStatuses = map[string]bool{
 	"PENDING": true, "PROCESSING": true, "READY": true, "FAILED": true, "ARCHIVED": true,
 }
 
+// updateContentStatus moves the rows selected by scope to toStatus in one
+// transaction. A READY move returns the updated rows and passes each through
+// feedstate.CommitReady, so bulk transitions reach the WebSub outbox exactly
+// like single-item ones.
+func updateContentStatus(db *gorm.DB, scope func(*gorm.DB) *gorm.DB, toStatus string) (int64, error) {
+	var updated int64
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if toStatus != string(models.ContentStatusReady) {
+			result := scope(tx.Model(&models.ContentItem{})).Update("status", toStatus)
+			updated = result.RowsAffected
+			return result.Error
+		}
+		var items []models.ContentItem
+		result := scope(tx.Model(&items).Clauses(clause.Returning{})).Update("status", toStatus)
+		if result.Error != nil {
+			return result.Error
+		}
+		updated = result.RowsAffected
+		for _, item := range items {
+			if err := feedstate.CommitReady(tx, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return updated, err
+}
+
 // BulkStatusChange handles POST /admin/content/bulk-status
 // Moves content items into to_status, selected either by explicit ids or by a
 // filter (from_status [+ source_name/type/created_before]).
@@ -1252,17 +1309,19 @@ func BulkStatusChange(c *gin.Context) {
 			return
 		}
 
-		result := query.Update("status", toStatus)
-		if result.Error != nil {
+		updated, err := updateContentStatus(db, func(q *gorm.DB) *gorm.DB {
+			return q.Where("tenant_id = ? AND public_id IN ?", principal.TenantID, req.IDs)
+		}, toStatus)
+		if err != nil {
 			c.JSON(http.StatusInternalServerError, authErrorResponse{
-				Message: "Failed to update status: " + result.Error.Error(),
+				Message: "Failed to update status: " + err.Error(),
 				Code:    "UPDATE_FAILED",
 			})
 			return
 		}
 
 		c.JSON(http.StatusOK, bulkStatusChangeResponse{
-			UpdatedCount: result.RowsAffected,
+			UpdatedCount: updated,
 			Message:      "Updated selected items to " + strings.ToLower(toStatus),
 		})
 		return
@@ -1344,28 +1403,25 @@ func BulkStatusChange(c *gin.Context) {
 		return
 	}
 
-	var result *gorm.DB
+	// Uncapped — update the entire matching set in a single statement.
+	scope := applyFilters
 	if limit > 0 {
 		// Bounded update — subquery caps the number of rows touched.
-		subQuery := applyFilters(db.Model(&models.ContentItem{}).Select("id")).Limit(limit)
-		result = db.Model(&models.ContentItem{}).
-			Where("id IN (?)", subQuery).
-			Update("status", toStatus)
-	} else {
-		// Uncapped — update the entire matching set in a single statement.
-		result = applyFilters(db.Model(&models.ContentItem{})).Update("status", toStatus)
+		scope = func(q *gorm.DB) *gorm.DB {
+			return q.Where("id IN (?)", applyFilters(db.Model(&models.ContentItem{}).Select("id")).Limit(limit))
+		}
 	}
-
-	if result.Error != nil {
+	updated, err := updateContentStatus(db, scope, toStatus)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, authErrorResponse{
-			Message: "Failed to update status: " + result.Error.Error(),
+			Message: "Failed to update status: " + err.Error(),
 			Code:    "UPDATE_FAILED",
 		})
 		return
 	}
 
 	c.JSON(http.StatusOK, bulkStatusChangeResponse{
-		UpdatedCount: result.RowsAffected,
+		UpdatedCount: updated,
 		Message:      "Updated " + strings.ToLower(fromStatus) + " items to " + strings.ToLower(toStatus),
 	})
 }
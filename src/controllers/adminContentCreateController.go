@@ -1,6 +1,8 @@
 package controllers
 
 import (
+	"content-management-system/src/feedstate"
+	"content-management-system/src/lifecycle"
 	"content-management-system/src/models"
 	"crypto/sha256"
 	"encoding/hex"
@@ -134,7 +136,12 @@ func createManualArticle(db *gorm.DB, tenantID string, req createAdminContentReq
 		Metadata:       datatypes.JSON(metadataJSON),
 		PublishedAt:    publishedAt,
 	}
-	if err := db.Create(&item).Error; err != nil {
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&item).Error; err != nil {
+			return err
+		}
+		return feedstate.CommitReady(tx, item)
+	}); err != nil {
 		return models.ContentItem{}, false, err
 	}
 
@@ -142,7 +149,7 @@ func createManualArticle(db *gorm.DB, tenantID string, req createAdminContentReq
 		if text := buildEmbeddingText(&item); strings.TrimSpace(text) != "" {
 			id := item.PublicID.String()
 			go func() {
-				_ = triggerEmbedding(text, id)
+				_ = triggerEmbedding(lifecycle.Root(), text, id)
 			}()
 		}
 	}
@@ -215,7 +222,7 @@ func ImportFeed(c *gin.Context) {
 		return
 	}
 
-	feed, err := extractFeedViaEnrichment(strings.TrimSpace(req.URL))
+	feed, err := extractFeedViaEnrichment(c.Request.Context(), strings.TrimSpace(req.URL))
 	if err != nil {
 		c.JSON(http.StatusBadGateway, authErrorResponse{Message: "Failed to read feed: " + err.Error(), Code: "FEED_EXTRACT_FAILED"})
 		return
@@ -287,7 +294,7 @@ func ExtractContentURL(c *gin.Context) {
 		return
 	}
 
-	result, err := extractURLViaEnrichment(strings.TrimSpace(req.URL))
+	result, err := extractURLViaEnrichment(c.Request.Context(), strings.TrimSpace(req.URL))
 	if err != nil {
 		c.JSON(http.StatusBadGateway, authErrorResponse{
 			Message: "Failed to extract URL: " + err.Error(),
@@ -3,6 +3,7 @@ package controllers
 import (
 	"content-management-system/src/models"
 	"content-management-system/src/utils"
+	"context"
 	"encoding/json"
 	"net/http"
 	"os"
@@ -993,7 +994,7 @@ func ImportYouTube(c *gin.Context) {
 		"tenantId":  principal.TenantID,
 		"keywords":  keywords,
 	}
-	body, status, err := proxyAggregationRequest(aggregationBaseURL, "/admin/discovery/import-youtube", c.GetHeader("Authorization"), payload)
+	body, status, err := proxyAggregationRequest(c.Request.Context(), aggregationBaseURL, "/admin/discovery/import-youtube", c.GetHeader("Authorization"), payload)
 	if err != nil {
 		c.JSON(http.StatusBadGateway, authErrorResponse{Message: "Aggregation request failed: " + err.Error(), Code: "AGGREGATION_FAILED"})
 		return
@@ -1061,7 +1062,7 @@ func ImportYouTubeLinks(c *gin.Context) {
 		"tenantId":  principal.TenantID,
 		"keywords":  keywords,
 	}
-	body, status, err := proxyAggregationRequest(aggregationBaseURL, "/admin/discovery/import-youtube-links", c.GetHeader("Authorization"), payload)
+	body, status, err := proxyAggregationRequest(c.Request.Context(), aggregationBaseURL, "/admin/discovery/import-youtube-links", c.GetHeader("Authorization"), payload)
 	if err != nil {
 		c.JSON(http.StatusBadGateway, authErrorResponse{Message: "Aggregation request failed: " + err.Error(), Code: "AGGREGATION_FAILED"})
 		return
@@ -1083,7 +1084,7 @@ func proxyAggregationSimple(c *gin.Context, path string) {
 		c.JSON(http.StatusServiceUnavailable, authErrorResponse{Message: "Aggregation service URL is not configured", Code: "AGGREGATION_NOT_CONFIGURED"})
 		return
 	}
-	body, status, err := proxyAggregationRequest(aggregationBaseURL, path, c.GetHeader("Authorization"), map[string]interface{}{})
+	body, status, err := proxyAggregationRequest(c.Request.Context(), aggregationBaseURL, path, c.GetHeader("Authorization"), map[string]interface{}{})
 	if err != nil {
 		c.JSON(http.StatusBadGateway, authErrorResponse{Message: "Aggregation request failed: " + err.Error(), Code: "AGGREGATION_FAILED"})
 		return
@@ -1135,7 +1136,7 @@ func triggerAggregationResync(authHeader string) {
 	if aggregationBaseURL == "" {
 		return
 	}
-	_, _, _ = proxyAggregationRequest(aggregationBaseURL, "/admin/discovery/resync-schedule", authHeader, map[string]interface{}{})
+	_, _, _ = proxyAggregationRequest(context.Background(), aggregationBaseURL, "/admin/discovery/resync-schedule", authHeader, map[string]interface{}{})
 }
 
 // ---------- helpers ----------
@@ -1853,7 +1854,7 @@ func triggerSourceFirstFetch(db *gorm.DB, authHeader string, source *models.Cont
 }
 
 func triggerAggregationDiscoveryRun(aggregationBaseURL, authorizationHeader string, payload aggregationDiscoveryRequest) (aggregationTriggerResponse, error) {
-	requestBody, statusCode, err := proxyAggregationRequest(aggregationBaseURL, "/admin/discovery/run", authorizationHeader, payload)
+	requestBody, statusCode, err := proxyAggregationRequest(context.Background(), aggregationBaseURL, "/admin/discovery/run", authorizationHeader, payload)
 	if err != nil {
 		return aggregationTriggerResponse{}, err
 	}
@@ -1,8 +1,10 @@
 package controllers
 
 import (
+	"content-management-system/src/lifecycle"
 	"content-management-system/src/models"
 	"content-management-system/src/utils"
+	"context"
 	"fmt"
 	"net/http"
 	"regexp"
@@ -307,7 +309,7 @@ func TriggerEnrichment(c *gin.Context) {
 		return
 	}
 
-	results, errors := triggerItemArtifacts(db, &item, req.Types, req.Force)
+	results, errors := triggerItemArtifacts(c.Request.Context(), db, &item, req.Types, req.Force)
 
 	c.JSON(http.StatusOK, utils.ResponseMessage{
 		Code:    http.StatusOK,
@@ -362,7 +364,7 @@ func TriggerBatchEnrichment(c *gin.Context) {
 			continue
 		}
 
-		_, itemErrors := triggerItemArtifacts(db, &item, req.Types, false)
+		_, itemErrors := triggerItemArtifacts(c.Request.Context(), db, &item, req.Types, false)
 
 		if len(itemErrors) > 0 {
 			results = append(results, triggerResultItem{
@@ -535,7 +537,7 @@ type artifactOutcome struct {
 // derivation). `force` only affects the transcript (STT) pass: it bypasses the
 // guard's toggle + state-machine checks for a manual upgrade (budget cap still
 // applies). This is the single place the per-artifact logic lives.
-func triggerItemArtifactsTraced(db *gorm.DB, item *models.ContentItem, types []string, force bool, triggerSource string) []artifactOutcome {
+func triggerItemArtifactsTraced(ctx context.Context, db *gorm.DB, item *models.ContentItem, types []string, force bool, triggerSource string) []artifactOutcome {
 	id := item.PublicID.String()
 	out := make([]artifactOutcome, 0, len(types))
 	for _, enrichType := range types {
@@ -551,7 +553,7 @@ func triggerItemArtifactsTraced(db *gorm.DB, item *models.ContentItem, types []s
 				o.Status, o.Reason = artifactOutcomeSkipped, "not VIDEO/PODCAST"
 			} else if item.MediaURL == nil || *item.MediaURL == "" {
 				o.Status, o.Reason = artifactOutcomeError, "no media_url available"
-			} else if jobID, err := triggerTranscription(item, db, force, triggerSource); err != nil {
+			} else if jobID, err := triggerTranscription(ctx, item, db, force, triggerSource); err != nil {
 				if isSTTSkipped(err) {
 					o.Status, o.Reason = artifactOutcomeSkipped, err.Error()
 					o.SkipKind = string(sttSkipKindOf(err))
@@ -569,7 +571,7 @@ func triggerItemArtifactsTraced(db *gorm.DB, item *models.ContentItem, types []s
 				o.Status, o.Reason = artifactOutcomeAlready, "already exists"
 			} else if text := buildEmbeddingText(item); text == "" {
 				o.Status, o.Reason = artifactOutcomeError, "no text content available"
-			} else if err := triggerEmbedding(text, id); err != nil {
+			} else if err := triggerEmbedding(ctx, text, id); err != nil {
 				o.Status, o.Reason = artifactOutcomeError, err.Error()
 			} else {
 				o.Status = artifactOutcomeTriggered
@@ -582,7 +584,7 @@ func triggerItemArtifactsTraced(db *gorm.DB, item *models.ContentItem, types []s
 				o.Status, o.Reason = artifactOutcomeAlready, "already exists"
 			} else if item.ThumbnailURL == nil || *item.ThumbnailURL == "" {
 				o.Status, o.Reason = artifactOutcomeError, "no thumbnail_url available"
-			} else if err := triggerImageEmbedding(*item.ThumbnailURL, id); err != nil {
+			} else if err := triggerImageEmbedding(ctx, *item.ThumbnailURL, id); err != nil {
 				o.Status, o.Reason = artifactOutcomeError, err.Error()
 			} else {
 				o.Status = artifactOutcomeTriggered
@@ -596,8 +598,8 @@ func triggerItemArtifactsTraced(db *gorm.DB, item *models.ContentItem, types []s
 // triggerItemArtifacts is the string-shaped wrapper kept for the single, batch,
 // and bulk human trigger paths so their behaviour is identical. Already-present
 // artifacts are reported as skips (in results), not errors.
-func triggerItemArtifacts(db *gorm.DB, item *models.ContentItem, types []string, force bool) (results, errs []string) {
-	for _, o := range triggerItemArtifactsTraced(db, item, types, force, "") {
+func triggerItemArtifacts(ctx context.Context, db *gorm.DB, item *models.ContentItem, types []string, force bool) (results, errs []string) {
+	for _, o := range triggerItemArtifactsTraced(ctx, db, item, types, force, "") {
 		switch o.Status {
 		case artifactOutcomeError:
 			errs = append(errs, o.Artifact+": "+o.Reason)
@@ -731,7 +733,7 @@ func TriggerAllEnrichment(c *gin.Context) {
 // (one model call at a time) instead of stampeding them.
 func runBulkEnrich(db *gorm.DB, items []models.ContentItem, types []string) {
 	for i := range items {
-		_, errs := triggerItemArtifacts(db, &items[i], types, false)
+		_, errs := triggerItemArtifacts(lifecycle.Root(), db, &items[i], types, false)
 		bulkMu.Lock()
 		bulkState.Done++
 		if len(errs) > 0 {
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"content-management-system/src/models"
+	"encoding/json"
 	"net/http"
 	"os"
 	"regexp"
@@ -10,14 +11,16 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"gorm.io/gorm"
 )
 
 type rssFeedResponse struct {
 	models.RSSFeed
-	RSSURL  string `json:"rss_url"`
-	AtomURL string `json:"atom_url"`
-	JSONURL string `json:"json_url"`
+	RSSURL     string `json:"rss_url"`
+	AtomURL    string `json:"atom_url"`
+	JSONURL    string `json:"json_url"`
+	PodcastURL string `json:"podcast_url"`
 }
 
 type rssFeedListResponse struct {
@@ -49,10 +52,11 @@ func publicBaseFor(c *gin.Context) string {
 func feedToResponse(base string, f models.RSSFeed) rssFeedResponse {
 	saved := base + "/api/v1/feed/saved/" + f.Slug
 	return rssFeedResponse{
-		RSSFeed: f,
-		RSSURL:  saved,
-		AtomURL: saved + "?format=atom",
-		JSONURL: saved + "?format=json",
+		RSSFeed:    f,
+		RSSURL:     saved,
+		AtomURL:    saved + "?format=atom",
+		JSONURL:    saved + "?format=json",
+		PodcastURL: saved + "?format=podcast",
 	}
 }
 
@@ -107,6 +111,122 @@ func ListRSSFeeds(c *gin.Context) {
 	c.JSON(http.StatusOK, rssFeedListResponse{Data: data, PublicBase: base})
 }
 
+// rssFeedFilterRequest carries the curation filters shared by create and
+// update. A nil list leaves the stored value untouched; an empty list clears
+// it. min_quality: null clears, a number in [0,1] sets.
+type rssFeedFilterRequest struct {
+	StoryIDs          *[]string       `json:"story_ids"`
+	ContentSourceIDs  *[]string       `json:"content_source_ids"`
+	ContentLanguages  *[]string       `json:"content_languages"`
+	StoryCategories   *[]string       `json:"story_categories"`
+	Formats           *[]string       `json:"formats"`
+	MinQuality        json.RawMessage `json:"min_quality"`
+	ExcludeContentIDs *[]string       `json:"exclude_content_ids"`
+	ExcludeSourceIDs  *[]string       `json:"exclude_source_ids"`
+	ExcludeStoryIDs   *[]string       `json:"exclude_story_ids"`
+}
+
+const maxFeedFilterValues = 200
+
+type feedFilterError struct{ message string }
+
+func (e feedFilterError) Error() string { return e.message }
+
+// applyTo validates the provided filters and writes them onto feed. When
+// updates is non-nil the same values are recorded by column for a partial
+// UPDATE.
+func (r rssFeedFilterRequest) applyTo(feed *models.RSSFeed, updates map[string]interface{}) error {
+	set := func(column string, target *pq.StringArray, raw *[]string, normalize func(string) (string, bool)) error {
+		if raw == nil {
+			return nil
+		}
+		values, err := normalizeFeedFilterList(column, *raw, normalize)
+		if err != nil {
+			return err
+		}
+		*target = values
+		if updates != nil {
+			updates[column] = values
+		}
+		return nil
+	}
+	steps := []error{
+		set("story_ids", &feed.StoryIDs, r.StoryIDs, normalizeFeedFilterUUID),
+		set("content_source_ids", &feed.ContentSourceIDs, r.ContentSourceIDs, normalizeFeedFilterUUID),
+		set("content_languages", &feed.ContentLanguages, r.ContentLanguages, normalizeFeedFilterLanguage),
+		set("story_categories", &feed.StoryCategories, r.StoryCategories, normalizeFeedFilterCategory),
+		set("formats", &feed.Formats, r.Formats, normalizeFeedFilterFormat),
+		set("exclude_content_ids", &feed.ExcludeContentIDs, r.ExcludeContentIDs, normalizeFeedFilterUUID),
+		set("exclude_source_ids", &feed.ExcludeSourceIDs, r.ExcludeSourceIDs, normalizeFeedFilterUUID),
+		set("exclude_story_ids", &feed.ExcludeStoryIDs, r.ExcludeStoryIDs, normalizeFeedFilterUUID),
+	}
+	for _, err := range steps {
+		if err != nil {
+			return err
+		}
+	}
+	if len(r.MinQuality) > 0 {
+		var value *float64
+		if err := json.Unmarshal(r.MinQuality, &value); err != nil || (value != nil && (*value < 0 || *value > 1)) {
+			return feedFilterError{"min_quality must be null or a number between 0 and 1"}
+		}
+		feed.MinQuality = value
+		if updates != nil {
+			updates["min_quality"] = value
+		}
+	}
+	return nil
+}
+
+func normalizeFeedFilterList(field string, raw []string, normalize func(string) (string, bool)) (pq.StringArray, error) {
+	if len(raw) > maxFeedFilterValues {
+		return nil, feedFilterError{field + " accepts at most " + strconv.Itoa(maxFeedFilterValues) + " values"}
+	}
+	out := pq.StringArray{}
+	seen := map[string]bool{}
+	for _, value := range raw {
+		normalized, ok := normalize(value)
+		if !ok {
+			return nil, feedFilterError{field + " contains an invalid value: " + strings.TrimSpace(value)}
+		}
+		if !seen[normalized] {
+			seen[normalized] = true
+			out = append(out, normalized)
+		}
+	}
+	return out, nil
+}
+
+func normalizeFeedFilterUUID(raw string) (string, bool) {
+	id, err := uuid.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return "", false
+	}
+	return id.String(), true
+}
+
+func normalizeFeedFilterLanguage(raw string) (string, bool) {
+	language := normalizeContentLanguage(&raw)
+	if language == nil {
+		return "", false
+	}
+	return *language, true
+}
+
+func normalizeFeedFilterCategory(raw string) (string, bool) {
+	category := slugify(raw)
+	return category, category != ""
+}
+
+func normalizeFeedFilterFormat(raw string) (string, bool) {
+	switch format := models.ContentFormat(strings.ToUpper(strings.TrimSpace(raw))); format {
+	case models.ContentFormatArticle, models.ContentFormatTweet, models.ContentFormatComment:
+		return string(format), true
+	default:
+		return "", false
+	}
+}
+
 type createRSSFeedRequest struct {
 	Name        string  `json:"name"`
 	Title       string  `json:"title"`
@@ -115,6 +235,7 @@ type createRSSFeedRequest struct {
 	ContentType string  `json:"content_type"`
 	ItemLimit   int     `json:"item_limit"`
 	Slug        string  `json:"slug"`
+	rssFeedFilterRequest
 }
 
 // CreateRSSFeed handles POST /admin/feeds.
@@ -139,6 +260,19 @@ func CreateRSSFeed(c *gin.Context) {
 		ContentType: strings.ToUpper(strings.TrimSpace(req.ContentType)),
 		ItemLimit:   clampLimit(req.ItemLimit),
 		Enabled:     true,
+
+		StoryIDs:          pq.StringArray{},
+		ContentSourceIDs:  pq.StringArray{},
+		ContentLanguages:  pq.StringArray{},
+		StoryCategories:   pq.StringArray{},
+		Formats:           pq.StringArray{},
+		ExcludeContentIDs: pq.StringArray{},
+		ExcludeSourceIDs:  pq.StringArray{},
+		ExcludeStoryIDs:   pq.StringArray{},
+	}
+	if err := req.rssFeedFilterRequest.applyTo(&feed, nil); err != nil {
+		c.JSON(http.StatusBadRequest, authErrorResponse{Message: err.Error(), Code: "INVALID_FILTER"})
+		return
 	}
 	if req.StoryID != nil {
 		if tid, err := uuid.Parse(strings.TrimSpace(*req.StoryID)); err == nil {
@@ -167,6 +301,7 @@ type updateRSSFeedRequest struct {
 	ItemLimit   *int    `json:"item_limit"`
 	Enabled     *bool   `json:"enabled"`
 	Slug        *string `json:"slug"`
+	rssFeedFilterRequest
 }
 
 // UpdateRSSFeed handles PUT /admin/feeds/:id.
@@ -222,6 +357,10 @@ func UpdateRSSFeed(c *gin.Context) {
 			updates["story_id"] = tid
 		}
 	}
+	if err := req.rssFeedFilterRequest.applyTo(&feed, updates); err != nil {
+		c.JSON(http.StatusBadRequest, authErrorResponse{Message: err.Error(), Code: "INVALID_FILTER"})
+		return
+	}
 	if req.Slug != nil {
 		updates["slug"] = uniqueFeedSlug(db, principal.TenantID, slugify(*req.Slug), &id)
 	}
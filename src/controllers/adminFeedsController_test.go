@@ -0,0 +1,83 @@
+package controllers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"content-management-system/src/models"
+)
+
+func TestRSSFeedFilterRequestNormalizesAndRecordsUpdates(t *testing.T) {
+	var req rssFeedFilterRequest
+	body := `{
+		"story_ids": ["3F2504E0-4F89-11D3-9A0C-0305E82C3301", "3f2504e0-4f89-11d3-9a0c-0305e82c3301"],
+		"content_languages": [" AR ", "en"],
+		"story_categories": ["Politics", "economy"],
+		"formats": ["tweet"],
+		"min_quality": 0.4,
+		"exclude_source_ids": []
+	}`
+	if err := json.Unmarshal([]byte(body), &req); err != nil {
+		t.Fatal(err)
+	}
+	var feed models.RSSFeed
+	updates := map[string]interface{}{}
+	if err := req.applyTo(&feed, updates); err != nil {
+		t.Fatalf("valid filters rejected: %v", err)
+	}
+	if len(feed.StoryIDs) != 1 || feed.StoryIDs[0] != "3f2504e0-4f89-11d3-9a0c-0305e82c3301" {
+		t.Fatalf("story ids = %v, want one canonical id", feed.StoryIDs)
+	}
+	if len(feed.ContentLanguages) != 2 || feed.ContentLanguages[0] != "ar" {
+		t.Fatalf("languages = %v", feed.ContentLanguages)
+	}
+	if feed.StoryCategories[0] != "politics" || feed.Formats[0] != "TWEET" {
+		t.Fatalf("categories/formats not normalized: %v %v", feed.StoryCategories, feed.Formats)
+	}
+	if feed.MinQuality == nil || *feed.MinQuality != 0.4 {
+		t.Fatalf("min_quality = %v", feed.MinQuality)
+	}
+	if _, ok := updates["exclude_source_ids"]; !ok {
+		t.Fatal("explicit empty list must clear the stored exclusion")
+	}
+	if _, ok := updates["content_source_ids"]; ok {
+		t.Fatal("omitted list must not be written")
+	}
+}
+
+func TestRSSFeedFilterRequestRejectsInvalidValues(t *testing.T) {
+	for _, body := range []string{
+		`{"story_ids": ["not-a-uuid"]}`,
+		`{"content_languages": ["fr"]}`,
+		`{"formats": ["VIDEO"]}`,
+		`{"min_quality": 1.5}`,
+		`{"min_quality": "high"}`,
+	} {
+		var req rssFeedFilterRequest
+		if err := json.Unmarshal([]byte(body), &req); err != nil {
+			t.Fatal(err)
+		}
+		if err := req.applyTo(&models.RSSFeed{}, nil); err == nil {
+			t.Fatalf("%s was accepted", body)
+		}
+	}
+}
+
+func TestRSSFeedFilterRequestNullClearsMinQuality(t *testing.T) {
+	var req rssFeedFilterRequest
+	if err := json.Unmarshal([]byte(`{"min_quality": null}`), &req); err != nil {
+		t.Fatal(err)
+	}
+	value := 0.5
+	feed := models.RSSFeed{MinQuality: &value}
+	updates := map[string]interface{}{}
+	if err := req.applyTo(&feed, updates); err != nil {
+		t.Fatal(err)
+	}
+	if feed.MinQuality != nil {
+		t.Fatalf("min_quality = %v, want cleared", *feed.MinQuality)
+	}
+	if v, ok := updates["min_quality"]; !ok || v.(*float64) != nil {
+		t.Fatalf("min_quality update = %#v, want explicit NULL", v)
+	}
+}
@@ -518,7 +518,7 @@ func ProbeContentItem(c *gin.Context) {
 	if it.StorageTier != nil {
 		tier = *it.StorageTier
 	}
-	body, status, err := proxyAggregationPost(c.GetHeader("Authorization"), "/admin/quality/probe", map[string]any{
+	body, status, err := proxyAggregationPost(c.Request.Context(), c.GetHeader("Authorization"), "/admin/quality/probe", map[string]any{
 		"content_item_id": it.PublicID.String(),
 		"tier":            tier,
 	})
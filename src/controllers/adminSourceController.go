@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"content-management-system/src/models"
 	"content-management-system/src/utils"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -220,6 +221,8 @@ var contentSourceQueryConfig = utils.QueryConfig{
 		"created_at": "content_sources.created_at",
 		"updated_at": "content_sources.updated_at",
 	},
+	TimeFields: map[string]bool{"created_at": true, "updated_at": true},
+	JSONFields: map[string]string{"metadata": "content_sources.metadata"},
 	SearchableFields: map[string]string{
 		"name":     "content_sources.name",
 		"feed_url": "content_sources.feed_url",
@@ -1107,6 +1110,7 @@ func DiscoverSourceFeeds(c *gin.Context) {
 	}
 
 	responseBody, statusCode, err := proxyAggregationRequest(
+		c.Request.Context(),
 		aggregationBaseURL,
 		"/admin/discover",
 		c.GetHeader("Authorization"),
@@ -1182,6 +1186,7 @@ func PreviewSource(c *gin.Context) {
 	}
 
 	responseBody, statusCode, err := proxyAggregationRequest(
+		c.Request.Context(),
 		aggregationBaseURL,
 		"/admin/preview",
 		c.GetHeader("Authorization"),
@@ -1244,7 +1249,7 @@ func SearchPodcasts(c *gin.Context) {
 		q.Set("country", co)
 	}
 
-	body, statusCode, err := proxyAggregationGet(c.GetHeader("Authorization"), "/admin/itunes/search?"+q.Encode())
+	body, statusCode, err := proxyAggregationGet(c.Request.Context(), c.GetHeader("Authorization"), "/admin/itunes/search?"+q.Encode())
 	if err != nil {
 		c.JSON(http.StatusBadGateway, authErrorResponse{Message: "Failed to search podcasts: " + err.Error(), Code: "ITUNES_SEARCH_FAILED"})
 		return
@@ -1293,7 +1298,7 @@ func ResolveYoutube(c *gin.Context) {
 	q := url.Values{}
 	q.Set("url", target)
 
-	body, statusCode, err := proxyAggregationGet(c.GetHeader("Authorization"), "/admin/youtube/resolve?"+q.Encode())
+	body, statusCode, err := proxyAggregationGet(c.Request.Context(), c.GetHeader("Authorization"), "/admin/youtube/resolve?"+q.Encode())
 	if err != nil {
 		c.JSON(http.StatusBadGateway, authErrorResponse{Message: "Failed to resolve channel: " + err.Error(), Code: "YOUTUBE_RESOLVE_FAILED"})
 		return
@@ -1416,6 +1421,7 @@ func triggerAggregationSourceRun(
 	payload aggregationTriggerRequest,
 ) (aggregationTriggerResponse, error) {
 	requestBody, statusCode, err := proxyAggregationRequest(
+		context.Background(),
 		aggregationBaseURL,
 		"/admin/trigger",
 		authorizationHeader,
@@ -1448,6 +1454,7 @@ func triggerAggregationSourceRun(
 }
 
 func proxyAggregationRequest(
+	ctx context.Context,
 	aggregationBaseURL string,
 	path string,
 	authorizationHeader string,
@@ -1458,7 +1465,7 @@ func proxyAggregationRequest(
 		return nil, 0, err
 	}
 
-	req, err := http.NewRequest(http.MethodPost, aggregationBaseURL+path, bytes.NewReader(requestBody))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, aggregationBaseURL+path, bytes.NewReader(requestBody))
 	if err != nil {
 		return nil, 0, err
 	}
@@ -1468,7 +1475,7 @@ func proxyAggregationRequest(
 		req.Header.Set("Authorization", authorizationHeader)
 	}
 
-	client := &http.Client{Timeout: 15 * time.Second}
+	client := &http.Client{Transport: ownerTransport, Timeout: 15 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, 0, err
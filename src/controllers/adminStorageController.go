@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"content-management-system/src/intelligence"
 	"content-management-system/src/models"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -1189,7 +1190,7 @@ func ReconcileStorage(c *gin.Context) {
 	if _, ok := requireAdminPrincipal(c); !ok {
 		return
 	}
-	body, status, err := proxyAggregationGet(c.GetHeader("Authorization"), "/admin/storage/reconcile")
+	body, status, err := proxyAggregationGet(c.Request.Context(), c.GetHeader("Authorization"), "/admin/storage/reconcile")
 	if err != nil {
 		c.JSON(http.StatusBadGateway, authErrorResponse{Message: err.Error(), Code: "RECONCILE_FAILED"})
 		return
@@ -1328,7 +1329,7 @@ func aggregationBaseURL() (string, error) {
 }
 
 func callAggregationStorageStats(authHeader string) (aggStatsResponse, error) {
-	body, status, err := proxyAggregationGet(authHeader, "/admin/storage/stats")
+	body, status, err := proxyAggregationGet(context.Background(), authHeader, "/admin/storage/stats")
 	if err != nil {
 		return aggStatsResponse{}, err
 	}
@@ -1343,7 +1344,7 @@ func callAggregationStorageStats(authHeader string) (aggStatsResponse, error) {
 }
 
 func callAggregationDeleteObjects(authHeader string, payload aggDeleteRequest) (aggDeleteResponse, error) {
-	body, status, err := proxyAggregationPost(authHeader, "/admin/storage/delete-objects", payload)
+	body, status, err := proxyAggregationPost(context.Background(), authHeader, "/admin/storage/delete-objects", payload)
 	if err != nil {
 		return aggDeleteResponse{}, err
 	}
@@ -1376,7 +1377,7 @@ func callAggregationRunSweepScoped(authHeader, tenantID, archiveAction string, c
 }
 
 func callAggregationRunSweepWithPayload(authHeader string, payload map[string]any) error {
-	body, status, err := proxyAggregationPost(authHeader, "/admin/storage/sweep", payload)
+	body, status, err := proxyAggregationPost(context.Background(), authHeader, "/admin/storage/sweep", payload)
 	if err != nil {
 		return err
 	}
@@ -1387,7 +1388,7 @@ func callAggregationRunSweepWithPayload(authHeader string, payload map[string]an
 }
 
 func callAggregationPolicyChanged(authHeader string) error {
-	_, status, err := proxyAggregationPost(authHeader, "/admin/storage/policy-changed", map[string]any{})
+	_, status, err := proxyAggregationPost(context.Background(), authHeader, "/admin/storage/policy-changed", map[string]any{})
 	if err != nil {
 		return err
 	}
@@ -1397,7 +1398,7 @@ func callAggregationPolicyChanged(authHeader string) error {
 	return nil
 }
 
-func proxyAggregationPost(authHeader, path string, payload any) ([]byte, int, error) {
+func proxyAggregationPost(ctx context.Context, authHeader, path string, payload any) ([]byte, int, error) {
 	base, err := aggregationBaseURL()
 	if err != nil {
 		return nil, 0, err
@@ -1406,7 +1407,7 @@ func proxyAggregationPost(authHeader, path string, payload any) ([]byte, int, er
 	if err != nil {
 		return nil, 0, err
 	}
-	req, err := http.NewRequest(http.MethodPost, base+path, bytes.NewReader(buf))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, base+path, bytes.NewReader(buf))
 	if err != nil {
 		return nil, 0, err
 	}
@@ -1417,7 +1418,7 @@ func proxyAggregationPost(authHeader, path string, payload any) ([]byte, int, er
 	if t := strings.TrimSpace(os.Getenv("AGGREGATION_SERVICE_TOKEN")); t != "" {
 		req.Header.Set("X-Service-Token", t)
 	}
-	client := &http.Client{Timeout: 30 * time.Second}
+	client := &http.Client{Transport: ownerTransport, Timeout: 30 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, 0, err
@@ -1430,12 +1431,12 @@ func proxyAggregationPost(authHeader, path string, payload any) ([]byte, int, er
 	return respBody, resp.StatusCode, nil
 }
 
-func proxyAggregationGet(authHeader, path string) ([]byte, int, error) {
+func proxyAggregationGet(ctx context.Context, authHeader, path string) ([]byte, int, error) {
 	base, err := aggregationBaseURL()
 	if err != nil {
 		return nil, 0, err
 	}
-	req, err := http.NewRequest(http.MethodGet, base+path, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+path, nil)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -1445,7 +1446,7 @@ func proxyAggregationGet(authHeader, path string) ([]byte, int, error) {
 	if t := strings.TrimSpace(os.Getenv("AGGREGATION_SERVICE_TOKEN")); t != "" {
 		req.Header.Set("X-Service-Token", t)
 	}
-	client := &http.Client{Timeout: 30 * time.Second}
+	client := &http.Client{Transport: ownerTransport, Timeout: 30 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, 0, err
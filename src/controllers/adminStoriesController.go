@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"content-management-system/src/lifecycle"
 	"content-management-system/src/models"
 	"content-management-system/src/utils"
 	"encoding/json"
@@ -359,8 +360,9 @@ type mergeTopicsRequest struct {
 	TargetID  string   `json:"target_id"`
 }
 
-// MergeTopics handles POST /admin/stories/merge — repoints all content from the
-// source topics onto the target, then deletes the empty sources.
+// MergeTopics handles POST /admin/stories/merge — repoints all content,
+// follows and summary history from the source topics onto the target, then
+// deletes the empty sources.
 func MergeTopics(c *gin.Context) {
 	principal, ok := requireAdminPrincipal(c)
 	if !ok {
@@ -416,6 +418,29 @@ func MergeTopics(c *gin.Context) {
 			return err
 		}
 
+		// Followers of a merged-away story keep following the survivor; a
+		// reader who followed both keeps the earlier "last looked" mark.
+		// Follows are consumer-owned, so only this tenant's sources are moved.
+		ownedSources := tx.Model(&models.Story{}).Select("public_id").Where("tenant_id = ? AND public_id IN ?", principal.TenantID, sources)
+		if err := tx.Exec(`INSERT INTO story_follows (tenant_id, user_id, story_id, last_seen_at, created_at, updated_at)
+			SELECT tenant_id, user_id, ?, MIN(last_seen_at), MIN(created_at), NOW() FROM story_follows
+			WHERE tenant_id = ? AND story_id IN (?) GROUP BY tenant_id, user_id
+			ON CONFLICT (tenant_id, user_id, story_id) DO UPDATE SET
+				last_seen_at = LEAST(story_follows.last_seen_at, EXCLUDED.last_seen_at), updated_at = NOW()`,
+			target, consumerTenant, ownedSources).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("tenant_id = ? AND story_id IN (?)", consumerTenant, ownedSources).Delete(&models.StoryFollow{}).Error; err != nil {
+			return err
+		}
+		// The survivor's timeline keeps the merged stories' digest history.
+		// Revisions carry no tenant, so only this tenant's sources are moved.
+		if err := tx.Model(&models.StorySummaryRevision{}).
+			Where("story_id IN (SELECT public_id FROM stories WHERE tenant_id = ? AND public_id IN ?)", principal.TenantID, sources).
+			Update("story_id", target).Error; err != nil {
+			return err
+		}
+
 		return tx.Where("tenant_id = ? AND public_id IN ?", principal.TenantID, sources).
 			Delete(&models.Story{}).Error
 	})
@@ -431,7 +456,8 @@ func MergeTopics(c *gin.Context) {
 }
 
 // DeleteTopic handles DELETE /admin/stories/:id. Content survives — its story_id
-// is cleared (so the articles fall back into "uncategorized").
+// is cleared (so the articles fall back into "uncategorized"); follows and the
+// summary history are deleted with the story.
 func DeleteTopic(c *gin.Context) {
 	principal, ok := requireAdminPrincipal(c)
 	if !ok {
@@ -457,7 +483,14 @@ func DeleteTopic(c *gin.Context) {
 			return res.Error
 		}
 		deleted = res.RowsAffected
-		return nil
+		if deleted == 0 {
+			return nil
+		}
+		if e := tx.Where("story_id = ? AND tenant_id = ?", id, consumerTenant).Delete(&models.StoryFollow{}).Error; e != nil {
+			return e
+		}
+		// The story was this tenant's, so its digest history goes with it.
+		return tx.Where("story_id = ?", id).Delete(&models.StorySummaryRevision{}).Error
 	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, authErrorResponse{Message: "Failed to delete topic", Code: "DELETE_FAILED"})
@@ -714,7 +747,7 @@ func ReclusterTopics(c *gin.Context) {
 		return
 	}
 
-	StartClassificationBackfill(db)
+	StartClassificationBackfill(lifecycle.Root(), db)
 
 	c.JSON(http.StatusOK, reclusterResponse{
 		Clusters: 0,
@@ -769,7 +802,7 @@ func LabelTopicsBatch(c *gin.Context) {
 			continue
 		}
 
-		label, lerr := generateTopicLabelViaEnrichment(texts)
+		label, lerr := generateTopicLabelViaEnrichment(c.Request.Context(), texts)
 		if lerr != nil {
 			// Enrichment/LLM is unreachable — surface it instead of silently
 			// stamping "Cluster N" on every topic. The caller can retry.
@@ -864,7 +897,7 @@ func DigestTopicsBatch(c *gin.Context) {
 				Updates(map[string]interface{}{"summary_built_at": now, "category": "general"})
 			continue
 		}
-		summary, bullets, category, derr := generateStorySummaryViaEnrichment(texts)
+		summary, bullets, category, derr := generateStorySummaryViaEnrichment(c.Request.Context(), texts)
 		if derr != nil {
 			// Surface an Enrichment outage (don't mark attempted) so the caller
 			// can retry — matches LabelTopicsBatch's behaviour.
@@ -887,6 +920,7 @@ func DigestTopicsBatch(c *gin.Context) {
 				"summary_built_at": now,
 				"category":         normalizeStoryCategory(category),
 			})
+		recordStorySummaryRevision(db, t.PublicID, summary, bulletsJSON, normalizeStoryCategory(category), t.ArticleCount)
 		processed++
 	}
 
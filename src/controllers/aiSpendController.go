@@ -5,14 +5,19 @@ package controllers
 // or mutates a spender; callers consume the allowance answer separately.
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"math"
 	"net/http"
 	"strings"
 	"sync"
 	"time"
 
+	"content-management-system/src/lifecycle"
+	"content-management-system/src/metrics"
 	"content-management-system/src/models"
+	"content-management-system/src/utils"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -87,6 +92,12 @@ func InternalIngestAISpendEvents(c *gin.Context) {
 		return
 	}
 	db := c.MustGet("db").(*gorm.DB)
+	// Metrics label spend by the authenticated caller; source_service is
+	// self-reported and only recorded in the ledger.
+	service := "unknown"
+	if principal, ok := utils.GetMachinePrincipal(c); ok {
+		service = string(principal)
+	}
 	accepted := 0
 	for _, in := range req.Events {
 		id, err := uuid.Parse(in.EventID)
@@ -122,6 +133,7 @@ func InternalIngestAISpendEvents(c *gin.Context) {
 		}
 		if result := db.Where("event_id = ?", id).FirstOrCreate(&e); result.Error == nil && result.RowsAffected > 0 {
 			accepted++
+			metrics.AISpendAccepted(e.SpendClass, service, e.CostUSD, e.AvoidedCostUSD)
 		}
 	}
 	c.JSON(http.StatusAccepted, gin.H{"accepted": accepted, "dropped_reported": req.Dropped})
@@ -377,7 +389,9 @@ func CheckSpendAllowance(db *gorm.DB, class string, estimatedUSD float64, trigge
 }
 func InternalGetAISpendAllowance(c *gin.Context) {
 	class := c.DefaultQuery("class", "llm")
-	c.JSON(200, CheckSpendAllowance(c.MustGet("db").(*gorm.DB), class, 0, c.DefaultQuery("trigger_source", "unknown")))
+	answer := CheckSpendAllowance(c.MustGet("db").(*gorm.DB), class, 0, c.DefaultQuery("trigger_source", "unknown"))
+	metrics.AISpendAllowanceChecked(class, fmt.Sprint(answer["verdict"]))
+	c.JSON(200, answer)
 }
 func ListAIPriceBook(c *gin.Context) {
 	var rows []models.AIPriceBook
@@ -404,8 +418,8 @@ func CreateAIPriceBook(c *gin.Context) {
 	c.JSON(201, row)
 }
 
-func StartAISpendGovernorHeartbeat(db *gorm.DB) {
-	go func() {
+func StartAISpendGovernorHeartbeat(ctx context.Context, db *gorm.DB) {
+	lifecycle.Go(ctx, "ai-spend-governor", func(ctx context.Context) {
 		// Metering is always on, including observe mode. Governance (verdicts,
 		// caps, and episodes) remains gated by policy.enabled, but raw events
 		// must become rollups without an operator having to enable enforcement.
@@ -431,7 +445,7 @@ func StartAISpendGovernorHeartbeat(db *gorm.DB) {
 		runMetering()
 		ticker := time.NewTicker(time.Minute)
 		defer ticker.Stop()
-		for range ticker.C {
+		for lifecycle.Wait(ctx, ticker.C) {
 			p, err := getAISpendPolicy(db)
 			if err != nil {
 				continue
@@ -441,5 +455,5 @@ func StartAISpendGovernorHeartbeat(db *gorm.DB) {
 			}
 			runMetering()
 		}
-	}()
+	})
 }
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -12,25 +13,30 @@ import (
 	"time"
 
 	"content-management-system/src/artifacts"
+	"content-management-system/src/lifecycle"
 	"gorm.io/gorm"
 )
 
 var artifactCoverageHeartbeat atomic.Int64
 
-func StartArtifactCoverageWorker(db *gorm.DB) {
+func StartArtifactCoverageWorker(ctx context.Context, db *gorm.DB) {
 	runArtifactCoverageWorker(db)
-	go func() {
+	lifecycle.Go(ctx, "artifact-coverage", func(ctx context.Context) {
 		ticker := time.NewTicker(10 * time.Second)
 		defer ticker.Stop()
-		for range ticker.C {
+		for lifecycle.Wait(ctx, ticker.C) {
 			runArtifactCoverageWorker(db)
 		}
-	}()
+	})
 }
 func ArtifactCoverageWorkerHealthy(now time.Time) bool {
 	last := artifactCoverageHeartbeat.Load()
 	return last > 0 && now.UTC().Sub(time.Unix(0, last).UTC()) <= 90*time.Second
 }
+
+func ArtifactCoverageWorkerLastHeartbeat() time.Time {
+	return lifecycle.HeartbeatTime(artifactCoverageHeartbeat.Load())
+}
 func runArtifactCoverageWorker(db *gorm.DB) {
 	if err := artifacts.RecoverExpired(db); err != nil {
 		log.Printf("artifact coverage recovery failed: %v", err)
@@ -109,7 +115,7 @@ func executeEnrichmentArtifact(claim artifacts.Claim) (map[string]any, error) {
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+token)
-	response, err := (&http.Client{Timeout: 45 * time.Second}).Do(req)
+	response, err := (&http.Client{Transport: ownerTransport, Timeout: 45 * time.Second}).Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"content-management-system/src/contentstage"
+	"content-management-system/src/feedstate"
 	"content-management-system/src/models"
 	"content-management-system/src/utils"
 	"encoding/json"
@@ -1621,7 +1622,7 @@ func adminQueueMediaParentAtomization(c *gin.Context, reatomize bool) {
 		"contentType": parent.Type, "mediaUrl": parent.MediaURL, "thumbnailUrl": parent.ThumbnailURL,
 		"title": parent.Title, "excerpt": parent.Excerpt, "bodyText": parent.BodyText,
 	}
-	body, statusCode, err := proxyAggregationRequest(aggregationBaseURL, "/admin/atomization/parents/"+parent.PublicID.String()+"/atomize", c.GetHeader("Authorization"), payload)
+	body, statusCode, err := proxyAggregationRequest(c.Request.Context(), aggregationBaseURL, "/admin/atomization/parents/"+parent.PublicID.String()+"/atomize", c.GetHeader("Authorization"), payload)
 	if err != nil {
 		c.JSON(http.StatusBadGateway, utils.HTTPError{Code: http.StatusBadGateway, Message: "Aggregation request failed: " + err.Error()})
 		return
@@ -3379,7 +3380,10 @@ func applyAtomizedChapterReviewWithOptions(db *gorm.DB, tenantID string, chapter
 			status := chapterStatusRejected
 			child.ChapteringStatus = &status
 		}
-		return tx.Save(&child).Error
+		if err := tx.Save(&child).Error; err != nil {
+			return err
+		}
+		return feedstate.CommitReady(tx, child)
 	}); err != nil {
 		if errors.Is(err, errChapterReviewStale) {
 			return nil, &chapterReviewError{http.StatusConflict, chapterReviewErrStale, "Chapter is no longer awaiting review"}
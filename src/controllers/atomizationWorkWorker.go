@@ -1,25 +1,27 @@
 package controllers
 
 import (
+	"context"
 	"log"
 	"sync/atomic"
 	"time"
 
 	"content-management-system/src/atomizationwork"
+	"content-management-system/src/lifecycle"
 	"gorm.io/gorm"
 )
 
 var atomizationWorkHeartbeat atomic.Int64
 
-func StartAtomizationWorkVerifier(db *gorm.DB) {
+func StartAtomizationWorkVerifier(ctx context.Context, db *gorm.DB) {
 	runAtomizationWorkVerifier(db)
-	go func() {
+	lifecycle.Go(ctx, "atomization-work-verifier", func(ctx context.Context) {
 		ticker := time.NewTicker(10 * time.Second)
 		defer ticker.Stop()
-		for range ticker.C {
+		for lifecycle.Wait(ctx, ticker.C) {
 			runAtomizationWorkVerifier(db)
 		}
-	}()
+	})
 }
 func runAtomizationWorkVerifier(db *gorm.DB) {
 	if err := atomizationwork.RecoverExpired(db); err != nil {
@@ -36,3 +38,7 @@ func AtomizationWorkVerifierHealthy(now time.Time) bool {
 	last := atomizationWorkHeartbeat.Load()
 	return last > 0 && now.UTC().Sub(time.Unix(0, last).UTC()) <= 90*time.Second
 }
+
+func AtomizationWorkVerifierLastHeartbeat() time.Time {
+	return lifecycle.HeartbeatTime(atomizationWorkHeartbeat.Load())
+}
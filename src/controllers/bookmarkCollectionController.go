@@ -0,0 +1,905 @@
+package controllers
+
+import (
+	"content-management-system/src/models"
+	"content-management-system/src/utils"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	maxBookmarkCollections         = 50
+	maxBookmarkCollectionItems     = 500
+	maxBookmarkCollectionNameRunes = 80
+	maxBookmarkCollectionTextRunes = 500
+)
+
+var (
+	errBookmarkCollectionNotFound  = errors.New("collection not found")
+	errBookmarkCollectionNameTaken = errors.New("a collection with this name already exists")
+	errBookmarkCollectionLimit     = errors.New("collection limit reached")
+	errBookmarkCollectionItemDup   = errors.New("content is already in this collection")
+	errBookmarkCollectionItemGone  = errors.New("content is not in this collection")
+	errBookmarkCollectionOrder     = errors.New("content_ids must list every item in the collection exactly once")
+)
+
+// BookmarkCollectionEntry is one placed item: the content card plus the
+// owner's ordering and note.
+type BookmarkCollectionEntry struct {
+	Position int       `json:"position"`
+	Note     string    `json:"note"`
+	AddedAt  time.Time `json:"added_at"`
+	Item     PodsItem  `json:"item"`
+}
+
+type bookmarkCollectionResponse struct {
+	models.BookmarkCollection
+	ShareURL string                    `json:"share_url,omitempty"`
+	FeedURL  string                    `json:"feed_url,omitempty"`
+	Items    []BookmarkCollectionEntry `json:"items,omitempty"`
+}
+
+type bookmarkCollectionRequest struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+}
+
+type bookmarkCollectionItemRequest struct {
+	ContentID string  `json:"content_id"`
+	Note      *string `json:"note"`
+	Position  *int    `json:"position"`
+}
+
+type bookmarkCollectionOrderRequest struct {
+	ContentIDs []string `json:"content_ids" binding:"required"`
+}
+
+func newBookmarkCollectionResponse(c *gin.Context, collection models.BookmarkCollection) bookmarkCollectionResponse {
+	response := bookmarkCollectionResponse{BookmarkCollection: collection}
+	if collection.ShareToken != nil {
+		response.ShareURL = bookmarkCollectionShareURL(publicBaseURL(c), *collection.ShareToken)
+		response.FeedURL = response.ShareURL + "/feed?format=podcast"
+	}
+	return response
+}
+
+func bookmarkCollectionShareURL(base, token string) string {
+	return base + "/api/v1/collections/shared/" + token
+}
+
+// normalizeBookmarkCollectionText trims and bounds free text fields.
+func normalizeBookmarkCollectionText(field, value string, maxRunes int, required bool) (string, error) {
+	value = strings.TrimSpace(value)
+	if required && value == "" {
+		return "", fmt.Errorf("%s is required", field)
+	}
+	if utf8.RuneCountInString(value) > maxRunes {
+		return "", fmt.Errorf("%s must be at most %d characters", field, maxRunes)
+	}
+	return value, nil
+}
+
+// lockBookmarkCollection loads the caller's collection with a row lock; every
+// item mutation goes through it so positions stay dense under concurrency.
+func lockBookmarkCollection(tx *gorm.DB, uid, collectionID uuid.UUID) (models.BookmarkCollection, error) {
+	var collection models.BookmarkCollection
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("public_id = ? AND tenant_id = ? AND user_id = ?", collectionID, consumerTenant, uid).
+		First(&collection).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return collection, errBookmarkCollectionNotFound
+	}
+	return collection, err
+}
+
+// touchBookmarkCollection bumps the collection's validator and item count.
+func touchBookmarkCollection(tx *gorm.DB, collectionID uuid.UUID, delta int) error {
+	return tx.Model(&models.BookmarkCollection{}).Where("public_id = ?", collectionID).Updates(map[string]any{
+		"item_count": gorm.Expr("item_count + ?", delta),
+		"updated_at": time.Now().UTC(),
+	}).Error
+}
+
+// bookmarkCollectionNameTaken reports whether the caller already has another
+// collection with the same case-insensitive name.
+func bookmarkCollectionNameTaken(tx *gorm.DB, uid uuid.UUID, name string, except *uuid.UUID) (bool, error) {
+	query := tx.Model(&models.BookmarkCollection{}).
+		Where("tenant_id = ? AND user_id = ? AND lower(name) = lower(?)", consumerTenant, uid, name)
+	if except != nil {
+		query = query.Where("public_id <> ?", *except)
+	}
+	var count int64
+	err := query.Count(&count).Error
+	return count > 0, err
+}
+
+// moveBookmarkCollectionItem returns ids with id moved to position, clamped
+// to the list bounds.
+func moveBookmarkCollectionItem(ids []uuid.UUID, id uuid.UUID, position int) []uuid.UUID {
+	out := make([]uuid.UUID, 0, len(ids))
+	for _, existing := range ids {
+		if existing != id {
+			out = append(out, existing)
+		}
+	}
+	if position < 0 {
+		position = 0
+	}
+	if position > len(out) {
+		position = len(out)
+	}
+	out = append(out, uuid.Nil)
+	copy(out[position+1:], out[position:])
+	out[position] = id
+	return out
+}
+
+// validateBookmarkCollectionOrder checks that order is a permutation of the
+// collection's current items.
+func validateBookmarkCollectionOrder(current, order []uuid.UUID) error {
+	if len(current) != len(order) {
+		return errBookmarkCollectionOrder
+	}
+	remaining := make(map[uuid.UUID]bool, len(current))
+	for _, id := range current {
+		remaining[id] = true
+	}
+	for _, id := range order {
+		if !remaining[id] {
+			return errBookmarkCollectionOrder
+		}
+		delete(remaining, id)
+	}
+	return nil
+}
+
+// bookmarkCollectionItemOrder lists the collection's content ids by position.
+func bookmarkCollectionItemOrder(tx *gorm.DB, collectionID uuid.UUID) ([]models.BookmarkCollectionItem, []uuid.UUID, error) {
+	var items []models.BookmarkCollectionItem
+	if err := tx.Where("collection_id = ?", collectionID).Order("position ASC, id ASC").Find(&items).Error; err != nil {
+		return nil, nil, err
+	}
+	ids := make([]uuid.UUID, len(items))
+	for i, item := range items {
+		ids[i] = item.ContentItemID
+	}
+	return items, ids, nil
+}
+
+// applyBookmarkCollectionOrder rewrites only the positions that changed.
+func applyBookmarkCollectionOrder(tx *gorm.DB, collectionID uuid.UUID, items []models.BookmarkCollectionItem, order []uuid.UUID) error {
+	current := make(map[uuid.UUID]int, len(items))
+	for _, item := range items {
+		current[item.ContentItemID] = item.Position
+	}
+	for position, id := range order {
+		// Ids not placed yet (an item being added) are inserted by the caller.
+		if existing, ok := current[id]; !ok || existing == position {
+			continue
+		}
+		if err := tx.Model(&models.BookmarkCollectionItem{}).
+			Where("collection_id = ? AND content_item_id = ?", collectionID, id).
+			Update("position", position).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bookmarkCollectionContents loads a collection's placements in order along
+// with their content rows. Items that are no longer publicly visible are
+// skipped, so a shared collection never leaks withdrawn content.
+func bookmarkCollectionContents(db *gorm.DB, collectionID uuid.UUID) ([]models.BookmarkCollectionItem, []models.ContentItem, error) {
+	placements, ids, err := bookmarkCollectionItemOrder(db, collectionID)
+	if err != nil || len(ids) == 0 {
+		return nil, nil, err
+	}
+	var rows []models.ContentItem
+	if err := publicContentQuery(db).Where("content_items.public_id IN ?", ids).Find(&rows).Error; err != nil {
+		return nil, nil, err
+	}
+	byID := make(map[uuid.UUID]models.ContentItem, len(rows))
+	for _, row := range rows {
+		byID[row.PublicID] = row
+	}
+	keptPlacements := make([]models.BookmarkCollectionItem, 0, len(placements))
+	content := make([]models.ContentItem, 0, len(placements))
+	for _, placement := range placements {
+		row, ok := byID[placement.ContentItemID]
+		if !ok {
+			continue
+		}
+		keptPlacements = append(keptPlacements, placement)
+		content = append(content, row)
+	}
+	return keptPlacements, content, nil
+}
+
+func bookmarkCollectionEntries(placements []models.BookmarkCollectionItem, content []models.ContentItem) []BookmarkCollectionEntry {
+	entries := make([]BookmarkCollectionEntry, 0, len(placements))
+	for i, placement := range placements {
+		entries = append(entries, BookmarkCollectionEntry{
+			Position: placement.Position,
+			Note:     placement.Note,
+			AddedAt:  placement.CreatedAt,
+			Item:     mapToPodsItem(content[i], false, false),
+		})
+	}
+	return entries
+}
+
+func bookmarkCollectionError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, errBookmarkCollectionNotFound):
+		c.JSON(http.StatusNotFound, utils.HTTPError{Code: http.StatusNotFound, Message: "Collection not found"})
+	case errors.Is(err, errBookmarkCollectionItemGone):
+		c.JSON(http.StatusNotFound, utils.HTTPError{Code: http.StatusNotFound, Message: "Content is not in this collection"})
+	case errors.Is(err, errBookmarkCollectionNameTaken), errors.Is(err, errBookmarkCollectionItemDup):
+		c.JSON(http.StatusConflict, utils.HTTPError{Code: http.StatusConflict, Message: err.Error()})
+	case errors.Is(err, errBookmarkCollectionLimit), errors.Is(err, errBookmarkCollectionOrder):
+		c.JSON(http.StatusUnprocessableEntity, utils.HTTPError{Code: http.StatusUnprocessableEntity, Message: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, utils.HTTPError{Code: http.StatusInternalServerError, Message: fallback})
+	}
+}
+
+// bookmarkCollectionParams resolves the verified caller and the :id param.
+func bookmarkCollectionParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	uid, ok := authedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, utils.HTTPError{Code: http.StatusUnauthorized, Message: "Authentication required"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	collectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.HTTPError{Code: http.StatusBadRequest, Message: "Invalid collection id"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return uid, collectionID, true
+}
+
+// ListBookmarkCollections lists the caller's collections, most recently
+// changed first.
+// GET /api/v1/me/collections
+func ListBookmarkCollections(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+
+	uid, ok := authedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, utils.HTTPError{Code: http.StatusUnauthorized, Message: "Authentication required"})
+		return
+	}
+	var collections []models.BookmarkCollection
+	if err := db.Where("tenant_id = ? AND user_id = ?", consumerTenant, uid).
+		Order("updated_at DESC, id DESC").Find(&collections).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.HTTPError{Code: http.StatusInternalServerError, Message: "Failed to load collections"})
+		return
+	}
+	items := make([]bookmarkCollectionResponse, 0, len(collections))
+	for _, collection := range collections {
+		items = append(items, newBookmarkCollectionResponse(c, collection))
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
+
+// CreateBookmarkCollection creates an empty, private collection.
+// POST /api/v1/me/collections
+func CreateBookmarkCollection(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+
+	uid, ok := authedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, utils.HTTPError{Code: http.StatusUnauthorized, Message: "Authentication required"})
+		return
+	}
+	var req bookmarkCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Name == nil {
+		c.JSON(http.StatusBadRequest, utils.HTTPError{Code: http.StatusBadRequest, Message: "name is required"})
+		return
+	}
+	name, err := normalizeBookmarkCollectionText("name", *req.Name, maxBookmarkCollectionNameRunes, true)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.HTTPError{Code: http.StatusBadRequest, Message: err.Error()})
+		return
+	}
+	description := ""
+	if req.Description != nil {
+		if description, err = normalizeBookmarkCollectionText("description", *req.Description, maxBookmarkCollectionTextRunes, false); err != nil {
+			c.JSON(http.StatusBadRequest, utils.HTTPError{Code: http.StatusBadRequest, Message: err.Error()})
+			return
+		}
+	}
+
+	var collection models.BookmarkCollection
+	err = db.Transaction(func(tx *gorm.DB) error {
+		// Serializes the per-user limit and name checks.
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?)::bigint)", "bookmark-collections:"+uid.String()).Error; err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Model(&models.BookmarkCollection{}).Where("tenant_id = ? AND user_id = ?", consumerTenant, uid).Count(&count).Error; err != nil {
+			return err
+		}
+		if count >= maxBookmarkCollections {
+			return errBookmarkCollectionLimit
+		}
+		if taken, err := bookmarkCollectionNameTaken(tx, uid, name, nil); err != nil || taken {
+			if taken {
+				return errBookmarkCollectionNameTaken
+			}
+			return err
+		}
+		collection = models.BookmarkCollection{TenantID: consumerTenant, UserID: uid, Name: name, Description: description}
+		return tx.Create(&collection).Error
+	})
+	if err != nil {
+		bookmarkCollectionError(c, err, "Failed to create collection")
+		return
+	}
+	c.JSON(http.StatusCreated, utils.ResponseMessage{Code: http.StatusCreated, Message: "Collection created", Data: newBookmarkCollectionResponse(c, collection)})
+}
+
+// GetBookmarkCollection returns one of the caller's collections with its
+// items in order.
+// GET /api/v1/me/collections/:id
+func GetBookmarkCollection(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+
+	uid, collectionID, ok := bookmarkCollectionParams(c)
+	if !ok {
+		return
+	}
+	var collection models.BookmarkCollection
+	if err := db.Where("public_id = ? AND tenant_id = ? AND user_id = ?", collectionID, consumerTenant, uid).
+		First(&collection).Error; err != nil {
+		bookmarkCollectionError(c, errBookmarkCollectionNotFound, "")
+		return
+	}
+	placements, content, err := bookmarkCollectionContents(db, collection.PublicID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.HTTPError{Code: http.StatusInternalServerError, Message: "Failed to load collection"})
+		return
+	}
+	response := newBookmarkCollectionResponse(c, collection)
+	response.Items = bookmarkCollectionEntries(placements, content)
+	if response.Items == nil {
+		response.Items = []BookmarkCollectionEntry{}
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdateBookmarkCollection renames a collection or changes its description.
+// PATCH /api/v1/me/collections/:id
+func UpdateBookmarkCollection(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+
+	uid, collectionID, ok := bookmarkCollectionParams(c)
+	if !ok {
+		return
+	}
+	var req bookmarkCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil || (req.Name == nil && req.Description == nil) {
+		c.JSON(http.StatusBadRequest, utils.HTTPError{Code: http.StatusBadRequest, Message: "name or description is required"})
+		return
+	}
+	updates := map[string]any{"updated_at": time.Now().UTC()}
+	var err error
+	if req.Name != nil {
+		var name string
+		if name, err = normalizeBookmarkCollectionText("name", *req.Name, maxBookmarkCollectionNameRunes, true); err != nil {
+			c.JSON(http.StatusBadRequest, utils.HTTPError{Code: http.StatusBadRequest, Message: err.Error()})
+			return
+		}
+		updates["name"] = name
+	}
+	if req.Description != nil {
+		var description string
+		if description, err = normalizeBookmarkCollectionText("description", *req.Description, maxBookmarkCollectionTextRunes, false); err != nil {
+			c.JSON(http.StatusBadRequest, utils.HTTPError{Code: http.StatusBadRequest, Message: err.Error()})
+			return
+		}
+		updates["description"] = description
+	}
+
+	var collection models.BookmarkCollection
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?)::bigint)", "bookmark-collections:"+uid.String()).Error; err != nil {
+			return err
+		}
+		var err error
+		if collection, err = lockBookmarkCollection(tx, uid, collectionID); err != nil {
+			return err
+		}
+		if name, ok := updates["name"].(string); ok {
+			taken, err := bookmarkCollectionNameTaken(tx, uid, name, &collection.PublicID)
+			if err != nil {
+				return err
+			}
+			if taken {
+				return errBookmarkCollectionNameTaken
+			}
+		}
+		if err := tx.Model(&collection).Updates(updates).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", collection.ID).First(&collection).Error
+	})
+	if err != nil {
+		bookmarkCollectionError(c, err, "Failed to update collection")
+		return
+	}
+	c.JSON(http.StatusOK, utils.ResponseMessage{Code: http.StatusOK, Message: "Collection updated", Data: newBookmarkCollectionResponse(c, collection)})
+}
+
+// DeleteBookmarkCollection deletes a collection and its placements. The
+// bookmarked content itself is untouched.
+// DELETE /api/v1/me/collections/:id
+func DeleteBookmarkCollection(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+
+	uid, collectionID, ok := bookmarkCollectionParams(c)
+	if !ok {
+		return
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		collection, err := lockBookmarkCollection(tx, uid, collectionID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("collection_id = ?", collection.PublicID).Delete(&models.BookmarkCollectionItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&collection).Error
+	})
+	if err != nil {
+		bookmarkCollectionError(c, err, "Failed to delete collection")
+		return
+	}
+	c.JSON(http.StatusOK, utils.ResponseMessage{Code: http.StatusOK, Message: "Collection deleted"})
+}
+
+// AddBookmarkCollectionItem places a Pods or News item in a collection,
+// appended unless a position is given.
+// POST /api/v1/me/collections/:id/items
+func AddBookmarkCollectionItem(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+
+	uid, collectionID, ok := bookmarkCollectionParams(c)
+	if !ok {
+		return
+	}
+	var req bookmarkCollectionItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.HTTPError{Code: http.StatusBadRequest, Message: "Invalid request body"})
+		return
+	}
+	contentID, err := uuid.Parse(strings.TrimSpace(req.ContentID))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.HTTPError{Code: http.StatusBadRequest, Message: "content_id must be a UUID"})
+		return
+	}
+	note := ""
+	if req.Note != nil {
+		if note, err = normalizeBookmarkCollectionText("note", *req.Note, maxBookmarkCollectionTextRunes, false); err != nil {
+			c.JSON(http.StatusBadRequest, utils.HTTPError{Code: http.StatusBadRequest, Message: err.Error()})
+			return
+		}
+	}
+	var content models.ContentItem
+	if err := publicContentQuery(db).Where("content_items.public_id = ?", contentID).First(&content).Error; err != nil {
+		c.JSON(http.StatusNotFound, utils.HTTPError{Code: http.StatusNotFound, Message: "Content not found"})
+		return
+	}
+
+	var placement models.BookmarkCollectionItem
+	err = db.Transaction(func(tx *gorm.DB) error {
+		collection, err := lockBookmarkCollection(tx, uid, collectionID)
+		if err != nil {
+			return err
+		}
+		items, ids, err := bookmarkCollectionItemOrder(tx, collection.PublicID)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			if id == contentID {
+				return errBookmarkCollectionItemDup
+			}
+		}
+		if len(ids) >= maxBookmarkCollectionItems {
+			return errBookmarkCollectionLimit
+		}
+		position := len(ids)
+		if req.Position != nil {
+			position = *req.Position
+		}
+		order := moveBookmarkCollectionItem(ids, contentID, position)
+		if err := applyBookmarkCollectionOrder(tx, collection.PublicID, items, order); err != nil {
+			return err
+		}
+		for i, id := range order {
+			if id == contentID {
+				position = i
+			}
+		}
+		placement = models.BookmarkCollectionItem{CollectionID: collection.PublicID, ContentItemID: contentID, Position: position, Note: note}
+		if err := tx.Create(&placement).Error; err != nil {
+			return err
+		}
+		return touchBookmarkCollection(tx, collection.PublicID, 1)
+	})
+	if err != nil {
+		bookmarkCollectionError(c, err, "Failed to add item to collection")
+		return
+	}
+	c.JSON(http.StatusCreated, utils.ResponseMessage{Code: http.StatusCreated, Message: "Item added to collection", Data: BookmarkCollectionEntry{
+		Position: placement.Position,
+		Note:     placement.Note,
+		AddedAt:  placement.CreatedAt,
+		Item:     mapToPodsItem(content, false, false),
+	}})
+}
+
+// UpdateBookmarkCollectionItem edits an item's note and/or moves it.
+// PATCH /api/v1/me/collections/:id/items/:content_id
+func UpdateBookmarkCollectionItem(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+
+	uid, collectionID, ok := bookmarkCollectionParams(c)
+	if !ok {
+		return
+	}
+	contentID, err := uuid.Parse(c.Param("content_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.HTTPError{Code: http.StatusBadRequest, Message: "Invalid content id"})
+		return
+	}
+	var req bookmarkCollectionItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil || (req.Note == nil && req.Position == nil) {
+		c.JSON(http.StatusBadRequest, utils.HTTPError{Code: http.StatusBadRequest, Message: "note or position is required"})
+		return
+	}
+	var note string
+	if req.Note != nil {
+		if note, err = normalizeBookmarkCollectionText("note", *req.Note, maxBookmarkCollectionTextRunes, false); err != nil {
+			c.JSON(http.StatusBadRequest, utils.HTTPError{Code: http.StatusBadRequest, Message: err.Error()})
+			return
+		}
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		collection, err := lockBookmarkCollection(tx, uid, collectionID)
+		if err != nil {
+			return err
+		}
+		items, ids, err := bookmarkCollectionItemOrder(tx, collection.PublicID)
+		if err != nil {
+			return err
+		}
+		found := false
+		for _, id := range ids {
+			found = found || id == contentID
+		}
+		if !found {
+			return errBookmarkCollectionItemGone
+		}
+		if req.Position != nil {
+			if err := applyBookmarkCollectionOrder(tx, collection.PublicID, items, moveBookmarkCollectionItem(ids, contentID, *req.Position)); err != nil {
+				return err
+			}
+		}
+		if req.Note != nil {
+			if err := tx.Model(&models.BookmarkCollectionItem{}).
+				Where("collection_id = ? AND content_item_id = ?", collection.PublicID, contentID).
+				Update("note", note).Error; err != nil {
+				return err
+			}
+		}
+		return touchBookmarkCollection(tx, collection.PublicID, 0)
+	})
+	if err != nil {
+		bookmarkCollectionError(c, err, "Failed to update collection item")
+		return
+	}
+	c.JSON(http.StatusOK, utils.ResponseMessage{Code: http.StatusOK, Message: "Collection item updated"})
+}
+
+// RemoveBookmarkCollectionItem takes an item out of a collection and closes
+// the gap in positions.
+// DELETE /api/v1/me/collections/:id/items/:content_id
+func RemoveBookmarkCollectionItem(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+
+	uid, collectionID, ok := bookmarkCollectionParams(c)
+	if !ok {
+		return
+	}
+	contentID, err := uuid.Parse(c.Param("content_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.HTTPError{Code: http.StatusBadRequest, Message: "Invalid content id"})
+		return
+	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		collection, err := lockBookmarkCollection(tx, uid, collectionID)
+		if err != nil {
+			return err
+		}
+		var placement models.BookmarkCollectionItem
+		if err := tx.Where("collection_id = ? AND content_item_id = ?", collection.PublicID, contentID).First(&placement).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errBookmarkCollectionItemGone
+			}
+			return err
+		}
+		if err := tx.Delete(&placement).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.BookmarkCollectionItem{}).
+			Where("collection_id = ? AND position > ?", collection.PublicID, placement.Position).
+			UpdateColumn("position", gorm.Expr("position - 1")).Error; err != nil {
+			return err
+		}
+		return touchBookmarkCollection(tx, collection.PublicID, -1)
+	})
+	if err != nil {
+		bookmarkCollectionError(c, err, "Failed to remove collection item")
+		return
+	}
+	c.JSON(http.StatusOK, utils.ResponseMessage{Code: http.StatusOK, Message: "Item removed from collection"})
+}
+
+// ReorderBookmarkCollection replaces the whole order in one call. The body
+// must list every item exactly once.
+// PUT /api/v1/me/collections/:id/items/order
+func ReorderBookmarkCollection(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+
+	uid, collectionID, ok := bookmarkCollectionParams(c)
+	if !ok {
+		return
+	}
+	var req bookmarkCollectionOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.HTTPError{Code: http.StatusBadRequest, Message: "content_ids is required"})
+		return
+	}
+	order := make([]uuid.UUID, 0, len(req.ContentIDs))
+	for _, raw := range req.ContentIDs {
+		id, err := uuid.Parse(strings.TrimSpace(raw))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, utils.HTTPError{Code: http.StatusBadRequest, Message: "content_ids must be UUIDs"})
+			return
+		}
+		order = append(order, id)
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		collection, err := lockBookmarkCollection(tx, uid, collectionID)
+		if err != nil {
+			return err
+		}
+		items, ids, err := bookmarkCollectionItemOrder(tx, collection.PublicID)
+		if err != nil {
+			return err
+		}
+		if err := validateBookmarkCollectionOrder(ids, order); err != nil {
+			return err
+		}
+		if err := applyBookmarkCollectionOrder(tx, collection.PublicID, items, order); err != nil {
+			return err
+		}
+		return touchBookmarkCollection(tx, collection.PublicID, 0)
+	})
+	if err != nil {
+		bookmarkCollectionError(c, err, "Failed to reorder collection")
+		return
+	}
+	c.JSON(http.StatusOK, utils.ResponseMessage{Code: http.StatusOK, Message: "Collection reordered"})
+}
+
+// ShareBookmarkCollection turns on the public share link. Sharing an already
+// shared collection returns the existing link.
+// POST /api/v1/me/collections/:id/share
+func ShareBookmarkCollection(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+
+	uid, collectionID, ok := bookmarkCollectionParams(c)
+	if !ok {
+		return
+	}
+	var collection models.BookmarkCollection
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if collection, err = lockBookmarkCollection(tx, uid, collectionID); err != nil || collection.ShareToken != nil {
+			return err
+		}
+		token, err := utils.NewOpaqueToken()
+		if err != nil {
+			return err
+		}
+		now := time.Now().UTC()
+		collection.ShareToken, collection.SharedAt = &token, &now
+		return tx.Model(&collection).Updates(map[string]any{"share_token": token, "shared_at": now, "updated_at": now}).Error
+	})
+	if err != nil {
+		bookmarkCollectionError(c, err, "Failed to share collection")
+		return
+	}
+	c.JSON(http.StatusOK, utils.ResponseMessage{Code: http.StatusOK, Message: "Collection shared", Data: newBookmarkCollectionResponse(c, collection)})
+}
+
+// UnshareBookmarkCollection revokes the share link; sharing again mints a new
+// one.
+// DELETE /api/v1/me/collections/:id/share
+func UnshareBookmarkCollection(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+
+	uid, collectionID, ok := bookmarkCollectionParams(c)
+	if !ok {
+		return
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		collection, err := lockBookmarkCollection(tx, uid, collectionID)
+		if err != nil {
+			return err
+		}
+		return tx.Model(&collection).Updates(map[string]any{"share_token": nil, "shared_at": nil, "updated_at": time.Now().UTC()}).Error
+	})
+	if err != nil {
+		bookmarkCollectionError(c, err, "Failed to unshare collection")
+		return
+	}
+	c.JSON(http.StatusOK, utils.ResponseMessage{Code: http.StatusOK, Message: "Collection is no longer shared"})
+}
+
+func loadSharedBookmarkCollection(c *gin.Context) (models.BookmarkCollection, bool) {
+	db := c.MustGet("db").(*gorm.DB)
+	token := strings.TrimSpace(c.Param("token"))
+	var collection models.BookmarkCollection
+	if token == "" || db.Where("share_token = ?", token).First(&collection).Error != nil {
+		c.JSON(http.StatusNotFound, utils.HTTPError{Code: http.StatusNotFound, Message: "Collection not found"})
+		return collection, false
+	}
+	return collection, true
+}
+
+// GetSharedBookmarkCollection is the read-only view behind a share link. It
+// never exposes the owner.
+// GET /api/v1/collections/shared/:token
+func GetSharedBookmarkCollection(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+
+	collection, ok := loadSharedBookmarkCollection(c)
+	if !ok {
+		return
+	}
+	placements, content, err := bookmarkCollectionContents(db, collection.PublicID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.HTTPError{Code: http.StatusInternalServerError, Message: "Failed to load collection"})
+		return
+	}
+	items := bookmarkCollectionEntries(placements, content)
+	base := publicBaseURL(c)
+	share := bookmarkCollectionShareURL(base, *collection.ShareToken)
+	c.JSON(http.StatusOK, gin.H{
+		"name":        collection.Name,
+		"description": collection.Description,
+		"item_count":  len(items),
+		"updated_at":  collection.UpdatedAt,
+		"feed_url":    share + "/feed?format=podcast",
+		"items":       items,
+	})
+}
+
+// GetSharedBookmarkCollectionFeed syndicates a shared collection in its own
+// order through the saved-feed renderers.
+// GET /api/v1/collections/shared/:token/feed?format=rss|atom|json|podcast
+func GetSharedBookmarkCollectionFeed(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+
+	collection, ok := loadSharedBookmarkCollection(c)
+	if !ok {
+		return
+	}
+	placements, content, err := bookmarkCollectionContents(db, collection.PublicID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "failed to build feed"})
+		return
+	}
+
+	format := savedFeedFormat(c.Query("format"))
+	base := publicBaseURL(c)
+	self := bookmarkCollectionShareURL(base, *collection.ShareToken) + "/feed"
+	if format != "rss" {
+		self += "?format=" + format
+	}
+	revisions, err := bookmarkCollectionFeedRevisions(db, content)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "failed to build feed"})
+		return
+	}
+	validator := bookmarkCollectionFeedValidator(collection, revisions, format+"\n"+self)
+	ids := make([]string, len(revisions))
+	for i, row := range revisions {
+		ids[i] = row.PublicID
+	}
+	validator, err = markFeedMembership(db, "collection:"+collection.PublicID.String(), ids, validator)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "failed to build feed"})
+		return
+	}
+	if writeFeedValidator(c, validator) {
+		return
+	}
+	items, err := feedItemsFor(db, content)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "failed to build feed"})
+		return
+	}
+	for i, placement := range placements {
+		if placement.Note != "" {
+			items[i].Description = strings.TrimSpace(placement.Note + "\n\n" + items[i].Description)
+		}
+	}
+	description := collection.Description
+	if description == "" {
+		description = "A listener collection on the Wahb platform."
+	}
+	writeFeed(c, format, feedMeta{
+		Title:       collection.Name,
+		Description: description,
+		SelfURL:     self,
+		BaseURL:     base,
+		Updated:     validator.LastModified,
+	}, items)
+}
+
+// bookmarkCollectionFeedRevisions loads the feedRevisionRow of each item, in
+// collection order, so chapter publishes and source edits move the validator
+// just as they do for saved feeds.
+func bookmarkCollectionFeedRevisions(db *gorm.DB, content []models.ContentItem) ([]feedRevisionRow, error) {
+	if len(content) == 0 {
+		return nil, nil
+	}
+	ids := make([]uuid.UUID, len(content))
+	for i, item := range content {
+		ids[i] = item.PublicID
+	}
+	var found []feedRevisionRow
+	if err := db.Model(&models.ContentItem{}).
+		Where("content_items.public_id IN ?", ids).
+		Select(feedRevisionColumns, chapterStatusPublished, chapterStatusPublished).
+		Scan(&found).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[string]feedRevisionRow, len(found))
+	for _, row := range found {
+		byID[row.PublicID] = row
+	}
+	rows := make([]feedRevisionRow, 0, len(content))
+	for _, item := range content {
+		row, ok := byID[item.PublicID.String()]
+		if !ok {
+			row = feedRevisionRow{PublicID: item.PublicID.String(), UpdatedAt: item.UpdatedAt}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// bookmarkCollectionFeedValidator hashes the collection row (which moves on
+// every reorder and note edit) with the revisions of the rendered items.
+func bookmarkCollectionFeedValidator(collection models.BookmarkCollection, items []feedRevisionRow, variant string) feedValidator {
+	rows := make([]feedRevisionRow, 0, len(items)+1)
+	rows = append(rows, feedRevisionRow{PublicID: "collection:" + collection.PublicID.String(), UpdatedAt: collection.UpdatedAt})
+	rows = append(rows, items...)
+	return buildFeedValidator(rows, variant, nil)
+}
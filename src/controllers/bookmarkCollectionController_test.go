@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"content-management-system/src/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestMoveBookmarkCollectionItem(t *testing.T) {
+	a, b, c, added := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	ids := []uuid.UUID{a, b, c}
+
+	cases := []struct {
+		name     string
+		id       uuid.UUID
+		position int
+		want     []uuid.UUID
+	}{
+		{"move to front", c, 0, []uuid.UUID{c, a, b}},
+		{"move down", a, 1, []uuid.UUID{b, a, c}},
+		{"clamp past end", a, 99, []uuid.UUID{b, c, a}},
+		{"clamp negative", b, -4, []uuid.UUID{b, a, c}},
+		{"insert new", added, 1, []uuid.UUID{a, added, b, c}},
+		{"append new", added, 3, []uuid.UUID{a, b, c, added}},
+	}
+	for _, tc := range cases {
+		got := moveBookmarkCollectionItem(ids, tc.id, tc.position)
+		if len(got) != len(tc.want) {
+			t.Fatalf("%s: got %v", tc.name, got)
+		}
+		for i := range got {
+			if got[i] != tc.want[i] {
+				t.Fatalf("%s: got %v, want %v", tc.name, got, tc.want)
+			}
+		}
+	}
+	if ids[0] != a || ids[1] != b || ids[2] != c {
+		t.Fatal("input order must not be mutated")
+	}
+}
+
+func TestValidateBookmarkCollectionOrder(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	current := []uuid.UUID{a, b}
+	if err := validateBookmarkCollectionOrder(current, []uuid.UUID{b, a}); err != nil {
+		t.Fatalf("permutation rejected: %v", err)
+	}
+	for _, order := range [][]uuid.UUID{{a}, {a, a}, {a, uuid.New()}, {a, b, b}} {
+		if err := validateBookmarkCollectionOrder(current, order); err != errBookmarkCollectionOrder {
+			t.Fatalf("order %v: err = %v", order, err)
+		}
+	}
+}
+
+func TestBookmarkCollectionFeedValidatorTracksCollectionChanges(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0).UTC()
+	collection := models.BookmarkCollection{PublicID: uuid.New(), UpdatedAt: now}
+	items := []feedRevisionRow{
+		{PublicID: uuid.NewString(), UpdatedAt: now.Add(-time.Hour)},
+		{PublicID: uuid.NewString(), UpdatedAt: now.Add(-2 * time.Hour)},
+	}
+
+	base := bookmarkCollectionFeedValidator(collection, items, "podcast")
+	if !base.LastModified.Equal(now) {
+		t.Fatalf("last modified = %v", base.LastModified)
+	}
+	reordered := bookmarkCollectionFeedValidator(collection, []feedRevisionRow{items[1], items[0]}, "podcast")
+	if reordered.ETag == base.ETag {
+		t.Fatal("item order must change the validator")
+	}
+	edited := collection
+	edited.UpdatedAt = now.Add(time.Minute)
+	if bookmarkCollectionFeedValidator(edited, items, "podcast").ETag == base.ETag {
+		t.Fatal("a collection edit (e.g. a note) must change the validator")
+	}
+	if bookmarkCollectionFeedValidator(collection, items, "rss").ETag == base.ETag {
+		t.Fatal("format must change the validator")
+	}
+	chapterEdit := now.Add(-time.Minute)
+	chaptered := []feedRevisionRow{items[0], {PublicID: items[1].PublicID, UpdatedAt: items[1].UpdatedAt, ChaptersUpdatedAt: &chapterEdit, PublishedChapters: 2}}
+	if bookmarkCollectionFeedValidator(collection, chaptered, "podcast").ETag == base.ETag {
+		t.Fatal("a chapter publish must change the validator")
+	}
+}
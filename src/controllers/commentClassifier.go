@@ -0,0 +1,365 @@
+package controllers
+
+import (
+	"content-management-system/src/models"
+	"content-management-system/src/toxicity"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// commentClassification is the evidence stored on a classified comment
+// (user_interactions.comment_moderation_signals). Moderators see it in the
+// review queue; AdminResolveCommentReview learns from its held categories.
+type commentClassification struct {
+	Scores      map[toxicity.Category]float64 `json:"scores"`
+	Reasons     []toxicity.Reason             `json:"reasons,omitempty"`
+	Classifiers []string                      `json:"classifiers"`
+	// Unavailable lists classifiers that failed; the decision used the rest.
+	Unavailable []string `json:"unavailable,omitempty"`
+	// PolicyReason is the deterministic policy's reason, when it had one.
+	PolicyReason string `json:"policy_reason,omitempty"`
+	AutoAllowed  bool   `json:"auto_allowed,omitempty"`
+}
+
+var (
+	commentLexiconOnce sync.Once
+	commentLexicon     *toxicity.Lexicon
+)
+
+// commentToxicityLexicon compiles the built-in rule packs plus any packs in
+// COMMENT_TOXICITY_RULES_FILE. A broken rules file is logged and ignored so
+// comments keep the built-in coverage.
+func commentToxicityLexicon() *toxicity.Lexicon {
+	commentLexiconOnce.Do(func() {
+		packs := toxicity.DefaultRulePacks()
+		if path := strings.TrimSpace(os.Getenv("COMMENT_TOXICITY_RULES_FILE")); path != "" {
+			extra, err := toxicity.LoadRulePacks(path)
+			if err == nil {
+				_, err = toxicity.NewLexicon(extra...)
+			}
+			if err != nil {
+				log.Printf("comment toxicity: ignoring rules file: %v", err)
+			} else {
+				packs = append(packs, extra...)
+			}
+		}
+		commentLexicon, _ = toxicity.NewLexicon(packs...)
+	})
+	return commentLexicon
+}
+
+// commentToxicityScorer returns the remote scorer, or nil when none is
+// configured. COMMENT_TOXICITY_SCORER=stub swaps in a local stub that scores
+// nothing, for development without the model. Tests replace the variable.
+var commentToxicityScorer = func() toxicity.Classifier {
+	if strings.EqualFold(strings.TrimSpace(os.Getenv("COMMENT_TOXICITY_SCORER")), "stub") {
+		return &toxicity.StubScorer{}
+	}
+	baseURL := strings.TrimSpace(os.Getenv("COMMENT_TOXICITY_SCORER_URL"))
+	if baseURL == "" {
+		return nil
+	}
+	return &toxicity.HTTPScorer{BaseURL: baseURL, Token: commentToxicityScorerToken()}
+}
+
+// commentToxicityScorerToken follows the shared service-token fallback chain.
+func commentToxicityScorerToken() string {
+	if token := strings.TrimSpace(os.Getenv("COMMENT_TOXICITY_SCORER_TOKEN")); token != "" {
+		return token
+	}
+	if token := strings.TrimSpace(os.Getenv("SERVICE_AUTH_TOKEN")); token != "" {
+		return token
+	}
+	return strings.TrimSpace(os.Getenv("CMS_SERVICE_TOKEN"))
+}
+
+func loadCommentClassifierConfig(db *gorm.DB, tenantID string) models.CommentClassifierConfig {
+	var config models.CommentClassifierConfig
+	if err := db.Where("tenant_id = ?", tenantID).First(&config).Error; err != nil {
+		return models.DefaultCommentClassifierConfig(tenantID)
+	}
+	return config
+}
+
+func loadCommentClassifierStats(db *gorm.DB, tenantID string) []models.CommentClassifierStat {
+	var stats []models.CommentClassifierStat
+	if err := db.Where("tenant_id = ?", tenantID).Order("category").Find(&stats).Error; err != nil {
+		return nil
+	}
+	return stats
+}
+
+// commentClassifierThresholds overlays the tenant's thresholds on the
+// defaults and, when learning is on, moves hold thresholds by the observed
+// false-positive rate.
+func commentClassifierThresholds(config models.CommentClassifierConfig, stats []models.CommentClassifierStat) toxicity.Thresholds {
+	t := toxicity.DefaultThresholds()
+	overlayCommentThresholds(t.Hold, config.HoldThresholds)
+	overlayCommentThresholds(t.Reject, config.RejectThresholds)
+	if config.LearningEnabled {
+		for _, s := range stats {
+			cat := toxicity.Category(s.Category)
+			t.Hold[cat] = toxicity.LearnedHold(t.Hold[cat], s.Overturned, s.Confirmed)
+		}
+	}
+	return t
+}
+
+func overlayCommentThresholds(dst map[toxicity.Category]float64, raw datatypes.JSON) {
+	var values map[string]float64
+	if len(raw) == 0 || json.Unmarshal(raw, &values) != nil {
+		return
+	}
+	for key, value := range values {
+		if cat := toxicity.Category(key); toxicity.ValidCategory(cat) && value >= 0 && value <= 1 {
+			dst[cat] = value
+		}
+	}
+}
+
+// evaluateCommentPolicyForTenant runs evaluateCommentPolicy and then, unless
+// it already rejected, the toxicity classifier under the tenant's
+// thresholds. The stricter outcome wins. A deterministic review is released
+// only when the tenant set auto_allow_below, every classifier answered, and
+// every score stayed under it.
+func evaluateCommentPolicyForTenant(ctx context.Context, db *gorm.DB, tenantID, text string) commentPolicyDecision {
+	decision := evaluateCommentPolicy(text)
+	if decision.Outcome == commentPolicyReject {
+		return decision
+	}
+	config := loadCommentClassifierConfig(db, tenantID)
+	var stats []models.CommentClassifierStat
+	if config.LearningEnabled {
+		stats = loadCommentClassifierStats(db, tenantID)
+	}
+	classifiers := []toxicity.Classifier{commentToxicityLexicon()}
+	if config.ScorerEnabled {
+		if scorer := commentToxicityScorer(); scorer != nil {
+			classifiers = append(classifiers, scorer)
+		}
+	}
+	result := toxicity.Run(ctx, text, classifiers...)
+	verdict := toxicity.Decide(result, commentClassifierThresholds(config, stats))
+
+	evidence := &commentClassification{
+		Scores: result.Scores, Reasons: verdict.Reasons, Classifiers: result.Classifiers,
+		Unavailable: result.Unavailable, PolicyReason: decision.Reason,
+	}
+	switch {
+	case verdict.Outcome == toxicity.OutcomeReject:
+		primary, _ := verdict.Primary()
+		decision = commentPolicyDecision{Outcome: commentPolicyReject, Reason: "toxicity:" + string(primary.Category)}
+	case verdict.Outcome == toxicity.OutcomeReview:
+		primary, _ := verdict.Primary()
+		decision = commentPolicyDecision{Outcome: commentPolicyReview, Reason: "toxicity:" + string(primary.Category)}
+	case decision.Outcome == commentPolicyReview && config.AutoAllowBelow > 0 &&
+		len(result.Unavailable) == 0 && result.Max() < config.AutoAllowBelow:
+		evidence.AutoAllowed = true
+		decision = commentPolicyDecision{Outcome: commentPolicyAllow}
+	}
+	decision.Signals = evidence
+	return decision
+}
+
+// recordCommentClassifierOutcome counts a moderator's decision against every
+// category the classifier held the comment for. Comments held only by the
+// deterministic policy carry no held categories and are not counted.
+func recordCommentClassifierOutcome(tx *gorm.DB, tenantID string, signals datatypes.JSON, removed bool) error {
+	var evidence commentClassification
+	if len(signals) == 0 || json.Unmarshal(signals, &evidence) != nil {
+		return nil
+	}
+	column := "overturned"
+	if removed {
+		column = "confirmed"
+	}
+	for _, reason := range evidence.Reasons {
+		if reason.Outcome != toxicity.OutcomeReview {
+			continue
+		}
+		row := models.CommentClassifierStat{TenantID: tenantID, Category: string(reason.Category)}
+		if removed {
+			row.Confirmed = 1
+		} else {
+			row.Overturned = 1
+		}
+		if err := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "tenant_id"}, {Name: "category"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				column:       gorm.Expr("comment_classifier_stats." + column + " + 1"),
+				"updated_at": time.Now().UTC(),
+			}),
+		}).Create(&row).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type commentClassifierCategoryView struct {
+	Category          toxicity.Category `json:"category"`
+	HoldThreshold     float64           `json:"hold_threshold"`
+	RejectThreshold   *float64          `json:"reject_threshold,omitempty"`
+	EffectiveHold     float64           `json:"effective_hold"`
+	Confirmed         int64             `json:"confirmed"`
+	Overturned        int64             `json:"overturned"`
+	FalsePositiveRate *float64          `json:"false_positive_rate,omitempty"`
+}
+
+func commentClassifierView(config models.CommentClassifierConfig, stats []models.CommentClassifierStat) gin.H {
+	configured := commentClassifierThresholds(config, nil)
+	effective := commentClassifierThresholds(config, stats)
+	byCategory := map[string]models.CommentClassifierStat{}
+	for _, s := range stats {
+		byCategory[s.Category] = s
+	}
+	categories := make([]commentClassifierCategoryView, 0, len(toxicity.Categories))
+	for _, cat := range toxicity.Categories {
+		s := byCategory[string(cat)]
+		view := commentClassifierCategoryView{
+			Category: cat, HoldThreshold: configured.Hold[cat], EffectiveHold: effective.Hold[cat],
+			Confirmed: s.Confirmed, Overturned: s.Overturned,
+		}
+		if limit, ok := configured.Reject[cat]; ok && limit > 0 {
+			view.RejectThreshold = &limit
+		}
+		if n := s.Confirmed + s.Overturned; n > 0 {
+			rate := float64(s.Overturned) / float64(n)
+			view.FalsePositiveRate = &rate
+		}
+		categories = append(categories, view)
+	}
+	return gin.H{
+		"config":            config,
+		"categories":        categories,
+		"scorer_configured": commentToxicityScorer() != nil,
+		"learning": gin.H{
+			"min_samples":                toxicity.MinLearningSamples,
+			"target_false_positive_rate": toxicity.TargetFalsePositiveRate,
+		},
+	}
+}
+
+// AdminGetCommentClassifier handles GET /admin/moderation/comments/classifier:
+// the tenant's thresholds, review statistics and the hold thresholds in
+// effect after learning.
+func AdminGetCommentClassifier(c *gin.Context) {
+	principal, ok := requireAdminPrincipal(c)
+	if !ok {
+		return
+	}
+	db := c.MustGet("db").(*gorm.DB)
+	config := loadCommentClassifierConfig(db, principal.TenantID)
+	c.JSON(http.StatusOK, commentClassifierView(config, loadCommentClassifierStats(db, principal.TenantID)))
+}
+
+type updateCommentClassifierRequest struct {
+	HoldThresholds   map[string]float64 `json:"hold_thresholds"`
+	RejectThresholds map[string]float64 `json:"reject_thresholds"`
+	AutoAllowBelow   *float64           `json:"auto_allow_below"`
+	ScorerEnabled    *bool              `json:"scorer_enabled"`
+	LearningEnabled  *bool              `json:"learning_enabled"`
+	// ResetStats clears the review statistics, e.g. after changing thresholds
+	// substantially.
+	ResetStats bool `json:"reset_stats"`
+}
+
+func validateCommentThresholds(values map[string]float64) error {
+	for key, value := range values {
+		if !toxicity.ValidCategory(toxicity.Category(key)) {
+			return fmt.Errorf("unknown category %q", key)
+		}
+		if value < 0 || value > 1 {
+			return fmt.Errorf("threshold for %s must be between 0 and 1", key)
+		}
+	}
+	return nil
+}
+
+// AdminUpdateCommentClassifier handles PUT /admin/moderation/comments/classifier.
+// Omitted fields are unchanged; threshold maps replace the stored map. A
+// reject threshold of 0 disables auto-rejection for that category.
+func AdminUpdateCommentClassifier(c *gin.Context) {
+	principal, ok := requireAdminPrincipal(c)
+	if !ok {
+		return
+	}
+	var req updateCommentClassifierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request: " + err.Error()})
+		return
+	}
+	for _, values := range []map[string]float64{req.HoldThresholds, req.RejectThresholds} {
+		if err := validateCommentThresholds(values); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
+	}
+	if req.AutoAllowBelow != nil && (*req.AutoAllowBelow < 0 || *req.AutoAllowBelow > 1) {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "auto_allow_below must be between 0 and 1"})
+		return
+	}
+
+	db := c.MustGet("db").(*gorm.DB)
+	config := loadCommentClassifierConfig(db, principal.TenantID)
+	if req.HoldThresholds != nil {
+		encoded, _ := json.Marshal(req.HoldThresholds)
+		config.HoldThresholds = datatypes.JSON(encoded)
+	}
+	if req.RejectThresholds != nil {
+		encoded, _ := json.Marshal(req.RejectThresholds)
+		config.RejectThresholds = datatypes.JSON(encoded)
+	}
+	if req.AutoAllowBelow != nil {
+		config.AutoAllowBelow = *req.AutoAllowBelow
+	}
+	if req.ScorerEnabled != nil {
+		config.ScorerEnabled = *req.ScorerEnabled
+	}
+	if req.LearningEnabled != nil {
+		config.LearningEnabled = *req.LearningEnabled
+	}
+	// Holding at or above the reject threshold would never reach review.
+	thresholds := commentClassifierThresholds(config, nil)
+	var inverted []string
+	for cat, reject := range thresholds.Reject {
+		if reject > 0 && thresholds.Hold[cat] >= reject {
+			inverted = append(inverted, string(cat))
+		}
+	}
+	if len(inverted) > 0 {
+		sort.Strings(inverted)
+		c.JSON(http.StatusBadRequest, gin.H{"message": "hold threshold must be below reject threshold for " + strings.Join(inverted, ", ")})
+		return
+	}
+	config.UpdatedBy = principal.Email
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&config).Error; err != nil {
+			return err
+		}
+		if req.ResetStats {
+			return tx.Where("tenant_id = ?", principal.TenantID).Delete(&models.CommentClassifierStat{}).Error
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to save comment classifier config"})
+		return
+	}
+	writeModerationAudit(db, principal, "moderation.comment_classifier.update", principal.TenantID, map[string]any{"reset_stats": req.ResetStats})
+	c.JSON(http.StatusOK, commentClassifierView(config, loadCommentClassifierStats(db, principal.TenantID)))
+}
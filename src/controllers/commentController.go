@@ -0,0 +1,741 @@
+package controllers
+
+import (
+	"content-management-system/src/models"
+	"content-management-system/src/utils"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxCommentDepth is the deepest reply level. A reply to a comment already at
+// this depth is attached to that comment's parent instead, so long back-and-
+// forth exchanges stay readable on a phone.
+const maxCommentDepth = 3
+
+// maxCommentThreadReplies bounds the replies loaded for each top-level
+// comment on a page. reply_count still reports every visible direct reply, so
+// a client can tell a long thread was cut short.
+const maxCommentThreadReplies = 200
+
+type commentSort string
+
+const (
+	commentSortNewest commentSort = "newest"
+	commentSortTop    commentSort = "top"
+	commentSortOldest commentSort = "oldest"
+)
+
+// CommentItem is a single comment in a content item's comment list. Deleted
+// comments that still have replies are listed as tombstones: no text, author
+// or reactions.
+type CommentItem struct {
+	ID         uuid.UUID      `json:"id"`
+	ParentID   *uuid.UUID     `json:"parent_id,omitempty"`
+	Text       string         `json:"text"`
+	Author     string         `json:"author,omitempty"`
+	AuthorID   *uuid.UUID     `json:"author_id,omitempty"`
+	IsMine     bool           `json:"is_mine"`
+	Deleted    bool           `json:"deleted"`
+	Edited     bool           `json:"edited"`
+	EditedAt   *time.Time     `json:"edited_at,omitempty"`
+	Reactions  map[string]int `json:"reactions,omitempty"`
+	MyReaction string         `json:"my_reaction,omitempty"`
+	ReplyCount int            `json:"reply_count"`
+	Replies    []CommentItem  `json:"replies,omitempty"`
+	CreatedAt  time.Time      `json:"created_at"`
+}
+
+// visibleCommentQuery selects comments the public may see: held (review)
+// comments stay private until a moderator decides; legacy NULL is allowed.
+func visibleCommentQuery(db *gorm.DB) *gorm.DB {
+	return db.Model(&models.UserInteraction{}).
+		Where("type = ?", models.InteractionTypeComment).
+		Where("comment_moderation_status IS NULL OR comment_moderation_status = ?", string(commentPolicyAllow))
+}
+
+func commentIsVisible(comment models.UserInteraction) bool {
+	return comment.CommentDeletedAt == nil &&
+		(comment.CommentModerationStatus == nil || *comment.CommentModerationStatus == string(commentPolicyAllow))
+}
+
+// commentPlacement threads a reply under parent, flattening below
+// maxCommentDepth.
+func commentPlacement(parent models.UserInteraction) (parentID *uuid.UUID, rootID *uuid.UUID, depth int) {
+	root := parent.PublicID
+	if parent.CommentRootID != nil {
+		root = *parent.CommentRootID
+	}
+	if parent.CommentDepth >= maxCommentDepth && parent.CommentParentID != nil {
+		grandparent := *parent.CommentParentID
+		return &grandparent, &root, parent.CommentDepth
+	}
+	id := parent.PublicID
+	return &id, &root, parent.CommentDepth + 1
+}
+
+// removeComment deletes a comment for its author or a moderator. Reactions
+// and edit history go with it. A comment with replies becomes a tombstone so
+// the thread keeps its shape; otherwise the row is deleted, along with any
+// tombstoned ancestors it was the last reply of.
+func removeComment(tx *gorm.DB, comment models.UserInteraction) error {
+	if commentIsVisible(comment) {
+		if err := updateEngagementCount(tx, comment.ContentItemID, models.InteractionTypeComment, -1); err != nil {
+			return err
+		}
+	}
+	if err := tx.Where("comment_id = ?", comment.PublicID).Delete(&models.CommentReaction{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("comment_id = ?", comment.PublicID).Delete(&models.CommentRevision{}).Error; err != nil {
+		return err
+	}
+	var replies int64
+	if err := tx.Model(&models.UserInteraction{}).Where("comment_parent_id = ?", comment.PublicID).Count(&replies).Error; err != nil {
+		return err
+	}
+	if replies > 0 {
+		return tx.Model(&comment).Updates(map[string]any{
+			"metadata":           datatypes.JSON(`{}`),
+			"comment_score":      0,
+			"comment_deleted_at": time.Now().UTC(),
+		}).Error
+	}
+	if err := tx.Delete(&comment).Error; err != nil {
+		return err
+	}
+	for parentID := comment.CommentParentID; parentID != nil; {
+		var parent models.UserInteraction
+		err := tx.Where("public_id = ? AND comment_deleted_at IS NOT NULL", *parentID).First(&parent).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&models.UserInteraction{}).Where("comment_parent_id = ?", parent.PublicID).Count(&replies).Error; err != nil {
+			return err
+		}
+		if replies > 0 {
+			return nil
+		}
+		if err := tx.Delete(&parent).Error; err != nil {
+			return err
+		}
+		parentID = parent.CommentParentID
+	}
+	return nil
+}
+
+// loadPublicComment finds a live, visible comment on public content.
+func loadPublicComment(db *gorm.DB, id uuid.UUID) (models.UserInteraction, models.ContentItem, bool) {
+	var comment models.UserInteraction
+	var contentItem models.ContentItem
+	if err := visibleCommentQuery(db).Where("public_id = ? AND comment_deleted_at IS NULL", id).First(&comment).Error; err != nil {
+		return comment, contentItem, false
+	}
+	if err := publicContentQuery(db).Where("public_id = ?", comment.ContentItemID).First(&contentItem).Error; err != nil {
+		return comment, contentItem, false
+	}
+	return comment, contentItem, true
+}
+
+func encodeTopCommentCursor(score int, createdAt time.Time, id uuid.UUID) string {
+	raw := fmt.Sprintf("%d:%d:%s", score, createdAt.UnixNano(), id)
+	return base64.URLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeTopCommentCursor(cursor string) (int, time.Time, uuid.UUID, error) {
+	decoded, err := base64.URLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, time.Time{}, uuid.Nil, fmt.Errorf("invalid cursor encoding: %w", err)
+	}
+	parts := strings.SplitN(string(decoded), ":", 3)
+	if len(parts) != 3 {
+		return 0, time.Time{}, uuid.Nil, fmt.Errorf("invalid cursor format")
+	}
+	score, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, time.Time{}, uuid.Nil, fmt.Errorf("invalid cursor score: %w", err)
+	}
+	nanos, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, time.Time{}, uuid.Nil, fmt.Errorf("invalid cursor timestamp: %w", err)
+	}
+	id, err := uuid.Parse(parts[2])
+	if err != nil {
+		return 0, time.Time{}, uuid.Nil, fmt.Errorf("invalid cursor uuid: %w", err)
+	}
+	return score, time.Unix(0, nanos), id, nil
+}
+
+// commentThreadView is what buildCommentThreads needs to know about the
+// caller and the listed comments' reactions.
+type commentThreadView struct {
+	callerUserID *uuid.UUID
+	sessionID    string
+	reactions    map[uuid.UUID]map[string]int
+	mine         map[uuid.UUID]string
+	// replyCounts is the number of visible direct replies per comment,
+	// including any beyond maxCommentThreadReplies.
+	replyCounts map[uuid.UUID]int
+}
+
+// buildCommentThreads nests replies under their top-level comments in
+// oldest-first order. A reply whose parent was not loaded (held, blocked or
+// removed) is dropped with its subtree, and a tombstone with no remaining
+// replies is dropped too.
+func buildCommentThreads(roots, replies []models.UserInteraction, view commentThreadView) []CommentItem {
+	children := make(map[uuid.UUID][]models.UserInteraction)
+	for _, reply := range replies {
+		if reply.CommentParentID != nil {
+			children[*reply.CommentParentID] = append(children[*reply.CommentParentID], reply)
+		}
+	}
+	var build func(in models.UserInteraction) (CommentItem, bool)
+	build = func(in models.UserInteraction) (CommentItem, bool) {
+		item := CommentItem{ID: in.PublicID, ParentID: in.CommentParentID, CreatedAt: in.CreatedAt}
+		for _, child := range children[in.PublicID] {
+			if reply, ok := build(child); ok {
+				item.Replies = append(item.Replies, reply)
+			}
+		}
+		item.ReplyCount = max(len(item.Replies), view.replyCounts[in.PublicID])
+		if in.CommentDeletedAt != nil {
+			item.Deleted = true
+			return item, len(item.Replies) > 0
+		}
+		var meta commentMetadata
+		if err := json.Unmarshal(in.Metadata, &meta); err != nil || strings.TrimSpace(meta.Text) == "" {
+			return item, false // skip malformed rows rather than failing the whole list
+		}
+		item.Text = meta.Text
+		item.Author = meta.Author
+		item.AuthorID = in.UserID
+		item.IsMine = (view.callerUserID != nil && in.UserID != nil && *in.UserID == *view.callerUserID) ||
+			(view.sessionID != "" && in.SessionID != nil && *in.SessionID == view.sessionID)
+		item.Edited = in.CommentEditedAt != nil
+		item.EditedAt = in.CommentEditedAt
+		item.Reactions = view.reactions[in.PublicID]
+		item.MyReaction = view.mine[in.PublicID]
+		return item, true
+	}
+	items := make([]CommentItem, 0, len(roots))
+	for _, root := range roots {
+		if item, ok := build(root); ok {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// GetContentComments lists a content item's top-level comments with their
+// reply threads.
+// GET /api/v1/content/:id/comments?sort=newest|top|oldest&cursor=xxx&limit=20&session_id=xxx
+// session_id is optional and only used to mark the caller's own legacy
+// comments (is_mine) so the client can label them.
+func GetContentComments(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+
+	contentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid content ID",
+		})
+		return
+	}
+
+	sort := commentSort(strings.ToLower(strings.TrimSpace(c.DefaultQuery("sort", string(commentSortNewest)))))
+	if sort != commentSortNewest && sort != commentSortTop && sort != commentSortOldest {
+		c.JSON(http.StatusBadRequest, utils.HTTPError{Code: http.StatusBadRequest, Message: "sort must be newest, top or oldest"})
+		return
+	}
+	cursor := c.Query("cursor")
+	timeCursor := cursor
+	if sort == commentSortTop {
+		timeCursor = ""
+	}
+	pagination, err := utils.ParseCursorParams(timeCursor, c.Query("limit"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid cursor: " + err.Error(),
+		})
+		return
+	}
+
+	var contentItem models.ContentItem
+	if err := publicContentQuery(db).Where("public_id = ?", contentID).First(&contentItem).Error; err != nil {
+		c.JSON(http.StatusNotFound, utils.HTTPError{Code: http.StatusNotFound, Message: "Content item not found"})
+		return
+	}
+
+	var blockedIDs []uuid.UUID
+	uid, authenticated := authedUserID(c)
+	if authenticated {
+		if err := db.Model(&models.UserBlock{}).
+			Where("tenant_id = ? AND user_id = ?", consumerTenant, uid).
+			Pluck("blocked_user_id", &blockedIDs).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, utils.HTTPError{Code: http.StatusInternalServerError, Message: "Failed to load comment visibility"})
+			return
+		}
+	}
+	unblocked := func(query *gorm.DB) *gorm.DB {
+		if len(blockedIDs) > 0 {
+			return query.Where("user_id IS NULL OR user_id NOT IN ?", blockedIDs)
+		}
+		return query
+	}
+
+	// A tombstone is only worth a slot on the page while it has a live reply.
+	query := unblocked(visibleCommentQuery(db)).
+		Where("content_item_id = ? AND comment_parent_id IS NULL", contentID).
+		Where("comment_deleted_at IS NULL OR EXISTS (SELECT 1 FROM user_interactions r WHERE r.comment_root_id = user_interactions.public_id AND r.comment_deleted_at IS NULL AND (r.comment_moderation_status IS NULL OR r.comment_moderation_status = ?))", string(commentPolicyAllow))
+	switch sort {
+	case commentSortTop:
+		query = query.Order("comment_score DESC, created_at DESC, public_id DESC")
+		if cursor != "" {
+			score, createdAt, lastID, err := decodeTopCommentCursor(cursor)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, utils.HTTPError{Code: http.StatusBadRequest, Message: "Invalid cursor: " + err.Error()})
+				return
+			}
+			query = query.Where(
+				"comment_score < ? OR (comment_score = ? AND (created_at < ? OR (created_at = ? AND public_id < ?)))",
+				score, score, createdAt, createdAt, lastID,
+			)
+		}
+	case commentSortOldest:
+		query = query.Order("created_at ASC, public_id ASC")
+		if !pagination.Timestamp.IsZero() {
+			query = query.Where(
+				"created_at > ? OR (created_at = ? AND public_id > ?)",
+				pagination.Timestamp,
+				pagination.Timestamp,
+				pagination.LastID,
+			)
+		}
+	default:
+		query = query.Order("created_at DESC, public_id DESC")
+		if !pagination.Timestamp.IsZero() {
+			query = query.Where(
+				"created_at < ? OR (created_at = ? AND public_id < ?)",
+				pagination.Timestamp,
+				pagination.Timestamp,
+				pagination.LastID,
+			)
+		}
+	}
+
+	var roots []models.UserInteraction
+	if err := query.Limit(pagination.Limit + 1).Find(&roots).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to fetch comments: " + err.Error(),
+		})
+		return
+	}
+
+	hasMore := len(roots) > pagination.Limit
+	if hasMore {
+		roots = roots[:pagination.Limit]
+	}
+
+	var replies []models.UserInteraction
+	rootIDs := make([]uuid.UUID, 0, len(roots))
+	for _, root := range roots {
+		rootIDs = append(rootIDs, root.PublicID)
+	}
+	if len(roots) > 0 {
+		ranked := unblocked(visibleCommentQuery(db)).
+			Select("user_interactions.*, ROW_NUMBER() OVER (PARTITION BY comment_root_id ORDER BY created_at ASC, public_id ASC) AS thread_rank").
+			Where("comment_root_id IN ?", rootIDs)
+		if err := db.Table("(?) AS thread_replies", ranked).
+			Where("thread_rank <= ?", maxCommentThreadReplies).
+			Order("created_at ASC, public_id ASC").
+			Find(&replies).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, utils.HTTPError{Code: http.StatusInternalServerError, Message: "Failed to fetch comment replies"})
+			return
+		}
+	}
+
+	view := commentThreadView{
+		sessionID:   c.Query("session_id"),
+		reactions:   map[uuid.UUID]map[string]int{},
+		mine:        map[uuid.UUID]string{},
+		replyCounts: map[uuid.UUID]int{},
+	}
+	if len(replies) > 0 {
+		var counts []struct {
+			CommentParentID uuid.UUID
+			Count           int
+		}
+		if err := unblocked(visibleCommentQuery(db)).
+			Select("comment_parent_id, count(*) AS count").
+			Where("comment_root_id IN ?", rootIDs).
+			Group("comment_parent_id").
+			Scan(&counts).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, utils.HTTPError{Code: http.StatusInternalServerError, Message: "Failed to fetch comment replies"})
+			return
+		}
+		for _, row := range counts {
+			view.replyCounts[row.CommentParentID] = row.Count
+		}
+	}
+	if authenticated {
+		view.callerUserID = &uid
+	}
+	commentIDs := make([]uuid.UUID, 0, len(roots)+len(replies))
+	for _, list := range [][]models.UserInteraction{roots, replies} {
+		for _, in := range list {
+			if in.CommentDeletedAt == nil && in.CommentScore > 0 {
+				commentIDs = append(commentIDs, in.PublicID)
+			}
+		}
+	}
+	if len(commentIDs) > 0 {
+		var counts []struct {
+			CommentID uuid.UUID
+			Kind      string
+			Count     int
+		}
+		if err := db.Model(&models.CommentReaction{}).
+			Select("comment_id, kind, count(*) AS count").
+			Where("comment_id IN ?", commentIDs).
+			Group("comment_id, kind").
+			Scan(&counts).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, utils.HTTPError{Code: http.StatusInternalServerError, Message: "Failed to fetch comment reactions"})
+			return
+		}
+		for _, row := range counts {
+			if view.reactions[row.CommentID] == nil {
+				view.reactions[row.CommentID] = map[string]int{}
+			}
+			view.reactions[row.CommentID][row.Kind] = row.Count
+		}
+		if authenticated {
+			var mine []models.CommentReaction
+			if err := db.Where("user_id = ? AND comment_id IN ?", uid, commentIDs).Find(&mine).Error; err != nil {
+				c.JSON(http.StatusInternalServerError, utils.HTTPError{Code: http.StatusInternalServerError, Message: "Failed to fetch comment reactions"})
+				return
+			}
+			for _, reaction := range mine {
+				view.mine[reaction.CommentID] = reaction.Kind
+			}
+		}
+	}
+
+	items := buildCommentThreads(roots, replies, view)
+
+	var nextCursor *string
+	if hasMore && len(roots) > 0 {
+		last := roots[len(roots)-1]
+		next := utils.EncodeCursor(last.CreatedAt, last.PublicID)
+		if sort == commentSortTop {
+			next = encodeTopCommentCursor(last.CommentScore, last.CreatedAt, last.PublicID)
+		}
+		nextCursor = &next
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"cursor": nextCursor,
+		"sort":   sort,
+		"items":  items,
+	})
+}
+
+type editCommentRequest struct {
+	Text string `json:"text" binding:"required"`
+}
+
+// EditComment replaces the text of the caller's own comment. The previous
+// text is kept as a revision, and the new text goes through the same policy
+// as a new comment. Editing never releases a comment held for review.
+// PATCH /api/v1/comments/:id
+func EditComment(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+
+	uid, ok := authedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, utils.HTTPError{Code: http.StatusUnauthorized, Message: "Authentication required"})
+		return
+	}
+	commentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.HTTPError{Code: http.StatusBadRequest, Message: "Invalid comment id"})
+		return
+	}
+	var req editCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.Text) == "" {
+		c.JSON(http.StatusBadRequest, utils.HTTPError{Code: http.StatusBadRequest, Message: "Comment requires text"})
+		return
+	}
+	text := strings.TrimSpace(req.Text)
+	if len([]rune(text)) > maxCommentLength {
+		c.JSON(http.StatusBadRequest, utils.HTTPError{Code: http.StatusBadRequest, Message: "Comment text exceeds maximum length"})
+		return
+	}
+
+	// Authors can edit their comments while they are held for review, so this
+	// does not go through visibleCommentQuery.
+	var comment models.UserInteraction
+	if err := db.Where("public_id = ? AND type = ? AND user_id = ? AND comment_deleted_at IS NULL", commentID, models.InteractionTypeComment, uid).
+		First(&comment).Error; err != nil {
+		c.JSON(http.StatusNotFound, utils.HTTPError{Code: http.StatusNotFound, Message: "Comment not found"})
+		return
+	}
+	var contentItem models.ContentItem
+	if err := publicContentQuery(db).Where("public_id = ?", comment.ContentItemID).First(&contentItem).Error; err != nil {
+		c.JSON(http.StatusNotFound, utils.HTTPError{Code: http.StatusNotFound, Message: "Comment not found"})
+		return
+	}
+
+	var meta commentMetadata
+	_ = json.Unmarshal(comment.Metadata, &meta)
+	if meta.Text == text {
+		c.JSON(http.StatusOK, utils.ResponseMessage{Code: http.StatusOK, Message: "Comment unchanged"})
+		return
+	}
+	if !utils.EnforceRateLimit(c, "comments.create", 1) {
+		return
+	}
+	decision := evaluateCommentPolicyForTenant(c.Request.Context(), db, contentItem.TenantID, text)
+	if decision.Outcome == commentPolicyReject {
+		c.JSON(http.StatusUnprocessableEntity, utils.HTTPError{
+			Code:    http.StatusUnprocessableEntity,
+			Message: "Comment violates the community safety policy",
+		})
+		return
+	}
+	wasVisible := commentIsVisible(comment)
+	if comment.CommentModerationStatus != nil && *comment.CommentModerationStatus == string(commentPolicyReview) {
+		decision.Outcome = commentPolicyReview
+		if comment.CommentModerationReason != nil && decision.Reason == "" {
+			decision.Reason = *comment.CommentModerationReason
+		}
+	}
+	var reason *string
+	if decision.Reason != "" {
+		reason = &decision.Reason
+	}
+	var signals datatypes.JSON
+	if decision.Signals != nil {
+		encoded, _ := json.Marshal(decision.Signals)
+		signals = datatypes.JSON(encoded)
+	}
+	previous := meta.Text
+	meta.Text = text
+	canonical, _ := json.Marshal(meta)
+	editedAt := time.Now().UTC()
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&models.CommentRevision{CommentID: comment.PublicID, Text: previous, EditedBy: uid}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&comment).Updates(map[string]any{
+			"metadata":                   datatypes.JSON(canonical),
+			"comment_edited_at":          editedAt,
+			"comment_moderation_status":  string(decision.Outcome),
+			"comment_moderation_reason":  reason,
+			"comment_moderation_signals": signals,
+		}).Error; err != nil {
+			return err
+		}
+		if wasVisible && decision.Outcome != commentPolicyAllow {
+			return updateEngagementCount(tx, comment.ContentItemID, models.InteractionTypeComment, -1)
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.HTTPError{Code: http.StatusInternalServerError, Message: "Failed to edit comment"})
+		return
+	}
+
+	message := "Comment updated"
+	if decision.Outcome == commentPolicyReview {
+		message = "Comment updated and held for review"
+	}
+	c.JSON(http.StatusOK, utils.ResponseMessage{Code: http.StatusOK, Message: message, Data: CommentItem{
+		ID:        comment.PublicID,
+		ParentID:  comment.CommentParentID,
+		Text:      text,
+		Author:    meta.Author,
+		AuthorID:  comment.UserID,
+		IsMine:    true,
+		Edited:    true,
+		EditedAt:  &editedAt,
+		CreatedAt: comment.CreatedAt,
+	}})
+}
+
+// GetCommentHistory lists the earlier texts of the caller's own comment,
+// newest first.
+// GET /api/v1/comments/:id/history
+func GetCommentHistory(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+
+	uid, ok := authedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, utils.HTTPError{Code: http.StatusUnauthorized, Message: "Authentication required"})
+		return
+	}
+	commentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.HTTPError{Code: http.StatusBadRequest, Message: "Invalid comment id"})
+		return
+	}
+	var comment models.UserInteraction
+	if err := db.Where("public_id = ? AND type = ? AND user_id = ? AND comment_deleted_at IS NULL", commentID, models.InteractionTypeComment, uid).
+		First(&comment).Error; err != nil {
+		c.JSON(http.StatusNotFound, utils.HTTPError{Code: http.StatusNotFound, Message: "Comment not found"})
+		return
+	}
+	revisions, err := commentRevisions(db, comment.PublicID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.HTTPError{Code: http.StatusInternalServerError, Message: "Failed to load comment history"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"id": comment.PublicID, "edited_at": comment.CommentEditedAt, "revisions": revisions})
+}
+
+// AdminGetCommentHistory shows moderators what a comment said before edits.
+// GET /admin/moderation/comments/:id/history
+func AdminGetCommentHistory(c *gin.Context) {
+	principal, ok := requireAdminPrincipal(c)
+	if !ok {
+		return
+	}
+	commentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid comment id"})
+		return
+	}
+	db := c.MustGet("db").(*gorm.DB)
+	var comment models.UserInteraction
+	if err := db.Model(&models.UserInteraction{}).
+		Joins("JOIN content_items ON content_items.public_id = user_interactions.content_item_id").
+		Where("content_items.tenant_id = ? AND user_interactions.public_id = ? AND user_interactions.type = ?", principal.TenantID, commentID, models.InteractionTypeComment).
+		First(&comment).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Comment not found"})
+		return
+	}
+	revisions, err := commentRevisions(db, comment.PublicID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to load comment history"})
+		return
+	}
+	var meta commentMetadata
+	_ = json.Unmarshal(comment.Metadata, &meta)
+	c.JSON(http.StatusOK, gin.H{
+		"id":         comment.PublicID,
+		"text":       meta.Text,
+		"author_id":  comment.UserID,
+		"edited_at":  comment.CommentEditedAt,
+		"deleted_at": comment.CommentDeletedAt,
+		"revisions":  revisions,
+	})
+}
+
+func commentRevisions(db *gorm.DB, commentID uuid.UUID) ([]models.CommentRevision, error) {
+	revisions := []models.CommentRevision{}
+	err := db.Where("comment_id = ?", commentID).Order("created_at DESC, id DESC").Find(&revisions).Error
+	return revisions, err
+}
+
+type commentReactionRequest struct {
+	Kind string `json:"kind" binding:"required"`
+}
+
+// ReactToComment sets the caller's reaction to a comment, replacing any
+// earlier one.
+// POST /api/v1/comments/:id/reactions
+func ReactToComment(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+
+	uid, ok := authedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, utils.HTTPError{Code: http.StatusUnauthorized, Message: "Authentication required"})
+		return
+	}
+	commentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.HTTPError{Code: http.StatusBadRequest, Message: "Invalid comment id"})
+		return
+	}
+	var req commentReactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil || !slices.Contains(models.CommentReactionKinds, req.Kind) {
+		c.JSON(http.StatusBadRequest, utils.HTTPError{Code: http.StatusBadRequest, Message: "kind must be one of " + strings.Join(models.CommentReactionKinds, ", ")})
+		return
+	}
+	comment, _, found := loadPublicComment(db, commentID)
+	if !found {
+		c.JSON(http.StatusNotFound, utils.HTTPError{Code: http.StatusNotFound, Message: "Comment not found"})
+		return
+	}
+	if !utils.EnforceRateLimit(c, "comments.react", 1) {
+		return
+	}
+
+	reaction := models.CommentReaction{CommentID: comment.PublicID, UserID: uid, Kind: req.Kind}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&reaction)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 1 {
+			return tx.Model(&models.UserInteraction{}).Where("public_id = ?", comment.PublicID).
+				UpdateColumn("comment_score", gorm.Expr("comment_score + 1")).Error
+		}
+		return tx.Model(&models.CommentReaction{}).
+			Where("comment_id = ? AND user_id = ?", comment.PublicID, uid).
+			Updates(map[string]any{"kind": req.Kind, "updated_at": time.Now().UTC()}).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.HTTPError{Code: http.StatusInternalServerError, Message: "Failed to save reaction"})
+		return
+	}
+	c.JSON(http.StatusOK, utils.ResponseMessage{Code: http.StatusOK, Message: "Reaction saved", Data: gin.H{"comment_id": comment.PublicID, "kind": req.Kind}})
+}
+
+// RemoveCommentReaction clears the caller's reaction to a comment.
+// DELETE /api/v1/comments/:id/reactions
+func RemoveCommentReaction(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+
+	uid, ok := authedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, utils.HTTPError{Code: http.StatusUnauthorized, Message: "Authentication required"})
+		return
+	}
+	commentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.HTTPError{Code: http.StatusBadRequest, Message: "Invalid comment id"})
+		return
+	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("comment_id = ? AND user_id = ?", commentID, uid).Delete(&models.CommentReaction{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		return tx.Model(&models.UserInteraction{}).Where("public_id = ? AND comment_score > 0", commentID).
+			UpdateColumn("comment_score", gorm.Expr("comment_score - 1")).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.HTTPError{Code: http.StatusInternalServerError, Message: "Failed to remove reaction"})
+		return
+	}
+	c.JSON(http.StatusOK, utils.ResponseMessage{Code: http.StatusOK, Message: "Reaction removed"})
+}
@@ -0,0 +1,133 @@
+package controllers
+
+import (
+	"content-management-system/src/models"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+func testComment(text string, parent *models.UserInteraction, at time.Time) models.UserInteraction {
+	comment := models.UserInteraction{
+		PublicID:  uuid.New(),
+		Type:      models.InteractionTypeComment,
+		Metadata:  datatypes.JSON(`{"text":"` + text + `"}`),
+		CreatedAt: at,
+	}
+	if parent != nil {
+		comment.CommentParentID, comment.CommentRootID, comment.CommentDepth = commentPlacement(*parent)
+	}
+	return comment
+}
+
+func TestCommentPlacementFlattensAtMaxDepth(t *testing.T) {
+	root := testComment("root", nil, time.Now())
+	parent := root
+	for depth := 1; depth <= maxCommentDepth; depth++ {
+		reply := testComment("reply", &parent, time.Now())
+		if reply.CommentDepth != depth || *reply.CommentParentID != parent.PublicID || *reply.CommentRootID != root.PublicID {
+			t.Fatalf("depth %d: %+v", depth, reply)
+		}
+		parent = reply
+	}
+	// parent is at maxCommentDepth: a reply to it becomes its sibling.
+	flattened := testComment("deep", &parent, time.Now())
+	if flattened.CommentDepth != maxCommentDepth || *flattened.CommentParentID != *parent.CommentParentID || *flattened.CommentRootID != root.PublicID {
+		t.Fatalf("flattened reply = %+v", flattened)
+	}
+}
+
+func TestBuildCommentThreads(t *testing.T) {
+	now := time.Now()
+	caller := uuid.New()
+	first := testComment("first", nil, now)
+	first.UserID = &caller
+	edited := now.Add(time.Minute)
+	first.CommentEditedAt = &edited
+
+	reply := testComment("reply", &first, now.Add(time.Second))
+	nested := testComment("nested", &reply, now.Add(2*time.Second))
+	orphan := testComment("orphan", &models.UserInteraction{PublicID: uuid.New(), CommentRootID: &first.PublicID, CommentDepth: 1}, now)
+
+	deleted := now
+	tombstone := testComment("", nil, now)
+	tombstone.Metadata = datatypes.JSON(`{}`)
+	tombstone.CommentDeletedAt = &deleted
+	underTombstone := testComment("still here", &tombstone, now)
+	emptyTombstone := tombstone
+	emptyTombstone.PublicID = uuid.New()
+
+	items := buildCommentThreads(
+		[]models.UserInteraction{first, tombstone, emptyTombstone},
+		[]models.UserInteraction{reply, nested, orphan, underTombstone},
+		commentThreadView{
+			callerUserID: &caller,
+			reactions:    map[uuid.UUID]map[string]int{reply.PublicID: {"like": 2}},
+			mine:         map[uuid.UUID]string{reply.PublicID: "like"},
+			replyCounts:  map[uuid.UUID]int{reply.PublicID: 3},
+		},
+	)
+	if len(items) != 2 {
+		t.Fatalf("items = %+v", items)
+	}
+	top := items[0]
+	if top.Text != "first" || !top.IsMine || !top.Edited || top.ReplyCount != 1 {
+		t.Fatalf("top = %+v", top)
+	}
+	got := top.Replies[0]
+	if got.Text != "reply" || got.Reactions["like"] != 2 || got.MyReaction != "like" || len(got.Replies) != 1 || got.Replies[0].Text != "nested" || got.ReplyCount != 3 {
+		t.Fatalf("reply = %+v", got)
+	}
+	if !items[1].Deleted || items[1].Text != "" || items[1].Replies[0].Text != "still here" {
+		t.Fatalf("tombstone = %+v", items[1])
+	}
+}
+
+func TestTopCommentCursorRoundTrip(t *testing.T) {
+	at := time.Unix(1_700_000_000, 123)
+	id := uuid.New()
+	score, createdAt, lastID, err := decodeTopCommentCursor(encodeTopCommentCursor(7, at, id))
+	if err != nil || score != 7 || !createdAt.Equal(at) || lastID != id {
+		t.Fatalf("round trip = %d %v %v %v", score, createdAt, lastID, err)
+	}
+	if _, _, _, err := decodeTopCommentCursor("bm90LWEtY3Vyc29y"); err == nil {
+		t.Fatal("expected malformed cursor to fail")
+	}
+}
+
+func TestRemoveCommentLeavesTombstoneWhenReplied(t *testing.T) {
+	db, mock := newMockGorm(t)
+	comment := testComment("hello", nil, time.Now())
+	comment.ID = 42
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "content_items" SET "comment_count"=comment_count + $1`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "comment_reactions" WHERE comment_id = $1`)).
+		WithArgs(comment.PublicID).WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "comment_revisions" WHERE comment_id = $1`)).
+		WithArgs(comment.PublicID).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "user_interactions" WHERE comment_parent_id = $1`)).
+		WithArgs(comment.PublicID).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "user_interactions" SET "comment_deleted_at"=$1,"comment_score"=$2,"metadata"=$3 WHERE "id" = $4`)).
+		WithArgs(sqlmock.AnyArg(), 0, datatypes.JSON(`{}`), 42).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := removeComment(db, comment); err != nil {
+		t.Fatalf("removeComment: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
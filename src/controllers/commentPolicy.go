@@ -1,10 +1,9 @@
 package controllers
 
 import (
+	"content-management-system/src/toxicity"
 	"strings"
 	"unicode"
-
-	"golang.org/x/text/unicode/norm"
 )
 
 type commentPolicyOutcome string
@@ -18,19 +17,15 @@ const (
 type commentPolicyDecision struct {
 	Outcome commentPolicyOutcome
 	Reason  string
+	// Signals is the classifier evidence; nil when only the deterministic
+	// policy ran.
+	Signals *commentClassification
 }
 
 // normalizeCommentPolicyText makes deterministic rules resilient to harmless
 // Unicode presentation variation without retaining a second copy of the text.
 func normalizeCommentPolicyText(value string) string {
-	value = strings.ToLower(norm.NFKC.String(value))
-	value = strings.Map(func(r rune) rune {
-		if unicode.Is(unicode.Mn, r) || r == '\u0640' { // Arabic tashkil/tatweel
-			return -1
-		}
-		return r
-	}, value)
-	return strings.Join(strings.Fields(value), " ")
+	return toxicity.Normalize(value)
 }
 
 func hasRepeatedToken(value string) bool {
@@ -63,7 +58,10 @@ func hasRepeatedRune(value string) bool {
 // evaluateCommentPolicy is intentionally local, deterministic, and bilingual.
 // It rejects direct abusive threats, routes ambiguous adult/illicit references
 // to human review, and catches mechanical spam. It does not call an LLM or
-// transmit comment text beyond CMS.
+// send comment text anywhere. evaluateCommentPolicyForTenant adds the
+// classifier stage behind it; a tenant that sets scorer_enabled opts in to
+// sending the comment text to the remote toxicity scorer at
+// COMMENT_TOXICITY_SCORER_URL.
 func evaluateCommentPolicy(text string) commentPolicyDecision {
 	normalized := normalizeCommentPolicyText(text)
 	for _, phrase := range []string{
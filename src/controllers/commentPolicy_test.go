@@ -1,6 +1,14 @@
 package controllers
 
-import "testing"
+import (
+	"content-management-system/src/toxicity"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
 
 func TestEvaluateCommentPolicyBilingualSafetyAndSpam(t *testing.T) {
 	cases := []struct {
@@ -27,3 +35,98 @@ func TestEvaluateCommentPolicyBilingualSafetyAndSpam(t *testing.T) {
 		})
 	}
 }
+
+func TestEvaluateCommentPolicyForTenantClassifierStage(t *testing.T) {
+	configCols := []string{"id", "tenant_id", "hold_thresholds", "reject_thresholds", "auto_allow_below", "scorer_enabled", "learning_enabled"}
+	statCols := []string{"tenant_id", "category", "confirmed", "overturned"}
+	cases := []struct {
+		name      string
+		text      string
+		config    *sqlmock.Rows
+		stats     *sqlmock.Rows
+		scorer    toxicity.Classifier
+		outcome   commentPolicyOutcome
+		reason    string
+		autoAllow bool
+	}{
+		{
+			name: "defaults hold stacked insults", text: "You're stupid.",
+			outcome: commentPolicyReview, reason: "toxicity:harassment",
+		},
+		{
+			name: "overturned holds raise the threshold", text: "You're stupid.",
+			stats:   sqlmock.NewRows(statCols).AddRow("tenant-a", "harassment", 0, 30),
+			outcome: commentPolicyAllow,
+		},
+		{
+			name: "tenant reject threshold", text: "You're stupid.",
+			config:  sqlmock.NewRows(configCols).AddRow(1, "tenant-a", `{"harassment":0.3}`, `{"harassment":0.6}`, 0, false, false),
+			outcome: commentPolicyReject, reason: "toxicity:harassment",
+		},
+		{
+			name: "confident classifier releases a sensitive reference", text: "This report discusses porn regulation.",
+			config:  sqlmock.NewRows(configCols).AddRow(1, "tenant-a", `{"sexual":0.9}`, nil, 0.7, true, false),
+			scorer:  &toxicity.StubScorer{Scores: map[string]float64{"sexual": 0.1}},
+			outcome: commentPolicyAllow, autoAllow: true,
+		},
+		{
+			name: "unavailable scorer keeps the review", text: "This report discusses porn regulation.",
+			config:  sqlmock.NewRows(configCols).AddRow(1, "tenant-a", `{"sexual":0.9}`, nil, 0.7, true, false),
+			scorer:  &toxicity.StubScorer{Err: errors.New("timeout")},
+			outcome: commentPolicyReview, reason: "sensitive_reference",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock := newMockGorm(t)
+			config := tc.config
+			if config == nil {
+				config = sqlmock.NewRows(configCols)
+			}
+			mock.ExpectQuery(`SELECT \* FROM "comment_classifier_configs"`).WillReturnRows(config)
+			if tc.config == nil {
+				stats := tc.stats
+				if stats == nil {
+					stats = sqlmock.NewRows(statCols)
+				}
+				mock.ExpectQuery(`SELECT \* FROM "comment_classifier_stats"`).WillReturnRows(stats)
+			}
+			original := commentToxicityScorer
+			commentToxicityScorer = func() toxicity.Classifier { return tc.scorer }
+			defer func() { commentToxicityScorer = original }()
+
+			got := evaluateCommentPolicyForTenant(context.Background(), db, "tenant-a", tc.text)
+			if got.Outcome != tc.outcome || got.Reason != tc.reason {
+				t.Fatalf("decision = %s/%q, want %s/%q", got.Outcome, got.Reason, tc.outcome, tc.reason)
+			}
+			if got.Signals == nil || got.Signals.AutoAllowed != tc.autoAllow {
+				t.Fatalf("signals = %+v", got.Signals)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestRecordCommentClassifierOutcomeCountsHeldCategories(t *testing.T) {
+	db, mock := newMockGorm(t)
+	signals, _ := json.Marshal(commentClassification{Reasons: []toxicity.Reason{
+		{Category: toxicity.CategoryHarassment, Score: 0.7, Threshold: 0.6, Outcome: toxicity.OutcomeReview},
+	}})
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "comment_classifier_stats" .* ON CONFLICT \("tenant_id","category"\) DO UPDATE SET "overturned"=comment_classifier_stats.overturned \+ 1`).
+		WithArgs("tenant-a", "harassment", 0, 1, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	if err := recordCommentClassifierOutcome(db, "tenant-a", signals, false); err != nil {
+		t.Fatal(err)
+	}
+	// Comments held only by the deterministic policy carry no evidence.
+	if err := recordCommentClassifierOutcome(db, "tenant-a", nil, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
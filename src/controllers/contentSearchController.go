@@ -0,0 +1,609 @@
+package controllers
+
+import (
+	"content-management-system/src/models"
+	"content-management-system/src/utils"
+	"context"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// ─── Full-text search over content, transcripts and stories ─
+//
+// Lexical recall comes from the trigger-maintained search_vector columns (see
+// migrations/20260823100000_content_search.sql). In hybrid mode the query is
+// also embedded through Enrichment and blended with the item's dense cosine,
+// guarded by embedding_space_id so only same-space vectors are compared.
+// Query embeddings are cached briefly so repeated searches cost one call.
+
+const (
+	searchMaxQueryLength    = 256
+	searchLexicalCandidates = 200
+	searchDenseCandidates   = 50
+	searchDenseFloor        = 0.45
+	searchMaxWindow         = searchLexicalCandidates + searchDenseCandidates
+	searchSnippetRadius     = 90
+	searchStoryLimit        = 5
+	searchFacetLimit        = 20
+	searchTranscriptWeight  = 0.8
+	searchEmbedCacheTTL     = 10 * time.Minute
+	searchEmbedCacheSize    = 2048
+)
+
+// searchEmbedQuery is the dense query encoder (swappable in tests).
+var searchEmbedQuery = embedQueryViaEnrichmentWithSpace
+
+type searchEmbedding struct {
+	Vector   []float32
+	SpaceID  string
+	CachedAt time.Time
+}
+
+var (
+	searchEmbedMu    sync.Mutex
+	searchEmbedCache = map[string]searchEmbedding{} // normalized query -> vector
+)
+
+// cachedSearchEmbedding embeds the query once per searchEmbedCacheTTL. Only
+// successful answers are cached; a full cache drops its expired entries and,
+// if still full, starts over rather than growing without bound.
+func cachedSearchEmbedding(ctx context.Context, query string) ([]float32, string, error) {
+	key := normalizeSearchText(strings.TrimSpace(query))
+	searchEmbedMu.Lock()
+	cached, ok := searchEmbedCache[key]
+	searchEmbedMu.Unlock()
+	if ok && time.Since(cached.CachedAt) <= searchEmbedCacheTTL {
+		return cached.Vector, cached.SpaceID, nil
+	}
+	vec, spaceID, err := searchEmbedQuery(ctx, query)
+	if err != nil {
+		return nil, "", err
+	}
+	searchEmbedMu.Lock()
+	if len(searchEmbedCache) >= searchEmbedCacheSize {
+		for k, v := range searchEmbedCache {
+			if time.Since(v.CachedAt) > searchEmbedCacheTTL {
+				delete(searchEmbedCache, k)
+			}
+		}
+		if len(searchEmbedCache) >= searchEmbedCacheSize {
+			searchEmbedCache = map[string]searchEmbedding{}
+		}
+	}
+	searchEmbedCache[key] = searchEmbedding{Vector: vec, SpaceID: spaceID, CachedAt: time.Now()}
+	searchEmbedMu.Unlock()
+	return vec, spaceID, nil
+}
+
+type contentSearchOptions struct {
+	TenantID        string
+	Terms           []string
+	Query           string
+	Public          bool
+	Statuses        []string
+	Types           []string
+	SourceIDs       []string
+	StoryCategories []string
+	Language        deliveryLanguage
+	Semantic        bool
+	Limit           int
+	Offset          int
+}
+
+type searchResult struct {
+	ID              string   `json:"id"`
+	Type            string   `json:"type"`
+	Format          *string  `json:"format,omitempty"`
+	Status          string   `json:"status,omitempty"`
+	Title           string   `json:"title"`
+	TitleHighlight  string   `json:"title_highlight"`
+	Snippet         string   `json:"snippet"`
+	MatchedIn       []string `json:"matched_in"`
+	SourceName      *string  `json:"source_name,omitempty"`
+	ContentSourceID *string  `json:"content_source_id,omitempty"`
+	StoryID         *string  `json:"story_id,omitempty"`
+	ContentLanguage *string  `json:"content_language,omitempty"`
+	ThumbnailURL    *string  `json:"thumbnail_url,omitempty"`
+	PublishedAt     *string  `json:"published_at,omitempty"`
+	Score           float64  `json:"score"`
+	LexicalScore    float64  `json:"lexical_score"`
+	SemanticScore   *float64 `json:"semantic_score,omitempty"`
+}
+
+type searchStoryHit struct {
+	ID       string  `json:"id"`
+	Label    string  `json:"label"`
+	Category *string `json:"category,omitempty"`
+	Snippet  string  `json:"snippet"`
+	Rank     float64 `json:"-"`
+}
+
+type searchFacetBucket struct {
+	Value string `json:"value"`
+	Label string `json:"label,omitempty"`
+	Count int64  `json:"count"`
+}
+
+type searchFacets struct {
+	Type          []searchFacetBucket `json:"type"`
+	Source        []searchFacetBucket `json:"source"`
+	StoryCategory []searchFacetBucket `json:"story_category"`
+}
+
+type searchMeta struct {
+	Total          int    `json:"total"`
+	LexicalMatches int64  `json:"lexical_matches"`
+	Limit          int    `json:"limit"`
+	Offset         int    `json:"offset"`
+	Mode           string `json:"mode"`
+	Semantic       bool   `json:"semantic"`
+}
+
+type searchResponse struct {
+	Query   string           `json:"query"`
+	Results []searchResult   `json:"results"`
+	Stories []searchStoryHit `json:"stories"`
+	Facets  searchFacets     `json:"facets"`
+	Meta    searchMeta       `json:"meta"`
+}
+
+// searchSemanticWeight is the dense share of the hybrid score.
+// SEARCH_SEMANTIC_WEIGHT overrides the 0.35 default; 0 disables embedding.
+func searchSemanticWeight() float64 {
+	weight := 0.35
+	if raw := strings.TrimSpace(os.Getenv("SEARCH_SEMANTIC_WEIGHT")); raw != "" {
+		if v, err := strconv.ParseFloat(raw, 64); err == nil {
+			weight = v
+		}
+	}
+	return clampUnit(weight)
+}
+
+func splitSearchParam(raw string) []string {
+	var out []string
+	for _, v := range strings.Split(raw, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// parseContentSearchRequest reads the params shared by the public and admin
+// endpoints: q, type, source_id, story_category, content_language, mode,
+// limit, offset. Returns a client-facing message when the request is invalid.
+func parseContentSearchRequest(c *gin.Context) (contentSearchOptions, string) {
+	opts := contentSearchOptions{Query: strings.TrimSpace(c.Query("q")), Limit: 20}
+	if len([]rune(opts.Query)) > searchMaxQueryLength {
+		return opts, "q must be at most 256 characters"
+	}
+	opts.Terms = searchQueryTerms(opts.Query)
+	if len(opts.Terms) == 0 {
+		return opts, "q is required"
+	}
+	for _, t := range splitSearchParam(c.Query("type")) {
+		opts.Types = append(opts.Types, strings.ToUpper(t))
+	}
+	for _, id := range splitSearchParam(c.Query("source_id")) {
+		parsed, err := uuid.Parse(id)
+		if err != nil {
+			return opts, "source_id must be a UUID"
+		}
+		opts.SourceIDs = append(opts.SourceIDs, parsed.String())
+	}
+	for _, cat := range splitSearchParam(c.Query("story_category")) {
+		opts.StoryCategories = append(opts.StoryCategories, strings.ToLower(cat))
+	}
+	language, ok := parseDeliveryLanguage(c.Query("content_language"))
+	if !ok {
+		return opts, "content_language must be ar, en, or both"
+	}
+	opts.Language = language
+	switch mode := strings.ToLower(strings.TrimSpace(c.Query("mode"))); mode {
+	case "", "hybrid":
+		opts.Semantic = searchSemanticWeight() > 0
+	case "lexical":
+	default:
+		return opts, "mode must be hybrid or lexical"
+	}
+	if raw := strings.TrimSpace(c.Query("limit")); raw != "" {
+		if n, err := strconv.Atoi(raw); err == nil && n > 0 {
+			opts.Limit = n
+		}
+	}
+	if opts.Limit > 50 {
+		opts.Limit = 50
+	}
+	if raw := strings.TrimSpace(c.Query("offset")); raw != "" {
+		if n, err := strconv.Atoi(raw); err == nil && n > 0 {
+			opts.Offset = n
+		}
+	}
+	return opts, ""
+}
+
+// SearchContent handles GET /api/v1/search?q=&type=&source_id=&story_category=&content_language=&mode=&limit=&offset=
+// over the public tenant's READY, feed-visible content.
+func SearchContent(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+	tenantID, err := trustedPublicFeedTenant(c)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, utils.HTTPError{Code: http.StatusServiceUnavailable, Message: "Public feed tenant is unavailable"})
+		return
+	}
+	opts, msg := parseContentSearchRequest(c)
+	if msg != "" {
+		c.JSON(http.StatusBadRequest, utils.HTTPError{Code: http.StatusBadRequest, Message: msg})
+		return
+	}
+	opts.TenantID = tenantID
+	opts.Public = true
+
+	resp, err := runContentSearch(c.Request.Context(), db, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.HTTPError{Code: http.StatusInternalServerError, Message: "Search failed"})
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// AdminSearchContent handles GET /admin/search (same params plus status=) over
+// every item in the admin's tenant regardless of lifecycle state.
+func AdminSearchContent(c *gin.Context) {
+	principal, ok := requireAdminPrincipal(c)
+	if !ok {
+		return
+	}
+	db := c.MustGet("db").(*gorm.DB)
+	opts, msg := parseContentSearchRequest(c)
+	if msg != "" {
+		c.JSON(http.StatusBadRequest, authErrorResponse{Message: msg, Code: "INVALID_SEARCH"})
+		return
+	}
+	opts.TenantID = principal.TenantID
+	for _, s := range splitSearchParam(c.Query("status")) {
+		opts.Statuses = append(opts.Statuses, strings.ToUpper(s))
+	}
+
+	resp, err := runContentSearch(c.Request.Context(), db, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, authErrorResponse{Message: "Search failed", Code: "SEARCH_FAILED"})
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// scopedSearchQuery applies tenant, visibility and facet filters shared by the
+// candidate, dense and facet queries.
+func scopedSearchQuery(db *gorm.DB, opts contentSearchOptions) *gorm.DB {
+	q := db.Table("content_items").Where("content_items.tenant_id = ?", opts.TenantID)
+	if opts.Public {
+		q = q.Where("content_items.status = ?", models.ContentStatusReady).
+			Where("(content_items.type = ? OR content_items.feed_visibility = 'visible')", models.ContentTypeNews).
+			Where(newsRetentionFeedPredicate)
+	} else if len(opts.Statuses) > 0 {
+		q = q.Where("content_items.status IN ?", opts.Statuses)
+	}
+	if len(opts.Types) > 0 {
+		q = q.Where("content_items.type IN ?", opts.Types)
+	}
+	if len(opts.SourceIDs) > 0 {
+		q = q.Where("content_items.content_source_id = ANY(?::uuid[])", pq.StringArray(opts.SourceIDs))
+	}
+	if len(opts.StoryCategories) > 0 {
+		q = q.Where("content_items.story_id IN (SELECT public_id FROM stories WHERE category = ANY(?))", pq.StringArray(opts.StoryCategories))
+	}
+	return applyDeliveryLanguage(q, opts.Language)
+}
+
+// lexicalSearchMatch keeps rows whose own text or linked transcript matches.
+func lexicalSearchMatch(q *gorm.DB, tsq string) *gorm.DB {
+	return q.Where(`(content_items.search_vector @@ to_tsquery('simple', ?) OR EXISTS (
+		SELECT 1 FROM transcripts t
+		WHERE t.public_id = content_items.transcript_id AND t.search_vector @@ to_tsquery('simple', ?)))`, tsq, tsq)
+}
+
+type searchCandidateRow struct {
+	PublicID    uuid.UUID
+	PublishedAt *time.Time
+	Lexical     float64
+	ItemMatch   bool
+}
+
+type searchSimilarityRow struct {
+	PublicID    uuid.UUID
+	PublishedAt *time.Time
+	Similarity  float64
+}
+
+func runContentSearch(ctx context.Context, db *gorm.DB, opts contentSearchOptions) (searchResponse, error) {
+	tsq := searchTSQuery(opts.Terms)
+	resp := searchResponse{
+		Query:   opts.Query,
+		Results: []searchResult{},
+		Stories: []searchStoryHit{},
+		Meta:    searchMeta{Limit: opts.Limit, Offset: opts.Offset, Mode: "lexical"},
+	}
+
+	var lexicalRows []searchCandidateRow
+	if err := lexicalSearchMatch(scopedSearchQuery(db, opts), tsq).
+		Select(`content_items.public_id, content_items.published_at,
+			GREATEST(ts_rank_cd(content_items.search_vector, to_tsquery('simple', ?), 32),
+				COALESCE((SELECT ts_rank_cd(t.search_vector, to_tsquery('simple', ?), 32) * ?
+					FROM transcripts t WHERE t.public_id = content_items.transcript_id), 0)) AS lexical,
+			content_items.search_vector @@ to_tsquery('simple', ?) AS item_match`,
+			tsq, tsq, searchTranscriptWeight, tsq).
+		Order("lexical DESC, content_items.published_at DESC NULLS LAST").
+		Limit(searchLexicalCandidates).
+		Scan(&lexicalRows).Error; err != nil {
+		return resp, err
+	}
+
+	cands := make([]searchCandidate, 0, len(lexicalRows))
+	itemMatch := map[uuid.UUID]bool{}
+	index := map[uuid.UUID]int{}
+	for _, row := range lexicalRows {
+		index[row.PublicID] = len(cands)
+		itemMatch[row.PublicID] = row.ItemMatch
+		cands = append(cands, searchCandidate{ID: row.PublicID, Lexical: row.Lexical, PublishedAt: row.PublishedAt})
+	}
+
+	weight := 0.0
+	if opts.Semantic {
+		if vec, spaceID, err := cachedSearchEmbedding(ctx, opts.Query); err == nil && len(vec) == textEmbeddingDim && strings.TrimSpace(spaceID) != "" {
+			denseRows, err := searchDenseNeighbours(db, opts, utils.PgvectorToLiteral(vec), spaceID, lexicalRows)
+			if err != nil {
+				return resp, err
+			}
+			for _, row := range denseRows {
+				similarity := row.Similarity
+				if i, ok := index[row.PublicID]; ok {
+					cands[i].Semantic = &similarity
+					continue
+				}
+				if similarity < searchDenseFloor {
+					continue
+				}
+				index[row.PublicID] = len(cands)
+				cands = append(cands, searchCandidate{ID: row.PublicID, Semantic: &similarity, PublishedAt: row.PublishedAt})
+			}
+			weight = searchSemanticWeight()
+			resp.Meta.Mode, resp.Meta.Semantic = "hybrid", true
+		}
+	}
+	rankSearchCandidates(cands, weight)
+	resp.Meta.Total = len(cands)
+
+	if opts.Offset < len(cands) {
+		end := opts.Offset + opts.Limit
+		if end > len(cands) {
+			end = len(cands)
+		}
+		results, err := hydrateSearchResults(db, opts, cands[opts.Offset:end], itemMatch)
+		if err != nil {
+			return resp, err
+		}
+		resp.Results = results
+	}
+
+	facets, lexicalMatches, err := searchFacetCounts(db, opts, tsq)
+	if err != nil {
+		return resp, err
+	}
+	resp.Facets, resp.Meta.LexicalMatches = facets, lexicalMatches
+
+	if opts.Offset == 0 {
+		stories, err := searchStories(db, opts, tsq)
+		if err != nil {
+			return resp, err
+		}
+		resp.Stories = stories
+	}
+	return resp, nil
+}
+
+// searchDenseNeighbours returns the cosine of every lexical candidate plus the
+// nearest dense neighbours in the same embedding space (search_dense consumer;
+// the embedding_space_id guard keeps cross-space vectors out).
+func searchDenseNeighbours(db *gorm.DB, opts contentSearchOptions, vecLiteral, spaceID string, lexical []searchCandidateRow) ([]searchSimilarityRow, error) {
+	var rows []searchSimilarityRow
+	if err := scopedSearchQuery(db, opts).
+		Where("content_items.embedding IS NOT NULL AND content_items.embedding_space_id = ?", spaceID).
+		Select("content_items.public_id, content_items.published_at, 1 - (content_items.embedding <=> '" + vecLiteral + "') AS similarity").
+		Order("content_items.embedding <=> '" + vecLiteral + "'").
+		Limit(searchDenseCandidates).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	if len(lexical) == 0 {
+		return rows, nil
+	}
+	ids := make([]uuid.UUID, 0, len(lexical))
+	for _, row := range lexical {
+		ids = append(ids, row.PublicID)
+	}
+	var scored []searchSimilarityRow
+	if err := db.Table("content_items").
+		Where("content_items.public_id IN ? AND content_items.embedding IS NOT NULL AND content_items.embedding_space_id = ?", ids, spaceID).
+		Select("content_items.public_id, content_items.published_at, 1 - (content_items.embedding <=> '" + vecLiteral + "') AS similarity").
+		Scan(&scored).Error; err != nil {
+		return nil, err
+	}
+	return append(scored, rows...), nil
+}
+
+func hydrateSearchResults(db *gorm.DB, opts contentSearchOptions, page []searchCandidate, itemMatch map[uuid.UUID]bool) ([]searchResult, error) {
+	ids := make([]uuid.UUID, 0, len(page))
+	for _, cand := range page {
+		ids = append(ids, cand.ID)
+	}
+	var items []models.ContentItem
+	if err := db.Omit("embedding", "image_embedding").Where("public_id IN ?", ids).Find(&items).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]models.ContentItem, len(items))
+	var transcriptIDs []uuid.UUID
+	for _, it := range items {
+		byID[it.PublicID] = it
+		if !itemMatch[it.PublicID] && it.TranscriptID != nil {
+			transcriptIDs = append(transcriptIDs, *it.TranscriptID)
+		}
+	}
+	transcriptText := map[uuid.UUID]string{}
+	if len(transcriptIDs) > 0 {
+		var rows []models.Transcript
+		if err := db.Select("public_id, full_text").Where("public_id IN ?", transcriptIDs).Find(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			transcriptText[row.PublicID] = row.FullText
+		}
+	}
+
+	out := make([]searchResult, 0, len(page))
+	for _, cand := range page {
+		it, ok := byID[cand.ID]
+		if !ok {
+			continue
+		}
+		r := searchResult{
+			ID:              it.PublicID.String(),
+			Type:            string(it.Type),
+			Format:          it.Format,
+			Title:           derefStr(it.Title),
+			SourceName:      it.SourceName,
+			ContentLanguage: it.ContentLanguage,
+			ThumbnailURL:    it.ThumbnailURL,
+			Score:           cand.Score,
+			LexicalScore:    cand.Lexical,
+			SemanticScore:   cand.Semantic,
+			MatchedIn:       []string{},
+		}
+		if !opts.Public {
+			r.Status = string(it.Status)
+		}
+		if it.ContentSourceID != nil {
+			id := it.ContentSourceID.String()
+			r.ContentSourceID = &id
+		}
+		if it.StoryID != nil {
+			id := it.StoryID.String()
+			r.StoryID = &id
+		}
+		if it.PublishedAt != nil {
+			ts := it.PublishedAt.UTC().Format(time.RFC3339)
+			r.PublishedAt = &ts
+		}
+		r.TitleHighlight = searchSnippet(r.Title, opts.Terms, searchMaxQueryLength)
+
+		body := derefStr(it.Excerpt)
+		if it.BodyText != nil && strings.TrimSpace(*it.BodyText) != "" {
+			body = *it.BodyText
+		}
+		switch {
+		case itemMatch[it.PublicID]:
+			r.MatchedIn = append(r.MatchedIn, "content")
+			r.Snippet = searchSnippet(body, opts.Terms, searchSnippetRadius)
+		case cand.Lexical > 0 && it.TranscriptID != nil:
+			r.MatchedIn = append(r.MatchedIn, "transcript")
+			r.Snippet = searchSnippet(transcriptText[*it.TranscriptID], opts.Terms, searchSnippetRadius)
+		default:
+			r.Snippet = searchSnippet(body, opts.Terms, searchSnippetRadius)
+		}
+		if cand.Semantic != nil && *cand.Semantic >= searchDenseFloor {
+			r.MatchedIn = append(r.MatchedIn, "semantic")
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+// searchFacetCounts counts the full lexical match set (not just the ranked
+// window) by type, source and story category.
+func searchFacetCounts(db *gorm.DB, opts contentSearchOptions, tsq string) (searchFacets, int64, error) {
+	facets := searchFacets{Type: []searchFacetBucket{}, Source: []searchFacetBucket{}, StoryCategory: []searchFacetBucket{}}
+
+	if err := lexicalSearchMatch(scopedSearchQuery(db, opts), tsq).
+		Select("content_items.type AS value, COUNT(*) AS count").
+		Group("content_items.type").Order("count DESC, value").
+		Scan(&facets.Type).Error; err != nil {
+		return facets, 0, err
+	}
+	var total int64
+	for _, bucket := range facets.Type {
+		total += bucket.Count
+	}
+	if total == 0 {
+		return facets, 0, nil
+	}
+
+	if err := lexicalSearchMatch(scopedSearchQuery(db, opts), tsq).
+		Select(`COALESCE(content_items.content_source_id::text, content_items.source_name, '') AS value,
+			COALESCE(MAX(content_items.source_name), '') AS label, COUNT(*) AS count`).
+		Group("COALESCE(content_items.content_source_id::text, content_items.source_name, '')").
+		Order("count DESC, value").Limit(searchFacetLimit).
+		Scan(&facets.Source).Error; err != nil {
+		return facets, 0, err
+	}
+	if err := lexicalSearchMatch(scopedSearchQuery(db, opts), tsq).
+		Joins("JOIN stories ON stories.public_id = content_items.story_id").
+		Where("stories.category IS NOT NULL AND stories.category <> ''").
+		Select("stories.category AS value, COUNT(*) AS count").
+		Group("stories.category").Order("count DESC, value").Limit(searchFacetLimit).
+		Scan(&facets.StoryCategory).Error; err != nil {
+		return facets, 0, err
+	}
+	return facets, total, nil
+}
+
+func searchStories(db *gorm.DB, opts contentSearchOptions, tsq string) ([]searchStoryHit, error) {
+	type storyRow struct {
+		PublicID uuid.UUID
+		Label    string
+		Category *string
+		Summary  *string
+		Rank     float64
+	}
+	q := db.Table("stories").
+		Where("stories.tenant_id = ? AND stories.search_vector @@ to_tsquery('simple', ?)", opts.TenantID, tsq)
+	if len(opts.StoryCategories) > 0 {
+		q = q.Where("stories.category = ANY(?)", pq.StringArray(opts.StoryCategories))
+	}
+	if opts.Public {
+		// A story is public only while it still serves a READY, retained item.
+		q = q.Where(`EXISTS (SELECT 1 FROM content_items
+			WHERE content_items.story_id = stories.public_id AND content_items.tenant_id = stories.tenant_id
+			  AND content_items.status = ? AND `+newsRetentionFeedPredicate+`)`, models.ContentStatusReady)
+	}
+	var rows []storyRow
+	if err := q.Select("stories.public_id, stories.label, stories.category, stories.summary, ts_rank_cd(stories.search_vector, to_tsquery('simple', ?), 32) AS rank", tsq).
+		Order("rank DESC, stories.last_member_at DESC NULLS LAST").
+		Limit(searchStoryLimit).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make([]searchStoryHit, 0, len(rows))
+	for _, row := range rows {
+		text := row.Label
+		if row.Summary != nil && strings.TrimSpace(*row.Summary) != "" {
+			text = *row.Summary
+		}
+		out = append(out, searchStoryHit{
+			ID:       row.PublicID.String(),
+			Label:    row.Label,
+			Category: row.Category,
+			Snippet:  searchSnippet(text, opts.Terms, searchSnippetRadius),
+			Rank:     row.Rank,
+		})
+	}
+	return out, nil
+}
@@ -0,0 +1,39 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCachedSearchEmbeddingReusesSuccessfulAnswers(t *testing.T) {
+	saved := searchEmbedQuery
+	t.Cleanup(func() {
+		searchEmbedQuery = saved
+		searchEmbedCache = map[string]searchEmbedding{}
+	})
+	searchEmbedCache = map[string]searchEmbedding{}
+
+	calls := 0
+	fail := true
+	searchEmbedQuery = func(context.Context, string) ([]float32, string, error) {
+		calls++
+		if fail {
+			return nil, "", errors.New("enrichment unavailable")
+		}
+		return []float32{1, 2}, "space-1", nil
+	}
+	if _, _, err := cachedSearchEmbedding(context.Background(), "الاقتصاد"); err == nil {
+		t.Fatal("encoder failure was swallowed")
+	}
+	fail = false
+	for _, q := range []string{"الاقتصاد", " اقتصاد "} {
+		vec, space, err := cachedSearchEmbedding(context.Background(), q)
+		if err != nil || len(vec) != 2 || space != "space-1" {
+			t.Fatalf("cachedSearchEmbedding(%q) = %v, %q, %v", q, vec, space, err)
+		}
+	}
+	if calls != 2 {
+		t.Fatalf("encoder calls = %d, want failure not cached and normalized repeats served from cache", calls)
+	}
+}
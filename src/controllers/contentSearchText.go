@@ -0,0 +1,231 @@
+package controllers
+
+import (
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// ─── Search text normalization (mirrors cms_search_normalize) ─
+
+// searchArticlePrefix drops a leading definite article (ال / وال) from words
+// of 3+ letters, matching the SQL function's final regexp_replace.
+var searchArticlePrefix = regexp.MustCompile(`(^|\s)و?ال(\S{2,})`)
+
+const maxSearchTerms = 12
+
+// foldSearchRune applies the per-character Arabic folding shared by the SQL
+// normalizer and snippet highlighting. keep=false drops the rune (diacritics
+// and tatweel).
+func foldSearchRune(r rune) (rune, bool) {
+	switch {
+	case r >= 0x064B && r <= 0x065F, r == 0x0670, r == 0x0640:
+		return 0, false
+	case r == 'أ', r == 'إ', r == 'آ', r == 'ٱ':
+		return 'ا', true
+	case r == 'ة':
+		return 'ه', true
+	case r == 'ى', r == 'ئ':
+		return 'ي', true
+	case r == 'ؤ':
+		return 'و', true
+	}
+	return unicode.ToLower(r), true
+}
+
+// normalizeSearchText is the Go twin of the cms_search_normalize SQL function
+// behind the trigger-maintained search_vector columns. Both must change together.
+func normalizeSearchText(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if folded, keep := foldSearchRune(r); keep {
+			b.WriteRune(folded)
+		}
+	}
+	return searchArticlePrefix.ReplaceAllString(b.String(), "$1$2")
+}
+
+// searchQueryTerms splits a user query into normalized lexemes. Only letters
+// and digits survive, so the terms can be joined into a to_tsquery expression
+// without any user-controlled operators.
+func searchQueryTerms(query string) []string {
+	fields := strings.FieldsFunc(normalizeSearchText(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	seen := map[string]bool{}
+	terms := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if seen[f] {
+			continue
+		}
+		seen[f] = true
+		terms = append(terms, f)
+		if len(terms) == maxSearchTerms {
+			break
+		}
+	}
+	return terms
+}
+
+// searchTSQuery ANDs the terms and prefix-matches the last one so partially
+// typed queries still hit ("اقتص" → اقتصاد).
+func searchTSQuery(terms []string) string {
+	if len(terms) == 0 {
+		return ""
+	}
+	parts := make([]string, len(terms))
+	copy(parts, terms)
+	parts[len(parts)-1] += ":*"
+	return strings.Join(parts, " & ")
+}
+
+// ─── Snippets ────────────────────────────────────────────────
+
+// searchSnippet returns an HTML-escaped excerpt of text centred on the first
+// term match, with every term occurrence inside the window wrapped in <mark>.
+// Matching runs on folded characters so "مدرسة" highlights "المدرسه" while the
+// original spelling (diacritics included) is what the reader sees.
+func searchSnippet(text string, terms []string, radius int) string {
+	original := []rune(strings.Join(strings.Fields(text), " "))
+	if len(original) == 0 {
+		return ""
+	}
+	folded := make([]rune, 0, len(original))
+	origin := make([]int, 0, len(original))
+	for i, r := range original {
+		if f, keep := foldSearchRune(r); keep {
+			folded = append(folded, f)
+			origin = append(origin, i)
+		}
+	}
+
+	type span struct{ start, end int } // original rune offsets, end exclusive
+	var spans []span
+	for _, term := range terms {
+		needle := []rune(term)
+		if len(needle) == 0 {
+			continue
+		}
+		for i := 0; i+len(needle) <= len(folded); i++ {
+			if runesEqual(folded[i:i+len(needle)], needle) {
+				// Extend over trailing diacritics so marks never split a letter.
+				end := len(original)
+				if i+len(needle) < len(origin) {
+					end = origin[i+len(needle)]
+				}
+				spans = append(spans, span{origin[i], end})
+				i += len(needle) - 1
+			}
+		}
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+
+	from, to := 0, len(original)
+	if len(spans) > 0 {
+		from = spans[0].start - radius
+		to = spans[0].end + radius
+	} else {
+		to = 2 * radius
+	}
+	if from < 0 {
+		from = 0
+	}
+	if to > len(original) {
+		to = len(original)
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("…")
+	}
+	cursor := from
+	for _, s := range spans {
+		if s.start < cursor || s.end > to {
+			continue
+		}
+		b.WriteString(html.EscapeString(string(original[cursor:s.start])))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(string(original[s.start:s.end])))
+		b.WriteString("</mark>")
+		cursor = s.end
+	}
+	b.WriteString(html.EscapeString(string(original[cursor:to])))
+	if to < len(original) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+func runesEqual(a, b []rune) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ─── Hybrid ranking ──────────────────────────────────────────
+
+type searchCandidate struct {
+	ID          uuid.UUID
+	Lexical     float64  // ts_rank_cd, best of item and transcript match
+	Semantic    *float64 // cosine similarity; nil = not embedded in the query space
+	PublishedAt *time.Time
+	Score       float64
+}
+
+// rankSearchCandidates blends lexical and dense relevance in place and sorts
+// best-first. Lexical rank is normalized by the best lexical hit so the two
+// signals share a [0,1] scale; a candidate without a comparable embedding
+// keeps its lexical score for the semantic share instead of being penalized.
+func rankSearchCandidates(cands []searchCandidate, semanticWeight float64) {
+	maxLexical := 0.0
+	for _, cand := range cands {
+		if cand.Lexical > maxLexical {
+			maxLexical = cand.Lexical
+		}
+	}
+	for i := range cands {
+		lexical := 0.0
+		if maxLexical > 0 {
+			lexical = cands[i].Lexical / maxLexical
+		}
+		semantic := lexical
+		if cands[i].Semantic != nil {
+			semantic = clampUnit(*cands[i].Semantic)
+		}
+		cands[i].Score = (1-semanticWeight)*lexical + semanticWeight*semantic
+	}
+	sort.SliceStable(cands, func(i, j int) bool {
+		if cands[i].Score != cands[j].Score {
+			return cands[i].Score > cands[j].Score
+		}
+		pi, pj := cands[i].PublishedAt, cands[j].PublishedAt
+		if pi != nil && pj != nil && !pi.Equal(*pj) {
+			return pi.After(*pj)
+		}
+		if (pi == nil) != (pj == nil) {
+			return pi != nil
+		}
+		return cands[i].ID.String() < cands[j].ID.String()
+	})
+}
+
+func clampUnit(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
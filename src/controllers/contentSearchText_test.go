@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestNormalizeSearchTextFoldsArabicVariants(t *testing.T) {
+	cases := map[string]string{
+		"أحمد":             "احمد",
+		"إسلام":            "اسلام",
+		"مدرسة":            "مدرسه",
+		"مستشفى":           "مستشفي",
+		"مُحَمَّد":         "محمد",
+		"جمـــيل":          "جميل",
+		"المدرسة":          "مدرسه",
+		"والاقتصاد":        "اقتصاد",
+		"ال":               "ال",
+		"Breaking News":    "breaking news",
+		"سؤال مسائل":       "سوال مسايل",
+		"قال الرئيس اليوم": "قال رييس يوم",
+	}
+	for in, want := range cases {
+		if got := normalizeSearchText(in); got != want {
+			t.Errorf("normalizeSearchText(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestSearchQueryTermsStripsOperators(t *testing.T) {
+	got := searchQueryTerms(`الاقتصاد & !oil | "oil" (2024)`)
+	want := []string{"اقتصاد", "oil", "2024"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("terms = %v, want %v", got, want)
+	}
+	if q := searchTSQuery(got); q != "اقتصاد & oil & 2024:*" {
+		t.Fatalf("tsquery = %q", q)
+	}
+	if searchTSQuery(nil) != "" || len(searchQueryTerms("  !! ")) != 0 {
+		t.Fatal("empty query must yield no terms")
+	}
+}
+
+func TestSearchSnippetHighlightsFoldedMatches(t *testing.T) {
+	got := searchSnippet("زار الوزير المدرسةَ الجديدة", searchQueryTerms("مدرسه"), 40)
+	if !strings.Contains(got, "<mark>مدرسةَ</mark>") {
+		t.Fatalf("snippet = %q, want the original spelling highlighted", got)
+	}
+
+	got = searchSnippet(`<b>Oil</b> & gas`, []string{"oil"}, 40)
+	if got != "&lt;b&gt;<mark>Oil</mark>&lt;/b&gt; &amp; gas" {
+		t.Fatalf("snippet = %q, want escaped HTML around the mark", got)
+	}
+
+	long := strings.Repeat("a ", 100) + "target" + strings.Repeat(" b", 100)
+	got = searchSnippet(long, []string{"target"}, 10)
+	if !strings.HasPrefix(got, "…") || !strings.HasSuffix(got, "…") || !strings.Contains(got, "<mark>target</mark>") {
+		t.Fatalf("snippet = %q, want a window around the match", got)
+	}
+}
+
+func TestRankSearchCandidatesBlendsSignals(t *testing.T) {
+	high, low := 0.9, 0.1
+	older := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+	a, b, c, d := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	cands := []searchCandidate{
+		{ID: a, Lexical: 0.4, Semantic: &low, PublishedAt: &older},
+		{ID: b, Lexical: 0.2, Semantic: &high, PublishedAt: &older},
+		{ID: c, Lexical: 0.0, Semantic: &high, PublishedAt: &newer},
+		{ID: d, Lexical: 0.2, PublishedAt: &newer},
+	}
+
+	rankSearchCandidates(cands, 0)
+	if cands[0].ID != a || cands[0].Score != 1 {
+		t.Fatalf("lexical-only ranking: top = %v score %v", cands[0].ID, cands[0].Score)
+	}
+	// Equal lexical scores tie-break on recency.
+	if cands[1].ID != d || cands[2].ID != b {
+		t.Fatalf("tie-break order = %v, %v", cands[1].ID, cands[2].ID)
+	}
+
+	rankSearchCandidates(cands, 0.5)
+	if cands[0].ID != b {
+		t.Fatalf("hybrid top = %v, want the strong semantic + lexical match", cands[0].ID)
+	}
+	for _, cand := range cands {
+		if cand.ID == d && cand.Score != 0.5 {
+			t.Fatalf("unembedded candidate score = %v, want its lexical score", cand.Score)
+		}
+	}
+}
@@ -14,7 +14,9 @@ import (
 	"strings"
 	"time"
 
+	"content-management-system/src/feedstate"
 	"content-management-system/src/models"
+	"content-management-system/src/tracing"
 	"content-management-system/src/utils"
 
 	"github.com/gin-gonic/gin"
@@ -228,7 +230,10 @@ func SubmitUserContent(c *gin.Context) {
 	// does not retain uploads after the request, so a server-side outbox could
 	// not safely replay the binary payload.
 	if err := db.Transaction(func(tx *gorm.DB) error {
-		return tx.Create(&item).Error
+		if err := tx.Create(&item).Error; err != nil {
+			return err
+		}
+		return feedstate.CommitReady(tx, item)
 	}); err != nil {
 		if idempotencyKey != "" {
 			var existing models.ContentItem
@@ -384,7 +389,7 @@ func dispatchAudioToAggregation(c *gin.Context, contentItemID uuid.UUID, tenantI
 		ExpectContinueTimeout: time.Second,
 	}
 	defer transport.CloseIdleConnections()
-	client := &http.Client{Transport: transport, Timeout: aggregationHandoffTimeout}
+	client := &http.Client{Transport: tracing.Transport(transport), Timeout: aggregationHandoffTimeout}
 	resp, err := client.Do(req)
 	_ = reader.Close()
 	for streamErr := range writeErr {
@@ -0,0 +1,105 @@
+package controllers
+
+import (
+	"content-management-system/src/models"
+	"content-management-system/src/utils"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Tenant CORS origins admit a browser origin for one route group (public,
+// consumer or admin) on top of CORS_*_ORIGINS. They only matter for groups
+// configured with an explicit allowlist; a "*" group already admits every
+// origin. CORS runs before authentication, so every browser request resolves
+// to DEFAULT_TENANT_ID; only that tenant may register origins, since another
+// tenant's would never be admitted. Changes apply on this replica at once and
+// on others within a minute.
+
+func ListTenantCORSOrigins(c *gin.Context) {
+	principal, ok := requireAdminPrincipal(c)
+	if !ok {
+		return
+	}
+	db := c.MustGet("db").(*gorm.DB)
+	var origins []models.TenantCORSOrigin
+	if err := db.Where("tenant_id = ?", principal.TenantID).Order("route_group, origin").Find(&origins).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not list CORS origins"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"items": origins}})
+}
+
+func CreateTenantCORSOrigin(c *gin.Context) {
+	principal, ok := requireAdminPrincipal(c)
+	if !ok {
+		return
+	}
+	if deploymentTenant := utils.GetDefaultTenantID(); principal.TenantID != deploymentTenant {
+		c.JSON(http.StatusForbidden, gin.H{"error": "CORS origins can only be registered by this deployment's tenant (" + deploymentTenant + "); browser requests never resolve to tenant " + principal.TenantID})
+		return
+	}
+	var request struct {
+		RouteGroup string `json:"route_group"`
+		Origin     string `json:"origin"`
+	}
+	if c.ShouldBindJSON(&request) != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "route_group and origin are required"})
+		return
+	}
+	group := utils.CORSRouteGroup(strings.TrimSpace(request.RouteGroup))
+	valid := false
+	for _, known := range utils.CORSRouteGroups {
+		valid = valid || group == known
+	}
+	if !valid {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "route_group must be public, consumer or admin"})
+		return
+	}
+	origin, err := utils.NormalizeCORSOrigin(request.Origin)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := c.MustGet("db").(*gorm.DB)
+	row := models.TenantCORSOrigin{TenantID: principal.TenantID, RouteGroup: string(group), Origin: origin, CreatedBy: principal.Email}
+	result := db.Where(models.TenantCORSOrigin{TenantID: row.TenantID, RouteGroup: row.RouteGroup, Origin: row.Origin}).FirstOrCreate(&row)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not save CORS origin"})
+		return
+	}
+	utils.InvalidateTenantCORSOrigins()
+	status := http.StatusOK
+	if result.RowsAffected > 0 {
+		status = http.StatusCreated
+	}
+	c.JSON(status, gin.H{"data": row})
+}
+
+func DeleteTenantCORSOrigin(c *gin.Context) {
+	principal, ok := requireAdminPrincipal(c)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid CORS origin id"})
+		return
+	}
+	db := c.MustGet("db").(*gorm.DB)
+	result := db.Where("tenant_id = ? AND id = ?", principal.TenantID, id).Delete(&models.TenantCORSOrigin{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not delete CORS origin"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "CORS origin not found"})
+		return
+	}
+	utils.InvalidateTenantCORSOrigins()
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,34 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"content-management-system/src/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCreateTenantCORSOriginRejectsOtherTenants(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("DEFAULT_TENANT_ID", "tenant-a")
+	db, mock := newMockGorm(t)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Set("db", db)
+	c.Set(utils.AdminPrincipalContextKey, utils.AdminPrincipal{UserID: "admin-1", TenantID: "tenant-b"})
+	c.Request = httptest.NewRequest(http.MethodPost, "/admin/cors/origins",
+		strings.NewReader(`{"route_group":"admin","origin":"https://console.example.com"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	CreateTenantCORSOrigin(c)
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unexpected queries: %v", err)
+	}
+}
@@ -0,0 +1,365 @@
+package controllers
+
+import (
+	"content-management-system/src/models"
+	"content-management-system/src/utils"
+	"errors"
+	"net/http"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type digestSubscriptionRequest struct {
+	Enabled   *bool   `json:"enabled"`
+	Cadence   *string `json:"cadence"`
+	Language  *string `json:"language"`
+	Email     *string `json:"email"`
+	Timezone  *string `json:"timezone"`
+	SendHour  *int    `json:"send_hour"`
+	WeeklyDay *int    `json:"weekly_day"`
+}
+
+type digestSubscriptionResponse struct {
+	models.DigestSubscription
+	UnsubscribeURL string `json:"unsubscribe_url,omitempty"`
+}
+
+func defaultDigestSubscription(uid uuid.UUID) models.DigestSubscription {
+	return models.DigestSubscription{
+		TenantID: consumerTenant, UserID: uid, Cadence: models.DigestCadenceDaily,
+		Language: "ar", Timezone: "UTC", SendHour: 7,
+	}
+}
+
+// applyDigestSubscriptionRequest validates the request onto sub.
+func applyDigestSubscriptionRequest(sub *models.DigestSubscription, req digestSubscriptionRequest) error {
+	if req.Enabled != nil {
+		sub.Enabled = *req.Enabled
+	}
+	if req.Cadence != nil {
+		cadence := strings.ToLower(strings.TrimSpace(*req.Cadence))
+		if cadence != models.DigestCadenceDaily && cadence != models.DigestCadenceWeekly {
+			return errors.New("cadence must be daily or weekly")
+		}
+		sub.Cadence = cadence
+	}
+	if req.Language != nil {
+		language := strings.ToLower(strings.TrimSpace(*req.Language))
+		if _, ok := digestLocales[language]; !ok {
+			return errors.New("language must be ar or en")
+		}
+		sub.Language = language
+	}
+	if req.Email != nil {
+		email := strings.TrimSpace(*req.Email)
+		if email != "" {
+			addr, err := mail.ParseAddress(email)
+			if err != nil || addr.Address != email {
+				return errors.New("email is not a valid address")
+			}
+		}
+		sub.Email = email
+	}
+	if req.Timezone != nil {
+		timezone := strings.TrimSpace(*req.Timezone)
+		if _, err := time.LoadLocation(timezone); err != nil || timezone == "" || timezone == "Local" {
+			return errors.New("timezone must be an IANA zone name")
+		}
+		sub.Timezone = timezone
+	}
+	if req.SendHour != nil {
+		if *req.SendHour < 0 || *req.SendHour > 23 {
+			return errors.New("send_hour must be between 0 and 23")
+		}
+		sub.SendHour = *req.SendHour
+	}
+	if req.WeeklyDay != nil {
+		if *req.WeeklyDay < 0 || *req.WeeklyDay > 6 {
+			return errors.New("weekly_day must be between 0 (Sunday) and 6")
+		}
+		sub.WeeklyDay = *req.WeeklyDay
+	}
+	return nil
+}
+
+func newDigestSubscriptionResponse(c *gin.Context, sub models.DigestSubscription) digestSubscriptionResponse {
+	response := digestSubscriptionResponse{DigestSubscription: sub}
+	if sub.UnsubscribeToken != "" {
+		response.UnsubscribeURL = digestUnsubscribeURL(publicBaseURL(c), sub.UnsubscribeToken)
+	}
+	return response
+}
+
+// GetDigestSubscription returns the caller's digest settings; readers who
+// never subscribed get the disabled defaults.
+// GET /api/v1/me/digest
+func GetDigestSubscription(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+	uid, ok := authedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, utils.HTTPError{Code: http.StatusUnauthorized, Message: "Authentication required"})
+		return
+	}
+	sub := defaultDigestSubscription(uid)
+	if err := db.Where("tenant_id = ? AND user_id = ?", consumerTenant, uid).First(&sub).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, utils.HTTPError{Code: http.StatusInternalServerError, Message: "Failed to load digest settings"})
+		return
+	}
+	c.JSON(http.StatusOK, newDigestSubscriptionResponse(c, sub))
+}
+
+// UpdateDigestSubscription creates or changes the caller's digest settings
+// and reschedules the next send.
+// PUT /api/v1/me/digest
+func UpdateDigestSubscription(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+	uid, ok := authedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, utils.HTTPError{Code: http.StatusUnauthorized, Message: "Authentication required"})
+		return
+	}
+	var req digestSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.HTTPError{Code: http.StatusBadRequest, Message: "Invalid request body"})
+		return
+	}
+
+	var sub models.DigestSubscription
+	errInvalid := errors.New("invalid")
+	var invalid error
+	errThrottled := errors.New("throttled")
+	err := db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("tenant_id = ? AND user_id = ?", consumerTenant, uid).First(&sub).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			sub = defaultDigestSubscription(uid)
+			if sub.UnsubscribeToken, err = utils.NewOpaqueToken(); err != nil {
+				return err
+			}
+		} else if err != nil {
+			return err
+		}
+		previousEmail := sub.Email
+		if invalid = applyDigestSubscriptionRequest(&sub, req); invalid != nil {
+			return errInvalid
+		}
+		// A new address (or a resend for one still unconfirmed) gets a fresh
+		// confirmation link; digests wait until it is followed.
+		if req.Email != nil && (sub.Email != previousEmail || sub.EmailConfirmedAt == nil) {
+			sub.EmailConfirmedAt, sub.EmailConfirmToken, sub.EmailConfirmSentAt = nil, nil, nil
+			sub.EmailConfirmRetryAt, sub.EmailConfirmAttempts = nil, 0
+			if sub.Email != "" {
+				if !utils.EnforceRateLimit(c, "digests.confirm", 1) {
+					return errThrottled
+				}
+				token, err := utils.NewOpaqueToken()
+				if err != nil {
+					return err
+				}
+				sub.EmailConfirmToken = &token
+			}
+		}
+		sub.NextDueAt, sub.LeaseUntil = nil, nil
+		if sub.Enabled {
+			next := nextDigestDue(sub, time.Now().UTC())
+			sub.NextDueAt = &next
+		}
+		return tx.Save(&sub).Error
+	})
+	if errors.Is(err, errInvalid) {
+		c.JSON(http.StatusBadRequest, utils.HTTPError{Code: http.StatusBadRequest, Message: invalid.Error()})
+		return
+	}
+	if errors.Is(err, errThrottled) {
+		return // EnforceRateLimit wrote the 429
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.HTTPError{Code: http.StatusInternalServerError, Message: "Failed to save digest settings"})
+		return
+	}
+	c.JSON(http.StatusOK, utils.ResponseMessage{Code: http.StatusOK, Message: "Digest settings saved", Data: newDigestSubscriptionResponse(c, sub)})
+}
+
+// ListDigestDeliveries returns the caller's most recent ledger entries.
+// GET /api/v1/me/digest/deliveries
+func ListDigestDeliveries(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+	uid, ok := authedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, utils.HTTPError{Code: http.StatusUnauthorized, Message: "Authentication required"})
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	deliveries := []models.DigestDelivery{}
+	if err := db.Where("tenant_id = ? AND user_id = ?", consumerTenant, uid).
+		Order("created_at DESC, id DESC").Limit(limit).Find(&deliveries).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.HTTPError{Code: http.StatusInternalServerError, Message: "Failed to load digest deliveries"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": deliveries})
+}
+
+// PreviewDigest renders what the caller's next digest would carry right now,
+// without sending it or touching the ledger. format is json (default), html
+// or text.
+// GET /api/v1/me/digest/preview
+func PreviewDigest(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+	uid, ok := authedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, utils.HTTPError{Code: http.StatusUnauthorized, Message: "Authentication required"})
+		return
+	}
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "html" && format != "text" {
+		c.JSON(http.StatusBadRequest, utils.HTTPError{Code: http.StatusBadRequest, Message: "format must be json, html or text"})
+		return
+	}
+	if !utils.EnforceRateLimit(c, "digests.preview", 1) {
+		return
+	}
+	sub := defaultDigestSubscription(uid)
+	if err := db.Where("tenant_id = ? AND user_id = ?", consumerTenant, uid).First(&sub).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, utils.HTTPError{Code: http.StatusInternalServerError, Message: "Failed to load digest settings"})
+		return
+	}
+	// cadence and language may be overridden to preview other settings.
+	var override digestSubscriptionRequest
+	if v := c.Query("cadence"); v != "" {
+		override.Cadence = &v
+	}
+	if v := c.Query("language"); v != "" {
+		override.Language = &v
+	}
+	if err := applyDigestSubscriptionRequest(&sub, override); err != nil {
+		c.JSON(http.StatusBadRequest, utils.HTTPError{Code: http.StatusBadRequest, Message: err.Error()})
+		return
+	}
+
+	now := time.Now().UTC()
+	_, since := digestPeriod(sub, now)
+	content, err := selectDigestContent(db, sub, since, now)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.HTTPError{Code: http.StatusInternalServerError, Message: "Failed to assemble digest"})
+		return
+	}
+	unsubscribeURL := newDigestSubscriptionResponse(c, sub).UnsubscribeURL
+	subject, html, text, err := renderDigest(sub.Language, sub.Cadence, now.In(digestLocation(sub.Timezone)), content, unsubscribeURL)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.HTTPError{Code: http.StatusInternalServerError, Message: "Failed to render digest"})
+		return
+	}
+	switch format {
+	case "html":
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(html))
+	case "text":
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(text))
+	default:
+		c.JSON(http.StatusOK, gin.H{"subject": subject, "language": sub.Language, "cadence": sub.Cadence, "since": since, "content": content})
+	}
+}
+
+// UnsubscribeDigestPage is where the unsubscribe link in every digest lands.
+// It only asks for confirmation; see UnsubscribeDigest.
+// GET /api/v1/digests/unsubscribe/:token
+func UnsubscribeDigestPage(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+	var sub models.DigestSubscription
+	if err := db.Where("unsubscribe_token = ?", strings.TrimSpace(c.Param("token"))).First(&sub).Error; err != nil {
+		c.JSON(http.StatusNotFound, utils.HTTPError{Code: http.StatusNotFound, Message: "Subscription not found"})
+		return
+	}
+	locale := digestLocaleFor(sub.Language)
+	writeDigestActionPage(c, sub.Language, locale.UnsubscribeAsk, locale.Unsubscribe)
+}
+
+// UnsubscribeDigest turns a digest off by the token carried in every digest.
+// It is the RFC 8058 one-click POST (List-Unsubscribe-Post) and the target of
+// the landing page's form; it is idempotent.
+// POST /api/v1/digests/unsubscribe/:token
+func UnsubscribeDigest(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+	token := strings.TrimSpace(c.Param("token"))
+	if token == "" {
+		c.JSON(http.StatusNotFound, utils.HTTPError{Code: http.StatusNotFound, Message: "Subscription not found"})
+		return
+	}
+	var sub models.DigestSubscription
+	if err := db.Where("unsubscribe_token = ?", token).First(&sub).Error; err != nil {
+		c.JSON(http.StatusNotFound, utils.HTTPError{Code: http.StatusNotFound, Message: "Subscription not found"})
+		return
+	}
+	if err := db.Model(&models.DigestSubscription{}).Where("id = ?", sub.ID).
+		Updates(map[string]any{"enabled": false, "next_due_at": nil, "lease_until": nil}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.HTTPError{Code: http.StatusInternalServerError, Message: "Failed to unsubscribe"})
+		return
+	}
+	if c.NegotiateFormat(gin.MIMEJSON, gin.MIMEHTML) == gin.MIMEHTML {
+		writeDigestActionPage(c, sub.Language, digestLocaleFor(sub.Language).UnsubscribeDone, "")
+		return
+	}
+	c.JSON(http.StatusOK, utils.ResponseMessage{Code: http.StatusOK, Message: "Unsubscribed from digests"})
+}
+
+func writeDigestActionPage(c *gin.Context, language, message, button string) {
+	page, err := renderDigestActionPage(language, message, button)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.HTTPError{Code: http.StatusInternalServerError, Message: "Failed to render page"})
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(page))
+}
+
+// ConfirmDigestEmailPage is where the confirmation link mailed to a new
+// digest address lands. It only asks; see ConfirmDigestEmail.
+// GET /api/v1/digests/confirm/:token
+func ConfirmDigestEmailPage(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+	var sub models.DigestSubscription
+	if err := db.Where("email_confirm_token = ?", strings.TrimSpace(c.Param("token"))).First(&sub).Error; err != nil {
+		c.JSON(http.StatusNotFound, utils.HTTPError{Code: http.StatusNotFound, Message: "Confirmation link not found"})
+		return
+	}
+	locale := digestLocaleFor(sub.Language)
+	if sub.EmailConfirmedAt != nil {
+		writeDigestActionPage(c, sub.Language, locale.ConfirmDone, "")
+		return
+	}
+	writeDigestActionPage(c, sub.Language, locale.ConfirmAsk, locale.ConfirmButton)
+}
+
+// ConfirmDigestEmail marks the digest address the token was mailed to as
+// confirmed, so digests start going to it. It is idempotent; a token stops
+// working once the address changes.
+// POST /api/v1/digests/confirm/:token
+func ConfirmDigestEmail(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+	token := strings.TrimSpace(c.Param("token"))
+	var sub models.DigestSubscription
+	if token == "" || db.Where("email_confirm_token = ?", token).First(&sub).Error != nil {
+		c.JSON(http.StatusNotFound, utils.HTTPError{Code: http.StatusNotFound, Message: "Confirmation link not found"})
+		return
+	}
+	if err := db.Model(&models.DigestSubscription{}).
+		Where("id = ? AND email_confirm_token = ? AND email_confirmed_at IS NULL", sub.ID, token).
+		Update("email_confirmed_at", time.Now().UTC()).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.HTTPError{Code: http.StatusInternalServerError, Message: "Failed to confirm address"})
+		return
+	}
+	if c.NegotiateFormat(gin.MIMEJSON, gin.MIMEHTML) == gin.MIMEHTML {
+		writeDigestActionPage(c, sub.Language, digestLocaleFor(sub.Language).ConfirmDone, "")
+		return
+	}
+	c.JSON(http.StatusOK, utils.ResponseMessage{Code: http.StatusOK, Message: "Digest address confirmed"})
+}
@@ -0,0 +1,275 @@
+package controllers
+
+import (
+	"bytes"
+	"content-management-system/src/models"
+	htmltemplate "html/template"
+	"strconv"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type digestStory struct {
+	StoryID     uuid.UUID `json:"story_id"`
+	LeadID      uuid.UUID `json:"lead_id"`
+	Title       string    `json:"title"`
+	Summary     string    `json:"summary,omitempty"`
+	SourceName  string    `json:"source_name,omitempty"`
+	MemberCount int       `json:"member_count"`
+	URL         string    `json:"url,omitempty"`
+}
+
+type digestPod struct {
+	ID          uuid.UUID `json:"id"`
+	Title       string    `json:"title"`
+	Excerpt     string    `json:"excerpt,omitempty"`
+	SourceName  string    `json:"source_name,omitempty"`
+	DurationSec int       `json:"duration_sec,omitempty"`
+	URL         string    `json:"url,omitempty"`
+}
+
+// digestContent is what one digest carries, in rank order.
+type digestContent struct {
+	Stories []digestStory `json:"stories"`
+	Pods    []digestPod   `json:"pods"`
+}
+
+func (d digestContent) empty() bool {
+	return len(d.Stories) == 0 && len(d.Pods) == 0
+}
+
+func (d digestContent) contentIDs() []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(d.Stories)+len(d.Pods))
+	for _, story := range d.Stories {
+		ids = append(ids, story.StoryID)
+	}
+	for _, pod := range d.Pods {
+		ids = append(ids, pod.ID)
+	}
+	return ids
+}
+
+// digestLocale holds a digest language's strings and text direction.
+type digestLocale struct {
+	Lang          string
+	Dir           string
+	Align         string
+	SubjectDaily  string
+	SubjectWeekly string
+	TopStories    string
+	Pods          string
+	Reports       string
+	Minutes       string
+	Footer        string
+	Unsubscribe   string
+	// UnsubscribeAsk and UnsubscribeDone are the unsubscribe landing page.
+	UnsubscribeAsk  string
+	UnsubscribeDone string
+	// Confirm* are the address confirmation email and its landing page.
+	ConfirmSubject string
+	ConfirmIntro   string
+	ConfirmAsk     string
+	ConfirmButton  string
+	ConfirmDone    string
+}
+
+var digestLocales = map[string]digestLocale{
+	"ar": {
+		Lang: "ar", Dir: "rtl", Align: "right",
+		SubjectDaily:    "ملخصك اليومي",
+		SubjectWeekly:   "ملخصك الأسبوعي",
+		TopStories:      "أبرز الأخبار",
+		Pods:            "مختارات Pods",
+		Reports:         "تقارير",
+		Minutes:         "دقيقة",
+		Footer:          "تصلك هذه الرسالة لأنك اشتركت في الملخص.",
+		Unsubscribe:     "إلغاء الاشتراك",
+		UnsubscribeAsk:  "هل تريد إيقاف رسائل الملخص؟",
+		UnsubscribeDone: "لن تصلك رسائل الملخص بعد الآن.",
+		ConfirmSubject:  "أكّد بريدك لتلقي الملخص",
+		ConfirmIntro:    "طلب أحدهم إرسال الملخص إلى هذا العنوان. إن كنت أنت، فأكّده من الرابط التالي، وإلا فتجاهل هذه الرسالة.",
+		ConfirmAsk:      "هل تريد تلقي الملخص على هذا العنوان؟",
+		ConfirmButton:   "تأكيد",
+		ConfirmDone:     "تم تأكيد عنوانك، وسيصلك الملخص في موعده.",
+	},
+	"en": {
+		Lang: "en", Dir: "ltr", Align: "left",
+		SubjectDaily:    "Your daily digest",
+		SubjectWeekly:   "Your weekly digest",
+		TopStories:      "Top stories",
+		Pods:            "Pods for you",
+		Reports:         "reports",
+		Minutes:         "min",
+		Footer:          "You are receiving this because you subscribed to the digest.",
+		Unsubscribe:     "Unsubscribe",
+		UnsubscribeAsk:  "Stop receiving the digest?",
+		UnsubscribeDone: "You will no longer receive the digest.",
+		ConfirmSubject:  "Confirm your address for the digest",
+		ConfirmIntro:    "Someone asked for the digest to be sent to this address. If it was you, confirm it with the link below; otherwise ignore this email.",
+		ConfirmAsk:      "Receive the digest at this address?",
+		ConfirmButton:   "Confirm",
+		ConfirmDone:     "Your address is confirmed; the digest will arrive on schedule.",
+	},
+}
+
+func digestLocaleFor(language string) digestLocale {
+	if locale, ok := digestLocales[language]; ok {
+		return locale
+	}
+	return digestLocales["ar"]
+}
+
+type digestView struct {
+	L              digestLocale
+	Subject        string
+	Stories        []digestStory
+	Pods           []digestPodView
+	UnsubscribeURL string
+}
+
+type digestPodView struct {
+	digestPod
+	Duration string
+}
+
+var digestHTMLTemplate = htmltemplate.Must(htmltemplate.New("digest").Parse(`<!DOCTYPE html>
+<html lang="{{.L.Lang}}" dir="{{.L.Dir}}">
+<head><meta charset="utf-8"><title>{{.Subject}}</title></head>
+<body dir="{{.L.Dir}}" style="margin:0;padding:24px;font-family:Arial,Tahoma,sans-serif;text-align:{{.L.Align}};direction:{{.L.Dir}};">
+<h1 style="font-size:20px;">{{.Subject}}</h1>
+{{- if .Stories}}
+<h2 style="font-size:16px;">{{.L.TopStories}}</h2>
+{{- range .Stories}}
+<div style="margin:0 0 16px;">
+<h3 style="font-size:15px;margin:0;">{{if .URL}}<a href="{{.URL}}">{{.Title}}</a>{{else}}{{.Title}}{{end}}</h3>
+{{- if .Summary}}<p style="margin:4px 0;">{{.Summary}}</p>{{end}}
+<p style="margin:0;color:#666;font-size:12px;">{{.SourceName}}{{if gt .MemberCount 1}} · {{.MemberCount}} {{$.L.Reports}}{{end}}</p>
+</div>
+{{- end}}
+{{- end}}
+{{- if .Pods}}
+<h2 style="font-size:16px;">{{.L.Pods}}</h2>
+{{- range .Pods}}
+<div style="margin:0 0 16px;">
+<h3 style="font-size:15px;margin:0;">{{if .URL}}<a href="{{.URL}}">{{.Title}}</a>{{else}}{{.Title}}{{end}}</h3>
+{{- if .Excerpt}}<p style="margin:4px 0;">{{.Excerpt}}</p>{{end}}
+<p style="margin:0;color:#666;font-size:12px;">{{.SourceName}}{{if .Duration}} · {{.Duration}}{{end}}</p>
+</div>
+{{- end}}
+{{- end}}
+<hr>
+<p style="color:#666;font-size:12px;">{{.L.Footer}} <a href="{{.UnsubscribeURL}}">{{.L.Unsubscribe}}</a></p>
+</body>
+</html>
+`))
+
+var digestTextTemplate = texttemplate.Must(texttemplate.New("digest").Parse(`{{.Subject}}
+{{if .Stories}}
+{{.L.TopStories}}
+{{range .Stories}}
+- {{.Title}}{{if .SourceName}} ({{.SourceName}}){{end}}
+{{- if .Summary}}
+  {{.Summary}}{{end}}
+{{- if .URL}}
+  {{.URL}}{{end}}
+{{end}}{{end}}{{if .Pods}}
+{{.L.Pods}}
+{{range .Pods}}
+- {{.Title}}{{if .Duration}} · {{.Duration}}{{end}}
+{{- if .URL}}
+  {{.URL}}{{end}}
+{{end}}{{end}}
+--
+{{.L.Footer}}
+{{.L.Unsubscribe}}: {{.UnsubscribeURL}}
+`))
+
+// digestActionTemplate is the page a digest link opens. It only asks: the
+// change is made by the form's POST, so mail scanners that prefetch links
+// cannot act for the reader.
+var digestActionTemplate = htmltemplate.Must(htmltemplate.New("digest-action").Parse(`<!DOCTYPE html>
+<html lang="{{.L.Lang}}" dir="{{.L.Dir}}">
+<head><meta charset="utf-8"><meta name="viewport" content="width=device-width"><title>{{.Message}}</title></head>
+<body dir="{{.L.Dir}}">
+<p>{{.Message}}</p>
+{{- if .Button}}
+<form method="post"><button type="submit">{{.Button}}</button></form>
+{{- end}}
+</body>
+</html>
+`))
+
+var digestConfirmHTMLTemplate = htmltemplate.Must(htmltemplate.New("digest-confirm").Parse(`<!DOCTYPE html>
+<html lang="{{.L.Lang}}" dir="{{.L.Dir}}">
+<head><meta charset="utf-8"><title>{{.L.ConfirmSubject}}</title></head>
+<body dir="{{.L.Dir}}" style="margin:0;padding:24px;font-family:Arial,Tahoma,sans-serif;text-align:{{.L.Align}};direction:{{.L.Dir}};">
+<p>{{.L.ConfirmIntro}}</p>
+<p><a href="{{.URL}}">{{.L.ConfirmButton}}</a></p>
+</body>
+</html>
+`))
+
+var digestConfirmTextTemplate = texttemplate.Must(texttemplate.New("digest-confirm").Parse(`{{.L.ConfirmIntro}}
+
+{{.L.ConfirmButton}}: {{.URL}}
+`))
+
+// renderDigestConfirmation produces the email that asks a reader to confirm
+// a new digest address.
+func renderDigestConfirmation(language, confirmURL string) (subject, html, text string, err error) {
+	view := struct {
+		L   digestLocale
+		URL string
+	}{digestLocaleFor(language), confirmURL}
+	var htmlBuf, textBuf bytes.Buffer
+	if err = digestConfirmHTMLTemplate.Execute(&htmlBuf, view); err != nil {
+		return "", "", "", err
+	}
+	if err = digestConfirmTextTemplate.Execute(&textBuf, view); err != nil {
+		return "", "", "", err
+	}
+	return view.L.ConfirmSubject, htmlBuf.String(), textBuf.String(), nil
+}
+
+// renderDigestActionPage renders a digest link's landing page; an empty
+// button renders the outcome without a form.
+func renderDigestActionPage(language, message, button string) (string, error) {
+	var buf bytes.Buffer
+	err := digestActionTemplate.Execute(&buf, struct {
+		L               digestLocale
+		Message, Button string
+	}{digestLocaleFor(language), message, button})
+	return buf.String(), err
+}
+
+// renderDigest produces the subject, HTML and plain-text bodies. Arabic
+// digests are laid out right-to-left.
+func renderDigest(language, cadence string, periodEnd time.Time, content digestContent, unsubscribeURL string) (subject, html, text string, err error) {
+	locale := digestLocaleFor(language)
+	subject = locale.SubjectDaily
+	if cadence == models.DigestCadenceWeekly {
+		subject = locale.SubjectWeekly
+	}
+	subject += " — " + periodEnd.Format("2006-01-02")
+
+	view := digestView{L: locale, Subject: subject, Stories: content.Stories, UnsubscribeURL: unsubscribeURL}
+	for _, pod := range content.Pods {
+		podView := digestPodView{digestPod: pod}
+		if pod.DurationSec > 0 {
+			minutes := (pod.DurationSec + 59) / 60
+			podView.Duration = strconv.Itoa(minutes) + " " + locale.Minutes
+		}
+		view.Pods = append(view.Pods, podView)
+	}
+
+	var htmlBuf, textBuf bytes.Buffer
+	if err = digestHTMLTemplate.Execute(&htmlBuf, view); err != nil {
+		return "", "", "", err
+	}
+	if err = digestTextTemplate.Execute(&textBuf, view); err != nil {
+		return "", "", "", err
+	}
+	return subject, htmlBuf.String(), textBuf.String(), nil
+}
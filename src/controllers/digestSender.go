@@ -0,0 +1,280 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const digestWebhookTimeout = 15 * time.Second
+
+// errDigestNoRecipient means the sender needs an address the subscription
+// does not have; the ledger records the period as skipped, not failed.
+var errDigestNoRecipient = errors.New("digest subscription has no email address")
+
+// Digest message kinds.
+const (
+	digestKindDigest            = "digest"
+	digestKindEmailConfirmation = "email_confirmation"
+)
+
+// DigestMessage is one rendered digest handed to a sender. ID is the ledger
+// row's public id, stable across retries of the same period. Senders also
+// carry the address confirmation email (Kind email_confirmation), which has
+// no period, content or unsubscribe link.
+type DigestMessage struct {
+	ID             uuid.UUID     `json:"id"`
+	Kind           string        `json:"kind"`
+	TenantID       string        `json:"tenant_id"`
+	UserID         uuid.UUID     `json:"user_id"`
+	To             string        `json:"email,omitempty"`
+	Language       string        `json:"language"`
+	Cadence        string        `json:"cadence"`
+	Period         string        `json:"period"`
+	Subject        string        `json:"subject"`
+	HTML           string        `json:"html"`
+	Text           string        `json:"text"`
+	UnsubscribeURL string        `json:"unsubscribe_url,omitempty"`
+	ConfirmURL     string        `json:"confirm_url,omitempty"`
+	Content        digestContent `json:"content"`
+}
+
+// DigestSender delivers rendered digests. Implementations must be safe to
+// retry with the same message: the worker re-sends a period only when the
+// previous attempt returned an error.
+type DigestSender interface {
+	Name() string
+	Send(ctx context.Context, msg DigestMessage) error
+}
+
+// digestSenderFromEnv picks the deployment's sender. An empty DIGEST_SENDER
+// leaves digests disabled.
+func digestSenderFromEnv() (DigestSender, error) {
+	env := func(key string) string { return strings.TrimSpace(os.Getenv(key)) }
+	switch kind := strings.ToLower(env("DIGEST_SENDER")); kind {
+	case "":
+		return nil, nil
+	case "smtp":
+		sender := smtpDigestSender{Host: env("SMTP_HOST"), Port: env("SMTP_PORT"), Username: env("SMTP_USERNAME"), Password: os.Getenv("SMTP_PASSWORD"), From: env("DIGEST_FROM")}
+		if sender.Port == "" {
+			sender.Port = "587"
+		}
+		if sender.Host == "" || sender.From == "" {
+			return nil, errors.New("DIGEST_SENDER=smtp requires SMTP_HOST and DIGEST_FROM")
+		}
+		return sender, nil
+	case "webhook":
+		sender := webhookDigestSender{URL: env("DIGEST_WEBHOOK_URL"), Secret: os.Getenv("DIGEST_WEBHOOK_SECRET"), Client: &http.Client{Timeout: digestWebhookTimeout}}
+		if sender.URL == "" {
+			return nil, errors.New("DIGEST_SENDER=webhook requires DIGEST_WEBHOOK_URL")
+		}
+		return sender, nil
+	case "file":
+		sender := fileDigestSender{Dir: env("DIGEST_FILE_DIR"), From: env("DIGEST_FROM")}
+		if sender.Dir == "" {
+			return nil, errors.New("DIGEST_SENDER=file requires DIGEST_FILE_DIR")
+		}
+		return sender, nil
+	default:
+		return nil, fmt.Errorf("unknown DIGEST_SENDER %q", kind)
+	}
+}
+
+// ─── SMTP ───────────────────────────────────────────────────
+
+type smtpDigestSender struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+func (smtpDigestSender) Name() string { return "smtp" }
+
+// Send relays through the configured server, upgrading to STARTTLS when
+// offered; PLAIN auth refuses to run over an unencrypted remote link. The
+// whole exchange is bounded by ctx, so a stalled relay cannot hold the
+// worker's lease.
+func (s smtpDigestSender) Send(ctx context.Context, msg DigestMessage) error {
+	if msg.To == "" {
+		return errDigestNoRecipient
+	}
+	body, err := buildDigestMIME(msg, s.From, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(s.Host, s.Port))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return err
+		}
+	}
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	client, err := smtp.NewClient(conn, s.Host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.Host}); err != nil {
+			return err
+		}
+	}
+	if s.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.Username, s.Password, s.Host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(s.From); err != nil {
+		return err
+	}
+	if err := client.Rcpt(msg.To); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(body); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// ─── Webhook ────────────────────────────────────────────────
+
+// webhookDigestSender posts the rendered digest as JSON to a notification
+// service (push, in-app inbox). Bodies are signed like WebSub deliveries.
+type webhookDigestSender struct {
+	URL    string
+	Secret string
+	Client *http.Client
+}
+
+func (webhookDigestSender) Name() string { return "webhook" }
+
+func (s webhookDigestSender) Send(ctx context.Context, msg DigestMessage) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", msg.ID.String())
+	if s.Secret != "" {
+		req.Header.Set("X-Digest-Signature", webSubSignature(s.Secret, body))
+	}
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("digest webhook returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// ─── File sink ──────────────────────────────────────────────
+
+// fileDigestSender writes each digest as an .eml file for local development
+// and tests. Digests without an address are still written.
+type fileDigestSender struct {
+	Dir  string
+	From string
+}
+
+func (fileDigestSender) Name() string { return "file" }
+
+func (s fileDigestSender) Send(_ context.Context, msg DigestMessage) error {
+	from := s.From
+	if from == "" {
+		from = "digest@localhost"
+	}
+	body, err := buildDigestMIME(msg, from, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(s.Dir, msg.ID.String()+".eml"), body, 0o644)
+}
+
+// buildDigestMIME renders a multipart/alternative message with one-click
+// unsubscribe headers (RFC 8058).
+func buildDigestMIME(msg DigestMessage, from string, now time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	parts := multipart.NewWriter(&buf)
+	header := func(key, value string) {
+		fmt.Fprintf(&buf, "%s: %s\r\n", key, value)
+	}
+	header("From", from)
+	if msg.To != "" {
+		header("To", msg.To)
+	}
+	header("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	header("Date", now.Format(time.RFC1123Z))
+	header("Message-ID", "<"+msg.ID.String()+"@digest>")
+	header("MIME-Version", "1.0")
+	if msg.UnsubscribeURL != "" {
+		header("List-Unsubscribe", "<"+msg.UnsubscribeURL+">")
+		header("List-Unsubscribe-Post", "List-Unsubscribe=One-Click")
+	}
+	header("Content-Type", "multipart/alternative; boundary="+parts.Boundary())
+	buf.WriteString("\r\n")
+
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", msg.Text},
+		{"text/html; charset=utf-8", msg.HTML},
+	} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.body)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
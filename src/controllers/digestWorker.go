@@ -0,0 +1,495 @@
+package controllers
+
+import (
+	"content-management-system/src/lifecycle"
+	"content-management-system/src/models"
+	"content-management-system/src/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	digestTick        = time.Minute
+	digestLease       = 10 * time.Minute
+	digestSendTimeout = 30 * time.Second
+	digestMaxAttempts = 3
+	digestBatch       = 20
+	digestMaxStories  = 5
+	digestMaxPods     = 5
+)
+
+var (
+	digestHeartbeat atomic.Int64
+	// digestsDisabled is set when no sender is configured: an idle worker
+	// is not an unhealthy one.
+	digestsDisabled atomic.Bool
+)
+
+// StartDigestWorker sends due digests through the sender DIGEST_SENDER
+// selects. Subscriptions are claimed with a lease and every period is written
+// to the ledger before sending, so replicas never double-send a period.
+func StartDigestWorker(ctx context.Context, db *gorm.DB) {
+	sender, err := digestSenderFromEnv()
+	if err != nil {
+		log.Printf("digest worker disabled: %v", err)
+		return
+	}
+	if sender == nil {
+		log.Printf("digest worker disabled: DIGEST_SENDER is not set")
+		digestsDisabled.Store(true)
+		return
+	}
+	lifecycle.Go(ctx, "digests", func(ctx context.Context) {
+		ticker := time.NewTicker(digestTick)
+		defer ticker.Stop()
+		runDigests(ctx, db, sender)
+		for lifecycle.Wait(ctx, ticker.C) {
+			runDigests(ctx, db, sender)
+		}
+	})
+}
+
+// DigestWorkerHealthy reports a fresh heartbeat. Digests switched off by an
+// empty DIGEST_SENDER count as healthy; a sender that fails to configure
+// does not.
+func DigestWorkerHealthy(now time.Time) bool {
+	if digestsDisabled.Load() {
+		return true
+	}
+	last := digestHeartbeat.Load()
+	return last > 0 && now.UTC().Sub(time.Unix(0, last).UTC()) <= 6*digestTick
+}
+
+func DigestWorkerLastHeartbeat() time.Time {
+	return lifecycle.HeartbeatTime(digestHeartbeat.Load())
+}
+
+// runDigests stops claiming once ctx is cancelled; a claimed digest is always
+// finished so its ledger row and lease are settled before shutdown.
+func runDigests(ctx context.Context, db *gorm.DB, sender DigestSender) {
+	sendDigestConfirmations(ctx, db, sender)
+	for i := 0; i < digestBatch && ctx.Err() == nil; i++ {
+		sub, err := claimDueDigest(db, time.Now().UTC())
+		if err != nil {
+			log.Printf("digest claim failed: %v", err)
+			break
+		}
+		if sub == nil {
+			break
+		}
+		processDigest(db, sender, *sub, time.Now().UTC())
+	}
+	digestHeartbeat.Store(time.Now().UTC().UnixNano())
+}
+
+// ─── Schedule ───────────────────────────────────────────────
+
+func digestLocation(name string) *time.Location {
+	if loc, err := time.LoadLocation(name); err == nil {
+		return loc
+	}
+	return time.UTC
+}
+
+// nextDigestDue is the first send slot strictly after `after`, at SendHour
+// local time (on WeeklyDay for weekly digests).
+func nextDigestDue(sub models.DigestSubscription, after time.Time) time.Time {
+	local := after.In(digestLocation(sub.Timezone))
+	slot := time.Date(local.Year(), local.Month(), local.Day(), sub.SendHour, 0, 0, 0, local.Location())
+	step := 1
+	if sub.Cadence == models.DigestCadenceWeekly {
+		step = 7
+		slot = slot.AddDate(0, 0, (sub.WeeklyDay-int(slot.Weekday())+7)%7)
+	}
+	if !slot.After(after) {
+		slot = slot.AddDate(0, 0, step)
+	}
+	return slot.UTC()
+}
+
+// digestPeriod names the ledger period a send slot belongs to and returns the
+// start of the window it covers.
+func digestPeriod(sub models.DigestSubscription, due time.Time) (string, time.Time) {
+	local := due.In(digestLocation(sub.Timezone))
+	if sub.Cadence == models.DigestCadenceWeekly {
+		year, week := local.ISOWeek()
+		return fmt.Sprintf("weekly:%d-W%02d", year, week), local.AddDate(0, 0, -7).UTC()
+	}
+	return "daily:" + local.Format("2006-01-02"), local.AddDate(0, 0, -1).UTC()
+}
+
+func digestUnsubscribeURL(base, token string) string {
+	return base + "/api/v1/digests/unsubscribe/" + token
+}
+
+func digestConfirmURL(base, token string) string {
+	return base + "/api/v1/digests/confirm/" + token
+}
+
+// digestPublicBase is the origin links in worker-sent mail point at.
+func digestPublicBase() string {
+	return strings.TrimRight(strings.TrimSpace(os.Getenv("PUBLIC_BASE_URL")), "/")
+}
+
+// digestLinkBase is where digest links point; unset, links go to the
+// original source.
+func digestLinkBase() string {
+	return strings.TrimRight(strings.TrimSpace(os.Getenv("DIGEST_LINK_BASE")), "/")
+}
+
+func digestItemURL(base, kind string, id uuid.UUID, original *string) string {
+	if base != "" {
+		return base + "/" + kind + "/" + id.String()
+	}
+	return derefStr(original)
+}
+
+// ─── Selection ──────────────────────────────────────────────
+
+// selectDigestContent picks the reader's top stories and Pods since the
+// window start. Stories come from the personalized News assembly, Pods from
+// the ranking engine with the preference hook; muted sources and topics are
+// dropped from both.
+func selectDigestContent(db *gorm.DB, sub models.DigestSubscription, since, now time.Time) (digestContent, error) {
+	content := digestContent{Stories: []digestStory{}, Pods: []digestPod{}}
+	// The subscription row is consumer-owned; the content comes from the
+	// same tenant the public feeds serve.
+	tenantID, err := utils.GetConfiguredPublicTenantID()
+	if err != nil {
+		return content, err
+	}
+	userIDStr := sub.UserID.String()
+	config := loadTenantConfig(db, tenantID)
+	mutedSources := loadMutedSourceKeys(db, tenantID, sub.UserID)
+	_, _, mutedTopics := loadUserAffinityMaps(db, tenantID, sub.UserID)
+	linkBase := digestLinkBase()
+
+	window := models.NewsWindowToday
+	if sub.Cadence == models.DigestCadenceWeekly {
+		window = models.NewsWindowWeek
+	}
+	circ := circulationContextFor(db, tenantID, window, now)
+	slides, _, err := assembleStoryNewsFeed(db, tenantID, config, circ, time.Time{}, uuid.Nil, digestMaxStories*2, nil, userIDStr, false)
+	if err != nil {
+		return content, err
+	}
+	var stories []StorySummary
+	leadIDs := make([]uuid.UUID, 0, len(slides))
+	for _, slide := range slides {
+		if slide.Featured.LastMemberAt.After(since) {
+			stories = append(stories, slide.Featured.StorySummary)
+			leadIDs = append(leadIDs, slide.Featured.LeadID)
+		}
+	}
+	var leads []models.ContentItem
+	if len(leadIDs) > 0 {
+		if err := db.Where("public_id IN ?", leadIDs).Find(&leads).Error; err != nil {
+			return content, err
+		}
+	}
+	leadByID := make(map[uuid.UUID]models.ContentItem, len(leads))
+	for _, lead := range leads {
+		leadByID[lead.PublicID] = lead
+	}
+	mutedLeads := digestMutedTopicItems(db, tenantID, leadIDs, mutedTopics)
+	for _, story := range stories {
+		lead, ok := leadByID[story.LeadID]
+		if !ok || mutedLeads[story.LeadID] || digestSourceMuted(mutedSources, lead) {
+			continue
+		}
+		title := story.Label
+		if title == "" {
+			title = story.Title
+		}
+		summary := story.Summary
+		if summary == "" {
+			summary = story.Excerpt
+		}
+		content.Stories = append(content.Stories, digestStory{
+			StoryID: story.StoryID, LeadID: story.LeadID, Title: title, Summary: summary,
+			SourceName: story.SourceName, MemberCount: story.MemberCount,
+			URL: digestItemURL(linkBase, "stories", story.StoryID, lead.OriginalURL),
+		})
+		if len(content.Stories) == digestMaxStories {
+			break
+		}
+	}
+
+	var candidates []models.ContentItem
+	if err := podsEligibleMediaQuery(db, tenantID, supportsAtomizedPodsSchema(db)).
+		Where("COALESCE(published_at, created_at) > ?", since).
+		Order("COALESCE(published_at, created_at) DESC").Limit(200).Find(&candidates).Error; err != nil {
+		return content, err
+	}
+	candidates = excludeCollapsedRedundancyMembers(db, tenantID, candidates)
+	ids := extractPublicIDs(candidates)
+	scored := ScoreItems(candidates, config, LoadContentFlags(db, tenantID, ids), LoadVelocityData(db, ids, config.VelocityWindowHours, now), nil, now)
+	scored, _ = applyPreferenceFeedHook(db, tenantID, userIDStr, scored)
+	scored = applyIntelligenceFeedHooks(db, tenantID, scored)
+	mutedPods := digestMutedTopicItems(db, tenantID, ids, mutedTopics)
+	seenParents := map[uuid.UUID]bool{}
+	for _, s := range scored {
+		item := s.Item
+		if mutedPods[item.PublicID] || digestSourceMuted(mutedSources, item) {
+			continue
+		}
+		// One chapter per episode is enough for a digest.
+		if item.ParentContentItemID != nil {
+			if seenParents[*item.ParentContentItemID] {
+				continue
+			}
+			seenParents[*item.ParentContentItemID] = true
+		}
+		pod := digestPod{
+			ID: item.PublicID, Title: derefStr(item.Title), Excerpt: derefStr(item.Excerpt),
+			SourceName: derefStr(item.SourceName), URL: digestItemURL(linkBase, "pods", item.PublicID, item.OriginalURL),
+		}
+		if item.DurationSec != nil {
+			pod.DurationSec = *item.DurationSec
+		}
+		content.Pods = append(content.Pods, pod)
+		if len(content.Pods) == digestMaxPods {
+			break
+		}
+	}
+	return content, nil
+}
+
+func digestSourceMuted(muted map[string]struct{}, item models.ContentItem) bool {
+	_, ok := muted[canonicalContentSourceKey(item)]
+	return ok
+}
+
+// digestMutedTopicItems returns the items tagged with any of the reader's
+// muted topics.
+func digestMutedTopicItems(db *gorm.DB, tenantID string, ids []uuid.UUID, mutedTopics map[uuid.UUID]bool) map[uuid.UUID]bool {
+	out := map[uuid.UUID]bool{}
+	if len(ids) == 0 || len(mutedTopics) == 0 {
+		return out
+	}
+	topics := make([]uuid.UUID, 0, len(mutedTopics))
+	for id := range mutedTopics {
+		topics = append(topics, id)
+	}
+	var muted []uuid.UUID
+	db.Table("content_item_topics cit").
+		Joins("JOIN topics ON topics.public_id = cit.topic_id").
+		Where("cit.content_item_id IN ? AND cit.topic_id IN ? AND topics.tenant_id = ?", ids, topics, tenantID).
+		Distinct().Pluck("cit.content_item_id", &muted)
+	for _, id := range muted {
+		out[id] = true
+	}
+	return out
+}
+
+// ─── Delivery ───────────────────────────────────────────────
+
+// sendDigestConfirmations mails the confirmation link for new digest
+// addresses. email_confirm_sent_at is only written once a send succeeds; a
+// failed send backs off and is retried up to digestMaxAttempts, and a worker
+// that dies mid-send is retried when its lease lapses. A reader whose link
+// never arrived saves the address again for a new one.
+func sendDigestConfirmations(ctx context.Context, db *gorm.DB, sender DigestSender) {
+	for i := 0; i < digestBatch && ctx.Err() == nil; i++ {
+		now := time.Now().UTC()
+		sub, err := claimDigestConfirmation(db, now)
+		if err != nil {
+			log.Printf("digest confirmation claim failed: %v", err)
+			return
+		}
+		if sub == nil {
+			return
+		}
+		msg := DigestMessage{
+			ID: uuid.New(), Kind: digestKindEmailConfirmation, TenantID: sub.TenantID, UserID: sub.UserID,
+			To: sub.Email, Language: sub.Language, Cadence: sub.Cadence,
+			ConfirmURL: digestConfirmURL(digestPublicBase(), *sub.EmailConfirmToken),
+		}
+		msg.Subject, msg.HTML, msg.Text, err = renderDigestConfirmation(sub.Language, msg.ConfirmURL)
+		if err == nil {
+			sendCtx, cancel := context.WithTimeout(context.Background(), digestSendTimeout)
+			err = sender.Send(sendCtx, msg)
+			cancel()
+		}
+		if err == nil {
+			if err := db.Model(&models.DigestSubscription{}).Where("id = ?", sub.ID).Updates(map[string]any{
+				"email_confirm_sent_at":  now,
+				"email_confirm_retry_at": nil,
+			}).Error; err != nil {
+				log.Printf("digest confirmation for subscription %d: failed to record send: %v", sub.ID, err)
+			}
+			continue
+		}
+		log.Printf("digest confirmation for subscription %d attempt %d failed: %v", sub.ID, sub.EmailConfirmAttempts, err)
+		retryAt := now.Add(time.Duration(sub.EmailConfirmAttempts) * 5 * time.Minute)
+		db.Model(&models.DigestSubscription{}).Where("id = ?", sub.ID).Update("email_confirm_retry_at", retryAt)
+	}
+}
+
+// claimDigestConfirmation leases the next unsent confirmation link. After a
+// failed send email_confirm_retry_at is the retry backoff.
+func claimDigestConfirmation(db *gorm.DB, now time.Time) (*models.DigestSubscription, error) {
+	var sub models.DigestSubscription
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("email_confirm_token IS NOT NULL AND email_confirm_sent_at IS NULL AND email_confirmed_at IS NULL AND COALESCE(email, '') <> ''").
+			Where("(email_confirm_retry_at IS NULL OR email_confirm_retry_at < ?) AND email_confirm_attempts < ?", now, digestMaxAttempts).
+			Order("id").First(&sub).Error; err != nil {
+			return err
+		}
+		lease := now.Add(digestLease)
+		sub.EmailConfirmRetryAt = &lease
+		sub.EmailConfirmAttempts++
+		return tx.Model(&models.DigestSubscription{}).Where("id = ?", sub.ID).Updates(map[string]any{
+			"email_confirm_retry_at": lease,
+			"email_confirm_attempts": gorm.Expr("email_confirm_attempts + 1"),
+		}).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// claimDueDigest leases the most overdue enabled subscription. After a
+// failed send lease_until is the retry backoff.
+func claimDueDigest(db *gorm.DB, now time.Time) (*models.DigestSubscription, error) {
+	var sub models.DigestSubscription
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("enabled AND next_due_at <= ? AND (lease_until IS NULL OR lease_until < ?)", now, now).
+			Order("next_due_at").First(&sub).Error; err != nil {
+			return err
+		}
+		lease := now.Add(digestLease)
+		sub.LeaseUntil = &lease
+		return tx.Model(&models.DigestSubscription{}).Where("id = ?", sub.ID).Update("lease_until", lease).Error
+	})
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// openDigestDelivery returns the ledger row for the period, creating it on
+// first attempt.
+func openDigestDelivery(db *gorm.DB, sub models.DigestSubscription, period, sender string) (models.DigestDelivery, error) {
+	delivery := models.DigestDelivery{
+		SubscriptionID: sub.ID, PeriodKey: period, TenantID: sub.TenantID, UserID: sub.UserID,
+		Cadence: sub.Cadence, Sender: sender, Status: models.DigestDeliveryPending,
+	}
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&delivery).Error; err != nil {
+		return delivery, err
+	}
+	err := db.Where("subscription_id = ? AND period_key = ?", sub.ID, period).First(&delivery).Error
+	return delivery, err
+}
+
+func processDigest(db *gorm.DB, sender DigestSender, sub models.DigestSubscription, now time.Time) {
+	due := *sub.NextDueAt
+	period, since := digestPeriod(sub, due)
+	if sub.LastSentAt != nil && sub.LastSentAt.After(since) {
+		since = *sub.LastSentAt
+	}
+	delivery, err := openDigestDelivery(db, sub, period, sender.Name())
+	if err != nil {
+		log.Printf("digest %d %s: failed to open ledger: %v", sub.ID, period, err)
+		return
+	}
+	if delivery.Status != models.DigestDeliveryPending {
+		advanceDigest(db, sub, due, now, nil)
+		return
+	}
+
+	content, err := selectDigestContent(db, sub, since, now)
+	if err == nil && content.empty() {
+		finishDigestDelivery(db, delivery, models.DigestDeliverySkipped, "nothing new this period", content, "", nil)
+		advanceDigest(db, sub, due, now, nil)
+		return
+	}
+	var msg DigestMessage
+	if err == nil {
+		msg = DigestMessage{
+			ID: delivery.PublicID, Kind: digestKindDigest, TenantID: sub.TenantID, UserID: sub.UserID,
+			Language: sub.Language, Cadence: sub.Cadence, Period: period, Content: content,
+			UnsubscribeURL: digestUnsubscribeURL(digestPublicBase(), sub.UnsubscribeToken),
+		}
+		// Only an address the reader confirmed is mailed.
+		if sub.EmailConfirmedAt != nil {
+			msg.To = sub.Email
+		}
+		msg.Subject, msg.HTML, msg.Text, err = renderDigest(sub.Language, sub.Cadence, due.In(digestLocation(sub.Timezone)), content, msg.UnsubscribeURL)
+	}
+	if err == nil {
+		db.Model(&models.DigestDelivery{}).Where("id = ?", delivery.ID).Update("attempts", gorm.Expr("attempts + 1"))
+		delivery.Attempts++
+		ctx, cancel := context.WithTimeout(context.Background(), digestSendTimeout)
+		err = sender.Send(ctx, msg)
+		cancel()
+	}
+	switch {
+	case err == nil:
+		sentAt := now
+		finishDigestDelivery(db, delivery, models.DigestDeliverySent, "", content, msg.Subject, &sentAt)
+		advanceDigest(db, sub, due, now, &sentAt)
+	case errors.Is(err, errDigestNoRecipient):
+		finishDigestDelivery(db, delivery, models.DigestDeliverySkipped, err.Error(), content, msg.Subject, nil)
+		advanceDigest(db, sub, due, now, nil)
+	case delivery.Attempts >= digestMaxAttempts:
+		log.Printf("digest %s failed after %d attempts: %v", delivery.PublicID, delivery.Attempts, err)
+		finishDigestDelivery(db, delivery, models.DigestDeliveryFailed, err.Error(), content, msg.Subject, nil)
+		advanceDigest(db, sub, due, now, nil)
+	default:
+		log.Printf("digest %s attempt %d failed: %v", delivery.PublicID, delivery.Attempts, err)
+		db.Model(&models.DigestDelivery{}).Where("id = ?", delivery.ID).Update("last_error", err.Error())
+		retryAt := now.Add(time.Duration(delivery.Attempts) * 5 * time.Minute)
+		db.Model(&models.DigestSubscription{}).Where("id = ?", sub.ID).Update("lease_until", retryAt)
+	}
+}
+
+func finishDigestDelivery(db *gorm.DB, delivery models.DigestDelivery, status, reason string, content digestContent, subject string, sentAt *time.Time) {
+	ids, _ := json.Marshal(content.contentIDs())
+	if err := db.Model(&models.DigestDelivery{}).Where("id = ?", delivery.ID).Updates(map[string]any{
+		"status":      status,
+		"last_error":  reason,
+		"subject":     subject,
+		"story_count": len(content.Stories),
+		"pod_count":   len(content.Pods),
+		"content_ids": ids,
+		"sent_at":     sentAt,
+	}).Error; err != nil {
+		log.Printf("digest %s: failed to record %s: %v", delivery.PublicID, status, err)
+	}
+}
+
+// advanceDigest moves the subscription to its next slot and releases the
+// lease. A schedule the reader changed mid-send is left alone.
+func advanceDigest(db *gorm.DB, sub models.DigestSubscription, due, now time.Time, sentAt *time.Time) {
+	updates := map[string]any{
+		"lease_until": nil,
+		"next_due_at": gorm.Expr("CASE WHEN next_due_at = ? THEN ? ELSE next_due_at END", due, nextDigestDue(sub, now)),
+	}
+	if sentAt != nil {
+		updates["last_sent_at"] = *sentAt
+	}
+	if err := db.Model(&models.DigestSubscription{}).Where("id = ?", sub.ID).Updates(updates).Error; err != nil {
+		log.Printf("digest %d: failed to advance schedule: %v", sub.ID, err)
+	}
+}
@@ -0,0 +1,282 @@
+package controllers
+
+import (
+	"content-management-system/src/models"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestNextDigestDue(t *testing.T) {
+	riyadh := models.DigestSubscription{Cadence: models.DigestCadenceDaily, Timezone: "Asia/Riyadh", SendHour: 7}
+	// 03:30 UTC is 06:30 in Riyadh: today's 07:00 slot is still ahead.
+	after := time.Date(2026, 10, 16, 3, 30, 0, 0, time.UTC)
+	if got, want := nextDigestDue(riyadh, after), time.Date(2026, 10, 16, 4, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Fatalf("daily before slot = %v, want %v", got, want)
+	}
+	if got, want := nextDigestDue(riyadh, time.Date(2026, 10, 16, 4, 0, 0, 0, time.UTC)), time.Date(2026, 10, 17, 4, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Fatalf("daily at slot = %v, want %v", got, want)
+	}
+
+	weekly := models.DigestSubscription{Cadence: models.DigestCadenceWeekly, Timezone: "UTC", SendHour: 9, WeeklyDay: int(time.Sunday)}
+	// 2026-10-16 is a Friday.
+	if got, want := nextDigestDue(weekly, after), time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Fatalf("weekly = %v, want %v", got, want)
+	}
+	if got, want := nextDigestDue(weekly, time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)), time.Date(2026, 10, 25, 9, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Fatalf("weekly at slot = %v, want %v", got, want)
+	}
+
+	// Local wall-clock time is kept across a DST change.
+	berlin := models.DigestSubscription{Cadence: models.DigestCadenceDaily, Timezone: "Europe/Berlin", SendHour: 7}
+	if got, want := nextDigestDue(berlin, time.Date(2026, 10, 24, 6, 0, 0, 0, time.UTC)), time.Date(2026, 10, 25, 6, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Fatalf("dst = %v, want %v", got, want)
+	}
+
+	unknown := models.DigestSubscription{Cadence: models.DigestCadenceDaily, Timezone: "Mars/Olympus", SendHour: 7}
+	if got, want := nextDigestDue(unknown, after), time.Date(2026, 10, 16, 7, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Fatalf("unknown zone = %v, want %v", got, want)
+	}
+}
+
+func TestDigestPeriod(t *testing.T) {
+	due := time.Date(2026, 10, 15, 22, 0, 0, 0, time.UTC)
+	daily := models.DigestSubscription{Cadence: models.DigestCadenceDaily, Timezone: "Asia/Riyadh"}
+	key, since := digestPeriod(daily, due)
+	if key != "daily:2026-10-16" || !since.Equal(due.AddDate(0, 0, -1)) {
+		t.Fatalf("daily = %q %v", key, since)
+	}
+	weekly := models.DigestSubscription{Cadence: models.DigestCadenceWeekly, Timezone: "UTC"}
+	key, since = digestPeriod(weekly, due)
+	if key != "weekly:2026-W42" || !since.Equal(due.AddDate(0, 0, -7)) {
+		t.Fatalf("weekly = %q %v", key, since)
+	}
+}
+
+func digestTestContent() digestContent {
+	return digestContent{
+		Stories: []digestStory{{StoryID: uuid.New(), Title: "قمة الرياض <تنتهي>", Summary: "اتفاق على خطة", SourceName: "واس", MemberCount: 4, URL: "https://example.com/s"}},
+		Pods:    []digestPod{{ID: uuid.New(), Title: "حلقة الأسبوع", DurationSec: 610}},
+	}
+}
+
+func TestRenderDigest(t *testing.T) {
+	periodEnd := time.Date(2026, 10, 16, 7, 0, 0, 0, time.UTC)
+
+	subject, html, text, err := renderDigest("ar", models.DigestCadenceDaily, periodEnd, digestTestContent(), "https://cms.example/api/v1/digests/unsubscribe/tok")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject != "ملخصك اليومي — 2026-10-16" {
+		t.Fatalf("subject = %q", subject)
+	}
+	for _, want := range []string{`<html lang="ar" dir="rtl">`, "text-align:right", "أبرز الأخبار", "قمة الرياض &lt;تنتهي&gt;", "4 تقارير", "11 دقيقة", `href="https://cms.example/api/v1/digests/unsubscribe/tok"`} {
+		if !strings.Contains(html, want) {
+			t.Fatalf("html missing %q:\n%s", want, html)
+		}
+	}
+	for _, want := range []string{"- قمة الرياض <تنتهي> (واس)", "  https://example.com/s", "- حلقة الأسبوع · 11 دقيقة", "إلغاء الاشتراك: https://cms.example/api/v1/digests/unsubscribe/tok"} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("text missing %q:\n%s", want, text)
+		}
+	}
+
+	subject, html, _, err = renderDigest("en", models.DigestCadenceWeekly, periodEnd, digestContent{Pods: digestTestContent().Pods}, "u")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject != "Your weekly digest — 2026-10-16" || !strings.Contains(html, `dir="ltr"`) || strings.Contains(html, "Top stories") {
+		t.Fatalf("en digest = %q\n%s", subject, html)
+	}
+}
+
+func TestFileDigestSender(t *testing.T) {
+	dir := t.TempDir()
+	msg := DigestMessage{
+		ID: uuid.New(), To: "reader@example.com", Subject: "ملخصك اليومي",
+		HTML: "<p>مرحبا</p>", Text: "مرحبا", UnsubscribeURL: "https://cms.example/api/v1/digests/unsubscribe/tok",
+	}
+	if err := (fileDigestSender{Dir: dir}).Send(context.Background(), msg); err != nil {
+		t.Fatal(err)
+	}
+	raw, err := os.ReadFile(filepath.Join(dir, msg.ID.String()+".eml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	eml := string(raw)
+	for _, want := range []string{
+		"To: reader@example.com\r\n",
+		"Subject: =?utf-8?q?",
+		"List-Unsubscribe: <https://cms.example/api/v1/digests/unsubscribe/tok>\r\n",
+		"List-Unsubscribe-Post: List-Unsubscribe=One-Click\r\n",
+		"Content-Type: multipart/alternative; boundary=",
+		"Content-Type: text/plain; charset=utf-8",
+		"Content-Type: text/html; charset=utf-8",
+	} {
+		if !strings.Contains(eml, want) {
+			t.Fatalf("eml missing %q:\n%s", want, eml)
+		}
+	}
+
+	if err := (smtpDigestSender{Host: "localhost", Port: "25", From: "d@example.com"}).Send(context.Background(), DigestMessage{ID: uuid.New()}); err != errDigestNoRecipient {
+		t.Fatalf("smtp without address = %v", err)
+	}
+}
+
+func TestSMTPDigestSenderHonoursContext(t *testing.T) {
+	// A relay that accepts the connection but never greets.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		if conn, err := ln.Accept(); err == nil {
+			<-done
+			conn.Close()
+		}
+	}()
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	err = (smtpDigestSender{Host: host, Port: port, From: "d@example.com"}).Send(ctx, DigestMessage{ID: uuid.New(), To: "reader@example.com"})
+	if err == nil || time.Since(started) > 5*time.Second {
+		t.Fatalf("stalled relay: err=%v after %v", err, time.Since(started))
+	}
+}
+
+func TestDigestSenderFromEnv(t *testing.T) {
+	t.Setenv("DIGEST_SENDER", "")
+	if sender, err := digestSenderFromEnv(); sender != nil || err != nil {
+		t.Fatalf("unset = %v %v", sender, err)
+	}
+	t.Setenv("DIGEST_SENDER", "smtp")
+	t.Setenv("SMTP_HOST", "")
+	if _, err := digestSenderFromEnv(); err == nil {
+		t.Fatal("smtp without host must be rejected")
+	}
+	t.Setenv("DIGEST_SENDER", "file")
+	t.Setenv("DIGEST_FILE_DIR", t.TempDir())
+	if sender, err := digestSenderFromEnv(); err != nil || sender.Name() != "file" {
+		t.Fatalf("file = %v %v", sender, err)
+	}
+	t.Setenv("DIGEST_SENDER", "pigeon")
+	if _, err := digestSenderFromEnv(); err == nil {
+		t.Fatal("unknown sender must be rejected")
+	}
+}
+
+func TestUnsubscribeDigestPageOnlyAsks(t *testing.T) {
+	db, mock := newMockGorm(t)
+	mock.ExpectQuery(`SELECT \* FROM "digest_subscriptions" WHERE unsubscribe_token = \$1`).
+		WithArgs("tok", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "language"}).AddRow(7, "en"))
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Set("db", db)
+	c.Params = gin.Params{{Key: "token", Value: "tok"}}
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/digests/unsubscribe/tok", nil)
+	UnsubscribeDigestPage(c)
+
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `<form method="post">`) || !strings.Contains(w.Body.String(), "Stop receiving the digest?") {
+		t.Fatalf("page = %d %s", w.Code, w.Body.String())
+	}
+	// No UPDATE was expected: the GET must not unsubscribe.
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRenderDigestConfirmation(t *testing.T) {
+	url := digestConfirmURL("https://cms.example", "tok")
+	subject, html, text, err := renderDigestConfirmation("ar", url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject != digestLocales["ar"].ConfirmSubject || !strings.Contains(html, `dir="rtl"`) || !strings.Contains(html, `href="https://cms.example/api/v1/digests/confirm/tok"`) || !strings.Contains(text, url) {
+		t.Fatalf("confirmation = %q\n%s\n%s", subject, html, text)
+	}
+}
+
+type stubDigestSender struct {
+	err  error
+	sent []DigestMessage
+}
+
+func (s *stubDigestSender) Name() string { return "stub" }
+
+func (s *stubDigestSender) Send(_ context.Context, msg DigestMessage) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.sent = append(s.sent, msg)
+	return nil
+}
+
+func TestSendDigestConfirmationsRetriesFailedSend(t *testing.T) {
+	db, mock := newMockGorm(t)
+	claimSQL := `SELECT \* FROM "digest_subscriptions" WHERE .*email_confirm_retry_at IS NULL OR email_confirm_retry_at < \$1\) AND email_confirm_attempts < \$2\) ORDER BY id,.* LIMIT \$3 FOR UPDATE SKIP LOCKED`
+	leaseSQL := regexp.QuoteMeta(`UPDATE "digest_subscriptions" SET "email_confirm_attempts"=email_confirm_attempts + 1,"email_confirm_retry_at"=$1,"updated_at"=$2 WHERE id = $3`)
+	pending := func(attempts int) *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "tenant_id", "user_id", "language", "email", "email_confirm_token", "email_confirm_attempts"}).
+			AddRow(7, "default", uuid.New(), "en", "reader@example.com", "tok", attempts)
+	}
+	noneLeft := func() {
+		mock.ExpectBegin()
+		mock.ExpectQuery(claimSQL).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectRollback()
+	}
+
+	// The first send fails: the claim only backs off, it never marks the link sent.
+	mock.ExpectBegin()
+	mock.ExpectQuery(claimSQL).WillReturnRows(pending(0))
+	mock.ExpectExec(leaseSQL).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "digest_subscriptions" SET "email_confirm_retry_at"=$1,"updated_at"=$2 WHERE id = $3`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	noneLeft()
+	failing := &stubDigestSender{err: errors.New("smtp: 451 try again later")}
+	sendDigestConfirmations(context.Background(), db, failing)
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("failed send: %v", err)
+	}
+
+	// Once the backoff lapses the same subscription is claimed and mailed.
+	mock.ExpectBegin()
+	mock.ExpectQuery(claimSQL).WillReturnRows(pending(1))
+	mock.ExpectExec(leaseSQL).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "digest_subscriptions" SET "email_confirm_retry_at"=$1,"email_confirm_sent_at"=$2,"updated_at"=$3 WHERE id = $4`)).
+		WithArgs(nil, sqlmock.AnyArg(), sqlmock.AnyArg(), 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	noneLeft()
+	working := &stubDigestSender{}
+	sendDigestConfirmations(context.Background(), db, working)
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	if len(working.sent) != 1 || working.sent[0].Kind != digestKindEmailConfirmation || working.sent[0].To != "reader@example.com" {
+		t.Fatalf("retry sent %+v", working.sent)
+	}
+}
@@ -0,0 +1,180 @@
+package controllers
+
+import (
+	"content-management-system/src/models"
+	"content-management-system/src/richtext"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ─── Content rendering for Pages and Posts ───────────────────
+//
+// content is the editor's source; content_html/excerpt are what readers get.
+// Media referenced from the content (media:<id>, data-media-id or a pasted
+// media URL) is resolved against the media table in one query.
+
+// editorialRendering is the outcome of rendering a working copy.
+type editorialRendering struct {
+	Warnings []richtext.Warning
+	// Media is the referenced media, in order of first reference.
+	Media []models.Media
+}
+
+// tenantMedia scopes a media query to tenantID's rows plus the pre-tenancy
+// shared library (tenant_id IS NULL).
+func tenantMedia(db *gorm.DB, tenantID string) *gorm.DB {
+	return db.Model(&models.Media{}).Where("(media.tenant_id = ? OR media.tenant_id IS NULL)", tenantID)
+}
+
+// renderEditorialContent renders content in format into state and returns
+// the referenced media and sanitizer warnings. Media references resolve only
+// within state's tenant.
+func renderEditorialContent(db *gorm.DB, state *models.EditorialState, content string, format richtext.Format) (editorialRendering, error) {
+	found := map[uuid.UUID]models.Media{}
+	resolve := func(ids []uuid.UUID, urls []string) (richtext.MediaLookup, error) {
+		lookup := richtext.MediaLookup{ByID: map[uuid.UUID]string{}, ByURL: map[string]uuid.UUID{}}
+		var media []models.Media
+		q := tenantMedia(db, state.TenantID)
+		switch {
+		case len(ids) > 0 && len(urls) > 0:
+			q = q.Where("public_id IN ? OR url IN ?", ids, urls)
+		case len(ids) > 0:
+			q = q.Where("public_id IN ?", ids)
+		default:
+			q = q.Where("url IN ?", urls)
+		}
+		if err := q.Find(&media).Error; err != nil {
+			return lookup, err
+		}
+		for _, m := range media {
+			found[m.PublicID] = m
+			lookup.ByID[m.PublicID] = m.URL
+			lookup.ByURL[m.URL] = m.PublicID
+		}
+		return lookup, nil
+	}
+
+	doc, err := richtext.Render(content, richtext.Options{
+		Format:  format,
+		Policy:  richtext.PolicyFromEnv(),
+		Resolve: resolve,
+	})
+	if err != nil {
+		return editorialRendering{}, err
+	}
+	state.ContentFormat = string(doc.Format)
+	state.ContentHTML = doc.HTML
+	state.Excerpt = doc.Excerpt
+
+	out := editorialRendering{Warnings: doc.Warnings}
+	for _, id := range doc.MediaIDs {
+		out.Media = append(out.Media, found[id])
+	}
+	return out, nil
+}
+
+// parseEditorialFormat resolves the request's content_format, falling back to
+// current (the stored format) and then to Markdown.
+func parseEditorialFormat(raw *string, current string) (richtext.Format, error) {
+	value := current
+	if raw != nil {
+		value = *raw
+	}
+	format, ok := richtext.ParseFormat(value)
+	if !ok {
+		return "", fmt.Errorf("content_format must be markdown or html, got %q", value)
+	}
+	return format, nil
+}
+
+// editorialRenderError maps a render failure to a client message, or ""
+// when it is a server error.
+func editorialRenderError(err error) string {
+	if errors.Is(err, richtext.ErrTooLarge) {
+		return err.Error()
+	}
+	return ""
+}
+
+// mergeEditorialMedia appends referenced media not already attached.
+func mergeEditorialMedia(attached, referenced []models.Media) []models.Media {
+	out := make([]models.Media, 0, len(attached)+len(referenced))
+	seen := map[uuid.UUID]bool{}
+	for _, list := range [][]models.Media{attached, referenced} {
+		for _, m := range list {
+			if !seen[m.PublicID] {
+				seen[m.PublicID] = true
+				out = append(out, m)
+			}
+		}
+	}
+	return out
+}
+
+func mediaPublicIDs(media []models.Media) []string {
+	ids := make([]string, 0, len(media))
+	for _, m := range media {
+		ids = append(ids, m.PublicID.String())
+	}
+	return ids
+}
+
+// syncPostMedia points post_media at the post's attached media plus the media
+// its content references, reporting whether the links changed. On restore the
+// revision's links minus its content references become the attached set.
+func syncPostMedia(tx *gorm.DB, post *models.Post, referenced []models.Media, restored *models.EditorialRevision) (bool, error) {
+	if restored != nil {
+		inContent := map[string]bool{}
+		for _, m := range referenced {
+			inContent[m.PublicID.String()] = true
+		}
+		post.AttachedMediaIDs = nil
+		for _, id := range restored.MediaIDs {
+			if !inContent[id] {
+				post.AttachedMediaIDs = append(post.AttachedMediaIDs, id)
+			}
+		}
+	}
+	var attached []models.Media
+	if len(post.AttachedMediaIDs) > 0 {
+		// Attached media deleted since is skipped rather than failing the save.
+		var found []models.Media
+		if err := tenantMedia(tx, post.TenantID).Where("public_id = ANY(?::uuid[])", post.AttachedMediaIDs).Find(&found).Error; err != nil {
+			return false, err
+		}
+		byID := make(map[string]models.Media, len(found))
+		for _, m := range found {
+			byID[m.PublicID.String()] = m
+		}
+		for _, id := range post.AttachedMediaIDs {
+			if m, ok := byID[id]; ok {
+				attached = append(attached, m)
+			}
+		}
+	}
+	media := mergeEditorialMedia(attached, referenced)
+	if sameMediaOrder(post.Media, media) {
+		return false, nil
+	}
+	if err := tx.Model(post).Association("Media").Replace(media); err != nil {
+		return false, err
+	}
+	post.Media = media
+	return true, nil
+}
+
+// publicEditorialHTML returns the stored rendering, sanitizing rows saved
+// before rendering existed (always HTML) on the fly.
+func publicEditorialHTML(rendered, excerpt *string, source string) (string, string) {
+	if rendered != nil && (*rendered != "" || source == "") {
+		return *rendered, derefStr(excerpt)
+	}
+	doc, err := richtext.Render(source, richtext.Options{Format: richtext.FormatHTML, Policy: richtext.PolicyFromEnv()})
+	if err != nil {
+		return "", ""
+	}
+	return doc.HTML, doc.Excerpt
+}
@@ -0,0 +1,973 @@
+package controllers
+
+import (
+	"content-management-system/src/models"
+	"content-management-system/src/richtext"
+	"content-management-system/src/utils"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ─── Editorial workflow shared by Pages and Posts ────────────
+//
+// The row holds the working copy and lifecycle state; every content change
+// appends an immutable editorial_revisions row. Public readers only see the
+// revision named by published_revision (see migrations/20260825100000_editorial_pages_posts.sql).
+
+type editorialKind struct {
+	entity string
+	table  string
+	label  string
+	newDoc func() models.EditorialDocument
+	// syncMedia maintains the post_media links after the content was
+	// rendered (posts only); restored is set when a revision was restored.
+	syncMedia func(tx *gorm.DB, doc models.EditorialDocument, referenced []models.Media, restored *models.EditorialRevision) (bool, error)
+}
+
+var pageEditorial = editorialKind{
+	entity: models.EditorialEntityPage,
+	table:  "pages",
+	label:  "Page",
+	newDoc: func() models.EditorialDocument { return &models.Page{} },
+}
+
+var postEditorial = editorialKind{
+	entity: models.EditorialEntityPost,
+	table:  "posts",
+	label:  "Post",
+	newDoc: func() models.EditorialDocument { return &models.Post{} },
+	syncMedia: func(tx *gorm.DB, doc models.EditorialDocument, referenced []models.Media, restored *models.EditorialRevision) (bool, error) {
+		return syncPostMedia(tx, doc.(*models.Post), referenced, restored)
+	},
+}
+
+var (
+	errEditorialSlugTaken   = errors.New("slug is already in use")
+	errEditorialSlugInvalid = errors.New("slug must contain letters or digits")
+)
+
+// editorialWriteRequest is the create/update body for pages and posts. On
+// update every field is optional; nil leaves the working copy unchanged.
+type editorialWriteRequest struct {
+	Title   *string `json:"title"`
+	Content *string `json:"content"`
+	// ContentFormat is markdown (default) or html.
+	ContentFormat *string  `json:"content_format"`
+	Slug          *string  `json:"slug"`
+	Author        *string  `json:"author"`
+	MediaIDs      []string `json:"media_ids"`
+	// Media is the legacy [{"id": "<media id>"}] shape, still accepted.
+	Media []struct {
+		ID string `json:"id"`
+	} `json:"media"`
+	// Status on create: draft or published; omitted means published.
+	Status      string     `json:"status"`
+	PublishAt   *time.Time `json:"publish_at"`
+	UnpublishAt *time.Time `json:"unpublish_at"`
+	Note        *string    `json:"note"`
+}
+
+// mediaIDs merges media_ids and the legacy media list; ok is false when
+// neither was sent.
+func (r editorialWriteRequest) mediaIDs() (ids []string, ok bool) {
+	if r.MediaIDs == nil && r.Media == nil {
+		return nil, false
+	}
+	ids = append(ids, r.MediaIDs...)
+	for _, m := range r.Media {
+		ids = append(ids, m.ID)
+	}
+	return ids, true
+}
+
+type editorialScheduleRequest struct {
+	// Revision defaults to the current (newest) revision.
+	Revision    *int       `json:"revision"`
+	PublishAt   *time.Time `json:"publish_at"`
+	UnpublishAt *time.Time `json:"unpublish_at"`
+}
+
+// ─── State transitions (pure) ────────────────────────────────
+
+// applyEditorialPublish publishes revision now, or schedules it when
+// publishAt is in the future. A live document stays live on its current
+// revision until a scheduled one replaces it.
+func applyEditorialPublish(state *models.EditorialState, revision int, publishAt, unpublishAt *time.Time, now time.Time) error {
+	if revision < 1 || revision > state.CurrentRevision {
+		return fmt.Errorf("revision %d does not exist", revision)
+	}
+	goLive := now
+	if publishAt != nil && publishAt.After(now) {
+		goLive = *publishAt
+	}
+	if unpublishAt != nil && !unpublishAt.After(goLive) {
+		return errors.New("unpublish_at must be after the publish time")
+	}
+	state.UnpublishAt = unpublishAt
+	state.ArchivedAt = nil
+	if goLive.After(now) {
+		if state.Status != models.EditorialPublished {
+			state.Status = models.EditorialScheduled
+		}
+		state.ScheduledRevision = &revision
+		state.PublishAt = &goLive
+		return nil
+	}
+	state.Status = models.EditorialPublished
+	state.PublishedRevision = &revision
+	state.ScheduledRevision = nil
+	state.PublishAt = nil
+	state.PublishedAt = &now
+	return nil
+}
+
+// applyEditorialUnpublish takes a document offline now, or at a future
+// unpublishAt when it is currently live.
+func applyEditorialUnpublish(state *models.EditorialState, unpublishAt *time.Time, now time.Time) error {
+	if state.Status != models.EditorialPublished && state.Status != models.EditorialScheduled {
+		return fmt.Errorf("cannot unpublish a %s document", state.Status)
+	}
+	if unpublishAt != nil && unpublishAt.After(now) {
+		if state.Status != models.EditorialPublished {
+			return errors.New("only a published document can be scheduled to unpublish")
+		}
+		state.UnpublishAt = unpublishAt
+		return nil
+	}
+	state.Status = models.EditorialDraft
+	state.PublishedRevision = nil
+	state.ScheduledRevision = nil
+	state.PublishAt = nil
+	state.UnpublishAt = nil
+	return nil
+}
+
+// applyEditorialArchive retires a document from every state; publishing
+// again revives it.
+func applyEditorialArchive(state *models.EditorialState, now time.Time) error {
+	if state.Status == models.EditorialArchived {
+		return errors.New("document is already archived")
+	}
+	state.Status = models.EditorialArchived
+	state.PublishedRevision = nil
+	state.ScheduledRevision = nil
+	state.PublishAt = nil
+	state.UnpublishAt = nil
+	state.ArchivedAt = &now
+	return nil
+}
+
+// ─── Persistence helpers ─────────────────────────────────────
+
+// claimEditorialSlug resolves a row's slug. An explicit slug must be free in
+// the tenant; a title-derived one gets -2, -3… until it is. selfID excludes
+// the row being updated.
+func claimEditorialSlug(tx *gorm.DB, kind editorialKind, tenantID, requested, title string, selfID uint) (string, error) {
+	explicit := strings.TrimSpace(requested) != ""
+	base := editorialSlug(requested)
+	if !explicit {
+		base = editorialSlug(title)
+	}
+	if base == "" {
+		if explicit {
+			return "", errEditorialSlugInvalid
+		}
+		base = kind.entity
+	}
+	// Slugs only hold letters, digits and '-', so LIKE needs no escaping.
+	var existing []string
+	if err := tx.Table(kind.table).
+		Where("tenant_id = ? AND id <> ? AND (slug = ? OR slug LIKE ?)", tenantID, selfID, base, base+"-%").
+		Pluck("slug", &existing).Error; err != nil {
+		return "", err
+	}
+	taken := make(map[string]bool, len(existing))
+	for _, s := range existing {
+		taken[s] = true
+	}
+	if explicit && taken[base] {
+		return "", errEditorialSlugTaken
+	}
+	return uniqueEditorialSlug(base, taken), nil
+}
+
+// insertEditorialRevision snapshots doc as revision CurrentRevision; callers
+// bump CurrentRevision first.
+func insertEditorialRevision(tx *gorm.DB, doc models.EditorialDocument, createdBy string, note *string) (models.EditorialRevision, error) {
+	rev := doc.Snapshot()
+	rev.Revision = doc.Editorial().CurrentRevision
+	rev.Note = note
+	if createdBy != "" {
+		rev.CreatedBy = &createdBy
+	}
+	err := tx.Create(&rev).Error
+	return rev, err
+}
+
+func lockEditorialDocument(tx *gorm.DB, kind editorialKind, tenantID string, id uuid.UUID) (models.EditorialDocument, error) {
+	doc := kind.newDoc()
+	q := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("tenant_id = ? AND public_id = ?", tenantID, id)
+	if kind.syncMedia != nil {
+		q = q.Preload("Media")
+	}
+	if err := q.First(doc).Error; err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+func findEditorialRevision(db *gorm.DB, kind editorialKind, doc models.EditorialDocument, revision int) (models.EditorialRevision, error) {
+	var rev models.EditorialRevision
+	err := db.Where("entity_type = ? AND entity_id = ? AND revision = ?", kind.entity, doc.EditorialRowID(), revision).
+		First(&rev).Error
+	return rev, err
+}
+
+// findMediaByPublicIDs resolves the tenant's media public IDs in order.
+// clientErr names the first unknown, foreign or malformed ID; err is a
+// database failure.
+func findMediaByPublicIDs(db *gorm.DB, tenantID string, ids []string) (media []models.Media, clientErr string, err error) {
+	if len(ids) == 0 {
+		return []models.Media{}, "", nil
+	}
+	parsed := make([]uuid.UUID, 0, len(ids))
+	seen := map[uuid.UUID]bool{}
+	for _, raw := range ids {
+		id, err := uuid.Parse(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Sprintf("Invalid media IDs: invalid media id %q", raw), nil
+		}
+		if !seen[id] {
+			seen[id] = true
+			parsed = append(parsed, id)
+		}
+	}
+	var found []models.Media
+	if err := tenantMedia(db, tenantID).Where("public_id IN ?", parsed).Find(&found).Error; err != nil {
+		return nil, "", err
+	}
+	byID := make(map[uuid.UUID]models.Media, len(found))
+	for _, m := range found {
+		byID[m.PublicID] = m
+	}
+	out := make([]models.Media, 0, len(parsed))
+	for _, id := range parsed {
+		m, ok := byID[id]
+		if !ok {
+			return nil, fmt.Sprintf("Invalid media IDs: media %s not found", id), nil
+		}
+		out = append(out, m)
+	}
+	return out, "", nil
+}
+
+func editorialError(c *gin.Context, status int, message string) {
+	c.JSON(status, utils.HTTPError{Code: status, Message: message})
+}
+
+// editorialServerError logs err and answers 500 with message alone; driver
+// and SQL details never reach the client.
+func editorialServerError(c *gin.Context, message string, err error) {
+	log.Printf("editorial: %s: %v", message, err)
+	editorialError(c, http.StatusInternalServerError, message)
+}
+
+// editorialLookupError answers 404 when the row does not exist and 500 for
+// any other failure (a lock timeout, a lost connection).
+func editorialLookupError(c *gin.Context, what string, err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		editorialError(c, http.StatusNotFound, what+" not found")
+		return
+	}
+	editorialServerError(c, "Failed to load "+strings.ToLower(what), err)
+}
+
+// editorialUniqueViolation reports a Postgres unique_violation (SQLSTATE
+// 23505), which for pages and posts means a concurrent writer took the slug.
+func editorialUniqueViolation(err error) bool {
+	var state interface{ SQLState() string }
+	return errors.As(err, &state) && state.SQLState() == "23505"
+}
+
+func parseEditorialID(c *gin.Context, kind editorialKind) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		editorialError(c, http.StatusBadRequest, "Invalid "+strings.ToLower(kind.label)+" ID")
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// ─── Create / update / delete ────────────────────────────────
+
+// createEditorialDocument stores a new row at revision 1, publishing or
+// scheduling it when the request asks to. media is the explicitly attached
+// media for posts; media referenced from the content is linked as well.
+func createEditorialDocument(c *gin.Context, kind editorialKind, doc models.EditorialDocument, req editorialWriteRequest, principal utils.AdminPrincipal, media []models.Media) {
+	db := c.MustGet("db").(*gorm.DB)
+	state := doc.Editorial()
+	format, err := parseEditorialFormat(req.ContentFormat, "")
+	if err != nil {
+		editorialError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	state.TenantID = principal.TenantID
+	rendering, err := renderEditorialContent(db, state, doc.Snapshot().Content, format)
+	if err != nil {
+		if msg := editorialRenderError(err); msg != "" {
+			editorialError(c, http.StatusBadRequest, msg)
+		} else {
+			editorialServerError(c, "Failed to render content", err)
+		}
+		return
+	}
+	if kind.syncMedia != nil {
+		media = mergeEditorialMedia(media, rendering.Media)
+	}
+	state.Status = models.EditorialDraft
+	state.CurrentRevision = 1
+	if principal.UserID != "" {
+		userID := principal.UserID
+		state.AuthorID = &userID
+	}
+	// POST /pages and /posts have always published immediately; clients opt
+	// into drafts with status=draft.
+	status := req.Status
+	if status == "" {
+		status = string(models.EditorialPublished)
+	}
+	switch status {
+	case string(models.EditorialDraft):
+		if req.PublishAt != nil {
+			editorialError(c, http.StatusBadRequest, "publish_at requires status published")
+			return
+		}
+	case string(models.EditorialPublished):
+		if err := applyEditorialPublish(state, 1, req.PublishAt, req.UnpublishAt, time.Now().UTC()); err != nil {
+			editorialError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	default:
+		editorialError(c, http.StatusBadRequest, "status must be draft or published")
+		return
+	}
+
+	tx := db.Begin()
+	slug, err := claimEditorialSlug(tx, kind, principal.TenantID, derefStr(req.Slug), derefStr(req.Title), 0)
+	if err != nil {
+		tx.Rollback()
+		switch {
+		case errors.Is(err, errEditorialSlugTaken):
+			editorialError(c, http.StatusConflict, err.Error())
+		case errors.Is(err, errEditorialSlugInvalid):
+			editorialError(c, http.StatusBadRequest, err.Error())
+		default:
+			editorialServerError(c, "Failed to create "+strings.ToLower(kind.label), err)
+		}
+		return
+	}
+	state.Slug = slug
+
+	if err := tx.Omit(clause.Associations).Create(doc).Error; err != nil {
+		tx.Rollback()
+		if editorialUniqueViolation(err) {
+			editorialError(c, http.StatusConflict, errEditorialSlugTaken.Error())
+			return
+		}
+		editorialServerError(c, "Failed to create "+strings.ToLower(kind.label), err)
+		return
+	}
+	if len(media) > 0 {
+		if err := tx.Model(doc).Association("Media").Replace(media); err != nil {
+			tx.Rollback()
+			editorialServerError(c, "Failed to attach media", err)
+			return
+		}
+	}
+	if _, err := insertEditorialRevision(tx, doc, principal.UserID, req.Note); err != nil {
+		tx.Rollback()
+		editorialServerError(c, "Failed to record revision", err)
+		return
+	}
+	if err := tx.Commit().Error; err != nil {
+		editorialServerError(c, "Failed to create "+strings.ToLower(kind.label), err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.ResponseMessage{
+		Data:     doc,
+		Code:     http.StatusCreated,
+		Message:  kind.label + " created successfully",
+		Warnings: rendering.Warnings,
+	})
+}
+
+// editorialApplyFunc copies request fields onto the locked working copy and
+// reports whether the content changed (which appends a revision).
+type editorialApplyFunc func(tx *gorm.DB, doc models.EditorialDocument, req editorialWriteRequest) (changed bool, clientErr string, err error)
+
+// updateEditorialDocument edits the working copy. Content changes append a
+// revision; the published revision is untouched until the next publish.
+func updateEditorialDocument(c *gin.Context, kind editorialKind, apply editorialApplyFunc) {
+	principal, ok := requireAdminPrincipal(c)
+	if !ok {
+		return
+	}
+	id, ok := parseEditorialID(c, kind)
+	if !ok {
+		return
+	}
+	var req editorialWriteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		editorialError(c, http.StatusBadRequest, "Invalid "+strings.ToLower(kind.label)+" data")
+		return
+	}
+	if req.Status != "" || req.PublishAt != nil || req.UnpublishAt != nil {
+		editorialError(c, http.StatusBadRequest, "Use the publish, unpublish and archive endpoints to change status")
+		return
+	}
+
+	db := c.MustGet("db").(*gorm.DB)
+	tx := db.Begin()
+	doc, err := lockEditorialDocument(tx, kind, principal.TenantID, id)
+	if err != nil {
+		tx.Rollback()
+		editorialLookupError(c, kind.label, err)
+		return
+	}
+	state := doc.Editorial()
+
+	changed, clientErr, err := apply(tx, doc, req)
+	if clientErr != "" || err != nil {
+		tx.Rollback()
+		if clientErr != "" {
+			editorialError(c, http.StatusBadRequest, clientErr)
+		} else {
+			editorialServerError(c, "Failed to update "+strings.ToLower(kind.label), err)
+		}
+		return
+	}
+	if req.Slug != nil && strings.TrimSpace(*req.Slug) != "" {
+		slug, err := claimEditorialSlug(tx, kind, principal.TenantID, *req.Slug, "", doc.EditorialRowID())
+		if err != nil {
+			tx.Rollback()
+			switch {
+			case errors.Is(err, errEditorialSlugTaken):
+				editorialError(c, http.StatusConflict, err.Error())
+			case errors.Is(err, errEditorialSlugInvalid):
+				editorialError(c, http.StatusBadRequest, err.Error())
+			default:
+				editorialServerError(c, "Failed to update "+strings.ToLower(kind.label), err)
+			}
+			return
+		}
+		state.Slug = slug
+	}
+
+	// Re-render when the source, its format or (for posts) the attached
+	// media changed, so content_html and post_media stay in step.
+	var rendering editorialRendering
+	_, mediaSent := req.mediaIDs()
+	if req.Content != nil || req.ContentFormat != nil || (mediaSent && kind.syncMedia != nil) {
+		format, err := parseEditorialFormat(req.ContentFormat, state.ContentFormat)
+		if err != nil {
+			tx.Rollback()
+			editorialError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		changed = changed || string(format) != state.ContentFormat
+		rendering, err = renderEditorialContent(tx, state, doc.Snapshot().Content, format)
+		if err == nil && kind.syncMedia != nil {
+			var relinked bool
+			relinked, err = kind.syncMedia(tx, doc, rendering.Media, nil)
+			changed = changed || relinked
+		}
+		if err != nil {
+			tx.Rollback()
+			if msg := editorialRenderError(err); msg != "" {
+				editorialError(c, http.StatusBadRequest, msg)
+			} else {
+				editorialServerError(c, "Failed to update "+strings.ToLower(kind.label), err)
+			}
+			return
+		}
+	}
+	if changed {
+		state.CurrentRevision++
+	}
+	if err := tx.Omit(clause.Associations).Save(doc).Error; err != nil {
+		tx.Rollback()
+		if editorialUniqueViolation(err) {
+			editorialError(c, http.StatusConflict, errEditorialSlugTaken.Error())
+			return
+		}
+		editorialServerError(c, "Failed to update "+strings.ToLower(kind.label), err)
+		return
+	}
+	if changed {
+		if _, err := insertEditorialRevision(tx, doc, principal.UserID, req.Note); err != nil {
+			tx.Rollback()
+			editorialServerError(c, "Failed to record revision", err)
+			return
+		}
+	}
+	if err := tx.Commit().Error; err != nil {
+		editorialServerError(c, "Failed to update "+strings.ToLower(kind.label), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.ResponseMessage{
+		Data:     doc,
+		Code:     http.StatusOK,
+		Message:  kind.label + " updated successfully",
+		Warnings: rendering.Warnings,
+	})
+}
+
+// deleteEditorialDocument removes the row and its revision history.
+func deleteEditorialDocument(c *gin.Context, kind editorialKind) (models.EditorialDocument, bool) {
+	principal, ok := requireAdminPrincipal(c)
+	if !ok {
+		return nil, false
+	}
+	id, ok := parseEditorialID(c, kind)
+	if !ok {
+		return nil, false
+	}
+	db := c.MustGet("db").(*gorm.DB)
+	tx := db.Begin()
+	doc, err := lockEditorialDocument(tx, kind, principal.TenantID, id)
+	if err != nil {
+		tx.Rollback()
+		editorialLookupError(c, kind.label, err)
+		return nil, false
+	}
+	if err := tx.Where("entity_type = ? AND entity_id = ?", kind.entity, doc.EditorialRowID()).
+		Delete(&models.EditorialRevision{}).Error; err != nil {
+		tx.Rollback()
+		editorialServerError(c, "Failed to delete "+strings.ToLower(kind.label), err)
+		return nil, false
+	}
+	if err := tx.Delete(doc).Error; err != nil {
+		tx.Rollback()
+		editorialServerError(c, "Failed to delete "+strings.ToLower(kind.label), err)
+		return nil, false
+	}
+	if err := tx.Commit().Error; err != nil {
+		editorialServerError(c, "Failed to delete "+strings.ToLower(kind.label), err)
+		return nil, false
+	}
+	return doc, true
+}
+
+// ─── Admin reads ─────────────────────────────────────────────
+
+// listEditorialDocuments lists every row in the admin's tenant regardless of
+// status (filter with status=eq:draft etc.).
+func listEditorialDocuments(c *gin.Context, kind editorialKind, cfg utils.QueryConfig, dest interface{}) {
+	principal, ok := requireAdminPrincipal(c)
+	if !ok {
+		return
+	}
+	db := c.MustGet("db").(*gorm.DB)
+	params, err := utils.ParseQueryParams(c, cfg)
+	if err != nil {
+		editorialError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	query := db.Model(kind.newDoc()).Where(kind.table+".tenant_id = ?", principal.TenantID)
+	if kind.syncMedia != nil {
+		query = query.Preload("Media")
+	}
+	query = utils.ApplyQuery(query, params, cfg)
+	meta, err := utils.FetchWithPagination(query, params, dest)
+	if err != nil {
+		editorialServerError(c, "Failed to fetch "+kind.table, err)
+		return
+	}
+	c.JSON(http.StatusOK, utils.ResponseMessage{
+		Data:    dest,
+		Meta:    meta,
+		Links:   utils.BuildPaginationLinks(c, meta),
+		Code:    http.StatusOK,
+		Message: kind.label + "s fetched successfully",
+	})
+}
+
+// loadEditorialDocument reads one row of the admin's tenant (no lock).
+func loadEditorialDocument(c *gin.Context, kind editorialKind) (models.EditorialDocument, bool) {
+	principal, ok := requireAdminPrincipal(c)
+	if !ok {
+		return nil, false
+	}
+	id, ok := parseEditorialID(c, kind)
+	if !ok {
+		return nil, false
+	}
+	db := c.MustGet("db").(*gorm.DB)
+	doc := kind.newDoc()
+	q := db.Where("tenant_id = ? AND public_id = ?", principal.TenantID, id)
+	if kind.syncMedia != nil {
+		q = q.Preload("Media")
+	}
+	if err := q.First(doc).Error; err != nil {
+		editorialLookupError(c, kind.label, err)
+		return nil, false
+	}
+	return doc, true
+}
+
+func getEditorialDocument(c *gin.Context, kind editorialKind) {
+	doc, ok := loadEditorialDocument(c, kind)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, doc)
+}
+
+type editorialRevisionSummary struct {
+	Revision      int       `json:"revision"`
+	Title         string    `json:"title"`
+	Author        *string   `json:"author,omitempty"`
+	Note          *string   `json:"note,omitempty"`
+	CreatedBy     *string   `json:"created_by,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	ContentLength int       `json:"content_length"`
+	Published     bool      `json:"published"`
+	Scheduled     bool      `json:"scheduled"`
+}
+
+type editorialRevisionListResponse struct {
+	Data              []editorialRevisionSummary `json:"data"`
+	CurrentRevision   int                        `json:"current_revision"`
+	PublishedRevision *int                       `json:"published_revision,omitempty"`
+	ScheduledRevision *int                       `json:"scheduled_revision,omitempty"`
+	Page              int                        `json:"page"`
+	Limit             int                        `json:"limit"`
+}
+
+func listEditorialRevisions(c *gin.Context, kind editorialKind) {
+	doc, ok := loadEditorialDocument(c, kind)
+	if !ok {
+		return
+	}
+	db := c.MustGet("db").(*gorm.DB)
+	limit, page := paginationParams(c, 50, 200)
+	var rows []editorialRevisionSummary
+	if err := db.Model(&models.EditorialRevision{}).
+		Select("revision, title, author, note, created_by, created_at, char_length(content) AS content_length").
+		Where("entity_type = ? AND entity_id = ?", kind.entity, doc.EditorialRowID()).
+		Order("revision DESC").
+		Limit(limit).Offset((page - 1) * limit).
+		Scan(&rows).Error; err != nil {
+		editorialServerError(c, "Failed to fetch revisions", err)
+		return
+	}
+	state := doc.Editorial()
+	for i := range rows {
+		rows[i].Published = state.PublishedRevision != nil && *state.PublishedRevision == rows[i].Revision
+		rows[i].Scheduled = state.ScheduledRevision != nil && *state.ScheduledRevision == rows[i].Revision
+	}
+	if rows == nil {
+		rows = []editorialRevisionSummary{}
+	}
+	c.JSON(http.StatusOK, editorialRevisionListResponse{
+		Data:              rows,
+		CurrentRevision:   state.CurrentRevision,
+		PublishedRevision: state.PublishedRevision,
+		ScheduledRevision: state.ScheduledRevision,
+		Page:              page,
+		Limit:             limit,
+	})
+}
+
+func parseRevisionNumber(raw string) (int, bool) {
+	n, err := strconv.Atoi(strings.TrimSpace(raw))
+	return n, err == nil && n >= 0
+}
+
+func getEditorialRevision(c *gin.Context, kind editorialKind) {
+	doc, ok := loadEditorialDocument(c, kind)
+	if !ok {
+		return
+	}
+	n, ok := parseRevisionNumber(c.Param("revision"))
+	if !ok || n == 0 {
+		editorialError(c, http.StatusBadRequest, "Invalid revision")
+		return
+	}
+	rev, err := findEditorialRevision(c.MustGet("db").(*gorm.DB), kind, doc, n)
+	if err != nil {
+		editorialLookupError(c, "Revision", err)
+		return
+	}
+	c.JSON(http.StatusOK, rev)
+}
+
+type editorialFieldChange struct {
+	From *string `json:"from"`
+	To   *string `json:"to"`
+}
+
+type editorialDiffResponse struct {
+	From         int                   `json:"from"`
+	To           int                   `json:"to"`
+	Title        *editorialFieldChange `json:"title,omitempty"`
+	Author       *editorialFieldChange `json:"author,omitempty"`
+	MediaAdded   []string              `json:"media_added,omitempty"`
+	MediaRemoved []string              `json:"media_removed,omitempty"`
+	Content      []editorialDiffLine   `json:"content"`
+	Insertions   int                   `json:"insertions"`
+	Deletions    int                   `json:"deletions"`
+}
+
+// buildEditorialDiff compares two revisions; a zero-valued from (revision 0)
+// diffs against an empty document.
+func buildEditorialDiff(from, to models.EditorialRevision) editorialDiffResponse {
+	out := editorialDiffResponse{From: from.Revision, To: to.Revision}
+	if from.Title != to.Title {
+		fromTitle, toTitle := from.Title, to.Title
+		out.Title = &editorialFieldChange{From: &fromTitle, To: &toTitle}
+	}
+	if derefStr(from.Author) != derefStr(to.Author) {
+		out.Author = &editorialFieldChange{From: from.Author, To: to.Author}
+	}
+	before := map[string]bool{}
+	for _, id := range from.MediaIDs {
+		before[id] = true
+	}
+	after := map[string]bool{}
+	for _, id := range to.MediaIDs {
+		after[id] = true
+		if !before[id] {
+			out.MediaAdded = append(out.MediaAdded, id)
+		}
+	}
+	for _, id := range from.MediaIDs {
+		if !after[id] {
+			out.MediaRemoved = append(out.MediaRemoved, id)
+		}
+	}
+	out.Content = diffEditorialLines(from.Content, to.Content)
+	for _, line := range out.Content {
+		switch line.Op {
+		case "insert":
+			out.Insertions++
+		case "delete":
+			out.Deletions++
+		}
+	}
+	return out
+}
+
+// diffEditorialRevisions handles GET …/:id/diff?from=&to=. to defaults to the
+// current revision; from defaults to the published revision (or to-1).
+func diffEditorialRevisions(c *gin.Context, kind editorialKind) {
+	doc, ok := loadEditorialDocument(c, kind)
+	if !ok {
+		return
+	}
+	state := doc.Editorial()
+	to := state.CurrentRevision
+	if raw := c.Query("to"); raw != "" {
+		n, ok := parseRevisionNumber(raw)
+		if !ok || n == 0 {
+			editorialError(c, http.StatusBadRequest, "Invalid to revision")
+			return
+		}
+		to = n
+	}
+	from := to - 1
+	if state.PublishedRevision != nil && *state.PublishedRevision != to {
+		from = *state.PublishedRevision
+	}
+	if raw := c.Query("from"); raw != "" {
+		n, ok := parseRevisionNumber(raw)
+		if !ok {
+			editorialError(c, http.StatusBadRequest, "Invalid from revision")
+			return
+		}
+		from = n
+	}
+
+	db := c.MustGet("db").(*gorm.DB)
+	toRev, err := findEditorialRevision(db, kind, doc, to)
+	if err != nil {
+		editorialLookupError(c, "Revision", err)
+		return
+	}
+	fromRev := models.EditorialRevision{}
+	if from > 0 {
+		if fromRev, err = findEditorialRevision(db, kind, doc, from); err != nil {
+			editorialLookupError(c, "Revision", err)
+			return
+		}
+	}
+	c.JSON(http.StatusOK, buildEditorialDiff(fromRev, toRev))
+}
+
+// ─── Admin mutations ─────────────────────────────────────────
+
+// mutateEditorialDocument locks the tenant's row, runs fn and saves the
+// result. fn returns a client-facing error for invalid transitions.
+func mutateEditorialDocument(c *gin.Context, kind editorialKind, fn func(tx *gorm.DB, doc models.EditorialDocument, principal utils.AdminPrincipal) (clientErr error, err error)) {
+	principal, ok := requireAdminPrincipal(c)
+	if !ok {
+		return
+	}
+	id, ok := parseEditorialID(c, kind)
+	if !ok {
+		return
+	}
+	db := c.MustGet("db").(*gorm.DB)
+	tx := db.Begin()
+	doc, err := lockEditorialDocument(tx, kind, principal.TenantID, id)
+	if err != nil {
+		tx.Rollback()
+		editorialLookupError(c, kind.label, err)
+		return
+	}
+	clientErr, err := fn(tx, doc, principal)
+	if clientErr != nil {
+		tx.Rollback()
+		editorialError(c, http.StatusConflict, clientErr.Error())
+		return
+	}
+	if err == nil {
+		err = tx.Omit(clause.Associations).Save(doc).Error
+	}
+	if err == nil {
+		err = tx.Commit().Error
+	} else {
+		tx.Rollback()
+	}
+	if err != nil {
+		editorialServerError(c, "Failed to update "+strings.ToLower(kind.label), err)
+		return
+	}
+	c.JSON(http.StatusOK, doc)
+}
+
+// bindOptionalJSON binds a body only when one was sent.
+func bindOptionalJSON(c *gin.Context, dest interface{}) bool {
+	if c.Request.ContentLength == 0 {
+		return true
+	}
+	if err := c.ShouldBindJSON(dest); err != nil {
+		editorialError(c, http.StatusBadRequest, "Invalid request body")
+		return false
+	}
+	return true
+}
+
+// publishEditorialDocument handles POST …/:id/publish
+// {revision?, publish_at?, unpublish_at?}.
+func publishEditorialDocument(c *gin.Context, kind editorialKind) {
+	var req editorialScheduleRequest
+	if !bindOptionalJSON(c, &req) {
+		return
+	}
+	mutateEditorialDocument(c, kind, func(_ *gorm.DB, doc models.EditorialDocument, _ utils.AdminPrincipal) (error, error) {
+		state := doc.Editorial()
+		revision := state.CurrentRevision
+		if req.Revision != nil {
+			revision = *req.Revision
+		}
+		return applyEditorialPublish(state, revision, req.PublishAt, req.UnpublishAt, time.Now().UTC()), nil
+	})
+}
+
+// unpublishEditorialDocument handles POST …/:id/unpublish {unpublish_at?}.
+func unpublishEditorialDocument(c *gin.Context, kind editorialKind) {
+	var req editorialScheduleRequest
+	if !bindOptionalJSON(c, &req) {
+		return
+	}
+	mutateEditorialDocument(c, kind, func(_ *gorm.DB, doc models.EditorialDocument, _ utils.AdminPrincipal) (error, error) {
+		return applyEditorialUnpublish(doc.Editorial(), req.UnpublishAt, time.Now().UTC()), nil
+	})
+}
+
+func archiveEditorialDocument(c *gin.Context, kind editorialKind) {
+	mutateEditorialDocument(c, kind, func(_ *gorm.DB, doc models.EditorialDocument, _ utils.AdminPrincipal) (error, error) {
+		return applyEditorialArchive(doc.Editorial(), time.Now().UTC()), nil
+	})
+}
+
+// restoreEditorialRevision handles POST …/:id/revisions/:revision/restore. The
+// old revision is copied forward as a new revision; history is never rewritten.
+func restoreEditorialRevision(c *gin.Context, kind editorialKind) {
+	n, ok := parseRevisionNumber(c.Param("revision"))
+	if !ok || n == 0 {
+		editorialError(c, http.StatusBadRequest, "Invalid revision")
+		return
+	}
+	mutateEditorialDocument(c, kind, func(tx *gorm.DB, doc models.EditorialDocument, principal utils.AdminPrincipal) (error, error) {
+		rev, err := findEditorialRevision(tx, kind, doc, n)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("revision %d does not exist", n), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		doc.ApplyRevision(rev)
+		// Re-render under the current policy; this also recovers the
+		// attached media for posts.
+		state := doc.Editorial()
+		rendering, err := renderEditorialContent(tx, state, rev.Content, richtext.Format(state.ContentFormat))
+		if err != nil {
+			return nil, err
+		}
+		if kind.syncMedia != nil {
+			if _, err := kind.syncMedia(tx, doc, rendering.Media, &rev); err != nil {
+				return nil, err
+			}
+		}
+		state.CurrentRevision++
+		note := "Restored from revision " + strconv.Itoa(n)
+		_, err = insertEditorialRevision(tx, doc, principal.UserID, &note)
+		return nil, err
+	})
+}
+
+// ─── Public reads ────────────────────────────────────────────
+
+// publishedEditorialQuery selects the public tenant's live rows joined to
+// their published revision as r.
+func publishedEditorialQuery(db *gorm.DB, kind editorialKind, tenantID string) *gorm.DB {
+	return db.Table(kind.table).
+		Joins("JOIN editorial_revisions r ON r.entity_type = ? AND r.entity_id = "+kind.table+".id AND r.revision = "+kind.table+".published_revision", kind.entity).
+		Where(kind.table+".tenant_id = ? AND "+kind.table+".status = ?", tenantID, models.EditorialPublished)
+}
+
+// whereEditorialIdentifier matches :id as a public UUID or a slug; ok is
+// false when it is neither.
+func whereEditorialIdentifier(q *gorm.DB, kind editorialKind, raw string) (*gorm.DB, bool) {
+	if id, err := uuid.Parse(raw); err == nil {
+		return q.Where(kind.table+".public_id = ?", id), true
+	}
+	if raw == "" || editorialSlug(raw) != raw {
+		return q, false
+	}
+	return q.Where(kind.table+".slug = ?", raw), true
+}
+
+// publicEditorialTenant resolves the public tenant or writes a 503.
+func publicEditorialTenant(c *gin.Context) (string, bool) {
+	tenantID, err := trustedPublicFeedTenant(c)
+	if err != nil {
+		editorialError(c, http.StatusServiceUnavailable, "Public feed tenant is unavailable")
+		return "", false
+	}
+	return tenantID, true
+}
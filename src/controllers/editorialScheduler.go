@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"content-management-system/src/lifecycle"
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const editorialSchedulerTick = 30 * time.Second
+
+var editorialSchedulerHeartbeat atomic.Int64
+
+// StartEditorialScheduler applies due publish_at/unpublish_at transitions for
+// pages and posts. The transitions are single UPDATEs, so overlapping
+// replicas cannot double-apply them.
+func StartEditorialScheduler(ctx context.Context, db *gorm.DB) {
+	lifecycle.Go(ctx, "editorial-scheduler", func(ctx context.Context) {
+		ticker := time.NewTicker(editorialSchedulerTick)
+		defer ticker.Stop()
+		runEditorialScheduler(db)
+		for lifecycle.Wait(ctx, ticker.C) {
+			runEditorialScheduler(db)
+		}
+	})
+}
+
+func EditorialSchedulerHealthy(now time.Time) bool {
+	last := editorialSchedulerHeartbeat.Load()
+	return last > 0 && now.UTC().Sub(time.Unix(0, last).UTC()) <= 6*editorialSchedulerTick
+}
+
+func EditorialSchedulerLastHeartbeat() time.Time {
+	return lifecycle.HeartbeatTime(editorialSchedulerHeartbeat.Load())
+}
+
+func runEditorialScheduler(db *gorm.DB) {
+	for _, kind := range []editorialKind{pageEditorial, postEditorial} {
+		published, unpublished, err := applyDueEditorialTransitions(db, kind.table)
+		if err != nil {
+			log.Printf("editorial scheduler (%s) failed: %v", kind.table, err)
+			continue
+		}
+		if published+unpublished > 0 {
+			log.Printf("editorial scheduler: %s published=%d unpublished=%d", kind.table, published, unpublished)
+		}
+	}
+	editorialSchedulerHeartbeat.Store(time.Now().UTC().UnixNano())
+}
+
+// applyDueEditorialTransitions promotes due scheduled revisions, then takes
+// expired rows offline (unpublish_at is always after the publish time).
+func applyDueEditorialTransitions(db *gorm.DB, table string) (published, unpublished int64, err error) {
+	res := db.Exec(`UPDATE ` + table + `
+		   SET status = 'published', published_revision = scheduled_revision, scheduled_revision = NULL,
+		       publish_at = NULL, published_at = now(), archived_at = NULL, updated_at = now()
+		 WHERE scheduled_revision IS NOT NULL AND publish_at <= now()`)
+	if res.Error != nil {
+		return 0, 0, res.Error
+	}
+	published = res.RowsAffected
+	res = db.Exec(`UPDATE ` + table + `
+		   SET status = 'draft', published_revision = NULL, unpublish_at = NULL, updated_at = now()
+		 WHERE status = 'published' AND unpublish_at <= now()`)
+	if res.Error != nil {
+		return published, 0, res.Error
+	}
+	return published, res.RowsAffected, nil
+}
@@ -0,0 +1,112 @@
+package controllers
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ─── Slugs ───────────────────────────────────────────────────
+
+// editorialSlugNonWord keeps letters and digits of any script so Arabic titles
+// produce readable slugs (the feed slugify is ASCII-only).
+var editorialSlugNonWord = regexp.MustCompile(`[^\p{L}\p{N}]+`)
+
+const editorialSlugMaxRunes = 120
+
+func editorialSlug(s string) string {
+	s = editorialSlugNonWord.ReplaceAllString(strings.ToLower(strings.TrimSpace(s)), "-")
+	s = strings.Trim(s, "-")
+	if utf8.RuneCountInString(s) > editorialSlugMaxRunes {
+		s = strings.Trim(string([]rune(s)[:editorialSlugMaxRunes]), "-")
+	}
+	return s
+}
+
+// uniqueEditorialSlug returns base, or base-2, base-3… when taken.
+func uniqueEditorialSlug(base string, taken map[string]bool) string {
+	if !taken[base] {
+		return base
+	}
+	for i := 2; ; i++ {
+		candidate := base + "-" + strconv.Itoa(i)
+		if !taken[candidate] {
+			return candidate
+		}
+	}
+}
+
+// ─── Revision diff ───────────────────────────────────────────
+
+type editorialDiffLine struct {
+	Op   string `json:"op"` // equal | insert | delete
+	Text string `json:"text"`
+}
+
+// editorialDiffMaxCells bounds the LCS table; larger documents fall back to a
+// whole-document replace rather than an O(n·m) blow-up.
+const editorialDiffMaxCells = 4_000_000
+
+// diffEditorialLines is a line-level LCS diff from → to.
+func diffEditorialLines(from, to string) []editorialDiffLine {
+	a, b := splitEditorialLines(from), splitEditorialLines(to)
+	if len(a)*len(b) > editorialDiffMaxCells {
+		out := make([]editorialDiffLine, 0, len(a)+len(b))
+		for _, line := range a {
+			out = append(out, editorialDiffLine{Op: "delete", Text: line})
+		}
+		for _, line := range b {
+			out = append(out, editorialDiffLine{Op: "insert", Text: line})
+		}
+		return out
+	}
+
+	// lcs[i][j] = LCS length of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	out := make([]editorialDiffLine, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			out = append(out, editorialDiffLine{Op: "equal", Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, editorialDiffLine{Op: "delete", Text: a[i]})
+			i++
+		default:
+			out = append(out, editorialDiffLine{Op: "insert", Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		out = append(out, editorialDiffLine{Op: "delete", Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		out = append(out, editorialDiffLine{Op: "insert", Text: b[j]})
+	}
+	return out
+}
+
+func splitEditorialLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+}
@@ -0,0 +1,150 @@
+package controllers
+
+import (
+	"content-management-system/src/models"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+func TestEditorialSlugKeepsUnicodeWords(t *testing.T) {
+	cases := map[string]string{
+		"  Hello, World!  ":  "hello-world",
+		"سياسة الخصوصية":     "سياسة-الخصوصية",
+		"Terms & Conditions": "terms-conditions",
+		"under_score":        "under-score",
+		"---":                "",
+	}
+	for in, want := range cases {
+		if got := editorialSlug(in); got != want {
+			t.Errorf("editorialSlug(%q) = %q, want %q", in, got, want)
+		}
+	}
+	if got := editorialSlug(strings.Repeat("ab ", 100)); len([]rune(got)) > editorialSlugMaxRunes || strings.HasSuffix(got, "-") {
+		t.Fatalf("long slug = %q", got)
+	}
+	taken := map[string]bool{"about": true, "about-2": true}
+	if got := uniqueEditorialSlug("about", taken); got != "about-3" {
+		t.Fatalf("unique slug = %q, want about-3", got)
+	}
+}
+
+func TestDiffEditorialLines(t *testing.T) {
+	got := diffEditorialLines("a\nb\nc", "a\nB\nc\nd")
+	want := []editorialDiffLine{
+		{Op: "equal", Text: "a"},
+		{Op: "delete", Text: "b"},
+		{Op: "insert", Text: "B"},
+		{Op: "equal", Text: "c"},
+		{Op: "insert", Text: "d"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("diff = %+v", got)
+	}
+
+	diff := buildEditorialDiff(
+		models.EditorialRevision{Revision: 1, Title: "Old", Content: "x", MediaIDs: pq.StringArray{"m1", "m2"}},
+		models.EditorialRevision{Revision: 3, Title: "New", Content: "x\ny", MediaIDs: pq.StringArray{"m2", "m3"}},
+	)
+	if diff.Title == nil || *diff.Title.To != "New" || diff.Insertions != 1 || diff.Deletions != 0 {
+		t.Fatalf("revision diff = %+v", diff)
+	}
+	if !reflect.DeepEqual(diff.MediaAdded, []string{"m3"}) || !reflect.DeepEqual(diff.MediaRemoved, []string{"m1"}) {
+		t.Fatalf("media diff = +%v -%v", diff.MediaAdded, diff.MediaRemoved)
+	}
+}
+
+func TestEditorialPublishTransitions(t *testing.T) {
+	now := time.Date(2026, 8, 25, 10, 0, 0, 0, time.UTC)
+	later, latest := now.Add(time.Hour), now.Add(2*time.Hour)
+
+	state := models.EditorialState{Status: models.EditorialDraft, CurrentRevision: 2}
+	if err := applyEditorialPublish(&state, 3, nil, nil, now); err == nil {
+		t.Fatal("publishing a missing revision must fail")
+	}
+	if err := applyEditorialPublish(&state, 2, &later, &later, now); err == nil {
+		t.Fatal("unpublish_at must follow the publish time")
+	}
+
+	// Future publish_at schedules without going live.
+	if err := applyEditorialPublish(&state, 2, &later, &latest, now); err != nil {
+		t.Fatal(err)
+	}
+	if state.Status != models.EditorialScheduled || state.PublishedRevision != nil || *state.ScheduledRevision != 2 {
+		t.Fatalf("scheduled state = %+v", state)
+	}
+
+	if err := applyEditorialPublish(&state, 1, nil, nil, now); err != nil {
+		t.Fatal(err)
+	}
+	if state.Status != models.EditorialPublished || *state.PublishedRevision != 1 || state.ScheduledRevision != nil || state.UnpublishAt != nil {
+		t.Fatalf("published state = %+v", state)
+	}
+
+	// Scheduling a newer revision keeps the live one live.
+	if err := applyEditorialPublish(&state, 2, &later, nil, now); err != nil {
+		t.Fatal(err)
+	}
+	if state.Status != models.EditorialPublished || *state.PublishedRevision != 1 || *state.ScheduledRevision != 2 {
+		t.Fatalf("live + scheduled state = %+v", state)
+	}
+}
+
+func TestEditorialUnpublishAndArchive(t *testing.T) {
+	now := time.Date(2026, 8, 25, 10, 0, 0, 0, time.UTC)
+	later := now.Add(time.Hour)
+	rev := 1
+
+	draft := models.EditorialState{Status: models.EditorialDraft}
+	if err := applyEditorialUnpublish(&draft, nil, now); err == nil {
+		t.Fatal("a draft cannot be unpublished")
+	}
+
+	live := models.EditorialState{Status: models.EditorialPublished, CurrentRevision: 1, PublishedRevision: &rev}
+	if err := applyEditorialUnpublish(&live, &later, now); err != nil || live.Status != models.EditorialPublished || live.UnpublishAt == nil {
+		t.Fatalf("scheduled unpublish: err=%v state=%+v", err, live)
+	}
+	if err := applyEditorialUnpublish(&live, nil, now); err != nil || live.Status != models.EditorialDraft || live.PublishedRevision != nil {
+		t.Fatalf("unpublish now: err=%v state=%+v", err, live)
+	}
+
+	if err := applyEditorialArchive(&live, now); err != nil || live.Status != models.EditorialArchived || live.ArchivedAt == nil {
+		t.Fatalf("archive: err=%v state=%+v", err, live)
+	}
+	if err := applyEditorialArchive(&live, now); err == nil {
+		t.Fatal("archiving twice must fail")
+	}
+	if err := applyEditorialPublish(&live, 1, nil, nil, now); err != nil || live.Status != models.EditorialPublished || live.ArchivedAt != nil {
+		t.Fatalf("republish archived: err=%v state=%+v", err, live)
+	}
+}
+
+func TestEditorialLookupErrorOnlyReportsMissingRowsAsNotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cases := []struct {
+		err  error
+		want int
+	}{
+		{gorm.ErrRecordNotFound, http.StatusNotFound},
+		{errors.New("canceling statement due to lock timeout"), http.StatusInternalServerError},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		editorialLookupError(c, "Page", tc.err)
+		if w.Code != tc.want {
+			t.Errorf("%v: status = %d, want %d", tc.err, w.Code, tc.want)
+		}
+		if strings.Contains(w.Body.String(), "lock timeout") {
+			t.Errorf("driver error leaked to the client: %s", w.Body.String())
+		}
+	}
+}
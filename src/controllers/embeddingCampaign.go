@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"content-management-system/src/lifecycle"
 	"content-management-system/src/models"
 	"content-management-system/src/spaceid"
 
@@ -453,12 +454,12 @@ func invokeItemAdapter(db *gorm.DB, s EmbeddingSurface, targetID string) error {
 		if text == "" {
 			return fmt.Errorf("no text content to embed")
 		}
-		return triggerEmbedding(text, targetID)
+		return triggerEmbedding(lifecycle.Root(), text, targetID)
 	case "content_image":
 		if item.ThumbnailURL == nil || *item.ThumbnailURL == "" {
 			return fmt.Errorf("no thumbnail_url to embed")
 		}
-		return triggerImageEmbedding(*item.ThumbnailURL, targetID)
+		return triggerImageEmbedding(lifecycle.Root(), *item.ThumbnailURL, targetID)
 	default:
 		return fmt.Errorf("no item adapter for surface %s", s.Key)
 	}
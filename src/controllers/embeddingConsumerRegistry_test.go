@@ -31,6 +31,8 @@ var knownSemanticConsumers = map[string][]string{
 	"internalContentController.go":   {"knn_dense", "related_dense"},
 	"intelligenceController.go":      {"related_dense"},
 	"redundancyHygieneController.go": {"redundancy_dense", "redundancy_image"},
+	"contentSearchController.go":     {"search_dense"},
+	"tasteVector.go":                 {"taste_similarity"},
 }
 
 // semanticExemptFiles use `<=>` only in comments or as pure string/literal
@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -8,6 +9,7 @@ import (
 	"sync"
 	"time"
 
+	"content-management-system/src/lifecycle"
 	"content-management-system/src/spaceid"
 )
 
@@ -113,7 +115,9 @@ func fetchExpectedSpace(space string) expectedSpace {
 		return expectedSpace{Space: space, ObservedAt: now, Err: "service base URL/token not configured"}
 	}
 
-	desc, err := fetchModelDescriptor(baseURL, token, itemType)
+	// The descriptor fills a cache shared by every caller, so it is bound to
+	// the process rather than to whichever request happened to miss.
+	desc, err := fetchModelDescriptor(lifecycle.Root(), baseURL, token, itemType)
 	if err != nil {
 		return expectedSpace{Space: space, ObservedAt: now, Err: err.Error()}
 	}
@@ -164,13 +168,13 @@ func textStampForObservedSpace(recipe, observedSpaceID string) (model, producer
 	return es.Model, producer, producer != ""
 }
 
-func fetchModelDescriptor(baseURL, token, itemType string) (*modelDescriptor, error) {
-	req, err := http.NewRequest(http.MethodGet, baseURL+"/v1/models", nil)
+func fetchModelDescriptor(ctx context.Context, baseURL, token, itemType string) (*modelDescriptor, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/v1/models", nil)
 	if err != nil {
 		return nil, err
 	}
 	req.Header.Set("Authorization", "Bearer "+token)
-	client := &http.Client{Timeout: 15 * time.Second}
+	client := &http.Client{Transport: ownerTransport, Timeout: 15 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("models fetch: %w", err)
@@ -1,9 +1,11 @@
 package controllers
 
 import (
+	"context"
 	"log"
 	"time"
 
+	"content-management-system/src/lifecycle"
 	"content-management-system/src/models"
 
 	"gorm.io/gorm"
@@ -13,15 +15,15 @@ import (
 // tick that fires a scheduled audit when `audit_interval_minutes` has elapsed
 // and `audit_enabled` is true. Observation only; the campaign tick (Slice 3) is
 // separate. Mirrors the family heartbeat pattern (System Health / Media Studio).
-func StartEmbeddingLifecycleHeartbeat(db *gorm.DB) {
-	go func() {
+func StartEmbeddingLifecycleHeartbeat(ctx context.Context, db *gorm.DB) {
+	lifecycle.Go(ctx, "embedding-lifecycle", func(ctx context.Context) {
 		ticker := time.NewTicker(1 * time.Minute)
 		defer ticker.Stop()
-		for range ticker.C {
+		for lifecycle.Wait(ctx, ticker.C) {
 			tickEmbeddingLifecycle(db)
 			tickEmbeddingCampaigns(db)
 		}
-	}()
+	})
 	log.Println("Embedding Lifecycle heartbeat started")
 }
 
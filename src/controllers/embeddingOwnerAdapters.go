@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	"content-management-system/src/lifecycle"
 	"content-management-system/src/models"
 	"content-management-system/src/spaceid"
 
@@ -60,7 +61,7 @@ func advanceCampaignVerification(db *gorm.DB, c *models.EmbeddingCampaign) {
 	if c.Space == EmbeddingSpaceText {
 		// Replay work deliberately held by the comparability firewall. Completion
 		// waits for both the News backlog and Preferences dirty-remap handshake.
-		StartClassificationBackfill(db)
+		StartClassificationBackfill(lifecycle.Root(), db)
 		var heldNews, dirtyTopics int64
 		db.Model(&models.ContentItem{}).
 			Where("type = ? AND status = ? AND embedding_space_id = ? AND story_id IS NULL AND COALESCE(news_retention_state, 'full') = 'full'",
@@ -233,7 +234,7 @@ func refreshTopicCentroid(db *gorm.DB, topicPublicID string, es expectedSpace) (
 	if e := db.Where("public_id = ?", topicPublicID).First(&topic).Error; e != nil {
 		return "", e
 	}
-	emb, observedSpace, e := embedQueryViaEnrichmentWithSpace(topic.LabelEN + " " + topic.LabelAR)
+	emb, observedSpace, e := embedQueryViaEnrichmentWithSpace(lifecycle.Root(), topic.LabelEN+" "+topic.LabelAR)
 	if e != nil || len(emb) != 1024 {
 		return "", fmt.Errorf("label embed failed: %v", e)
 	}
@@ -348,7 +349,7 @@ func refreshTopicProposalVector(db *gorm.DB, proposalID string, es expectedSpace
 		return e
 	}
 	text := p.SuggestedLabelEN + " " + p.SuggestedLabelAR + " " + p.SuggestedSlug
-	emb, observedSpace, e := embedQueryViaEnrichmentWithSpace(text)
+	emb, observedSpace, e := embedQueryViaEnrichmentWithSpace(lifecycle.Root(), text)
 	if e != nil || len(emb) != 1024 {
 		return fmt.Errorf("proposal embed failed: %v", e)
 	}
@@ -375,7 +376,7 @@ func refreshDiscoveryProfileVector(db *gorm.DB, profilePublicID string, es expec
 	if text == "" {
 		return fmt.Errorf("profile has no embedding input")
 	}
-	emb, observedSpace, err := embedQueryViaEnrichmentWithSpace(text)
+	emb, observedSpace, err := embedQueryViaEnrichmentWithSpace(lifecycle.Root(), text)
 	if err != nil || len(emb) != 1024 {
 		return fmt.Errorf("profile embed failed: %v", err)
 	}
@@ -92,7 +92,7 @@ var embeddingSurfaceRegistry = []EmbeddingSurface{
 		TenantCol: "tenant_id", Dim: 1024, Kind: SurfaceKindItem, Owner: OwnerEnrichment,
 		IDCol:        "public_id",
 		Recipe:       spaceid.RecipeContentText,
-		ConsumerKeys: []string{"knn_dense", "related_dense", "story_classify", "discovery_dense", "redundancy_dense"},
+		ConsumerKeys: []string{"knn_dense", "related_dense", "story_classify", "discovery_dense", "redundancy_dense", "search_dense", "taste_similarity"},
 	},
 	{
 		Key: "content_image", Label: "Content image embeddings", Space: EmbeddingSpaceImage,
@@ -119,7 +119,7 @@ var embeddingSurfaceRegistry = []EmbeddingSurface{
 		TenantCol: "tenant_id", Dim: 1024, Kind: SurfaceKindCentroid, Owner: OwnerPreferences,
 		IDCol:        "public_id",
 		Recipe:       spaceid.RecipeTopicCentroid,
-		ConsumerKeys: []string{"topic_map", "topic_affinity"},
+		ConsumerKeys: []string{"topic_map", "topic_affinity", "taste_similarity"},
 	},
 	{
 		Key: "topic_proposal", Label: "Topic proposal seeds", Space: EmbeddingSpaceText,
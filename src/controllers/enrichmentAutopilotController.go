@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -14,6 +15,7 @@ import (
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 
+	"content-management-system/src/lifecycle"
 	"content-management-system/src/models"
 )
 
@@ -631,7 +633,7 @@ func (r *enrichmentAutopilotRunner) dispatchItem(item *models.ContentItem, artif
 	}
 
 	// Safe Auto: execute through the shared traced path with autopilot attribution.
-	outcomes := triggerItemArtifactsTraced(r.db, item, []string{artifact}, false, models.TranscriptionTriggerEnrichmentAutopilot)
+	outcomes := triggerItemArtifactsTraced(lifecycle.Root(), r.db, item, []string{artifact}, false, models.TranscriptionTriggerEnrichmentAutopilot)
 	finishedAt := time.Now()
 	action := models.EnrichmentAutopilotAction{
 		ContentID: &id, Artifact: artifact,
@@ -898,14 +900,14 @@ func pluralizeArtifacts(trusted []string) string {
 // ticker fires runs for tenants whose interval has elapsed. One run per tenant at
 // a time; pause and disable are respected without touching policy. Mirrors the
 // Media/News heartbeat pattern.
-func StartEnrichmentAutopilotHeartbeat(db *gorm.DB) {
-	go func() {
+func StartEnrichmentAutopilotHeartbeat(ctx context.Context, db *gorm.DB) {
+	lifecycle.Go(ctx, "enrichment-autopilot", func(ctx context.Context) {
 		ticker := time.NewTicker(1 * time.Minute)
 		defer ticker.Stop()
-		for range ticker.C {
+		for lifecycle.Wait(ctx, ticker.C) {
 			withEnrichmentAutopilotRecovery("heartbeat", func() { runEnrichmentAutopilotDue(db) })
 		}
-	}()
+	})
 }
 
 // withEnrichmentAutopilotRecovery prevents one malformed row or downstream edge
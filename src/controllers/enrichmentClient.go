@@ -3,6 +3,8 @@ package controllers
 import (
 	"bytes"
 	"content-management-system/src/models"
+	"content-management-system/src/tracing"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -15,6 +17,10 @@ import (
 	"gorm.io/gorm"
 )
 
+// ownerTransport is shared by the clients that call Aggregation, Media and
+// Enrichment: each call gets a client span and carries traceparent.
+var ownerTransport = tracing.Transport(nil)
+
 // ─── Service URL + token resolution ─────────────────────────
 
 // enrichmentBaseURL returns the configured Enrichment Service URL (text
@@ -78,7 +84,7 @@ func checkServiceHealth(baseURL, serviceName string) (*serviceHealthResponse, er
 		return nil, fmt.Errorf("%s base URL is not configured", serviceName)
 	}
 
-	client := &http.Client{Timeout: 15 * time.Second}
+	client := &http.Client{Transport: ownerTransport, Timeout: 15 * time.Second}
 	resp, err := client.Get(baseURL + "/ready")
 	if err != nil {
 		return nil, fmt.Errorf("%s unreachable: %w", serviceName, err)
@@ -122,7 +128,7 @@ func checkMediaHealth() (*serviceHealthResponse, error) {
 // empty preserves the historical ingest_auto/manual derivation from `force`.
 // Returns the created job's public id (empty when the guard skips) so callers can
 // cross-link it (e.g. the Enrichment Autopilot ledger).
-func triggerTranscription(item *models.ContentItem, db *gorm.DB, force bool, triggerSource string) (string, error) {
+func triggerTranscription(ctx context.Context, item *models.ContentItem, db *gorm.DB, force bool, triggerSource string) (string, error) {
 	trigger := triggerSource
 	if trigger == "" {
 		trigger = models.TranscriptionTriggerIngestAuto
@@ -138,13 +144,13 @@ func triggerTranscription(item *models.ContentItem, db *gorm.DB, force bool, tri
 		return "", &sttSkippedError{reason: reason, kind: kind}
 	}
 	jobID := job.PublicID.String()
-	if err := submitTranscriptionJobToMedia(db, item, jobID); err != nil {
+	if err := submitTranscriptionJobToMedia(ctx, db, item, jobID); err != nil {
 		return jobID, err
 	}
 	return jobID, nil
 }
 
-func triggerTranscriptionForJob(item *models.ContentItem, transcriptionJobID string) (string, error) {
+func triggerTranscriptionForJob(ctx context.Context, item *models.ContentItem, transcriptionJobID string) (string, error) {
 	if item.MediaURL == nil || *item.MediaURL == "" {
 		return "", fmt.Errorf("no media_url available")
 	}
@@ -172,7 +178,7 @@ func triggerTranscriptionForJob(item *models.ContentItem, transcriptionJobID str
 	writer.WriteField("word_timestamps", "true")
 	writer.Close()
 
-	req, err := http.NewRequest(http.MethodPost, baseURL+"/v1/transcribe/jobs", &buf)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+"/v1/transcribe/jobs", &buf)
 	if err != nil {
 		return "", fmt.Errorf("failed to create transcription request: %w", err)
 	}
@@ -180,7 +186,7 @@ func triggerTranscriptionForJob(item *models.ContentItem, transcriptionJobID str
 	req.Header.Set("Content-Type", writer.FormDataContentType())
 	req.Header.Set("Authorization", "Bearer "+token)
 
-	client := &http.Client{Timeout: 60 * time.Second}
+	client := &http.Client{Transport: ownerTransport, Timeout: 60 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
 		return "", fmt.Errorf("media transcription request failed: %w", err)
@@ -198,7 +204,7 @@ func triggerTranscriptionForJob(item *models.ContentItem, transcriptionJobID str
 	return decoded.JobID, nil
 }
 
-func cancelMediaTranscriptionJob(mediaJobID string) error {
+func cancelMediaTranscriptionJob(ctx context.Context, mediaJobID string) error {
 	mediaJobID = strings.TrimSpace(mediaJobID)
 	if mediaJobID == "" {
 		return nil
@@ -211,12 +217,12 @@ func cancelMediaTranscriptionJob(mediaJobID string) error {
 	if token == "" {
 		return fmt.Errorf("media service token is not configured")
 	}
-	req, err := http.NewRequest(http.MethodDelete, baseURL+"/v1/transcribe/jobs/"+mediaJobID, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, baseURL+"/v1/transcribe/jobs/"+mediaJobID, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create media cancel request: %w", err)
 	}
 	req.Header.Set("Authorization", "Bearer "+token)
-	client := &http.Client{Timeout: 10 * time.Second}
+	client := &http.Client{Transport: ownerTransport, Timeout: 10 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("media cancel request failed: %w", err)
@@ -232,7 +238,7 @@ func cancelMediaTranscriptionJob(mediaJobID string) error {
 // triggerImageEmbedding sends a CLIP image-embedding request to Media-Service
 // for the item's thumbnail/hero image. Same multipart shape as
 // triggerTranscription; Media writes the 512-dim vector back to CMS.
-func triggerImageEmbedding(imageURL string, contentID string) error {
+func triggerImageEmbedding(ctx context.Context, imageURL string, contentID string) error {
 	baseURL := mediaBaseURL()
 	if baseURL == "" {
 		return fmt.Errorf("MEDIA_BASE_URL is not configured")
@@ -249,7 +255,7 @@ func triggerImageEmbedding(imageURL string, contentID string) error {
 	writer.WriteField("content_id", contentID)
 	writer.Close()
 
-	req, err := http.NewRequest(http.MethodPost, baseURL+"/v1/embed/image", &buf)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+"/v1/embed/image", &buf)
 	if err != nil {
 		return fmt.Errorf("failed to create image-embed request: %w", err)
 	}
@@ -257,7 +263,7 @@ func triggerImageEmbedding(imageURL string, contentID string) error {
 	req.Header.Set("Content-Type", writer.FormDataContentType())
 	req.Header.Set("Authorization", "Bearer "+token)
 
-	client := &http.Client{Timeout: 60 * time.Second}
+	client := &http.Client{Transport: ownerTransport, Timeout: 60 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("media image-embed request failed: %w", err)
@@ -290,7 +296,7 @@ type extractURLResult struct {
 	WordCount   int     `json:"word_count"`
 }
 
-func extractURLViaEnrichment(url string) (*extractURLResult, error) {
+func extractURLViaEnrichment(ctx context.Context, url string) (*extractURLResult, error) {
 	baseURL := enrichmentBaseURL()
 	if baseURL == "" {
 		return nil, fmt.Errorf("ENRICHMENT_BASE_URL is not configured")
@@ -307,7 +313,7 @@ func extractURLViaEnrichment(url string) (*extractURLResult, error) {
 		return nil, fmt.Errorf("marshal extract request: %w", err)
 	}
 
-	req, err := http.NewRequest(http.MethodPost, baseURL+"/v1/extract", bytes.NewReader(body))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+"/v1/extract", bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("build extract request: %w", err)
 	}
@@ -315,7 +321,7 @@ func extractURLViaEnrichment(url string) (*extractURLResult, error) {
 	req.Header.Set("Authorization", "Bearer "+token)
 
 	// Stealth fetch of an arbitrary page can be slow — allow 20s.
-	client := &http.Client{Timeout: 20 * time.Second}
+	client := &http.Client{Transport: ownerTransport, Timeout: 20 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("enrichment extract request failed: %w", err)
@@ -354,7 +360,7 @@ type feedExtractResult struct {
 
 // extractFeedViaEnrichment asks Enrichment to extract EVERY item from an
 // RSS/Atom feed (stealth fetch via Scrapling). Used by the News feed-import.
-func extractFeedViaEnrichment(url string) (*feedExtractResult, error) {
+func extractFeedViaEnrichment(ctx context.Context, url string) (*feedExtractResult, error) {
 	baseURL := enrichmentBaseURL()
 	if baseURL == "" {
 		return nil, fmt.Errorf("ENRICHMENT_BASE_URL is not configured")
@@ -370,7 +376,7 @@ func extractFeedViaEnrichment(url string) (*feedExtractResult, error) {
 		return nil, fmt.Errorf("marshal feed-extract request: %w", err)
 	}
 
-	req, err := http.NewRequest(http.MethodPost, baseURL+"/v1/extract/feed", bytes.NewReader(body))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+"/v1/extract/feed", bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("build feed-extract request: %w", err)
 	}
@@ -378,7 +384,7 @@ func extractFeedViaEnrichment(url string) (*feedExtractResult, error) {
 	req.Header.Set("Authorization", "Bearer "+token)
 
 	// A feed can have many items behind a stealth fetch — allow 60s.
-	client := &http.Client{Timeout: 60 * time.Second}
+	client := &http.Client{Transport: ownerTransport, Timeout: 60 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("enrichment feed-extract request failed: %w", err)
@@ -418,7 +424,7 @@ type storyDigestResult struct {
 // source-grounded lede + bullets + one category slug via Enrichment's
 // /v1/stories/digest. Mirrors generateTopicLabelViaEnrichment. Caller treats
 // failure as best-effort.
-func generateStorySummaryViaEnrichment(texts []string) (string, []string, string, error) {
+func generateStorySummaryViaEnrichment(ctx context.Context, texts []string) (string, []string, string, error) {
 	baseURL := enrichmentBaseURL()
 	if baseURL == "" {
 		return "", nil, "", fmt.Errorf("ENRICHMENT_BASE_URL is not configured")
@@ -434,14 +440,14 @@ func generateStorySummaryViaEnrichment(texts []string) (string, []string, string
 		return "", nil, "", fmt.Errorf("marshal story-digest request: %w", err)
 	}
 
-	req, err := http.NewRequest(http.MethodPost, baseURL+"/v1/stories/digest", bytes.NewReader(body))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+"/v1/stories/digest", bytes.NewReader(body))
 	if err != nil {
 		return "", nil, "", fmt.Errorf("build story-digest request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+token)
 
-	client := &http.Client{Timeout: 60 * time.Second}
+	client := &http.Client{Transport: ownerTransport, Timeout: 60 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
 		return "", nil, "", fmt.Errorf("enrichment story-digest request failed: %w", err)
@@ -469,7 +475,7 @@ type accountToClassify struct {
 // classifyAccountsViaEnrichment sends the ambiguous accounts the deterministic
 // pass couldn't resolve to Enrichment's cached LLM classifier. Returns a
 // handle(lowercased) -> source_class map.
-func classifyAccountsViaEnrichment(accounts []accountToClassify) (map[string]string, error) {
+func classifyAccountsViaEnrichment(ctx context.Context, accounts []accountToClassify) (map[string]string, error) {
 	baseURL := enrichmentBaseURL()
 	if baseURL == "" {
 		return nil, fmt.Errorf("ENRICHMENT_BASE_URL is not configured")
@@ -485,14 +491,14 @@ func classifyAccountsViaEnrichment(accounts []accountToClassify) (map[string]str
 		return nil, fmt.Errorf("marshal classify request: %w", err)
 	}
 
-	req, err := http.NewRequest(http.MethodPost, baseURL+"/v1/classify/accounts", bytes.NewReader(body))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+"/v1/classify/accounts", bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("build classify request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+token)
 
-	client := &http.Client{Timeout: 60 * time.Second}
+	client := &http.Client{Transport: ownerTransport, Timeout: 60 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("enrichment classify request failed: %w", err)
@@ -520,7 +526,7 @@ func classifyAccountsViaEnrichment(accounts []accountToClassify) (map[string]str
 	return out, nil
 }
 
-func generateTopicLabelViaEnrichment(texts []string) (string, error) {
+func generateTopicLabelViaEnrichment(ctx context.Context, texts []string) (string, error) {
 	baseURL := enrichmentBaseURL()
 	if baseURL == "" {
 		return "", fmt.Errorf("ENRICHMENT_BASE_URL is not configured")
@@ -536,14 +542,14 @@ func generateTopicLabelViaEnrichment(texts []string) (string, error) {
 		return "", fmt.Errorf("marshal topic-label request: %w", err)
 	}
 
-	req, err := http.NewRequest(http.MethodPost, baseURL+"/v1/stories/label", bytes.NewReader(body))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+"/v1/stories/label", bytes.NewReader(body))
 	if err != nil {
 		return "", fmt.Errorf("build topic-label request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+token)
 
-	client := &http.Client{Timeout: 30 * time.Second}
+	client := &http.Client{Transport: ownerTransport, Timeout: 30 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
 		return "", fmt.Errorf("enrichment topic-label request failed: %w", err)
@@ -603,6 +609,7 @@ type chaptersGenerateResult struct {
 }
 
 func generateChaptersViaEnrichment(
+	ctx context.Context,
 	windows []chapterWindowPayload,
 	opts chaptersGenOpts,
 ) ([]generatedChapter, error) {
@@ -633,7 +640,7 @@ func generateChaptersViaEnrichment(
 		return nil, fmt.Errorf("marshal chapters request: %w", err)
 	}
 
-	req, err := http.NewRequest(http.MethodPost, baseURL+"/v1/chapters/generate", bytes.NewReader(body))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+"/v1/chapters/generate", bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("build chapters request: %w", err)
 	}
@@ -641,7 +648,7 @@ func generateChaptersViaEnrichment(
 	req.Header.Set("Authorization", "Bearer "+token)
 
 	// LLM segmentation over a long transcript — allow generous time.
-	client := &http.Client{Timeout: 90 * time.Second}
+	client := &http.Client{Transport: ownerTransport, Timeout: 90 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("enrichment chapters request failed: %w", err)
@@ -667,7 +674,7 @@ func generateChaptersViaEnrichment(
 // — never an approval input by itself. The scorer caps calls per run; failure
 // degrades to slug-derived labels flagged needs_label.
 
-func translateViaEnrichment(text, targetLanguage string) (string, error) {
+func translateViaEnrichment(ctx context.Context, text, targetLanguage string) (string, error) {
 	baseURL := enrichmentBaseURL()
 	if baseURL == "" {
 		return "", fmt.Errorf("ENRICHMENT_BASE_URL is not configured")
@@ -687,14 +694,14 @@ func translateViaEnrichment(text, targetLanguage string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	req, err := http.NewRequest(http.MethodPost, baseURL+"/v1/translate", bytes.NewReader(payload))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+"/v1/translate", bytes.NewReader(payload))
 	if err != nil {
 		return "", err
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+token)
 
-	client := &http.Client{Timeout: 20 * time.Second}
+	client := &http.Client{Transport: ownerTransport, Timeout: 20 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
 		return "", err
@@ -732,15 +739,15 @@ func hasArabicScript(s string) bool {
 // embedQueryViaEnrichment embeds a single text synchronously and returns the
 // 1024-dim L2-normalized dense vector (Qwen). Unlike triggerEmbedding it does
 // NOT persist anything — used for on-the-fly relevance scoring of candidates.
-func embedQueryViaEnrichment(text string) ([]float32, error) {
-	vec, _, err := embedQueryViaEnrichmentWithSpace(text)
+func embedQueryViaEnrichment(ctx context.Context, text string) ([]float32, error) {
+	vec, _, err := embedQueryViaEnrichmentWithSpace(ctx, text)
 	return vec, err
 }
 
 // embedQueryViaEnrichmentWithSpace returns the vector and the exact space_id
 // reported by the inference call. Lifecycle owner adapters compare this with
 // their frozen campaign target before stamping, closing a model-swap race.
-func embedQueryViaEnrichmentWithSpace(text string) ([]float32, string, error) {
+func embedQueryViaEnrichmentWithSpace(ctx context.Context, text string) ([]float32, string, error) {
 	baseURL := enrichmentBaseURL()
 	if baseURL == "" {
 		return nil, "", fmt.Errorf("ENRICHMENT_BASE_URL is not configured")
@@ -754,14 +761,14 @@ func embedQueryViaEnrichmentWithSpace(text string) ([]float32, string, error) {
 	if err != nil {
 		return nil, "", err
 	}
-	req, err := http.NewRequest(http.MethodPost, baseURL+"/v1/embed/query", bytes.NewReader(payload))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+"/v1/embed/query", bytes.NewReader(payload))
 	if err != nil {
 		return nil, "", err
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+token)
 
-	client := &http.Client{Timeout: 15 * time.Second}
+	client := &http.Client{Transport: ownerTransport, Timeout: 15 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, "", err
@@ -788,12 +795,12 @@ func embedQueryViaEnrichmentWithSpace(text string) ([]float32, string, error) {
 // embedBatchViaEnrichment embeds multiple texts in one call and returns their
 // vectors (no persistence — content_ids omitted). Used to score a candidate's
 // sample items individually for sharper topic discrimination.
-func embedBatchViaEnrichment(texts []string) ([][]float32, error) {
-	vectors, _, err := embedBatchViaEnrichmentWithSpace(texts)
+func embedBatchViaEnrichment(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors, _, err := embedBatchViaEnrichmentWithSpace(ctx, texts)
 	return vectors, err
 }
 
-func embedBatchViaEnrichmentWithSpace(texts []string) ([][]float32, string, error) {
+func embedBatchViaEnrichmentWithSpace(ctx context.Context, texts []string) ([][]float32, string, error) {
 	if len(texts) == 0 {
 		return nil, "", fmt.Errorf("no texts")
 	}
@@ -806,14 +813,14 @@ func embedBatchViaEnrichmentWithSpace(texts []string) ([][]float32, string, erro
 	if err != nil {
 		return nil, "", err
 	}
-	req, err := http.NewRequest(http.MethodPost, baseURL+"/v1/embed", bytes.NewReader(payload))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+"/v1/embed", bytes.NewReader(payload))
 	if err != nil {
 		return nil, "", err
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+token)
 
-	client := &http.Client{Timeout: 20 * time.Second}
+	client := &http.Client{Transport: ownerTransport, Timeout: 20 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, "", err
@@ -838,7 +845,7 @@ func embedBatchViaEnrichmentWithSpace(texts []string) ([][]float32, string, erro
 
 // triggerEmbedding sends an embedding request to the Enrichment-Service.
 // Enrichment writes the embedding back to CMS via /internal endpoints.
-func triggerEmbedding(text string, contentID string) error {
+func triggerEmbedding(ctx context.Context, text string, contentID string) error {
 	baseURL := enrichmentBaseURL()
 	if baseURL == "" {
 		return fmt.Errorf("ENRICHMENT_BASE_URL is not configured")
@@ -862,7 +869,7 @@ func triggerEmbedding(text string, contentID string) error {
 		return fmt.Errorf("failed to marshal embedding request: %w", err)
 	}
 
-	req, err := http.NewRequest(http.MethodPost, baseURL+"/v1/embed", bytes.NewReader(payloadBytes))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+"/v1/embed", bytes.NewReader(payloadBytes))
 	if err != nil {
 		return fmt.Errorf("failed to create embedding request: %w", err)
 	}
@@ -870,7 +877,7 @@ func triggerEmbedding(text string, contentID string) error {
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+token)
 
-	client := &http.Client{Timeout: 30 * time.Second}
+	client := &http.Client{Transport: ownerTransport, Timeout: 30 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("enrichment embedding request failed: %w", err)
@@ -0,0 +1,33 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestEmbedQueryStopsWithTheCallerContext(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+	t.Setenv("ENRICHMENT_BASE_URL", server.URL)
+	t.Setenv("ENRICHMENT_SERVICE_TOKEN", "test-token")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	if _, _, err := embedQueryViaEnrichmentWithSpace(ctx, "query"); err == nil {
+		t.Fatal("a cancelled caller still got an embedding")
+	}
+	if elapsed := time.Since(started); elapsed > 5*time.Second {
+		t.Fatalf("embed call outlived its context by %s", elapsed)
+	}
+}
@@ -30,17 +30,11 @@ import (
 const (
 	ruxDefaultTenant     = "default"
 	ruxMaxBatchEvents    = 50
-	ruxRateWindow        = time.Minute
-	ruxRatePerWindow     = 600 // events/min per rate-key; batches of ~20 → ~30 flushes/min
-	ruxRateMaxKeys       = 50000
 	ruxMaxRequestBytes   = 64 * 1024
 	ruxIngestTokenHeader = "X-RUX-Ingest-Token"
 	ruxRateKeyHeader     = "X-RUX-Rate-Key" // HMAC/opaque key computed by the trusted BFF
 )
 
-// ruxTelemetryLimiter is the bounded, self-cleaning limiter for the ingest path.
-var ruxTelemetryLimiter = utils.NewTelemetryRateLimiter(ruxRatePerWindow, ruxRateWindow, ruxRateMaxKeys)
-
 // RuxIngestAuthMiddleware enforces the dedicated RUX ingest token. Fail-closed:
 // if RUX_INGEST_TOKEN is unset the endpoint refuses all traffic, so a
 // misconfiguration can never silently accept anonymous public writes.
@@ -133,7 +127,9 @@ func IngestExperienceEvents(c *gin.Context) {
 		return
 	}
 	rateKey := strings.TrimSpace(c.GetHeader(ruxRateKeyHeader))
-	if allowed, _ := ruxTelemetryLimiter.AllowN(rateKey, len(batch.Events)); !allowed {
+	// The BFF reads {"error":"rate limited"}, so the shared policy's headers
+	// are kept but not its 429 body.
+	if rateKey == "" || !utils.TakeRateLimitKey(c, "telemetry.ingest", rateKey, len(batch.Events)) {
 		c.JSON(http.StatusTooManyRequests, gin.H{"error": "rate limited"})
 		return
 	}
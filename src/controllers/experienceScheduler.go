@@ -1,10 +1,12 @@
 package controllers
 
 import (
+	"context"
 	"log"
 	"sync"
 	"time"
 
+	"content-management-system/src/lifecycle"
 	"content-management-system/src/models"
 
 	"gorm.io/gorm"
@@ -31,21 +33,21 @@ func finishExperienceRunLock() {
 // ticks are no-ops (no newly-closed bucket), which is cheap. Retention sweeps
 // run at most once per hour, independent of evaluation (raw events accumulate
 // even when evaluation is off but ingestion is on).
-func StartExperienceHeartbeat(db *gorm.DB) {
-	go func() {
+func StartExperienceHeartbeat(ctx context.Context, db *gorm.DB) {
+	lifecycle.Go(ctx, "experience", func(ctx context.Context) {
 		ticker := time.NewTicker(time.Minute)
 		defer ticker.Stop()
 		runExperienceDue(db)
 		SweepExperienceRetention(db)
 		lastSweep := time.Now()
-		for range ticker.C {
+		for lifecycle.Wait(ctx, ticker.C) {
 			runExperienceDue(db)
 			if time.Since(lastSweep) >= time.Hour {
 				SweepExperienceRetention(db)
 				lastSweep = time.Now()
 			}
 		}
-	}()
+	})
 }
 
 func runExperienceDue(db *gorm.DB) {
@@ -0,0 +1,407 @@
+package controllers
+
+import (
+	"content-management-system/src/models"
+	"content-management-system/src/utils"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// continueListeningWindow is how long an untouched checkpoint stays on the
+	// rail; older ones count as abandoned.
+	continueListeningWindow = 30 * 24 * time.Hour
+	// continueListeningLowProgressWindow drops items the listener barely got
+	// into (under continueListeningLowProgressRatio) sooner.
+	continueListeningLowProgressWindow = 7 * 24 * time.Hour
+	continueListeningLowProgressRatio  = 0.10
+	// continueListeningNextChapterWindow bounds how long a finished chapter
+	// keeps suggesting the one after it.
+	continueListeningNextChapterWindow = 14 * 24 * time.Hour
+	// continueListeningMinResumeSeconds ignores accidental starts.
+	continueListeningMinResumeSeconds = 15
+	// continueListeningFinishedRatio treats a checkpoint this close to the end
+	// as finished even when no completion evidence arrived (e.g. the app was
+	// closed during the outro).
+	continueListeningFinishedRatio = 0.95
+	continueListeningCandidates    = 200
+	continueListeningDefaultLimit  = 20
+	continueListeningMaxLimit      = 50
+)
+
+// ContinueListeningItem is one rail entry. Kind "resume" continues Item at
+// ResumePositionSeconds; "next_chapter" starts Item, the chapter after the one
+// the listener just finished (PreviousChapterID).
+type ContinueListeningItem struct {
+	Kind                  string                   `json:"kind"`
+	Item                  PodsItem                 `json:"item"`
+	ResumePositionSeconds int                      `json:"resume_position_seconds"`
+	Progress              float64                  `json:"progress"`
+	LastPlayedAt          time.Time                `json:"last_played_at"`
+	Parent                *ContinueListeningParent `json:"parent,omitempty"`
+	PreviousChapterID     *uuid.UUID               `json:"previous_chapter_id,omitempty"`
+}
+
+// ContinueListeningParent describes the atomized episode a chapter belongs to.
+type ContinueListeningParent struct {
+	ID           uuid.UUID `json:"id"`
+	Title        string    `json:"title,omitempty"`
+	ChapterCount int       `json:"chapter_count"`
+}
+
+// continueCheckpoint is the latest progress checkpoint for one content item.
+type continueCheckpoint struct {
+	ContentItemID   uuid.UUID
+	PositionSeconds int
+	At              time.Time
+}
+
+// continueListeningInputs is everything the rail is derived from; it is
+// loaded once per request so the rules below stay pure.
+type continueListeningInputs struct {
+	// Checkpoints are newest first.
+	Checkpoints []continueCheckpoint
+	// Content holds the eligible rows: public chapters/standalone media and
+	// playable atomized parents.
+	Content map[uuid.UUID]models.ContentItem
+	// Chapters lists each parent's public chapters by ChapterIndex.
+	Chapters map[uuid.UUID][]models.ContentItem
+	// Parents carries titles for chapters whose parent is not itself playable.
+	Parents map[uuid.UUID]models.ContentItem
+	// Completed is the latest completion per item; Hidden are explicit hides.
+	Completed map[uuid.UUID]time.Time
+	Hidden    map[uuid.UUID]bool
+}
+
+func progressPositionSeconds(metadata []byte) (int, bool) {
+	if len(metadata) == 0 {
+		return 0, false
+	}
+	var progress struct {
+		PositionSeconds *float64 `json:"position_seconds"`
+	}
+	if json.Unmarshal(metadata, &progress) != nil || progress.PositionSeconds == nil || *progress.PositionSeconds < 0 {
+		return 0, false
+	}
+	return int(*progress.PositionSeconds), true
+}
+
+func continueDuration(item models.ContentItem) int {
+	if item.DurationSec == nil || *item.DurationSec < 0 {
+		return 0
+	}
+	return *item.DurationSec
+}
+
+func continueProgressRatio(position, duration int) float64 {
+	if duration <= 0 {
+		return 0
+	}
+	ratio := float64(position) / float64(duration)
+	if ratio > 1 {
+		ratio = 1
+	}
+	return float64(int(ratio*1000)) / 1000
+}
+
+func (in continueListeningInputs) finished(id uuid.UUID, position int, at time.Time) bool {
+	if completed, ok := in.Completed[id]; ok && !completed.Before(at) {
+		return true
+	}
+	item := in.Content[id]
+	duration := continueDuration(item)
+	return duration > 0 && float64(position) >= float64(duration)*continueListeningFinishedRatio
+}
+
+func (in continueListeningInputs) parentRef(parentID uuid.UUID) *ContinueListeningParent {
+	ref := &ContinueListeningParent{ID: parentID, ChapterCount: len(in.Chapters[parentID])}
+	parent, ok := in.Content[parentID]
+	if !ok {
+		parent, ok = in.Parents[parentID]
+	}
+	if ok && parent.Title != nil {
+		ref.Title = *parent.Title
+	}
+	return ref
+}
+
+// nextChapter is the first public chapter after index that the listener has
+// neither finished nor hidden.
+func (in continueListeningInputs) nextChapter(parentID uuid.UUID, index int) (models.ContentItem, bool) {
+	for _, chapter := range in.Chapters[parentID] {
+		if chapter.ChapterIndex == nil || *chapter.ChapterIndex <= index || in.Hidden[chapter.PublicID] {
+			continue
+		}
+		if _, done := in.Completed[chapter.PublicID]; done {
+			continue
+		}
+		return chapter, true
+	}
+	return models.ContentItem{}, false
+}
+
+// chapterAt maps a position in an atomized parent onto the chapter covering it.
+func (in continueListeningInputs) chapterAt(parentID uuid.UUID, position int) (models.ContentItem, int, bool) {
+	ms := position * 1000
+	for _, chapter := range in.Chapters[parentID] {
+		if chapter.ChapterStartMs == nil || chapter.ChapterEndMs == nil {
+			continue
+		}
+		if ms >= *chapter.ChapterStartMs && ms < *chapter.ChapterEndMs {
+			return chapter, (ms - *chapter.ChapterStartMs) / 1000, true
+		}
+	}
+	return models.ContentItem{}, 0, false
+}
+
+// buildContinueListening applies the rail rules to the latest checkpoints:
+//
+//   - one entry per episode, from its most recent activity on any device;
+//   - finished items drop out, and a finished chapter suggests the next one;
+//   - accidental starts, hidden items and abandoned checkpoints drop out;
+//   - progress on an atomized parent resumes inside the matching chapter.
+func buildContinueListening(in continueListeningInputs, now time.Time, limit int) []ContinueListeningItem {
+	out := make([]ContinueListeningItem, 0, limit)
+	seenEpisodes := make(map[uuid.UUID]bool)
+	for _, checkpoint := range in.Checkpoints {
+		if len(out) >= limit {
+			break
+		}
+		age := now.Sub(checkpoint.At)
+		if age > continueListeningWindow {
+			break
+		}
+		item, ok := in.Content[checkpoint.ContentItemID]
+		if !ok || in.Hidden[item.PublicID] {
+			continue
+		}
+		episode := item.PublicID
+		if item.ParentContentItemID != nil {
+			episode = *item.ParentContentItemID
+		}
+		if seenEpisodes[episode] || in.Hidden[episode] {
+			continue
+		}
+		// Only the latest activity on an episode counts, even when it drops
+		// the episode: finishing the last chapter must not resurface an older
+		// chapter's checkpoint.
+		seenEpisodes[episode] = true
+
+		position := checkpoint.PositionSeconds
+		duration := continueDuration(item)
+		if in.finished(item.PublicID, position, checkpoint.At) {
+			if item.ParentContentItemID == nil || item.ChapterIndex == nil || age > continueListeningNextChapterWindow {
+				continue
+			}
+			next, ok := in.nextChapter(*item.ParentContentItemID, *item.ChapterIndex)
+			if !ok {
+				continue
+			}
+			previous := item.PublicID
+			out = append(out, ContinueListeningItem{
+				Kind:              "next_chapter",
+				Item:              mapToPodsItem(next, false, false),
+				LastPlayedAt:      checkpoint.At,
+				Parent:            in.parentRef(*item.ParentContentItemID),
+				PreviousChapterID: &previous,
+			})
+			continue
+		}
+		if position < continueListeningMinResumeSeconds {
+			continue
+		}
+		ratio := continueProgressRatio(position, duration)
+		if age > continueListeningLowProgressWindow && ratio < continueListeningLowProgressRatio {
+			continue
+		}
+
+		entry := ContinueListeningItem{Kind: "resume", LastPlayedAt: checkpoint.At}
+		switch {
+		case item.ParentContentItemID != nil:
+			entry.Item = mapToPodsItem(item, false, false)
+			entry.ResumePositionSeconds = position
+			entry.Progress = ratio
+			entry.Parent = in.parentRef(*item.ParentContentItemID)
+		case len(in.Chapters[item.PublicID]) > 0:
+			chapter, offset, ok := in.chapterAt(item.PublicID, position)
+			if !ok {
+				entry.Item = mapToPodsItem(item, false, false)
+				entry.ResumePositionSeconds = position
+				entry.Progress = ratio
+				break
+			}
+			entry.Item = mapToPodsItem(chapter, false, false)
+			entry.ResumePositionSeconds = offset
+			entry.Progress = continueProgressRatio(offset, continueDuration(chapter))
+			entry.Parent = in.parentRef(item.PublicID)
+		default:
+			entry.Item = mapToPodsItem(item, false, false)
+			entry.ResumePositionSeconds = position
+			entry.Progress = ratio
+		}
+		out = append(out, entry)
+	}
+	return out
+}
+
+// loadContinueListeningInputs reads the caller's checkpoints and the content
+// they point at. scope restricts user_interactions to the caller's identity.
+func loadContinueListeningInputs(db *gorm.DB, scope func(*gorm.DB) *gorm.DB, now time.Time) (continueListeningInputs, error) {
+	in := continueListeningInputs{
+		Content:   map[uuid.UUID]models.ContentItem{},
+		Chapters:  map[uuid.UUID][]models.ContentItem{},
+		Parents:   map[uuid.UUID]models.ContentItem{},
+		Completed: map[uuid.UUID]time.Time{},
+		Hidden:    map[uuid.UUID]bool{},
+	}
+	podsTypes := []models.ContentType{models.ContentTypeVideo, models.ContentTypePodcast}
+
+	latest := scope(db.Model(&models.UserInteraction{})).
+		Select("DISTINCT ON (content_item_id) user_interactions.*").
+		Where("type = ?", models.InteractionTypeProgress).
+		Where("created_at > ?", now.Add(-continueListeningWindow)).
+		Order("content_item_id, created_at DESC, id DESC")
+	var rows []models.UserInteraction
+	if err := db.Table("(?) AS latest_progress", latest).
+		Order("created_at DESC, id DESC").Limit(continueListeningCandidates).
+		Scan(&rows).Error; err != nil {
+		return in, err
+	}
+	if len(rows) == 0 {
+		return in, nil
+	}
+	ids := make([]uuid.UUID, 0, len(rows))
+	for _, row := range rows {
+		position, ok := progressPositionSeconds(row.Metadata)
+		if !ok {
+			continue
+		}
+		in.Checkpoints = append(in.Checkpoints, continueCheckpoint{ContentItemID: row.ContentItemID, PositionSeconds: position, At: row.CreatedAt})
+		ids = append(ids, row.ContentItemID)
+	}
+	if len(ids) == 0 {
+		return in, nil
+	}
+
+	var units []models.ContentItem
+	if err := publicContentQuery(db).Where("content_items.public_id IN ? AND content_items.type IN ?", ids, podsTypes).Find(&units).Error; err != nil {
+		return in, err
+	}
+	// Atomized parents are not feed units, but a listener who played the full
+	// episode still resumes it (inside the matching chapter when one exists).
+	var parents []models.ContentItem
+	if err := db.Where("public_id IN ? AND type IN ? AND is_feed_unit = FALSE AND status = ?", ids, podsTypes, models.ContentStatusReady).
+		Where("COALESCE(playback_url, media_url) IS NOT NULL AND COALESCE(playback_url, media_url) <> ''").
+		Where("(storage_state IS NULL OR storage_state NOT IN ?)", []string{
+			models.StorageStateRecoverableDeleted,
+			models.StorageStateMissing,
+			models.StorageStateRecoveryPending,
+			models.StorageStateUnrecoverable,
+		}).
+		Find(&parents).Error; err != nil {
+		return in, err
+	}
+	parentIDs := make([]uuid.UUID, 0, len(units)+len(parents))
+	for _, item := range append(units, parents...) {
+		in.Content[item.PublicID] = item
+		if item.ParentContentItemID != nil {
+			parentIDs = append(parentIDs, *item.ParentContentItemID)
+		} else if !item.IsFeedUnit {
+			parentIDs = append(parentIDs, item.PublicID)
+		}
+	}
+
+	related := append([]uuid.UUID(nil), ids...)
+	if len(parentIDs) > 0 {
+		var chapters []models.ContentItem
+		if err := publicContentQuery(db).Where("content_items.parent_content_item_id IN ?", parentIDs).
+			Order("content_items.chapter_index ASC").Find(&chapters).Error; err != nil {
+			return in, err
+		}
+		for _, chapter := range chapters {
+			in.Chapters[*chapter.ParentContentItemID] = append(in.Chapters[*chapter.ParentContentItemID], chapter)
+			related = append(related, chapter.PublicID)
+		}
+		var titles []models.ContentItem
+		if err := db.Select("public_id", "title").Where("public_id IN ?", parentIDs).Find(&titles).Error; err != nil {
+			return in, err
+		}
+		for _, parent := range titles {
+			in.Parents[parent.PublicID] = parent
+		}
+		related = append(related, parentIDs...)
+	}
+
+	var terminal []struct {
+		ContentItemID uuid.UUID
+		Type          models.InteractionType
+		At            time.Time
+	}
+	if err := scope(db.Model(&models.UserInteraction{})).
+		Select("content_item_id, type, MAX(created_at) AS at").
+		Where("type IN ? AND content_item_id IN ?", []models.InteractionType{models.InteractionTypeComplete, models.InteractionTypeHide}, related).
+		Group("content_item_id, type").
+		Scan(&terminal).Error; err != nil {
+		return in, err
+	}
+	for _, row := range terminal {
+		if row.Type == models.InteractionTypeHide {
+			in.Hidden[row.ContentItemID] = true
+		} else {
+			in.Completed[row.ContentItemID] = row.At
+		}
+	}
+	return in, nil
+}
+
+// GetPodsContinueListening returns the continue-listening rail: unfinished
+// Pods items with resume offsets and next-chapter suggestions, most recent
+// first. Signed-in listeners get the same rail on every device because it is
+// derived from checkpoints keyed by the verified user id, latest checkpoint
+// winning (so a rewind on one device is respected on the others).
+// GET /api/v1/feed/pods/continue?limit=20&session_id=xxx
+func GetPodsContinueListening(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+
+	limit := continueListeningDefaultLimit
+	if raw := strings.TrimSpace(c.Query("limit")); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			c.JSON(http.StatusBadRequest, utils.HTTPError{Code: http.StatusBadRequest, Message: "limit must be a positive integer"})
+			return
+		}
+		if n > continueListeningMaxLimit {
+			n = continueListeningMaxLimit
+		}
+		limit = n
+	}
+
+	var scope func(*gorm.DB) *gorm.DB
+	if uid, ok := authedUserID(c); ok {
+		scope = func(q *gorm.DB) *gorm.DB { return q.Where("user_id = ?", uid) }
+	} else if sessionID := c.Query("session_id"); sessionID != "" {
+		scope = func(q *gorm.DB) *gorm.DB { return q.Where("session_id = ?", sessionID) }
+	} else {
+		c.JSON(http.StatusUnauthorized, utils.HTTPError{Code: http.StatusUnauthorized, Message: "Authentication or session_id required"})
+		return
+	}
+
+	now := time.Now().UTC()
+	in, err := loadContinueListeningInputs(db, scope, now)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.HTTPError{Code: http.StatusInternalServerError, Message: "Failed to load continue listening"})
+		return
+	}
+	sort.SliceStable(in.Checkpoints, func(i, j int) bool { return in.Checkpoints[i].At.After(in.Checkpoints[j].At) })
+
+	c.Header("Cache-Control", "private, no-store")
+	c.JSON(http.StatusOK, gin.H{"items": buildContinueListening(in, now, limit)})
+}
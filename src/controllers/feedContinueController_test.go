@@ -0,0 +1,126 @@
+package controllers
+
+import (
+	"content-management-system/src/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func continueTestItem(duration int) models.ContentItem {
+	return models.ContentItem{PublicID: uuid.New(), Type: models.ContentTypePodcast, DurationSec: &duration, IsFeedUnit: true}
+}
+
+func continueTestChapter(parent uuid.UUID, index, startSec, endSec int) models.ContentItem {
+	item := continueTestItem(endSec - startSec)
+	startMs, endMs := startSec*1000, endSec*1000
+	item.ParentContentItemID, item.ChapterIndex = &parent, &index
+	item.ChapterStartMs, item.ChapterEndMs = &startMs, &endMs
+	return item
+}
+
+func continueTestInputs(items ...models.ContentItem) continueListeningInputs {
+	in := continueListeningInputs{
+		Content:   map[uuid.UUID]models.ContentItem{},
+		Chapters:  map[uuid.UUID][]models.ContentItem{},
+		Parents:   map[uuid.UUID]models.ContentItem{},
+		Completed: map[uuid.UUID]time.Time{},
+		Hidden:    map[uuid.UUID]bool{},
+	}
+	for _, item := range items {
+		in.Content[item.PublicID] = item
+		if item.ParentContentItemID != nil {
+			in.Chapters[*item.ParentContentItemID] = append(in.Chapters[*item.ParentContentItemID], item)
+		}
+	}
+	return in
+}
+
+func TestBuildContinueListeningResumeAndDecay(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0).UTC()
+	resume := continueTestItem(600)
+	accidental := continueTestItem(600)
+	nearlyDone := continueTestItem(600)
+	completed := continueTestItem(600)
+	abandoned := continueTestItem(600)
+	stale := continueTestItem(600)
+	hidden := continueTestItem(600)
+
+	in := continueTestInputs(resume, accidental, nearlyDone, completed, abandoned, stale, hidden)
+	in.Completed[completed.PublicID] = now.Add(-time.Minute)
+	in.Hidden[hidden.PublicID] = true
+	in.Checkpoints = []continueCheckpoint{
+		{ContentItemID: resume.PublicID, PositionSeconds: 120, At: now.Add(-time.Hour)},
+		{ContentItemID: accidental.PublicID, PositionSeconds: 3, At: now.Add(-time.Hour)},
+		{ContentItemID: nearlyDone.PublicID, PositionSeconds: 590, At: now.Add(-time.Hour)},
+		{ContentItemID: completed.PublicID, PositionSeconds: 300, At: now.Add(-2 * time.Hour)},
+		{ContentItemID: hidden.PublicID, PositionSeconds: 300, At: now.Add(-2 * time.Hour)},
+		{ContentItemID: abandoned.PublicID, PositionSeconds: 30, At: now.Add(-8 * 24 * time.Hour)},
+		{ContentItemID: stale.PublicID, PositionSeconds: 300, At: now.Add(-31 * 24 * time.Hour)},
+	}
+
+	got := buildContinueListening(in, now, 10)
+	if len(got) != 1 {
+		t.Fatalf("items = %+v", got)
+	}
+	if got[0].Kind != "resume" || got[0].Item.ID != resume.PublicID || got[0].ResumePositionSeconds != 120 || got[0].Progress != 0.2 {
+		t.Fatalf("resume = %+v", got[0])
+	}
+}
+
+func TestBuildContinueListeningChapters(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0).UTC()
+	parent := continueTestItem(900)
+	parent.IsFeedUnit = false
+	first := continueTestChapter(parent.PublicID, 0, 0, 300)
+	second := continueTestChapter(parent.PublicID, 1, 300, 600)
+	third := continueTestChapter(parent.PublicID, 2, 600, 900)
+
+	t.Run("finished chapter suggests the next unfinished one", func(t *testing.T) {
+		in := continueTestInputs(first, second, third)
+		in.Completed[first.PublicID] = now.Add(-time.Hour)
+		in.Completed[second.PublicID] = now.Add(-time.Hour)
+		in.Checkpoints = []continueCheckpoint{
+			{ContentItemID: first.PublicID, PositionSeconds: 299, At: now.Add(-time.Hour)},
+			{ContentItemID: second.PublicID, PositionSeconds: 120, At: now.Add(-2 * time.Hour)},
+		}
+		got := buildContinueListening(in, now, 10)
+		if len(got) != 1 || got[0].Kind != "next_chapter" || got[0].Item.ID != third.PublicID ||
+			*got[0].PreviousChapterID != first.PublicID || got[0].Parent.ChapterCount != 3 {
+			t.Fatalf("items = %+v", got)
+		}
+	})
+
+	t.Run("finishing the last chapter drops the episode", func(t *testing.T) {
+		in := continueTestInputs(first, second, third)
+		in.Completed[third.PublicID] = now
+		in.Checkpoints = []continueCheckpoint{
+			{ContentItemID: third.PublicID, PositionSeconds: 300, At: now.Add(-time.Minute)},
+			{ContentItemID: first.PublicID, PositionSeconds: 100, At: now.Add(-time.Hour)},
+		}
+		if got := buildContinueListening(in, now, 10); len(got) != 0 {
+			t.Fatalf("items = %+v", got)
+		}
+	})
+
+	t.Run("parent progress resumes inside the matching chapter", func(t *testing.T) {
+		in := continueTestInputs(parent, first, second, third)
+		in.Checkpoints = []continueCheckpoint{{ContentItemID: parent.PublicID, PositionSeconds: 450, At: now.Add(-time.Hour)}}
+		got := buildContinueListening(in, now, 10)
+		if len(got) != 1 || got[0].Item.ID != second.PublicID || got[0].ResumePositionSeconds != 150 || got[0].Parent.ID != parent.PublicID {
+			t.Fatalf("items = %+v", got)
+		}
+	})
+}
+
+func TestProgressPositionSeconds(t *testing.T) {
+	if got, ok := progressPositionSeconds([]byte(`{"position_seconds":42.7}`)); !ok || got != 42 {
+		t.Fatalf("position = %d %v", got, ok)
+	}
+	for _, raw := range []string{``, `{}`, `{"position_seconds":-1}`, `not json`} {
+		if _, ok := progressPositionSeconds([]byte(raw)); ok {
+			t.Fatalf("%q must be rejected", raw)
+		}
+	}
+}
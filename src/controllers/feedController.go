@@ -3,6 +3,7 @@ package controllers
 import (
 	"content-management-system/src/feedcontract"
 	"content-management-system/src/intelligence"
+	"content-management-system/src/metrics"
 	"content-management-system/src/models"
 	"content-management-system/src/utils"
 	"encoding/json"
@@ -145,7 +146,11 @@ func GetPodsFeed(c *gin.Context) {
 		contentIDs := extractPublicIDs(allItems)
 		flagMap := LoadContentFlags(db, tenantID, contentIDs)
 		velocityData := LoadVelocityData(db, contentIDs, config.VelocityWindowHours, time.Now())
-		scored := ScoreItems(allItems, config, flagMap, velocityData, time.Now())
+		var similarityData SimilarityData
+		if config.SimilarityWeight > 0 {
+			similarityData = LoadSimilarityData(db, loadTasteProfile(db, tenantID, userIDStr, sessionID), contentIDs)
+		}
+		scored := ScoreItems(allItems, config, flagMap, velocityData, similarityData, time.Now())
 		scored, preferenceEligible := applyPreferenceFeedHook(db, tenantID, userIDStr, scored)
 		scored = applyIntelligenceFeedHooks(db, tenantID, scored)
 		scored = spaceScoredSiblingChapters(scored)
@@ -226,6 +231,7 @@ func GetPodsFeed(c *gin.Context) {
 		c.JSON(http.StatusOK, PodsResponse{Cursor: nextCursor, Items: responseItems, CaughtUp: len(responseItems) == 0 && !hasCursor(pagination), Meta: availability})
 		if !isFeedIntegritySynthetic(c) {
 			recordPodsServe(db, tenantID, items, pagination.Limit, durationTargetMinutes)
+			metrics.FeedServed("pods", len(responseItems))
 		}
 		boosted := int64(0)
 		for _, item := range pageItems {
@@ -324,6 +330,7 @@ func GetPodsFeed(c *gin.Context) {
 	})
 	if !isFeedIntegritySynthetic(c) {
 		recordPodsServe(db, tenantID, items, pagination.Limit, durationTargetMinutes)
+		metrics.FeedServed("pods", len(responseItems))
 		recordPreferenceServes(db, tenantID, preferenceEligible, int64(boosted), int64(len(items)))
 	}
 }
@@ -527,6 +534,9 @@ func GetNewsFeed(c *gin.Context) {
 		Slides: slides,
 		Meta:   availability,
 	})
+	if !isFeedIntegritySynthetic(c) {
+		metrics.FeedServed("news", len(slides))
+	}
 }
 
 func hydrateStoryInteractionStatus(db *gorm.DB, slides []StorySlide, sessionID, userIDStr string) {
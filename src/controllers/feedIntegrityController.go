@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"content-management-system/src/feedcontract"
+	"content-management-system/src/lifecycle"
 	"content-management-system/src/models"
 	"content-management-system/src/utils"
 
@@ -1539,15 +1540,15 @@ func feedIntegrityAudit(db *gorm.DB, p utils.AdminPrincipal, action, resource, s
 	_ = db.Create(&models.AuditLog{TenantID: p.TenantID, UserID: p.UserID, UserEmail: p.Email, Action: action, TargetService: "cms", TargetResource: resource, Status: status, Payload: datatypes.JSON(raw)}).Error
 }
 
-func StartFeedIntegrityHeartbeat(db *gorm.DB) {
-	go func() {
+func StartFeedIntegrityHeartbeat(ctx context.Context, db *gorm.DB) {
+	lifecycle.Go(ctx, "feed-integrity", func(ctx context.Context) {
 		ticker := time.NewTicker(time.Minute)
 		defer ticker.Stop()
 		runFeedIntegrityDue(db)
-		for range ticker.C {
+		for lifecycle.Wait(ctx, ticker.C) {
 			runFeedIntegrityDue(db)
 		}
-	}()
+	})
 }
 func runFeedIntegrityDue(db *gorm.DB) {
 	evaluatePendingFeedIntegrityRuns(db)
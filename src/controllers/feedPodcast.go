@@ -0,0 +1,474 @@
+package controllers
+
+import (
+	"content-management-system/src/models"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ─── Enclosures ─────────────────────────────────────────────
+
+type feedEnclosure struct {
+	URL    string
+	Type   string
+	Length int64
+}
+
+func (e *feedEnclosure) rss() *rssEnclosure {
+	if e == nil {
+		return nil
+	}
+	return &rssEnclosure{URL: e.URL, Length: e.Length, Type: e.Type}
+}
+
+var enclosureMimeByExt = map[string]string{
+	".mp3":  "audio/mpeg",
+	".m4a":  "audio/mp4",
+	".aac":  "audio/aac",
+	".ogg":  "audio/ogg",
+	".oga":  "audio/ogg",
+	".opus": "audio/opus",
+	".wav":  "audio/wav",
+	".mp4":  "video/mp4",
+	".m4v":  "video/x-m4v",
+	".webm": "video/webm",
+	".mov":  "video/quicktime",
+}
+
+// progressiveURLSQL matches a column holding an http(s) URL that is not an
+// HLS manifest, the SQL side of the candidate checks in feedEnclosureFor.
+const progressiveURLSQL = `(btrim(%[1]s) ~* '^https?://' AND btrim(%[1]s) !~* '\.m3u8([?#]|$)')`
+
+// feedEnclosureSQL is feedEnclosureFor as a WHERE predicate, so the podcast
+// format applies its LIMIT to rows that will actually render.
+var feedEnclosureSQL = fmt.Sprintf(`(type IN ('%s', '%s') AND (%s OR %s OR (COALESCE(lower(playback_type), '') <> 'hls' AND %s)))`,
+	models.ContentTypePodcast, models.ContentTypeVideo,
+	fmt.Sprintf(progressiveURLSQL, "media_url"), fmt.Sprintf(progressiveURLSQL, "fallback_playback_url"), fmt.Sprintf(progressiveURLSQL, "playback_url"))
+
+// feedEnclosureFor picks a progressive (downloadable) media file for podcast
+// clients. HLS manifests are never enclosures: standard podcast apps cannot
+// play them, so an HLS-only row syndicates without an enclosure.
+func feedEnclosureFor(it models.ContentItem) *feedEnclosure {
+	if it.Type != models.ContentTypePodcast && it.Type != models.ContentTypeVideo {
+		return nil
+	}
+	candidates := []*string{it.MediaURL, it.FallbackPlaybackURL}
+	if it.PlaybackType == nil || !strings.EqualFold(*it.PlaybackType, "hls") {
+		candidates = append(candidates, it.PlaybackURL)
+	}
+	for _, candidate := range candidates {
+		if candidate == nil || strings.TrimSpace(*candidate) == "" {
+			continue
+		}
+		raw := strings.TrimSpace(*candidate)
+		parsed, err := url.Parse(raw)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+			continue
+		}
+		ext := strings.ToLower(path.Ext(parsed.Path))
+		if ext == ".m3u8" {
+			continue
+		}
+		mime := enclosureMimeByExt[ext]
+		if mime == "" {
+			mime = "audio/mpeg"
+			if it.Type == models.ContentTypeVideo || (it.HasVideo != nil && *it.HasVideo) {
+				mime = "video/mp4"
+			}
+		}
+		return &feedEnclosure{URL: raw, Type: mime, Length: it.FileSizeBytes}
+	}
+	return nil
+}
+
+// transcriptKey is a transcript in the tenant of the item that references it.
+type transcriptKey struct {
+	TenantID     string
+	TranscriptID string
+}
+
+// transcriptsWithPublishedChapters reports which transcripts carry at least one
+// published (editorially approved) chapter marker in the item's own tenant.
+func transcriptsWithPublishedChapters(db *gorm.DB, keys []transcriptKey) (map[transcriptKey]bool, error) {
+	pairs := make([][]interface{}, len(keys))
+	for i, key := range keys {
+		pairs[i] = []interface{}{key.TenantID, key.TranscriptID}
+	}
+	var found []transcriptKey
+	if err := db.Model(&models.Chapter{}).
+		Distinct("tenant_id", "transcript_id").
+		Where("(tenant_id, transcript_id) IN ? AND status = ?", pairs, chapterStatusPublished).
+		Scan(&found).Error; err != nil {
+		return nil, err
+	}
+	out := make(map[transcriptKey]bool, len(found))
+	for _, key := range found {
+		out[key] = true
+	}
+	return out, nil
+}
+
+// ─── Podcast RSS (iTunes + Podcasting 2.0) ──────────────────
+
+type podcastDocument struct {
+	XMLName   xml.Name       `xml:"rss"`
+	Version   string         `xml:"version,attr"`
+	AtomNS    string         `xml:"xmlns:atom,attr"`
+	ITunesNS  string         `xml:"xmlns:itunes,attr"`
+	PodcastNS string         `xml:"xmlns:podcast,attr"`
+	Channel   podcastChannel `xml:"channel"`
+}
+
+type podcastChannel struct {
+	Title          string        `xml:"title"`
+	Link           string        `xml:"link"`
+	Description    string        `xml:"description"`
+	Language       string        `xml:"language"`
+	LastBuild      string        `xml:"lastBuildDate"`
+	TTL            int           `xml:"ttl,omitempty"`
+	AtomLinks      []rssAtomLink `xml:"atom:link"`
+	ITunesAuthor   string        `xml:"itunes:author,omitempty"`
+	ITunesSummary  string        `xml:"itunes:summary,omitempty"`
+	ITunesImage    *itunesImage  `xml:"itunes:image,omitempty"`
+	ITunesExplicit string        `xml:"itunes:explicit"`
+	ITunesType     string        `xml:"itunes:type"`
+	Items          []podcastItem `xml:"item"`
+}
+
+type itunesImage struct {
+	Href string `xml:"href,attr"`
+}
+
+type podcastItem struct {
+	Title             string              `xml:"title"`
+	Link              string              `xml:"link,omitempty"`
+	GUID              rssGUID             `xml:"guid"`
+	Description       string              `xml:"description,omitempty"`
+	PubDate           string              `xml:"pubDate"`
+	Enclosure         rssEnclosure        `xml:"enclosure"`
+	ITunesTitle       string              `xml:"itunes:title"`
+	ITunesAuthor      string              `xml:"itunes:author,omitempty"`
+	ITunesDuration    string              `xml:"itunes:duration,omitempty"`
+	ITunesImage       *itunesImage        `xml:"itunes:image,omitempty"`
+	ITunesSummary     string              `xml:"itunes:summary,omitempty"`
+	ITunesEpisodeType string              `xml:"itunes:episodeType"`
+	ITunesExplicit    string              `xml:"itunes:explicit"`
+	Transcripts       []podcastTranscript `xml:"podcast:transcript"`
+	Chapters          *podcastChapters    `xml:"podcast:chapters,omitempty"`
+}
+
+type podcastTranscript struct {
+	URL      string `xml:"url,attr"`
+	Type     string `xml:"type,attr"`
+	Language string `xml:"language,attr,omitempty"`
+	Rel      string `xml:"rel,attr,omitempty"`
+}
+
+type podcastChapters struct {
+	URL  string `xml:"url,attr"`
+	Type string `xml:"type,attr"`
+}
+
+// podcastDocumentFor builds an RSS 2.0 feed podcast apps can subscribe to. Only
+// items with a progressive enclosure are included; transcript and chapter
+// links point at CMS-served documents backed by our own transcript rows and
+// published chapter markers.
+func podcastDocumentFor(meta feedMeta, items []feedItem) podcastDocument {
+	ch := podcastChannel{
+		Title:          meta.Title,
+		Link:           meta.SelfURL,
+		Description:    meta.Description,
+		Language:       dominantFeedLanguage(items),
+		LastBuild:      meta.updatedAt().Format(time.RFC1123Z),
+		TTL:            int(feedPollInterval() / time.Minute),
+		AtomLinks:      meta.rssAtomLinks(),
+		ITunesAuthor:   emptyFallback(strings.TrimSpace(os.Getenv("FEED_PODCAST_AUTHOR")), "Wahb"),
+		ITunesSummary:  meta.Description,
+		ITunesExplicit: "false",
+		ITunesType:     "episodic",
+		Items:          []podcastItem{},
+	}
+	if image := strings.TrimSpace(os.Getenv("FEED_PODCAST_IMAGE_URL")); image != "" {
+		ch.ITunesImage = &itunesImage{Href: image}
+	}
+	for _, fi := range items {
+		if fi.Enclosure == nil {
+			continue
+		}
+		if ch.ITunesImage == nil && fi.ImageURL != "" {
+			ch.ITunesImage = &itunesImage{Href: fi.ImageURL}
+		}
+		e := podcastItem{
+			Title:             fi.Title,
+			Link:              fi.Link,
+			GUID:              rssGUID{IsPermaLink: false, Value: fi.ID},
+			Description:       fi.Description,
+			PubDate:           fi.Published.Format(time.RFC1123Z),
+			Enclosure:         *fi.Enclosure.rss(),
+			ITunesTitle:       fi.Title,
+			ITunesAuthor:      fi.Author,
+			ITunesDuration:    itunesDuration(fi.DurationSec),
+			ITunesSummary:     fi.Description,
+			ITunesEpisodeType: "full",
+			ITunesExplicit:    "false",
+		}
+		if fi.ImageURL != "" {
+			e.ITunesImage = &itunesImage{Href: fi.ImageURL}
+		}
+		if fi.TranscriptID != "" {
+			itemBase := meta.BaseURL + "/api/v1/feed/items/" + fi.ID
+			e.Transcripts = []podcastTranscript{
+				{URL: itemBase + "/transcript.vtt", Type: "text/vtt", Language: fi.Language, Rel: "captions"},
+				{URL: itemBase + "/transcript.txt", Type: "text/plain", Language: fi.Language},
+			}
+			if fi.HasChapters {
+				e.Chapters = &podcastChapters{URL: itemBase + "/chapters.json", Type: "application/json+chapters"}
+			}
+		}
+		ch.Items = append(ch.Items, e)
+	}
+	return podcastDocument{
+		Version:   "2.0",
+		AtomNS:    "http://www.w3.org/2005/Atom",
+		ITunesNS:  "http://www.itunes.com/dtds/podcast-1.0.dtd",
+		PodcastNS: "https://podcastindex.org/namespace/1.0",
+		Channel:   ch,
+	}
+}
+
+func emptyFallback(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// itunesDuration formats seconds as HH:MM:SS (iTunes accepts either form; the
+// clock form is what most directories display verbatim).
+func itunesDuration(seconds int) string {
+	if seconds <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%02d:%02d:%02d", seconds/3600, (seconds%3600)/60, seconds%60)
+}
+
+// dominantFeedLanguage picks the channel language from the items' declared
+// content languages, defaulting to English like the plain RSS channel.
+func dominantFeedLanguage(items []feedItem) string {
+	counts := map[string]int{}
+	best, bestCount := "en", 0
+	for _, fi := range items {
+		if fi.Language == "" || fi.Enclosure == nil {
+			continue
+		}
+		counts[fi.Language]++
+		if counts[fi.Language] > bestCount {
+			best, bestCount = fi.Language, counts[fi.Language]
+		}
+	}
+	return best
+}
+
+// ─── Transcript + chapter documents ─────────────────────────
+
+// loadSyndicatedItem resolves a READY content item and its active transcript
+// with the same visibility rule as GetTranscript.
+func loadSyndicatedItem(c *gin.Context) (*models.ContentItem, *models.Transcript, bool) {
+	db := c.MustGet("db").(*gorm.DB)
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "invalid content id"})
+		return nil, nil, false
+	}
+	var item models.ContentItem
+	if err := db.Where("public_id = ? AND status = ?", id, models.ContentStatusReady).First(&item).Error; err != nil || item.TranscriptID == nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": "transcript not found"})
+		return nil, nil, false
+	}
+	var transcript models.Transcript
+	if err := db.Where("public_id = ? AND content_item_id IN (?, ?)", *item.TranscriptID, item.PublicID, parentOrSelf(item)).
+		First(&transcript).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": "transcript not found"})
+		return nil, nil, false
+	}
+	return &item, &transcript, true
+}
+
+func parentOrSelf(item models.ContentItem) uuid.UUID {
+	if item.ParentContentItemID != nil {
+		return *item.ParentContentItemID
+	}
+	return item.PublicID
+}
+
+// chapterWindowMs is the [start, end) slice of the parent timeline an
+// atomized child covers; zero end means "to the end of the media".
+func chapterWindowMs(item *models.ContentItem) (int, int) {
+	if item.ParentContentItemID == nil || item.ChapterStartMs == nil {
+		return 0, 0
+	}
+	end := 0
+	if item.ChapterEndMs != nil {
+		end = *item.ChapterEndMs
+	}
+	return *item.ChapterStartMs, end
+}
+
+// GetFeedItemTranscriptVTT handles GET /api/v1/feed/items/:id/transcript.vtt.
+// Atomized children get cues re-based onto their own chapter window.
+func GetFeedItemTranscriptVTT(c *gin.Context) {
+	item, transcript, ok := loadSyndicatedItem(c)
+	if !ok {
+		return
+	}
+	startMs, endMs := chapterWindowMs(item)
+	body := buildTranscriptVTT(extractSegments(transcript), transcript.FullText, startMs, endMs, durationMs(item))
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(feedPollInterval().Seconds())))
+	c.Data(http.StatusOK, "text/vtt; charset=utf-8", []byte(body))
+}
+
+// GetFeedItemTranscriptText handles GET /api/v1/feed/items/:id/transcript.txt.
+func GetFeedItemTranscriptText(c *gin.Context) {
+	item, transcript, ok := loadSyndicatedItem(c)
+	if !ok {
+		return
+	}
+	text := transcript.FullText
+	if startMs, endMs := chapterWindowMs(item); startMs > 0 || endMs > 0 {
+		var parts []string
+		for _, seg := range extractSegments(transcript) {
+			if segmentInWindow(seg, startMs, endMs) {
+				parts = append(parts, strings.TrimSpace(seg.Text))
+			}
+		}
+		if len(parts) > 0 {
+			text = strings.Join(parts, " ")
+		}
+	}
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(feedPollInterval().Seconds())))
+	c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(text))
+}
+
+func segmentInWindow(seg segmentData, startMs, endMs int) bool {
+	startSec := float64(startMs) / 1000
+	if seg.End <= startSec {
+		return false
+	}
+	return endMs <= 0 || seg.Start < float64(endMs)/1000
+}
+
+// buildTranscriptVTT renders WebVTT cues from timestamped segments. Without
+// segments the full text becomes one cue spanning the known duration.
+func buildTranscriptVTT(segments []segmentData, fullText string, startMs, endMs, mediaMs int) string {
+	var sb strings.Builder
+	sb.WriteString("WEBVTT\n")
+	offset := float64(startMs) / 1000
+	cue := 0
+	for _, seg := range segments {
+		if !segmentInWindow(seg, startMs, endMs) {
+			continue
+		}
+		from, to := seg.Start-offset, seg.End-offset
+		if from < 0 {
+			from = 0
+		}
+		if endMs > 0 && to > float64(endMs-startMs)/1000 {
+			to = float64(endMs-startMs) / 1000
+		}
+		if to <= from {
+			continue
+		}
+		cue++
+		fmt.Fprintf(&sb, "\n%d\n%s --> %s\n%s\n", cue, vttTimestamp(from), vttTimestamp(to), vttCueText(seg.Text))
+	}
+	if cue == 0 && strings.TrimSpace(fullText) != "" {
+		spanMs := mediaMs
+		if endMs > startMs {
+			spanMs = endMs - startMs
+		}
+		if spanMs <= 0 {
+			spanMs = 1000
+		}
+		fmt.Fprintf(&sb, "\n1\n%s --> %s\n%s\n", vttTimestamp(0), vttTimestamp(float64(spanMs)/1000), vttCueText(fullText))
+	}
+	return sb.String()
+}
+
+func vttTimestamp(seconds float64) string {
+	ms := int(seconds*1000 + 0.5)
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3600000, (ms%3600000)/60000, (ms%60000)/1000, ms%1000)
+}
+
+// vttCueText keeps a cue on one logical block: blank lines would end the cue
+// and "-->" would be parsed as a timing line.
+func vttCueText(text string) string {
+	text = strings.ReplaceAll(text, "-->", "->")
+	return strings.Join(strings.Fields(text), " ")
+}
+
+type podcastChapterDocument struct {
+	Version  string                `json:"version"`
+	Chapters []podcastChapterEntry `json:"chapters"`
+}
+
+type podcastChapterEntry struct {
+	StartTime float64 `json:"startTime"`
+	EndTime   float64 `json:"endTime,omitempty"`
+	Title     string  `json:"title"`
+	URL       string  `json:"url,omitempty"`
+}
+
+// GetFeedItemChapters handles GET /api/v1/feed/items/:id/chapters.json in the
+// Podcasting 2.0 JSON chapters format. Only published markers are exposed;
+// a marker whose atomized child is itself a feed unit links to that unit.
+func GetFeedItemChapters(c *gin.Context) {
+	item, transcript, ok := loadSyndicatedItem(c)
+	if !ok {
+		return
+	}
+	if item.ParentContentItemID != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": "chapters are published on the parent episode"})
+		return
+	}
+	db := c.MustGet("db").(*gorm.DB)
+	var rows []models.Chapter
+	if err := db.Where("transcript_id = ? AND tenant_id = ? AND status = ?", transcript.PublicID, item.TenantID, chapterStatusPublished).
+		Order("start_ms ASC").Find(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "failed to load chapters"})
+		return
+	}
+	if len(rows) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"message": "chapters not found"})
+		return
+	}
+	doc := podcastChapterDocument{Version: "1.2.0", Chapters: make([]podcastChapterEntry, 0, len(rows))}
+	base := publicBaseURL(c)
+	for _, ch := range chaptersToDTO(rows, durationMs(item)) {
+		entry := podcastChapterEntry{StartTime: float64(ch.StartMs) / 1000, Title: ch.Title}
+		if ch.EndMs > ch.StartMs {
+			entry.EndTime = float64(ch.EndMs) / 1000
+		}
+		if ch.ChildContentItemID != nil {
+			entry.URL = base + "/api/v1/content/" + *ch.ChildContentItemID
+		}
+		doc.Chapters = append(doc.Chapters, entry)
+	}
+	body, err := json.Marshal(doc)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "failed to build chapters"})
+		return
+	}
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(feedPollInterval().Seconds())))
+	c.Data(http.StatusOK, "application/json+chapters; charset=utf-8", body)
+}
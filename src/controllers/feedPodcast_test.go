@@ -0,0 +1,109 @@
+package controllers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"content-management-system/src/models"
+
+	"gorm.io/gorm"
+)
+
+func TestFeedEnclosureForSkipsHLSAndInfersMime(t *testing.T) {
+	hls := "https://cdn.example.test/master.m3u8"
+	hlsType := "hls"
+	mp3 := "https://cdn.example.test/episode.mp3?sig=1"
+	item := models.ContentItem{Type: models.ContentTypePodcast, PlaybackURL: &hls, PlaybackType: &hlsType, FileSizeBytes: 4096}
+	if got := feedEnclosureFor(item); got != nil {
+		t.Fatalf("HLS-only item produced enclosure %+v", got)
+	}
+	item.MediaURL = &mp3
+	got := feedEnclosureFor(item)
+	if got == nil || got.URL != mp3 || got.Type != "audio/mpeg" || got.Length != 4096 {
+		t.Fatalf("enclosure = %+v, want mp3 audio/mpeg", got)
+	}
+
+	opaque := "https://cdn.example.test/content/42/processed"
+	video := models.ContentItem{Type: models.ContentTypeVideo, MediaURL: &opaque}
+	if got := feedEnclosureFor(video); got == nil || got.Type != "video/mp4" {
+		t.Fatalf("extensionless video enclosure = %+v", got)
+	}
+	if got := feedEnclosureFor(models.ContentItem{Type: models.ContentTypeNews, MediaURL: &mp3}); got != nil {
+		t.Fatal("NEWS rows must not syndicate enclosures")
+	}
+}
+
+func TestScopedFeedQueryFiltersEnclosuresBeforeLimit(t *testing.T) {
+	db, _ := newMockGorm(t)
+	dry := db.Session(&gorm.Session{DryRun: true})
+	sql := scopedFeedQuery(dry, feedQuery{Limit: 10, RequireEnclosure: true}).Find(&[]models.ContentItem{}).Statement.SQL.String()
+	where, limit := strings.Index(sql, "playback_type"), strings.Index(sql, "LIMIT")
+	if where < 0 || !strings.Contains(sql, "m3u8") || limit < where {
+		t.Fatalf("podcast query must filter enclosures ahead of the LIMIT, got %s", sql)
+	}
+	plain := scopedFeedQuery(dry, feedQuery{Limit: 10}).Find(&[]models.ContentItem{}).Statement.SQL.String()
+	if strings.Contains(plain, "m3u8") {
+		t.Fatalf("non-podcast formats must not require an enclosure, got %s", plain)
+	}
+}
+
+func TestBuildTranscriptVTTRebasesChildWindow(t *testing.T) {
+	segments := []segmentData{
+		{Start: 0, End: 5, Text: "intro"},
+		{Start: 60, End: 64, Text: "first --> point"},
+		{Start: 70, End: 130, Text: "crosses the end"},
+		{Start: 200, End: 205, Text: "outside"},
+	}
+	got := buildTranscriptVTT(segments, "", 60000, 120000, 0)
+	if !strings.HasPrefix(got, "WEBVTT\n") {
+		t.Fatalf("missing header: %q", got)
+	}
+	if strings.Contains(got, "intro") || strings.Contains(got, "outside") {
+		t.Fatalf("cues outside the chapter window leaked: %q", got)
+	}
+	if !strings.Contains(got, "00:00:00.000 --> 00:00:04.000\nfirst -> point") {
+		t.Fatalf("first cue not rebased/escaped: %q", got)
+	}
+	if !strings.Contains(got, "00:00:10.000 --> 00:01:00.000\ncrosses the end") {
+		t.Fatalf("overflowing cue not clipped: %q", got)
+	}
+
+	fallback := buildTranscriptVTT(nil, "whole\n\ntext", 0, 0, 90000)
+	if !strings.Contains(fallback, "00:00:00.000 --> 00:01:30.000\nwhole text") {
+		t.Fatalf("full-text fallback cue = %q", fallback)
+	}
+}
+
+func TestPodcastDocumentEmitsPodcastTags(t *testing.T) {
+	items := []feedItem{
+		{ID: "ep-1", Title: "Episode", Published: time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC),
+			Enclosure:   &feedEnclosure{URL: "https://cdn.test/ep.mp3", Type: "audio/mpeg", Length: 10},
+			DurationSec: 3725, ImageURL: "https://cdn.test/ep.jpg", Language: "ar", TranscriptID: "t-1", HasChapters: true},
+		{ID: "news-1", Title: "Article"},
+	}
+	meta := feedMeta{Title: "Pods", SelfURL: "http://cms.test/api/v1/feed/podcast.xml", BaseURL: "http://cms.test"}
+	contentType, raw, err := encodeFeedDocument("podcast", meta, items)
+	if err != nil || !strings.HasPrefix(contentType, "application/rss+xml") {
+		t.Fatalf("encode podcast = %q, %v", contentType, err)
+	}
+
+	body := string(raw)
+	for _, want := range []string{
+		`xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd"`,
+		`xmlns:podcast="https://podcastindex.org/namespace/1.0"`,
+		`<enclosure url="https://cdn.test/ep.mp3" length="10" type="audio/mpeg">`,
+		`<itunes:duration>01:02:05</itunes:duration>`,
+		`<language>ar</language>`,
+		`<podcast:transcript url="http://cms.test/api/v1/feed/items/ep-1/transcript.vtt" type="text/vtt" language="ar" rel="captions">`,
+		`<podcast:chapters url="http://cms.test/api/v1/feed/items/ep-1/chapters.json" type="application/json+chapters">`,
+		`<itunes:image href="https://cdn.test/ep.jpg">`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("podcast feed missing %s\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "news-1") {
+		t.Fatal("items without an enclosure must be dropped from podcast feeds")
+	}
+}
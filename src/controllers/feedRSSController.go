@@ -1,9 +1,14 @@
 package controllers
 
 import (
+	"content-management-system/src/metrics"
 	"content-management-system/src/models"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
+	"fmt"
 	"net/http"
 	"os"
 	"strconv"
@@ -11,6 +16,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
 	"gorm.io/gorm"
 )
 
@@ -24,12 +30,29 @@ type feedItem struct {
 	Author      string
 	Published   time.Time
 	Categories  []string
+
+	// Media fields feed the podcast renderer and the RSS/Atom/JSON enclosure
+	// equivalents. Enclosure is nil for NEWS rows and HLS-only media.
+	Enclosure    *feedEnclosure
+	DurationSec  int
+	ImageURL     string
+	Language     string
+	TranscriptID string // active transcript public id, "" = none
+	HasChapters  bool   // published chapter markers exist for the transcript
+	IsChild      bool   // atomized chapter unit; chapter markers are parent-scoped
 }
 
 type feedMeta struct {
 	Title       string
 	Description string
 	SelfURL     string
+	// BaseURL is the public origin used for item-level document links
+	// (transcripts, chapters). HubURL, when set, advertises the WebSub hub.
+	BaseURL string
+	HubURL  string
+	// Updated is the validator's Last-Modified instant. Zero means the feed has
+	// no dated rows yet and renderers fall back to the build time.
+	Updated time.Time
 }
 
 type feedQuery struct {
@@ -38,20 +61,41 @@ type feedQuery struct {
 	Topic       string // legacy free-form tag, or ""
 	ContentType string
 	Limit       int
+	// Saved-feed curation filters (see models.RSSFeed). Empty = unconstrained.
+	StoryIDs          []string
+	SourceIDs         []string
+	Languages         []string
+	Categories        []string
+	Formats           []string
+	MinQuality        *float64
+	ExcludeContentIDs []string
+	ExcludeSourceIDs  []string
+	ExcludeStoryIDs   []string
+	// RequireEnclosure keeps only rows with a progressive media file (the
+	// podcast format), before the LIMIT.
+	RequireEnclosure bool
+	// ContentIDs pins the query to specific items (WebSub content deltas).
+	ContentIDs []string
+	// Since narrows the feed to items published after a poller's last seen
+	// instant so incremental pollers only download the delta.
+	Since *time.Time
 }
 
-// fetchFeedItems pulls READY content for a feed, newest first, normalized into
-// format-agnostic feedItems shared by the RSS/Atom/JSON renderers.
-func fetchFeedItems(db *gorm.DB, q feedQuery) ([]feedItem, error) {
-	limit := q.Limit
-	if limit <= 0 || limit > 200 {
-		limit = 50
+// feedLimit clamps a requested item count to the served window.
+func feedLimit(n int) int {
+	if n <= 0 || n > 200 {
+		return 50
 	}
+	return n
+}
 
+// scopedFeedQuery applies the filters shared by the item fetch and the
+// conditional-GET validator so both always describe the same row set.
+func scopedFeedQuery(db *gorm.DB, q feedQuery) *gorm.DB {
 	query := db.Model(&models.ContentItem{}).
 		Where("status = ?", models.ContentStatusReady).
 		Order("published_at DESC NULLS LAST, created_at DESC").
-		Limit(limit)
+		Limit(feedLimit(q.Limit))
 
 	if q.TenantID != "" {
 		query = query.Where("tenant_id = ?", q.TenantID)
@@ -65,14 +109,92 @@ func fetchFeedItems(db *gorm.DB, q feedQuery) ([]feedItem, error) {
 	if q.Topic != "" {
 		query = query.Where("? = ANY(topic_tags)", q.Topic)
 	}
+	if len(q.StoryIDs) > 0 {
+		query = query.Where("story_id = ANY(?::uuid[])", pq.StringArray(q.StoryIDs))
+	}
+	if len(q.ContentIDs) > 0 {
+		query = query.Where("public_id = ANY(?::uuid[])", pq.StringArray(q.ContentIDs))
+	}
+	if len(q.SourceIDs) > 0 {
+		query = query.Where("content_source_id = ANY(?::uuid[])", pq.StringArray(q.SourceIDs))
+	}
+	if len(q.Languages) > 0 {
+		query = query.Where("content_language = ANY(?)", pq.StringArray(q.Languages))
+	}
+	if len(q.Categories) > 0 {
+		query = query.Where("story_id IN (SELECT public_id FROM stories WHERE category = ANY(?))", pq.StringArray(q.Categories))
+	}
+	if len(q.Formats) > 0 {
+		query = query.Where("type = ? AND format = ANY(?)", models.ContentTypeNews, pq.StringArray(q.Formats))
+	}
+	if q.MinQuality != nil {
+		query = query.Where(`(type = ? OR EXISTS (
+			SELECT 1 FROM media_intelligence_scores mis
+			WHERE mis.content_item_id = content_items.public_id AND mis.value >= ?))`,
+			models.ContentTypeNews, *q.MinQuality)
+	}
+	if len(q.ExcludeContentIDs) > 0 {
+		query = query.Where("public_id <> ALL(?::uuid[])", pq.StringArray(q.ExcludeContentIDs))
+	}
+	if len(q.ExcludeSourceIDs) > 0 {
+		query = query.Where("(content_source_id IS NULL OR content_source_id <> ALL(?::uuid[]))", pq.StringArray(q.ExcludeSourceIDs))
+	}
+	if len(q.ExcludeStoryIDs) > 0 {
+		query = query.Where("(story_id IS NULL OR story_id <> ALL(?::uuid[]))", pq.StringArray(q.ExcludeStoryIDs))
+	}
+	if q.Since != nil {
+		query = query.Where("COALESCE(published_at, created_at) > ?", q.Since.UTC())
+	}
+	if q.RequireEnclosure {
+		query = query.Where(feedEnclosureSQL)
+	}
+	return query
+}
 
+// savedFeedQuery maps a saved feed's persisted filters onto a feedQuery. The
+// legacy single StoryID is folded into the StoryIDs union.
+func savedFeedQuery(feed models.RSSFeed) feedQuery {
+	q := feedQuery{
+		TenantID:          feed.TenantID,
+		ContentType:       feed.ContentType,
+		Limit:             feed.ItemLimit,
+		StoryIDs:          append([]string(nil), feed.StoryIDs...),
+		SourceIDs:         feed.ContentSourceIDs,
+		Languages:         feed.ContentLanguages,
+		Categories:        feed.StoryCategories,
+		Formats:           feed.Formats,
+		MinQuality:        feed.MinQuality,
+		ExcludeContentIDs: feed.ExcludeContentIDs,
+		ExcludeSourceIDs:  feed.ExcludeSourceIDs,
+		ExcludeStoryIDs:   feed.ExcludeStoryIDs,
+	}
+	if feed.StoryID != nil {
+		legacy := feed.StoryID.String()
+		if len(q.StoryIDs) == 0 {
+			q.StoryID = legacy
+		} else if !containsString(q.StoryIDs, legacy) {
+			q.StoryIDs = append(q.StoryIDs, legacy)
+		}
+	}
+	return q
+}
+
+// fetchFeedItems pulls READY content for a feed, newest first, normalized into
+// format-agnostic feedItems shared by the RSS/Atom/JSON renderers.
+func fetchFeedItems(db *gorm.DB, q feedQuery) ([]feedItem, error) {
 	var items []models.ContentItem
-	if err := query.Find(&items).Error; err != nil {
+	if err := scopedFeedQuery(db, q).Find(&items).Error; err != nil {
 		return nil, err
 	}
+	return feedItemsFor(db, items)
+}
 
+// feedItemsFor normalizes content rows into feedItems, keeping their order.
+func feedItemsFor(db *gorm.DB, items []models.ContentItem) ([]feedItem, error) {
 	out := make([]feedItem, 0, len(items))
-	for _, it := range items {
+	var transcripts []transcriptKey
+	keys := make([]transcriptKey, len(items))
+	for i, it := range items {
 		fi := feedItem{ID: it.PublicID.String(), Title: "Untitled", Published: it.CreatedAt}
 		if it.Title != nil && strings.TrimSpace(*it.Title) != "" {
 			fi.Title = *it.Title
@@ -98,8 +220,35 @@ func fetchFeedItems(db *gorm.DB, q feedQuery) ([]feedItem, error) {
 				fi.Categories = append(fi.Categories, t)
 			}
 		}
+		fi.Enclosure = feedEnclosureFor(it)
+		if it.DurationSec != nil && *it.DurationSec > 0 {
+			fi.DurationSec = *it.DurationSec
+		}
+		if it.ThumbnailURL != nil {
+			fi.ImageURL = strings.TrimSpace(*it.ThumbnailURL)
+		}
+		if it.ContentLanguage != nil {
+			fi.Language = *it.ContentLanguage
+		}
+		if it.TranscriptID != nil {
+			fi.TranscriptID = it.TranscriptID.String()
+			keys[i] = transcriptKey{TenantID: it.TenantID, TranscriptID: fi.TranscriptID}
+			if it.ParentContentItemID == nil {
+				transcripts = append(transcripts, keys[i])
+			}
+		}
+		fi.IsChild = it.ParentContentItemID != nil
 		out = append(out, fi)
 	}
+	if len(transcripts) > 0 {
+		chaptered, err := transcriptsWithPublishedChapters(db, transcripts)
+		if err != nil {
+			return nil, err
+		}
+		for i := range out {
+			out[i].HasChapters = !out[i].IsChild && chaptered[keys[i]]
+		}
+	}
 	return out, nil
 }
 
@@ -130,12 +279,32 @@ type rssDocument struct {
 }
 
 type rssChannel struct {
-	Title       string    `xml:"title"`
-	Link        string    `xml:"link"`
-	Description string    `xml:"description"`
-	Language    string    `xml:"language,omitempty"`
-	LastBuild   string    `xml:"lastBuildDate,omitempty"`
-	Items       []rssItem `xml:"item"`
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link"`
+	Description string        `xml:"description"`
+	Language    string        `xml:"language,omitempty"`
+	LastBuild   string        `xml:"lastBuildDate,omitempty"`
+	TTL         int           `xml:"ttl,omitempty"`
+	AtomLinks   []rssAtomLink `xml:"atom:link"`
+	Items       []rssItem     `xml:"item"`
+}
+
+// rssAtomLink is the atom:link extension RSS channels use for rel=self and
+// rel=hub (WebSub discovery).
+type rssAtomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+// rssAtomLinks returns the channel's self link plus the hub link when the
+// feed is WebSub-enabled.
+func (m feedMeta) rssAtomLinks() []rssAtomLink {
+	links := []rssAtomLink{{Href: m.SelfURL, Rel: "self", Type: "application/rss+xml"}}
+	if m.HubURL != "" {
+		links = append(links, rssAtomLink{Href: m.HubURL, Rel: "hub"})
+	}
+	return links
 }
 
 type rssItem struct {
@@ -146,6 +315,13 @@ type rssItem struct {
 	PubDate     string        `xml:"pubDate,omitempty"`
 	Author      string        `xml:"author,omitempty"`
 	Category    []rssCategory `xml:"category,omitempty"`
+	Enclosure   *rssEnclosure `xml:"enclosure,omitempty"`
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Length int64  `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
 }
 
 type rssGUID struct {
@@ -157,13 +333,15 @@ type rssCategory struct {
 	Value string `xml:",chardata"`
 }
 
-func renderRSS(c *gin.Context, meta feedMeta, items []feedItem) {
+func rssDocumentFor(meta feedMeta, items []feedItem) rssDocument {
 	ch := rssChannel{
 		Title:       meta.Title,
 		Link:        meta.SelfURL,
 		Description: meta.Description,
 		Language:    "en",
-		LastBuild:   time.Now().UTC().Format(time.RFC1123Z),
+		LastBuild:   meta.updatedAt().Format(time.RFC1123Z),
+		TTL:         int(feedPollInterval() / time.Minute),
+		AtomLinks:   meta.rssAtomLinks(),
 	}
 	for _, fi := range items {
 		e := rssItem{
@@ -179,11 +357,10 @@ func renderRSS(c *gin.Context, meta feedMeta, items []feedItem) {
 		for _, cat := range fi.Categories {
 			e.Category = append(e.Category, rssCategory{Value: cat})
 		}
+		e.Enclosure = fi.Enclosure.rss()
 		ch.Items = append(ch.Items, e)
 	}
-	doc := rssDocument{Version: "2.0", AtomNS: "http://www.w3.org/2005/Atom", Channel: ch}
-	c.Header("Content-Type", "application/rss+xml; charset=utf-8")
-	c.XML(http.StatusOK, doc)
+	return rssDocument{Version: "2.0", AtomNS: "http://www.w3.org/2005/Atom", Channel: ch}
 }
 
 // ─── Atom 1.0 ───────────────────────────────────────────────
@@ -200,8 +377,10 @@ type atomFeed struct {
 }
 
 type atomLink struct {
-	Href string `xml:"href,attr"`
-	Rel  string `xml:"rel,attr,omitempty"`
+	Href   string `xml:"href,attr"`
+	Rel    string `xml:"rel,attr,omitempty"`
+	Type   string `xml:"type,attr,omitempty"`
+	Length int64  `xml:"length,attr,omitempty"`
 }
 
 type atomEntry struct {
@@ -209,7 +388,7 @@ type atomEntry struct {
 	ID        string      `xml:"id"`
 	Updated   string      `xml:"updated"`
 	Published string      `xml:"published,omitempty"`
-	Link      atomLink    `xml:"link"`
+	Links     []atomLink  `xml:"link"`
 	Summary   string      `xml:"summary,omitempty"`
 	Author    *atomAuthor `xml:"author,omitempty"`
 }
@@ -218,31 +397,36 @@ type atomAuthor struct {
 	Name string `xml:"name"`
 }
 
-func renderAtom(c *gin.Context, meta feedMeta, items []feedItem) {
+func atomFeedFor(meta feedMeta, items []feedItem) atomFeed {
 	f := atomFeed{
 		Xmlns:    "http://www.w3.org/2005/Atom",
 		Title:    meta.Title,
 		Subtitle: meta.Description,
 		ID:       meta.SelfURL,
-		Updated:  time.Now().UTC().Format(time.RFC3339),
+		Updated:  meta.updatedAt().Format(time.RFC3339),
 		Links:    []atomLink{{Href: meta.SelfURL, Rel: "self"}},
 	}
+	if meta.HubURL != "" {
+		f.Links = append(f.Links, atomLink{Href: meta.HubURL, Rel: "hub"})
+	}
 	for _, fi := range items {
 		e := atomEntry{
 			Title:     fi.Title,
 			ID:        "urn:uuid:" + fi.ID,
 			Updated:   fi.Published.UTC().Format(time.RFC3339),
 			Published: fi.Published.UTC().Format(time.RFC3339),
-			Link:      atomLink{Href: fi.Link},
+			Links:     []atomLink{{Href: fi.Link}},
 			Summary:   fi.Description,
 		}
+		if fi.Enclosure != nil {
+			e.Links = append(e.Links, atomLink{Href: fi.Enclosure.URL, Rel: "enclosure", Type: fi.Enclosure.Type, Length: fi.Enclosure.Length})
+		}
 		if fi.Author != "" {
 			e.Author = &atomAuthor{Name: fi.Author}
 		}
 		f.Entries = append(f.Entries, e)
 	}
-	c.Header("Content-Type", "application/atom+xml; charset=utf-8")
-	c.XML(http.StatusOK, f)
+	return f
 }
 
 // ─── JSON Feed 1.1 ──────────────────────────────────────────
@@ -252,9 +436,15 @@ type jsonFeed struct {
 	Title       string         `json:"title"`
 	Description string         `json:"description,omitempty"`
 	FeedURL     string         `json:"feed_url,omitempty"`
+	Hubs        []jsonFeedHub  `json:"hubs,omitempty"`
 	Items       []jsonFeedItem `json:"items"`
 }
 
+type jsonFeedHub struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
 type jsonFeedItem struct {
 	ID            string           `json:"id"`
 	URL           string           `json:"url,omitempty"`
@@ -263,13 +453,22 @@ type jsonFeedItem struct {
 	DatePublished string           `json:"date_published,omitempty"`
 	Authors       []jsonFeedAuthor `json:"authors,omitempty"`
 	Tags          []string         `json:"tags,omitempty"`
+	Image         string           `json:"image,omitempty"`
+	Attachments   []jsonFeedAttach `json:"attachments,omitempty"`
+}
+
+type jsonFeedAttach struct {
+	URL               string `json:"url"`
+	MimeType          string `json:"mime_type"`
+	SizeInBytes       int64  `json:"size_in_bytes,omitempty"`
+	DurationInSeconds int    `json:"duration_in_seconds,omitempty"`
 }
 
 type jsonFeedAuthor struct {
 	Name string `json:"name"`
 }
 
-func renderJSONFeed(c *gin.Context, meta feedMeta, items []feedItem) {
+func jsonFeedFor(meta feedMeta, items []feedItem) jsonFeed {
 	jf := jsonFeed{
 		Version:     "https://jsonfeed.org/version/1.1",
 		Title:       meta.Title,
@@ -277,6 +476,9 @@ func renderJSONFeed(c *gin.Context, meta feedMeta, items []feedItem) {
 		FeedURL:     meta.SelfURL,
 		Items:       make([]jsonFeedItem, 0, len(items)),
 	}
+	if meta.HubURL != "" {
+		jf.Hubs = []jsonFeedHub{{Type: "WebSub", URL: meta.HubURL}}
+	}
 	for _, fi := range items {
 		ji := jsonFeedItem{
 			ID:            fi.ID,
@@ -291,20 +493,295 @@ func renderJSONFeed(c *gin.Context, meta feedMeta, items []feedItem) {
 		if len(fi.Categories) > 0 {
 			ji.Tags = fi.Categories
 		}
+		ji.Image = fi.ImageURL
+		if fi.Enclosure != nil {
+			ji.Attachments = []jsonFeedAttach{{URL: fi.Enclosure.URL, MimeType: fi.Enclosure.Type, SizeInBytes: fi.Enclosure.Length, DurationInSeconds: fi.DurationSec}}
+		}
 		jf.Items = append(jf.Items, ji)
 	}
-	body, err := json.Marshal(jf)
+	return jf
+}
+
+// ─── Encoding ───────────────────────────────────────────────
+
+// encodeFeedDocument renders one syndication format to bytes. It is shared by
+// the HTTP handlers and the WebSub hub, which pushes the same representation
+// a subscriber would have fetched from the topic URL.
+func encodeFeedDocument(format string, meta feedMeta, items []feedItem) (string, []byte, error) {
+	var (
+		contentType string
+		doc         interface{}
+	)
+	switch format {
+	case "json":
+		body, err := json.Marshal(jsonFeedFor(meta, items))
+		return "application/feed+json; charset=utf-8", body, err
+	case "atom":
+		contentType, doc = "application/atom+xml; charset=utf-8", atomFeedFor(meta, items)
+	case "podcast":
+		contentType, doc = "application/rss+xml; charset=utf-8", podcastDocumentFor(meta, items)
+	default:
+		contentType, doc = "application/rss+xml; charset=utf-8", rssDocumentFor(meta, items)
+	}
+	body, err := xml.Marshal(doc)
+	if err != nil {
+		return "", nil, err
+	}
+	return contentType, append([]byte(xml.Header), body...), nil
+}
+
+func writeFeed(c *gin.Context, format string, meta feedMeta, items []feedItem) {
+	contentType, body, err := encodeFeedDocument(format, meta, items)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": "failed to build feed"})
 		return
 	}
-	c.Data(http.StatusOK, "application/feed+json; charset=utf-8", body)
+	c.Data(http.StatusOK, contentType, body)
+}
+
+// ─── Conditional GET ────────────────────────────────────────
+
+// feedPollInterval is the polling hint advertised to aggregators through
+// Cache-Control, Retry-After and the RSS <ttl>. FEED_POLL_INTERVAL_SECONDS
+// overrides the one-minute default; values under 15s are clamped.
+func feedPollInterval() time.Duration {
+	seconds := 60
+	if raw := strings.TrimSpace(os.Getenv("FEED_POLL_INTERVAL_SECONDS")); raw != "" {
+		if n, err := strconv.Atoi(raw); err == nil {
+			seconds = n
+		}
+	}
+	if seconds < 15 {
+		seconds = 15
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func (m feedMeta) updatedAt() time.Time {
+	if m.Updated.IsZero() {
+		return time.Now().UTC()
+	}
+	return m.Updated.UTC()
+}
+
+// feedValidator is the HTTP validator pair for one feed representation.
+type feedValidator struct {
+	ETag         string
+	LastModified time.Time
+}
+
+// feedRevisionRow is everything a rendered item depends on: the content row,
+// its published chapter markers (podcast:chapters) and its source.
+type feedRevisionRow struct {
+	PublicID          string
+	UpdatedAt         time.Time
+	ChaptersUpdatedAt *time.Time
+	PublishedChapters int
+	SourceUpdatedAt   *time.Time
+}
+
+// feedRevisionColumns selects feedRevisionRow alongside the scoped item query.
+const feedRevisionColumns = `content_items.public_id::text AS public_id, content_items.updated_at,
+	(SELECT MAX(ch.updated_at) FROM chapters ch
+		WHERE ch.tenant_id = content_items.tenant_id AND ch.transcript_id = content_items.transcript_id AND ch.status = ?) AS chapters_updated_at,
+	(SELECT COUNT(*) FROM chapters ch
+		WHERE ch.tenant_id = content_items.tenant_id AND ch.transcript_id = content_items.transcript_id AND ch.status = ?) AS published_chapters,
+	(SELECT cs.updated_at FROM content_sources cs
+		WHERE cs.public_id = content_items.content_source_id) AS source_updated_at`
+
+// computeFeedValidator derives a strong validator without loading item bodies:
+// it hashes the identity and update time of exactly the rows the feed would
+// render (including their chapter markers and source), the saved-feed row
+// (when present) and the representation variant (format + presentation), so
+// any edit that changes the document changes the ETag while identical polls
+// hash identically. Last-Modified also honours the feed's membership mark, so
+// an item leaving the feed moves it on every replica.
+func computeFeedValidator(db *gorm.DB, q feedQuery, variant string, saved *models.RSSFeed) (feedValidator, error) {
+	var rows []feedRevisionRow
+	if err := scopedFeedQuery(db, q).
+		Select(feedRevisionColumns, chapterStatusPublished, chapterStatusPublished).
+		Scan(&rows).Error; err != nil {
+		return feedValidator{}, err
+	}
+	validator := buildFeedValidator(rows, variant, saved)
+	if q.Since != nil {
+		// Since windows are deltas keyed by each poller's own instant; an
+		// item leaving one cannot be expressed, and marking them would grow
+		// a row per poll.
+		return validator, nil
+	}
+	ids := make([]string, len(rows))
+	for i, row := range rows {
+		ids[i] = row.PublicID
+	}
+	return markFeedMembership(db, feedMembershipKey(q), ids, validator)
+}
+
+func buildFeedValidator(rows []feedRevisionRow, variant string, saved *models.RSSFeed) feedValidator {
+	h := sha256.New()
+	h.Write([]byte(variant))
+	var lastModified time.Time
+	if saved != nil {
+		fmt.Fprintf(h, "\nfeed:%s:%d", saved.PublicID, saved.UpdatedAt.UnixNano())
+		lastModified = saved.UpdatedAt
+	}
+	touch := func(t time.Time) {
+		if t.After(lastModified) {
+			lastModified = t
+		}
+	}
+	for _, row := range rows {
+		fmt.Fprintf(h, "\nitem:%s:%d", row.PublicID, row.UpdatedAt.UnixNano())
+		touch(row.UpdatedAt)
+		if row.ChaptersUpdatedAt != nil {
+			fmt.Fprintf(h, ":chapters:%d:%d", row.PublishedChapters, row.ChaptersUpdatedAt.UnixNano())
+			touch(*row.ChaptersUpdatedAt)
+		}
+		if row.SourceUpdatedAt != nil {
+			fmt.Fprintf(h, ":source:%d", row.SourceUpdatedAt.UnixNano())
+			touch(*row.SourceUpdatedAt)
+		}
+	}
+	v := feedValidator{ETag: `"` + hex.EncodeToString(h.Sum(nil))[:32] + `"`}
+	if !lastModified.IsZero() {
+		v.LastModified = lastModified.UTC().Truncate(time.Second)
+	}
+	return v
+}
+
+// feedMembershipKey names a feed by its normalized query (limit clamped, as
+// served), so parameter order, presentation-only params and unknown params
+// all resolve to the same mark.
+func feedMembershipKey(q feedQuery) string {
+	q.Limit = feedLimit(q.Limit)
+	raw, _ := json.Marshal(q)
+	sum := sha256.Sum256(append([]byte("feed\n"), raw...))
+	return hex.EncodeToString(sum[:])
+}
+
+// feedMembershipDigest hashes the identities of the rendered items, in order.
+func feedMembershipDigest(ids []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(ids, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// markFeedMembership keeps Last-Modified moving when an item leaves a feed.
+// Row timestamps only cover the rows still rendered, so an unpublished or
+// deleted item would leave Last-Modified where it was and If-Modified-Since
+// pollers would keep the stale item. The item set's digest and the moment it
+// last changed live in feed_membership_marks, so every replica (and every
+// restart) answers the same Last-Modified: a new mark starts at the row-derived
+// value, and a changed set moves it to the database clock once — the
+// conditional upsert only lets the first replica that sees the change write.
+// Empty feeds are not marked, so junk filters leave no rows behind.
+func markFeedMembership(db *gorm.DB, key string, ids []string, v feedValidator) (feedValidator, error) {
+	if len(ids) == 0 {
+		return v, nil
+	}
+	digest := feedMembershipDigest(ids)
+	var mark models.FeedMembershipMark
+	err := db.Where("feed_key = ?", key).Take(&mark).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return v, err
+	}
+	if err != nil || mark.Digest != digest {
+		if err := db.Exec(`INSERT INTO feed_membership_marks (feed_key, digest, changed_at) VALUES (?, ?, ?)
+			ON CONFLICT (feed_key) DO UPDATE SET digest = EXCLUDED.digest,
+				changed_at = GREATEST(now(), feed_membership_marks.changed_at)
+			WHERE feed_membership_marks.digest <> EXCLUDED.digest`, key, digest, v.LastModified).Error; err != nil {
+			return v, err
+		}
+		if err := db.Where("feed_key = ?", key).Take(&mark).Error; err != nil {
+			return v, err
+		}
+	}
+	if changed := mark.ChangedAt.UTC().Truncate(time.Second); changed.After(v.LastModified) {
+		v.LastModified = changed
+	}
+	return v, nil
+}
+
+// writeFeedValidator sets the caching headers on every feed response and
+// answers 304 when the client's validators still match. If-None-Match takes
+// precedence over If-Modified-Since (RFC 9110 §13.2.2). Returns true when the
+// response has been completed.
+func writeFeedValidator(c *gin.Context, v feedValidator) bool {
+	poll := feedPollInterval()
+	c.Header("ETag", v.ETag)
+	if !v.LastModified.IsZero() {
+		c.Header("Last-Modified", v.LastModified.Format(http.TimeFormat))
+	}
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d, must-revalidate", int(poll.Seconds())))
+	c.Header("Retry-After", strconv.Itoa(int(poll.Seconds())))
+
+	if inm := c.GetHeader("If-None-Match"); inm != "" {
+		if !etagListMatches(inm, v.ETag) {
+			return false
+		}
+	} else if ims := c.GetHeader("If-Modified-Since"); ims != "" && !v.LastModified.IsZero() {
+		since, err := http.ParseTime(ims)
+		if err != nil || v.LastModified.After(since) {
+			return false
+		}
+	} else {
+		return false
+	}
+	c.Status(http.StatusNotModified)
+	c.Writer.WriteHeaderNow()
+	return true
+}
+
+// etagListMatches applies the weak comparison GET requires for If-None-Match.
+func etagListMatches(header, etag string) bool {
+	want := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == want {
+			return true
+		}
+	}
+	return false
+}
+
+// parseFeedSince reads the optional ?since= incremental-polling bound.
+func parseFeedSince(raw string) (*time.Time, bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, true
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, false
+	}
+	return &t, true
+}
+
+// serveFeed runs the shared validate → 304 | fetch → render sequence.
+func serveFeed(c *gin.Context, db *gorm.DB, q feedQuery, meta feedMeta, format, variant string, saved *models.RSSFeed) {
+	q.RequireEnclosure = format == "podcast"
+	validator, err := computeFeedValidator(db, q, variant, saved)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "failed to build feed"})
+		return
+	}
+	if writeFeedValidator(c, validator) {
+		return
+	}
+	items, err := fetchFeedItems(db, q)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "failed to build feed"})
+		return
+	}
+	meta.Updated = validator.LastModified
+	writeFeed(c, format, meta, items)
+	metrics.FeedServed(format, len(items))
 }
 
 // ─── Ad-hoc public feeds (power per-topic feeds) ────────────
 
-// adhocFeedData builds items + meta from query params shared by all 3 formats.
-func adhocFeedData(c *gin.Context) ([]feedItem, feedMeta, bool) {
+// adhocFeedRequest builds the query + meta from query params shared by all 3
+// formats. Items are fetched later, only when the validator misses.
+func adhocFeedRequest(c *gin.Context) (feedQuery, feedMeta, bool) {
 	db := c.MustGet("db").(*gorm.DB)
 
 	q := feedQuery{
@@ -317,12 +794,12 @@ func adhocFeedData(c *gin.Context) ([]feedItem, feedMeta, bool) {
 			q.Limit = n
 		}
 	}
-
-	items, err := fetchFeedItems(db, q)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"message": "failed to build feed"})
-		return nil, feedMeta{}, false
+	since, ok := parseFeedSince(c.Query("since"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "since must be an RFC3339 timestamp"})
+		return feedQuery{}, feedMeta{}, false
 	}
+	q.Since = since
 
 	title := strings.TrimSpace(c.Query("title"))
 	if title == "" {
@@ -336,43 +813,46 @@ func adhocFeedData(c *gin.Context) ([]feedItem, feedMeta, bool) {
 		}
 	}
 
-	return items, feedMeta{
+	return q, feedMeta{
 		Title:       title,
 		Description: "Latest published content from the Wahb platform.",
 		SelfURL:     selfURL(c),
+		BaseURL:     publicBaseURL(c),
 	}, true
 }
 
-// GetRSSFeed handles GET /api/v1/feed/rss.xml?story_id=&type=&limit=&title=
-func GetRSSFeed(c *gin.Context) {
-	items, meta, ok := adhocFeedData(c)
+func serveAdhocFeed(c *gin.Context, format string) {
+	q, meta, ok := adhocFeedRequest(c)
 	if !ok {
 		return
 	}
-	renderRSS(c, meta, items)
+	variant := format + "\n" + meta.Title + "\n" + c.Request.URL.RawQuery
+	serveFeed(c, c.MustGet("db").(*gorm.DB), q, meta, format, variant, nil)
+}
+
+// GetRSSFeed handles GET /api/v1/feed/rss.xml?story_id=&type=&limit=&title=&since=
+func GetRSSFeed(c *gin.Context) {
+	serveAdhocFeed(c, "rss")
 }
 
 // GetAtomFeed handles GET /api/v1/feed/atom.xml (same params as RSS).
 func GetAtomFeed(c *gin.Context) {
-	items, meta, ok := adhocFeedData(c)
-	if !ok {
-		return
-	}
-	renderAtom(c, meta, items)
+	serveAdhocFeed(c, "atom")
 }
 
 // GetJSONFeed handles GET /api/v1/feed/feed.json (same params as RSS).
 func GetJSONFeed(c *gin.Context) {
-	items, meta, ok := adhocFeedData(c)
-	if !ok {
-		return
-	}
-	renderJSONFeed(c, meta, items)
+	serveAdhocFeed(c, "json")
+}
+
+// GetPodcastFeed handles GET /api/v1/feed/podcast.xml (same params as RSS).
+func GetPodcastFeed(c *gin.Context) {
+	serveAdhocFeed(c, "podcast")
 }
 
 // ─── Saved feeds ────────────────────────────────────────────
 
-// GetSavedFeed handles GET /api/v1/feed/saved/:slug?format=rss|atom|json.
+// GetSavedFeed handles GET /api/v1/feed/saved/:slug?format=rss|atom|json|podcast&since=.
 func GetSavedFeed(c *gin.Context) {
 	db := c.MustGet("db").(*gorm.DB)
 	slug := strings.TrimSpace(c.Param("slug"))
@@ -383,33 +863,55 @@ func GetSavedFeed(c *gin.Context) {
 		return
 	}
 
-	q := feedQuery{
-		TenantID:    feed.TenantID,
-		ContentType: feed.ContentType,
-		Limit:       feed.ItemLimit,
+	q := savedFeedQuery(feed)
+	since, ok := parseFeedSince(c.Query("since"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "since must be an RFC3339 timestamp"})
+		return
 	}
-	if feed.StoryID != nil {
-		q.StoryID = feed.StoryID.String()
+	q.Since = since
+
+	format := savedFeedFormat(c.Query("format"))
+	base := publicBaseURL(c)
+	meta := savedFeedMeta(feed, base, format)
+
+	// WebSub discovery (W3C WebSub §4): the topic is the canonical self URL,
+	// without incremental-polling params.
+	c.Header("Link", fmt.Sprintf(`<%s>; rel="hub", <%s>; rel="self"`, meta.HubURL, meta.SelfURL))
+	variant := format + "\n" + meta.SelfURL
+	serveFeed(c, db, q, meta, format, variant, &feed)
+}
+
+// savedFeedFormat normalizes ?format= to one of the served representations.
+func savedFeedFormat(raw string) string {
+	switch format := strings.ToLower(strings.TrimSpace(raw)); format {
+	case "atom", "json", "podcast":
+		return format
+	default:
+		return "rss"
 	}
+}
 
-	items, err := fetchFeedItems(db, q)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"message": "failed to build feed"})
-		return
+// savedFeedTopicURL is the canonical URL of one saved-feed representation and
+// the WebSub topic subscribers register for.
+func savedFeedTopicURL(base, slug, format string) string {
+	topic := base + "/api/v1/feed/saved/" + slug
+	if format != "rss" {
+		topic += "?format=" + format
 	}
+	return topic
+}
 
+func savedFeedMeta(feed models.RSSFeed, base, format string) feedMeta {
 	title := strings.TrimSpace(feed.Title)
 	if title == "" {
 		title = feed.Name
 	}
-	meta := feedMeta{Title: title, Description: feed.Description, SelfURL: selfURL(c)}
-
-	switch strings.ToLower(strings.TrimSpace(c.Query("format"))) {
-	case "atom":
-		renderAtom(c, meta, items)
-	case "json":
-		renderJSONFeed(c, meta, items)
-	default:
-		renderRSS(c, meta, items)
+	return feedMeta{
+		Title:       title,
+		Description: feed.Description,
+		SelfURL:     savedFeedTopicURL(base, feed.Slug, format),
+		BaseURL:     base,
+		HubURL:      base + webSubHubPath,
 	}
 }
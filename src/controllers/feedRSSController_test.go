@@ -0,0 +1,229 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"content-management-system/src/models"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestBuildFeedValidatorTracksRowsFeedAndVariant(t *testing.T) {
+	older := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+	newer := older.Add(90 * time.Minute)
+	rows := []feedRevisionRow{{PublicID: "a", UpdatedAt: older}, {PublicID: "b", UpdatedAt: newer}}
+	feed := &models.RSSFeed{PublicID: uuid.New(), UpdatedAt: older}
+
+	base := buildFeedValidator(rows, "rss", feed)
+	if again := buildFeedValidator(rows, "rss", feed); again.ETag != base.ETag {
+		t.Fatalf("identical inputs produced different ETags: %s vs %s", base.ETag, again.ETag)
+	}
+	if !base.LastModified.Equal(newer) {
+		t.Fatalf("Last-Modified = %s, want newest row %s", base.LastModified, newer)
+	}
+	if buildFeedValidator(rows, "atom", feed).ETag == base.ETag {
+		t.Fatal("format variant must change the ETag")
+	}
+	edited := *feed
+	edited.UpdatedAt = newer.Add(time.Minute)
+	if buildFeedValidator(rows, "rss", &edited).ETag == base.ETag {
+		t.Fatal("saved-feed edit must change the ETag")
+	}
+	touched := []feedRevisionRow{rows[0], {PublicID: "b", UpdatedAt: newer.Add(time.Second)}}
+	if buildFeedValidator(touched, "rss", feed).ETag == base.ETag {
+		t.Fatal("item update must change the ETag")
+	}
+	chapterEdit := newer.Add(time.Hour)
+	chaptered := []feedRevisionRow{rows[0], {PublicID: "b", UpdatedAt: newer, ChaptersUpdatedAt: &chapterEdit, PublishedChapters: 3}}
+	withChapters := buildFeedValidator(chaptered, "rss", feed)
+	if withChapters.ETag == base.ETag {
+		t.Fatal("chapter publish must change the ETag")
+	}
+	if !withChapters.LastModified.Equal(chapterEdit) {
+		t.Fatalf("Last-Modified = %s, want chapter edit %s", withChapters.LastModified, chapterEdit)
+	}
+	chaptered[1].PublishedChapters = 2
+	if buildFeedValidator(chaptered, "rss", feed).ETag == withChapters.ETag {
+		t.Fatal("chapter removal must change the ETag")
+	}
+	sourceEdit := newer.Add(2 * time.Hour)
+	sourced := []feedRevisionRow{rows[0], {PublicID: "b", UpdatedAt: newer, SourceUpdatedAt: &sourceEdit}}
+	if v := buildFeedValidator(sourced, "rss", feed); v.ETag == base.ETag || !v.LastModified.Equal(sourceEdit) {
+		t.Fatal("source edit must change the ETag and Last-Modified")
+	}
+}
+
+func TestWriteFeedValidatorAnswersNotModified(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	modified := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+	v := feedValidator{ETag: `"abc"`, LastModified: modified}
+
+	cases := []struct {
+		name   string
+		header map[string]string
+		want   bool
+	}{
+		{"no validators", nil, false},
+		{"etag match", map[string]string{"If-None-Match": `"zzz", W/"abc"`}, true},
+		{"etag wildcard", map[string]string{"If-None-Match": "*"}, true},
+		{"etag miss ignores ims", map[string]string{"If-None-Match": `"zzz"`, "If-Modified-Since": modified.Format(http.TimeFormat)}, false},
+		{"ims current", map[string]string{"If-Modified-Since": modified.Format(http.TimeFormat)}, true},
+		{"ims stale", map[string]string{"If-Modified-Since": modified.Add(-time.Hour).Format(http.TimeFormat)}, false},
+		{"ims malformed", map[string]string{"If-Modified-Since": "yesterday"}, false},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/feed/saved/x", nil)
+		for k, val := range tc.header {
+			c.Request.Header.Set(k, val)
+		}
+		if got := writeFeedValidator(c, v); got != tc.want {
+			t.Fatalf("%s: notModified = %v, want %v", tc.name, got, tc.want)
+		}
+		if tc.want && w.Code != http.StatusNotModified {
+			t.Fatalf("%s: status = %d, want 304", tc.name, w.Code)
+		}
+		if w.Header().Get("ETag") != `"abc"` || w.Header().Get("Cache-Control") == "" || w.Header().Get("Retry-After") == "" {
+			t.Fatalf("%s: caching headers missing: %v", tc.name, w.Header())
+		}
+	}
+}
+
+// replicaMarkFeed runs markFeedMembership the way a separate CMS process would:
+// on its own connection, with nothing but the shared feed_membership_marks
+// table (simulated by table, with the upsert's semantics) in common.
+func replicaMarkFeed(t *testing.T, table map[string]models.FeedMembershipMark, dbNow time.Time, key string, rows []feedRevisionRow) feedValidator {
+	t.Helper()
+	db, mock := newMockGorm(t)
+	ids := make([]string, len(rows))
+	for i, row := range rows {
+		ids[i] = row.PublicID
+	}
+	built := buildFeedValidator(rows, "rss", nil)
+	digest := feedMembershipDigest(ids)
+	cols := []string{"feed_key", "digest", "changed_at"}
+	selectSQL := `SELECT \* FROM "feed_membership_marks" WHERE feed_key = \$1`
+	markRows := func() *sqlmock.Rows {
+		found := sqlmock.NewRows(cols)
+		if mark, ok := table[key]; ok {
+			found.AddRow(mark.FeedKey, mark.Digest, mark.ChangedAt)
+		}
+		return found
+	}
+
+	mock.ExpectQuery(selectSQL).WillReturnRows(markRows())
+	if mark, ok := table[key]; !ok || mark.Digest != digest {
+		mock.ExpectExec(`INSERT INTO feed_membership_marks`).WillReturnResult(sqlmock.NewResult(0, 1))
+		changed := built.LastModified
+		if ok {
+			changed = dbNow
+			if mark.ChangedAt.After(changed) {
+				changed = mark.ChangedAt
+			}
+		}
+		table[key] = models.FeedMembershipMark{FeedKey: key, Digest: digest, ChangedAt: changed}
+		mock.ExpectQuery(selectSQL).WillReturnRows(markRows())
+	}
+
+	v, err := markFeedMembership(db, key, ids, built)
+	if err != nil {
+		t.Fatalf("markFeedMembership: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sql expectations: %v", err)
+	}
+	return v
+}
+
+func TestMarkFeedMembershipAgreesAcrossReplicas(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	older := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+	rows := []feedRevisionRow{{PublicID: "a", UpdatedAt: older}, {PublicID: "b", UpdatedAt: newer}}
+	table := map[string]models.FeedMembershipMark{}
+	key := feedMembershipKey(feedQuery{StoryID: uuid.NewString()})
+
+	first := replicaMarkFeed(t, table, newer.Add(time.Hour), key, rows)
+	if !first.LastModified.Equal(newer) {
+		t.Fatalf("Last-Modified = %s, want newest row %s", first.LastModified, newer)
+	}
+	// A second process (or a restart) must not reset the mark to its own clock.
+	if other := replicaMarkFeed(t, table, newer.Add(5*time.Hour), key, rows); !other.LastModified.Equal(first.LastModified) {
+		t.Fatalf("replicas disagree on Last-Modified: %s vs %s", first.LastModified, other.LastModified)
+	}
+
+	// Removing the newest item leaves no row newer than the client's copy.
+	removedAt := newer.Add(2 * time.Hour)
+	removed := replicaMarkFeed(t, table, removedAt, key, rows[:1])
+	if !removed.LastModified.Equal(removedAt) {
+		t.Fatalf("Last-Modified after removal = %s, want %s", removed.LastModified, removedAt)
+	}
+	other := replicaMarkFeed(t, table, removedAt.Add(time.Hour), key, rows[:1])
+	if !other.LastModified.Equal(removed.LastModified) {
+		t.Fatalf("replicas disagree after removal: %s vs %s", removed.LastModified, other.LastModified)
+	}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/feed/rss.xml", nil)
+	c.Request.Header.Set("If-Modified-Since", first.LastModified.Format(http.TimeFormat))
+	if writeFeedValidator(c, other) {
+		t.Fatal("If-Modified-Since poller got 304 after an item was removed")
+	}
+}
+
+func TestFeedMembershipKeyNormalizesQuery(t *testing.T) {
+	story := uuid.NewString()
+	base := feedMembershipKey(feedQuery{StoryID: story})
+	if feedMembershipKey(feedQuery{StoryID: story, Limit: 50}) != base || feedMembershipKey(feedQuery{StoryID: story, Limit: 1000}) != base {
+		t.Fatal("limits that serve the same window must share a key")
+	}
+	if feedMembershipKey(feedQuery{StoryID: story, Limit: 10}) == base {
+		t.Fatal("a different window must get its own key")
+	}
+	if feedMembershipKey(feedQuery{StoryID: story, RequireEnclosure: true}) == base {
+		t.Fatal("the podcast scope must get its own key")
+	}
+	if feedMembershipKey(feedQuery{StoryID: uuid.NewString()}) == base {
+		t.Fatal("a different story must get its own key")
+	}
+}
+
+func TestParseFeedSince(t *testing.T) {
+	if since, ok := parseFeedSince(""); !ok || since != nil {
+		t.Fatalf("empty since = %v, %v", since, ok)
+	}
+	if since, ok := parseFeedSince("2026-05-01T10:00:00Z"); !ok || since == nil {
+		t.Fatalf("valid since rejected")
+	}
+	if _, ok := parseFeedSince("last tuesday"); ok {
+		t.Fatal("malformed since accepted")
+	}
+}
+
+func TestSavedFeedQueryFoldsLegacyStoryIntoUnion(t *testing.T) {
+	legacy := uuid.New()
+	other := uuid.New().String()
+	feed := models.RSSFeed{TenantID: "t1", StoryID: &legacy, Formats: []string{"ARTICLE"}}
+
+	q := savedFeedQuery(feed)
+	if q.StoryID != legacy.String() || len(q.StoryIDs) != 0 {
+		t.Fatalf("legacy-only feed = %+v, want single story filter", q)
+	}
+
+	feed.StoryIDs = []string{other}
+	q = savedFeedQuery(feed)
+	if q.StoryID != "" || len(q.StoryIDs) != 2 || q.StoryIDs[1] != legacy.String() {
+		t.Fatalf("union feed = %+v, want both stories", q)
+	}
+	if len(feed.StoryIDs) != 1 {
+		t.Fatal("savedFeedQuery must not mutate the feed row")
+	}
+	if q.TenantID != "t1" || len(q.Formats) != 1 {
+		t.Fatalf("filters not carried: %+v", q)
+	}
+}
@@ -1766,12 +1766,11 @@ func ExecuteFeedRecoveryRun(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "recovery plan not found"})
 		return
 	}
-	secret, err := utils.GetJWTSecret()
-	if err != nil {
+	proof, err := utils.VerifyFeedRecoveryReauthProof(c.Request.Context(), strings.TrimSpace(req.ReauthProof))
+	if err != nil && err != utils.ErrTokenInvalid && err != utils.ErrTokenExpired {
 		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "execution re-auth unavailable"})
 		return
 	}
-	proof, err := utils.ParseFeedRecoveryReauthProof(strings.TrimSpace(req.ReauthProof), secret)
 	if err != nil || proof.UserID != principal.UserID || proof.TenantID != principal.TenantID || proof.PlanID != plan.PublicID.String() || proof.ManifestHash != plan.ManifestHash {
 		c.JSON(http.StatusForbidden, gin.H{"error": "fresh execution confirmation does not match this plan"})
 		return
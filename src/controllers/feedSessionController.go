@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"content-management-system/src/feedcontract"
+	"content-management-system/src/metrics"
 	"content-management-system/src/models"
 	"content-management-system/src/utils"
 	"encoding/base64"
@@ -250,6 +251,7 @@ func GetPodsFeedSessionPage(c *gin.Context) {
 	}
 	page, nextOffset := visibleFrozenPodsPage(db, tenantID, items, offset, frozenSessionLimit(c))
 	c.JSON(http.StatusOK, frozenPodsSessionResponse{SessionID: session.ID.String(), ExpiresAt: session.ExpiresAt, Cursor: frozenSessionCursor(nextOffset, len(items)), Items: page, CaughtUp: offset >= len(items)})
+	metrics.FeedServed("pods_session", len(page))
 }
 
 // GetPodsFeedSessionFreshness reports whether the current policy-selected
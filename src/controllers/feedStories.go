@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"bytes"
+	"content-management-system/src/lifecycle"
 	"encoding/json"
 	"log"
 	"math"
@@ -270,7 +271,7 @@ func assembleStoryNewsFeed(
 	contentIDs := extractPublicIDs(members)
 	flagMap := LoadContentFlags(db, tenantID, contentIDs)
 	velocityData := LoadVelocityData(db, contentIDs, config.VelocityWindowHours, now)
-	scored := ScoreItems(members, config, flagMap, velocityData, now)
+	scored := ScoreItems(members, config, flagMap, velocityData, nil, now)
 
 	// 2. Aggregate scored members into stories. Story momentum = max member
 	//    score; we also track the newest-member time for the cursor.
@@ -1574,7 +1575,7 @@ func refreshStorySummary(db *gorm.DB, tenantID string, storyID uuid.UUID) {
 		return
 	}
 
-	summary, bullets, category, err := generateStorySummaryViaEnrichment(texts)
+	summary, bullets, category, err := generateStorySummaryViaEnrichment(lifecycle.Root(), texts)
 	if err != nil || len(bullets) == 0 {
 		return // best-effort — keep the previous digest (or none)
 	}
@@ -1592,6 +1593,25 @@ func refreshStorySummary(db *gorm.DB, tenantID string, storyID uuid.UUID) {
 			"summary_built_at": now,
 			"category":         normalizeStoryCategory(category),
 		})
+	recordStorySummaryRevision(db, storyID, summary, bulletsJSON, normalizeStoryCategory(category), topic.ArticleCount)
+}
+
+// recordStorySummaryRevision appends the digest to the story's history when it
+// differs from the last recorded one, so regenerations that restate the same
+// digest do not clutter the timeline. Best-effort like the digest itself.
+func recordStorySummaryRevision(db *gorm.DB, storyID uuid.UUID, summary string, bulletsJSON []byte, category string, memberCount int) {
+	var last models.StorySummaryRevision
+	err := db.Where("story_id = ?", storyID).Order("created_at DESC, id DESC").First(&last).Error
+	if err == nil && last.Summary == summary && bytes.Equal(last.Bullets, bulletsJSON) {
+		return
+	}
+	db.Create(&models.StorySummaryRevision{
+		StoryID:     storyID,
+		Summary:     summary,
+		Bullets:     datatypes.JSON(bulletsJSON),
+		Category:    category,
+		MemberCount: memberCount,
+	})
 }
 
 // normalizeStoryCategory keeps the stored slug non-empty so the backfill's
@@ -1718,7 +1738,7 @@ func rerankStorySummaries(query string, summaries []StorySummary) []StorySummary
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+token)
 
-	client := &http.Client{Timeout: 30 * time.Second}
+	client := &http.Client{Transport: ownerTransport, Timeout: 30 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
 		return summaries
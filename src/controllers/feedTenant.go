@@ -9,6 +9,11 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// consumerTenant scopes data owned by a consumer identity (bookmark
+// collections, story follows, digests, data exports). Consumer identities are tenant-scoped
+// like preferences and blocks.
+const consumerTenant = "default"
+
 // trustedPublicFeedTenant derives feed scope from boot configuration and, for
 // authenticated callers, verifies the IAM claim agrees. Query parameters and
 // ordinary browser headers are intentionally ignored.
@@ -0,0 +1,326 @@
+package controllers
+
+import (
+	"content-management-system/src/feedstate"
+	"content-management-system/src/models"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ─── WebSub hub (W3C WebSub) for saved feeds ───────────────
+//
+// Every saved feed representation advertises this CMS as its hub. Subscribers
+// POST a subscribe/unsubscribe intent here; the hub worker verifies it against
+// the callback and, once active, pushes signed content deltas whenever items
+// matching the feed become READY on the ingest path.
+
+const (
+	webSubHubPath = "/api/v1/feed/websub"
+
+	webSubDefaultLease = 7 * 24 * time.Hour
+	webSubMinLease     = time.Hour
+	webSubMaxLease     = 30 * 24 * time.Hour
+	webSubMaxSecretLen = 200
+)
+
+type webSubTopic struct {
+	Base   string
+	Slug   string
+	Format string
+}
+
+// parseWebSubTopic accepts only canonical saved-feed URLs served by this hub:
+// <base>/api/v1/feed/saved/<slug>[?format=atom|json|podcast].
+func parseWebSubTopic(raw, base string) (webSubTopic, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return webSubTopic{}, errors.New("hub.topic must be an absolute http(s) URL")
+	}
+	topicBase := u.Scheme + "://" + u.Host
+	if !strings.EqualFold(topicBase, base) {
+		return webSubTopic{}, errors.New("hub.topic is not served by this hub")
+	}
+	const prefix = "/api/v1/feed/saved/"
+	slug := strings.TrimPrefix(u.Path, prefix)
+	if slug == u.Path || slug == "" || strings.Contains(slug, "/") {
+		return webSubTopic{}, errors.New("hub.topic must be a saved feed URL")
+	}
+	format := "rss"
+	if raw := u.Query().Get("format"); raw != "" {
+		if format = savedFeedFormat(raw); format != strings.ToLower(raw) {
+			return webSubTopic{}, errors.New("hub.topic has an unsupported format")
+		}
+	}
+	return webSubTopic{Base: base, Slug: slug, Format: format}, nil
+}
+
+func (t webSubTopic) URL() string {
+	return savedFeedTopicURL(t.Base, t.Slug, t.Format)
+}
+
+func webSubAllowPrivateCallbacks() bool {
+	return strings.EqualFold(strings.TrimSpace(os.Getenv("WEBSUB_ALLOW_PRIVATE_CALLBACKS")), "true")
+}
+
+// webSubPublicIP reports whether ip may receive hub traffic: loopback,
+// private (RFC 1918 and IPv6 ULA), link-local, multicast and unspecified
+// addresses are refused.
+func webSubPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified())
+}
+
+// validateWebSubCallback rejects non-http(s) callbacks and, unless
+// WEBSUB_ALLOW_PRIVATE_CALLBACKS=true, loopback/private literal hosts. This is
+// only an early refusal: a public name can still resolve privately, which the
+// hub client's dialer (webSubDialControl) catches on every request.
+func validateWebSubCallback(raw string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", errors.New("hub.callback must be an absolute http(s) URL")
+	}
+	u.Fragment = ""
+	if webSubAllowPrivateCallbacks() {
+		return u.String(), nil
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return "", errors.New("hub.callback must be publicly reachable")
+	}
+	if ip := net.ParseIP(host); ip != nil && !webSubPublicIP(ip) {
+		return "", errors.New("hub.callback must be publicly reachable")
+	}
+	return u.String(), nil
+}
+
+// webSubLease clamps the requested hub.lease_seconds into the hub's bounds.
+func webSubLease(raw string) int {
+	lease := webSubDefaultLease
+	if n, err := strconv.Atoi(strings.TrimSpace(raw)); err == nil && n > 0 {
+		lease = time.Duration(n) * time.Second
+	}
+	if lease < webSubMinLease {
+		lease = webSubMinLease
+	}
+	if lease > webSubMaxLease {
+		lease = webSubMaxLease
+	}
+	return int(lease / time.Second)
+}
+
+// PostWebSubHub handles POST /api/v1/feed/websub (form-encoded hub.mode,
+// hub.topic, hub.callback, hub.lease_seconds, hub.secret). The intent is
+// recorded and answered 202; verification happens asynchronously.
+func PostWebSubHub(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+
+	mode := strings.ToLower(strings.TrimSpace(c.PostForm("hub.mode")))
+	if mode != "subscribe" && mode != "unsubscribe" {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "hub.mode must be subscribe or unsubscribe"})
+		return
+	}
+	topic, err := parseWebSubTopic(c.PostForm("hub.topic"), publicBaseURL(c))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	callback, err := validateWebSubCallback(c.PostForm("hub.callback"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	secret := c.PostForm("hub.secret")
+	if len(secret) > webSubMaxSecretLen {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "hub.secret must be under 200 bytes"})
+		return
+	}
+
+	var feed models.RSSFeed
+	if err := db.Where("slug = ? AND enabled = ?", topic.Slug, true).First(&feed).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": "feed not found"})
+		return
+	}
+
+	now := time.Now().UTC()
+	var sub models.WebSubSubscription
+	err = db.Where("topic = ? AND callback = ?", topic.URL(), callback).First(&sub).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		if mode == "unsubscribe" {
+			c.Status(http.StatusAccepted)
+			return
+		}
+		sub = models.WebSubSubscription{
+			TenantID:            feed.TenantID,
+			RSSFeedID:           feed.PublicID,
+			Topic:               topic.URL(),
+			Callback:            callback,
+			Format:              topic.Format,
+			State:               models.WebSubStatePending,
+			PendingMode:         mode,
+			PendingLeaseSeconds: webSubLease(c.PostForm("hub.lease_seconds")),
+			PendingSecret:       secret,
+			VerifyAfter:         &now,
+		}
+		err = db.Clauses(clause.OnConflict{DoNothing: true}).Create(&sub).Error
+	case err == nil:
+		err = db.Model(&sub).Updates(map[string]interface{}{
+			"pending_mode":          mode,
+			"pending_lease_seconds": webSubLease(c.PostForm("hub.lease_seconds")),
+			"pending_secret":        secret,
+			"verify_after":          now,
+			"verify_attempts":       0,
+		}).Error
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "failed to record subscription"})
+		return
+	}
+	c.Status(http.StatusAccepted)
+}
+
+// Every READY commit — ingest, status callbacks, the durable stage reducer,
+// chapter approval and admin bulk status changes — reaches the outbox through feedstate.CommitReady.
+func init() {
+	feedstate.RegisterReadyHook(queueWebSubNotifications)
+}
+
+// queueWebSubNotifications writes one pending delivery per active subscription
+// whose saved feed now includes item. It runs inside the transaction that made
+// the item READY so a committed status change always has its outbox rows.
+// Deliveries are unique per subscription and item, so a repeat is a no-op.
+func queueWebSubNotifications(tx *gorm.DB, item models.ContentItem) error {
+	if item.Status != models.ContentStatusReady {
+		return nil
+	}
+	var subs []models.WebSubSubscription
+	if err := tx.Where("tenant_id = ? AND state = ?", item.TenantID, models.WebSubStateActive).
+		Find(&subs).Error; err != nil || len(subs) == 0 {
+		return err
+	}
+
+	byFeed := map[uuid.UUID][]models.WebSubSubscription{}
+	for _, sub := range subs {
+		byFeed[sub.RSSFeedID] = append(byFeed[sub.RSSFeedID], sub)
+	}
+	now := time.Now().UTC()
+	for feedID, feedSubs := range byFeed {
+		var feed models.RSSFeed
+		if err := tx.Where("public_id = ? AND enabled = ?", feedID, true).First(&feed).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue
+			}
+			return err
+		}
+		q := savedFeedQuery(feed)
+		q.ContentIDs = []string{item.PublicID.String()}
+		var matched []string
+		if err := scopedFeedQuery(tx, q).Pluck("public_id::text", &matched).Error; err != nil {
+			return err
+		}
+		if len(matched) == 0 {
+			continue
+		}
+		for _, sub := range feedSubs {
+			delivery := models.WebSubDelivery{
+				TenantID:       sub.TenantID,
+				SubscriptionID: sub.PublicID,
+				ContentItemID:  item.PublicID,
+				State:          models.WebSubDeliveryPending,
+				NextAttemptAt:  now,
+			}
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&delivery).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ─── Admin listing ──────────────────────────────────────────
+
+type webSubSubscriptionListResponse struct {
+	Data  []models.WebSubSubscription `json:"data"`
+	Total int64                       `json:"total"`
+	Page  int                         `json:"page"`
+	Limit int                         `json:"limit"`
+}
+
+// ListWebSubSubscriptions handles GET /admin/feeds/subscriptions and
+// GET /admin/feeds/:id/subscriptions (?state=&limit=&page=).
+func ListWebSubSubscriptions(c *gin.Context) {
+	principal, ok := requireAdminPrincipal(c)
+	if !ok {
+		return
+	}
+	db := c.MustGet("db").(*gorm.DB)
+
+	query := db.Model(&models.WebSubSubscription{}).Where("tenant_id = ?", principal.TenantID)
+	if raw := strings.TrimSpace(c.Param("id")); raw != "" {
+		feedID, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, authErrorResponse{Message: "Invalid feed id", Code: "INVALID_ID"})
+			return
+		}
+		query = query.Where("rss_feed_id = ?", feedID)
+	}
+	if state := strings.TrimSpace(c.Query("state")); state != "" {
+		query = query.Where("state = ?", state)
+	}
+
+	limit, page := paginationParams(c, 50, 200)
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, authErrorResponse{Message: "Failed to list subscriptions", Code: "SUBSCRIPTIONS_LIST_FAILED"})
+		return
+	}
+	rows := []models.WebSubSubscription{}
+	if err := query.Order("created_at DESC").Limit(limit).Offset((page - 1) * limit).Find(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, authErrorResponse{Message: "Failed to list subscriptions", Code: "SUBSCRIPTIONS_LIST_FAILED"})
+		return
+	}
+	c.JSON(http.StatusOK, webSubSubscriptionListResponse{Data: rows, Total: total, Page: page, Limit: limit})
+}
+
+// DeleteWebSubSubscription handles DELETE /admin/feeds/subscriptions/:id. The
+// subscription is retired immediately (no callback verification) and its
+// pending deliveries are dropped.
+func DeleteWebSubSubscription(c *gin.Context) {
+	principal, ok := requireAdminPrincipal(c)
+	if !ok {
+		return
+	}
+	db := c.MustGet("db").(*gorm.DB)
+
+	id, err := uuid.Parse(strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, authErrorResponse{Message: "Invalid subscription id", Code: "INVALID_ID"})
+		return
+	}
+	var sub models.WebSubSubscription
+	if err := db.Where("tenant_id = ? AND public_id = ?", principal.TenantID, id).First(&sub).Error; err != nil {
+		c.JSON(http.StatusNotFound, authErrorResponse{Message: "Subscription not found", Code: "NOT_FOUND"})
+		return
+	}
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&sub).Updates(map[string]interface{}{"state": models.WebSubStateUnsubscribed, "pending_mode": ""}).Error; err != nil {
+			return err
+		}
+		return dropWebSubDeliveries(tx, []uuid.UUID{sub.PublicID}, "subscription removed by admin")
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, authErrorResponse{Message: "Failed to remove subscription", Code: "SUBSCRIPTION_DELETE_FAILED"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
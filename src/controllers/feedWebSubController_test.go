@@ -0,0 +1,179 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"content-management-system/src/models"
+)
+
+func TestParseWebSubTopicAcceptsOnlyCanonicalSavedFeeds(t *testing.T) {
+	base := "https://cms.test"
+	topic, err := parseWebSubTopic("https://cms.test/api/v1/feed/saved/gulf-news?format=atom", base)
+	if err != nil || topic.Slug != "gulf-news" || topic.Format != "atom" {
+		t.Fatalf("atom topic = %+v, %v", topic, err)
+	}
+	if topic.URL() != "https://cms.test/api/v1/feed/saved/gulf-news?format=atom" {
+		t.Fatalf("canonical topic = %s", topic.URL())
+	}
+	if rss, err := parseWebSubTopic("https://cms.test/api/v1/feed/saved/gulf-news", base); err != nil || rss.URL() != "https://cms.test/api/v1/feed/saved/gulf-news" {
+		t.Fatalf("rss topic = %+v, %v", rss, err)
+	}
+	for _, bad := range []string{
+		"https://elsewhere.test/api/v1/feed/saved/gulf-news",
+		"https://cms.test/api/v1/feed/rss.xml",
+		"https://cms.test/api/v1/feed/saved/",
+		"https://cms.test/api/v1/feed/saved/a/b",
+		"https://cms.test/api/v1/feed/saved/gulf-news?format=html",
+		"ftp://cms.test/api/v1/feed/saved/gulf-news",
+	} {
+		if _, err := parseWebSubTopic(bad, base); err == nil {
+			t.Fatalf("topic %q accepted", bad)
+		}
+	}
+}
+
+func TestValidateWebSubCallbackRejectsPrivateHosts(t *testing.T) {
+	t.Setenv("WEBSUB_ALLOW_PRIVATE_CALLBACKS", "")
+	if got, err := validateWebSubCallback("https://reader.example/hook?id=1#frag"); err != nil || got != "https://reader.example/hook?id=1" {
+		t.Fatalf("public callback = %q, %v", got, err)
+	}
+	for _, bad := range []string{"http://localhost/hook", "http://127.0.0.1/hook", "http://10.1.2.3/hook", "http://[::1]/hook", "mailto:x@y"} {
+		if _, err := validateWebSubCallback(bad); err == nil {
+			t.Fatalf("callback %q accepted", bad)
+		}
+	}
+	t.Setenv("WEBSUB_ALLOW_PRIVATE_CALLBACKS", "true")
+	if _, err := validateWebSubCallback("http://127.0.0.1/hook"); err != nil {
+		t.Fatalf("opt-in private callback rejected: %v", err)
+	}
+}
+
+func TestWebSubClientRefusesPrivateAddressesAndRedirects(t *testing.T) {
+	redirected := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/elsewhere" {
+			redirected = true
+			return
+		}
+		http.Redirect(w, r, "/elsewhere", http.StatusFound)
+	}))
+	defer srv.Close()
+	client := newWebSubClient()
+
+	t.Setenv("WEBSUB_ALLOW_PRIVATE_CALLBACKS", "")
+	if _, err := client.Get(srv.URL + "/hook"); !errors.Is(err, errWebSubPrivateAddress) {
+		t.Fatalf("loopback dial err = %v, want errWebSubPrivateAddress", err)
+	}
+
+	t.Setenv("WEBSUB_ALLOW_PRIVATE_CALLBACKS", "true")
+	resp, err := client.Get(srv.URL + "/hook")
+	if err != nil {
+		t.Fatalf("opt-in private callback: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusFound || redirected {
+		t.Fatalf("status = %d, redirected = %v; redirects must not be followed", resp.StatusCode, redirected)
+	}
+}
+
+func TestWebSubLeaseAndBackoffBounds(t *testing.T) {
+	if got := webSubLease(""); got != int(webSubDefaultLease/time.Second) {
+		t.Fatalf("default lease = %d", got)
+	}
+	if got := webSubLease("60"); got != int(webSubMinLease/time.Second) {
+		t.Fatalf("short lease = %d, want clamped to minimum", got)
+	}
+	if got := webSubLease("99999999"); got != int(webSubMaxLease/time.Second) {
+		t.Fatalf("long lease = %d, want clamped to maximum", got)
+	}
+	if webSubRetryDelay(1) != 30*time.Second || webSubRetryDelay(3) != 2*time.Minute {
+		t.Fatalf("backoff = %s, %s", webSubRetryDelay(1), webSubRetryDelay(3))
+	}
+	if webSubRetryDelay(40) != webSubMaxRetryDelay {
+		t.Fatalf("backoff cap = %s", webSubRetryDelay(40))
+	}
+}
+
+func TestVerifyWebSubIntentRequiresEchoedChallenge(t *testing.T) {
+	var echo bool
+	var status int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("hub.mode") != "subscribe" || q.Get("hub.lease_seconds") != "3600" || q.Get("keep") != "1" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(status)
+		if echo {
+			_, _ = io.WriteString(w, q.Get("hub.challenge"))
+		} else {
+			_, _ = io.WriteString(w, "nope")
+		}
+	}))
+	defer srv.Close()
+
+	sub := models.WebSubSubscription{Callback: srv.URL + "/hook?keep=1", Topic: "https://cms.test/api/v1/feed/saved/x", PendingMode: "subscribe", PendingLeaseSeconds: 3600}
+	echo, status = true, http.StatusOK
+	if ok, retry, reason := verifyWebSubIntent(context.Background(), srv.Client(), sub); !ok || retry {
+		t.Fatalf("echoed challenge refused: %s", reason)
+	}
+	echo = false
+	if ok, retry, _ := verifyWebSubIntent(context.Background(), srv.Client(), sub); ok || retry {
+		t.Fatal("mismatched challenge must refuse without retry")
+	}
+	echo, status = true, http.StatusServiceUnavailable
+	if ok, retry, _ := verifyWebSubIntent(context.Background(), srv.Client(), sub); ok || !retry {
+		t.Fatal("5xx must be retryable")
+	}
+}
+
+func TestPostWebSubContentSignsBody(t *testing.T) {
+	var gotSig, gotLink string
+	var gotBody []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSig, gotLink = r.Header.Get("X-Hub-Signature"), r.Header.Get("Link")
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	sub := models.WebSubSubscription{Callback: srv.URL, Topic: "https://cms.test/api/v1/feed/saved/x?format=json", Secret: "s3cret", Format: "json"}
+	body := []byte(`{"items":[]}`)
+	if status, err := postWebSubContent(context.Background(), srv.Client(), sub, "application/feed+json", body); err != nil || status != http.StatusAccepted {
+		t.Fatalf("push = %d, %v", status, err)
+	}
+	if gotSig != webSubSignature("s3cret", gotBody) || !strings.HasPrefix(gotSig, "sha256=") {
+		t.Fatalf("signature = %q", gotSig)
+	}
+	if !strings.Contains(gotLink, `<https://cms.test/api/v1/feed/websub>; rel="hub"`) || !strings.Contains(gotLink, `rel="self"`) {
+		t.Fatalf("link header = %q", gotLink)
+	}
+	if webSubSignature("other", body) == webSubSignature("s3cret", body) {
+		t.Fatal("signature must depend on the secret")
+	}
+}
+
+func TestSavedFeedDocumentsAdvertiseHub(t *testing.T) {
+	feed := models.RSSFeed{Slug: "gulf", Name: "Gulf"}
+	for format, want := range map[string]string{
+		"rss":     `<atom:link href="https://cms.test/api/v1/feed/websub" rel="hub">`,
+		"podcast": `<atom:link href="https://cms.test/api/v1/feed/websub" rel="hub">`,
+		"atom":    `<link href="https://cms.test/api/v1/feed/websub" rel="hub">`,
+		"json":    `"hubs":[{"type":"WebSub","url":"https://cms.test/api/v1/feed/websub"}]`,
+	} {
+		_, body, err := encodeFeedDocument(format, savedFeedMeta(feed, "https://cms.test", format), nil)
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		if !strings.Contains(string(body), want) {
+			t.Fatalf("%s document missing hub link %s\n%s", format, want, body)
+		}
+	}
+}
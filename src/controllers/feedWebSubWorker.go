@@ -0,0 +1,482 @@
+package controllers
+
+import (
+	"bytes"
+	"content-management-system/src/lifecycle"
+	"content-management-system/src/models"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	webSubTick                 = 15 * time.Second
+	webSubClaimLease           = 2 * time.Minute
+	webSubRequestTimeout       = 10 * time.Second
+	webSubMaxVerifyAttempts    = 3
+	webSubMaxDeliveryAttempts  = 8
+	webSubBaseRetryDelay       = 30 * time.Second
+	webSubMaxRetryDelay        = 6 * time.Hour
+	webSubDeliveryBatch        = 64
+	webSubMaxItemsPerPush      = 50
+	webSubMaxCallbackBodyBytes = 4096
+)
+
+var webSubHeartbeat atomic.Int64
+
+// StartWebSubHeartbeat drives the hub: intent verification, lease expiry and
+// content distribution. Deliveries live in Postgres so a restart only delays,
+// never loses, a push.
+//
+// The first run happens inside the worker: it calls subscriber-controlled
+// callbacks, which must not hold up startup.
+func StartWebSubHeartbeat(ctx context.Context, db *gorm.DB) {
+	lifecycle.Go(ctx, "websub-hub", func(ctx context.Context) {
+		ticker := time.NewTicker(webSubTick)
+		defer ticker.Stop()
+		runWebSubHub(ctx, db)
+		for lifecycle.Wait(ctx, ticker.C) {
+			runWebSubHub(ctx, db)
+		}
+	})
+}
+
+func WebSubWorkerHealthy(now time.Time) bool {
+	last := webSubHeartbeat.Load()
+	return last > 0 && now.UTC().Sub(time.Unix(0, last).UTC()) <= 6*webSubTick
+}
+
+func WebSubWorkerLastHeartbeat() time.Time {
+	return lifecycle.HeartbeatTime(webSubHeartbeat.Load())
+}
+
+// errWebSubPrivateAddress is returned by the hub dialer for callbacks that
+// resolve into the CMS's own network.
+var errWebSubPrivateAddress = errors.New("websub callback resolves to a non-public address")
+
+// webSubDialControl checks the address actually being dialled, after DNS
+// resolution, so a public callback name cannot be rebound to an internal host.
+func webSubDialControl(network, address string, _ syscall.RawConn) error {
+	if webSubAllowPrivateCallbacks() {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !webSubPublicIP(ip) {
+		return errWebSubPrivateAddress
+	}
+	return nil
+}
+
+// newWebSubClient is the hub's outbound client for subscriber callbacks. It
+// never follows redirects (a 3xx is the subscriber's answer, not a hop to
+// another host) and never uses an environment proxy, whose address would
+// otherwise be the one the dial check sees.
+func newWebSubClient() *http.Client {
+	dialer := &net.Dialer{Timeout: webSubRequestTimeout, Control: webSubDialControl}
+	return &http.Client{
+		Timeout: webSubRequestTimeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: webSubRequestTimeout,
+			MaxIdleConnsPerHost: 2,
+			IdleConnTimeout:     time.Minute,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+var webSubClient = newWebSubClient()
+
+func runWebSubHub(ctx context.Context, db *gorm.DB) {
+	client := webSubClient
+	if err := verifyDueWebSubIntents(ctx, db, client); err != nil {
+		log.Printf("websub verification failed: %v", err)
+	}
+	if err := expireWebSubLeases(db); err != nil {
+		log.Printf("websub lease expiry failed: %v", err)
+	}
+	if err := deliverDueWebSubNotifications(ctx, db, client); err != nil {
+		log.Printf("websub delivery failed: %v", err)
+	}
+	webSubHeartbeat.Store(time.Now().UTC().UnixNano())
+}
+
+// webSubRetryDelay is the exponential backoff after the n-th failed attempt.
+func webSubRetryDelay(attempts int) time.Duration {
+	delay := webSubBaseRetryDelay
+	for i := 1; i < attempts && delay < webSubMaxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > webSubMaxRetryDelay {
+		delay = webSubMaxRetryDelay
+	}
+	return delay
+}
+
+// webSubSignature is the X-Hub-Signature value for a content distribution body.
+func webSubSignature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func webSubTopicBase(topic string) string {
+	u, err := url.Parse(topic)
+	if err != nil {
+		return ""
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// ─── Intent verification ────────────────────────────────────
+
+func verifyDueWebSubIntents(ctx context.Context, db *gorm.DB, client *http.Client) error {
+	now := time.Now().UTC()
+	var due []models.WebSubSubscription
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("pending_mode <> '' AND verify_after <= ?", now).
+			Order("verify_after").Limit(16).Find(&due).Error; err != nil || len(due) == 0 {
+			return err
+		}
+		ids := make([]uint, 0, len(due))
+		for _, sub := range due {
+			ids = append(ids, sub.ID)
+		}
+		return tx.Model(&models.WebSubSubscription{}).Where("id IN ?", ids).
+			Update("verify_after", now.Add(webSubClaimLease)).Error
+	}); err != nil {
+		return err
+	}
+	for _, sub := range due {
+		confirmed, retryable, reason := verifyWebSubIntent(ctx, client, sub)
+		// A verification cut off by shutdown is not an attempt; the claim
+		// lapses and another run retries it.
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err := applyWebSubVerification(db, sub, confirmed, retryable, reason); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifyWebSubIntent performs the W3C WebSub §5.3 GET. The subscriber confirms
+// by answering 2xx with the exact challenge. Transport errors and 5xx are
+// retryable; any other answer refuses the intent.
+func verifyWebSubIntent(ctx context.Context, client *http.Client, sub models.WebSubSubscription) (confirmed, retryable bool, reason string) {
+	challenge := make([]byte, 16)
+	if _, err := rand.Read(challenge); err != nil {
+		return false, true, err.Error()
+	}
+	token := hex.EncodeToString(challenge)
+
+	u, err := url.Parse(sub.Callback)
+	if err != nil {
+		return false, false, "invalid callback"
+	}
+	params := u.Query()
+	params.Set("hub.mode", sub.PendingMode)
+	params.Set("hub.topic", sub.Topic)
+	params.Set("hub.challenge", token)
+	if sub.PendingMode == "subscribe" {
+		params.Set("hub.lease_seconds", fmt.Sprint(sub.PendingLeaseSeconds))
+	}
+	u.RawQuery = params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return false, false, "invalid callback"
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, true, err.Error()
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, webSubMaxCallbackBodyBytes))
+	if resp.StatusCode >= 500 {
+		return false, true, fmt.Sprintf("callback returned %d", resp.StatusCode)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return false, false, fmt.Sprintf("callback returned %d", resp.StatusCode)
+	}
+	if strings.TrimSpace(string(body)) != token {
+		return false, false, "challenge mismatch"
+	}
+	return true, false, ""
+}
+
+func applyWebSubVerification(db *gorm.DB, sub models.WebSubSubscription, confirmed, retryable bool, reason string) error {
+	now := time.Now().UTC()
+	clearPending := map[string]interface{}{
+		"pending_mode": "", "pending_secret": "", "pending_lease_seconds": 0,
+		"verify_after": nil, "verify_attempts": 0, "last_error": reason,
+	}
+	switch {
+	case confirmed && sub.PendingMode == "subscribe":
+		expires := now.Add(time.Duration(sub.PendingLeaseSeconds) * time.Second)
+		clearPending["state"] = models.WebSubStateActive
+		clearPending["secret"] = sub.PendingSecret
+		clearPending["lease_seconds"] = sub.PendingLeaseSeconds
+		clearPending["expires_at"] = expires
+		clearPending["verified_at"] = now
+		clearPending["consecutive_failures"] = 0
+		return db.Model(&sub).Updates(clearPending).Error
+	case confirmed:
+		return db.Transaction(func(tx *gorm.DB) error {
+			clearPending["state"] = models.WebSubStateUnsubscribed
+			if err := tx.Model(&sub).Updates(clearPending).Error; err != nil {
+				return err
+			}
+			return dropWebSubDeliveries(tx, []uuid.UUID{sub.PublicID}, "unsubscribed")
+		})
+	case retryable && sub.VerifyAttempts+1 < webSubMaxVerifyAttempts:
+		return db.Model(&sub).Updates(map[string]interface{}{
+			"verify_attempts": sub.VerifyAttempts + 1,
+			"verify_after":    now.Add(webSubRetryDelay(sub.VerifyAttempts + 1)),
+			"last_error":      reason,
+		}).Error
+	default:
+		// Refused intent: a never-activated subscription is denied; an existing
+		// one keeps its current state (a failed unsubscribe or renewal must not
+		// silently change what the subscriber already has).
+		if sub.State == models.WebSubStatePending {
+			clearPending["state"] = models.WebSubStateDenied
+		}
+		return db.Model(&sub).Updates(clearPending).Error
+	}
+}
+
+// expireWebSubLeases retires subscriptions whose lease lapsed without renewal
+// and drops deliveries that can no longer be sent.
+func expireWebSubLeases(db *gorm.DB) error {
+	now := time.Now().UTC()
+	if err := db.Model(&models.WebSubSubscription{}).
+		Where("state = ? AND expires_at < ?", models.WebSubStateActive, now).
+		Update("state", models.WebSubStateExpired).Error; err != nil {
+		return err
+	}
+	return db.Model(&models.WebSubDelivery{}).
+		Where("state = ? AND subscription_id NOT IN (SELECT public_id FROM websub_subscriptions WHERE state = ?)",
+			models.WebSubDeliveryPending, models.WebSubStateActive).
+		Updates(map[string]interface{}{"state": models.WebSubDeliveryDropped, "last_error": "subscription inactive"}).Error
+}
+
+func dropWebSubDeliveries(tx *gorm.DB, subscriptionIDs []uuid.UUID, reason string) error {
+	return tx.Model(&models.WebSubDelivery{}).
+		Where("state = ? AND subscription_id IN ?", models.WebSubDeliveryPending, subscriptionIDs).
+		Updates(map[string]interface{}{"state": models.WebSubDeliveryDropped, "last_error": reason}).Error
+}
+
+// ─── Content distribution ───────────────────────────────────
+
+// deliverDueWebSubNotifications claims due deliveries, groups them per
+// subscription and pushes one delta document per subscriber. The claim bumps
+// next_attempt_at by a lease so concurrent replicas skip the same rows and a
+// crashed push is retried once the lease lapses.
+func deliverDueWebSubNotifications(ctx context.Context, db *gorm.DB, client *http.Client) error {
+	now := time.Now().UTC()
+	var due []models.WebSubDelivery
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("state = ? AND next_attempt_at <= ?", models.WebSubDeliveryPending, now).
+			Order("next_attempt_at").Limit(webSubDeliveryBatch).Find(&due).Error; err != nil || len(due) == 0 {
+			return err
+		}
+		ids := make([]uint, 0, len(due))
+		for _, d := range due {
+			ids = append(ids, d.ID)
+		}
+		return tx.Model(&models.WebSubDelivery{}).Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(webSubClaimLease)).Error
+	}); err != nil {
+		return err
+	}
+
+	bySub := map[uuid.UUID][]models.WebSubDelivery{}
+	var order []uuid.UUID
+	for _, d := range due {
+		if _, seen := bySub[d.SubscriptionID]; !seen {
+			order = append(order, d.SubscriptionID)
+		}
+		if len(bySub[d.SubscriptionID]) < webSubMaxItemsPerPush {
+			bySub[d.SubscriptionID] = append(bySub[d.SubscriptionID], d)
+		}
+	}
+	for _, subID := range order {
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err := pushWebSubDelta(ctx, db, client, subID, bySub[subID]); err != nil {
+			log.Printf("websub push to subscription %s failed: %v", subID, err)
+		}
+	}
+	return nil
+}
+
+func pushWebSubDelta(ctx context.Context, db *gorm.DB, client *http.Client, subID uuid.UUID, deliveries []models.WebSubDelivery) error {
+	var sub models.WebSubSubscription
+	if err := db.Where("public_id = ? AND state = ?", subID, models.WebSubStateActive).First(&sub).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dropWebSubDeliveries(db, []uuid.UUID{subID}, "subscription inactive")
+		}
+		return err
+	}
+	var feed models.RSSFeed
+	if err := db.Where("public_id = ? AND enabled = ?", sub.RSSFeedID, true).First(&feed).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dropWebSubDeliveries(db, []uuid.UUID{subID}, "feed disabled or deleted")
+		}
+		return err
+	}
+
+	q := savedFeedQuery(feed)
+	q.Since = nil
+	for _, d := range deliveries {
+		q.ContentIDs = append(q.ContentIDs, d.ContentItemID.String())
+	}
+	q.Limit = len(q.ContentIDs)
+	items, err := fetchFeedItems(db, q)
+	if err != nil {
+		return err
+	}
+	if sub.Format == "podcast" {
+		kept := items[:0]
+		for _, it := range items {
+			if it.Enclosure != nil {
+				kept = append(kept, it)
+			}
+		}
+		items = kept
+	}
+
+	// Items edited out of the feed since they were queued are not pushed.
+	present := map[string]bool{}
+	for _, it := range items {
+		present[it.ID] = true
+	}
+	var sendIDs, staleIDs []uint
+	for _, d := range deliveries {
+		if present[d.ContentItemID.String()] {
+			sendIDs = append(sendIDs, d.ID)
+		} else {
+			staleIDs = append(staleIDs, d.ID)
+		}
+	}
+	if len(staleIDs) > 0 {
+		if err := db.Model(&models.WebSubDelivery{}).Where("id IN ?", staleIDs).
+			Updates(map[string]interface{}{"state": models.WebSubDeliveryDropped, "last_error": "no longer in feed"}).Error; err != nil {
+			return err
+		}
+	}
+	if len(sendIDs) == 0 {
+		return nil
+	}
+
+	meta := savedFeedMeta(feed, webSubTopicBase(sub.Topic), sub.Format)
+	meta.Updated = time.Now().UTC()
+	contentType, body, err := encodeFeedDocument(sub.Format, meta, items)
+	if err != nil {
+		return err
+	}
+	status, pushErr := postWebSubContent(ctx, client, sub, contentType, body)
+	// A push cut off by shutdown is not an attempt; the claim lapses and
+	// another run retries it.
+	if ctx.Err() != nil {
+		return nil
+	}
+	return recordWebSubPush(db, sub, deliveries, sendIDs, status, pushErr)
+}
+
+// postWebSubContent performs the W3C WebSub §7 content distribution POST.
+func postWebSubContent(ctx context.Context, client *http.Client, sub models.WebSubSubscription, contentType string, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.Callback, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Link", fmt.Sprintf(`<%s>; rel="hub", <%s>; rel="self"`, webSubTopicBase(sub.Topic)+webSubHubPath, sub.Topic))
+	if sub.Secret != "" {
+		req.Header.Set("X-Hub-Signature", webSubSignature(sub.Secret, body))
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, webSubMaxCallbackBodyBytes))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("callback returned %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+func recordWebSubPush(db *gorm.DB, sub models.WebSubSubscription, deliveries []models.WebSubDelivery, sendIDs []uint, status int, pushErr error) error {
+	now := time.Now().UTC()
+	return db.Transaction(func(tx *gorm.DB) error {
+		if pushErr == nil {
+			if err := tx.Model(&models.WebSubDelivery{}).Where("id IN ?", sendIDs).Updates(map[string]interface{}{
+				"state": models.WebSubDeliveryDelivered, "attempts": gorm.Expr("attempts + 1"),
+				"last_status": status, "last_error": "", "delivered_at": now,
+			}).Error; err != nil {
+				return err
+			}
+			return tx.Model(&sub).Updates(map[string]interface{}{"last_delivery_at": now, "consecutive_failures": 0, "last_error": ""}).Error
+		}
+
+		// 410 Gone is the subscriber's way of saying the callback is retired.
+		if status == http.StatusGone {
+			if err := tx.Model(&sub).Updates(map[string]interface{}{"state": models.WebSubStateUnsubscribed, "last_error": pushErr.Error()}).Error; err != nil {
+				return err
+			}
+			return dropWebSubDeliveries(tx, []uuid.UUID{sub.PublicID}, "callback gone")
+		}
+
+		sending := map[uint]bool{}
+		for _, id := range sendIDs {
+			sending[id] = true
+		}
+		for _, d := range deliveries {
+			if !sending[d.ID] {
+				continue
+			}
+			attempts := d.Attempts + 1
+			updates := map[string]interface{}{"attempts": attempts, "last_status": status, "last_error": pushErr.Error()}
+			if attempts >= webSubMaxDeliveryAttempts {
+				updates["state"] = models.WebSubDeliveryFailed
+			} else {
+				updates["next_attempt_at"] = now.Add(webSubRetryDelay(attempts))
+			}
+			if err := tx.Model(&models.WebSubDelivery{}).Where("id = ?", d.ID).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&sub).Updates(map[string]interface{}{
+			"consecutive_failures": gorm.Expr("consecutive_failures + 1"), "last_error": pushErr.Error(),
+		}).Error
+	})
+}
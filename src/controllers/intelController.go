@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"content-management-system/src/lifecycle"
 	"content-management-system/src/models"
 	"encoding/json"
 	"log"
@@ -752,7 +753,7 @@ func classifyCandidates(candidates []models.SourceCandidate) (map[uint]string, m
 		return classMap, methodMap
 	}
 
-	results, err := classifyAccountsViaEnrichment(batch)
+	results, err := classifyAccountsViaEnrichment(lifecycle.Root(), batch)
 	if err != nil {
 		log.Printf("source classify: LLM fallback failed (keeping deterministic): %v", err)
 		return classMap, methodMap
@@ -767,7 +768,7 @@ func classifyCandidates(candidates []models.SourceCandidate) (map[uint]string, m
 }
 
 func promoteForProfile(db *gorm.DB, tenantID string, profile *models.DiscoveryProfile, cfg models.DiscoveryConfig, candidates []models.SourceCandidate, classMap map[uint]string, methodMap map[uint]string) int {
-	profileVec, ok := ensureProfileEmbedding(db, profile)
+	profileVec, ok := ensureProfileEmbedding(lifecycle.Root(), db, profile)
 	if !ok {
 		return 0
 	}
@@ -791,7 +792,7 @@ func promoteForProfile(db *gorm.DB, tenantID string, profile *models.DiscoveryPr
 		relevance := 0.0
 		novelty := 1.0
 		if len(titles) > 0 {
-			if vecs, sampleSpaceID, err := embedBatchViaEnrichmentWithSpace(titles); err == nil && len(vecs) > 0 &&
+			if vecs, sampleSpaceID, err := embedBatchViaEnrichmentWithSpace(lifecycle.Root(), titles); err == nil && len(vecs) > 0 &&
 				profile.EmbeddingSpaceID != nil && sampleSpaceID == *profile.EmbeddingSpaceID {
 				var sum float64
 				n := 0
@@ -1,11 +1,13 @@
 package controllers
 
 import (
+	"content-management-system/src/lifecycle"
 	"content-management-system/src/models"
 	"fmt"
 	"math"
 	"net/http"
 	"strconv"
+	"strings"
 	"sync/atomic"
 	"time"
 
@@ -173,7 +175,7 @@ func RefreshNewsSnapshot(c *gin.Context) {
 	// Kick the classification self-heal in the background — when it finds and
 	// classifies stragglers it rebuilds the snapshot again on completion, so a
 	// single Refresh click converges even after bulk re-embeds.
-	StartClassificationBackfill(db)
+	StartClassificationBackfill(lifecycle.Root(), db)
 
 	window := normalizeNewsWindow(c.DefaultQuery("window", models.NewsWindowToday))
 	count, err := buildNewsSnapshot(db, principal.TenantID, window)
@@ -668,7 +670,7 @@ func GetScoreDistribution(c *gin.Context) {
 	contentIDs := extractPublicIDs(items)
 	flagMap := LoadContentFlags(db, principal.TenantID, contentIDs)
 	velocityData := LoadVelocityData(db, contentIDs, config.VelocityWindowHours, time.Now())
-	scored := ScoreItems(items, config, flagMap, velocityData, time.Now())
+	scored := ScoreItems(items, config, flagMap, velocityData, nil, time.Now())
 
 	// Bucket into 10 ranges
 	buckets := make([]int, 10) // 0-0.1, 0.1-0.2, ...
@@ -927,9 +929,14 @@ type previewFeedItem struct {
 type previewFeedResponse struct {
 	Items    []previewFeedItem `json:"items"`
 	IsActive bool              `json:"is_active"`
+	// Personalization describes the taste vector behind the similarity
+	// signal when the preview runs as an identity (Pods only).
+	Personalization *tasteProfile `json:"personalization,omitempty"`
 }
 
-// PreviewPodsFeed handles GET /admin/intelligence/preview/pods
+// PreviewPodsFeed handles GET /admin/intelligence/preview/pods. With
+// ?user_id= or ?session_id= the similarity signal is personalized for that
+// identity, as the Pods feed would rank it for them.
 func PreviewPodsFeed(c *gin.Context) {
 	principal, ok := requireAdminPrincipal(c)
 	if !ok {
@@ -951,7 +958,17 @@ func PreviewPodsFeed(c *gin.Context) {
 		Limit(50).
 		Find(&items)
 
-	c.JSON(http.StatusOK, buildPreviewResponse(db, items, config, principal.TenantID))
+	var taste *tasteProfile
+	if userID, sessionID := strings.TrimSpace(c.Query("user_id")), strings.TrimSpace(c.Query("session_id")); userID != "" || sessionID != "" {
+		if _, err := uuid.Parse(userID); userID != "" && err != nil {
+			c.JSON(http.StatusBadRequest, authErrorResponse{Message: "user_id must be a UUID", Code: "INVALID_USER_ID"})
+			return
+		}
+		profile := loadTasteProfile(db, principal.TenantID, userID, sessionID)
+		taste = &profile
+	}
+
+	c.JSON(http.StatusOK, buildPreviewResponse(db, items, config, principal.TenantID, taste))
 }
 
 // PreviewNewsFeed handles GET /admin/intelligence/preview/news
@@ -972,7 +989,7 @@ func PreviewNewsFeed(c *gin.Context) {
 		Limit(50).
 		Find(&items)
 
-	c.JSON(http.StatusOK, buildPreviewResponse(db, items, config, principal.TenantID))
+	c.JSON(http.StatusOK, buildPreviewResponse(db, items, config, principal.TenantID, nil))
 }
 
 // ================================================================
@@ -1065,9 +1082,9 @@ func applyWeightOverrides(c *gin.Context, config *models.RankingConfig) {
 	}
 }
 
-func buildPreviewResponse(db *gorm.DB, items []models.ContentItem, config models.RankingConfig, tenantID string) previewFeedResponse {
+func buildPreviewResponse(db *gorm.DB, items []models.ContentItem, config models.RankingConfig, tenantID string, taste *tasteProfile) previewFeedResponse {
 	if len(items) == 0 {
-		return previewFeedResponse{Items: []previewFeedItem{}, IsActive: config.IsActive}
+		return previewFeedResponse{Items: []previewFeedItem{}, IsActive: config.IsActive, Personalization: taste}
 	}
 
 	// Build chronological position map
@@ -1080,7 +1097,11 @@ func buildPreviewResponse(db *gorm.DB, items []models.ContentItem, config models
 	contentIDs := extractPublicIDs(items)
 	flagMap := LoadContentFlags(db, tenantID, contentIDs)
 	velocityData := LoadVelocityData(db, contentIDs, config.VelocityWindowHours, time.Now())
-	scored := ScoreItems(items, config, flagMap, velocityData, time.Now())
+	var similarityData SimilarityData
+	if taste != nil {
+		similarityData = LoadSimilarityData(db, *taste, contentIDs)
+	}
+	scored := ScoreItems(items, config, flagMap, velocityData, similarityData, time.Now())
 
 	result := make([]previewFeedItem, 0, len(scored))
 	for i, s := range scored {
@@ -1115,5 +1136,5 @@ func buildPreviewResponse(db *gorm.DB, items []models.ContentItem, config models
 		})
 	}
 
-	return previewFeedResponse{Items: result, IsActive: config.IsActive}
+	return previewFeedResponse{Items: result, IsActive: config.IsActive, Personalization: taste}
 }
@@ -16,16 +16,15 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
 // maxCommentLength caps comment text to keep payloads and rendering sane.
 const maxCommentLength = 1000
 
-const (
-	maxCommentsPerMinute   = 5
-	commentDuplicateWindow = 5 * time.Minute
-)
+// Comment frequency is limited by the "comments.create" rate limit policy.
+const commentDuplicateWindow = 5 * time.Minute
 
 const maxConsumerIdempotencyKeyLength = 160
 
@@ -200,6 +199,9 @@ func interactionIdentityScope(interaction models.UserInteraction) string {
 
 func interactionRequestDigest(req models.CreateInteractionRequest) string {
 	value := req.ContentItemID + "\n" + string(req.InteractionType) + "\n" + string(req.Metadata)
+	if req.ParentID != nil {
+		value += "\nparent:" + *req.ParentID
+	}
 	digest := sha256.Sum256([]byte(value))
 	return fmt.Sprintf("%x", digest[:])
 }
@@ -250,7 +252,14 @@ func CreateInteraction(c *gin.Context) {
 		return
 	}
 
+	if req.ParentID != nil && req.InteractionType != models.InteractionTypeComment {
+		c.JSON(http.StatusBadRequest, utils.HTTPError{Code: http.StatusBadRequest, Message: "parent_id is only valid for comments"})
+		return
+	}
+
 	// Comments must carry non-blank text (length-capped)
+	var commentDecision commentPolicyDecision
+	var commentParent *models.UserInteraction
 	if req.InteractionType == models.InteractionTypeComment {
 		// Comments are public user-generated content. Anonymous sessions can read
 		// legacy comments but must never create new ones, even if they know a
@@ -278,8 +287,13 @@ func CreateInteraction(c *gin.Context) {
 			return
 		}
 		meta.Text = strings.TrimSpace(meta.Text)
-		decision := evaluateCommentPolicy(meta.Text)
-		if decision.Outcome == commentPolicyReject {
+		// Charge the limits before the policy check: it may call the
+		// classifier, which costs more than the write itself.
+		if !utils.EnforceRateLimit(c, "interactions.create", 1) || !utils.EnforceRateLimit(c, "comments.create", 1) {
+			return
+		}
+		commentDecision = evaluateCommentPolicyForTenant(c.Request.Context(), db, contentItem.TenantID, meta.Text)
+		if commentDecision.Outcome == commentPolicyReject {
 			c.JSON(http.StatusUnprocessableEntity, utils.HTTPError{
 				Code:    http.StatusUnprocessableEntity,
 				Message: "Comment violates the community safety policy",
@@ -290,6 +304,22 @@ func CreateInteraction(c *gin.Context) {
 		// payload smuggling and makes duplicate detection deterministic.
 		canonical, _ := json.Marshal(meta)
 		req.Metadata = canonical
+
+		if req.ParentID != nil {
+			parentID, err := uuid.Parse(strings.TrimSpace(*req.ParentID))
+			if err != nil {
+				c.JSON(http.StatusBadRequest, utils.HTTPError{Code: http.StatusBadRequest, Message: "Invalid parent_id"})
+				return
+			}
+			var parent models.UserInteraction
+			if err := visibleCommentQuery(db).
+				Where("public_id = ? AND content_item_id = ? AND comment_deleted_at IS NULL", parentID, contentItemID).
+				First(&parent).Error; err != nil {
+				c.JSON(http.StatusNotFound, utils.HTTPError{Code: http.StatusNotFound, Message: "Parent comment not found"})
+				return
+			}
+			commentParent = &parent
+		}
 	}
 
 	// Build interaction
@@ -299,15 +329,19 @@ func CreateInteraction(c *gin.Context) {
 		Metadata:      req.Metadata,
 	}
 	if req.InteractionType == models.InteractionTypeComment {
-		var meta commentMetadata
-		_ = json.Unmarshal(req.Metadata, &meta)
-		decision := evaluateCommentPolicy(meta.Text)
-		status := string(decision.Outcome)
+		status := string(commentDecision.Outcome)
 		interaction.CommentModerationStatus = &status
-		if decision.Reason != "" {
-			reason := decision.Reason
+		if commentDecision.Reason != "" {
+			reason := commentDecision.Reason
 			interaction.CommentModerationReason = &reason
 		}
+		if commentDecision.Signals != nil {
+			encoded, _ := json.Marshal(commentDecision.Signals)
+			interaction.CommentModerationSignals = datatypes.JSON(encoded)
+		}
+		if commentParent != nil {
+			interaction.CommentParentID, interaction.CommentRootID, interaction.CommentDepth = commentPlacement(*commentParent)
+		}
 	}
 
 	// Identity: prefer the authenticated user (verified JWT). Never trust the
@@ -324,21 +358,15 @@ func CreateInteraction(c *gin.Context) {
 		})
 		return
 	}
+	if interaction.SessionID != nil {
+		c.Set(utils.RateLimitSessionContextKey, *interaction.SessionID)
+	}
+	if req.InteractionType != models.InteractionTypeComment && !utils.EnforceRateLimit(c, "interactions.create", 1) {
+		return
+	}
 	if req.InteractionType == models.InteractionTypeComment {
 		var meta commentMetadata
 		_ = json.Unmarshal(req.Metadata, &meta)
-		oneMinuteAgo := time.Now().Add(-time.Minute)
-		var recentCount int64
-		if err := db.Model(&models.UserInteraction{}).
-			Where("user_id = ? AND type = ? AND created_at >= ?", *interaction.UserID, models.InteractionTypeComment, oneMinuteAgo).
-			Count(&recentCount).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, utils.HTTPError{Code: http.StatusInternalServerError, Message: "Failed to validate comment"})
-			return
-		}
-		if recentCount >= maxCommentsPerMinute {
-			c.JSON(http.StatusTooManyRequests, utils.HTTPError{Code: http.StatusTooManyRequests, Message: "Comment rate limit exceeded"})
-			return
-		}
 		var duplicateCount int64
 		if err := db.Model(&models.UserInteraction{}).
 			Where("user_id = ? AND content_item_id = ? AND type = ? AND created_at >= ?", *interaction.UserID, contentItemID, models.InteractionTypeComment, time.Now().Add(-commentDuplicateWindow)).
@@ -424,6 +452,10 @@ func CreateInteraction(c *gin.Context) {
 			}
 		}
 
+		if created {
+			recordTasteInteraction(tx, contentItem, saved)
+		}
+
 		if idempotencyKey != "" {
 			if err := tx.Create(&models.ConsumerRequestIdempotency{
 				IdentityScope:  interactionIdentityScope(interaction),
@@ -858,12 +890,28 @@ func DeleteInteraction(c *gin.Context) {
 		}
 	}
 
+	if interaction.Type == models.InteractionTypeComment {
+		if interaction.CommentDeletedAt != nil {
+			c.JSON(http.StatusNotFound, utils.HTTPError{Code: http.StatusNotFound, Message: "Interaction not found"})
+			return
+		}
+		if err := db.Transaction(func(tx *gorm.DB) error { return removeComment(tx, interaction) }); err != nil {
+			c.JSON(http.StatusInternalServerError, utils.HTTPError{
+				Code:    http.StatusInternalServerError,
+				Message: "Failed to delete interaction: " + err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, utils.ResponseMessage{Code: http.StatusOK, Message: "Interaction deleted successfully"})
+		return
+	}
+
 	if err := updateEngagementCount(db, interaction.ContentItemID, interaction.Type, -1); err != nil {
 		log.Printf("failed to decrement engagement counter for interaction %s on content %s: %v", interaction.Type, interaction.ContentItemID, err)
 	}
 
 	// Delete the interaction
-	if err := db.Delete(&interaction).Error; err != nil {
+	if err := deleteInteraction(db, contentItem, interaction); err != nil {
 		c.JSON(http.StatusInternalServerError, utils.HTTPError{
 			Code:    http.StatusInternalServerError,
 			Message: "Failed to delete interaction: " + err.Error(),
@@ -877,6 +925,22 @@ func DeleteInteraction(c *gin.Context) {
 	})
 }
 
+// deleteInteraction removes a non-comment interaction and takes it back out
+// of the taste vector in the same transaction. Only the request whose delete
+// removed the row retracts it, so a repeated unlike cannot retract twice.
+func deleteInteraction(db *gorm.DB, item models.ContentItem, interaction models.UserInteraction) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Delete(&interaction)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected > 0 {
+			retractTasteInteraction(tx, item, interaction)
+		}
+		return nil
+	})
+}
+
 // DeleteInteractionByContext removes an interaction by content + type + user/session.
 // DELETE /api/v1/interactions?content_item_id=...&type=like|bookmark&user_id=...|session_id=...
 func DeleteInteractionByContext(c *gin.Context) {
@@ -945,7 +1009,7 @@ func DeleteInteractionByContext(c *gin.Context) {
 		log.Printf("failed to decrement engagement counter for interaction %s on content %s: %v", interaction.Type, interaction.ContentItemID, err)
 	}
 
-	if err := db.Delete(&interaction).Error; err != nil {
+	if err := deleteInteraction(db, contentItem, interaction); err != nil {
 		c.JSON(http.StatusInternalServerError, utils.HTTPError{
 			Code:    http.StatusInternalServerError,
 			Message: "Failed to delete interaction: " + err.Error(),
@@ -959,125 +1023,6 @@ func DeleteInteractionByContext(c *gin.Context) {
 	})
 }
 
-// CommentItem is a single comment in a content item's comment list
-type CommentItem struct {
-	ID        uuid.UUID  `json:"id"`
-	Text      string     `json:"text"`
-	Author    string     `json:"author,omitempty"`
-	AuthorID  *uuid.UUID `json:"author_id,omitempty"`
-	IsMine    bool       `json:"is_mine"`
-	CreatedAt time.Time  `json:"created_at"`
-}
-
-// GetContentComments lists comments for a content item, newest first.
-// GET /api/v1/content/:id/comments?cursor=xxx&limit=20&session_id=xxx&user_id=xxx
-// session_id / user_id are optional and only used to mark the caller's own
-// comments (is_mine) so the client can label them.
-func GetContentComments(c *gin.Context) {
-	db := c.MustGet("db").(*gorm.DB)
-
-	contentID, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, utils.HTTPError{
-			Code:    http.StatusBadRequest,
-			Message: "Invalid content ID",
-		})
-		return
-	}
-
-	pagination, err := utils.ParseCursorParams(c.Query("cursor"), c.Query("limit"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, utils.HTTPError{
-			Code:    http.StatusBadRequest,
-			Message: "Invalid cursor: " + err.Error(),
-		})
-		return
-	}
-
-	var contentItem models.ContentItem
-	if err := publicContentQuery(db).Where("public_id = ?", contentID).First(&contentItem).Error; err != nil {
-		c.JSON(http.StatusNotFound, utils.HTTPError{Code: http.StatusNotFound, Message: "Content item not found"})
-		return
-	}
-
-	query := db.Model(&models.UserInteraction{}).
-		Where("content_item_id = ?", contentID).
-		Where("type = ?", models.InteractionTypeComment).
-		Where("comment_moderation_status IS NULL OR comment_moderation_status = ?", string(commentPolicyAllow)).
-		Order("created_at DESC, public_id DESC")
-	if uid, authenticated := authedUserID(c); authenticated {
-		var blockedIDs []uuid.UUID
-		if err := db.Model(&models.UserBlock{}).
-			Where("tenant_id = ? AND user_id = ?", "default", uid).
-			Pluck("blocked_user_id", &blockedIDs).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, utils.HTTPError{Code: http.StatusInternalServerError, Message: "Failed to load comment visibility"})
-			return
-		}
-		if len(blockedIDs) > 0 {
-			query = query.Where("user_id IS NULL OR user_id NOT IN ?", blockedIDs)
-		}
-	}
-
-	if !pagination.Timestamp.IsZero() {
-		query = query.Where(
-			"created_at < ? OR (created_at = ? AND public_id < ?)",
-			pagination.Timestamp,
-			pagination.Timestamp,
-			pagination.LastID,
-		)
-	}
-
-	var interactions []models.UserInteraction
-	if err := query.Limit(pagination.Limit + 1).Find(&interactions).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, utils.HTTPError{
-			Code:    http.StatusInternalServerError,
-			Message: "Failed to fetch comments: " + err.Error(),
-		})
-		return
-	}
-
-	hasMore := len(interactions) > pagination.Limit
-	if hasMore {
-		interactions = interactions[:pagination.Limit]
-	}
-
-	sessionID := c.Query("session_id")
-	var callerUserID *uuid.UUID
-	if uid, ok := authedUserID(c); ok {
-		callerUserID = &uid
-	}
-
-	items := make([]CommentItem, 0, len(interactions))
-	for _, in := range interactions {
-		var meta commentMetadata
-		if err := json.Unmarshal(in.Metadata, &meta); err != nil || strings.TrimSpace(meta.Text) == "" {
-			continue // skip malformed rows rather than failing the whole list
-		}
-		isMine := (callerUserID != nil && in.UserID != nil && *in.UserID == *callerUserID) ||
-			(sessionID != "" && in.SessionID != nil && *in.SessionID == sessionID)
-		items = append(items, CommentItem{
-			ID:        in.PublicID,
-			Text:      meta.Text,
-			Author:    meta.Author,
-			AuthorID:  in.UserID,
-			IsMine:    isMine,
-			CreatedAt: in.CreatedAt,
-		})
-	}
-
-	var nextCursor *string
-	if hasMore && len(interactions) > 0 {
-		last := interactions[len(interactions)-1]
-		cursor := utils.EncodeCursor(last.CreatedAt, last.PublicID)
-		nextCursor = &cursor
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"cursor": nextCursor,
-		"items":  items,
-	})
-}
-
 // HistoryItem is a single entry in the user's watch history
 type HistoryItem struct {
 	ContentID       uuid.UUID `json:"content_id"`
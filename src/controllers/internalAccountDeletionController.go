@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"net/http"
+	"sort"
 	"strings"
 
 	"content-management-system/src/models"
@@ -32,17 +33,23 @@ func InternalDeleteUserProductData(c *gin.Context) {
 	err = db.Transaction(func(tx *gorm.DB) error {
 		// Remove the account's interactions, but first preserve the rows needed
 		// to keep the denormalized engagement counts and comment-report graph
-		// consistent after the erasure.
+		// consistent after the erasure. Comments go through removeComment, so
+		// threads other users replied in keep their shape.
 		var interactions []models.UserInteraction
 		if err := tx.Where("user_id = ?", userID).Find(&interactions).Error; err != nil {
 			return err
 		}
+		var comments []models.UserInteraction
 		commentIDs := make([]uuid.UUID, 0)
 		for _, interaction := range interactions {
 			if interaction.Type == models.InteractionTypeComment {
+				comments = append(comments, interaction)
 				commentIDs = append(commentIDs, interaction.PublicID)
 			}
 		}
+		// Deepest first, so a reply chain of the account's own comments is
+		// removed outright instead of leaving tombstones behind.
+		sort.SliceStable(comments, func(i, j int) bool { return comments[i].CommentDepth > comments[j].CommentDepth })
 		statements := []struct {
 			query string
 			args  []any
@@ -56,9 +63,23 @@ func InternalDeleteUserProductData(c *gin.Context) {
 			{"DELETE FROM user_source_prefs WHERE tenant_id = ? AND user_id = ?", []any{req.TenantID, userID}},
 			{"DELETE FROM user_topic_affinity WHERE tenant_id = ? AND user_id = ?", []any{req.TenantID, userID}},
 			{"DELETE FROM user_category_affinity WHERE tenant_id = ? AND user_id = ?", []any{req.TenantID, userID}},
+			{"DELETE FROM user_taste_vectors WHERE tenant_id = ? AND identity = ?", []any{req.TenantID, identityScope}},
 			{"DELETE FROM preference_affinity_recompute_queue WHERE tenant_id = ? AND user_id = ?", []any{req.TenantID, userID}},
-			{"DELETE FROM user_interactions WHERE user_id = ?", []any{userID}},
+			// Reactions the account left come off the scores of the comments
+			// they were on.
+			{"UPDATE user_interactions SET comment_score = GREATEST(comment_score - r.n, 0) FROM (SELECT comment_id, COUNT(*) AS n FROM comment_reactions WHERE user_id = ? GROUP BY comment_id) r WHERE user_interactions.public_id = r.comment_id", []any{userID}},
+			{"DELETE FROM comment_reactions WHERE user_id = ?", []any{userID}},
+			{"DELETE FROM comment_revisions WHERE edited_by = ?", []any{userID}},
+			{"DELETE FROM user_interactions WHERE user_id = ? AND type <> ?", []any{userID, models.InteractionTypeComment}},
+			// Comments that other users replied to stay as anonymous tombstones.
+			{"UPDATE user_interactions SET user_id = NULL, session_id = NULL WHERE user_id = ? AND type = ?", []any{userID, models.InteractionTypeComment}},
 			{"DELETE FROM auth_suspensions WHERE user_id = ? AND tenant_id = ?", []any{userID, req.TenantID}},
+			{"DELETE FROM personal_data_exports WHERE user_id = ? AND tenant_id = ?", []any{userID, req.TenantID}},
+			{"DELETE FROM bookmark_collection_items WHERE collection_id IN (SELECT public_id FROM bookmark_collections WHERE user_id = ? AND tenant_id = ?)", []any{userID, req.TenantID}},
+			{"DELETE FROM bookmark_collections WHERE user_id = ? AND tenant_id = ?", []any{userID, req.TenantID}},
+			{"DELETE FROM story_follows WHERE user_id = ? AND tenant_id = ?", []any{userID, req.TenantID}},
+			{"DELETE FROM digest_deliveries WHERE user_id = ? AND tenant_id = ?", []any{userID, req.TenantID}},
+			{"DELETE FROM digest_subscriptions WHERE user_id = ? AND tenant_id = ?", []any{userID, req.TenantID}},
 		}
 		if len(commentIDs) > 0 {
 			// Other users' report idempotency records reference the comment report
@@ -75,12 +96,22 @@ func InternalDeleteUserProductData(c *gin.Context) {
 				return err
 			}
 		}
+		// removeComment clears each comment's text, reactions and edit history
+		// and keeps the comment count in step.
+		for _, comment := range comments {
+			if err := removeComment(tx, comment); err != nil {
+				return err
+			}
+		}
 		for _, statement := range statements {
 			if err := tx.Exec(statement.query, statement.args...).Error; err != nil {
 				return err
 			}
 		}
 		for _, interaction := range interactions {
+			if interaction.Type == models.InteractionTypeComment {
+				continue
+			}
 			if err := updateEngagementCount(tx, interaction.ContentItemID, interaction.Type, -1); err != nil {
 				return err
 			}
@@ -639,6 +639,9 @@ func InternalCreateContentItem(c *gin.Context) {
 		if err := feedstate.SyncMediaMembership(tx, item); err != nil {
 			return err
 		}
+		if err := feedstate.CommitReady(tx, item); err != nil {
+			return err
+		}
 		if contentSourceID == nil {
 			return nil
 		}
@@ -781,6 +784,7 @@ func InternalUpdateContentStatus(c *gin.Context) {
 		c.JSON(http.StatusConflict, gin.H{"error": "CMS content-stage reducer owns lifecycle in durable mode"})
 		return
 	}
+	wasReady := item.Status == models.ContentStatusReady
 	item.Status = models.ContentStatus(strings.ToUpper(req.Status))
 	if req.FeedVisibility != nil && strings.TrimSpace(*req.FeedVisibility) != "" {
 		item.FeedVisibility = strings.TrimSpace(*req.FeedVisibility)
@@ -812,6 +816,11 @@ func InternalUpdateContentStatus(c *gin.Context) {
 		if err := feedstate.SyncMediaMembership(tx, item); err != nil {
 			return err
 		}
+		if !wasReady {
+			if err := feedstate.CommitReady(tx, item); err != nil {
+				return err
+			}
+		}
 		return appendItemProcessingEvent(tx, item, "content_status", "completed", "aggregation", "content_status_updated", map[string]interface{}{"status": string(item.Status)})
 	}); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update status"})
@@ -4,6 +4,7 @@ import (
 	"content-management-system/src/models"
 	"content-management-system/src/spaceid"
 	"content-management-system/src/utils"
+	"context"
 	"encoding/json"
 	"net/http"
 	"strings"
@@ -128,7 +129,7 @@ func InternalCreateSourceSuggestions(c *gin.Context) {
 
 	// Best-effort semantic relevance + novelty scoring (no-op if Enrichment is
 	// down or there's no profile — rows still insert with a null score).
-	scoreSuggestionRows(db, tenantID, profileID, rows, cfg.DupThreshold, cfg.DupPenalty)
+	scoreSuggestionRows(c.Request.Context(), db, tenantID, profileID, rows, cfg.DupThreshold, cfg.DupPenalty)
 
 	if len(rows) > 0 {
 		// Upsert on (tenant_id, canonical_key). Refresh candidate data but do NOT
@@ -182,7 +183,7 @@ func InternalListEnabledProfiles(c *gin.Context) {
 // scoreSuggestionRows fills relevance_score on each row: cosine(profile, sample)
 // adjusted by a novelty penalty for near-duplicate (mirror) content. Best-effort
 // — leaves scores null on any failure so the loop never breaks.
-func scoreSuggestionRows(db *gorm.DB, tenantID string, profileID *uint, rows []models.SourceSuggestion, dupThreshold, dupPenalty float64) {
+func scoreSuggestionRows(ctx context.Context, db *gorm.DB, tenantID string, profileID *uint, rows []models.SourceSuggestion, dupThreshold, dupPenalty float64) {
 	if profileID == nil || len(rows) == 0 {
 		return
 	}
@@ -190,7 +191,7 @@ func scoreSuggestionRows(db *gorm.DB, tenantID string, profileID *uint, rows []m
 	if err := db.Where("id = ?", *profileID).First(&profile).Error; err != nil {
 		return
 	}
-	profileVec, ok := ensureProfileEmbedding(db, &profile)
+	profileVec, ok := ensureProfileEmbedding(ctx, db, &profile)
 	if !ok {
 		return
 	}
@@ -199,7 +200,7 @@ func scoreSuggestionRows(db *gorm.DB, tenantID string, profileID *uint, rows []m
 		if len(titles) == 0 {
 			continue
 		}
-		vecs, sampleSpaceID, err := embedBatchViaEnrichmentWithSpace(titles)
+		vecs, sampleSpaceID, err := embedBatchViaEnrichmentWithSpace(ctx, titles)
 		if err != nil || len(vecs) == 0 || profile.EmbeddingSpaceID == nil || sampleSpaceID != *profile.EmbeddingSpaceID {
 			continue
 		}
@@ -254,7 +255,7 @@ func meanVector(vecs [][]float32) []float32 {
 
 // ensureProfileEmbedding returns the profile's cached embedding, computing and
 // persisting it on first use. Returns (vec, true) on success.
-func ensureProfileEmbedding(db *gorm.DB, profile *models.DiscoveryProfile) ([]float32, bool) {
+func ensureProfileEmbedding(ctx context.Context, db *gorm.DB, profile *models.DiscoveryProfile) ([]float32, bool) {
 	expectedProducer := ""
 	if _, _, producer := textSurfaceStamp(spaceid.RecipeDiscoveryProfile); producer != nil {
 		expectedProducer = *producer
@@ -268,7 +269,7 @@ func ensureProfileEmbedding(db *gorm.DB, profile *models.DiscoveryProfile) ([]fl
 	if text == "" {
 		return nil, false
 	}
-	vec, observedSpace, err := embedQueryViaEnrichmentWithSpace(text)
+	vec, observedSpace, err := embedQueryViaEnrichmentWithSpace(ctx, text)
 	if err != nil || len(vec) == 0 {
 		return nil, false
 	}
@@ -3,6 +3,7 @@ package controllers
 import (
 	"content-management-system/src/artifacts"
 	"content-management-system/src/contentstage"
+	"content-management-system/src/lifecycle"
 	"content-management-system/src/models"
 	"encoding/json"
 	"net/http"
@@ -300,19 +301,13 @@ func InternalCreateTranscript(c *gin.Context) {
 			itemCopy := item
 			go func() {
 				if text := buildEmbeddingText(&itemCopy); text != "" {
-					_ = triggerEmbedding(text, itemCopy.PublicID.String())
+					_ = triggerEmbedding(lifecycle.Root(), text, itemCopy.PublicID.String())
 				}
 			}()
 		}
 		if !strings.HasPrefix(source, "stt_") && quality.Status == models.TranscriptQualityAutoRepair {
 			if job, triggered, _, _, err := createTranscriptionJobForItem(db, &item, models.TranscriptionTriggerAutoQuality, false); err == nil && triggered {
-				itemCopy := item
-				jobID := job.PublicID.String()
-				go func() {
-					if err := submitTranscriptionJobToMedia(db, &itemCopy, jobID); err != nil {
-						_ = updateTranscriptionJobTerminal(db, jobID, models.TranscriptionJobStatusFailed, err.Error())
-					}
-				}()
+				goSubmitTranscriptionJob(db, item, job.PublicID.String())
 			}
 		}
 	}
@@ -4,6 +4,7 @@ import (
 	"content-management-system/src/intelligence"
 	"content-management-system/src/models"
 	"content-management-system/src/supply"
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
@@ -217,7 +218,7 @@ func triggerCirculationAtomization(db *gorm.DB, tenantID string, parentID uuid.U
 		"excerpt":       parent.Excerpt,
 		"bodyText":      parent.BodyText,
 	}
-	_, statusCode, err := proxyAggregationRequest(aggregationBaseURL, "/admin/atomization/parents/"+parent.PublicID.String()+"/atomize", authorization, payload)
+	_, statusCode, err := proxyAggregationRequest(context.Background(), aggregationBaseURL, "/admin/atomization/parents/"+parent.PublicID.String()+"/atomize", authorization, payload)
 	if err != nil {
 		markCirculationAtomizationRunFailed(db, run.ID, err.Error())
 		return err
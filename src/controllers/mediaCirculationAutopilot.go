@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -15,6 +16,7 @@ import (
 	"gorm.io/gorm"
 
 	"content-management-system/src/intelligence"
+	"content-management-system/src/lifecycle"
 	"content-management-system/src/models"
 )
 
@@ -760,7 +762,7 @@ func runMediaCirculationAutopilot(db *gorm.DB, tenantID string, opts mediaAutopi
 	if blockedTranscripts > 0 {
 		runner.executeTool("atomization.transcript_sweep", "Atomization sweep auto-requests transcripts for blocked >40m parents.", true,
 			gin.H{"blocked_transcripts": blockedTranscripts}, func() (interface{}, error) {
-				body, status, err := proxyAggregationPost(bearer, "/admin/atomization/sweep-now", map[string]any{"trigger": "autopilot"})
+				body, status, err := proxyAggregationPost(context.Background(), bearer, "/admin/atomization/sweep-now", map[string]any{"trigger": "autopilot"})
 				if err != nil {
 					return nil, err
 				}
@@ -929,14 +931,14 @@ func pluralizeVerdicts(earned []string) string {
 // one-minute ticker fires runs for tenants whose interval has elapsed. One run
 // per tenant at a time; pause and disable are respected without touching
 // policy. Mirrors the News StartCirculationAutomation pattern.
-func StartMediaCirculationAutopilotHeartbeat(db *gorm.DB) {
-	go func() {
+func StartMediaCirculationAutopilotHeartbeat(ctx context.Context, db *gorm.DB) {
+	lifecycle.Go(ctx, "media-circulation-autopilot", func(ctx context.Context) {
 		ticker := time.NewTicker(1 * time.Minute)
 		defer ticker.Stop()
-		for range ticker.C {
+		for lifecycle.Wait(ctx, ticker.C) {
 			runMediaAutopilotDue(db)
 		}
-	}()
+	})
 }
 
 func runMediaAutopilotDue(db *gorm.DB) {
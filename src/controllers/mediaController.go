@@ -5,6 +5,7 @@ import (
 	"content-management-system/src/utils"
 	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -29,6 +30,7 @@ var mediaQueryConfig = utils.QueryConfig{
 		"created_at": "media.created_at",
 		"updated_at": "media.updated_at",
 	},
+	TimeFields: map[string]bool{"created_at": true, "updated_at": true},
 	SearchableFields: map[string]string{
 		"url":  "media.url",
 		"type": "media.type",
@@ -51,6 +53,12 @@ func CreateMedia(c *gin.Context) {
 		return
 	}
 
+	media.TenantID = nil
+	if principal, ok := utils.GetAdminPrincipal(c); ok && strings.TrimSpace(principal.TenantID) != "" {
+		tenantID := strings.TrimSpace(principal.TenantID)
+		media.TenantID = &tenantID
+	}
+
 	transaction := db.Begin()
 	if err := transaction.Create(&media).Error; err != nil {
 		transaction.Rollback()
@@ -91,8 +99,10 @@ func GetMedia(c *gin.Context) {
 			return
 		}
 
+		// Posts are not preloaded: this is a public read and the relation would
+		// expose unpublished working copies from every tenant.
 		var media models.Media
-		if err := db.Preload("Post").First(&media, "public_id = ?", parsedUUID).Error; err != nil {
+		if err := db.First(&media, "public_id = ?", parsedUUID).Error; err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
 				c.JSON(http.StatusNotFound, utils.HTTPError{
 					Code:    http.StatusNotFound,
@@ -126,7 +136,6 @@ func GetMedia(c *gin.Context) {
 
 	query := db.Model(&models.Media{})
 	query = utils.ApplyQuery(query, params, mediaQueryConfig)
-	query = query.Preload("Post")
 
 	var mediaList []models.Media
 	meta, err := utils.FetchWithPagination(query, params, &mediaList)
@@ -177,15 +186,30 @@ func DeleteMedia(c *gin.Context) {
 		return
 	}
 
-	if err := db.First(&mediaToStore, "public_id = ?", parsedUUID).Error; err != nil {
+	principal, ok := requireAdminPrincipal(c)
+	if !ok {
+		return
+	}
+	tenantID := strings.TrimSpace(principal.TenantID)
+
+	if err := tenantMedia(db, tenantID).First(&mediaToStore, "public_id = ?", parsedUUID).Error; err != nil {
 		c.JSON(http.StatusNotFound, utils.HTTPError{
 			Code:    http.StatusNotFound,
 			Message: "Media not found",
 		})
 		return
 	}
+	// The pre-tenancy shared library is attached by every tenant's posts, so no
+	// single tenant may delete it.
+	if mediaToStore.TenantID == nil {
+		c.JSON(http.StatusForbidden, utils.HTTPError{
+			Code:    http.StatusForbidden,
+			Message: "Shared media cannot be deleted by a tenant",
+		})
+		return
+	}
 	transaction := db.Begin()
-	if err := transaction.Delete(&mediaToStore).Error; err != nil {
+	if err := transaction.Where("tenant_id = ?", tenantID).Delete(&mediaToStore).Error; err != nil {
 		transaction.Rollback()
 		c.JSON(http.StatusInternalServerError, utils.HTTPError{
 			Code:    http.StatusInternalServerError,
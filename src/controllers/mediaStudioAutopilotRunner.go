@@ -11,6 +11,7 @@ import (
 	"sync"
 	"time"
 
+	"content-management-system/src/lifecycle"
 	"content-management-system/src/models"
 
 	"github.com/gin-gonic/gin"
@@ -746,7 +747,7 @@ func (r *studioRunner) runProposalPhase() {
 		items = append(items, r.buildProposalItem(pc))
 	}
 
-	proposals, err := generateChapterProposalsViaEnrichment(items)
+	proposals, err := generateChapterProposalsViaEnrichment(lifecycle.Root(), items)
 	if err != nil {
 		// Degrade: queue appears unranked, nothing blocked (S10).
 		for _, pc := range queue {
@@ -1035,7 +1036,7 @@ func triggerStudioTranscriptRepairSnapshot(db *gorm.DB, tenantID string, snapsho
 		return "", &sttSkippedError{reason: reason, kind: kind}
 	}
 	jobID := job.PublicID.String()
-	if err := submitTranscriptionJobToMedia(db, &item, jobID); err != nil {
+	if err := submitTranscriptionJobToMedia(lifecycle.Root(), db, &item, jobID); err != nil {
 		return jobID, err
 	}
 	return jobID, nil
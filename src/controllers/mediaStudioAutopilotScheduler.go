@@ -1,9 +1,11 @@
 package controllers
 
 import (
+	"context"
 	"errors"
 	"time"
 
+	"content-management-system/src/lifecycle"
 	"content-management-system/src/models"
 
 	"gorm.io/gorm"
@@ -18,14 +20,14 @@ import (
 // debounce prevents chain thrash (S8). No in-process coupling to the lead runner
 // (S9) — this side only reads the lead's already-written ledger.
 
-func StartMediaStudioAutopilotHeartbeat(db *gorm.DB) {
-	go func() {
+func StartMediaStudioAutopilotHeartbeat(ctx context.Context, db *gorm.DB) {
+	lifecycle.Go(ctx, "media-studio-autopilot", func(ctx context.Context) {
 		ticker := time.NewTicker(1 * time.Minute)
 		defer ticker.Stop()
-		for range ticker.C {
+		for lifecycle.Wait(ctx, ticker.C) {
 			runStudioAutopilotDue(db)
 		}
-	}()
+	})
 }
 
 func runStudioAutopilotDue(db *gorm.DB) {
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -52,7 +53,7 @@ type studioProposalResponse struct {
 // generateChapterProposalsViaEnrichment sends a bounded batch of review cases to
 // Enrichment and returns the proposals keyed by case id. Best-effort: a
 // transport or config failure returns an error the caller degrades on.
-func generateChapterProposalsViaEnrichment(items []studioProposalItem) (map[string]studioProposal, error) {
+func generateChapterProposalsViaEnrichment(ctx context.Context, items []studioProposalItem) (map[string]studioProposal, error) {
 	if len(items) == 0 {
 		return map[string]studioProposal{}, nil
 	}
@@ -69,7 +70,7 @@ func generateChapterProposalsViaEnrichment(items []studioProposalItem) (map[stri
 	if err != nil {
 		return nil, fmt.Errorf("marshal chapter-proposal request: %w", err)
 	}
-	req, err := http.NewRequest(http.MethodPost, baseURL+"/v1/studio/chapter-proposal", bytes.NewReader(body))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+"/v1/studio/chapter-proposal", bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("build chapter-proposal request: %w", err)
 	}
@@ -78,7 +79,7 @@ func generateChapterProposalsViaEnrichment(items []studioProposalItem) (map[stri
 
 	// Enrichment bounds each case to 12s with concurrency three; 75s leaves
 	// response overhead while retaining partial valid results from the batch.
-	client := &http.Client{Timeout: 75 * time.Second}
+	client := &http.Client{Transport: ownerTransport, Timeout: 75 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("enrichment chapter-proposal request failed: %w", err)
@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"content-management-system/src/lifecycle"
 	"content-management-system/src/models"
 	"content-management-system/src/pipeline"
 	"content-management-system/src/supply"
@@ -176,17 +177,17 @@ func buildMediaSupplyStatus(db *gorm.DB, tenantID string) mediaSupplyStatusRespo
 func loadMediaSupplyOperationalHealth(db *gorm.DB, tenantID string) mediaSupplyOperationalHealth {
 	now := time.Now().UTC()
 	workers := map[string]string{
-		"source_run_scheduler": workerState(supply.SourceRunSchedulerHealthy(now)),
-		"receipt_projection":   workerState(supply.ProjectionWorkerHealthy(now)),
-		"source_recovery":      workerState(supply.RecoveryWorkerHealthy(now)),
-		"source_reconciler":    workerState(supply.ReconcilerWorkerHealthy(now)),
-		"supply_action":        workerState(supply.SupplyActionWorkerHealthy(now)),
-		"supply_evaluator":     workerState(supply.MediaSupplyEvaluatorWorkerHealthy(now)),
-		"pipeline_repair":      workerState(pipeline.WorkerHealthy(now)),
-		"artifact_coverage":    workerState(ArtifactCoverageWorkerHealthy(now)),
-		"atomization_work":     workerState(AtomizationWorkVerifierHealthy(now)),
-		"studio_clearance":     workerState(StudioClearanceWorkerHealthy(now)),
-		"upstream_observation": workerState(supply.UpstreamObservationWorkerHealthy(now)),
+		"source_run_scheduler": workerState("source-run-scheduler", supply.SourceRunSchedulerHealthy(now)),
+		"receipt_projection":   workerState("source-run-projection", supply.ProjectionWorkerHealthy(now)),
+		"source_recovery":      workerState("source-run-recovery", supply.RecoveryWorkerHealthy(now)),
+		"source_reconciler":    workerState("source-run-reconciler", supply.ReconcilerWorkerHealthy(now)),
+		"supply_action":        workerState("supply-actions", supply.SupplyActionWorkerHealthy(now)),
+		"supply_evaluator":     workerState("media-supply-evaluation", supply.MediaSupplyEvaluatorWorkerHealthy(now)),
+		"pipeline_repair":      workerState("pipeline-repair", pipeline.WorkerHealthy(now)),
+		"artifact_coverage":    workerState("artifact-coverage", ArtifactCoverageWorkerHealthy(now)),
+		"atomization_work":     workerState("atomization-work-verifier", AtomizationWorkVerifierHealthy(now)),
+		"studio_clearance":     workerState("studio-clearance", StudioClearanceWorkerHealthy(now)),
+		"upstream_observation": workerState("upstream-observation", supply.UpstreamObservationWorkerHealthy(now)),
 	}
 	owners := supply.SupplyOwnerReadinessAt(now)
 	backlogs := map[string]int64{}
@@ -219,7 +220,7 @@ func loadMediaSupplyOperationalHealth(db *gorm.DB, tenantID string) mediaSupplyO
 	}
 	state := "ready"
 	for _, value := range workers {
-		if value != "ready" {
+		if value != "ready" && value != "remote" {
 			state = "degraded"
 			break
 		}
@@ -238,7 +239,12 @@ func loadMediaSupplyOperationalHealth(db *gorm.DB, tenantID string) mediaSupplyO
 	return mediaSupplyOperationalHealth{State: state, Workers: workers, Owners: owners, Backlogs: backlogs, Metrics: supply.BuildSupplyOperationalMetrics(db, tenantID, now), Unknowns: unknowns, Generated: now}
 }
 
-func workerState(healthy bool) string {
+// workerState reads an in-process heartbeat. Workers that CMS_ROLE or
+// CMS_WORKERS place in another process report "remote" rather than stale.
+func workerState(name string, healthy bool) string {
+	if !lifecycle.Runs(name) {
+		return "remote"
+	}
 	if healthy {
 		return "ready"
 	}
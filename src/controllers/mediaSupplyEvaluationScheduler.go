@@ -10,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"content-management-system/src/lifecycle"
 	"content-management-system/src/models"
 	"content-management-system/src/supply"
 
@@ -33,15 +34,15 @@ const (
 // Each run enumerates tenants from explicit Media source ownership instead of
 // inventing a default tenant. The per-tenant PostgreSQL advisory lock makes the
 // evidence write replica-safe while preserving the evaluator's bounded scope.
-func StartMediaSupplyEvaluationHeartbeat(db *gorm.DB) {
-	go func() {
+func StartMediaSupplyEvaluationHeartbeat(ctx context.Context, db *gorm.DB) {
+	lifecycle.Go(ctx, "media-supply-evaluation", func(ctx context.Context) {
 		runMediaSupplyEvaluationDue(db)
 		ticker := time.NewTicker(mediaSupplyEvaluationInterval)
 		defer ticker.Stop()
-		for range ticker.C {
+		for lifecycle.Wait(ctx, ticker.C) {
 			runMediaSupplyEvaluationDue(db)
 		}
-	}()
+	})
 }
 
 func runMediaSupplyEvaluationDue(db *gorm.DB) {
@@ -101,6 +101,12 @@ func CreateModerationReport(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, utils.HTTPError{Code: http.StatusBadRequest, Message: "Invalid target_id"})
 		return
 	}
+	if reporterID == nil {
+		c.Set(utils.RateLimitSessionContextKey, reporterScope)
+	}
+	if !utils.EnforceRateLimit(c, "moderation.reports.create", 1) {
+		return
+	}
 	db := c.MustGet("db").(*gorm.DB)
 	if req.TargetType == models.ModerationTargetContent {
 		var content models.ContentItem
@@ -248,6 +254,9 @@ type adminCommentReview struct {
 	Reason    string     `json:"reason"`
 	AuthorID  *uuid.UUID `json:"author_id,omitempty"`
 	CreatedAt time.Time  `json:"created_at"`
+	// Signals is the classifier evidence (scores, reasons, rules), when the
+	// classifier stage ran.
+	Signals json.RawMessage `json:"signals,omitempty"`
 }
 
 func AdminListCommentReviews(c *gin.Context) {
@@ -277,6 +286,7 @@ func AdminListCommentReviews(c *gin.Context) {
 		rows = append(rows, adminCommentReview{
 			ID: comment.PublicID, ContentID: comment.ContentItemID, Text: meta.Text,
 			Reason: reason, AuthorID: comment.UserID, CreatedAt: comment.CreatedAt,
+			Signals: json.RawMessage(comment.CommentModerationSignals),
 		})
 	}
 	c.JSON(http.StatusOK, gin.H{"data": rows, "total": len(rows), "tenant_id": principal.TenantID})
@@ -308,8 +318,11 @@ func AdminResolveCommentReview(c *gin.Context) {
 			First(&comment).Error; err != nil {
 			return err
 		}
+		if err := recordCommentClassifierOutcome(tx, principal.TenantID, comment.CommentModerationSignals, body.Status == "removed"); err != nil {
+			return err
+		}
 		if body.Status == "removed" {
-			return tx.Delete(&comment).Error
+			return removeComment(tx, comment)
 		}
 		status := string(commentPolicyAllow)
 		if err := tx.Model(&comment).Updates(map[string]any{
@@ -451,7 +464,8 @@ func AdminResolveModerationReport(c *gin.Context) {
 }
 
 // AdminRemoveComment removes a comment after a human review. This is separate
-// from an owner's self-delete and decrements engagement in the same mutation.
+// from an owner's self-delete and decrements engagement in the same mutation;
+// a comment with replies is left as a tombstone.
 func AdminRemoveComment(c *gin.Context) {
 	principal, ok := requireAdminPrincipal(c)
 	if !ok {
@@ -465,13 +479,10 @@ func AdminRemoveComment(c *gin.Context) {
 	db := c.MustGet("db").(*gorm.DB)
 	err = db.Transaction(func(tx *gorm.DB) error {
 		var comment models.UserInteraction
-		if err := tx.Where("public_id = ? AND type = ?", commentID, models.InteractionTypeComment).First(&comment).Error; err != nil {
-			return err
-		}
-		if err := updateEngagementCount(tx, comment.ContentItemID, models.InteractionTypeComment, -1); err != nil {
+		if err := tx.Where("public_id = ? AND type = ? AND comment_deleted_at IS NULL", commentID, models.InteractionTypeComment).First(&comment).Error; err != nil {
 			return err
 		}
-		return tx.Delete(&comment).Error
+		return removeComment(tx, comment)
 	})
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -1042,7 +1042,7 @@ func callAggregationInternal(method, path string, payload interface{}) ([]byte,
 	if payload != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
-	client := &http.Client{Timeout: 30 * time.Second}
+	client := &http.Client{Transport: ownerTransport, Timeout: 30 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, 0, err
@@ -1,9 +1,11 @@
 package controllers
 
 import (
+	"content-management-system/src/lifecycle"
 	"content-management-system/src/models"
 	"content-management-system/src/supply"
 	"content-management-system/src/utils"
+	"context"
 	"encoding/json"
 	"math"
 	"net/http"
@@ -692,7 +694,7 @@ func RunCirculationSweepNow(c *gin.Context) {
 		c.JSON(http.StatusServiceUnavailable, authErrorResponse{Message: "Aggregation service URL is not configured", Code: "AGGREGATION_NOT_CONFIGURED"})
 		return
 	}
-	body, status, err := proxyAggregationRequest(aggregationBaseURL, "/admin/circulation/sweep-now", c.GetHeader("Authorization"), map[string]interface{}{})
+	body, status, err := proxyAggregationRequest(c.Request.Context(), aggregationBaseURL, "/admin/circulation/sweep-now", c.GetHeader("Authorization"), map[string]interface{}{})
 	if err != nil {
 		c.JSON(http.StatusBadGateway, authErrorResponse{Message: "Aggregation request failed: " + err.Error(), Code: "AGGREGATION_FAILED"})
 		return
@@ -1180,14 +1182,14 @@ func InternalReportSourceRun(c *gin.Context) {
 // single lightweight ticker fires once a minute. Tenants with Autopilot enabled
 // run the full deterministic orchestration pass when due; tenants without
 // Autopilot keep the legacy source-recommendation heartbeat unchanged.
-func StartCirculationAutomation(db *gorm.DB) {
-	go func() {
+func StartCirculationAutomation(ctx context.Context, db *gorm.DB) {
+	lifecycle.Go(ctx, "circulation-automation", func(ctx context.Context) {
 		ticker := time.NewTicker(1 * time.Minute)
 		defer ticker.Stop()
-		for range ticker.C {
+		for lifecycle.Wait(ctx, ticker.C) {
 			runCirculationAutomationDue(db)
 		}
-	}()
+	})
 }
 
 func runCirculationAutomationDue(db *gorm.DB) {
@@ -1490,5 +1492,5 @@ func triggerCirculationResync(authHeader string) {
 	if aggregationBaseURL == "" {
 		return
 	}
-	_, _, _ = proxyAggregationRequest(aggregationBaseURL, "/admin/circulation/resync-schedule", authHeader, map[string]interface{}{})
+	_, _, _ = proxyAggregationRequest(context.Background(), aggregationBaseURL, "/admin/circulation/resync-schedule", authHeader, map[string]interface{}{})
 }
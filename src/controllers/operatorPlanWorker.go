@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"content-management-system/src/lifecycle"
 	operatorpkg "content-management-system/src/operator"
 
 	"gorm.io/gorm"
@@ -14,15 +15,15 @@ const operatorPlanWorkerInterval = 5 * time.Second
 // StartOperatorPlanWorker consumes only CMS-persisted, signed action jobs.
 // Approval is the only user transition that queues work; the browser never
 // invokes an executor or supplies a credential to this worker.
-func StartOperatorPlanWorker(db *gorm.DB) {
-	go func() {
+func StartOperatorPlanWorker(ctx context.Context, db *gorm.DB) {
+	lifecycle.Go(ctx, "operator-plans", func(ctx context.Context) {
 		runOperatorPlanWorker(db)
 		ticker := time.NewTicker(operatorPlanWorkerInterval)
 		defer ticker.Stop()
-		for range ticker.C {
+		for lifecycle.Wait(ctx, ticker.C) {
 			runOperatorPlanWorker(db)
 		}
-	}()
+	})
 }
 
 func runOperatorPlanWorker(db *gorm.DB) {
@@ -6,6 +6,7 @@ import (
 	"math"
 	"time"
 
+	"content-management-system/src/lifecycle"
 	"content-management-system/src/models"
 	operatorpkg "content-management-system/src/operator"
 
@@ -17,15 +18,15 @@ const operatorScheduleHeartbeatInterval = time.Minute
 // StartOperatorScheduleHeartbeat runs only persisted, read-only templates.
 // It obtains a live IAM snapshot at every run and pauses instead of reusing a
 // stale browser credential, prior approval, or historical permission set.
-func StartOperatorScheduleHeartbeat(db *gorm.DB) {
-	go func() {
+func StartOperatorScheduleHeartbeat(ctx context.Context, db *gorm.DB) {
+	lifecycle.Go(ctx, "operator-schedules", func(ctx context.Context) {
 		runOperatorScheduleHeartbeat(db)
 		ticker := time.NewTicker(operatorScheduleHeartbeatInterval)
 		defer ticker.Stop()
-		for range ticker.C {
+		for lifecycle.Wait(ctx, ticker.C) {
 			runOperatorScheduleHeartbeat(db)
 		}
-	}()
+	})
 }
 
 func runOperatorScheduleHeartbeat(db *gorm.DB) {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"content-management-system/src/lifecycle"
 	operatorpkg "content-management-system/src/operator"
 
 	"gorm.io/gorm"
@@ -14,17 +15,17 @@ const operatorInvestigationHeartbeatInterval = time.Minute
 // StartOperatorInvestigationHeartbeat resumes only expired, persisted work.
 // It has no browser dependency: every candidate receives a fresh IAM snapshot
 // and a fresh runtime-policy read before its read-only investigation resumes.
-func StartOperatorInvestigationHeartbeat(db *gorm.DB) {
-	go func() {
+func StartOperatorInvestigationHeartbeat(ctx context.Context, db *gorm.DB) {
+	lifecycle.Go(ctx, "operator-investigations", func(ctx context.Context) {
 		// Recover once at boot instead of making a restart wait for the first
 		// interval. Every candidate still gets a new IAM snapshot and lease.
 		runOperatorInvestigationHeartbeat(db)
 		ticker := time.NewTicker(operatorInvestigationHeartbeatInterval)
 		defer ticker.Stop()
-		for range ticker.C {
+		for lifecycle.Wait(ctx, ticker.C) {
 			runOperatorInvestigationHeartbeat(db)
 		}
-	}()
+	})
 }
 
 func runOperatorInvestigationHeartbeat(db *gorm.DB) {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"content-management-system/src/lifecycle"
 	"content-management-system/src/models"
 	operatorpkg "content-management-system/src/operator"
 
@@ -15,15 +16,15 @@ const operatorShadowHeartbeatInterval = 15 * time.Minute
 // StartOperatorShadowHeartbeat runs an explicitly enrolled, read-only
 // qualification workflow. It has no HTTP route, no LLM call, and no
 // action-plan access; normal Operator availability never changes that boundary.
-func StartOperatorShadowHeartbeat(db *gorm.DB) {
-	go func() {
+func StartOperatorShadowHeartbeat(ctx context.Context, db *gorm.DB) {
+	lifecycle.Go(ctx, "operator-shadow", func(ctx context.Context) {
 		runOperatorShadowHeartbeat(db)
 		ticker := time.NewTicker(operatorShadowHeartbeatInterval)
 		defer ticker.Stop()
-		for range ticker.C {
+		for lifecycle.Wait(ctx, ticker.C) {
 			runOperatorShadowHeartbeat(db)
 		}
-	}()
+	})
 }
 
 func runOperatorShadowHeartbeat(db *gorm.DB) {
@@ -4,12 +4,15 @@ import (
 	"content-management-system/src/models"
 	"content-management-system/src/utils"
 	"net/http"
-	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+// pageQueryConfig drives the admin listing over the working copy.
 var pageQueryConfig = utils.QueryConfig{
 	DefaultLimit: 20,
 	MaxLimit:     100,
@@ -17,16 +20,21 @@ var pageQueryConfig = utils.QueryConfig{
 		{Field: "created_at", Direction: "desc"},
 	},
 	SortableFields: map[string]string{
-		"created_at": "pages.created_at",
-		"updated_at": "pages.updated_at",
-		"title":      "pages.title",
+		"created_at":   "pages.created_at",
+		"updated_at":   "pages.updated_at",
+		"published_at": "pages.published_at",
+		"title":        "pages.title",
 	},
 	FilterableFields: map[string]string{
-		"title":      "pages.title",
-		"created_at": "pages.created_at",
-		"updated_at": "pages.updated_at",
-		"id":         "pages.public_id",
+		"title":        "pages.title",
+		"slug":         "pages.slug",
+		"status":       "pages.status",
+		"created_at":   "pages.created_at",
+		"updated_at":   "pages.updated_at",
+		"published_at": "pages.published_at",
+		"id":           "pages.public_id",
 	},
+	TimeFields: map[string]bool{"created_at": true, "updated_at": true, "published_at": true},
 	SearchableFields: map[string]string{
 		"title":   "pages.title",
 		"content": "pages.content",
@@ -38,51 +46,99 @@ var pageQueryConfig = utils.QueryConfig{
 	},
 }
 
-func CreatePage(c *gin.Context) {
-	db := c.MustGet("db").(*gorm.DB)
-	var page models.Page
+// publicPageQueryConfig reads the published revision (joined as r).
+var publicPageQueryConfig = utils.QueryConfig{
+	DefaultLimit: 20,
+	MaxLimit:     100,
+	DefaultSort: []utils.SortParam{
+		{Field: "published_at", Direction: "desc"},
+	},
+	SortableFields: map[string]string{
+		"created_at":   "pages.created_at",
+		"published_at": "pages.published_at",
+		"title":        "r.title",
+	},
+	FilterableFields: map[string]string{
+		"title":        "r.title",
+		"slug":         "pages.slug",
+		"created_at":   "pages.created_at",
+		"published_at": "pages.published_at",
+		"id":           "pages.public_id",
+	},
+	TimeFields: map[string]bool{"created_at": true, "published_at": true},
+	SearchableFields: map[string]string{
+		"title":   "r.title",
+		"content": "r.content",
+	},
+	DefaultSearchFields: []string{"title", "content"},
+	FieldDefaultOperators: map[string]string{
+		"title":   "contains",
+		"content": "contains",
+	},
+}
+
+// publicPage is a page as readers see it: the published revision, with
+// content as sanitized HTML.
+type publicPage struct {
+	PublicID    uuid.UUID  `json:"id"`
+	Slug        string     `json:"slug"`
+	Title       string     `json:"title"`
+	Content     string     `gorm:"-" json:"content"`
+	Excerpt     string     `gorm:"-" json:"excerpt"`
+	Revision    int        `json:"revision"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	// Source, RenderedHTML and RenderedExcerpt are the stored columns
+	// behind Content and Excerpt.
+	Source          string  `json:"-"`
+	RenderedHTML    *string `json:"-"`
+	RenderedExcerpt *string `json:"-"`
+}
+
+const publicPageColumns = "pages.public_id, pages.slug, r.title, r.content AS source, r.content_html AS rendered_html, r.excerpt AS rendered_excerpt, r.revision, pages.published_at, pages.created_at, pages.updated_at"
 
-	if err := c.ShouldBindJSON(&page); err != nil {
+func (p *publicPage) render() {
+	p.Content, p.Excerpt = publicEditorialHTML(p.RenderedHTML, p.RenderedExcerpt, p.Source)
+}
+
+// CreatePage handles POST /pages. As before revisions existed, the page is
+// published at revision 1 unless status=draft; publish_at schedules it. content
+// is Markdown unless content_format is html; either way it is sanitized.
+func CreatePage(c *gin.Context) {
+	principal, ok := requireAdminPrincipal(c)
+	if !ok {
+		return
+	}
+	var req editorialWriteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, utils.HTTPError{
-			Data:    page,
 			Code:    http.StatusBadRequest,
 			Message: "Invalid request body: " + err.Error(),
 		})
 		return
 	}
-
-	transaction := db.Begin()
-	if err := transaction.Create(&page).Error; err != nil {
-		transaction.Rollback()
-		c.JSON(http.StatusInternalServerError, utils.HTTPError{
-			Data:    page,
-			Code:    http.StatusInternalServerError,
-			Message: "Failed to create page: " + err.Error(),
-		})
-		return
-	}
-
-	if err := transaction.Commit().Error; err != nil {
-		transaction.Rollback()
-		c.JSON(http.StatusInternalServerError, utils.HTTPError{
-			Data:    page,
-			Code:    http.StatusInternalServerError,
-			Message: "Failed to create page: " + err.Error(),
+	if strings.TrimSpace(derefStr(req.Title)) == "" || req.Content == nil {
+		c.JSON(http.StatusBadRequest, utils.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid request body: title and content are required",
 		})
 		return
 	}
 
-	c.JSON(http.StatusCreated, utils.ResponseMessage{
-		Data:    page,
-		Code:    http.StatusCreated,
-		Message: "Page created successfully",
-	})
+	page := models.Page{Title: strings.TrimSpace(*req.Title), Content: *req.Content}
+	createEditorialDocument(c, pageEditorial, &page, req, principal, nil)
 }
 
+// GetPages handles GET /pages: published pages of the public tenant.
 func GetPages(c *gin.Context) {
 	db := c.MustGet("db").(*gorm.DB)
+	tenantID, ok := publicEditorialTenant(c)
+	if !ok {
+		return
+	}
 
-	params, err := utils.ParseQueryParams(c, pageQueryConfig)
+	params, err := utils.ParseQueryParams(c, publicPageQueryConfig)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, utils.HTTPError{
 			Code:    http.StatusBadRequest,
@@ -91,10 +147,10 @@ func GetPages(c *gin.Context) {
 		return
 	}
 
-	query := db.Model(&models.Page{})
-	query = utils.ApplyQuery(query, params, pageQueryConfig)
+	query := publishedEditorialQuery(db, pageEditorial, tenantID).Select(publicPageColumns)
+	query = utils.ApplyQuery(query, params, publicPageQueryConfig)
 
-	var pages []models.Page
+	pages := []publicPage{}
 	meta, err := utils.FetchWithPagination(query, params, &pages)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, utils.HTTPError{
@@ -103,6 +159,9 @@ func GetPages(c *gin.Context) {
 		})
 		return
 	}
+	for i := range pages {
+		pages[i].render()
+	}
 
 	links := utils.BuildPaginationLinks(c, meta)
 
@@ -115,27 +174,29 @@ func GetPages(c *gin.Context) {
 	})
 }
 
+// GetPage handles GET /pages/:id where :id is the page UUID or its slug.
 func GetPage(c *gin.Context) {
 	db := c.MustGet("db").(*gorm.DB)
-	var page models.Page
-	pageID := c.Param("id")
+	tenantID, ok := publicEditorialTenant(c)
+	if !ok {
+		return
+	}
 
-	if _, err := strconv.Atoi(pageID); err != nil {
+	query, ok := whereEditorialIdentifier(publishedEditorialQuery(db, pageEditorial, tenantID), pageEditorial, c.Param("id"))
+	if !ok {
 		c.JSON(http.StatusBadRequest, utils.HTTPError{
-			Data:    page,
 			Code:    http.StatusBadRequest,
 			Message: "Invalid page ID",
 		})
 		return
 	}
 
-	if err := db.First(&page, pageID).Error; err != nil {
-		c.JSON(http.StatusNotFound, utils.HTTPError{
-			Code:    http.StatusNotFound,
-			Message: "Page not found",
-		})
+	var page publicPage
+	if err := query.Select(publicPageColumns).Take(&page).Error; err != nil {
+		editorialLookupError(c, "Page", err)
 		return
 	}
+	page.render()
 
 	c.JSON(http.StatusOK, utils.ResponseMessage{
 		Data:    page,
@@ -144,110 +205,80 @@ func GetPage(c *gin.Context) {
 	})
 }
 
+// UpdatePage handles PUT /pages/:id. Title/content changes append a revision;
+// readers keep the published revision until the page is published again.
 func UpdatePage(c *gin.Context) {
-	db := c.MustGet("db").(*gorm.DB)
-	var page models.Page
-
-	pageID := c.Param("id")
-	if _, err := strconv.Atoi(pageID); err != nil {
-		c.JSON(http.StatusBadRequest, utils.HTTPError{
-			Code:    http.StatusBadRequest,
-			Message: "Invalid page ID",
-		})
-		return
-	}
-	if err := db.First(&page, pageID).Error; err != nil {
-		c.JSON(http.StatusNotFound, utils.HTTPError{
-			Data:    page,
-			Code:    http.StatusNotFound,
-			Message: "Page not found",
-		})
-		return
-	}
-	if err := c.ShouldBindJSON(&page); err != nil {
-		c.JSON(http.StatusBadRequest, utils.HTTPError{
-			Data:    page,
-			Code:    http.StatusBadRequest,
-			Message: "Invalid page data",
-		})
-		return
-	}
-
-	transaction := db.Begin()
-	if err := transaction.Save(&page).Error; err != nil {
-		transaction.Rollback()
-		c.JSON(http.StatusInternalServerError, utils.HTTPError{
-			Code:    http.StatusInternalServerError,
-			Message: "Failed to update page: " + err.Error(),
-		})
-		return
-	}
-	if err := transaction.Commit().Error; err != nil {
-		c.JSON(http.StatusInternalServerError, utils.HTTPError{
-			Data:    page,
-			Code:    http.StatusInternalServerError,
-			Message: "Failed to update page: " + err.Error(),
-		})
-		return
-	}
-
-	c.JSON(http.StatusOK, utils.ResponseMessage{
-		Data:    page,
-		Code:    http.StatusOK,
-		Message: "Page updated successfully",
+	updateEditorialDocument(c, pageEditorial, func(_ *gorm.DB, doc models.EditorialDocument, req editorialWriteRequest) (bool, string, error) {
+		page := doc.(*models.Page)
+		if req.Author != nil || req.MediaIDs != nil || req.Media != nil {
+			return false, "Pages have no author or media", nil
+		}
+		changed := false
+		if req.Title != nil {
+			title := strings.TrimSpace(*req.Title)
+			if title == "" {
+				return false, "title cannot be empty", nil
+			}
+			changed = changed || title != page.Title
+			page.Title = title
+		}
+		if req.Content != nil {
+			changed = changed || *req.Content != page.Content
+			page.Content = *req.Content
+		}
+		return changed, "", nil
 	})
 }
 
+// DeletePage handles DELETE /pages/:id, removing the page and its history.
 func DeletePage(c *gin.Context) {
-	db := c.MustGet("db").(*gorm.DB)
-	var page models.Page
-
-	pageID := c.Param("id")
-	if _, err := strconv.Atoi(pageID); err != nil {
-		c.JSON(http.StatusBadRequest, utils.HTTPError{
-			Data:    page,
-			Code:    http.StatusBadRequest,
-			Message: "Invalid page ID",
-		})
-		return
+	// Older clients send the page as the body; reject it only when malformed.
+	if c.Request.ContentLength != 0 {
+		var ignored map[string]interface{}
+		if err := c.ShouldBindJSON(&ignored); err != nil {
+			c.JSON(http.StatusBadRequest, utils.HTTPError{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid page data",
+			})
+			return
+		}
 	}
-	if err := c.ShouldBindJSON(&page); err != nil {
-		c.JSON(http.StatusBadRequest, utils.HTTPError{
-			Data:    page,
-			Code:    http.StatusBadRequest,
-			Message: "Invalid page data",
-		})
-		return
-	}
-	if err := db.First(&page, pageID).Error; err != nil {
-		c.JSON(http.StatusNotFound, utils.HTTPError{
-			Data:    page,
-			Code:    http.StatusNotFound,
-			Message: "Page not found",
-		})
-		return
-	}
-	transaction := db.Begin()
-	if err := transaction.Delete(&page).Error; err != nil {
-		transaction.Rollback()
-		c.JSON(http.StatusInternalServerError, utils.HTTPError{
-			Code:    http.StatusInternalServerError,
-			Message: "Failed to delete page: " + err.Error(),
-		})
-		return
-	}
-	if err := transaction.Commit().Error; err != nil {
-		transaction.Rollback()
-		c.JSON(http.StatusInternalServerError, utils.HTTPError{
-			Data:    page,
-			Code:    http.StatusInternalServerError,
-			Message: "Failed to delete page: " + err.Error(),
-		})
+	doc, ok := deleteEditorialDocument(c, pageEditorial)
+	if !ok {
 		return
 	}
 	c.JSON(http.StatusOK, utils.ResponseMessage{
-		Data:    page,
+		Data:    doc,
 		Code:    http.StatusOK,
 		Message: "Page deleted successfully",
 	})
 }
+
+// ListAdminPages handles GET /admin/pages: every page of the tenant.
+func ListAdminPages(c *gin.Context) {
+	listEditorialDocuments(c, pageEditorial, pageQueryConfig, &[]models.Page{})
+}
+
+// GetAdminPage handles GET /admin/pages/:id (working copy and state).
+func GetAdminPage(c *gin.Context) { getEditorialDocument(c, pageEditorial) }
+
+// ListPageRevisions handles GET /admin/pages/:id/revisions.
+func ListPageRevisions(c *gin.Context) { listEditorialRevisions(c, pageEditorial) }
+
+// GetPageRevision handles GET /admin/pages/:id/revisions/:revision.
+func GetPageRevision(c *gin.Context) { getEditorialRevision(c, pageEditorial) }
+
+// DiffPageRevisions handles GET /admin/pages/:id/diff?from=&to=.
+func DiffPageRevisions(c *gin.Context) { diffEditorialRevisions(c, pageEditorial) }
+
+// RestorePageRevision handles POST /admin/pages/:id/revisions/:revision/restore.
+func RestorePageRevision(c *gin.Context) { restoreEditorialRevision(c, pageEditorial) }
+
+// PublishPage handles POST /admin/pages/:id/publish.
+func PublishPage(c *gin.Context) { publishEditorialDocument(c, pageEditorial) }
+
+// UnpublishPage handles POST /admin/pages/:id/unpublish.
+func UnpublishPage(c *gin.Context) { unpublishEditorialDocument(c, pageEditorial) }
+
+// ArchivePage handles POST /admin/pages/:id/archive.
+func ArchivePage(c *gin.Context) { archiveEditorialDocument(c, pageEditorial) }
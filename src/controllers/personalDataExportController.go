@@ -0,0 +1,262 @@
+package controllers
+
+import (
+	"content-management-system/src/models"
+	"content-management-system/src/utils"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+const (
+	// personalDataExportRetention is how long a built archive can be
+	// downloaded before the worker clears it.
+	personalDataExportRetention = 7 * 24 * time.Hour
+	// personalDataExportLinkTTL bounds each signed download link; the status
+	// endpoint mints a fresh one on every poll.
+	personalDataExportLinkTTL = 15 * time.Minute
+)
+
+var errPersonalDataExportUnsigned = errors.New("personal data export signing secret is not configured")
+
+// personalDataExportSecret signs download links. It is a dedicated secret:
+// links must not be forgeable by anything else that holds JWT_SECRET.
+func personalDataExportSecret() ([]byte, error) {
+	if secret := strings.TrimSpace(os.Getenv("PERSONAL_DATA_EXPORT_SECRET")); secret != "" {
+		return []byte(secret), nil
+	}
+	return nil, errPersonalDataExportUnsigned
+}
+
+// personalDataExportSignature binds a link to one export, one archive (its
+// digest, so a rebuilt archive invalidates old links) and an expiry.
+func personalDataExportSignature(secret []byte, exportID uuid.UUID, archiveSHA256 string, expires int64) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte("personal-data-export-v1\n" + exportID.String() + "\n" + archiveSHA256 + "\n" + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func personalDataExportDownloadURL(secret []byte, export models.PersonalDataExport, now time.Time) (string, time.Time) {
+	expiresAt := now.Add(personalDataExportLinkTTL)
+	if export.ExpiresAt != nil && export.ExpiresAt.Before(expiresAt) {
+		expiresAt = *export.ExpiresAt
+	}
+	expires := expiresAt.Unix()
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("signature", personalDataExportSignature(secret, export.PublicID, export.ArchiveSHA256, expires))
+	return "/api/v1/data-exports/" + export.PublicID.String() + "/download?" + query.Encode(), time.Unix(expires, 0).UTC()
+}
+
+func verifyPersonalDataExportSignature(secret []byte, export models.PersonalDataExport, expiresParam, signature string, now time.Time) bool {
+	expires, err := strconv.ParseInt(expiresParam, 10, 64)
+	if err != nil || now.Unix() > expires {
+		return false
+	}
+	expected := personalDataExportSignature(secret, export.PublicID, export.ArchiveSHA256, expires)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+type personalDataExportResponse struct {
+	models.PersonalDataExport
+	DownloadURL       string     `json:"download_url,omitempty"`
+	DownloadExpiresAt *time.Time `json:"download_expires_at,omitempty"`
+}
+
+func newPersonalDataExportResponse(secret []byte, export models.PersonalDataExport, now time.Time) personalDataExportResponse {
+	response := personalDataExportResponse{PersonalDataExport: export}
+	if export.Status == models.PersonalDataExportReady && export.ExpiresAt != nil && now.Before(*export.ExpiresAt) {
+		link, expiresAt := personalDataExportDownloadURL(secret, export, now)
+		response.DownloadURL = link
+		response.DownloadExpiresAt = &expiresAt
+	}
+	return response
+}
+
+func writePrivacyAudit(db *gorm.DB, tenantID string, userID uuid.UUID, action string, exportID uuid.UUID, status string, payload map[string]any) {
+	encoded, _ := json.Marshal(payload)
+	_ = db.Create(&models.AuditLog{TenantID: tenantID, UserID: userID.String(), Action: action, TargetService: "cms", TargetResource: "personal_data_export:" + exportID.String(), Status: status, Payload: datatypes.JSON(encoded)}).Error
+}
+
+// RequestPersonalDataExport queues an export of the caller's product data. A
+// request while an earlier one is still queued or running returns that job.
+// POST /api/v1/me/data-exports
+func RequestPersonalDataExport(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+
+	uid, ok := authedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, utils.HTTPError{Code: http.StatusUnauthorized, Message: "Authentication required"})
+		return
+	}
+	secret, err := personalDataExportSecret()
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, utils.HTTPError{Code: http.StatusServiceUnavailable, Message: "Data export is not configured"})
+		return
+	}
+
+	var export models.PersonalDataExport
+	findActive := func(tx *gorm.DB) error {
+		return tx.Omit("archive").
+			Where("tenant_id = ? AND user_id = ? AND status IN ?", consumerTenant, uid, []string{models.PersonalDataExportQueued, models.PersonalDataExportRunning}).
+			Order("created_at DESC").First(&export).Error
+	}
+	err = findActive(db)
+	if err == nil {
+		c.JSON(http.StatusOK, newPersonalDataExportResponse(secret, export, time.Now().UTC()))
+		return
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, utils.HTTPError{Code: http.StatusInternalServerError, Message: "Failed to request data export"})
+		return
+	}
+	// Only a new job spends the daily allowance; re-posting while one is
+	// active is answered above.
+	if !utils.EnforceRateLimit(c, "data_exports.create", 1) {
+		return
+	}
+
+	created := false
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?)::bigint)", "personal-data-export:"+uid.String()).Error; err != nil {
+			return err
+		}
+		if err := findActive(tx); err == nil || !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		export = models.PersonalDataExport{TenantID: consumerTenant, UserID: uid, Status: models.PersonalDataExportQueued}
+		if err := tx.Create(&export).Error; err != nil {
+			return err
+		}
+		created = true
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.HTTPError{Code: http.StatusInternalServerError, Message: "Failed to request data export"})
+		return
+	}
+	status := http.StatusOK
+	if created {
+		writePrivacyAudit(db, export.TenantID, uid, "privacy.export.requested", export.PublicID, "success", nil)
+		status = http.StatusAccepted
+	}
+	c.JSON(status, newPersonalDataExportResponse(secret, export, time.Now().UTC()))
+}
+
+// ListPersonalDataExports lists the caller's recent export jobs, newest first.
+// GET /api/v1/me/data-exports
+func ListPersonalDataExports(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+
+	uid, ok := authedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, utils.HTTPError{Code: http.StatusUnauthorized, Message: "Authentication required"})
+		return
+	}
+	secret, err := personalDataExportSecret()
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, utils.HTTPError{Code: http.StatusServiceUnavailable, Message: "Data export is not configured"})
+		return
+	}
+	var exports []models.PersonalDataExport
+	if err := db.Omit("archive").Where("tenant_id = ? AND user_id = ?", consumerTenant, uid).
+		Order("created_at DESC").Limit(10).Find(&exports).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.HTTPError{Code: http.StatusInternalServerError, Message: "Failed to load data exports"})
+		return
+	}
+	now := time.Now().UTC()
+	items := make([]personalDataExportResponse, 0, len(exports))
+	for _, export := range exports {
+		items = append(items, newPersonalDataExportResponse(secret, export, now))
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
+
+// GetPersonalDataExport reports a job's status. Once the archive is ready it
+// includes a signed download link valid for personalDataExportLinkTTL.
+// GET /api/v1/me/data-exports/:id
+func GetPersonalDataExport(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+
+	uid, ok := authedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, utils.HTTPError{Code: http.StatusUnauthorized, Message: "Authentication required"})
+		return
+	}
+	exportID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.HTTPError{Code: http.StatusBadRequest, Message: "Invalid export id"})
+		return
+	}
+	secret, err := personalDataExportSecret()
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, utils.HTTPError{Code: http.StatusServiceUnavailable, Message: "Data export is not configured"})
+		return
+	}
+	var export models.PersonalDataExport
+	if err := db.Omit("archive").Where("public_id = ? AND tenant_id = ? AND user_id = ?", exportID, consumerTenant, uid).
+		First(&export).Error; err != nil {
+		c.JSON(http.StatusNotFound, utils.HTTPError{Code: http.StatusNotFound, Message: "Data export not found"})
+		return
+	}
+	c.JSON(http.StatusOK, newPersonalDataExportResponse(secret, export, time.Now().UTC()))
+}
+
+// DownloadPersonalDataExport serves a ready archive to the holder of a signed
+// link. The link is the credential, so it works from a browser without the
+// app's bearer token; it is short-lived and tied to the archive digest.
+// GET /api/v1/data-exports/:id/download?expires=…&signature=…
+func DownloadPersonalDataExport(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+
+	exportID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, utils.HTTPError{Code: http.StatusNotFound, Message: "Data export not found"})
+		return
+	}
+	secret, err := personalDataExportSecret()
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, utils.HTTPError{Code: http.StatusServiceUnavailable, Message: "Data export is not configured"})
+		return
+	}
+	now := time.Now().UTC()
+	var export models.PersonalDataExport
+	if err := db.Omit("archive").Where("public_id = ? AND status = ? AND expires_at > ?", exportID, models.PersonalDataExportReady, now).
+		First(&export).Error; err != nil {
+		c.JSON(http.StatusNotFound, utils.HTTPError{Code: http.StatusNotFound, Message: "Data export not found"})
+		return
+	}
+	if !verifyPersonalDataExportSignature(secret, export, c.Query("expires"), c.Query("signature"), now) {
+		c.JSON(http.StatusForbidden, utils.HTTPError{Code: http.StatusForbidden, Message: "Download link is invalid or expired"})
+		return
+	}
+	// The archive is only read once the link checks out.
+	var stored models.PersonalDataExport
+	if err := db.Select("archive").Where("id = ?", export.ID).First(&stored).Error; err != nil {
+		c.JSON(http.StatusNotFound, utils.HTTPError{Code: http.StatusNotFound, Message: "Data export not found"})
+		return
+	}
+	if err := db.Model(&models.PersonalDataExport{}).Where("id = ?", export.ID).
+		UpdateColumn("downloads", gorm.Expr("downloads + 1")).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.HTTPError{Code: http.StatusInternalServerError, Message: "Failed to record download"})
+		return
+	}
+	writePrivacyAudit(db, export.TenantID, export.UserID, "privacy.export.downloaded", export.PublicID, "success", map[string]any{"ip": c.ClientIP()})
+
+	c.Header("Cache-Control", "no-store")
+	c.Header("Content-Disposition", `attachment; filename="personal-data-`+export.CreatedAt.UTC().Format("20060102")+`.zip"`)
+	c.Data(http.StatusOK, "application/zip", stored.Archive)
+}
@@ -0,0 +1,504 @@
+package controllers
+
+import (
+	"archive/zip"
+	"bytes"
+	"content-management-system/src/lifecycle"
+	"content-management-system/src/models"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	personalDataExportTick        = 15 * time.Second
+	personalDataExportLease       = 5 * time.Minute
+	personalDataExportMaxAttempts = 3
+	personalDataExportBatch       = 4
+	personalDataExportFormat      = 1
+)
+
+var personalDataExportHeartbeat atomic.Int64
+
+// StartPersonalDataExportWorker builds queued exports and clears archives
+// past their retention. Jobs are claimed with a lease, so a replica that dies
+// mid-build only delays the export.
+func StartPersonalDataExportWorker(ctx context.Context, db *gorm.DB) {
+	runPersonalDataExports(ctx, db)
+	lifecycle.Go(ctx, "personal-data-exports", func(ctx context.Context) {
+		ticker := time.NewTicker(personalDataExportTick)
+		defer ticker.Stop()
+		for lifecycle.Wait(ctx, ticker.C) {
+			runPersonalDataExports(ctx, db)
+		}
+	})
+}
+
+func PersonalDataExportWorkerHealthy(now time.Time) bool {
+	last := personalDataExportHeartbeat.Load()
+	return last > 0 && now.UTC().Sub(time.Unix(0, last).UTC()) <= 6*personalDataExportTick
+}
+
+func PersonalDataExportWorkerLastHeartbeat() time.Time {
+	return lifecycle.HeartbeatTime(personalDataExportHeartbeat.Load())
+}
+
+func runPersonalDataExports(ctx context.Context, db *gorm.DB) {
+	if err := expirePersonalDataExports(db, time.Now().UTC()); err != nil {
+		log.Printf("personal data export expiry failed: %v", err)
+	}
+	for i := 0; i < personalDataExportBatch && ctx.Err() == nil; i++ {
+		job, err := claimPersonalDataExport(db, time.Now().UTC())
+		if err != nil {
+			log.Printf("personal data export claim failed: %v", err)
+			break
+		}
+		if job == nil {
+			break
+		}
+		processPersonalDataExport(db, *job)
+	}
+	personalDataExportHeartbeat.Store(time.Now().UTC().UnixNano())
+}
+
+func expirePersonalDataExports(db *gorm.DB, now time.Time) error {
+	return db.Model(&models.PersonalDataExport{}).
+		Where("status = ? AND expires_at <= ?", models.PersonalDataExportReady, now).
+		Updates(map[string]any{"status": models.PersonalDataExportExpired, "archive": nil}).Error
+}
+
+// claimPersonalDataExport takes the oldest queued job, or a running one whose
+// lease lapsed. On a queued job lease_until is the retry backoff. Every claim
+// bumps attempts, which fences the claimant's later writes (see
+// personalDataExportClaim).
+func claimPersonalDataExport(db *gorm.DB, now time.Time) (*models.PersonalDataExport, error) {
+	var job models.PersonalDataExport
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).Omit("archive").
+			Where("(status = ? AND (lease_until IS NULL OR lease_until < ?)) OR (status = ? AND lease_until < ?)",
+				models.PersonalDataExportQueued, now, models.PersonalDataExportRunning, now).
+			Order("created_at").First(&job).Error; err != nil {
+			return err
+		}
+		job.Attempts++
+		lease := now.Add(personalDataExportLease)
+		job.LeaseUntil = &lease
+		job.Status = models.PersonalDataExportRunning
+		return tx.Model(&models.PersonalDataExport{}).Where("id = ?", job.ID).Updates(map[string]any{
+			"status":      job.Status,
+			"attempts":    job.Attempts,
+			"lease_until": lease,
+		}).Error
+	})
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// personalDataExportClaim scopes an update to job while this worker still
+// holds the claim: a worker whose lease lapsed mid-build must not overwrite the
+// status or archive of the claimant that took the job over.
+func personalDataExportClaim(db *gorm.DB, job models.PersonalDataExport) *gorm.DB {
+	return db.Model(&models.PersonalDataExport{}).
+		Where("id = ? AND status = ? AND attempts = ?", job.ID, models.PersonalDataExportRunning, job.Attempts)
+}
+
+func processPersonalDataExport(db *gorm.DB, job models.PersonalDataExport) {
+	now := time.Now().UTC()
+	sections, err := collectPersonalDataSections(db, job.TenantID, job.UserID)
+	var archive []byte
+	if err == nil {
+		archive, err = writePersonalDataArchive(personalDataManifest{
+			FormatVersion: personalDataExportFormat,
+			ExportID:      job.PublicID,
+			UserID:        job.UserID,
+			TenantID:      job.TenantID,
+			GeneratedAt:   now,
+		}, sections)
+	}
+	if err != nil {
+		log.Printf("personal data export %s attempt %d failed: %v", job.PublicID, job.Attempts, err)
+		retryAt := now.Add(time.Duration(job.Attempts) * time.Minute)
+		updates := map[string]any{"status": models.PersonalDataExportQueued, "lease_until": retryAt, "last_error": err.Error()}
+		if job.Attempts >= personalDataExportMaxAttempts {
+			updates["status"] = models.PersonalDataExportFailed
+		}
+		result := personalDataExportClaim(db, job).Updates(updates)
+		if result.Error != nil {
+			log.Printf("personal data export %s: failed to record failure: %v", job.PublicID, result.Error)
+			return
+		}
+		if result.RowsAffected == 0 {
+			log.Printf("personal data export %s attempt %d lost its lease; dropping its failure", job.PublicID, job.Attempts)
+			return
+		}
+		if updates["status"] == models.PersonalDataExportFailed {
+			writePrivacyAudit(db, job.TenantID, job.UserID, "privacy.export.failed", job.PublicID, "failure", map[string]any{"attempts": job.Attempts})
+		}
+		return
+	}
+
+	digest := sha256.Sum256(archive)
+	expiresAt := now.Add(personalDataExportRetention)
+	result := personalDataExportClaim(db, job).Updates(map[string]any{
+		"status":         models.PersonalDataExportReady,
+		"archive":        archive,
+		"archive_sha256": hex.EncodeToString(digest[:]),
+		"archive_bytes":  len(archive),
+		"completed_at":   now,
+		"expires_at":     expiresAt,
+		"lease_until":    nil,
+		"last_error":     "",
+	})
+	if result.Error != nil {
+		log.Printf("personal data export %s: failed to store archive: %v", job.PublicID, result.Error)
+		return
+	}
+	if result.RowsAffected == 0 {
+		log.Printf("personal data export %s attempt %d lost its lease; dropping its archive", job.PublicID, job.Attempts)
+		return
+	}
+	counts := make(map[string]any, len(sections))
+	for _, section := range sections {
+		counts[section.Name] = len(section.Rows)
+	}
+	writePrivacyAudit(db, job.TenantID, job.UserID, "privacy.export.completed", job.PublicID, "success", map[string]any{"bytes": len(archive), "records": counts})
+}
+
+// personalDataSection is one dataset of the archive, written both as
+// <name>.json (Records, full fidelity) and <name>.csv (Header and Rows).
+type personalDataSection struct {
+	Name    string
+	Records any
+	Header  []string
+	Rows    [][]string
+}
+
+type personalDataManifest struct {
+	FormatVersion int                        `json:"format_version"`
+	ExportID      uuid.UUID                  `json:"export_id"`
+	UserID        uuid.UUID                  `json:"user_id"`
+	TenantID      string                     `json:"tenant_id"`
+	GeneratedAt   time.Time                  `json:"generated_at"`
+	Files         []personalDataManifestFile `json:"files"`
+}
+
+type personalDataManifestFile struct {
+	Name    string `json:"name"`
+	Records int    `json:"records"`
+}
+
+func writePersonalDataArchive(manifest personalDataManifest, sections []personalDataSection) ([]byte, error) {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, section := range sections {
+		encoded, err := json.MarshalIndent(section.Records, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		if err := writePersonalDataFile(archive, section.Name+".json", manifest.GeneratedAt, encoded); err != nil {
+			return nil, err
+		}
+		var table bytes.Buffer
+		w := csv.NewWriter(&table)
+		if err := w.Write(section.Header); err != nil {
+			return nil, err
+		}
+		for _, row := range section.Rows {
+			safe := make([]string, len(row))
+			for i, cell := range row {
+				safe[i] = csvSafeCell(cell)
+			}
+			if err := w.Write(safe); err != nil {
+				return nil, err
+			}
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return nil, err
+		}
+		if err := writePersonalDataFile(archive, section.Name+".csv", manifest.GeneratedAt, table.Bytes()); err != nil {
+			return nil, err
+		}
+		manifest.Files = append(manifest.Files,
+			personalDataManifestFile{Name: section.Name + ".json", Records: len(section.Rows)},
+			personalDataManifestFile{Name: section.Name + ".csv", Records: len(section.Rows)})
+	}
+	encoded, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := writePersonalDataFile(archive, "manifest.json", manifest.GeneratedAt, encoded); err != nil {
+		return nil, err
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writePersonalDataFile(archive *zip.Writer, name string, modified time.Time, body []byte) error {
+	w, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modified})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(body)
+	return err
+}
+
+// csvSafeCell keeps user-written text (comments, report details) from being
+// evaluated as a formula when the CSV is opened in a spreadsheet.
+func csvSafeCell(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
+
+func exportTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func exportUUID(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
+
+func exportString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// collectPersonalDataSections gathers everything CMS keeps about a consumer
+// identity: the same rows InternalDeleteUserProductData erases, plus the
+// content the user submitted.
+func collectPersonalDataSections(db *gorm.DB, tenantID string, userID uuid.UUID) ([]personalDataSection, error) {
+	var sections []personalDataSection
+
+	var interactions []models.UserInteraction
+	if err := db.Where("user_id = ?", userID).Order("created_at ASC, id ASC").Find(&interactions).Error; err != nil {
+		return nil, err
+	}
+	interactionRecords := make([]map[string]any, 0, len(interactions))
+	interactionRows := make([][]string, 0, len(interactions))
+	for _, in := range interactions {
+		var text string
+		if in.Type == models.InteractionTypeComment {
+			var meta commentMetadata
+			_ = json.Unmarshal(in.Metadata, &meta)
+			text = meta.Text
+		}
+		interactionRecords = append(interactionRecords, map[string]any{
+			"id":                in.PublicID,
+			"type":              in.Type,
+			"content_item_id":   in.ContentItemID,
+			"metadata":          json.RawMessage(nonEmptyJSON(in.Metadata)),
+			"parent_id":         in.CommentParentID,
+			"moderation_status": in.CommentModerationStatus,
+			"edited_at":         in.CommentEditedAt,
+			"deleted_at":        in.CommentDeletedAt,
+			"created_at":        in.CreatedAt.UTC(),
+		})
+		interactionRows = append(interactionRows, []string{
+			in.PublicID.String(), string(in.Type), in.ContentItemID.String(), in.CreatedAt.UTC().Format(time.RFC3339),
+			text, exportUUID(in.CommentParentID), exportString(in.CommentModerationStatus),
+			exportTime(in.CommentEditedAt), exportTime(in.CommentDeletedAt), string(nonEmptyJSON(in.Metadata)),
+		})
+	}
+	sections = append(sections, personalDataSection{
+		Name:    "interactions",
+		Records: interactionRecords,
+		Header:  []string{"id", "type", "content_item_id", "created_at", "comment_text", "parent_id", "moderation_status", "edited_at", "deleted_at", "metadata"},
+		Rows:    interactionRows,
+	})
+
+	var revisions []models.CommentRevision
+	if err := db.Where("edited_by = ?", userID).Order("created_at ASC, id ASC").Find(&revisions).Error; err != nil {
+		return nil, err
+	}
+	revisionRecords := make([]map[string]any, 0, len(revisions))
+	revisionRows := make([][]string, 0, len(revisions))
+	for _, revision := range revisions {
+		revisionRecords = append(revisionRecords, map[string]any{"comment_id": revision.CommentID, "text": revision.Text, "replaced_at": revision.CreatedAt.UTC()})
+		revisionRows = append(revisionRows, []string{revision.CommentID.String(), revision.Text, revision.CreatedAt.UTC().Format(time.RFC3339)})
+	}
+	sections = append(sections, personalDataSection{Name: "comment_revisions", Records: revisionRecords, Header: []string{"comment_id", "text", "replaced_at"}, Rows: revisionRows})
+
+	var reactions []models.CommentReaction
+	if err := db.Where("user_id = ?", userID).Order("created_at ASC").Find(&reactions).Error; err != nil {
+		return nil, err
+	}
+	reactionRows := make([][]string, 0, len(reactions))
+	for _, reaction := range reactions {
+		reactionRows = append(reactionRows, []string{reaction.CommentID.String(), reaction.Kind, reaction.CreatedAt.UTC().Format(time.RFC3339)})
+	}
+	sections = append(sections, personalDataSection{Name: "comment_reactions", Records: reactions, Header: []string{"comment_id", "kind", "created_at"}, Rows: reactionRows})
+
+	var topicPrefs []models.UserTopicPref
+	if err := db.Where("tenant_id = ? AND user_id = ?", tenantID, userID).Order("created_at ASC").Find(&topicPrefs).Error; err != nil {
+		return nil, err
+	}
+	topicRecords := make([]map[string]any, 0, len(topicPrefs))
+	topicRows := make([][]string, 0, len(topicPrefs))
+	for _, pref := range topicPrefs {
+		topicRecords = append(topicRecords, map[string]any{"topic_id": pref.TopicID, "state": pref.State, "created_at": pref.CreatedAt.UTC(), "updated_at": pref.UpdatedAt.UTC()})
+		topicRows = append(topicRows, []string{pref.TopicID.String(), pref.State, pref.CreatedAt.UTC().Format(time.RFC3339), pref.UpdatedAt.UTC().Format(time.RFC3339)})
+	}
+	sections = append(sections, personalDataSection{Name: "topic_preferences", Records: topicRecords, Header: []string{"topic_id", "state", "created_at", "updated_at"}, Rows: topicRows})
+
+	var sourcePrefs []models.UserSourcePref
+	if err := db.Where("tenant_id = ? AND user_id = ?", tenantID, userID).Order("created_at ASC").Find(&sourcePrefs).Error; err != nil {
+		return nil, err
+	}
+	sourceRecords := make([]map[string]any, 0, len(sourcePrefs))
+	sourceRows := make([][]string, 0, len(sourcePrefs))
+	for _, pref := range sourcePrefs {
+		sourceRecords = append(sourceRecords, map[string]any{"source_key": pref.SourceKey, "state": pref.State, "created_at": pref.CreatedAt.UTC(), "updated_at": pref.UpdatedAt.UTC()})
+		sourceRows = append(sourceRows, []string{pref.SourceKey, pref.State, pref.CreatedAt.UTC().Format(time.RFC3339), pref.UpdatedAt.UTC().Format(time.RFC3339)})
+	}
+	sections = append(sections, personalDataSection{Name: "source_preferences", Records: sourceRecords, Header: []string{"source_key", "state", "created_at", "updated_at"}, Rows: sourceRows})
+
+	var reports []models.ModerationReport
+	if err := db.Where("tenant_id = ? AND reporter_id = ?", tenantID, userID).Order("created_at ASC").Find(&reports).Error; err != nil {
+		return nil, err
+	}
+	reportRows := make([][]string, 0, len(reports))
+	for _, report := range reports {
+		reportRows = append(reportRows, []string{report.PublicID.String(), report.TargetType, report.TargetID.String(), report.Reason, exportString(report.Detail), report.Status, report.CreatedAt.UTC().Format(time.RFC3339)})
+	}
+	sections = append(sections, personalDataSection{Name: "moderation_reports", Records: reports, Header: []string{"id", "target_type", "target_id", "reason", "detail", "status", "created_at"}, Rows: reportRows})
+
+	var blocks []models.UserBlock
+	if err := db.Where("tenant_id = ? AND user_id = ?", tenantID, userID).Order("created_at ASC").Find(&blocks).Error; err != nil {
+		return nil, err
+	}
+	blockRecords := make([]map[string]any, 0, len(blocks))
+	blockRows := make([][]string, 0, len(blocks))
+	for _, block := range blocks {
+		blockRecords = append(blockRecords, map[string]any{"blocked_user_id": block.BlockedUserID, "created_at": block.CreatedAt.UTC()})
+		blockRows = append(blockRows, []string{block.BlockedUserID.String(), block.CreatedAt.UTC().Format(time.RFC3339)})
+	}
+	sections = append(sections, personalDataSection{Name: "blocked_authors", Records: blockRecords, Header: []string{"blocked_user_id", "created_at"}, Rows: blockRows})
+
+	// Same visibility as GET /content/mine.
+	var content []models.ContentItem
+	if err := db.Where("author_id = ?", userID).
+		Where("status IN ?", []models.ContentStatus{
+			models.ContentStatusReady,
+			models.ContentStatusPending,
+			models.ContentStatusProcessing,
+			models.ContentStatusFailed,
+			models.ContentStatusArchived,
+		}).
+		Order("created_at ASC, public_id ASC").Find(&content).Error; err != nil {
+		return nil, err
+	}
+	contentRecords := make([]MyContentItem, 0, len(content))
+	contentRows := make([][]string, 0, len(content))
+	for _, item := range content {
+		mapped := mapToMyContentItem(item)
+		contentRecords = append(contentRecords, mapped)
+		contentRows = append(contentRows, []string{mapped.ID.String(), mapped.Type, mapped.Status, mapped.Title, mapped.Excerpt, mapped.MediaURL, mapped.PublishedAt})
+	}
+	sections = append(sections, personalDataSection{Name: "submitted_content", Records: contentRecords, Header: []string{"id", "type", "status", "title", "excerpt", "media_url", "published_at"}, Rows: contentRows})
+
+	var collections []models.BookmarkCollection
+	if err := db.Where("tenant_id = ? AND user_id = ?", tenantID, userID).Order("created_at ASC, id ASC").Find(&collections).Error; err != nil {
+		return nil, err
+	}
+	collectionRecords := make([]map[string]any, 0, len(collections))
+	collectionRows := make([][]string, 0)
+	for _, collection := range collections {
+		var placements []models.BookmarkCollectionItem
+		if err := db.Where("collection_id = ?", collection.PublicID).Order("position ASC, id ASC").Find(&placements).Error; err != nil {
+			return nil, err
+		}
+		collectionRecords = append(collectionRecords, map[string]any{
+			"id": collection.PublicID, "name": collection.Name, "description": collection.Description, "shared": collection.ShareToken != nil,
+			"created_at": collection.CreatedAt.UTC(), "items": placements,
+		})
+		for _, placement := range placements {
+			collectionRows = append(collectionRows, []string{collection.PublicID.String(), collection.Name, placement.ContentItemID.String(), strconv.Itoa(placement.Position), placement.Note, placement.CreatedAt.UTC().Format(time.RFC3339)})
+		}
+	}
+	sections = append(sections, personalDataSection{Name: "bookmark_collections", Records: collectionRecords, Header: []string{"collection_id", "collection_name", "content_item_id", "position", "note", "added_at"}, Rows: collectionRows})
+
+	var storyFollows []models.StoryFollow
+	if err := db.Where("tenant_id = ? AND user_id = ?", tenantID, userID).Order("created_at ASC, id ASC").Find(&storyFollows).Error; err != nil {
+		return nil, err
+	}
+	followRows := make([][]string, 0, len(storyFollows))
+	for _, follow := range storyFollows {
+		followRows = append(followRows, []string{follow.StoryID.String(), follow.CreatedAt.UTC().Format(time.RFC3339), follow.LastSeenAt.UTC().Format(time.RFC3339)})
+	}
+	sections = append(sections, personalDataSection{Name: "story_follows", Records: storyFollows, Header: []string{"story_id", "followed_at", "last_seen_at"}, Rows: followRows})
+
+	var digestSubscriptions []models.DigestSubscription
+	if err := db.Where("tenant_id = ? AND user_id = ?", tenantID, userID).Find(&digestSubscriptions).Error; err != nil {
+		return nil, err
+	}
+	var digestDeliveries []models.DigestDelivery
+	if err := db.Where("tenant_id = ? AND user_id = ?", tenantID, userID).Order("created_at ASC, id ASC").Find(&digestDeliveries).Error; err != nil {
+		return nil, err
+	}
+	digestRows := make([][]string, 0, len(digestDeliveries))
+	for _, delivery := range digestDeliveries {
+		sentAt := ""
+		if delivery.SentAt != nil {
+			sentAt = delivery.SentAt.UTC().Format(time.RFC3339)
+		}
+		digestRows = append(digestRows, []string{delivery.PublicID.String(), delivery.PeriodKey, delivery.Sender, delivery.Status, delivery.Subject, sentAt})
+	}
+	sections = append(sections, personalDataSection{
+		Name:    "digests",
+		Records: map[string]any{"subscriptions": digestSubscriptions, "deliveries": digestDeliveries},
+		Header:  []string{"delivery_id", "period", "sender", "status", "subject", "sent_at"},
+		Rows:    digestRows,
+	})
+
+	var feedSessions []models.ConsumerFeedSession
+	if err := db.Where("identity_scope = ?", "user:"+userID.String()).Order("created_at ASC").Find(&feedSessions).Error; err != nil {
+		return nil, err
+	}
+	sessionRecords := make([]map[string]any, 0, len(feedSessions))
+	sessionRows := make([][]string, 0, len(feedSessions))
+	for _, session := range feedSessions {
+		sessionRecords = append(sessionRecords, map[string]any{
+			"id": session.ID, "feed_type": session.FeedType, "generation": session.Generation,
+			"snapshot": json.RawMessage(nonEmptyJSON(session.Snapshot)), "created_at": session.CreatedAt.UTC(), "expires_at": session.ExpiresAt.UTC(),
+		})
+		sessionRows = append(sessionRows, []string{session.ID.String(), session.FeedType, strconv.FormatInt(session.Generation, 10), session.CreatedAt.UTC().Format(time.RFC3339), session.ExpiresAt.UTC().Format(time.RFC3339)})
+	}
+	sections = append(sections, personalDataSection{Name: "feed_sessions", Records: sessionRecords, Header: []string{"id", "feed_type", "generation", "created_at", "expires_at"}, Rows: sessionRows})
+
+	return sections, nil
+}
+
+func nonEmptyJSON(raw []byte) []byte {
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return []byte("null")
+	}
+	return raw
+}
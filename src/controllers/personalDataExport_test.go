@@ -0,0 +1,153 @@
+package controllers
+
+import (
+	"archive/zip"
+	"bytes"
+	"content-management-system/src/models"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestPersonalDataExportSignedLinks(t *testing.T) {
+	secret := []byte("export-secret")
+	now := time.Unix(1_700_000_000, 0).UTC()
+	retained := now.Add(5 * time.Minute)
+	export := models.PersonalDataExport{PublicID: uuid.New(), ArchiveSHA256: strings.Repeat("a", 64), ExpiresAt: &retained}
+
+	link, expiresAt := personalDataExportDownloadURL(secret, export, now)
+	if !expiresAt.Equal(retained) {
+		t.Fatalf("link must not outlive the archive: %v", expiresAt)
+	}
+	u, err := url.Parse(link)
+	if err != nil || u.Path != "/api/v1/data-exports/"+export.PublicID.String()+"/download" {
+		t.Fatalf("link = %q", link)
+	}
+	expires, signature := u.Query().Get("expires"), u.Query().Get("signature")
+	if !verifyPersonalDataExportSignature(secret, export, expires, signature, now) {
+		t.Fatal("fresh link must verify")
+	}
+	if verifyPersonalDataExportSignature(secret, export, expires, signature, retained.Add(time.Second)) {
+		t.Fatal("expired link must not verify")
+	}
+	rebuilt := export
+	rebuilt.ArchiveSHA256 = strings.Repeat("b", 64)
+	if verifyPersonalDataExportSignature(secret, rebuilt, expires, signature, now) {
+		t.Fatal("link must be bound to the archive digest")
+	}
+	other := export
+	other.PublicID = uuid.New()
+	if verifyPersonalDataExportSignature(secret, other, expires, signature, now) {
+		t.Fatal("link must be bound to the export")
+	}
+	if verifyPersonalDataExportSignature([]byte("other"), export, expires, signature, now) {
+		t.Fatal("link must be bound to the secret")
+	}
+}
+
+func TestPersonalDataExportSecretIsDedicated(t *testing.T) {
+	t.Setenv("JWT_SECRET", "jwt-secret")
+	t.Setenv("PERSONAL_DATA_EXPORT_SECRET", "")
+	if _, err := personalDataExportSecret(); !errors.Is(err, errPersonalDataExportUnsigned) {
+		t.Fatalf("JWT_SECRET must not sign export links: %v", err)
+	}
+}
+
+func TestDownloadPersonalDataExportVerifiesBeforeLoadingArchive(t *testing.T) {
+	t.Setenv("PERSONAL_DATA_EXPORT_SECRET", "export-secret")
+	db, mock := newMockGorm(t)
+	exportID := uuid.New()
+	// The archive column is left out of the lookup, and a bad signature
+	// stops before it is read.
+	mock.ExpectQuery(`^SELECT "personal_data_exports"\."id",.* FROM "personal_data_exports" WHERE`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "public_id", "archive_sha256"}).AddRow(1, exportID, strings.Repeat("a", 64)))
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Set("db", db)
+	c.Params = gin.Params{{Key: "id", Value: exportID.String()}}
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/data-exports/"+exportID.String()+"/download?expires=9999999999&signature=00", nil)
+	DownloadPersonalDataExport(c)
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPersonalDataExportClaimFencesStaleWorkers(t *testing.T) {
+	db, mock := newMockGorm(t)
+	job := models.PersonalDataExport{ID: 7, Attempts: 2}
+	// Another worker re-claimed the job (attempts 3), so the stale write
+	// matches nothing.
+	mock.ExpectBegin()
+	mock.ExpectExec(`^UPDATE "personal_data_exports" SET .* WHERE id = \$\d+ AND status = \$\d+ AND attempts = \$\d+$`).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), int64(7), models.PersonalDataExportRunning, 2).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	result := personalDataExportClaim(db, job).Updates(map[string]any{"status": models.PersonalDataExportReady})
+	if result.Error != nil || result.RowsAffected != 0 {
+		t.Fatalf("result = %v, %d rows", result.Error, result.RowsAffected)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWritePersonalDataArchive(t *testing.T) {
+	generated := time.Unix(1_700_000_000, 0).UTC()
+	archive, err := writePersonalDataArchive(personalDataManifest{FormatVersion: 1, UserID: uuid.New(), TenantID: "default", GeneratedAt: generated}, []personalDataSection{{
+		Name:    "interactions",
+		Records: []map[string]any{{"type": "comment", "text": "=HYPERLINK(\"x\")"}},
+		Header:  []string{"type", "comment_text"},
+		Rows:    [][]string{{"comment", "=HYPERLINK(\"x\")"}},
+	}})
+	if err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string][]byte{}
+	for _, f := range reader.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = body
+	}
+	if len(files) != 3 {
+		t.Fatalf("files = %v", len(files))
+	}
+
+	var manifest personalDataManifest
+	if err := json.Unmarshal(files["manifest.json"], &manifest); err != nil || len(manifest.Files) != 2 || manifest.Files[0].Records != 1 {
+		t.Fatalf("manifest = %+v, err = %v", manifest, err)
+	}
+	var records []map[string]any
+	if err := json.Unmarshal(files["interactions.json"], &records); err != nil || records[0]["text"] != "=HYPERLINK(\"x\")" {
+		t.Fatalf("json keeps the original text: %v %v", records, err)
+	}
+	rows, err := csv.NewReader(bytes.NewReader(files["interactions.csv"])).ReadAll()
+	if err != nil || len(rows) != 2 || rows[1][1] != "'=HYPERLINK(\"x\")" {
+		t.Fatalf("csv must neutralize formulas: %v %v", rows, err)
+	}
+}
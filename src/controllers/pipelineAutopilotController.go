@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -13,6 +14,7 @@ import (
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 
+	"content-management-system/src/lifecycle"
 	"content-management-system/src/models"
 )
 
@@ -910,14 +912,14 @@ func buildPipelineAutopilotStatus(db *gorm.DB, tenantID string, policy models.Pi
 	return block
 }
 
-func StartPipelineAutopilotHeartbeat(db *gorm.DB) {
-	go func() {
+func StartPipelineAutopilotHeartbeat(ctx context.Context, db *gorm.DB) {
+	lifecycle.Go(ctx, "pipeline-autopilot", func(ctx context.Context) {
 		ticker := time.NewTicker(1 * time.Minute)
 		defer ticker.Stop()
-		for range ticker.C {
+		for lifecycle.Wait(ctx, ticker.C) {
 			withPipelineAutopilotRecovery("heartbeat", func() { runPipelineAutopilotDue(db) })
 		}
-	}()
+	})
 }
 
 func withPipelineAutopilotRecovery(tenantID string, fn func()) {
@@ -44,7 +44,7 @@ func buildPodsSupplyReturnProbe(db *gorm.DB, tenantID string, limit int) ([]supp
 		ids := extractPublicIDs(candidates)
 		flags := LoadContentFlags(db, tenantID, ids)
 		velocity := LoadVelocityData(db, ids, config.VelocityWindowHours, time.Now().UTC())
-		scored := ScoreItems(candidates, config, flags, velocity, time.Now().UTC())
+		scored := ScoreItems(candidates, config, flags, velocity, nil, time.Now().UTC())
 		scored = applyIntelligenceFeedHooks(db, tenantID, scored)
 		scored = spaceScoredSiblingChapters(scored)
 		if len(scored) > limit {
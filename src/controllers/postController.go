@@ -4,12 +4,16 @@ import (
 	"content-management-system/src/models"
 	"content-management-system/src/utils"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"gorm.io/gorm"
 )
 
+// postQueryConfig drives the admin listing over the working copy.
 var postQueryConfig = utils.QueryConfig{
 	DefaultLimit: 20,
 	MaxLimit:     100,
@@ -17,19 +21,24 @@ var postQueryConfig = utils.QueryConfig{
 		{Field: "created_at", Direction: "desc"},
 	},
 	SortableFields: map[string]string{
-		"created_at": "posts.created_at",
-		"updated_at": "posts.updated_at",
-		"title":      "posts.title",
-		"author":     "posts.author",
+		"created_at":   "posts.created_at",
+		"updated_at":   "posts.updated_at",
+		"published_at": "posts.published_at",
+		"title":        "posts.title",
+		"author":       "posts.author",
 	},
 	FilterableFields: map[string]string{
-		"title":      "posts.title",
-		"author":     "posts.author",
-		"content":    "posts.content",
-		"created_at": "posts.created_at",
-		"updated_at": "posts.updated_at",
-		"id":         "posts.public_id",
+		"title":        "posts.title",
+		"author":       "posts.author",
+		"content":      "posts.content",
+		"slug":         "posts.slug",
+		"status":       "posts.status",
+		"created_at":   "posts.created_at",
+		"updated_at":   "posts.updated_at",
+		"published_at": "posts.published_at",
+		"id":           "posts.public_id",
 	},
+	TimeFields: map[string]bool{"created_at": true, "updated_at": true, "published_at": true},
 	SearchableFields: map[string]string{
 		"title":   "posts.title",
 		"content": "posts.content",
@@ -42,95 +51,129 @@ var postQueryConfig = utils.QueryConfig{
 	},
 }
 
+// publicPostQueryConfig reads the published revision (joined as r).
+var publicPostQueryConfig = utils.QueryConfig{
+	DefaultLimit: 20,
+	MaxLimit:     100,
+	DefaultSort: []utils.SortParam{
+		{Field: "published_at", Direction: "desc"},
+	},
+	SortableFields: map[string]string{
+		"created_at":   "posts.created_at",
+		"published_at": "posts.published_at",
+		"title":        "r.title",
+		"author":       "r.author",
+	},
+	FilterableFields: map[string]string{
+		"title":        "r.title",
+		"author":       "r.author",
+		"content":      "r.content",
+		"slug":         "posts.slug",
+		"created_at":   "posts.created_at",
+		"published_at": "posts.published_at",
+		"id":           "posts.public_id",
+	},
+	TimeFields: map[string]bool{"created_at": true, "published_at": true},
+	SearchableFields: map[string]string{
+		"title":   "r.title",
+		"content": "r.content",
+		"author":  "r.author",
+	},
+	DefaultSearchFields: []string{"title", "content", "author"},
+	FieldDefaultOperators: map[string]string{
+		"title":   "contains",
+		"content": "contains",
+	},
+}
+
+// publicPost is a post as readers see it: the published revision's fields,
+// with content as sanitized HTML.
+type publicPost struct {
+	PublicID    uuid.UUID      `json:"id"`
+	Slug        string         `json:"slug"`
+	Title       string         `json:"title"`
+	Content     string         `gorm:"-" json:"content"`
+	Excerpt     string         `gorm:"-" json:"excerpt"`
+	Author      string         `json:"author"`
+	Revision    int            `json:"revision"`
+	MediaIDs    pq.StringArray `gorm:"type:text[]" json:"-"`
+	Media       []models.Media `gorm:"-" json:"media,omitempty"`
+	PublishedAt *time.Time     `json:"published_at,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	// Source, RenderedHTML and RenderedExcerpt are the stored columns
+	// behind Content and Excerpt.
+	Source          string  `json:"-"`
+	RenderedHTML    *string `json:"-"`
+	RenderedExcerpt *string `json:"-"`
+}
+
+const publicPostColumns = "posts.public_id, posts.slug, r.title, r.content AS source, r.content_html AS rendered_html, r.excerpt AS rendered_excerpt, COALESCE(r.author, posts.author) AS author, r.revision, r.media_ids, posts.published_at, posts.created_at, posts.updated_at"
+
+// CreatePost handles POST /posts. As before revisions existed, the post is
+// published at revision 1 unless status=draft; publish_at schedules it. Media
+// in media_ids must belong to the admin's tenant and is attached; media
+// referenced from the content is linked too.
 func CreatePost(c *gin.Context) {
+	principal, ok := requireAdminPrincipal(c)
+	if !ok {
+		return
+	}
 	db := c.MustGet("db").(*gorm.DB)
 
-	var post models.Post
-	if err := c.ShouldBindJSON(&post); err != nil {
+	var req editorialWriteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, utils.HTTPError{
-			Data:    post,
 			Code:    http.StatusBadRequest,
 			Message: err.Error(),
 		})
 		return
 	}
-
-	// Start database transaction (gorm begin method)
-	transaction := db.Begin()
-
-	if err := transaction.Create(&post).Error; err != nil {
-		transaction.Rollback()
-		c.JSON(http.StatusInternalServerError, utils.HTTPError{
-			Data:    post,
-			Code:    http.StatusInternalServerError,
-			Message: err.Error(),
+	if strings.TrimSpace(derefStr(req.Title)) == "" || req.Content == nil || strings.TrimSpace(derefStr(req.Author)) == "" {
+		c.JSON(http.StatusBadRequest, utils.HTTPError{
+			Code:    http.StatusBadRequest,
+			Message: "title, content and author are required",
 		})
 		return
 	}
 
-	// Handle many-to-many media association if PublicIDs are provided
-	if len(post.Media) > 0 {
-		var publicIDs []uuid.UUID
-		for _, m := range post.Media {
-			if m.PublicID != uuid.Nil {
-				publicIDs = append(publicIDs, m.PublicID)
-			}
+	var media []models.Media
+	if ids, ok := req.mediaIDs(); ok {
+		found, clientErr, err := findMediaByPublicIDs(db, principal.TenantID, ids)
+		if err != nil {
+			editorialServerError(c, "Failed to resolve media", err)
+			return
 		}
-		if len(publicIDs) > 0 {
-			var mediaList []models.Media
-			if err := transaction.Where("public_id IN ?", publicIDs).Find(&mediaList).Error; err != nil {
-				transaction.Rollback()
-				c.JSON(http.StatusBadRequest, utils.HTTPError{
-					Data:    post,
-					Code:    http.StatusBadRequest,
-					Message: "Invalid media IDs",
-				})
-				return
-			}
-			if err := transaction.Model(&post).Association("Media").Replace(mediaList); err != nil {
-				transaction.Rollback()
-				c.JSON(http.StatusInternalServerError, utils.HTTPError{
-					Data:    post,
-					Code:    http.StatusInternalServerError,
-					Message: err.Error(),
-				})
-				return
-			}
+		if clientErr != "" {
+			c.JSON(http.StatusBadRequest, utils.HTTPError{
+				Code:    http.StatusBadRequest,
+				Message: clientErr,
+			})
+			return
 		}
+		media = found
 	}
 
-	// Reload with associations for response
-	if err := transaction.Preload("Media").First(&post, post.ID).Error; err != nil {
-		transaction.Rollback()
-		c.JSON(http.StatusInternalServerError, utils.HTTPError{
-			Data:    post,
-			Code:    http.StatusInternalServerError,
-			Message: err.Error(),
-		})
-		return
+	post := models.Post{
+		Title:   strings.TrimSpace(*req.Title),
+		Content: *req.Content,
+		Author:  strings.TrimSpace(*req.Author),
 	}
-
-	if err := transaction.Commit().Error; err != nil {
-		transaction.Rollback()
-		c.JSON(http.StatusInternalServerError, utils.HTTPError{
-			Data:    post,
-			Code:    http.StatusInternalServerError,
-			Message: err.Error(),
-		})
-		return
+	if len(media) > 0 {
+		post.AttachedMediaIDs = mediaPublicIDs(media)
 	}
-
-	c.JSON(http.StatusCreated, utils.ResponseMessage{
-		Data:    post,
-		Code:    http.StatusCreated,
-		Message: "Post created successfully",
-	})
+	createEditorialDocument(c, postEditorial, &post, req, principal, media)
 }
 
+// GetPosts handles GET /posts: published posts of the public tenant.
 func GetPosts(c *gin.Context) {
 	db := c.MustGet("db").(*gorm.DB)
+	tenantID, ok := publicEditorialTenant(c)
+	if !ok {
+		return
+	}
 
-	params, err := utils.ParseQueryParams(c, postQueryConfig)
+	params, err := utils.ParseQueryParams(c, publicPostQueryConfig)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, utils.HTTPError{
 			Code:    http.StatusBadRequest,
@@ -139,18 +182,16 @@ func GetPosts(c *gin.Context) {
 		return
 	}
 
-	baseQuery := db.Model(&models.Post{})
-	baseQuery = utils.ApplyQuery(baseQuery, params, postQueryConfig)
-	baseQuery = baseQuery.Preload("Media")
+	baseQuery := publishedEditorialQuery(db, postEditorial, tenantID).Select(publicPostColumns)
+	baseQuery = utils.ApplyQuery(baseQuery, params, publicPostQueryConfig)
 
-	var posts []models.Post
+	posts := []publicPost{}
 	meta, err := utils.FetchWithPagination(baseQuery, params, &posts)
+	if err == nil {
+		err = attachPublicPostMedia(db, posts)
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, utils.HTTPError{
-			Data:    posts,
-			Code:    http.StatusInternalServerError,
-			Message: err.Error(),
-		})
+		editorialServerError(c, "Failed to fetch posts", err)
 		return
 	}
 
@@ -165,120 +206,162 @@ func GetPosts(c *gin.Context) {
 	})
 }
 
+// GetPost handles GET /posts/:id where :id is the post UUID or its slug.
 func GetPost(c *gin.Context) {
-	postIDStr := c.Param("id")
-	parsedID, err := uuid.Parse(postIDStr)
-	if err != nil {
+	db := c.MustGet("db").(*gorm.DB)
+	tenantID, ok := publicEditorialTenant(c)
+	if !ok {
+		return
+	}
+
+	query, ok := whereEditorialIdentifier(publishedEditorialQuery(db, postEditorial, tenantID), postEditorial, c.Param("id"))
+	if !ok {
 		c.JSON(http.StatusBadRequest, utils.HTTPError{
 			Code:    http.StatusBadRequest,
 			Message: "Invalid post ID",
 		})
 		return
 	}
-	//      The Type Conversion Chain
-	//      c.Param("postID") → Returns string (e.g., "123")
-	//      strconv.Atoi() → Converts string to int (e.g., 123)
-	//      uint() → Converts int to uint (e.g., 123 as unsigned integer)
-	// all that maybe deleted now and not happenning anymore
-	db := c.MustGet("db").(*gorm.DB)
-	var post models.Post // no need to put in a slice [] becuase it single post
 
-	if err := db.Preload("Media").First(&post, "public_id = ?", parsedID).Error; err != nil {
-		c.JSON(http.StatusNotFound, utils.HTTPError{
-			Code:    http.StatusNotFound,
-			Message: "Post not found",
-		})
+	var post publicPost
+	if err := query.Select(publicPostColumns).Take(&post).Error; err != nil {
+		editorialLookupError(c, "Post", err)
+		return
+	}
+	posts := []publicPost{post}
+	if err := attachPublicPostMedia(db, posts); err != nil {
+		editorialServerError(c, "Failed to fetch post", err)
 		return
 	}
 
 	c.JSON(http.StatusOK, utils.ResponseMessage{
-		Data:    post,
+		Data:    posts[0],
 		Code:    http.StatusOK,
 		Message: "Post fetched successfully",
 	})
 }
 
-func UpdatePost(c *gin.Context) {
-	db := c.MustGet("db").(*gorm.DB)
-	var post models.Post
-	var postIDStr = c.Param("id")
-	parsedID, err := uuid.Parse(postIDStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, utils.HTTPError{
-			Code:    http.StatusBadRequest,
-			Message: "Invalid post ID",
-		})
-		return
+// attachPublicPostMedia fills in the rendered content and loads the media
+// named by each post's published revision, not the working copy's current
+// links.
+func attachPublicPostMedia(db *gorm.DB, posts []publicPost) error {
+	var ids pq.StringArray
+	for i, p := range posts {
+		posts[i].Content, posts[i].Excerpt = publicEditorialHTML(p.RenderedHTML, p.RenderedExcerpt, p.Source)
+		ids = append(ids, p.MediaIDs...)
 	}
-	if err := db.First(&post, "public_id = ?", parsedID).Error; err != nil {
-		c.JSON(http.StatusNotFound, utils.HTTPError{
-			Code:    http.StatusNotFound,
-			Message: "Post not found",
-		})
-		return
+	if len(ids) == 0 {
+		return nil
 	}
-
-	if err := c.ShouldBindJSON(&post); err != nil {
-		c.JSON(http.StatusBadRequest, utils.HTTPError{
-			Code:    http.StatusBadRequest,
-			Message: err.Error(),
-		})
-		return
+	var media []models.Media
+	if err := db.Where("public_id = ANY(?::uuid[])", ids).Find(&media).Error; err != nil {
+		return err
 	}
-
-	if err := db.Save(&post).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, utils.HTTPError{
-			Code:    http.StatusInternalServerError,
-			Message: err.Error(),
-		})
-		return
+	byID := make(map[string]models.Media, len(media))
+	for _, m := range media {
+		byID[m.PublicID.String()] = m
 	}
+	for i := range posts {
+		for _, id := range posts[i].MediaIDs {
+			if m, ok := byID[id]; ok {
+				posts[i].Media = append(posts[i].Media, m)
+			}
+		}
+	}
+	return nil
+}
 
-	c.JSON(http.StatusOK, utils.ResponseMessage{
-		Data:    post,
-		Code:    http.StatusOK,
-		Message: "Post updated successfully",
+// UpdatePost handles PUT /posts/:id. Title/content/author/media changes
+// append a revision; readers keep the published revision until the post is
+// published again. media_ids replaces the attached media; post_media is
+// re-synced with the content's references by updateEditorialDocument.
+func UpdatePost(c *gin.Context) {
+	updateEditorialDocument(c, postEditorial, func(tx *gorm.DB, doc models.EditorialDocument, req editorialWriteRequest) (bool, string, error) {
+		post := doc.(*models.Post)
+		changed := false
+		if req.Title != nil {
+			title := strings.TrimSpace(*req.Title)
+			if title == "" {
+				return false, "title cannot be empty", nil
+			}
+			changed = changed || title != post.Title
+			post.Title = title
+		}
+		if req.Content != nil {
+			changed = changed || *req.Content != post.Content
+			post.Content = *req.Content
+		}
+		if req.Author != nil {
+			author := strings.TrimSpace(*req.Author)
+			if author == "" {
+				return false, "author cannot be empty", nil
+			}
+			changed = changed || author != post.Author
+			post.Author = author
+		}
+		if ids, ok := req.mediaIDs(); ok {
+			media, clientErr, err := findMediaByPublicIDs(tx, post.TenantID, ids)
+			if clientErr != "" || err != nil {
+				return false, clientErr, err
+			}
+			post.AttachedMediaIDs = mediaPublicIDs(media)
+		}
+		return changed, "", nil
 	})
-
 }
-func DeletePost(c *gin.Context) {
-	db := c.MustGet("db").(*gorm.DB)
-	var post models.Post
-	var postIDStr = c.Param("id")
-	parsedID, err := uuid.Parse(postIDStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, utils.HTTPError{
-			Code:    http.StatusBadRequest,
-			Message: "Invalid post ID",
-		})
-		return
-	}
-
-	if err := db.First(&post, "public_id = ?", parsedID).Error; err != nil {
 
-		c.JSON(http.StatusNotFound, utils.HTTPError{
-			Code:    http.StatusNotFound,
-			Message: "Post not found",
-		})
-		return
+func sameMediaOrder(a, b []models.Media) bool {
+	if len(a) != len(b) {
+		return false
 	}
+	for i := range a {
+		if a[i].PublicID != b[i].PublicID {
+			return false
+		}
+	}
+	return true
+}
 
-	if err := db.Delete(&post).Error; err != nil {
-
-		c.JSON(http.StatusInternalServerError, utils.HTTPError{
-			Code:    http.StatusInternalServerError,
-			Message: err.Error(),
-		})
+// DeletePost handles DELETE /posts/:id, removing the post and its history.
+func DeletePost(c *gin.Context) {
+	if _, ok := deleteEditorialDocument(c, postEditorial); !ok {
 		return
 	}
-
 	c.JSON(http.StatusNoContent, utils.ResponseMessage{
 		Code:    http.StatusNoContent,
 		Message: "Post deleted successfully",
 	})
+}
 
+// ListAdminPosts handles GET /admin/posts: every post of the tenant.
+func ListAdminPosts(c *gin.Context) {
+	listEditorialDocuments(c, postEditorial, postQueryConfig, &[]models.Post{})
 }
 
+// GetAdminPost handles GET /admin/posts/:id (working copy and state).
+func GetAdminPost(c *gin.Context) { getEditorialDocument(c, postEditorial) }
+
+// ListPostRevisions handles GET /admin/posts/:id/revisions.
+func ListPostRevisions(c *gin.Context) { listEditorialRevisions(c, postEditorial) }
+
+// GetPostRevision handles GET /admin/posts/:id/revisions/:revision.
+func GetPostRevision(c *gin.Context) { getEditorialRevision(c, postEditorial) }
+
+// DiffPostRevisions handles GET /admin/posts/:id/diff?from=&to=.
+func DiffPostRevisions(c *gin.Context) { diffEditorialRevisions(c, postEditorial) }
+
+// RestorePostRevision handles POST /admin/posts/:id/revisions/:revision/restore.
+func RestorePostRevision(c *gin.Context) { restoreEditorialRevision(c, postEditorial) }
+
+// PublishPost handles POST /admin/posts/:id/publish.
+func PublishPost(c *gin.Context) { publishEditorialDocument(c, postEditorial) }
+
+// UnpublishPost handles POST /admin/posts/:id/unpublish.
+func UnpublishPost(c *gin.Context) { unpublishEditorialDocument(c, postEditorial) }
+
+// ArchivePost handles POST /admin/posts/:id/archive.
+func ArchivePost(c *gin.Context) { archiveEditorialDocument(c, postEditorial) }
+
 // func GetPost(c *gin.Context) {
 // if postDB == nil {
 // 	c.JSON(http.StatusInternalServerError, gin.H{"error": "database not initialized"})
@@ -1,7 +1,9 @@
 package controllers
 
 import (
+	"content-management-system/src/lifecycle"
 	"content-management-system/src/models"
+	"context"
 	"errors"
 	"time"
 
@@ -21,16 +23,16 @@ import (
 // StartPreferenceAutopilotHeartbeat launches the one-minute scheduler loop. It
 // ensures the default policy row exists, runs an immediate due-pass (matching the
 // old heartbeat's eager first tick), then ticks.
-func StartPreferenceAutopilotHeartbeat(db *gorm.DB) {
-	go func() {
+func StartPreferenceAutopilotHeartbeat(ctx context.Context, db *gorm.DB) {
+	lifecycle.Go(ctx, "preference-autopilot", func(ctx context.Context) {
 		ensureDefaultPreferencePolicy(db)
 		ticker := time.NewTicker(1 * time.Minute)
 		defer ticker.Stop()
 		runPreferenceAutopilotDue(db)
-		for range ticker.C {
+		for lifecycle.Wait(ctx, ticker.C) {
 			runPreferenceAutopilotDue(db)
 		}
-	}()
+	})
 }
 
 func ensureDefaultPreferencePolicy(db *gorm.DB) {
@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"content-management-system/src/lifecycle"
 	"content-management-system/src/models"
 	"content-management-system/src/spaceid"
 	"crypto/sha256"
@@ -262,14 +263,14 @@ func (s *proposalScorer) fillLabels(p *models.TopicProposal) (string, string, bo
 	}
 
 	if arSluggish && !enSluggish && s.translateCalls < s.policy.MaxTranslationCalls {
-		if t, err := translateViaEnrichment(en, "ar"); err == nil && hasArabicScript(t) {
+		if t, err := translateViaEnrichment(lifecycle.Root(), en, "ar"); err == nil && hasArabicScript(t) {
 			ar = t
 			arSluggish = false
 		}
 		s.translateCalls++
 	}
 	if enSluggish && !arSluggish && s.translateCalls < s.policy.MaxTranslationCalls {
-		if t, err := translateViaEnrichment(ar, "en"); err == nil && strings.TrimSpace(t) != "" {
+		if t, err := translateViaEnrichment(lifecycle.Root(), ar, "en"); err == nil && strings.TrimSpace(t) != "" {
 			en = t
 			enSluggish = false
 		}
@@ -297,7 +298,7 @@ func (s *proposalScorer) ensureEmbedding(p *models.TopicProposal, ar, en string)
 		return nil, false
 	}
 	text := strings.TrimSpace(en + " " + ar + " " + strings.ReplaceAll(p.SuggestedSlug, "-", " "))
-	emb, observedSpace, err := embedQueryViaEnrichmentWithSpace(text)
+	emb, observedSpace, err := embedQueryViaEnrichmentWithSpace(lifecycle.Root(), text)
 	s.embedCalls++
 	if err != nil || len(emb) != 1024 {
 		return nil, false
@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"content-management-system/src/lifecycle"
 	"content-management-system/src/models"
 	"content-management-system/src/spaceid"
 	"encoding/json"
@@ -378,7 +379,7 @@ func refreshNullCentroidsBounded(db *gorm.DB, tenantID string, limitN int) (int,
 	}
 	recovered, failed := 0, 0
 	for _, topic := range topics {
-		emb, observedSpace, err := embedQueryViaEnrichmentWithSpace(topic.LabelEN + " " + topic.LabelAR)
+		emb, observedSpace, err := embedQueryViaEnrichmentWithSpace(lifecycle.Root(), topic.LabelEN+" "+topic.LabelAR)
 		if err != nil || len(emb) != 1024 {
 			failed++
 			continue
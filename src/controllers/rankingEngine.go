@@ -82,14 +82,20 @@ func computeVelocityRaw(itemID uuid.UUID, velocityData VelocityData, windowHours
 }
 
 // ----------------------------------------------------------------
-// Signal 4 — Similarity (pgvector cosine — placeholder for user context)
-// Without user context this returns 0; the controller can inject a reference embedding.
+// Signal 4 — Similarity (pgvector cosine against the identity's taste vector)
+// Raw cosines come from LoadSimilarityData; ScoreItems min-max normalizes them
+// over the batch. Anonymous/cold-start identities have no data and score 0.
 // ----------------------------------------------------------------
 
-func computeSimilarity(_ models.ContentItem) float64 {
-	// Requires reference embedding from user context.
-	// Actual pgvector <=> query is done at DB level in the controller preview endpoint.
-	return 0
+func computeSimilarity(item models.ContentItem, similarityData SimilarityData, minSim, maxSim float64) float64 {
+	raw, ok := similarityData[item.PublicID]
+	if !ok {
+		return 0
+	}
+	if maxSim-minSim < 1e-9 {
+		return math.Max(0, math.Min(1, raw))
+	}
+	return (raw - minSim) / (maxSim - minSim)
 }
 
 // ----------------------------------------------------------------
@@ -229,7 +235,8 @@ func LoadContentFlags(db *gorm.DB, tenantID string, contentIDs []uuid.UUID) map[
 // ----------------------------------------------------------------
 
 // ScoreItems scores a batch of content items using the ranking engine.
-func ScoreItems(items []models.ContentItem, config models.RankingConfig, flagMap map[uuid.UUID]models.ContentFlag, velocityData VelocityData, now time.Time) []ScoredItem {
+// similarityData may be nil (no identity), which zeroes the similarity signal.
+func ScoreItems(items []models.ContentItem, config models.RankingConfig, flagMap map[uuid.UUID]models.ContentFlag, velocityData VelocityData, similarityData SimilarityData, now time.Time) []ScoredItem {
 	if len(items) == 0 {
 		return nil
 	}
@@ -238,6 +245,10 @@ func ScoreItems(items []models.ContentItem, config models.RankingConfig, flagMap
 	engagementRaws := make([]float64, len(items))
 	velocityRaws := make([]float64, len(items))
 	var maxEng, maxVel float64
+	minSim, maxSim := math.Inf(1), math.Inf(-1)
+	for _, sim := range similarityData {
+		minSim, maxSim = math.Min(minSim, sim), math.Max(maxSim, sim)
+	}
 
 	for i, item := range items {
 		engagementRaws[i] = computeEngagementRaw(item)
@@ -273,7 +284,7 @@ func ScoreItems(items []models.ContentItem, config models.RankingConfig, flagMap
 			velocity = velocityRaws[i] / maxVel
 		}
 
-		similarity := computeSimilarity(item) // 0 without user context
+		similarity := computeSimilarity(item, similarityData, minSim, maxSim)
 
 		quality := computeQuality(item)
 
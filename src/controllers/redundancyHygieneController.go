@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
@@ -10,6 +11,7 @@ import (
 	"sync"
 	"time"
 
+	"content-management-system/src/lifecycle"
 	"content-management-system/src/models"
 	"content-management-system/src/utils"
 	"github.com/gin-gonic/gin"
@@ -257,14 +259,14 @@ func runRedundancyScanScope(db *gorm.DB, trigger, actor, scope string) (models.R
 	return run, nil
 }
 
-func StartRedundancyHygieneHeartbeat(db *gorm.DB) {
-	go func() {
+func StartRedundancyHygieneHeartbeat(ctx context.Context, db *gorm.DB) {
+	lifecycle.Go(ctx, "redundancy-hygiene", func(ctx context.Context) {
 		ticker := time.NewTicker(time.Minute)
 		defer ticker.Stop()
-		for range ticker.C {
+		for lifecycle.Wait(ctx, ticker.C) {
 			runRedundancyDue(db)
 		}
-	}()
+	})
 }
 func runRedundancyDue(db *gorm.DB) {
 	var policies []models.RedundancyPolicy
@@ -1,8 +1,10 @@
 package controllers
 
 import (
+	"context"
 	"time"
 
+	"content-management-system/src/lifecycle"
 	"content-management-system/src/models"
 
 	"gorm.io/gorm"
@@ -11,15 +13,15 @@ import (
 // StartRetentionHeartbeat polls persisted policy state. Scheduled work may
 // execute only the Slice 10 trusted derived-state snapshot refresh; canonical
 // content, sources, objects, and physical storage remain human/operator-owned.
-func StartRetentionHeartbeat(db *gorm.DB) {
-	go func() {
+func StartRetentionHeartbeat(ctx context.Context, db *gorm.DB) {
+	lifecycle.Go(ctx, "retention", func(ctx context.Context) {
 		ticker := time.NewTicker(time.Minute)
 		defer ticker.Stop()
 		runRetentionDue(db)
-		for range ticker.C {
+		for lifecycle.Wait(ctx, ticker.C) {
 			runRetentionDue(db)
 		}
-	}()
+	})
 }
 
 func runRetentionDue(db *gorm.DB) {
@@ -11,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"content-management-system/src/lifecycle"
 	"content-management-system/src/models"
 	"content-management-system/src/supply"
 	"content-management-system/src/utils"
@@ -54,7 +55,9 @@ type sourceRunReceiptBody struct {
 // receipt cannot be used to synthesize a queue state or bypass a unit lease.
 func InternalCreateSourceRunReceipt(c *gin.Context) {
 	db := c.MustGet("db").(*gorm.DB)
-	if !supply.ProjectionWorkerHealthy(time.Now().UTC()) {
+	// Receipts are durable and replayed by the projection worker; an API-only
+	// process cannot see that worker's heartbeat and leaves it to its /health.
+	if lifecycle.Runs("source-run-projection") && !supply.ProjectionWorkerHealthy(time.Now().UTC()) {
 		c.JSON(http.StatusServiceUnavailable, gin.H{"message": "Source-run evidence projection is unavailable"})
 		return
 	}
@@ -2,9 +2,11 @@ package controllers
 
 import (
 	"content-management-system/src/feedstate"
+	"content-management-system/src/lifecycle"
 	"content-management-system/src/models"
 	"content-management-system/src/spaceid"
 	"content-management-system/src/utils"
+	"context"
 	"errors"
 	"log"
 	"strings"
@@ -94,7 +96,7 @@ func classifyContentTopic(db *gorm.DB, contentID uuid.UUID) {
 	// (Labeled=false, renamed later by /admin/stories/label-batch). Returning
 	// without a topic would silently exclude the item from the News feed.
 	labeled := true
-	label, err := generateTopicLabelViaEnrichment(topicSeedTexts(&item))
+	label, err := generateTopicLabelViaEnrichment(lifecycle.Root(), topicSeedTexts(&item))
 	label = strings.TrimSpace(label)
 	if err != nil || label == "" {
 		labeled = false
@@ -282,11 +284,11 @@ var classificationBackfillRunning atomic.Bool
 // write-back fires classification per item, and this sweep catches everything
 // that slipped through (LLM outages before the placeholder fallback existed,
 // crashed goroutines, bulk re-embeds, taxonomy wipes).
-func StartClassificationBackfill(db *gorm.DB) {
+func StartClassificationBackfill(ctx context.Context, db *gorm.DB) {
 	if !classificationBackfillRunning.CompareAndSwap(false, true) {
 		return
 	}
-	go func() {
+	lifecycle.Go(ctx, "classification-backfill", func(ctx context.Context) {
 		defer classificationBackfillRunning.Store(false)
 		if attached, err := feedstate.ReconcileNewsMembership(db, "default"); err != nil {
 			log.Printf("[classification-backfill] news generation reconciliation failed: %v", err)
@@ -298,7 +300,7 @@ func StartClassificationBackfill(db *gorm.DB) {
 		const batchSize = 50
 		total := 0
 		prevRemaining := int64(-1)
-		for {
+		for ctx.Err() == nil {
 			var remaining int64
 			db.Model(&models.ContentItem{}).
 				Where("type = ? AND status = ? AND embedding IS NOT NULL AND story_id IS NULL",
@@ -327,11 +329,20 @@ func StartClassificationBackfill(db *gorm.DB) {
 				Limit(batchSize).
 				Pluck("public_id", &ids)
 			for _, id := range ids {
+				if ctx.Err() != nil {
+					break
+				}
 				classifyContentTopic(db, id)
 			}
 			total += len(ids)
 			time.Sleep(500 * time.Millisecond)
 		}
+		if ctx.Err() != nil {
+			// The snapshot rebuild is left to the next start, which resumes
+			// the remaining items too.
+			log.Printf("[classification-backfill] stopped at shutdown after %d items", total)
+			return
+		}
 
 		if total > 0 {
 			log.Printf("[classification-backfill] classified %d items", total)
@@ -347,7 +358,7 @@ func StartClassificationBackfill(db *gorm.DB) {
 				}
 			}
 		}
-	}()
+	})
 }
 
 // topicSeedTexts builds the snippet list used to name a brand-new topic.
@@ -378,11 +389,11 @@ var relatedBackfillRunning atomic.Bool
 // centroid alone is ~12-17KB of wire text per slide against a WAN DB.
 // Sequential and bounded (refreshStoryRelated self-limits via
 // storyRelatedWorkers); a no-op when nothing is missing.
-func StartRelatedBackfill(db *gorm.DB) {
+func StartRelatedBackfill(ctx context.Context, db *gorm.DB) {
 	if !relatedBackfillRunning.CompareAndSwap(false, true) {
 		return
 	}
-	go func() {
+	lifecycle.Go(ctx, "related-backfill", func(ctx context.Context) {
 		defer relatedBackfillRunning.Store(false)
 		type row struct {
 			PublicID uuid.UUID
@@ -397,9 +408,14 @@ func StartRelatedBackfill(db *gorm.DB) {
 			return
 		}
 		log.Printf("[related-backfill] computing related stories for %d topics", len(rows))
-		for _, r := range rows {
+		for i, r := range rows {
+			if ctx.Err() != nil {
+				// Unfinished topics keep related_ids NULL; the next start resumes them.
+				log.Printf("[related-backfill] stopped at shutdown after %d of %d topics", i, len(rows))
+				return
+			}
 			refreshStoryRelated(db, r.TenantID, r.PublicID)
 		}
 		log.Printf("[related-backfill] done (%d topics)", len(rows))
-	}()
+	})
 }
@@ -0,0 +1,586 @@
+package controllers
+
+import (
+	"content-management-system/src/models"
+	"content-management-system/src/utils"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	maxStoryFollows = 200
+	// storyTimelineMemberLimit bounds one timeline read; the newest members
+	// win and the response reports truncation.
+	storyTimelineMemberLimit = 500
+	// storyTimelineHourlySpan is the widest story (first to last member)
+	// grouped by hour when the caller asks for automatic granularity.
+	storyTimelineHourlySpan   = 48 * time.Hour
+	storyTimelineMaxRevisions = 50
+	// storyDigestPreviewMembers is how many new members a digest entry shows.
+	storyDigestPreviewMembers = 3
+)
+
+// StoryFollowItem is one followed story with its unread count. NewCount is the
+// number of members ingested since the reader last looked.
+type StoryFollowItem struct {
+	StoryID      uuid.UUID  `json:"story_id"`
+	Label        string     `json:"label"`
+	Summary      string     `json:"summary,omitempty"`
+	Category     string     `json:"category,omitempty"`
+	ArticleCount int        `json:"article_count"`
+	LastMemberAt *time.Time `json:"last_member_at,omitempty"`
+	FollowedAt   time.Time  `json:"followed_at"`
+	LastSeenAt   time.Time  `json:"last_seen_at"`
+	NewCount     int        `json:"new_count"`
+}
+
+// StoryDigestItem is a followed story with unread members in the digest.
+type StoryDigestItem struct {
+	StoryFollowItem
+	Bullets    []string      `json:"bullets,omitempty"`
+	NewMembers []StoryMember `json:"new_members"`
+}
+
+// StoryTimelineMember is a member on the timeline. IsNew marks members
+// ingested after the caller's last look at a followed story.
+type StoryTimelineMember struct {
+	StoryMember
+	IsNew bool `json:"is_new,omitempty"`
+}
+
+// StoryTimelineSource attributes a timeline period to the outlets covering it.
+type StoryTimelineSource struct {
+	Name     string `json:"name"`
+	ImageURL string `json:"image_url,omitempty"`
+	Count    int    `json:"count"`
+}
+
+// StoryTimelineGroup is one hour or day of a story, oldest first.
+type StoryTimelineGroup struct {
+	PeriodStart time.Time             `json:"period_start"`
+	PeriodEnd   time.Time             `json:"period_end"`
+	Sources     []StoryTimelineSource `json:"sources"`
+	Members     []StoryTimelineMember `json:"members"`
+}
+
+// StorySummaryRevisionItem is one past digest of the story.
+type StorySummaryRevisionItem struct {
+	Summary     string    `json:"summary"`
+	Bullets     []string  `json:"bullets,omitempty"`
+	Category    string    `json:"category,omitempty"`
+	MemberCount int       `json:"member_count"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// StoryTimelineResponse is the chronological view of one story.
+type StoryTimelineResponse struct {
+	StoryID          uuid.UUID                  `json:"story_id"`
+	Label            string                     `json:"label"`
+	Summary          string                     `json:"summary,omitempty"`
+	Bullets          []string                   `json:"bullets,omitempty"`
+	Category         string                     `json:"category,omitempty"`
+	ArticleCount     int                        `json:"article_count"`
+	SourceCount      int                        `json:"source_count"`
+	LastMemberAt     *time.Time                 `json:"last_member_at,omitempty"`
+	Granularity      string                     `json:"granularity"`
+	Truncated        bool                       `json:"truncated"`
+	Groups           []StoryTimelineGroup       `json:"groups"`
+	SummaryRevisions []StorySummaryRevisionItem `json:"summary_revisions"`
+	Following        bool                       `json:"following"`
+	LastSeenAt       *time.Time                 `json:"last_seen_at,omitempty"`
+	NewCount         int                        `json:"new_count"`
+}
+
+// storyTimelineGranularity resolves ?granularity=auto|hour|day. Auto groups a
+// story spanning up to two days by hour and longer ones by day.
+func storyTimelineGranularity(requested string, first, last time.Time) (string, bool) {
+	switch strings.ToLower(strings.TrimSpace(requested)) {
+	case "hour":
+		return "hour", true
+	case "day":
+		return "day", true
+	case "", "auto":
+		if last.Sub(first) <= storyTimelineHourlySpan {
+			return "hour", true
+		}
+		return "day", true
+	default:
+		return "", false
+	}
+}
+
+func storyTimelinePeriod(at time.Time, granularity string) (time.Time, time.Time) {
+	at = at.UTC()
+	if granularity == "hour" {
+		start := at.Truncate(time.Hour)
+		return start, start.Add(time.Hour)
+	}
+	start := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 0, 1)
+}
+
+// groupStoryTimeline buckets members (oldest first) into periods with source
+// attribution. lastSeen, when set, flags members ingested after it.
+func groupStoryTimeline(members []models.ContentItem, granularity string, lastSeen *time.Time, sourceImages map[string]string) []StoryTimelineGroup {
+	groups := make([]StoryTimelineGroup, 0)
+	sourceIndex := map[string]int{}
+	for _, member := range members {
+		start, end := storyTimelinePeriod(itemTime(member), granularity)
+		if len(groups) == 0 || !groups[len(groups)-1].PeriodStart.Equal(start) {
+			groups = append(groups, StoryTimelineGroup{PeriodStart: start, PeriodEnd: end, Sources: []StoryTimelineSource{}})
+			sourceIndex = map[string]int{}
+		}
+		group := &groups[len(groups)-1]
+		mapped := mapStoryMember(member, sourceImages)
+		group.Members = append(group.Members, StoryTimelineMember{
+			StoryMember: mapped,
+			IsNew:       lastSeen != nil && member.CreatedAt.After(*lastSeen),
+		})
+		name := strings.TrimSpace(mapped.SourceName)
+		if name == "" {
+			continue
+		}
+		key := strings.ToLower(name)
+		if i, ok := sourceIndex[key]; ok {
+			group.Sources[i].Count++
+			continue
+		}
+		sourceIndex[key] = len(group.Sources)
+		group.Sources = append(group.Sources, StoryTimelineSource{Name: name, ImageURL: mapped.SourceImageURL, Count: 1})
+	}
+	for i := range groups {
+		sort.SliceStable(groups[i].Sources, func(a, b int) bool { return groups[i].Sources[a].Count > groups[i].Sources[b].Count })
+	}
+	return groups
+}
+
+// publicStoryMembersQuery scopes READY, feed-visible News members of the
+// given stories, matching what the News feed would show.
+func publicStoryMembersQuery(db *gorm.DB, tenantID string, storyIDs []uuid.UUID) *gorm.DB {
+	return db.Model(&models.ContentItem{}).
+		Where("tenant_id = ? AND type = ? AND status = ? AND story_id IN ?",
+			tenantID, models.ContentTypeNews, models.ContentStatusReady, storyIDs).
+		Where(newsRetentionFeedPredicate)
+}
+
+// storyNewCounts counts members ingested since each followed story was last
+// looked at. Ingest time (not publish time) is used so a late-arriving report
+// about an earlier moment still counts as new to the reader.
+func storyNewCounts(db *gorm.DB, tenantID string, uid uuid.UUID, storyIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	counts := make(map[uuid.UUID]int, len(storyIDs))
+	if len(storyIDs) == 0 {
+		return counts, nil
+	}
+	var rows []struct {
+		StoryID  uuid.UUID
+		NewCount int
+	}
+	if err := publicStoryMembersQuery(db, tenantID, storyIDs).
+		Select("story_id, COUNT(*) AS new_count").
+		Where(`created_at > (SELECT sf.last_seen_at FROM story_follows sf
+			WHERE sf.tenant_id = ? AND sf.user_id = ? AND sf.story_id = content_items.story_id)`, consumerTenant, uid).
+		Group("story_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.StoryID] = row.NewCount
+	}
+	return counts, nil
+}
+
+// loadStoryFollows returns the caller's follows of stories that still exist,
+// with their unread counts, most recently active story first.
+func loadStoryFollows(db *gorm.DB, tenantID string, uid uuid.UUID) ([]StoryFollowItem, map[uuid.UUID]models.Story, error) {
+	var follows []models.StoryFollow
+	if err := db.Where("tenant_id = ? AND user_id = ?", consumerTenant, uid).Find(&follows).Error; err != nil {
+		return nil, nil, err
+	}
+	if len(follows) == 0 {
+		return []StoryFollowItem{}, map[uuid.UUID]models.Story{}, nil
+	}
+	ids := make([]uuid.UUID, len(follows))
+	for i, follow := range follows {
+		ids[i] = follow.StoryID
+	}
+	var stories []models.Story
+	if err := db.Select(topicMetaColumns).Where("tenant_id = ? AND public_id IN ?", tenantID, ids).Find(&stories).Error; err != nil {
+		return nil, nil, err
+	}
+	byID := make(map[uuid.UUID]models.Story, len(stories))
+	for _, story := range stories {
+		byID[story.PublicID] = story
+	}
+	counts, err := storyNewCounts(db, tenantID, uid, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	items := make([]StoryFollowItem, 0, len(follows))
+	for _, follow := range follows {
+		story, ok := byID[follow.StoryID]
+		if !ok {
+			continue
+		}
+		items = append(items, StoryFollowItem{
+			StoryID:      story.PublicID,
+			Label:        story.Label,
+			Summary:      derefStr(story.Summary),
+			Category:     derefStr(story.Category),
+			ArticleCount: story.ArticleCount,
+			LastMemberAt: story.LastMemberAt,
+			FollowedAt:   follow.CreatedAt,
+			LastSeenAt:   follow.LastSeenAt,
+			NewCount:     counts[story.PublicID],
+		})
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i].LastMemberAt, items[j].LastMemberAt
+		if a == nil || b == nil {
+			return a != nil
+		}
+		return a.After(*b)
+	})
+	return items, byID, nil
+}
+
+// storyFollowParams resolves the verified caller, the public tenant and :id.
+// Stories live in the public tenant; the follow rows themselves are
+// consumer-owned and scoped with consumerTenant.
+func storyFollowParams(c *gin.Context, withStory bool) (string, uuid.UUID, uuid.UUID, bool) {
+	uid, ok := authedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, utils.HTTPError{Code: http.StatusUnauthorized, Message: "Authentication required"})
+		return "", uuid.Nil, uuid.Nil, false
+	}
+	tenantID, err := trustedPublicFeedTenant(c)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, utils.HTTPError{Code: http.StatusServiceUnavailable, Message: "Public feed tenant is unavailable"})
+		return "", uuid.Nil, uuid.Nil, false
+	}
+	if !withStory {
+		return tenantID, uid, uuid.Nil, true
+	}
+	storyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.HTTPError{Code: http.StatusBadRequest, Message: "Invalid story id"})
+		return "", uuid.Nil, uuid.Nil, false
+	}
+	return tenantID, uid, storyID, true
+}
+
+// ListStoryFollows lists the stories the caller follows with unread counts.
+// GET /api/v1/me/stories/follows
+func ListStoryFollows(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+
+	tenantID, uid, _, ok := storyFollowParams(c, false)
+	if !ok {
+		return
+	}
+	items, _, err := loadStoryFollows(db, tenantID, uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.HTTPError{Code: http.StatusInternalServerError, Message: "Failed to load followed stories"})
+		return
+	}
+	total := 0
+	for _, item := range items {
+		total += item.NewCount
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items, "total_new": total})
+}
+
+// FollowStory subscribes the caller to a story. Following starts the "last
+// looked" mark now; following again is a no-op.
+// POST /api/v1/me/stories/:id/follow
+func FollowStory(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+
+	tenantID, uid, storyID, ok := storyFollowParams(c, true)
+	if !ok {
+		return
+	}
+	var story models.Story
+	if err := db.Select("public_id").Where("tenant_id = ? AND public_id = ?", tenantID, storyID).First(&story).Error; err != nil {
+		c.JSON(http.StatusNotFound, utils.HTTPError{Code: http.StatusNotFound, Message: "Story not found"})
+		return
+	}
+
+	errFollowLimit := errors.New("follow limit reached")
+	created := false
+	var follow models.StoryFollow
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?)::bigint)", "story-follows:"+uid.String()).Error; err != nil {
+			return err
+		}
+		err := tx.Where("tenant_id = ? AND user_id = ? AND story_id = ?", consumerTenant, uid, storyID).First(&follow).Error
+		if err == nil || !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		var count int64
+		if err := tx.Model(&models.StoryFollow{}).Where("tenant_id = ? AND user_id = ?", consumerTenant, uid).Count(&count).Error; err != nil {
+			return err
+		}
+		if count >= maxStoryFollows {
+			return errFollowLimit
+		}
+		follow = models.StoryFollow{TenantID: consumerTenant, UserID: uid, StoryID: storyID, LastSeenAt: time.Now().UTC()}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&follow).Error; err != nil {
+			return err
+		}
+		created = true
+		return nil
+	})
+	if errors.Is(err, errFollowLimit) {
+		c.JSON(http.StatusUnprocessableEntity, utils.HTTPError{Code: http.StatusUnprocessableEntity, Message: "You can follow at most 200 stories"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.HTTPError{Code: http.StatusInternalServerError, Message: "Failed to follow story"})
+		return
+	}
+	if !created {
+		c.JSON(http.StatusOK, utils.ResponseMessage{Code: http.StatusOK, Message: "Already following story", Data: follow})
+		return
+	}
+	c.JSON(http.StatusCreated, utils.ResponseMessage{Code: http.StatusCreated, Message: "Story followed", Data: follow})
+}
+
+// UnfollowStory removes the caller's follow.
+// DELETE /api/v1/me/stories/:id/follow
+func UnfollowStory(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+
+	_, uid, storyID, ok := storyFollowParams(c, true)
+	if !ok {
+		return
+	}
+	if err := db.Where("tenant_id = ? AND user_id = ? AND story_id = ?", consumerTenant, uid, storyID).
+		Delete(&models.StoryFollow{}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.HTTPError{Code: http.StatusInternalServerError, Message: "Failed to unfollow story"})
+		return
+	}
+	c.JSON(http.StatusOK, utils.ResponseMessage{Code: http.StatusOK, Message: "Story unfollowed"})
+}
+
+// MarkStorySeen moves a followed story's "last looked" mark to now, clearing
+// its unread count.
+// POST /api/v1/me/stories/:id/seen
+func MarkStorySeen(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+
+	_, uid, storyID, ok := storyFollowParams(c, true)
+	if !ok {
+		return
+	}
+	res := db.Model(&models.StoryFollow{}).
+		Where("tenant_id = ? AND user_id = ? AND story_id = ?", consumerTenant, uid, storyID).
+		Update("last_seen_at", time.Now().UTC())
+	if res.Error != nil {
+		c.JSON(http.StatusInternalServerError, utils.HTTPError{Code: http.StatusInternalServerError, Message: "Failed to mark story seen"})
+		return
+	}
+	if res.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, utils.HTTPError{Code: http.StatusNotFound, Message: "You do not follow this story"})
+		return
+	}
+	c.JSON(http.StatusOK, utils.ResponseMessage{Code: http.StatusOK, Message: "Story marked seen"})
+}
+
+// GetStoryDigest is the unread digest: followed stories with members the
+// caller has not seen, newest activity first, each with its current summary
+// and a preview of the new members.
+// GET /api/v1/me/stories/digest
+func GetStoryDigest(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+
+	tenantID, uid, _, ok := storyFollowParams(c, false)
+	if !ok {
+		return
+	}
+	follows, stories, err := loadStoryFollows(db, tenantID, uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.HTTPError{Code: http.StatusInternalServerError, Message: "Failed to build story digest"})
+		return
+	}
+	unread := make([]uuid.UUID, 0, len(follows))
+	for _, follow := range follows {
+		if follow.NewCount > 0 {
+			unread = append(unread, follow.StoryID)
+		}
+	}
+
+	membersByStory := map[uuid.UUID][]models.ContentItem{}
+	var sourceImages map[string]string
+	if len(unread) > 0 {
+		ranked := publicStoryMembersQuery(db, tenantID, unread).
+			Select(storyFeedColumns+", ROW_NUMBER() OVER (PARTITION BY story_id ORDER BY COALESCE(published_at, created_at) DESC) AS digest_rank").
+			Where(`created_at > (SELECT sf.last_seen_at FROM story_follows sf
+				WHERE sf.tenant_id = ? AND sf.user_id = ? AND sf.story_id = content_items.story_id)`, consumerTenant, uid)
+		var members []models.ContentItem
+		if err := db.Table("(?) AS ranked", ranked).Where("digest_rank <= ?", storyDigestPreviewMembers).
+			Order("COALESCE(published_at, created_at) DESC").Find(&members).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, utils.HTTPError{Code: http.StatusInternalServerError, Message: "Failed to build story digest"})
+			return
+		}
+		for _, member := range members {
+			if member.StoryID != nil {
+				membersByStory[*member.StoryID] = append(membersByStory[*member.StoryID], member)
+			}
+		}
+		sourceImages = loadSourceImagesByFeedURL(db, tenantID, members)
+	}
+
+	items := make([]StoryDigestItem, 0, len(unread))
+	total := 0
+	for _, follow := range follows {
+		if follow.NewCount == 0 {
+			continue
+		}
+		total += follow.NewCount
+		preview := make([]StoryMember, 0, len(membersByStory[follow.StoryID]))
+		for _, member := range membersByStory[follow.StoryID] {
+			preview = append(preview, mapStoryMember(member, sourceImages))
+		}
+		items = append(items, StoryDigestItem{
+			StoryFollowItem: follow,
+			Bullets:         parseStoryBullets(stories[follow.StoryID].Bullets),
+			NewMembers:      preview,
+		})
+	}
+	c.Header("Cache-Control", "private, no-store")
+	c.JSON(http.StatusOK, gin.H{"items": items, "total_new": total})
+}
+
+// MarkStoryDigestSeen clears every followed story's unread count.
+// POST /api/v1/me/stories/digest/seen
+func MarkStoryDigestSeen(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+
+	_, uid, _, ok := storyFollowParams(c, false)
+	if !ok {
+		return
+	}
+	if err := db.Model(&models.StoryFollow{}).Where("tenant_id = ? AND user_id = ?", consumerTenant, uid).
+		Update("last_seen_at", time.Now().UTC()).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.HTTPError{Code: http.StatusInternalServerError, Message: "Failed to mark stories seen"})
+		return
+	}
+	c.JSON(http.StatusOK, utils.ResponseMessage{Code: http.StatusOK, Message: "All followed stories marked seen"})
+}
+
+// GetStoryTimeline returns a story's members in chronological order grouped
+// by hour or day with source attribution, plus the history of its digest.
+// A signed-in follower also gets is_new flags and the unread count; reading
+// the timeline does not move the "last looked" mark (POST .../seen does).
+// GET /api/v1/stories/:id/timeline?granularity=auto|hour|day
+func GetStoryTimeline(c *gin.Context) {
+	db := c.MustGet("db").(*gorm.DB)
+
+	tenantID, err := trustedPublicFeedTenant(c)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, utils.HTTPError{Code: http.StatusServiceUnavailable, Message: "Public feed tenant is unavailable"})
+		return
+	}
+	storyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.HTTPError{Code: http.StatusBadRequest, Message: "Invalid story id"})
+		return
+	}
+	var story models.Story
+	if err := db.Select(topicMetaColumns).Where("tenant_id = ? AND public_id = ?", tenantID, storyID).First(&story).Error; err != nil {
+		c.JSON(http.StatusNotFound, utils.HTTPError{Code: http.StatusNotFound, Message: "Story not found"})
+		return
+	}
+
+	var members []models.ContentItem
+	if err := publicStoryMembersQuery(db, tenantID, []uuid.UUID{storyID}).
+		Select(storyFeedColumns).
+		Order("COALESCE(published_at, created_at) DESC, public_id DESC").
+		Limit(storyTimelineMemberLimit + 1).
+		Find(&members).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.HTTPError{Code: http.StatusInternalServerError, Message: "Failed to load story timeline"})
+		return
+	}
+	truncated := len(members) > storyTimelineMemberLimit
+	if truncated {
+		members = members[:storyTimelineMemberLimit]
+	}
+	for i, j := 0, len(members)-1; i < j; i, j = i+1, j-1 {
+		members[i], members[j] = members[j], members[i]
+	}
+
+	var first, last time.Time
+	if len(members) > 0 {
+		first, last = itemTime(members[0]), itemTime(members[len(members)-1])
+	}
+	granularity, ok := storyTimelineGranularity(c.Query("granularity"), first, last)
+	if !ok {
+		c.JSON(http.StatusBadRequest, utils.HTTPError{Code: http.StatusBadRequest, Message: "granularity must be auto, hour or day"})
+		return
+	}
+
+	response := StoryTimelineResponse{
+		StoryID:          story.PublicID,
+		Label:            story.Label,
+		Summary:          derefStr(story.Summary),
+		Bullets:          parseStoryBullets(story.Bullets),
+		Category:         derefStr(story.Category),
+		ArticleCount:     story.ArticleCount,
+		LastMemberAt:     story.LastMemberAt,
+		Granularity:      granularity,
+		Truncated:        truncated,
+		SummaryRevisions: []StorySummaryRevisionItem{},
+	}
+
+	if uid, ok := authedUserID(c); ok {
+		var follow models.StoryFollow
+		if db.Where("tenant_id = ? AND user_id = ? AND story_id = ?", consumerTenant, uid, storyID).First(&follow).Error == nil {
+			response.Following = true
+			response.LastSeenAt = &follow.LastSeenAt
+		}
+	}
+
+	sourceImages := loadSourceImagesByFeedURL(db, tenantID, members)
+	response.Groups = groupStoryTimeline(members, granularity, response.LastSeenAt, sourceImages)
+	sources := map[string]bool{}
+	for _, group := range response.Groups {
+		for _, member := range group.Members {
+			if member.IsNew {
+				response.NewCount++
+			}
+		}
+		for _, source := range group.Sources {
+			sources[strings.ToLower(source.Name)] = true
+		}
+	}
+	response.SourceCount = len(sources)
+
+	var revisions []models.StorySummaryRevision
+	if err := db.Where("story_id = ?", storyID).Order("created_at DESC, id DESC").
+		Limit(storyTimelineMaxRevisions).Find(&revisions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, utils.HTTPError{Code: http.StatusInternalServerError, Message: "Failed to load story timeline"})
+		return
+	}
+	// The newest revisions are kept; they are returned oldest first to read
+	// alongside the groups.
+	for i := len(revisions) - 1; i >= 0; i-- {
+		revision := revisions[i]
+		response.SummaryRevisions = append(response.SummaryRevisions, StorySummaryRevisionItem{
+			Summary:     revision.Summary,
+			Bullets:     parseStoryBullets(revision.Bullets),
+			Category:    revision.Category,
+			MemberCount: revision.MemberCount,
+			CreatedAt:   revision.CreatedAt,
+		})
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"content-management-system/src/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func storyTimelineTestMember(source string, published, ingested time.Time) models.ContentItem {
+	return models.ContentItem{
+		PublicID:    uuid.New(),
+		Type:        models.ContentTypeNews,
+		SourceName:  &source,
+		PublishedAt: &published,
+		CreatedAt:   ingested,
+	}
+}
+
+func TestStoryTimelineGranularity(t *testing.T) {
+	start := time.Date(2026, 5, 1, 8, 0, 0, 0, time.UTC)
+	cases := []struct {
+		requested string
+		last      time.Time
+		want      string
+		ok        bool
+	}{
+		{"", start.Add(47 * time.Hour), "hour", true},
+		{"auto", start.Add(72 * time.Hour), "day", true},
+		{"DAY", start.Add(time.Hour), "day", true},
+		{"hour", start.Add(30 * 24 * time.Hour), "hour", true},
+		{"week", start, "", false},
+	}
+	for _, tc := range cases {
+		got, ok := storyTimelineGranularity(tc.requested, start, tc.last)
+		if got != tc.want || ok != tc.ok {
+			t.Fatalf("%q: got %q %v", tc.requested, got, ok)
+		}
+	}
+}
+
+func TestGroupStoryTimeline(t *testing.T) {
+	day := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	lastSeen := day.Add(10 * time.Hour)
+	members := []models.ContentItem{
+		storyTimelineTestMember("Reuters", day.Add(9*time.Hour), day.Add(9*time.Hour)),
+		storyTimelineTestMember("AP", day.Add(9*time.Hour+30*time.Minute), day.Add(9*time.Hour+31*time.Minute)),
+		storyTimelineTestMember("ap", day.Add(9*time.Hour+45*time.Minute), day.Add(9*time.Hour+46*time.Minute)),
+		// Published earlier in the day but ingested after the last look: still new.
+		storyTimelineTestMember("Reuters", day.Add(11*time.Hour), day.Add(12*time.Hour)),
+	}
+
+	groups := groupStoryTimeline(members, "hour", &lastSeen, nil)
+	if len(groups) != 2 {
+		t.Fatalf("groups = %+v", groups)
+	}
+	first := groups[0]
+	if !first.PeriodStart.Equal(day.Add(9*time.Hour)) || !first.PeriodEnd.Equal(day.Add(10*time.Hour)) || len(first.Members) != 3 {
+		t.Fatalf("first group = %+v", first)
+	}
+	if len(first.Sources) != 2 || first.Sources[0].Name != "AP" || first.Sources[0].Count != 2 || first.Sources[1].Count != 1 {
+		t.Fatalf("sources = %+v", first.Sources)
+	}
+	if first.Members[0].IsNew || !groups[1].Members[0].IsNew {
+		t.Fatalf("is_new flags = %v %v", first.Members[0].IsNew, groups[1].Members[0].IsNew)
+	}
+
+	daily := groupStoryTimeline(members, "day", nil, nil)
+	if len(daily) != 1 || len(daily[0].Members) != 4 || daily[0].Members[3].IsNew {
+		t.Fatalf("daily = %+v", daily)
+	}
+}
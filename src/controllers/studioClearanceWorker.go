@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
@@ -10,6 +11,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"content-management-system/src/lifecycle"
 	"content-management-system/src/models"
 	"content-management-system/src/supply"
 
@@ -42,15 +44,15 @@ type studioClearanceDecision struct {
 // StartStudioClearanceWorker consumes only exact child sets emitted by the
 // atomization verifier. It never scans the global Studio queue or derives work
 // from timestamps.
-func StartStudioClearanceWorker(db *gorm.DB) {
+func StartStudioClearanceWorker(ctx context.Context, db *gorm.DB) {
 	runStudioClearanceWorker(db)
-	go func() {
+	lifecycle.Go(ctx, "studio-clearance", func(ctx context.Context) {
 		ticker := time.NewTicker(10 * time.Second)
 		defer ticker.Stop()
-		for range ticker.C {
+		for lifecycle.Wait(ctx, ticker.C) {
 			runStudioClearanceWorker(db)
 		}
-	}()
+	})
 }
 
 func StudioClearanceWorkerHealthy(now time.Time) bool {
@@ -58,6 +60,10 @@ func StudioClearanceWorkerHealthy(now time.Time) bool {
 	return last > 0 && now.UTC().Sub(time.Unix(0, last).UTC()) <= 2*studioClearanceLease
 }
 
+func StudioClearanceWorkerLastHeartbeat() time.Time {
+	return lifecycle.HeartbeatTime(studioClearanceHeartbeat.Load())
+}
+
 func runStudioClearanceWorker(db *gorm.DB) {
 	if err := recoverExpiredStudioClearance(db); err != nil {
 		log.Printf("studio clearance recovery failed: %v", err)
@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"content-management-system/src/lifecycle"
 	"content-management-system/src/models"
 	"content-management-system/src/utils"
 	"encoding/json"
@@ -478,7 +479,7 @@ func GenerateChapters(c *gin.Context) {
 		lang = *transcript.Language
 	}
 
-	generated, err := generateChaptersViaEnrichment(windows, chaptersGenOpts{
+	generated, err := generateChaptersViaEnrichment(c.Request.Context(), windows, chaptersGenOpts{
 		Mode:              req.Mode,
 		TargetCount:       req.TargetCount,
 		TargetDurationSec: req.TargetDurationSec,
@@ -745,7 +746,7 @@ func SaveTranscript(c *gin.Context) {
 	itemCopy := *item
 	go func() {
 		if text := buildEmbeddingText(&itemCopy); text != "" {
-			_ = triggerEmbedding(text, itemCopy.PublicID.String())
+			_ = triggerEmbedding(lifecycle.Root(), text, itemCopy.PublicID.String())
 		}
 	}()
 
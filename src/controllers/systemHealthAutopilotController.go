@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"bytes"
+	"content-management-system/src/lifecycle"
 	"content-management-system/src/models"
 	"context"
 	"encoding/json"
@@ -2125,14 +2126,14 @@ func clampQueryInt(c *gin.Context, name string, fallback, minValue, maxValue int
 	return int(math.Max(float64(minValue), math.Min(float64(maxValue), float64(value))))
 }
 
-func StartSystemHealthAutopilotHeartbeat(db *gorm.DB) {
-	go func() {
+func StartSystemHealthAutopilotHeartbeat(ctx context.Context, db *gorm.DB) {
+	lifecycle.Go(ctx, "system-health-autopilot", func(ctx context.Context) {
 		ticker := time.NewTicker(1 * time.Minute)
 		defer ticker.Stop()
-		for range ticker.C {
+		for lifecycle.Wait(ctx, ticker.C) {
 			runSystemHealthAutopilotDue(db)
 		}
-	}()
+	})
 }
 
 func runSystemHealthAutopilotDue(db *gorm.DB) {
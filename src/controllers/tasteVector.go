@@ -0,0 +1,275 @@
+package controllers
+
+import (
+	"content-management-system/src/models"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pgvector/pgvector-go"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// tasteInteractionWeights is how strongly each positive interaction pulls an
+// identity's taste vector towards the item. Views, progress and skips are
+// too weak (or negative) to count as taste.
+var tasteInteractionWeights = map[models.InteractionType]float64{
+	models.InteractionTypeLike:       1.0,
+	models.InteractionTypeBookmark:   1.5,
+	models.InteractionTypeMeaningful: 0.75,
+	models.InteractionTypeComplete:   1.25,
+}
+
+const (
+	// tasteHalfLife decays old interactions so the vector follows changing
+	// interests.
+	tasteHalfLife = 30 * 24 * time.Hour
+	// tasteMaxWeight caps the inertia of a long history: a new interest
+	// still shows within a few dozen interactions.
+	tasteMaxWeight = 50.0
+	// tasteMinWeight is where a vector that has had its interactions taken
+	// back out counts as empty.
+	tasteMinWeight = 1e-6
+	// tasteWarmInteractions is how many interactions the vector needs before
+	// it stands alone; below it, the topic-affinity seed is blended in.
+	tasteWarmInteractions = 5
+	// tasteSeedTopics bounds the topic centroids averaged into the seed.
+	tasteSeedTopics = 20
+)
+
+// foldTasteVector adds one item embedding with weight w to a running mean of
+// accumulated weight `weight`, decayed by age. It returns the new mean and
+// weight. A negative w is the inverse update: it takes the item back out of
+// the mean, and a vector left with no weight comes back nil.
+func foldTasteVector(current []float32, weight float64, age time.Duration, item []float32, w float64) ([]float32, float64) {
+	if len(current) != len(item) || weight <= 0 {
+		if w <= 0 {
+			return nil, 0
+		}
+		out := make([]float32, len(item))
+		copy(out, item)
+		return out, w
+	}
+	if age > 0 {
+		weight *= math.Pow(0.5, float64(age)/float64(tasteHalfLife))
+	}
+	weight = math.Min(weight, tasteMaxWeight)
+	total := weight + w
+	if total <= tasteMinWeight {
+		return nil, 0
+	}
+	out := make([]float32, len(item))
+	for i := range item {
+		out[i] = float32((float64(current[i])*weight + float64(item[i])*w) / total)
+	}
+	return out, total
+}
+
+// recordTasteInteraction folds a new positive interaction into the
+// identity's taste vector. It runs inside the transaction that creates the
+// interaction, so an unlike can only ever see a like whose fold has already
+// committed. It is best-effort: items without a comparable embedding (or an
+// unresolved text space) are skipped, and a failed fold is rolled back to a
+// savepoint without affecting the interaction itself.
+func recordTasteInteraction(tx *gorm.DB, item models.ContentItem, interaction models.UserInteraction) {
+	w, ok := tasteInteractionWeights[interaction.Type]
+	if !ok {
+		return
+	}
+	applyTasteInteraction(tx, item, interaction, w)
+}
+
+// retractTasteInteraction is the inverse of recordTasteInteraction for a
+// removed interaction (an unlike, a dropped bookmark), run inside the
+// transaction that deletes it. The item is taken back out with the weight it
+// has decayed to since the interaction was made.
+func retractTasteInteraction(tx *gorm.DB, item models.ContentItem, interaction models.UserInteraction) {
+	w, ok := tasteInteractionWeights[interaction.Type]
+	if !ok {
+		return
+	}
+	if age := time.Since(interaction.CreatedAt); age > 0 {
+		w *= math.Pow(0.5, float64(age)/float64(tasteHalfLife))
+	}
+	applyTasteInteraction(tx, item, interaction, -w)
+}
+
+// applyTasteInteraction updates the vector under a per-identity advisory lock
+// so concurrent folds for one identity apply one at a time.
+func applyTasteInteraction(db *gorm.DB, item models.ContentItem, interaction models.UserInteraction, w float64) {
+	if item.Embedding == nil || item.EmbeddingSpaceID == nil {
+		return
+	}
+	space := currentTextSpaceIDForSimilarity()
+	if space == "" || *item.EmbeddingSpaceID != space {
+		return
+	}
+	identity := interactionIdentityScope(interaction)
+	now := time.Now().UTC()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?)::bigint)", "taste:"+item.TenantID+":"+identity).Error; err != nil {
+			return err
+		}
+		var taste models.UserTasteVector
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("tenant_id = ? AND identity = ?", item.TenantID, identity).
+			First(&taste).Error
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return err
+		}
+		var current []float32
+		if err == nil && taste.EmbeddingSpaceID == space && taste.Embedding != nil {
+			current = taste.Embedding.Slice()
+		} else if w < 0 {
+			// Nothing comparable to take the item back out of.
+			return nil
+		} else {
+			// New identity, or its vector lives in a retired space.
+			taste = models.UserTasteVector{TenantID: item.TenantID, Identity: identity}
+		}
+		mean, weight := foldTasteVector(current, taste.Weight, now.Sub(taste.UpdatedAt), item.Embedding.Slice(), w)
+		if w < 0 {
+			taste.Interactions--
+		} else {
+			taste.Interactions++
+		}
+		if mean == nil || taste.Interactions <= 0 {
+			return tx.Where("tenant_id = ? AND identity = ?", item.TenantID, identity).
+				Delete(&models.UserTasteVector{}).Error
+		}
+		vec := pgvector.NewVector(mean)
+		taste.Embedding, taste.EmbeddingSpaceID, taste.Weight = &vec, space, weight
+		taste.UpdatedAt = now
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "tenant_id"}, {Name: "identity"}},
+			DoUpdates: clause.AssignmentColumns([]string{"embedding", "embedding_space_id", "weight", "interactions", "updated_at"}),
+		}).Create(&taste).Error
+	})
+	if err != nil {
+		log.Printf("taste vector update for %s failed: %v", identity, err)
+	}
+}
+
+// tasteProfile is the reference vector the similarity signal compares items
+// against, with where it came from.
+type tasteProfile struct {
+	Identity string `json:"identity,omitempty"`
+	// Source is interactions, topic_affinity, blended, or none (cold start:
+	// similarity contributes nothing). space_unresolved means the text
+	// embedding space is unknown, so no comparison is safe.
+	Source       string     `json:"source"`
+	Interactions int        `json:"interactions"`
+	SpaceID      string     `json:"embedding_space_id,omitempty"`
+	UpdatedAt    *time.Time `json:"updated_at,omitempty"`
+	vector       []float32
+}
+
+// loadTasteProfile builds the identity's reference vector. Only vectors in
+// the current text space are used: the stored taste vector and, for signed-in
+// users, the mean of the centroids of topics they have affinity for (the
+// cold-start seed until the vector is warm).
+func loadTasteProfile(db *gorm.DB, tenantID, userIDStr, sessionID string) tasteProfile {
+	var identity string
+	var userID *uuid.UUID
+	if uid, err := uuid.Parse(userIDStr); err == nil {
+		identity, userID = "user:"+uid.String(), &uid
+	} else if strings.TrimSpace(sessionID) != "" {
+		identity = "session:" + sessionID
+	} else {
+		return tasteProfile{Source: "none"}
+	}
+	profile := tasteProfile{Identity: identity, Source: "none"}
+	space := currentTextSpaceIDForSimilarity()
+	if space == "" {
+		profile.Source = "space_unresolved"
+		return profile
+	}
+	profile.SpaceID = space
+
+	var taste models.UserTasteVector
+	var learned []float32
+	if err := db.Where("tenant_id = ? AND identity = ? AND embedding_space_id = ? AND embedding IS NOT NULL", tenantID, identity, space).
+		First(&taste).Error; err == nil {
+		learned = taste.Embedding.Slice()
+		profile.Interactions = taste.Interactions
+		updated := taste.UpdatedAt
+		profile.UpdatedAt = &updated
+	}
+	var seed []float32
+	if userID != nil && profile.Interactions < tasteWarmInteractions {
+		seed = loadTopicAffinitySeed(db, tenantID, *userID, space)
+	}
+
+	switch {
+	case learned != nil && seed != nil:
+		alpha := float64(profile.Interactions) / tasteWarmInteractions
+		profile.vector = make([]float32, len(learned))
+		for i := range learned {
+			profile.vector[i] = float32(alpha*float64(learned[i]) + (1-alpha)*float64(seed[i]))
+		}
+		profile.Source = "blended"
+	case learned != nil:
+		profile.vector, profile.Source = learned, "interactions"
+	case seed != nil:
+		profile.vector, profile.Source = seed, "topic_affinity"
+	}
+	return profile
+}
+
+// loadTopicAffinitySeed averages the centroids of the user's strongest topic
+// affinities, weighted by affinity. Centroids from another space are skipped.
+func loadTopicAffinitySeed(db *gorm.DB, tenantID string, userID uuid.UUID, space string) []float32 {
+	type row struct {
+		Score    float64
+		Centroid *pgvector.Vector
+	}
+	var rows []row
+	if err := db.Table("user_topic_affinity uta").
+		Select("uta.score, topics.centroid").
+		Joins("JOIN topics ON topics.public_id = uta.topic_id AND topics.tenant_id = uta.tenant_id").
+		Where("uta.tenant_id = ? AND uta.user_id = ? AND uta.score > 0 AND topics.active AND topics.centroid IS NOT NULL AND topics.centroid_space_id = ?", tenantID, userID, space).
+		Order("uta.score DESC").
+		Limit(tasteSeedTopics).
+		Scan(&rows).Error; err != nil {
+		return nil
+	}
+	var seed []float32
+	var total float64
+	for _, r := range rows {
+		if r.Centroid == nil {
+			continue
+		}
+		seed, total = foldTasteVector(seed, total, 0, r.Centroid.Slice(), r.Score)
+	}
+	return seed
+}
+
+// SimilarityData maps content_item public_id → cosine similarity with the
+// identity's taste vector.
+type SimilarityData map[uuid.UUID]float64
+
+// LoadSimilarityData computes the pgvector cosine between the taste vector
+// and each item's embedding. Items outside the profile's embedding space (or
+// without an embedding) are left out and score 0.
+func LoadSimilarityData(db *gorm.DB, profile tasteProfile, contentIDs []uuid.UUID) SimilarityData {
+	data := make(SimilarityData)
+	if len(profile.vector) == 0 || len(contentIDs) == 0 || profile.SpaceID == "" {
+		return data
+	}
+	type result struct {
+		PublicID   uuid.UUID
+		Similarity float64
+	}
+	var results []result
+	db.Model(&models.ContentItem{}).
+		Select("public_id, 1 - (embedding <=> ?) AS similarity", pgvector.NewVector(profile.vector)).
+		Where("public_id IN ? AND embedding IS NOT NULL AND embedding_space_id = ?", contentIDs, profile.SpaceID).
+		Scan(&results)
+	for _, r := range results {
+		data[r.PublicID] = r.Similarity
+	}
+	return data
+}
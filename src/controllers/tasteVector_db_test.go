@@ -0,0 +1,93 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"content-management-system/src/models"
+
+	"github.com/google/uuid"
+	"github.com/pgvector/pgvector-go"
+	"gorm.io/gorm"
+)
+
+func TestTasteVectorLikeThenUnlikeLeavesNoTrace(t *testing.T) {
+	db := preferenceTestDB(t)
+	if err := db.AutoMigrate(&models.UserTasteVector{}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Exec("DELETE FROM user_taste_vectors").Error })
+
+	space := "taste-test-space"
+	descriptorMu.Lock()
+	previous, hadPrevious := descriptorCache[EmbeddingSpaceText]
+	descriptorCache[EmbeddingSpaceText] = expectedSpace{Space: EmbeddingSpaceText, SpaceID: space, ObservedAt: time.Now()}
+	descriptorMu.Unlock()
+	t.Cleanup(func() {
+		descriptorMu.Lock()
+		if hadPrevious {
+			descriptorCache[EmbeddingSpaceText] = previous
+		} else {
+			delete(descriptorCache, EmbeddingSpaceText)
+		}
+		descriptorMu.Unlock()
+	})
+
+	newItem := func(axis int) models.ContentItem {
+		vec := make([]float32, 1024)
+		vec[axis] = 1
+		embedding := pgvector.NewVector(vec)
+		item := models.ContentItem{PublicID: uuid.New(), TenantID: "tenant-a", Type: models.ContentTypePodcast, Source: models.SourceTypePodcast,
+			Status: models.ContentStatusReady, Embedding: &embedding, EmbeddingSpaceID: &space}
+		if err := db.Create(&item).Error; err != nil {
+			t.Fatal(err)
+		}
+		return item
+	}
+	userID := uuid.New()
+	like := func(item models.ContentItem) models.UserInteraction {
+		interaction := models.UserInteraction{UserID: &userID, ContentItemID: item.PublicID, Type: models.InteractionTypeLike}
+		if err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(&interaction).Error; err != nil {
+				return err
+			}
+			recordTasteInteraction(tx, item, interaction)
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		return interaction
+	}
+	loadTaste := func() (models.UserTasteVector, bool) {
+		var taste models.UserTasteVector
+		err := db.Where("tenant_id = ? AND identity = ?", "tenant-a", "user:"+userID.String()).First(&taste).Error
+		if err == gorm.ErrRecordNotFound {
+			return taste, false
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		return taste, true
+	}
+
+	kept, flipped := newItem(0), newItem(1)
+	like(kept)
+	interaction := like(flipped)
+	if taste, ok := loadTaste(); !ok || taste.Interactions != 2 {
+		t.Fatalf("after two likes taste = %+v (found %v), want two interactions", taste, ok)
+	}
+	if err := deleteInteraction(db, flipped, interaction); err != nil {
+		t.Fatal(err)
+	}
+	// A repeated unlike finds no row and must not retract again.
+	if err := deleteInteraction(db, flipped, interaction); err != nil {
+		t.Fatal(err)
+	}
+	taste, ok := loadTaste()
+	if !ok || taste.Interactions != 1 {
+		t.Fatalf("after unlike taste = %+v (found %v), want one interaction", taste, ok)
+	}
+	if vec := taste.Embedding.Slice(); vec[0] < 0.999 || vec[1] > 0.001 {
+		t.Fatalf("unliked item still pulls the vector: [%v %v]", vec[0], vec[1])
+	}
+}
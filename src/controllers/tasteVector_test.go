@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"content-management-system/src/models"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestFoldTasteVector(t *testing.T) {
+	mean, weight := foldTasteVector(nil, 0, 0, []float32{1, 0}, 1)
+	if mean[0] != 1 || mean[1] != 0 || weight != 1 {
+		t.Fatalf("first fold = %v/%v", mean, weight)
+	}
+	mean, weight = foldTasteVector(mean, weight, 0, []float32{0, 1}, 1)
+	if mean[0] != 0.5 || mean[1] != 0.5 || weight != 2 {
+		t.Fatalf("second fold = %v/%v", mean, weight)
+	}
+	// One half-life later the history counts half as much as before.
+	mean, weight = foldTasteVector([]float32{1, 0}, 2, tasteHalfLife, []float32{0, 1}, 1)
+	if mean[0] != 0.5 || weight != 2 {
+		t.Fatalf("decayed fold = %v/%v", mean, weight)
+	}
+	// Inertia is capped so new interests still register.
+	_, weight = foldTasteVector([]float32{1, 0}, 1000, 0, []float32{0, 1}, 1)
+	if weight != tasteMaxWeight+1 {
+		t.Fatalf("capped weight = %v", weight)
+	}
+	// A negative weight takes an item back out again.
+	mean, weight = foldTasteVector([]float32{0.5, 0.5}, 2, 0, []float32{0, 1}, -1)
+	if mean[0] != 1 || mean[1] != 0 || weight != 1 {
+		t.Fatalf("inverse fold = %v/%v", mean, weight)
+	}
+	// Retracting the last interaction leaves nothing.
+	if mean, weight = foldTasteVector([]float32{1, 0}, 1, 0, []float32{1, 0}, -1); mean != nil || weight != 0 {
+		t.Fatalf("emptied fold = %v/%v", mean, weight)
+	}
+}
+
+func TestScoreItemsSimilaritySignal(t *testing.T) {
+	now := time.Now()
+	near, far, unembedded := uuid.New(), uuid.New(), uuid.New()
+	items := []models.ContentItem{
+		{PublicID: far, CreatedAt: now},
+		{PublicID: near, CreatedAt: now},
+		{PublicID: unembedded, CreatedAt: now},
+	}
+	config := models.RankingConfig{SimilarityWeight: 1}
+
+	scored := ScoreItems(items, config, nil, nil, SimilarityData{near: 0.8, far: 0.3}, now)
+	got := map[uuid.UUID]float64{}
+	for _, s := range scored {
+		got[s.Item.PublicID] = s.ScoreBreakdown.Similarity
+	}
+	if got[near] != 1 || got[far] != 0 || got[unembedded] != 0 {
+		t.Fatalf("similarity = %v", got)
+	}
+	if scored[0].Item.PublicID != near {
+		t.Fatalf("most similar item should rank first, got %v", scored[0].Item.PublicID)
+	}
+
+	// A single comparable item keeps its raw (clamped) cosine.
+	scored = ScoreItems(items[:1], config, nil, nil, SimilarityData{far: 0.42}, now)
+	if math.Abs(scored[0].ScoreBreakdown.Similarity-0.42) > 1e-9 {
+		t.Fatalf("single similarity = %v", scored[0].ScoreBreakdown.Similarity)
+	}
+
+	// Cold start: no taste vector, no similarity.
+	for _, s := range ScoreItems(items, config, nil, nil, nil, now) {
+		if s.ScoreBreakdown.Similarity != 0 {
+			t.Fatalf("cold-start similarity = %v", s.ScoreBreakdown.Similarity)
+		}
+	}
+}
@@ -1,101 +1,19 @@
 package controllers
 
 import (
+	"content-management-system/src/lifecycle"
 	"content-management-system/src/models"
 	"content-management-system/src/utils"
+	"context"
 	"log"
 	"net/http"
 	"strings"
-	"sync"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
-// ── Rate limiter ────────────────────────────────────────────
-
-const (
-	maxTriggersPerHour = 5
-	rateLimitWindow    = 1 * time.Hour
-	cleanupInterval    = 10 * time.Minute
-)
-
-type userRateEntry struct {
-	timestamps []time.Time
-}
-
-var (
-	rateLimitMap = make(map[string]*userRateEntry)
-	rateLimitMu  sync.Mutex
-)
-
-func init() {
-	// Periodic cleanup goroutine to prevent memory leak
-	go func() {
-		ticker := time.NewTicker(cleanupInterval)
-		defer ticker.Stop()
-		for range ticker.C {
-			pruneRateLimitMap()
-		}
-	}()
-}
-
-// pruneRateLimitMap removes entries older than the rate limit window.
-func pruneRateLimitMap() {
-	rateLimitMu.Lock()
-	defer rateLimitMu.Unlock()
-
-	cutoff := time.Now().Add(-rateLimitWindow)
-	for userID, entry := range rateLimitMap {
-		valid := make([]time.Time, 0, len(entry.timestamps))
-		for _, ts := range entry.timestamps {
-			if ts.After(cutoff) {
-				valid = append(valid, ts)
-			}
-		}
-		if len(valid) == 0 {
-			delete(rateLimitMap, userID)
-		} else {
-			entry.timestamps = valid
-		}
-	}
-}
-
-// checkRateLimit returns true if the user is within rate limits.
-func checkRateLimit(userID string) bool {
-	rateLimitMu.Lock()
-	defer rateLimitMu.Unlock()
-
-	now := time.Now()
-	cutoff := now.Add(-rateLimitWindow)
-
-	entry, exists := rateLimitMap[userID]
-	if !exists {
-		rateLimitMap[userID] = &userRateEntry{
-			timestamps: []time.Time{now},
-		}
-		return true
-	}
-
-	// Prune old entries
-	valid := make([]time.Time, 0, len(entry.timestamps))
-	for _, ts := range entry.timestamps {
-		if ts.After(cutoff) {
-			valid = append(valid, ts)
-		}
-	}
-
-	if len(valid) >= maxTriggersPerHour {
-		entry.timestamps = valid
-		return false
-	}
-
-	entry.timestamps = append(valid, now)
-	return true
-}
-
 // ── POST /api/v1/content/:id/transcribe ─────────────────────
 
 // RequestTranscription allows a logged-in user to trigger transcript generation
@@ -133,15 +51,6 @@ func RequestTranscription(c *gin.Context) {
 		return
 	}
 
-	// Rate limit check using JWT-verified user_id
-	if !checkRateLimit(userIDStr) {
-		c.JSON(http.StatusTooManyRequests, utils.HTTPError{
-			Code:    http.StatusTooManyRequests,
-			Message: "Rate limit exceeded. You can request up to 5 transcriptions per hour.",
-		})
-		return
-	}
-
 	// Look up content item
 	var item models.ContentItem
 	if err := db.Where("public_id = ?", contentID).First(&item).Error; err != nil {
@@ -184,11 +93,16 @@ func RequestTranscription(c *gin.Context) {
 	// Routed through the guard (force=false) so the budget cap still applies.
 	itemCopy := item
 	publicID := item.PublicID.String()
-	go func() {
-		if _, err := triggerTranscription(&itemCopy, db, false, ""); err != nil {
+	// Tracked and detached from shutdown cancellation like
+	// goSubmitTranscriptionJob, so a deploy waits for the submit.
+	trigger := func(ctx context.Context) {
+		if _, err := triggerTranscription(context.WithoutCancel(ctx), &itemCopy, db, false, ""); err != nil {
 			log.Printf("[CMS] transcription trigger failed for %s: %v", publicID, err)
 		}
-	}()
+	}
+	if !lifecycle.Go(lifecycle.Root(), "transcription-trigger", trigger) {
+		trigger(lifecycle.Root())
+	}
 
 	c.JSON(http.StatusAccepted, utils.ResponseMessage{
 		Code:    http.StatusAccepted,
@@ -240,8 +154,16 @@ func UserAuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		secret, err := utils.GetJWTSecret()
-		if err != nil {
+		claims, err := utils.VerifyJWT(c.Request.Context(), tokenString)
+		if err == utils.ErrJWKSUnavailable {
+			c.JSON(http.StatusServiceUnavailable, utils.HTTPError{
+				Code:    http.StatusServiceUnavailable,
+				Message: "Token verification keys unavailable",
+			})
+			c.Abort()
+			return
+		}
+		if err != nil && err != utils.ErrTokenExpired && err != utils.ErrTokenInvalid && err != utils.ErrTokenSignatureInvalid {
 			c.JSON(http.StatusInternalServerError, utils.HTTPError{
 				Code:    http.StatusInternalServerError,
 				Message: "Internal server error",
@@ -249,8 +171,6 @@ func UserAuthMiddleware() gin.HandlerFunc {
 			c.Abort()
 			return
 		}
-
-		claims, err := utils.ParseJWT(tokenString, secret)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, utils.HTTPError{
 				Code:    http.StatusUnauthorized,
@@ -305,13 +225,7 @@ func OptionalUserAuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		secret, err := utils.GetJWTSecret()
-		if err != nil {
-			c.Next()
-			return
-		}
-
-		claims, err := utils.ParseJWT(tokenString, secret)
+		claims, err := utils.VerifyJWT(c.Request.Context(), tokenString)
 		if err != nil || claims.UserID == "" {
 			c.Next()
 			return
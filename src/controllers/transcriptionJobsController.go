@@ -1,8 +1,10 @@
 package controllers
 
 import (
+	"content-management-system/src/lifecycle"
 	"content-management-system/src/models"
 	"content-management-system/src/utils"
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
@@ -544,8 +546,8 @@ func latestTranscriptionJob(db *gorm.DB, contentID uuid.UUID) *models.Transcript
 	return &job
 }
 
-func submitTranscriptionJobToMedia(db *gorm.DB, item *models.ContentItem, jobID string) error {
-	mediaJobID, err := triggerTranscriptionForJob(item, jobID)
+func submitTranscriptionJobToMedia(ctx context.Context, db *gorm.DB, item *models.ContentItem, jobID string) error {
+	mediaJobID, err := triggerTranscriptionForJob(ctx, item, jobID)
 	if err != nil {
 		return err
 	}
@@ -557,6 +559,23 @@ func submitTranscriptionJobToMedia(db *gorm.DB, item *models.ContentItem, jobID
 	return nil
 }
 
+// goSubmitTranscriptionJob submits jobID to Media on a goroutine the
+// supervisor tracks, marking the job FAILED if Media rejects it. The submit is
+// detached from shutdown cancellation and bounded by the Media client's
+// timeout: a deploy must not fail a job Media may already have accepted, so
+// shutdown waits for it instead. Once shutdown has begun the submit runs on
+// the caller's goroutine, which the HTTP drain waits for.
+func goSubmitTranscriptionJob(db *gorm.DB, item models.ContentItem, jobID string) {
+	submit := func(ctx context.Context) {
+		if err := submitTranscriptionJobToMedia(context.WithoutCancel(ctx), db, &item, jobID); err != nil {
+			_ = updateTranscriptionJobTerminal(db, jobID, models.TranscriptionJobStatusFailed, err.Error())
+		}
+	}
+	if !lifecycle.Go(lifecycle.Root(), "transcription-submit", submit) {
+		submit(lifecycle.Root())
+	}
+}
+
 func updateTranscriptionJobFromRequest(db *gorm.DB, job *models.TranscriptionJob, req internalUpdateTranscriptionJobRequest) {
 	now := time.Now()
 	if req.Status != nil {
@@ -723,15 +742,7 @@ func CreateTranscriptionJob(c *gin.Context) {
 		return
 	}
 	if triggered {
-		itemCopy := item
-		jobID := job.PublicID.String()
-		go func() {
-			if err := submitTranscriptionJobToMedia(db, &itemCopy, jobID); err != nil {
-				msg := err.Error()
-				status := models.TranscriptionJobStatusFailed
-				_ = updateTranscriptionJobTerminal(db, jobID, status, msg)
-			}
-		}()
+		goSubmitTranscriptionJob(db, item, job.PublicID.String())
 	}
 	c.JSON(http.StatusAccepted, utils.ResponseMessage{
 		Code:    http.StatusAccepted,
@@ -755,6 +766,9 @@ const (
 	transcriptionDispatcherWorkers   = 4
 	transcriptionDispatcherQueueSize = 64
 	transcriptionDispatcherScanEvery = 5 * time.Second
+	// transcriptionMediaCancelTimeout bounds each Media cancel issued when a
+	// batch is canceled.
+	transcriptionMediaCancelTimeout = 10 * time.Second
 )
 
 // transcriptionBatchDispatcher is process-owned work admission for persisted
@@ -1120,7 +1134,10 @@ func dispatchTranscriptionBatch(db *gorm.DB, batchID uuid.UUID) {
 			db.Model(&batchItem).Updates(map[string]interface{}{"status": models.TranscriptionBatchItemStatusFailed, "error": err.Error()})
 		}
 		if submitItem != nil && submitJobID != "" {
-			if err := submitTranscriptionJobToMedia(db, submitItem, submitJobID); err != nil {
+			ctx := lifecycle.Root()
+			// A submit cut off by shutdown leaves the job RUNNING; the next
+			// process resubmits it once the claim above goes stale.
+			if err := submitTranscriptionJobToMedia(ctx, db, submitItem, submitJobID); err != nil && ctx.Err() == nil {
 				_ = updateTranscriptionJobTerminal(db, submitJobID, models.TranscriptionJobStatusFailed, err.Error())
 			}
 		}
@@ -1343,7 +1360,11 @@ func CancelTranscriptionBatch(c *gin.Context) {
 			updateBatchItemForJob(db, &job)
 		}
 		if job.MediaJobID != "" {
-			_ = cancelMediaTranscriptionJob(job.MediaJobID)
+			// The job is already saved as canceled, so the Media cancel must not
+			// stop when the client disconnects mid-loop.
+			cancelCtx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), transcriptionMediaCancelTimeout)
+			_ = cancelMediaTranscriptionJob(cancelCtx, job.MediaJobID)
+			cancel()
 		}
 	}
 	recomputeTranscriptionBatch(db, batch.PublicID)
@@ -1634,16 +1655,21 @@ func RepairTranscriptionQualitySweep(c *gin.Context) {
 	// Submit accepted jobs to Media from a single background goroutine, one at a
 	// time. Spawning a goroutine per item would fire up to `limit` concurrent
 	// submits, risking connection-pool exhaustion and overwhelming Media; the
-	// batch dispatcher submits sequentially for the same reason.
+	// batch dispatcher submits sequentially for the same reason. As in
+	// goSubmitTranscriptionJob, shutdown waits for the submits instead of
+	// cancelling them into FAILED jobs.
 	if len(submits) > 0 {
-		go func() {
+		submitAll := func(ctx context.Context) {
 			for _, s := range submits {
 				itemCopy := s.item
-				if err := submitTranscriptionJobToMedia(db, &itemCopy, s.jobID); err != nil {
+				if err := submitTranscriptionJobToMedia(context.WithoutCancel(ctx), db, &itemCopy, s.jobID); err != nil {
 					_ = updateTranscriptionJobTerminal(db, s.jobID, models.TranscriptionJobStatusFailed, err.Error())
 				}
 			}
-		}()
+		}
+		if !lifecycle.Go(lifecycle.Root(), "transcription-repair-submit", submitAll) {
+			submitAll(lifecycle.Root())
+		}
 	}
 	c.JSON(http.StatusAccepted, utils.ResponseMessage{Code: http.StatusAccepted, Message: "Repair sweep processed", Data: resp})
 }
@@ -0,0 +1,45 @@
+package feedstate
+
+import (
+	"sync"
+
+	"content-management-system/src/models"
+
+	"gorm.io/gorm"
+)
+
+// ReadyHook runs inside the transaction that commits an item as READY. Hooks
+// must be idempotent: an item re-saved as READY reaches them again.
+type ReadyHook func(tx *gorm.DB, item models.ContentItem) error
+
+var (
+	readyHooksMu sync.RWMutex
+	readyHooks   []ReadyHook
+)
+
+// RegisterReadyHook is called by owners that sit above the lifecycle (the
+// WebSub outbox lives in controllers) so the durable reducer in contentstage
+// reaches them without importing a controller.
+func RegisterReadyHook(hook ReadyHook) {
+	readyHooksMu.Lock()
+	readyHooks = append(readyHooks, hook)
+	readyHooksMu.Unlock()
+}
+
+// CommitReady is the single READY boundary: every write path that saves an
+// item as READY calls it in the same transaction, after the save. It is a
+// no-op for any other status.
+func CommitReady(tx *gorm.DB, item models.ContentItem) error {
+	if item.Status != models.ContentStatusReady {
+		return nil
+	}
+	readyHooksMu.RLock()
+	hooks := append([]ReadyHook(nil), readyHooks...)
+	readyHooksMu.RUnlock()
+	for _, hook := range hooks {
+		if err := hook(tx, item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
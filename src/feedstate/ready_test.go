@@ -0,0 +1,37 @@
+package feedstate
+
+import (
+	"errors"
+	"testing"
+
+	"content-management-system/src/models"
+
+	"gorm.io/gorm"
+)
+
+func TestCommitReadyRunsHooksOnlyForReadyItems(t *testing.T) {
+	saved := readyHooks
+	t.Cleanup(func() { readyHooks = saved })
+	readyHooks = nil
+
+	var seen []models.ContentStatus
+	RegisterReadyHook(func(_ *gorm.DB, item models.ContentItem) error {
+		seen = append(seen, item.Status)
+		return nil
+	})
+	if err := CommitReady(nil, models.ContentItem{Status: models.ContentStatusProcessing}); err != nil {
+		t.Fatal(err)
+	}
+	if err := CommitReady(nil, models.ContentItem{Status: models.ContentStatusReady}); err != nil {
+		t.Fatal(err)
+	}
+	if len(seen) != 1 || seen[0] != models.ContentStatusReady {
+		t.Fatalf("hooks saw %v, want one READY commit", seen)
+	}
+
+	boom := errors.New("outbox unavailable")
+	RegisterReadyHook(func(*gorm.DB, models.ContentItem) error { return boom })
+	if err := CommitReady(nil, models.ContentItem{Status: models.ContentStatusReady}); !errors.Is(err, boom) {
+		t.Fatalf("hook error = %v, want it to abort the READY transaction", err)
+	}
+}
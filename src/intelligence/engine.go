@@ -1,11 +1,13 @@
 package intelligence
 
 import (
+	"context"
 	"encoding/json"
 	"log"
 	"sort"
 	"time"
 
+	"content-management-system/src/lifecycle"
 	"content-management-system/src/models"
 
 	"github.com/google/uuid"
@@ -108,12 +110,12 @@ func (e Engine) RefreshStale(tenantID string, budget int) int {
 // walks the tenants that have media items and refreshes each one's stale
 // scores under the batch budget. Mirrors the news-circulation automation
 // pattern (single lightweight ticker, work skipped when nothing is stale).
-func StartRefreshLoop(db *gorm.DB) {
+func StartRefreshLoop(ctx context.Context, db *gorm.DB) {
 	engine := Engine{DB: db}
-	go func() {
+	lifecycle.Go(ctx, "intelligence-refresh", func(ctx context.Context) {
 		ticker := time.NewTicker(refreshLoopInterval)
 		defer ticker.Stop()
-		for range ticker.C {
+		for lifecycle.Wait(ctx, ticker.C) {
 			var tenants []string
 			db.Model(&models.ContentItem{}).
 				Where("type IN ?", []models.ContentType{models.ContentTypeVideo, models.ContentTypePodcast}).
@@ -125,7 +127,7 @@ func StartRefreshLoop(db *gorm.DB) {
 				}
 			}
 		}
-	}()
+	})
 }
 
 // loadBatchContext bulk-loads the per-item interaction tallies and corpus
@@ -0,0 +1,151 @@
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Role is what a CMS process does: serve the HTTP API, run background
+// workers, or both. Splitting them lets API replicas scale without
+// multiplying scheduler contention on the database.
+type Role string
+
+const (
+	RoleAll    Role = "all"
+	RoleAPI    Role = "api"
+	RoleWorker Role = "worker"
+)
+
+// ParseRole reads a CMS_ROLE value; empty means all.
+func ParseRole(raw string) (Role, error) {
+	switch role := Role(strings.ToLower(strings.TrimSpace(raw))); role {
+	case "":
+		return RoleAll, nil
+	case RoleAll, RoleAPI, RoleWorker:
+		return role, nil
+	default:
+		return "", fmt.Errorf("CMS_ROLE must be api, worker or all, not %q", raw)
+	}
+}
+
+// ServesAPI reports whether the process mounts the /api, /admin and
+// /internal routes. Every role serves /live and /health.
+func (r Role) ServesAPI() bool {
+	return r == RoleAll || r == RoleAPI
+}
+
+// RunsWorkers reports whether the process runs the background workers.
+func (r Role) RunsWorkers() bool {
+	return r == RoleAll || r == RoleWorker
+}
+
+// Worker is one background worker the process may start.
+type Worker struct {
+	Name string
+	// API marks process-local evidence that request handlers read (owner
+	// readiness); it starts in every role, including api.
+	API   bool
+	Start func(ctx context.Context)
+	// Health is the /health field reporting Healthy; workers without one are
+	// not part of the health verdict.
+	Health  string
+	Healthy func(now time.Time) bool
+	// LastHeartbeat is when the worker last marked itself alive, zero before
+	// its first heartbeat; /metrics exports it next to Healthy.
+	LastHeartbeat func() time.Time
+}
+
+// SelectWorkers returns the workers role runs, narrowed by the comma-separated
+// enabled allowlist (empty = all) and disabled denylist. Unknown names are an
+// error so a typo cannot silently leave a worker off.
+func SelectWorkers(role Role, workers []Worker, enabled, disabled string) ([]Worker, error) {
+	known := make(map[string]bool, len(workers))
+	for _, w := range workers {
+		known[w.Name] = true
+	}
+	only, err := parseWorkerList("CMS_WORKERS", enabled, known)
+	if err != nil {
+		return nil, err
+	}
+	skip, err := parseWorkerList("CMS_WORKERS_DISABLED", disabled, known)
+	if err != nil {
+		return nil, err
+	}
+	selected := make([]Worker, 0, len(workers))
+	for _, w := range workers {
+		if !role.RunsWorkers() && !w.API {
+			continue
+		}
+		if (len(only) > 0 && !only[w.Name]) || skip[w.Name] {
+			continue
+		}
+		selected = append(selected, w)
+	}
+	return selected, nil
+}
+
+func parseWorkerList(variable, raw string, known map[string]bool) (map[string]bool, error) {
+	names := map[string]bool{}
+	var unknown []string
+	for _, name := range strings.Split(raw, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+		names[name] = true
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("%s names unknown workers: %s", variable, strings.Join(unknown, ", "))
+	}
+	return names, nil
+}
+
+type processState struct {
+	role    Role
+	workers []Worker
+	names   map[string]bool
+}
+
+var process atomic.Pointer[processState]
+
+// SetProcess records the role and the workers this process started.
+func SetProcess(role Role, workers []Worker) {
+	names := make(map[string]bool, len(workers))
+	for _, w := range workers {
+		names[w.Name] = true
+	}
+	process.Store(&processState{role: role, workers: workers, names: names})
+}
+
+// ProcessRole is the role recorded by SetProcess, or all before it is called.
+func ProcessRole() Role {
+	if p := process.Load(); p != nil {
+		return p.role
+	}
+	return RoleAll
+}
+
+// ProcessWorkers lists the workers this process started.
+func ProcessWorkers() []Worker {
+	if p := process.Load(); p != nil {
+		return p.workers
+	}
+	return nil
+}
+
+// Runs reports whether the named worker runs in this process. Before
+// SetProcess every worker is assumed local, as in a single-process deploy.
+// In-process heartbeats only say something about workers that run here.
+func Runs(name string) bool {
+	if p := process.Load(); p != nil {
+		return p.names[name]
+	}
+	return true
+}
@@ -0,0 +1,77 @@
+package lifecycle
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func testWorkers() []Worker {
+	start := func(context.Context) {}
+	return []Worker{
+		{Name: "digests", Start: start},
+		{Name: "supply-owner-readiness", API: true, Start: start},
+		{Name: "source-run-projection", Start: start},
+	}
+}
+
+func workerNames(workers []Worker) []string {
+	names := []string{}
+	for _, w := range workers {
+		names = append(names, w.Name)
+	}
+	return names
+}
+
+func TestParseRole(t *testing.T) {
+	for raw, want := range map[string]Role{"": RoleAll, "all": RoleAll, " API ": RoleAPI, "worker": RoleWorker} {
+		if got, err := ParseRole(raw); err != nil || got != want {
+			t.Fatalf("ParseRole(%q) = %q, %v", raw, got, err)
+		}
+	}
+	if _, err := ParseRole("cron"); err == nil {
+		t.Fatal("unknown role must be rejected")
+	}
+	if RoleAPI.RunsWorkers() || !RoleAPI.ServesAPI() || RoleWorker.ServesAPI() || !RoleAll.RunsWorkers() {
+		t.Fatal("role capabilities are wrong")
+	}
+}
+
+func TestSelectWorkers(t *testing.T) {
+	cases := []struct {
+		role              Role
+		enabled, disabled string
+		want              []string
+	}{
+		{RoleAll, "", "", []string{"digests", "supply-owner-readiness", "source-run-projection"}},
+		{RoleAPI, "", "", []string{"supply-owner-readiness"}},
+		{RoleWorker, "digests, source-run-projection", "", []string{"digests", "source-run-projection"}},
+		{RoleWorker, "", "digests", []string{"supply-owner-readiness", "source-run-projection"}},
+		{RoleAPI, "", "supply-owner-readiness", []string{}},
+	}
+	for _, tc := range cases {
+		got, err := SelectWorkers(tc.role, testWorkers(), tc.enabled, tc.disabled)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if names := workerNames(got); !reflect.DeepEqual(names, tc.want) {
+			t.Fatalf("%s %q/%q = %v, want %v", tc.role, tc.enabled, tc.disabled, names, tc.want)
+		}
+	}
+	if _, err := SelectWorkers(RoleAll, testWorkers(), "digest", ""); err == nil {
+		t.Fatal("unknown worker names must be rejected")
+	}
+}
+
+func TestRuns(t *testing.T) {
+	process.Store(nil)
+	defer process.Store(nil)
+	if !Runs("digests") || ProcessRole() != RoleAll {
+		t.Fatal("before SetProcess every worker is local")
+	}
+	selected, _ := SelectWorkers(RoleAPI, testWorkers(), "", "")
+	SetProcess(RoleAPI, selected)
+	if Runs("digests") || !Runs("supply-owner-readiness") || ProcessRole() != RoleAPI {
+		t.Fatalf("api process runs %v", workerNames(ProcessWorkers()))
+	}
+}
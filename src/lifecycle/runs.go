@@ -0,0 +1,63 @@
+package lifecycle
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+type loopKey struct{}
+
+// loop is the per-goroutine run clock Wait keeps for a worker started by Go.
+type loop struct {
+	name    string
+	started time.Time
+}
+
+// RunStat describes the completed runs of one worker loop in this process.
+type RunStat struct {
+	Name         string
+	Runs         int64
+	LastFinished time.Time
+	LastDuration time.Duration
+}
+
+var runStats = struct {
+	sync.Mutex
+	byName map[string]*RunStat
+}{byName: map[string]*RunStat{}}
+
+func recordRun(name string, started, finished time.Time) {
+	runStats.Lock()
+	defer runStats.Unlock()
+	stat, ok := runStats.byName[name]
+	if !ok {
+		stat = &RunStat{Name: name}
+		runStats.byName[name] = stat
+	}
+	stat.Runs++
+	stat.LastFinished = finished
+	stat.LastDuration = finished.Sub(started)
+}
+
+// RunStats returns the run statistics of every worker loop that has completed
+// a run, sorted by name.
+func RunStats() []RunStat {
+	runStats.Lock()
+	defer runStats.Unlock()
+	stats := make([]RunStat, 0, len(runStats.byName))
+	for _, stat := range runStats.byName {
+		stats = append(stats, *stat)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Name < stats[j].Name })
+	return stats
+}
+
+// HeartbeatTime converts a worker's stored heartbeat (Unix nanoseconds, zero
+// before the first beat) to a time, zero when it has not beaten yet.
+func HeartbeatTime(nanos int64) time.Time {
+	if nanos <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos).UTC()
+}
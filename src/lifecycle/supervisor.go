@@ -0,0 +1,176 @@
+// Package lifecycle owns the process root context. Background workers start
+// with it and stop taking new work when it is cancelled; the supervisor then
+// waits, up to a deadline, for the work they already hold to finish so leases,
+// ledger rows and advisory locks are released by the code that took them.
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const progressInterval = 5 * time.Second
+
+type supervisorKey struct{}
+
+// Supervisor tracks the goroutines started through Go with its context.
+type Supervisor struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu       sync.Mutex
+	stopping bool
+	running  map[string]int
+}
+
+var installed atomic.Pointer[Supervisor]
+
+// New returns a supervisor whose root context derives from parent.
+func New(parent context.Context) *Supervisor {
+	ctx, cancel := context.WithCancel(parent)
+	s := &Supervisor{cancel: cancel, running: map[string]int{}}
+	s.ctx = context.WithValue(ctx, supervisorKey{}, s)
+	return s
+}
+
+// Install makes s the process supervisor returned by Root.
+func Install(s *Supervisor) {
+	installed.Store(s)
+}
+
+// Root is the installed supervisor's context, for work kicked off outside a
+// worker (an admin-triggered backfill) that must still stop on shutdown. A
+// request context would cancel the work when the response is written.
+func Root() context.Context {
+	if s := installed.Load(); s != nil {
+		return s.ctx
+	}
+	return context.Background()
+}
+
+// Context is the root context workers are started with.
+func (s *Supervisor) Context() context.Context {
+	return s.ctx
+}
+
+// Go runs fn on a goroutine tracked by the supervisor carried in ctx and
+// reports whether it started. Once shutdown has begun fn is not started;
+// without a supervisor the goroutine is simply untracked.
+func Go(ctx context.Context, name string, fn func(ctx context.Context)) bool {
+	s, _ := ctx.Value(supervisorKey{}).(*Supervisor)
+	ctx = context.WithValue(ctx, loopKey{}, &loop{name: name})
+	if s == nil {
+		go fn(ctx)
+		return true
+	}
+	s.mu.Lock()
+	if s.stopping {
+		s.mu.Unlock()
+		log.Printf("shutdown: not starting %s", name)
+		return false
+	}
+	s.running[name]++
+	s.wg.Add(1)
+	s.mu.Unlock()
+	go func() {
+		defer s.finished(name)
+		fn(ctx)
+	}()
+	return true
+}
+
+func (s *Supervisor) finished(name string) {
+	s.mu.Lock()
+	if s.running[name]--; s.running[name] <= 0 {
+		delete(s.running, name)
+	}
+	stopping := s.stopping
+	s.mu.Unlock()
+	if stopping {
+		log.Printf("shutdown: %s stopped", name)
+	}
+	s.wg.Done()
+}
+
+// Wait blocks until the next tick and reports whether the worker should run
+// again. It returns false once ctx is cancelled, so a loop written as
+// `for lifecycle.Wait(ctx, ticker.C) { run() }` never starts a run after
+// shutdown begins but always completes the one in progress. The time between
+// a Wait returning true and the next call is recorded as a run (RunStats).
+func Wait(ctx context.Context, tick <-chan time.Time) bool {
+	l, _ := ctx.Value(loopKey{}).(*loop)
+	if l != nil && !l.started.IsZero() {
+		recordRun(l.name, l.started, time.Now())
+		l.started = time.Time{}
+	}
+	select {
+	case <-ctx.Done():
+		return false
+	case <-tick:
+		if ctx.Err() != nil {
+			return false
+		}
+		if l != nil {
+			l.started = time.Now()
+		}
+		return true
+	}
+}
+
+// Running lists the tracked goroutines still running, with a count when a
+// name runs more than once.
+func (s *Supervisor) Running() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, 0, len(s.running))
+	for name, n := range s.running {
+		if n > 1 {
+			name = fmt.Sprintf("%s×%d", name, n)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Shutdown cancels the root context and waits for tracked goroutines until
+// deadline is done, logging the ones still running. Goroutines left running
+// at the deadline are abandoned: their leases expire and their transactions
+// roll back when the process exits.
+func (s *Supervisor) Shutdown(deadline context.Context) error {
+	s.mu.Lock()
+	s.stopping = true
+	s.mu.Unlock()
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	if running := s.Running(); len(running) > 0 {
+		log.Printf("shutdown: waiting for %d workers: %s", len(running), strings.Join(running, ", "))
+	}
+	progress := time.NewTicker(progressInterval)
+	defer progress.Stop()
+	for {
+		select {
+		case <-done:
+			log.Printf("shutdown: all workers stopped")
+			return nil
+		case <-progress.C:
+			log.Printf("shutdown: still waiting for %s", strings.Join(s.Running(), ", "))
+		case <-deadline.Done():
+			running := s.Running()
+			log.Printf("shutdown: deadline reached, abandoning %s", strings.Join(running, ", "))
+			return fmt.Errorf("workers still running at shutdown deadline: %s", strings.Join(running, ", "))
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package lifecycle
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestShutdownWaitsForInFlightRun(t *testing.T) {
+	s := New(context.Background())
+	tick := make(chan time.Time)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var runs, finished atomic.Int32
+
+	Go(s.Context(), "worker", func(ctx context.Context) {
+		for Wait(ctx, tick) {
+			runs.Add(1)
+			close(started)
+			<-release
+			finished.Add(1)
+		}
+	})
+	tick <- time.Now()
+	<-started
+	if got := s.Running(); len(got) != 1 || got[0] != "worker" {
+		t.Fatalf("running = %v", got)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- s.Shutdown(context.Background()) }()
+	select {
+	case err := <-done:
+		t.Fatalf("shutdown returned %v before the run finished", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if runs.Load() != 1 || finished.Load() != 1 {
+		t.Fatalf("runs = %d finished = %d", runs.Load(), finished.Load())
+	}
+	stats := RunStats()
+	if len(stats) != 1 || stats[0].Name != "worker" || stats[0].Runs != 1 || stats[0].LastDuration < 50*time.Millisecond {
+		t.Fatalf("run stats = %+v", stats)
+	}
+
+	// Nothing new starts once shutdown has begun.
+	var late atomic.Bool
+	lateStarted := Go(s.Context(), "late", func(context.Context) { late.Store(true) })
+	time.Sleep(10 * time.Millisecond)
+	if lateStarted || late.Load() {
+		t.Fatal("goroutine started after shutdown")
+	}
+}
+
+func TestShutdownAbandonsAtDeadline(t *testing.T) {
+	s := New(context.Background())
+	stuck := make(chan struct{})
+	defer close(stuck)
+	Go(s.Context(), "stuck", func(context.Context) { <-stuck })
+
+	deadline, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := s.Shutdown(deadline); err == nil {
+		t.Fatal("expected a deadline error")
+	}
+	if got := s.Running(); len(got) != 1 || got[0] != "stuck" {
+		t.Fatalf("running = %v", got)
+	}
+}
+
+func TestRootFallsBackToBackground(t *testing.T) {
+	installed.Store(nil)
+	if Root() != context.Background() {
+		t.Fatal("root without a supervisor must be Background")
+	}
+	s := New(context.Background())
+	Install(s)
+	defer installed.Store(nil)
+	if Root() != s.Context() {
+		t.Fatal("root must be the installed supervisor's context")
+	}
+}
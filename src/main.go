@@ -1,27 +1,31 @@
 package main
 
 import (
-	"content-management-system/src/contentstage"
 	"content-management-system/src/controllers"
-	"content-management-system/src/intelligence"
+	"content-management-system/src/lifecycle"
+	"content-management-system/src/metrics"
 	"content-management-system/src/models" // needs it for automigrate
-	"content-management-system/src/pipeline"
 	"content-management-system/src/routes"
 	"content-management-system/src/supply"
+	"content-management-system/src/tracing"
 	"content-management-system/src/utils"
 
+	"context"
 	"fmt"
 	"log"
+	"net/http"
 	"net/url"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
 	// "fmt"
 
-	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	_ "github.com/joho/godotenv/autoload"
+	"go.opentelemetry.io/otel/attribute"
 	"gorm.io/gorm"
 )
 
@@ -34,6 +38,7 @@ func SetupRoutes(router *gin.Engine, db *gorm.DB) {
 			"endpoints": gin.H{
 				"live":          "/live",
 				"health":        "/health",
+				"metrics":       "/metrics",
 				"api":           "/api/v1",
 				"posts":         "/api/v1/posts",
 				"media":         "/api/v1/media",
@@ -47,72 +52,10 @@ func SetupRoutes(router *gin.Engine, db *gorm.DB) {
 		})
 	})
 
-	// Liveness is deliberately independent of dependency and worker readiness.
-	// Dependencies use it to detect that CMS can accept HTTP requests without
-	// creating a circular /health -> owner /ready -> CMS /health failure.
-	router.GET("/live", func(c *gin.Context) {
-		c.JSON(200, gin.H{"status": "ok"})
-	})
-
-	// Health check endpoint
-	router.GET("/health", func(c *gin.Context) {
-		now := time.Now().UTC()
-		projectionHealthy := supply.ProjectionWorkerHealthy(now)
-		recoveryHealthy := supply.RecoveryWorkerHealthy(now)
-		reconcilerHealthy := supply.ReconcilerWorkerHealthy(now)
-		supplyActionHealthy := supply.SupplyActionWorkerHealthy(now)
-		supplyEvaluationHealthy := supply.MediaSupplyEvaluatorWorkerHealthy(now)
-		sourceRunSchedulerHealthy := supply.SourceRunSchedulerHealthy(now)
-		pipelineRepairHealthy := pipeline.WorkerHealthy(now)
-		contentStageHealthy := contentstage.WorkerHealthy(now)
-		artifactCoverageHealthy := controllers.ArtifactCoverageWorkerHealthy(now)
-		atomizationWorkHealthy := controllers.AtomizationWorkVerifierHealthy(now)
-		studioClearanceHealthy := controllers.StudioClearanceWorkerHealthy(now)
-		upstreamObservationHealthy := supply.UpstreamObservationWorkerHealthy(now)
-		supplyOwners := supply.SupplyOwnerReadinessAt(now)
-		externalSupplyOwnersHealthy := true
-		for _, owner := range []string{"aggregation", "media", "enrichment"} {
-			if supplyOwners[owner].State != "ready" {
-				externalSupplyOwnersHealthy = false
-				break
-			}
-		}
-		status := 200
-		if !projectionHealthy || !recoveryHealthy || !reconcilerHealthy || !supplyActionHealthy || !supplyEvaluationHealthy || !sourceRunSchedulerHealthy || !pipelineRepairHealthy || !contentStageHealthy || !artifactCoverageHealthy || !atomizationWorkHealthy || !studioClearanceHealthy || !upstreamObservationHealthy || !externalSupplyOwnersHealthy {
-			status = 503
-		}
-		contract, contractErr := utils.ReadDatabaseContract(db, "migrations")
-		if contractErr != nil {
-			status = 503
-		}
-		fence, fenceErr := utils.ReadWriterFence(db)
-		if fenceErr != nil {
-			status = 503
-		}
-		c.JSON(status, gin.H{
-			"status":                       map[bool]string{true: "ok", false: "degraded"}[status == 200],
-			"source_run_projection_ready":  projectionHealthy,
-			"source_run_recovery_ready":    recoveryHealthy,
-			"source_run_reconciler_ready":  reconcilerHealthy,
-			"media_supply_action_ready":    supplyActionHealthy,
-			"media_supply_evaluator_ready": supplyEvaluationHealthy,
-			"source_run_scheduler_ready":   sourceRunSchedulerHealthy,
-			"pipeline_repair_ready":        pipelineRepairHealthy,
-			"content_stage_ready":          contentStageHealthy,
-			"artifact_coverage_ready":      artifactCoverageHealthy,
-			"atomization_work_ready":       atomizationWorkHealthy,
-			"studio_clearance_ready":       studioClearanceHealthy,
-			"upstream_observation_ready":   upstreamObservationHealthy,
-			"supply_owner_readiness":       supplyOwners,
-			"database_contract":            contract,
-			"database_contract_error":      contractErrorMessage(contractErr),
-			"writer_fence":                 fence,
-			"writer_fence_error":           contractErrorMessage(fenceErr),
-		})
-	})
+	SetupHealthRoutes(router, db)
 
 	router.Use(func(c *gin.Context) {
-		c.Set("db", db)
+		c.Set("db", tracing.RequestDB(c, db))
 		c.Next()
 	})
 	router.Use(databaseWriterFenceMiddleware(db))
@@ -126,6 +69,7 @@ func SetupRoutes(router *gin.Engine, db *gorm.DB) {
 	routes.SetupFeedRoutes(v1, db)
 	routes.SetupInteractionRoutes(v1, db)
 	routes.SetupContentRoutes(v1, db)
+	routes.SetupSearchRoutes(v1, db)
 	routes.SetupTranscriptRoutes(v1, db)
 	routes.SetupPreferenceRoutes(v1, db)
 	routes.SetupModerationRoutes(v1, db)
@@ -137,6 +81,81 @@ func SetupRoutes(router *gin.Engine, db *gorm.DB) {
 
 }
 
+// SetupHealthRoutes mounts the probes every process role serves.
+func SetupHealthRoutes(router *gin.Engine, db *gorm.DB) {
+	// Liveness is deliberately independent of dependency and worker readiness.
+	// Dependencies use it to detect that CMS can accept HTTP requests without
+	// creating a circular /health -> owner /ready -> CMS /health failure.
+	router.GET("/live", func(c *gin.Context) {
+		c.JSON(200, gin.H{"status": "ok"})
+	})
+
+	// Health check endpoint. It reports the heartbeats of the workers this
+	// process runs (see CMS_ROLE / CMS_WORKERS); a worker running in another
+	// process is that process's health, not this one's.
+	router.GET("/health", func(c *gin.Context) {
+		now := time.Now().UTC()
+		role := lifecycle.ProcessRole()
+		healthy := true
+		body := gin.H{"role": role}
+		running := []string{}
+		for _, worker := range lifecycle.ProcessWorkers() {
+			running = append(running, worker.Name)
+			if worker.Healthy == nil {
+				continue
+			}
+			ready := worker.Healthy(now)
+			body[worker.Health] = ready
+			healthy = healthy && ready
+		}
+		body["workers"] = running
+		if lifecycle.Runs("supply-owner-readiness") {
+			supplyOwners := supply.SupplyOwnerReadinessAt(now)
+			body["supply_owner_readiness"] = supplyOwners
+			// Owners gate handoffs the workers make; an API-only process
+			// reports them without going unready when an owner is down.
+			if role.RunsWorkers() {
+				for _, owner := range []string{"aggregation", "media", "enrichment"} {
+					if supplyOwners[owner].State != "ready" {
+						healthy = false
+						break
+					}
+				}
+			}
+		}
+		contract, contractErr := utils.ReadDatabaseContract(db, "migrations")
+		if contractErr != nil {
+			healthy = false
+		}
+		fence, fenceErr := utils.ReadWriterFence(db)
+		if fenceErr != nil {
+			healthy = false
+		}
+		status := 200
+		if !healthy {
+			status = 503
+		}
+		body["status"] = map[bool]string{true: "ok", false: "degraded"}[healthy]
+		body["database_contract"] = contract
+		body["database_contract_error"] = contractErrorMessage(contractErr)
+		body["writer_fence"] = fence
+		body["writer_fence_error"] = contractErrorMessage(fenceErr)
+		c.JSON(status, body)
+	})
+}
+
+// SetupMetricsRoute mounts the Prometheus scrape endpoint behind the internal
+// machine-credential auth. Every role serves it; the db handle backs the
+// signed-request nonce store.
+func SetupMetricsRoute(router *gin.Engine, db *gorm.DB) {
+	router.GET("/metrics",
+		func(c *gin.Context) { c.Set("db", db) },
+		utils.InternalAuthMiddleware(),
+		utils.RequireInternalRoutePolicy(utils.MetricsRoutePolicy()),
+		metrics.Handler(),
+	)
+}
+
 func contractErrorMessage(err error) string {
 	if err == nil {
 		return ""
@@ -175,9 +194,32 @@ func main() {
 	log.Printf("Environment: %s", os.Getenv("ENV"))
 	logCMSConnectionTargets()
 
-	if _, err := utils.GetJWTSecret(); err != nil {
-		log.Fatalf("Refusing to start: %v. Set JWT_SECRET to the shared value used by IAM.", err)
+	role, err := lifecycle.ParseRole(os.Getenv("CMS_ROLE"))
+	if err != nil {
+		log.Fatalf("Refusing to start: %v", err)
+	}
+	corsPolicy, err := utils.LoadCORSPolicy()
+	if err != nil {
+		log.Fatalf("Refusing to start: %v", err)
+	}
+	log.Printf("CORS policy: %s", corsPolicy.Describe())
+	securityHeaders, err := utils.LoadSecurityHeaderPolicy()
+	if err != nil {
+		log.Fatalf("Refusing to start: %v", err)
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background(), attribute.String("cms.role", string(role)))
+	if err != nil {
+		log.Fatalf("Refusing to start: %v", err)
+	}
+	log.Printf("Tracing enabled: %t", tracing.Enabled())
+
+	if warning, err := utils.ValidateJWTVerificationConfig(); err != nil {
+		log.Fatalf("Refusing to start: %v. Set JWT_SECRET to the shared value used by IAM, or JWT_VERIFICATION_MODE=jwks with JWT_JWKS_URL/JWT_JWKS_FILE.", err)
+	} else if warning != "" {
+		log.Printf("WARNING: %s", warning)
 	}
+	log.Printf("JWT verification mode: %s", utils.GetJWTVerificationMode())
 
 	db, err := utils.ConnectDB()
 	if err != nil {
@@ -188,6 +230,16 @@ func main() {
 		log.Fatalf("CMS schema is not ready: %v", err)
 	}
 	utils.InstallWriterFenceCallbacks(db)
+	if tracing.Enabled() {
+		tracing.InstallGORMCallbacks(db)
+	}
+
+	workers, err := lifecycle.SelectWorkers(role, backgroundWorkers(db), os.Getenv("CMS_WORKERS"), os.Getenv("CMS_WORKERS_DISABLED"))
+	if err != nil {
+		log.Fatalf("Refusing to start: %v", err)
+	}
+	lifecycle.SetProcess(role, workers)
+	log.Printf("Process role: %s (%d background workers)", role, len(workers))
 
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -195,6 +247,12 @@ func main() {
 	}
 
 	defer sqlDB.Close()
+	metrics.RegisterDB(sqlDB)
+	// Supply samples are tenant scoped; /metrics reports the public feed
+	// tenant's and never carries a tenant label.
+	if tenant := strings.TrimSpace(os.Getenv("DEFAULT_TENANT_ID")); tenant != "" {
+		metrics.RegisterSupply(db, tenant)
+	}
 
 	env := os.Getenv("ENV")
 	if env == "" { //if env is not set, set it to development as default
@@ -215,6 +273,7 @@ func main() {
 		if err := utils.AutoMigrate(db,
 			&models.Page{},
 			&models.Post{},
+			&models.EditorialRevision{},
 			&models.Media{},
 			// Wahb Platform models
 			&models.ContentItem{},
@@ -224,6 +283,22 @@ func main() {
 			// Intelligence / Ranking
 			&models.RankingConfig{},
 			&models.ContentFlag{},
+			&models.CommentClassifierConfig{},
+			&models.CommentClassifierStat{},
+			&models.UserTasteVector{},
+			&models.InternalRequestNonce{},
+			&models.RateLimitBucket{},
+			&models.CommentRevision{},
+			&models.CommentReaction{},
+			&models.PersonalDataExport{},
+			&models.BookmarkCollection{},
+			&models.BookmarkCollectionItem{},
+			&models.StoryFollow{},
+			&models.StorySummaryRevision{},
+			&models.DigestSubscription{},
+			&models.DigestDelivery{},
+			&models.TenantCORSOrigin{},
+			&models.FeedMembershipMark{},
 			// Media — transcription/STT config (auto-STT toggle + budget)
 			&models.TranscriptionConfig{},
 			&models.TranscriptionJob{},
@@ -365,121 +440,83 @@ func main() {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
-	controllers.ResumeTranscriptionBatches(db)
+	// Transcription batches are dispatched by the API process that accepted
+	// them, so only API processes resume them.
+	if role.ServesAPI() {
+		controllers.ResumeTranscriptionBatches(db)
+	}
 
 	router := gin.Default()
 
-	// Configure CORS to allow all origins
-	router.Use(cors.New(cors.Config{
-		AllowAllOrigins:  true,
-		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization", "Idempotency-Key"},
-		ExposeHeaders:    []string{"Content-Length"},
-		AllowCredentials: false,
-		MaxAge:           12 * time.Hour,
-	}))
-
-	SetupRoutes(router, db)
-	routes.SetupAdminAuthRoutes(router, db)
-	logCMSAuthConfig()
-
-	// Self-heal classification drift: classify any embedded-but-unclassified
-	// NEWS items (LLM outages, bulk re-embeds, taxonomy wipes) and rebuild the
-	// precompute News snapshot when done. Runs in the background.
-	controllers.StartClassificationBackfill(db)
-	// Preferences Autopilot scheduler (stage 7) — REPLACES the bare topics
-	// heartbeat. Disabled tenants get the incumbent catalog maintenance exactly;
-	// enabled tenants get the bounded, ledgered runner with a health verdict.
-	controllers.StartPreferenceAutopilotHeartbeat(db)
-	// Precompute missing topics.related_ids (stories predating the write-time
-	// related feature) so feed reads never fall back to per-slide centroid kNN.
-	controllers.StartRelatedBackfill(db)
-	// News Circulation automation heartbeat — periodically recompute source
-	// cadence recommendations (and auto-apply inside guardrails) for tenants that
-	// opted in, so the news pipeline self-tunes without manual admin triggers.
-	controllers.StartCirculationAutomation(db)
-	// Ranking/Intelligence refresh heartbeat — recomputes stale/nudged media
-	// value scores in bounded batches (stage 4; scheduled + event-nudged
-	// triggers in one pass, on-demand scoring happens inside circulation).
-	intelligence.StartRefreshLoop(db)
-	// Media Circulation Autopilot heartbeat (stage 5) — fires deterministic
-	// runs for tenants whose autopilot interval has elapsed; Observe tenants
-	// get shadow (dry-run) ledgers, Safe Auto tenants get bounded execution.
-	controllers.StartMediaCirculationAutopilotHeartbeat(db)
-	controllers.StartRedundancyHygieneHeartbeat(db)
-	controllers.StartEnrichmentAutopilotHeartbeat(db)
-	controllers.StartPipelineAutopilotHeartbeat(db)
-	// Media Studio Clearance Autopilot (stage 6) — chain-first heartbeat: fires
-	// after the lead executes atomize_now, plus a slower interval sweep-up.
-	controllers.StartMediaStudioAutopilotHeartbeat(db)
-	// System Health / Incident Autopilot — CMS-owned probes + incident ledger.
-	controllers.StartSystemHealthAutopilotHeartbeat(db)
-	// Feed Integrity base system — deterministic CMS-edge verification, not an Autopilot.
-	controllers.StartFeedIntegrityHeartbeat(db)
-	// Retention Autopilot — persisted database-pressure sampling and shadow
-	// compact-News proposals. V1 cannot mutate canonical content.
-	controllers.StartRetentionHeartbeat(db)
-	// Real User Experience — Observe scheduler: rolls up closed telemetry buckets
-	// and evaluates deterministic surface verdicts for tenants that enabled it.
-	controllers.StartExperienceHeartbeat(db)
-	// Embedding & Model Lifecycle (stage 10) — vector-space audit scheduler.
-	// Observation only; disabled by default until an admin enables it.
-	controllers.StartEmbeddingLifecycleHeartbeat(db)
-	controllers.StartAISpendGovernorHeartbeat(db)
-	// Wahb Operator — resumes only expired, persisted read investigations after
-	// a fresh IAM access snapshot; no browser session or user bearer is replayed.
-	controllers.StartOperatorInvestigationHeartbeat(db)
-	// Read-only Operator schedules are separately leased and re-authorized on
-	// every tick. They never carry an approved action or browser credential.
-	controllers.StartOperatorScheduleHeartbeat(db)
-	// Approved Operator plans enter the CMS-owned durable work ledger. The
-	// worker rechecks IAM and tenant policy before it claims an immutable plan.
-	controllers.StartOperatorPlanWorker(db)
-	// Source-run evidence reduction is CMS-owned and read-only with respect to
-	// providers and queues. It replays immutable receipts after commit; it does
-	// not enable source dispatch or any new provider effect.
-	supply.StartProjectionWorker(db)
-	// Deferred upstream identities have their own immutable disposition and
-	// expiry projection. This worker never materializes provider content.
-	supply.StartUpstreamObservationWorker(db)
-	// Expired claims converge without provider I/O: pre-effect dispatch can be
-	// redelivered while started units enter verification, never blind retry.
-	supply.StartRecoveryWorker(db)
-	// Reconciliation independently repairs interrupted verification-task
-	// creation. It cannot dispatch or repeat a source/provider effect.
-	supply.StartReconcilerWorker(db)
-	// Only CMS-owned, static Supply actions are claimable here. Owner-service
-	// handoffs remain unavailable until their typed capability protocols exist.
-	supply.StartSupplyActionWorker(db)
-	// Static owner readiness is cached outside request paths. It can deny only
-	// new external handoffs; recovery evidence, cancellation, and verification
-	// retain authority when an owner is unavailable.
-	supply.StartSupplyOwnerReadinessObserver()
-	// Pipeline repair is verification/recovery only; Aggregation remains the
-	// sole owner of its declared stage effect.
-	pipeline.StartWorker(db)
-	contentstage.StartWorker(db, controllers.ClassifyContentStage)
-	controllers.StartArtifactCoverageWorker(db)
-	controllers.StartAtomizationWorkVerifier(db)
-	controllers.StartStudioClearanceWorker(db)
-	// Admission records due work in CMS only. Aggregation later claims the
-	// CMS-issued unit; this scheduler never selects a queue or provider itself.
-	supply.StartSourceRunScheduler(db)
-	// Supply Continuity records CMS-derived attention episodes for explicitly
-	// owned Media-source tenants. It has no source admission, dispatch, queue,
-	// provider, retry, or Operator-plan authority; its only possible effect is
-	// one separately promotion-gated native Supply action.
-	controllers.StartMediaSupplyEvaluationHeartbeat(db)
-	// Shadow qualification is a CMS-only read loop. It cannot render a Console
-	// surface, call a model, build a plan, or promote the launch state.
-	controllers.StartOperatorShadowHeartbeat(db)
+	// Per-route-group CORS (CORS_*_ORIGINS plus tenant_cors_origins) and the
+	// standard security headers.
+	router.Use(utils.SecurityHeadersMiddleware(securityHeaders))
+	router.Use(utils.CORSMiddleware(db, corsPolicy))
+	router.Use(tracing.Middleware())
+	router.Use(metrics.Middleware())
+
+	if role.ServesAPI() {
+		SetupRoutes(router, db)
+		routes.SetupAdminAuthRoutes(router, db)
+		logCMSAuthConfig()
+	} else {
+		// Worker processes answer probes only.
+		SetupHealthRoutes(router, db)
+	}
+	SetupMetricsRoute(router, db)
+
+	// Every background worker below runs under the supervisor's root context.
+	// SIGINT/SIGTERM cancels it: workers finish the run they are in and stop,
+	// and shutdown waits for them (up to SHUTDOWN_TIMEOUT) before the DB closes.
+	supervisor := lifecycle.New(context.Background())
+	lifecycle.Install(supervisor)
+	ctx := supervisor.Context()
+
+	// Registered before the workers start: some run a first pass
+	// synchronously, and a SIGTERM arriving meanwhile must still take the
+	// graceful path below instead of killing the process.
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+
+	for _, worker := range workers {
+		worker.Start(ctx)
+	}
 
 	serverAddr := cmsServerAddress()
-	log.Printf("Starting server on %s...", serverAddr)
-	if err := router.Run(serverAddr); err != nil {
+	server := &http.Server{Addr: serverAddr, Handler: router}
+	serveErr := make(chan error, 1)
+	go func() {
+		log.Printf("Starting server on %s...", serverAddr)
+		serveErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
 		log.Fatalf("Failed to start server: %v", err)
+	case sig := <-signals:
+		log.Printf("shutdown: received %s, draining HTTP and stopping workers", sig)
 	}
-
+	signal.Stop(signals)
+
+	timeout := cmsShutdownTimeout()
+	deadline, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	// Workers stop taking new work while in-flight requests drain; both share
+	// the one deadline.
+	workersDone := make(chan error, 1)
+	go func() { workersDone <- supervisor.Shutdown(deadline) }()
+	if err := server.Shutdown(deadline); err != nil {
+		log.Printf("shutdown: HTTP drain incomplete after %s: %v", timeout, err)
+	} else {
+		log.Println("shutdown: HTTP drained")
+	}
+	if err := <-workersDone; err != nil {
+		log.Printf("shutdown: %v", err)
+	}
+	if err := shutdownTracing(deadline); err != nil {
+		log.Printf("shutdown: flushing traces: %v", err)
+	}
+	log.Println("shutdown: closing database")
 }
 
 func logCMSAuthConfig() {
@@ -534,7 +571,6 @@ func logCMSConnectionTargets() {
 	log.Printf("[CMS] - Enrichment API: %s", emptyOr(enrichmentBaseURL, "(not set)"))
 	log.Printf("[CMS] - Storage endpoint: %s", emptyOr(storageEndpoint, "(not set)"))
 	log.Printf("[CMS] - Storage public URL: %s", emptyOr(storagePublicURL, "(not set)"))
-	log.Println("[CMS] - CORS mode: AllowAllOrigins=true")
 }
 
 func cmsDatabaseTarget(dsn string) string {
@@ -559,6 +595,19 @@ func emptyOr(value, fallback string) string {
 	return value
 }
 
+// cmsShutdownTimeout bounds the graceful shutdown. Orchestrators send SIGKILL
+// after their own grace period (30s by default on Kubernetes), so the default
+// leaves a margin for the database close.
+func cmsShutdownTimeout() time.Duration {
+	if raw := strings.TrimSpace(os.Getenv("SHUTDOWN_TIMEOUT")); raw != "" {
+		if d, err := time.ParseDuration(raw); err == nil && d > 0 {
+			return d
+		}
+		log.Printf("WARNING: invalid SHUTDOWN_TIMEOUT %q, using 25s", raw)
+	}
+	return 25 * time.Second
+}
+
 func cmsServerAddress() string {
 	port := strings.TrimSpace(os.Getenv("PORT"))
 	if port == "" {
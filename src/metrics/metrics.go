@@ -0,0 +1,219 @@
+// Package metrics is the Prometheus exposition for /metrics. Collectors read
+// state the process already keeps (worker run clocks and heartbeats, the
+// sql.DB pool, Supply operational samples); request and business counters
+// are incremented where the work happens. Labels are fixed vocabularies:
+// route templates, never raw paths, and no tenant, user or item identifiers.
+package metrics
+
+import (
+	"database/sql"
+	"strconv"
+	"time"
+
+	"content-management-system/src/lifecycle"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"gorm.io/gorm"
+)
+
+const namespace = "cms"
+
+// Registry holds every CMS collector. It is separate from the client
+// library's global registry so imported packages cannot add series.
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by Gin route template, method and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+	httpRequestsInFlight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "http_requests_in_flight",
+		Help:      "HTTP requests currently being served.",
+	})
+
+	feedServed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "feed_served_total",
+		Help:      "Feed responses served, by feed.",
+	}, []string{"feed"})
+	feedItemsServed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "feed_items_served_total",
+		Help:      "Items carried by served feed responses, by feed.",
+	}, []string{"feed"})
+
+	aiSpendEvents = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "ai_spend_events_total",
+		Help:      "AI spend events accepted into the ledger, by spend class and authenticated reporting service.",
+	}, []string{"spend_class", "source_service"})
+	aiSpendUSD = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "ai_spend_usd_total",
+		Help:      "Priced cost of accepted AI spend events in USD, by spend class.",
+	}, []string{"spend_class"})
+	aiSpendAvoidedUSD = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "ai_spend_avoided_usd_total",
+		Help:      "Cost avoided by cached AI results in USD, by spend class.",
+	}, []string{"spend_class"})
+	aiSpendAllowance = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "ai_spend_allowance_checks_total",
+		Help:      "AI spend allowance answers, by spend class and verdict.",
+	}, []string{"spend_class", "verdict"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequestDuration, httpRequestsInFlight,
+		feedServed, feedItemsServed,
+		aiSpendEvents, aiSpendUSD, aiSpendAvoidedUSD, aiSpendAllowance,
+		workerCollector{},
+	)
+}
+
+// Middleware records request latency under the matched route template.
+// Unmatched requests share one series so scanners cannot grow the label set.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		started := time.Now()
+		httpRequestsInFlight.Inc()
+		defer httpRequestsInFlight.Dec()
+		c.Next()
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		httpRequestDuration.WithLabelValues(route, c.Request.Method, strconv.Itoa(c.Writer.Status())).
+			Observe(time.Since(started).Seconds())
+	}
+}
+
+// Handler serves the registry in the Prometheus text format.
+func Handler() gin.HandlerFunc {
+	h := promhttp.HandlerFor(Registry, promhttp.HandlerOpts{ErrorHandling: promhttp.ContinueOnError})
+	return func(c *gin.Context) {
+		h.ServeHTTP(c.Writer, c.Request)
+	}
+}
+
+// RegisterDB exposes the connection pool statistics of db (go_sql_* series,
+// db_name="cms").
+func RegisterDB(db *sql.DB) {
+	Registry.MustRegister(collectors.NewDBStatsCollector(db, namespace))
+}
+
+// RegisterSupply exposes the Supply operational samples of tenant.
+func RegisterSupply(db *gorm.DB, tenant string) {
+	Registry.MustRegister(newSupplyCollector(db, tenant))
+}
+
+// FeedServed counts one served feed response carrying items entries.
+func FeedServed(feed string, items int) {
+	feedServed.WithLabelValues(feed).Inc()
+	feedItemsServed.WithLabelValues(feed).Add(float64(items))
+}
+
+// aiSpendClasses are the spend classes the price book seeds. Any other class
+// a caller names is counted as "other" so the spend_class label stays bounded.
+var aiSpendClasses = map[string]bool{
+	"llm": true, "stt_api": true, "stt_local": true, "embedding": true,
+	"rerank": true, "image_embed": true, "unknown": true,
+}
+
+func spendClassLabel(spendClass string) string {
+	if aiSpendClasses[spendClass] {
+		return spendClass
+	}
+	return "other"
+}
+
+// aiSpendServices are the machine principal names, plus "unknown" when no
+// principal is attached. Any other name is counted as "other" so the
+// source_service label stays bounded.
+var aiSpendServices = map[string]bool{
+	"aggregation": true, "enrichment": true, "media": true, "iam": true,
+	"migration-coordinator": true, "metrics": true, "legacy-shared": true,
+	"unknown": true,
+}
+
+func sourceServiceLabel(service string) string {
+	if aiSpendServices[service] {
+		return service
+	}
+	return "other"
+}
+
+// AISpendAccepted counts one AI spend event newly written to the ledger.
+// service is the authenticated machine principal, never the event's
+// self-reported source_service.
+func AISpendAccepted(spendClass, service string, costUSD, avoidedUSD float64) {
+	spendClass = spendClassLabel(spendClass)
+	aiSpendEvents.WithLabelValues(spendClass, sourceServiceLabel(service)).Inc()
+	aiSpendUSD.WithLabelValues(spendClass).Add(costUSD)
+	aiSpendAvoidedUSD.WithLabelValues(spendClass).Add(avoidedUSD)
+}
+
+// AISpendAllowanceChecked counts one allowance answer.
+func AISpendAllowanceChecked(spendClass, verdict string) {
+	aiSpendAllowance.WithLabelValues(spendClassLabel(spendClass), verdict).Inc()
+}
+
+var (
+	workerLastRunDesc = prometheus.NewDesc(namespace+"_worker_last_run_timestamp_seconds",
+		"Unix time the worker loop last finished a run in this process.", []string{"worker"}, nil)
+	workerRunDurationDesc = prometheus.NewDesc(namespace+"_worker_last_run_duration_seconds",
+		"Duration of the worker loop's last run in this process.", []string{"worker"}, nil)
+	workerRunsDesc = prometheus.NewDesc(namespace+"_worker_runs_total",
+		"Runs the worker loop completed in this process.", []string{"worker"}, nil)
+	workerHealthyDesc = prometheus.NewDesc(namespace+"_worker_healthy",
+		"1 when the worker's heartbeat is fresh, as /health reports it.", []string{"worker"}, nil)
+	workerLastHeartbeatDesc = prometheus.NewDesc(namespace+"_worker_last_heartbeat_timestamp_seconds",
+		"Unix time the worker last marked itself alive in this process.", []string{"worker"}, nil)
+)
+
+// workerCollector reads the lifecycle run clocks and the heartbeat checks of
+// the workers this process runs at scrape time.
+type workerCollector struct{}
+
+func (workerCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- workerLastRunDesc
+	ch <- workerRunDurationDesc
+	ch <- workerRunsDesc
+	ch <- workerHealthyDesc
+	ch <- workerLastHeartbeatDesc
+}
+
+func (workerCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, stat := range lifecycle.RunStats() {
+		ch <- prometheus.MustNewConstMetric(workerLastRunDesc, prometheus.GaugeValue, float64(stat.LastFinished.UnixNano())/1e9, stat.Name)
+		ch <- prometheus.MustNewConstMetric(workerRunDurationDesc, prometheus.GaugeValue, stat.LastDuration.Seconds(), stat.Name)
+		ch <- prometheus.MustNewConstMetric(workerRunsDesc, prometheus.CounterValue, float64(stat.Runs), stat.Name)
+	}
+	now := time.Now().UTC()
+	for _, worker := range lifecycle.ProcessWorkers() {
+		if worker.LastHeartbeat != nil {
+			if at := worker.LastHeartbeat(); !at.IsZero() {
+				ch <- prometheus.MustNewConstMetric(workerLastHeartbeatDesc, prometheus.GaugeValue, float64(at.UnixNano())/1e9, worker.Name)
+			}
+		}
+		if worker.Healthy == nil {
+			continue
+		}
+		healthy := 0.0
+		if worker.Healthy(now) {
+			healthy = 1
+		}
+		ch <- prometheus.MustNewConstMetric(workerHealthyDesc, prometheus.GaugeValue, healthy, worker.Name)
+	}
+}
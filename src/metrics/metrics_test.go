@@ -0,0 +1,112 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"content-management-system/src/lifecycle"
+
+	"github.com/gin-gonic/gin"
+)
+
+func scrape(t *testing.T) string {
+	t.Helper()
+	router := gin.New()
+	router.GET("/metrics", Handler())
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("scrape status = %d", rec.Code)
+	}
+	return rec.Body.String()
+}
+
+func TestMiddlewareLabelsRouteTemplates(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Middleware())
+	router.GET("/api/v1/content/:id", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	for _, path := range []string{"/api/v1/content/a", "/api/v1/content/b", "/wp-login.php"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	body := scrape(t)
+	for _, want := range []string{
+		`cms_http_request_duration_seconds_count{method="GET",route="/api/v1/content/:id",status="204"} 2`,
+		`cms_http_request_duration_seconds_count{method="GET",route="unmatched",status="404"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("scrape missing %q:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "/api/v1/content/a") {
+		t.Fatal("raw paths must not become labels")
+	}
+}
+
+func TestCountersAndWorkerRuns(t *testing.T) {
+	FeedServed("rss", 12)
+	AISpendAccepted("llm", "enrichment", 0.25, 0)
+	AISpendAccepted("llm", "caller-chosen-service", 0, 0)
+	AISpendAllowanceChecked("llm", "within")
+	AISpendAllowanceChecked("caller-chosen-class", "within")
+
+	supervisor := lifecycle.New(context.Background())
+	tick := make(chan time.Time)
+	lifecycle.Go(supervisor.Context(), "metrics-test-worker", func(ctx context.Context) {
+		for lifecycle.Wait(ctx, tick) {
+		}
+	})
+	tick <- time.Now()
+	if err := supervisor.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	body := scrape(t)
+	for _, want := range []string{
+		`cms_feed_served_total{feed="rss"} 1`,
+		`cms_feed_items_served_total{feed="rss"} 12`,
+		`cms_ai_spend_events_total{source_service="enrichment",spend_class="llm"} 1`,
+		`cms_ai_spend_events_total{source_service="other",spend_class="llm"} 1`,
+		`cms_ai_spend_usd_total{spend_class="llm"} 0.25`,
+		`cms_ai_spend_allowance_checks_total{spend_class="llm",verdict="within"} 1`,
+		`cms_ai_spend_allowance_checks_total{spend_class="other",verdict="within"} 1`,
+		`cms_worker_runs_total{worker="metrics-test-worker"} 1`,
+		`cms_worker_last_run_timestamp_seconds{worker="metrics-test-worker"}`,
+		"go_goroutines",
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("scrape missing %q:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "caller-chosen-class") {
+		t.Fatal("unknown spend classes must not become labels")
+	}
+}
+
+func TestWorkerHeartbeatTimestamps(t *testing.T) {
+	beat := time.Unix(1_700_000_000, 0).UTC()
+	lifecycle.SetProcess(lifecycle.RoleWorker, []lifecycle.Worker{
+		{Name: "metrics-beating", Healthy: func(time.Time) bool { return true }, LastHeartbeat: func() time.Time { return beat }},
+		{Name: "metrics-silent", Healthy: func(time.Time) bool { return false }, LastHeartbeat: func() time.Time { return time.Time{} }},
+	})
+	defer lifecycle.SetProcess(lifecycle.RoleAll, nil)
+
+	body := scrape(t)
+	for _, want := range []string{
+		`cms_worker_last_heartbeat_timestamp_seconds{worker="metrics-beating"} 1.7e+09`,
+		`cms_worker_healthy{worker="metrics-beating"} 1`,
+		`cms_worker_healthy{worker="metrics-silent"} 0`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("scrape missing %q:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, `cms_worker_last_heartbeat_timestamp_seconds{worker="metrics-silent"}`) {
+		t.Fatal("a worker that has not beaten yet must not export a heartbeat timestamp")
+	}
+}
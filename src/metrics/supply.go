@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"content-management-system/src/supply"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gorm.io/gorm"
+)
+
+// supplyCacheTTL bounds how often scrapes rerun the Supply scans; every
+// replica is scraped and each scan is a handful of COUNT/MIN queries.
+const supplyCacheTTL = 30 * time.Second
+
+var (
+	supplyLabels    = []string{"name", "owner", "action", "stage", "verdict"}
+	supplyCountDesc = prometheus.NewDesc(namespace+"_supply_operational_count",
+		"Supply operational count samples (supply.BuildSupplyOperationalMetrics).", supplyLabels, nil)
+	supplyAgeDesc = prometheus.NewDesc(namespace+"_supply_operational_age_seconds",
+		"Supply operational age samples (supply.BuildSupplyOperationalMetrics).", supplyLabels, nil)
+	supplyUnknownsDesc = prometheus.NewDesc(namespace+"_supply_operational_unknowns",
+		"Supply operational samples that could not be read.", nil, nil)
+)
+
+type supplyCollector struct {
+	db     *gorm.DB
+	tenant string
+
+	mu      sync.Mutex
+	sampled time.Time
+	last    supply.SupplyOperationalMetrics
+}
+
+func newSupplyCollector(db *gorm.DB, tenant string) *supplyCollector {
+	return &supplyCollector{db: db, tenant: tenant}
+}
+
+func (c *supplyCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- supplyCountDesc
+	ch <- supplyAgeDesc
+	ch <- supplyUnknownsDesc
+}
+
+func (c *supplyCollector) Collect(ch chan<- prometheus.Metric) {
+	snapshot := c.snapshot(time.Now().UTC())
+	for _, sample := range snapshot.Samples {
+		desc := supplyCountDesc
+		switch sample.Unit {
+		case "count":
+		case "seconds":
+			desc = supplyAgeDesc
+		default:
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, sample.Value,
+			sample.Name, sample.Owner, sample.Action, sample.Stage, sample.Verdict)
+	}
+	ch <- prometheus.MustNewConstMetric(supplyUnknownsDesc, prometheus.GaugeValue, float64(len(snapshot.Unknowns)))
+}
+
+func (c *supplyCollector) snapshot(now time.Time) supply.SupplyOperationalMetrics {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.sampled.IsZero() || now.Sub(c.sampled) >= supplyCacheTTL {
+		c.last = supply.BuildSupplyOperationalMetrics(c.db, c.tenant, now)
+		c.sampled = now
+	}
+	return c.last
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BookmarkCollection is a listener's named, ordered playlist of saved content
+// ("Commute", "Research"). It can mix Pods chapters and News stories. When
+// ShareToken is set, the collection is readable by anyone holding the link
+// and syndicates as a feed.
+type BookmarkCollection struct {
+	ID          uint      `gorm:"primaryKey" json:"-"`
+	PublicID    uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();uniqueIndex" json:"id"`
+	TenantID    string    `gorm:"type:varchar(64);not null;index:idx_bookmark_collections_user,priority:1" json:"-"`
+	UserID      uuid.UUID `gorm:"type:uuid;not null;index:idx_bookmark_collections_user,priority:2" json:"-"`
+	Name        string    `gorm:"type:varchar(80);not null" json:"name"`
+	Description string    `gorm:"type:text" json:"description"`
+	ItemCount   int       `gorm:"not null;default:0" json:"item_count"`
+
+	// ShareToken is the unguessable capability behind the public share link;
+	// nil means the collection is private. Revoking and re-sharing mints a new
+	// token so old links stop working.
+	ShareToken *string    `gorm:"type:varchar(64);uniqueIndex" json:"-"`
+	SharedAt   *time.Time `json:"shared_at,omitempty"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	// UpdatedAt also moves on item changes (add, remove, reorder, note) so it
+	// can validate the shared feed.
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (BookmarkCollection) TableName() string {
+	return "bookmark_collections"
+}
+
+// BookmarkCollectionItem places one content item in a collection. Position is
+// dense and zero-based; the owner's note travels with the item into the
+// shared view and feed.
+type BookmarkCollectionItem struct {
+	ID            uint      `gorm:"primaryKey" json:"-"`
+	CollectionID  uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_bookmark_collection_items_content,priority:1;index:idx_bookmark_collection_items_position,priority:1" json:"-"`
+	ContentItemID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_bookmark_collection_items_content,priority:2;index" json:"content_id"`
+	Position      int       `gorm:"not null;default:0;index:idx_bookmark_collection_items_position,priority:2" json:"position"`
+	Note          string    `gorm:"type:text" json:"note"`
+	CreatedAt     time.Time `gorm:"autoCreateTime" json:"added_at"`
+	UpdatedAt     time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (BookmarkCollectionItem) TableName() string {
+	return "bookmark_collection_items"
+}
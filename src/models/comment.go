@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CommentRevision keeps the text a comment had before an author edit. The
+// current text stays in UserInteraction.Metadata.
+type CommentRevision struct {
+	ID        uint      `gorm:"primaryKey" json:"-"`
+	CommentID uuid.UUID `gorm:"type:uuid;not null;index" json:"-"`
+	Text      string    `gorm:"type:text;not null" json:"text"`
+	EditedBy  uuid.UUID `gorm:"type:uuid;not null" json:"-"`
+	// CreatedAt is when this text was replaced.
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"replaced_at"`
+}
+
+func (CommentRevision) TableName() string {
+	return "comment_revisions"
+}
+
+// CommentReactionKinds is the allowlist of reactions on a comment.
+var CommentReactionKinds = []string{"like", "love", "laugh", "insightful", "disagree"}
+
+// CommentReaction is one user's reaction to a comment; a user has at most one
+// reaction per comment and changing it replaces the kind.
+type CommentReaction struct {
+	CommentID uuid.UUID `gorm:"type:uuid;primaryKey" json:"comment_id"`
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey;index" json:"-"`
+	Kind      string    `gorm:"type:varchar(16);not null" json:"kind"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (CommentReaction) TableName() string {
+	return "comment_reactions"
+}
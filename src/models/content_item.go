@@ -270,8 +270,9 @@ type ContentItem struct {
 	// versioned key (`content/{id}/processed.v{N}.mp4`) without a S3 LIST.
 	MediaVersion int `gorm:"default:1" json:"media_version"`
 
-	// Timestamps
-	PublishedAt *time.Time `gorm:"type:timestamp" json:"published_at,omitempty"`
+	// Timestamps. published_at is TIMESTAMPTZ in the canonical schema
+	// (migrations/20260120000001_wahb_platform.sql); keyset cursors cast to it.
+	PublishedAt *time.Time `gorm:"type:timestamptz" json:"published_at,omitempty"`
 	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt   time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
 }
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// Digest cadences and ledger states.
+const (
+	DigestCadenceDaily  = "daily"
+	DigestCadenceWeekly = "weekly"
+
+	DigestDeliveryPending = "pending"
+	DigestDeliverySent    = "sent"
+	DigestDeliverySkipped = "skipped"
+	DigestDeliveryFailed  = "failed"
+)
+
+// DigestSubscription is a reader's opt-in to a scheduled digest of top
+// stories and Pods. NextDueAt is the next local send slot in UTC; the worker
+// claims due rows with a lease. UnsubscribeToken backs the one-click
+// unsubscribe link carried by every digest.
+type DigestSubscription struct {
+	ID       uint      `gorm:"primaryKey" json:"-"`
+	TenantID string    `gorm:"type:varchar(64);not null;uniqueIndex:idx_digest_subscriptions_user,priority:1" json:"-"`
+	UserID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_digest_subscriptions_user,priority:2" json:"-"`
+	Enabled  bool      `gorm:"not null;default:false" json:"enabled"`
+	Cadence  string    `gorm:"type:varchar(8);not null;default:'daily'" json:"cadence"`
+	Language string    `gorm:"type:varchar(4);not null;default:'ar'" json:"language"`
+	Email    string    `gorm:"type:varchar(255)" json:"email,omitempty"`
+	Timezone string    `gorm:"type:varchar(64);not null;default:'UTC'" json:"timezone"`
+	SendHour int       `gorm:"not null;default:7" json:"send_hour"`
+	// WeeklyDay is the time.Weekday weekly digests go out on (0 = Sunday).
+	WeeklyDay int `gorm:"not null;default:0" json:"weekly_day"`
+	// EmailConfirmedAt is set once the reader followed the link mailed to
+	// Email; digests are only mailed to a confirmed address. A new address
+	// gets a new EmailConfirmToken, and the worker mails the link until one
+	// send succeeds (EmailConfirmSentAt). EmailConfirmRetryAt is the send
+	// lease and, after a failure, the retry backoff.
+	EmailConfirmedAt     *time.Time `json:"email_confirmed_at,omitempty"`
+	EmailConfirmToken    *string    `gorm:"type:varchar(64);uniqueIndex" json:"-"`
+	EmailConfirmSentAt   *time.Time `json:"-"`
+	EmailConfirmRetryAt  *time.Time `json:"-"`
+	EmailConfirmAttempts int        `gorm:"not null;default:0" json:"-"`
+
+	UnsubscribeToken string     `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	NextDueAt        *time.Time `gorm:"index" json:"next_due_at,omitempty"`
+	LastSentAt       *time.Time `json:"last_sent_at,omitempty"`
+	LeaseUntil       *time.Time `json:"-"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (DigestSubscription) TableName() string {
+	return "digest_subscriptions"
+}
+
+// DigestDelivery is the send ledger: one row per subscription and period, so
+// a retried or re-claimed run never sends the same digest twice.
+type DigestDelivery struct {
+	ID             uint      `gorm:"primaryKey" json:"-"`
+	PublicID       uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();uniqueIndex" json:"id"`
+	SubscriptionID uint      `gorm:"not null;uniqueIndex:idx_digest_deliveries_period,priority:1" json:"-"`
+	PeriodKey      string    `gorm:"type:varchar(32);not null;uniqueIndex:idx_digest_deliveries_period,priority:2" json:"period"`
+	TenantID       string    `gorm:"type:varchar(64);not null;index:idx_digest_deliveries_user,priority:1" json:"-"`
+	UserID         uuid.UUID `gorm:"type:uuid;not null;index:idx_digest_deliveries_user,priority:2" json:"-"`
+	Cadence        string    `gorm:"type:varchar(8);not null" json:"cadence"`
+	Sender         string    `gorm:"type:varchar(16);not null" json:"sender"`
+	Status         string    `gorm:"type:varchar(16);not null;default:'pending'" json:"status"`
+	Attempts       int       `gorm:"not null;default:0" json:"attempts"`
+	Subject        string    `gorm:"type:text" json:"subject,omitempty"`
+	StoryCount     int       `gorm:"not null;default:0" json:"story_count"`
+	PodCount       int       `gorm:"not null;default:0" json:"pod_count"`
+	// ContentIDs lists the story and Pod ids the digest carried.
+	ContentIDs datatypes.JSON `gorm:"type:jsonb" json:"content_ids,omitempty"`
+	LastError  string         `gorm:"type:text" json:"error,omitempty"`
+	SentAt     *time.Time     `json:"sent_at,omitempty"`
+	CreatedAt  time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (DigestDelivery) TableName() string {
+	return "digest_deliveries"
+}
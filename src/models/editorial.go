@@ -0,0 +1,109 @@
+package models
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// EditorialStatus is the publishing lifecycle of a Page or Post.
+//
+//	draft ──publish──▶ published ──unpublish──▶ draft
+//	  │                    ▲
+//	  └─publish(at)─▶ scheduled (worker flips at publish_at)
+//	any ──archive──▶ archived (publish again to revive)
+type EditorialStatus string
+
+const (
+	EditorialDraft     EditorialStatus = "draft"
+	EditorialScheduled EditorialStatus = "scheduled"
+	EditorialPublished EditorialStatus = "published"
+	EditorialArchived  EditorialStatus = "archived"
+)
+
+// Editorial entity types stored in editorial_revisions.entity_type.
+const (
+	EditorialEntityPage = "page"
+	EditorialEntityPost = "post"
+)
+
+// EditorialState is the lifecycle shared by pages and posts. Title/Content on
+// the owning row are the working copy; readers only ever see the revision
+// named by PublishedRevision.
+type EditorialState struct {
+	TenantID string `gorm:"type:varchar(64);not null;default:default" json:"tenant_id"`
+	// Slug is unique per tenant and table (see the editorial migration).
+	Slug     string          `gorm:"type:text;not null" json:"slug"`
+	Status   EditorialStatus `gorm:"type:varchar(16);not null;default:draft" json:"status"`
+	AuthorID *string         `gorm:"type:varchar(128)" json:"author_id,omitempty"`
+	// CurrentRevision is the newest revision number (the working copy).
+	CurrentRevision int `gorm:"not null;default:0" json:"current_revision"`
+	// PublishedRevision is what public readers get; nil = not live.
+	PublishedRevision *int `json:"published_revision,omitempty"`
+	// ScheduledRevision is promoted to PublishedRevision at PublishAt.
+	ScheduledRevision *int       `json:"scheduled_revision,omitempty"`
+	PublishAt         *time.Time `json:"publish_at,omitempty"`
+	UnpublishAt       *time.Time `json:"unpublish_at,omitempty"`
+	PublishedAt       *time.Time `json:"published_at,omitempty"`
+	ArchivedAt        *time.Time `json:"archived_at,omitempty"`
+	// ContentFormat is how Content is written (markdown or html);
+	// ContentHTML and Excerpt are its sanitized rendering (see src/richtext).
+	ContentFormat string `gorm:"type:varchar(16);not null;default:html" json:"content_format"`
+	ContentHTML   string `gorm:"type:text" json:"content_html"`
+	Excerpt       string `gorm:"type:text" json:"excerpt"`
+}
+
+// EditorialRevision is an immutable snapshot of a page or post. Restoring an
+// old revision appends a new one, so history is never rewritten.
+type EditorialRevision struct {
+	ID         uint   `gorm:"primaryKey" json:"-"`
+	TenantID   string `gorm:"type:varchar(64);not null" json:"tenant_id"`
+	EntityType string `gorm:"type:varchar(16);not null;uniqueIndex:idx_editorial_revisions_entity,priority:1" json:"entity_type"`
+	EntityID   uint   `gorm:"not null;uniqueIndex:idx_editorial_revisions_entity,priority:2" json:"-"`
+	Revision   int    `gorm:"not null;uniqueIndex:idx_editorial_revisions_entity,priority:3" json:"revision"`
+	Title      string `gorm:"size:255;not null" json:"title"`
+	Content    string `gorm:"type:text;not null" json:"content"`
+	// ContentFormat, ContentHTML and Excerpt mirror EditorialState; revisions
+	// recorded before rendering existed have no ContentHTML.
+	ContentFormat string `gorm:"type:varchar(16);not null;default:html" json:"content_format"`
+	ContentHTML   string `gorm:"type:text" json:"content_html,omitempty"`
+	Excerpt       string `gorm:"type:text" json:"excerpt,omitempty"`
+	// Author and MediaIDs (media public IDs) are only meaningful for posts.
+	Author    *string        `gorm:"size:255" json:"author,omitempty"`
+	MediaIDs  pq.StringArray `gorm:"type:text[]" json:"media_ids,omitempty"`
+	Note      *string        `gorm:"type:text" json:"note,omitempty"`
+	CreatedBy *string        `gorm:"type:varchar(128)" json:"created_by,omitempty"`
+	CreatedAt time.Time      `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (EditorialRevision) TableName() string { return "editorial_revisions" }
+
+// EditorialDocument is implemented by Page and Post so the editorial
+// workflow (revisions, publishing, scheduling) is written once.
+type EditorialDocument interface {
+	EditorialEntity() string
+	EditorialRowID() uint
+	Editorial() *EditorialState
+	// Snapshot captures the working copy as an unsaved revision.
+	Snapshot() EditorialRevision
+	// ApplyRevision loads a revision's fields into the working copy.
+	ApplyRevision(rev EditorialRevision)
+}
+
+// snapshotRendering copies the rendered content into a revision.
+func (s *EditorialState) snapshotRendering(rev *EditorialRevision) {
+	rev.ContentFormat = s.ContentFormat
+	rev.ContentHTML = s.ContentHTML
+	rev.Excerpt = s.Excerpt
+}
+
+// applyRendering loads a revision's rendered content; revisions from before
+// rendering existed were HTML.
+func (s *EditorialState) applyRendering(rev EditorialRevision) {
+	s.ContentFormat = rev.ContentFormat
+	if s.ContentFormat == "" {
+		s.ContentFormat = "html"
+	}
+	s.ContentHTML = rev.ContentHTML
+	s.Excerpt = rev.Excerpt
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// InternalRequestNonce remembers a signed /internal request nonce until its
+// timestamp falls outside the accepted clock skew, so a captured request
+// cannot be replayed. Scoped per credential: two services may pick the same
+// nonce without colliding.
+type InternalRequestNonce struct {
+	CredentialID string    `gorm:"primaryKey;type:varchar(128)" json:"credential_id"`
+	Nonce        string    `gorm:"primaryKey;type:varchar(128)" json:"nonce"`
+	ExpiresAt    time.Time `gorm:"not null;index" json:"expires_at"`
+	CreatedAt    time.Time `gorm:"not null" json:"created_at"`
+}
+
+func (InternalRequestNonce) TableName() string {
+	return "internal_request_nonces"
+}
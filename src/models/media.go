@@ -7,10 +7,13 @@ import (
 )
 
 type Media struct {
-	ID        uint      `gorm:"primaryKey" json:"-"`
-	PublicID  uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();uniqueIndex:idx_media_public_id" json:"id"`
-	URL       string    `gorm:"size:255;not null" json:"url" binding:"required"`
-	Type      string    `gorm:"size:50" json:"type"`
+	ID       uint      `gorm:"primaryKey" json:"-"`
+	PublicID uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();uniqueIndex:idx_media_public_id" json:"id"`
+	URL      string    `gorm:"size:255;not null" json:"url" binding:"required"`
+	Type     string    `gorm:"size:50" json:"type"`
+	// TenantID is stamped from the creating admin; NULL rows predate tenancy
+	// and stay usable by every tenant.
+	TenantID  *string   `gorm:"type:varchar(64);index:idx_media_tenant" json:"tenant_id,omitempty"`
 	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 	Post      []Post    `gorm:"many2many:post_media" json:"posts,omitempty"`
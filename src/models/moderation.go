@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/datatypes"
 )
 
 const (
@@ -71,3 +72,43 @@ type AuthSuspension struct {
 }
 
 func (AuthSuspension) TableName() string { return "auth_suspensions" }
+
+// CommentClassifierConfig is a tenant's comment-toxicity policy. Thresholds
+// are JSON maps of category → score in [0, 1]; empty means the built-in
+// defaults. AutoAllowBelow releases comments the deterministic policy would
+// hold for review when every classifier score stays under it (0 disables).
+type CommentClassifierConfig struct {
+	ID               uint           `gorm:"primaryKey" json:"-"`
+	TenantID         string         `gorm:"type:varchar(64);not null;uniqueIndex" json:"tenant_id"`
+	HoldThresholds   datatypes.JSON `gorm:"type:jsonb" json:"hold_thresholds,omitempty"`
+	RejectThresholds datatypes.JSON `gorm:"type:jsonb" json:"reject_thresholds,omitempty"`
+	AutoAllowBelow   float64        `gorm:"type:double precision;not null;default:0" json:"auto_allow_below"`
+	// ScorerEnabled adds the HTTP scorer (COMMENT_TOXICITY_SCORER_URL) to the
+	// local lexicon. Comment text leaves CMS only when this is on.
+	ScorerEnabled bool `gorm:"not null;default:false" json:"scorer_enabled"`
+	// LearningEnabled lets review outcomes move hold thresholds.
+	LearningEnabled bool      `gorm:"not null" json:"learning_enabled"`
+	UpdatedBy       string    `gorm:"type:varchar(255)" json:"updated_by,omitempty"`
+	CreatedAt       time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (CommentClassifierConfig) TableName() string { return "comment_classifier_configs" }
+
+// CommentClassifierStat counts moderator outcomes for comments the classifier
+// held, per category: Confirmed when removed, Overturned (a false positive)
+// when allowed.
+type CommentClassifierStat struct {
+	TenantID   string    `gorm:"type:varchar(64);primaryKey" json:"-"`
+	Category   string    `gorm:"type:varchar(32);primaryKey" json:"category"`
+	Confirmed  int64     `gorm:"not null;default:0" json:"confirmed"`
+	Overturned int64     `gorm:"not null;default:0" json:"overturned"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (CommentClassifierStat) TableName() string { return "comment_classifier_stats" }
+
+// DefaultCommentClassifierConfig is the policy of a tenant that never saved one.
+func DefaultCommentClassifierConfig(tenantID string) CommentClassifierConfig {
+	return CommentClassifierConfig{TenantID: tenantID, LearningEnabled: true}
+}
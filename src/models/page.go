@@ -7,10 +7,33 @@ import (
 )
 
 type Page struct {
-	ID        uint      `gorm:"primaryKey" json:"-"`
-	PublicID  uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();uniqueIndex:idx_pages_public_id" json:"id"`
-	Title     string    `gorm:"size:255;not null" json:"title" binding:"required"`
-	Content   string    `gorm:"type:text;not null" json:"content" binding:"required"`
-	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	ID             uint      `gorm:"primaryKey" json:"-"`
+	PublicID       uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();uniqueIndex:idx_pages_public_id" json:"id"`
+	Title          string    `gorm:"size:255;not null" json:"title" binding:"required"`
+	Content        string    `gorm:"type:text;not null" json:"content" binding:"required"`
+	EditorialState `gorm:"embedded"`
+	CreatedAt      time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (p *Page) EditorialEntity() string    { return EditorialEntityPage }
+func (p *Page) EditorialRowID() uint       { return p.ID }
+func (p *Page) Editorial() *EditorialState { return &p.EditorialState }
+
+func (p *Page) Snapshot() EditorialRevision {
+	rev := EditorialRevision{
+		TenantID:   p.TenantID,
+		EntityType: EditorialEntityPage,
+		EntityID:   p.ID,
+		Title:      p.Title,
+		Content:    p.Content,
+	}
+	p.snapshotRendering(&rev)
+	return rev
+}
+
+func (p *Page) ApplyRevision(rev EditorialRevision) {
+	p.Title = rev.Title
+	p.Content = rev.Content
+	p.applyRendering(rev)
 }
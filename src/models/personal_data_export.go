@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Personal data export job states.
+const (
+	PersonalDataExportQueued  = "queued"
+	PersonalDataExportRunning = "running"
+	PersonalDataExportReady   = "ready"
+	PersonalDataExportFailed  = "failed"
+	PersonalDataExportExpired = "expired"
+)
+
+// PersonalDataExport is one user's request for a copy of their CMS product
+// data. A worker builds the archive (a zip of JSON and CSV files) into
+// Archive; it is served through a signed, short-lived link until ExpiresAt and
+// then cleared.
+type PersonalDataExport struct {
+	ID       uint      `gorm:"primaryKey" json:"-"`
+	PublicID uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();uniqueIndex" json:"id"`
+	TenantID string    `gorm:"type:varchar(64);not null;index:idx_personal_data_exports_user,priority:1" json:"-"`
+	UserID   uuid.UUID `gorm:"type:uuid;not null;index:idx_personal_data_exports_user,priority:2" json:"-"`
+	Status   string    `gorm:"type:varchar(16);not null;default:'queued';index" json:"status"`
+
+	// Worker claim. LeaseUntil lets another replica take over a job whose
+	// worker died mid-build.
+	Attempts   int        `gorm:"not null;default:0" json:"-"`
+	LeaseUntil *time.Time `json:"-"`
+	LastError  string     `gorm:"type:text" json:"-"`
+
+	Archive       []byte     `gorm:"type:bytea" json:"-"`
+	ArchiveSHA256 string     `gorm:"type:char(64)" json:"-"`
+	ArchiveBytes  int64      `gorm:"not null;default:0" json:"size_bytes,omitempty"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt     *time.Time `gorm:"index" json:"expires_at,omitempty"`
+	Downloads     int        `gorm:"not null;default:0" json:"downloads"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"requested_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (PersonalDataExport) TableName() string {
+	return "personal_data_exports"
+}
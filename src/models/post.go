@@ -4,15 +4,53 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 type Post struct {
-	ID        uint      `gorm:"primaryKey" json:"-"`
-	PublicID  uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();uniqueIndex:idx_posts_public_id" json:"id"`
-	Title     string    `gorm:"size:255;not null" json:"title" binding:"required"`
-	Content   string    `gorm:"type:text;not null" json:"content" binding:"required"`
-	Author    string    `gorm:"size:255;not null" json:"author" binding:"required"`
-	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
-	Media     []Media   `gorm:"many2many:post_media" json:"media,omitempty"`
+	ID             uint      `gorm:"primaryKey" json:"-"`
+	PublicID       uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();uniqueIndex:idx_posts_public_id" json:"id"`
+	Title          string    `gorm:"size:255;not null" json:"title" binding:"required"`
+	Content        string    `gorm:"type:text;not null" json:"content" binding:"required"`
+	Author         string    `gorm:"size:255;not null" json:"author" binding:"required"`
+	EditorialState `gorm:"embedded"`
+	CreatedAt      time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	Media          []Media   `gorm:"many2many:post_media" json:"media,omitempty"`
+	// AttachedMediaIDs are the media public IDs attached explicitly; Media is
+	// these plus whatever the content references.
+	AttachedMediaIDs pq.StringArray `gorm:"type:text[]" json:"attached_media_ids,omitempty"`
+}
+
+func (p *Post) EditorialEntity() string    { return EditorialEntityPost }
+func (p *Post) EditorialRowID() uint       { return p.ID }
+func (p *Post) Editorial() *EditorialState { return &p.EditorialState }
+
+// Snapshot records the linked media by public ID so a restore can re-link it.
+func (p *Post) Snapshot() EditorialRevision {
+	author := p.Author
+	mediaIDs := make(pq.StringArray, 0, len(p.Media))
+	for _, m := range p.Media {
+		mediaIDs = append(mediaIDs, m.PublicID.String())
+	}
+	rev := EditorialRevision{
+		TenantID:   p.TenantID,
+		EntityType: EditorialEntityPost,
+		EntityID:   p.ID,
+		Title:      p.Title,
+		Content:    p.Content,
+		Author:     &author,
+		MediaIDs:   mediaIDs,
+	}
+	p.snapshotRendering(&rev)
+	return rev
+}
+
+func (p *Post) ApplyRevision(rev EditorialRevision) {
+	p.Title = rev.Title
+	p.Content = rev.Content
+	if rev.Author != nil {
+		p.Author = *rev.Author
+	}
+	p.applyRendering(rev)
 }
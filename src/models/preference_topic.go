@@ -158,6 +158,24 @@ type UserCategoryAffinity struct {
 
 func (UserCategoryAffinity) TableName() string { return "user_category_affinity" }
 
+// UserTasteVector is an identity's running taste in the text embedding space:
+// a decayed, weighted mean of the embeddings of items it liked, bookmarked or
+// watched meaningfully/completely. Identity is "user:<uuid>" or
+// "session:<id>". A vector is only comparable with items of the same
+// EmbeddingSpaceID; a space change starts it over.
+type UserTasteVector struct {
+	TenantID         string           `gorm:"type:varchar(64);primaryKey" json:"tenant_id"`
+	Identity         string           `gorm:"type:varchar(300);primaryKey" json:"identity"`
+	Embedding        *pgvector.Vector `gorm:"type:vector(1024)" json:"-"`
+	EmbeddingSpaceID string           `gorm:"type:char(64);not null;column:embedding_space_id" json:"embedding_space_id"`
+	// Weight is the decayed sum of interaction weights folded into Embedding.
+	Weight       float64   `gorm:"type:double precision;not null" json:"weight"`
+	Interactions int       `gorm:"type:integer;not null" json:"interactions"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (UserTasteVector) TableName() string { return "user_taste_vectors" }
+
 type PreferenceSettings struct {
 	ID                uint      `gorm:"primaryKey" json:"-"`
 	TenantID          string    `gorm:"type:varchar(64);not null;uniqueIndex:idx_preference_settings_tenant" json:"tenant_id"`
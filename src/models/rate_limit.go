@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// RateLimitBucket is one token bucket of the shared (Postgres) rate limiter.
+// BucketKey is "<policy>:<digest>"; the digest hides the raw identity, IP or
+// session it was derived from. Allowed records whether the last request was
+// admitted, so a single upsert can both refill and spend.
+type RateLimitBucket struct {
+	BucketKey string    `gorm:"primaryKey;type:varchar(160)" json:"bucket_key"`
+	Tokens    float64   `gorm:"not null" json:"tokens"`
+	Allowed   bool      `gorm:"not null" json:"allowed"`
+	UpdatedAt time.Time `gorm:"not null" json:"updated_at"`
+	ExpiresAt time.Time `gorm:"not null;index" json:"expires_at"`
+}
+
+func (RateLimitBucket) TableName() string {
+	return "rate_limit_buckets"
+}
@@ -4,10 +4,12 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 // RSSFeed is a saved, named syndication feed an admin defines in the News
-// manager. It captures the filters (topic, type, item count) + presentation
+// manager. It captures the filters (stories, sources, languages, categories,
+// formats, quality, exclusions, type, item count) + presentation
 // (title, description) and is served publicly at a stable slug in RSS/Atom/JSON.
 type RSSFeed struct {
 	ID       uint      `gorm:"primaryKey" json:"-"`
@@ -25,6 +27,23 @@ type RSSFeed struct {
 	ContentType string     `gorm:"type:varchar(20)" json:"content_type"`
 	ItemLimit   int        `gorm:"default:50" json:"item_limit"`
 
+	// Curated-feed filters. Every empty list is "no constraint"; non-empty
+	// lists are ORed within themselves and ANDed with each other. StoryIDs is
+	// unioned with the legacy single StoryID. IDs are canonical UUID strings.
+	StoryIDs         pq.StringArray `gorm:"type:text[];not null;default:'{}'" json:"story_ids"`
+	ContentSourceIDs pq.StringArray `gorm:"type:text[];not null;default:'{}'" json:"content_source_ids"`
+	ContentLanguages pq.StringArray `gorm:"type:text[];not null;default:'{}'" json:"content_languages"`
+	StoryCategories  pq.StringArray `gorm:"type:text[];not null;default:'{}'" json:"story_categories"`
+	Formats          pq.StringArray `gorm:"type:text[];not null;default:'{}'" json:"formats"`
+	// MinQuality is the minimum persisted media intelligence value (0..1).
+	// NEWS rows carry no value score and are not constrained by it.
+	MinQuality *float64 `gorm:"type:double precision" json:"min_quality,omitempty"`
+
+	// Exclusions win over every inclusion filter.
+	ExcludeContentIDs pq.StringArray `gorm:"type:text[];not null;default:'{}'" json:"exclude_content_ids"`
+	ExcludeSourceIDs  pq.StringArray `gorm:"type:text[];not null;default:'{}'" json:"exclude_source_ids"`
+	ExcludeStoryIDs   pq.StringArray `gorm:"type:text[];not null;default:'{}'" json:"exclude_story_ids"`
+
 	Enabled bool `gorm:"default:true" json:"enabled"`
 
 	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
@@ -34,3 +53,16 @@ type RSSFeed struct {
 func (RSSFeed) TableName() string {
 	return "rss_feeds"
 }
+
+// FeedMembershipMark records the item set a public feed last rendered and
+// when that set last changed. Row timestamps cannot move Last-Modified when an
+// item leaves a feed (unpublished, deleted, pushed past the limit), so every
+// replica reads ChangedAt from this row instead of remembering it in-process.
+// FeedKey hashes the normalized feed query, never the raw request URI.
+type FeedMembershipMark struct {
+	FeedKey   string    `gorm:"type:varchar(64);primaryKey" json:"feed_key"`
+	Digest    string    `gorm:"type:varchar(64);not null" json:"digest"`
+	ChangedAt time.Time `gorm:"not null" json:"changed_at"`
+}
+
+func (FeedMembershipMark) TableName() string { return "feed_membership_marks" }
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// StoryFollow subscribes a reader to a News story. LastSeenAt is the reader's
+// "last looked" mark: members ingested after it count as new. Follows of a
+// story that no longer exists are ignored by readers; merges move them to
+// the surviving story.
+type StoryFollow struct {
+	ID         uint      `gorm:"primaryKey" json:"-"`
+	TenantID   string    `gorm:"type:varchar(64);not null;uniqueIndex:idx_story_follows_user_story,priority:1" json:"-"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_story_follows_user_story,priority:2" json:"-"`
+	StoryID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_story_follows_user_story,priority:3;index" json:"story_id"`
+	LastSeenAt time.Time `gorm:"not null" json:"last_seen_at"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"followed_at"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (StoryFollow) TableName() string {
+	return "story_follows"
+}
+
+// StorySummaryRevision snapshots a story digest each time it changes, so the
+// timeline can show how the summary evolved. The current digest stays on
+// Story.
+type StorySummaryRevision struct {
+	ID          uint           `gorm:"primaryKey" json:"-"`
+	StoryID     uuid.UUID      `gorm:"type:uuid;not null;index:idx_story_summary_revisions_story,priority:1" json:"-"`
+	Summary     string         `gorm:"type:text" json:"summary"`
+	Bullets     datatypes.JSON `gorm:"type:jsonb" json:"-"`
+	Category    string         `gorm:"type:text" json:"category,omitempty"`
+	MemberCount int            `gorm:"not null;default:0" json:"member_count"`
+	CreatedAt   time.Time      `gorm:"autoCreateTime;index:idx_story_summary_revisions_story,priority:2" json:"created_at"`
+}
+
+func (StorySummaryRevision) TableName() string {
+	return "story_summary_revisions"
+}
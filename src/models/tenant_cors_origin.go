@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// TenantCORSOrigin lets a tenant admit a browser origin for one CORS route
+// group (public, consumer or admin) on top of the deployment's CORS_*_ORIGINS
+// allowlist.
+type TenantCORSOrigin struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	TenantID   string    `gorm:"type:varchar(64);not null;uniqueIndex:idx_tenant_cors_origins_origin,priority:1" json:"-"`
+	RouteGroup string    `gorm:"type:varchar(16);not null;uniqueIndex:idx_tenant_cors_origins_origin,priority:2" json:"route_group"`
+	Origin     string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_tenant_cors_origins_origin,priority:3" json:"origin"`
+	CreatedBy  string    `gorm:"type:varchar(255)" json:"created_by,omitempty"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (TenantCORSOrigin) TableName() string {
+	return "tenant_cors_origins"
+}
@@ -52,6 +52,21 @@ type UserInteraction struct {
 	// until a moderator decides; legacy NULL comments are treated as allowed.
 	CommentModerationStatus *string `gorm:"type:varchar(16);index" json:"-"`
 	CommentModerationReason *string `gorm:"type:varchar(64)" json:"-"`
+	// CommentModerationSignals is the classifier evidence (scores, reasons,
+	// rules) shown to moderators and used to learn from their decisions.
+	CommentModerationSignals datatypes.JSON `gorm:"type:jsonb" json:"-"`
+	// Threading for comments. CommentRootID is the top-level comment of the
+	// thread (nil on top-level comments); CommentDepth is 0 at the top level.
+	CommentParentID *uuid.UUID `gorm:"type:uuid;index" json:"parent_id,omitempty"`
+	CommentRootID   *uuid.UUID `gorm:"type:uuid;index" json:"-"`
+	CommentDepth    int        `gorm:"not null;default:0" json:"-"`
+	// CommentScore is the number of reactions, kept in step with
+	// comment_reactions for the "top" sort.
+	CommentScore    int        `gorm:"not null;default:0" json:"-"`
+	CommentEditedAt *time.Time `json:"-"`
+	// CommentDeletedAt marks a tombstone: a comment removed after it was
+	// replied to. Its text is cleared but the row keeps the thread together.
+	CommentDeletedAt *time.Time `gorm:"index" json:"-"`
 
 	// Timestamp
 	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
@@ -107,4 +122,6 @@ type CreateInteractionRequest struct {
 	// Kept only so older clients that still send it do not fail binding.
 	UserID   *string        `json:"user_id,omitempty"`
 	Metadata datatypes.JSON `json:"metadata,omitempty"`
+	// ParentID makes a comment a reply to another comment on the same item.
+	ParentID *string `json:"parent_id,omitempty"`
 }
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebSub subscription lifecycle. A subscription only receives content while
+// active; pending intents are verified asynchronously by the hub worker.
+const (
+	WebSubStatePending      = "pending"
+	WebSubStateActive       = "active"
+	WebSubStateDenied       = "denied"
+	WebSubStateExpired      = "expired"
+	WebSubStateUnsubscribed = "unsubscribed"
+)
+
+// WebSubSubscription is one subscriber callback registered with the CMS hub for
+// a saved feed representation (the topic: /api/v1/feed/saved/:slug[?format=]).
+// Subscribe/unsubscribe requests are recorded as a pending intent and only
+// applied once the callback echoes the hub challenge (W3C WebSub §5.3).
+type WebSubSubscription struct {
+	ID        uint      `gorm:"primaryKey" json:"-"`
+	PublicID  uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();uniqueIndex:idx_websub_subscriptions_public_id" json:"id"`
+	TenantID  string    `gorm:"type:varchar(64);not null;index:idx_websub_subscriptions_tenant" json:"tenant_id"`
+	RSSFeedID uuid.UUID `gorm:"type:uuid;not null;index:idx_websub_subscriptions_feed" json:"rss_feed_id"`
+	Topic     string    `gorm:"type:text;not null;uniqueIndex:idx_websub_subscriptions_topic_callback,priority:1" json:"topic"`
+	Callback  string    `gorm:"type:text;not null;uniqueIndex:idx_websub_subscriptions_topic_callback,priority:2" json:"callback"`
+	Format    string    `gorm:"type:varchar(16);not null" json:"format"`
+
+	// Secret signs content distribution (X-Hub-Signature). Never serialized.
+	Secret       string     `gorm:"type:text" json:"-"`
+	LeaseSeconds int        `gorm:"not null;default:0" json:"lease_seconds"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	State        string     `gorm:"type:varchar(20);not null;default:'pending'" json:"state"`
+	VerifiedAt   *time.Time `json:"verified_at,omitempty"`
+
+	// Pending intent awaiting verification. PendingMode "" = nothing to verify.
+	PendingMode         string     `gorm:"type:varchar(16)" json:"pending_mode,omitempty"`
+	PendingLeaseSeconds int        `json:"-"`
+	PendingSecret       string     `gorm:"type:text" json:"-"`
+	VerifyAfter         *time.Time `json:"verify_after,omitempty"`
+	VerifyAttempts      int        `gorm:"not null;default:0" json:"verify_attempts"`
+
+	LastDeliveryAt      *time.Time `json:"last_delivery_at,omitempty"`
+	ConsecutiveFailures int        `gorm:"not null;default:0" json:"consecutive_failures"`
+	LastError           string     `gorm:"type:text" json:"last_error,omitempty"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (WebSubSubscription) TableName() string { return "websub_subscriptions" }
+
+// WebSub delivery states.
+const (
+	WebSubDeliveryPending   = "pending"
+	WebSubDeliveryDelivered = "delivered"
+	WebSubDeliveryFailed    = "failed"
+	WebSubDeliveryDropped   = "dropped"
+)
+
+// WebSubDelivery is the durable outbox row for one item that became READY and
+// matched a subscribed saved feed. Rows are written in the same transaction as
+// the status change and drained by the hub worker with exponential backoff.
+type WebSubDelivery struct {
+	ID             uint       `gorm:"primaryKey" json:"-"`
+	PublicID       uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid()" json:"id"`
+	TenantID       string     `gorm:"type:varchar(64);not null" json:"tenant_id"`
+	SubscriptionID uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_websub_deliveries_subscription_item,priority:1" json:"subscription_id"`
+	ContentItemID  uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_websub_deliveries_subscription_item,priority:2" json:"content_item_id"`
+	State          string     `gorm:"type:varchar(16);not null;default:'pending'" json:"state"`
+	Attempts       int        `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt  time.Time  `gorm:"not null" json:"next_attempt_at"`
+	LastStatus     int        `json:"last_status,omitempty"`
+	LastError      string     `gorm:"type:text" json:"last_error,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (WebSubDelivery) TableName() string { return "websub_deliveries" }
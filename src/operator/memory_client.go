@@ -8,6 +8,8 @@ import (
 	"net/http"
 	"strings"
 	"time"
+
+	"content-management-system/src/tracing"
 )
 
 type HTTPMemoryEmbedder struct {
@@ -21,7 +23,7 @@ func NewHTTPMemoryEmbedder(baseURL, token string, client *http.Client) (*HTTPMem
 		return nil, fmt.Errorf("%w: memory embedding capability unavailable", ErrInvalidContract)
 	}
 	if client == nil {
-		client = &http.Client{Timeout: 20 * time.Second}
+		client = &http.Client{Transport: tracing.Transport(nil), Timeout: 20 * time.Second}
 	}
 	return &HTTPMemoryEmbedder{baseURL, token, client}, nil
 }
@@ -12,6 +12,8 @@ import (
 	"time"
 
 	"content-management-system/src/models"
+
+	"content-management-system/src/tracing"
 )
 
 type ReasonRequest struct {
@@ -75,7 +77,7 @@ func NewHTTPReasoner(baseURL, token string, client *http.Client) (*HTTPReasoner,
 		return nil, fmt.Errorf("%w: enrichment service capability is unavailable", ErrInvalidContract)
 	}
 	if client == nil {
-		client = &http.Client{Timeout: 20 * time.Second}
+		client = &http.Client{Transport: tracing.Transport(nil), Timeout: 20 * time.Second}
 	}
 	return &HTTPReasoner{endpoint: baseURL + "/v1/operator/reason", token: token, client: client}, nil
 }
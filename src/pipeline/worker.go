@@ -1,10 +1,13 @@
 package pipeline
 
 import (
+	"context"
 	"log"
 	"sync/atomic"
 	"time"
 
+	"content-management-system/src/lifecycle"
+
 	"gorm.io/gorm"
 )
 
@@ -12,20 +15,24 @@ var workerHeartbeat atomic.Int64
 
 // StartWorker only recovers leases and verifies persisted CMS evidence. It
 // never calls Aggregation, changes content status, or replays an owner effect.
-func StartWorker(db *gorm.DB) {
+func StartWorker(ctx context.Context, db *gorm.DB) {
 	runWorkerOnce(db)
-	go func() {
+	lifecycle.Go(ctx, "pipeline-repair", func(ctx context.Context) {
 		ticker := time.NewTicker(10 * time.Second)
 		defer ticker.Stop()
-		for range ticker.C {
+		for lifecycle.Wait(ctx, ticker.C) {
 			runWorkerOnce(db)
 		}
-	}()
+	})
 }
 func WorkerHealthy(now time.Time) bool {
 	at := workerHeartbeat.Load()
 	return at > 0 && now.UTC().Sub(time.Unix(0, at).UTC()) <= 90*time.Second
 }
+
+func WorkerLastHeartbeat() time.Time {
+	return lifecycle.HeartbeatTime(workerHeartbeat.Load())
+}
 func runWorkerOnce(db *gorm.DB) {
 	if err := RecoverExpired(db); err != nil {
 		log.Printf("pipeline repair recovery failed: %v", err)
@@ -0,0 +1,573 @@
+package richtext
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// renderMarkdown converts the CommonMark subset editors use to HTML: ATX and
+// setext headings, paragraphs, block quotes, nested lists, fenced and indented
+// code, thematic breaks, emphasis, strikethrough, code spans, links, images and
+// autolinks. Raw HTML passes through; the sanitizer cleans it afterwards.
+func renderMarkdown(src string) string {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	src = strings.ReplaceAll(src, "\r", "\n")
+	src = strings.ReplaceAll(src, "\t", "    ")
+	var b strings.Builder
+	renderBlocks(&b, strings.Split(src, "\n"), false)
+	return b.String()
+}
+
+var (
+	atxHeading    = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ ]+(.*?))?(?:[ ]+#+)?[ ]*$`)
+	thematicBreak = regexp.MustCompile(`^ {0,3}(?:(?:\*[ ]*){3,}|(?:-[ ]*){3,}|(?:_[ ]*){3,})$`)
+	setextLine    = regexp.MustCompile(`^ {0,3}(=+|-+)[ ]*$`)
+	fenceOpen     = regexp.MustCompile("^( {0,3})(`{3,}|~{3,})[ ]*([^`\\s]*)")
+	bulletItem    = regexp.MustCompile(`^( {0,3})([-+*])( +|$)`)
+	orderedItem   = regexp.MustCompile(`^( {0,3})(\d{1,9})([.)])( +|$)`)
+	htmlBlockOpen = regexp.MustCompile(`^ {0,3}</?[a-zA-Z][a-zA-Z0-9-]*(?:\s|/?>|$)|^ {0,3}<!--`)
+)
+
+func isBlank(line string) bool { return strings.TrimSpace(line) == "" }
+
+func indentOf(line string) int { return len(line) - len(strings.TrimLeft(line, " ")) }
+
+// renderBlocks renders a sequence of lines. tight drops the <p> wrapper around
+// paragraphs, as in tight list items.
+func renderBlocks(b *strings.Builder, lines []string, tight bool) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		switch {
+		case isBlank(line):
+			i++
+
+		case fenceOpen.MatchString(line):
+			i = renderFence(b, lines, i)
+
+		case indentOf(line) >= 4:
+			var code []string
+			for i < len(lines) && (isBlank(lines[i]) || indentOf(lines[i]) >= 4) {
+				if isBlank(lines[i]) {
+					code = append(code, "")
+				} else {
+					code = append(code, lines[i][4:])
+				}
+				i++
+			}
+			for len(code) > 0 && code[len(code)-1] == "" {
+				code = code[:len(code)-1]
+			}
+			b.WriteString("<pre><code>" + html.EscapeString(strings.Join(code, "\n")) + "\n</code></pre>\n")
+
+		case atxHeading.MatchString(line):
+			m := atxHeading.FindStringSubmatch(line)
+			level := strconv.Itoa(len(m[1]))
+			b.WriteString("<h" + level + ">" + renderInline(strings.TrimSpace(m[2])) + "</h" + level + ">\n")
+			i++
+
+		case thematicBreak.MatchString(line):
+			b.WriteString("<hr>\n")
+			i++
+
+		case strings.HasPrefix(strings.TrimLeft(line, " "), ">") && indentOf(line) < 4:
+			var quoted []string
+			for i < len(lines) && !isBlank(lines[i]) {
+				trimmed := strings.TrimLeft(lines[i], " ")
+				if strings.HasPrefix(trimmed, ">") {
+					trimmed = strings.TrimPrefix(trimmed[1:], " ")
+				} else if len(quoted) == 0 || startsBlock(lines[i]) {
+					break
+				}
+				quoted = append(quoted, trimmed)
+				i++
+			}
+			b.WriteString("<blockquote>\n")
+			renderBlocks(b, quoted, false)
+			b.WriteString("</blockquote>\n")
+
+		case bulletItem.MatchString(line) || orderedItem.MatchString(line):
+			i = renderList(b, lines, i)
+
+		case htmlBlockOpen.MatchString(line):
+			for i < len(lines) && !isBlank(lines[i]) {
+				b.WriteString(lines[i] + "\n")
+				i++
+			}
+
+		default:
+			var para []string
+			for i < len(lines) && !isBlank(lines[i]) {
+				if len(para) > 0 {
+					if m := setextLine.FindStringSubmatch(lines[i]); m != nil {
+						tag := "h2"
+						if m[1][0] == '=' {
+							tag = "h1"
+						}
+						b.WriteString("<" + tag + ">" + renderInline(strings.Join(para, "\n")) + "</" + tag + ">\n")
+						para = nil
+						i++
+						break
+					}
+					if startsBlock(lines[i]) {
+						break
+					}
+				}
+				para = append(para, strings.TrimLeft(lines[i], " "))
+				i++
+			}
+			if len(para) == 0 {
+				continue
+			}
+			text := renderInline(strings.TrimRight(strings.Join(para, "\n"), " "))
+			if tight {
+				b.WriteString(text + "\n")
+			} else {
+				b.WriteString("<p>" + text + "</p>\n")
+			}
+		}
+	}
+}
+
+// startsBlock reports whether line interrupts a paragraph.
+func startsBlock(line string) bool {
+	if indentOf(line) >= 4 {
+		return false
+	}
+	trimmed := strings.TrimLeft(line, " ")
+	return atxHeading.MatchString(line) || thematicBreak.MatchString(line) ||
+		fenceOpen.MatchString(line) || strings.HasPrefix(trimmed, ">") ||
+		bulletItem.MatchString(line) || orderedItem.MatchString(line) ||
+		htmlBlockOpen.MatchString(line)
+}
+
+func renderFence(b *strings.Builder, lines []string, i int) int {
+	m := fenceOpen.FindStringSubmatch(lines[i])
+	indent, fence, lang := len(m[1]), m[2], m[3]
+	var code []string
+	i++
+	for ; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" && indentOf(lines[i]) < 4 {
+			i++
+			break
+		}
+		line := lines[i]
+		if n := indentOf(line); n > 0 {
+			line = line[min(n, indent):]
+		}
+		code = append(code, line)
+	}
+	b.WriteString("<pre><code")
+	if lang != "" {
+		b.WriteString(` class="language-` + html.EscapeString(strings.ToLower(lang)) + `"`)
+	}
+	b.WriteString(">")
+	if len(code) > 0 {
+		b.WriteString(html.EscapeString(strings.Join(code, "\n")) + "\n")
+	}
+	b.WriteString("</code></pre>\n")
+	return i
+}
+
+// renderList renders consecutive items of the same list type starting at i.
+func renderList(b *strings.Builder, lines []string, i int) int {
+	ordered := !bulletItem.MatchString(lines[i])
+	var marker, tag, start string
+	if ordered {
+		m := orderedItem.FindStringSubmatch(lines[i])
+		marker, tag = m[3], "ol"
+		if n, _ := strconv.Atoi(m[2]); n != 1 {
+			start = strconv.Itoa(n)
+		}
+	} else {
+		marker, tag = bulletItem.FindStringSubmatch(lines[i])[2], "ul"
+	}
+
+	var items [][]string
+	loose := false
+	for i < len(lines) {
+		var width int
+		var first string
+		if ordered {
+			m := orderedItem.FindStringSubmatch(lines[i])
+			if m == nil || m[3] != marker {
+				break
+			}
+			width = len(m[0])
+			first = lines[i][len(m[0]):]
+		} else {
+			m := bulletItem.FindStringSubmatch(lines[i])
+			if m == nil || m[2] != marker || thematicBreak.MatchString(lines[i]) {
+				break
+			}
+			width = len(m[0])
+			first = lines[i][len(m[0]):]
+		}
+		if strings.TrimSpace(first) == "" || width-len(strings.TrimRight(lines[i][:width], " ")) > 4 {
+			width = len(strings.TrimRight(lines[i][:width], " ")) + 1
+			first = strings.TrimSpace(first)
+		}
+		item := []string{first}
+		i++
+		for i < len(lines) {
+			line := lines[i]
+			if isBlank(line) {
+				// A blank line continues the item only if indented content follows.
+				j := i
+				for j < len(lines) && isBlank(lines[j]) {
+					j++
+				}
+				if j < len(lines) && indentOf(lines[j]) >= width {
+					for ; i < j; i++ {
+						item = append(item, "")
+					}
+					loose = true
+					continue
+				}
+				break
+			}
+			if indentOf(line) >= width {
+				item = append(item, line[width:])
+			} else if !startsBlock(line) && !isBlank(item[len(item)-1]) {
+				item = append(item, strings.TrimLeft(line, " ")) // lazy continuation
+			} else {
+				break
+			}
+			i++
+		}
+		items = append(items, item)
+
+		// A blank line between items makes the whole list loose.
+		j := i
+		for j < len(lines) && isBlank(lines[j]) {
+			j++
+		}
+		if j > i && j < len(lines) && sameListMarker(lines[j], ordered, marker) {
+			loose = true
+			i = j
+		} else if j > i {
+			break
+		}
+	}
+
+	b.WriteString("<" + tag)
+	if start != "" {
+		b.WriteString(` start="` + start + `"`)
+	}
+	b.WriteString(">\n")
+	for _, item := range items {
+		b.WriteString("<li>")
+		var inner strings.Builder
+		renderBlocks(&inner, item, !loose)
+		b.WriteString(strings.TrimSuffix(inner.String(), "\n"))
+		b.WriteString("</li>\n")
+	}
+	b.WriteString("</" + tag + ">\n")
+	return i
+}
+
+func sameListMarker(line string, ordered bool, marker string) bool {
+	if ordered {
+		m := orderedItem.FindStringSubmatch(line)
+		return m != nil && m[3] == marker
+	}
+	m := bulletItem.FindStringSubmatch(line)
+	return m != nil && m[2] == marker && !thematicBreak.MatchString(line)
+}
+
+var (
+	autolinkPattern = regexp.MustCompile(`^<([a-zA-Z][a-zA-Z0-9+.-]{1,31}:[^<>\s]*|[^<>\s@]+@[^<>\s@]+\.[^<>\s@]+)>`)
+	rawTagPattern   = regexp.MustCompile(`^(?:<[a-zA-Z][a-zA-Z0-9-]*(?:\s+[a-zA-Z_:][a-zA-Z0-9_.:-]*(?:\s*=\s*(?:[^\s"'=<>` + "`" + `]+|'[^']*'|"[^"]*"))?)*\s*/?>|</[a-zA-Z][a-zA-Z0-9-]*\s*>|<!--[\s\S]*?-->)`)
+	entityPattern   = regexp.MustCompile(`^&(?:#[0-9]{1,7}|#[xX][0-9a-fA-F]{1,6}|[a-zA-Z][a-zA-Z0-9]{1,31});`)
+)
+
+const escapable = "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+
+// renderInline renders inline Markdown within one block.
+func renderInline(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && s[i+1] == '\n':
+			b.WriteString("<br>\n")
+			i += 2
+			continue
+		case c == '\\' && i+1 < len(s) && strings.IndexByte(escapable, s[i+1]) >= 0:
+			b.WriteString(html.EscapeString(s[i+1 : i+2]))
+			i += 2
+			continue
+		case c == '`':
+			if n, code, ok := codeSpan(s[i:]); ok {
+				b.WriteString("<code>" + html.EscapeString(code) + "</code>")
+				i += n
+				continue
+			}
+			run := len(s[i:]) - len(strings.TrimLeft(s[i:], "`"))
+			b.WriteString(s[i : i+run])
+			i += run
+			continue
+		case c == '!' && i+1 < len(s) && s[i+1] == '[':
+			if n, text, dest, title, ok := linkAt(s[i+1:]); ok {
+				b.WriteString(`<img src="` + html.EscapeString(dest) + `" alt="` + html.EscapeString(plainInline(text)) + `"`)
+				if title != "" {
+					b.WriteString(` title="` + html.EscapeString(title) + `"`)
+				}
+				b.WriteString(">")
+				i += 1 + n
+				continue
+			}
+		case c == '[':
+			if n, text, dest, title, ok := linkAt(s[i:]); ok {
+				b.WriteString(`<a href="` + html.EscapeString(dest) + `"`)
+				if title != "" {
+					b.WriteString(` title="` + html.EscapeString(title) + `"`)
+				}
+				b.WriteString(">" + renderInline(text) + "</a>")
+				i += n
+				continue
+			}
+		case c == '<':
+			if m := autolinkPattern.FindStringSubmatch(s[i:]); m != nil {
+				dest := m[1]
+				if !strings.Contains(dest, ":") {
+					dest = "mailto:" + dest
+				}
+				b.WriteString(`<a href="` + html.EscapeString(dest) + `">` + html.EscapeString(m[1]) + "</a>")
+				i += len(m[0])
+				continue
+			}
+			if m := rawTagPattern.FindString(s[i:]); m != "" {
+				b.WriteString(m)
+				i += len(m)
+				continue
+			}
+		case c == '&':
+			if m := entityPattern.FindString(s[i:]); m != "" {
+				b.WriteString(m)
+				i += len(m)
+				continue
+			}
+		case c == '\n':
+			if strings.HasSuffix(s[:i], "  ") {
+				b.WriteString("<br>\n")
+			} else {
+				b.WriteByte('\n')
+			}
+			i++
+			continue
+		case c == '*' || c == '_' || c == '~':
+			if n, out, ok := emphasisAt(s, i); ok {
+				b.WriteString(out)
+				i += n
+				continue
+			}
+		}
+		b.WriteString(html.EscapeString(s[i : i+1]))
+		i++
+	}
+	return strings.ReplaceAll(b.String(), "  <br>", "<br>")
+}
+
+// codeSpan matches a backtick run and its closing run of equal length.
+func codeSpan(s string) (int, string, bool) {
+	run := len(s) - len(strings.TrimLeft(s, "`"))
+	fence := s[:run]
+	for j := run; j < len(s); {
+		k := strings.Index(s[j:], fence)
+		if k < 0 {
+			return 0, "", false
+		}
+		end := j + k
+		after := end + run
+		if after < len(s) && s[after] == '`' {
+			j = after + len(s[after:]) - len(strings.TrimLeft(s[after:], "`"))
+			continue
+		}
+		code := strings.ReplaceAll(s[run:end], "\n", " ")
+		if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.TrimSpace(code) != "" {
+			code = code[1 : len(code)-1]
+		}
+		return after, code, true
+	}
+	return 0, "", false
+}
+
+// linkAt matches [text](dest "title") at the start of s.
+func linkAt(s string) (n int, text, dest, title string, ok bool) {
+	depth := 0
+	closeText := -1
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '`':
+			if m, _, ok := codeSpan(s[i:]); ok {
+				i += m - 1
+			}
+		case '[':
+			depth++
+		case ']':
+			depth--
+		}
+		if depth == 0 {
+			closeText = i
+			break
+		}
+	}
+	if closeText < 0 || closeText+1 >= len(s) || s[closeText+1] != '(' {
+		return 0, "", "", "", false
+	}
+	text = s[1:closeText]
+	rest := s[closeText+2:]
+	i := len(rest) - len(strings.TrimLeft(rest, " \n"))
+	if i < len(rest) && rest[i] == '<' {
+		end := strings.IndexAny(rest[i:], ">\n")
+		if end < 0 || rest[i+end] != '>' {
+			return 0, "", "", "", false
+		}
+		dest = rest[i+1 : i+end]
+		i += end + 1
+	} else {
+		start, parens := i, 0
+		for ; i < len(rest); i++ {
+			ch := rest[i]
+			if ch == '\\' && i+1 < len(rest) {
+				i++
+				continue
+			}
+			if ch == ' ' || ch == '\n' || ch < 0x20 {
+				break
+			}
+			if ch == '(' {
+				parens++
+			} else if ch == ')' {
+				if parens == 0 {
+					break
+				}
+				parens--
+			}
+		}
+		dest = unescapeMarkdown(rest[start:i])
+	}
+	i += len(rest[i:]) - len(strings.TrimLeft(rest[i:], " \n"))
+	if i < len(rest) && (rest[i] == '"' || rest[i] == '\'' || rest[i] == '(') {
+		closer := rest[i]
+		if closer == '(' {
+			closer = ')'
+		}
+		end := strings.IndexByte(rest[i+1:], closer)
+		if end < 0 {
+			return 0, "", "", "", false
+		}
+		title = unescapeMarkdown(rest[i+1 : i+1+end])
+		i += end + 2
+		i += len(rest[i:]) - len(strings.TrimLeft(rest[i:], " \n"))
+	}
+	if i >= len(rest) || rest[i] != ')' {
+		return 0, "", "", "", false
+	}
+	return closeText + 2 + i + 1, text, dest, title, true
+}
+
+func unescapeMarkdown(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && strings.IndexByte(escapable, s[i+1]) >= 0 {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return html.UnescapeString(b.String())
+}
+
+// plainInline is inline Markdown reduced to text, for image alt attributes.
+func plainInline(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\' && i+1 < len(s) && strings.IndexByte(escapable, s[i+1]) >= 0:
+			i++
+			b.WriteByte(s[i])
+		case strings.IndexByte("*_`~[]", c) >= 0:
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// emphasisAt matches **strong**, *em*, __strong__, _em_ or ~~del~~ starting
+// at s[i]. Delimiters must hug the enclosed text; intraword _ is literal.
+func emphasisAt(s string, i int) (int, string, bool) {
+	c := s[i]
+	run := 1
+	for i+run < len(s) && s[i+run] == c {
+		run++
+	}
+	type form struct {
+		width int
+		tag   string
+	}
+	var forms []form
+	switch {
+	case c == '~' && run == 2:
+		forms = []form{{2, "del"}}
+	case c == '~':
+		return 0, "", false
+	case run >= 3:
+		forms = []form{{3, "em><strong"}, {2, "strong"}, {1, "em"}}
+	case run == 2:
+		forms = []form{{2, "strong"}, {1, "em"}}
+	default:
+		forms = []form{{1, "em"}}
+	}
+	if c == '_' && i > 0 && isWordByte(s[i-1]) {
+		return 0, "", false
+	}
+	for _, f := range forms {
+		open := i + f.width
+		if open >= len(s) || s[open] == ' ' || s[open] == '\n' {
+			continue
+		}
+		delim := strings.Repeat(string(c), f.width)
+		for j := open + 1; j <= len(s)-f.width; j++ {
+			if s[j] == '\\' {
+				j++
+				continue
+			}
+			if s[j] == '`' {
+				if m, _, ok := codeSpan(s[j:]); ok {
+					j += m - 1
+					continue
+				}
+			}
+			if s[j:j+f.width] != delim || s[j-1] == ' ' || s[j-1] == '\n' {
+				continue
+			}
+			end := j + f.width
+			if end < len(s) && s[end] == c && f.width < 3 {
+				// Part of a longer run: let an outer form claim it.
+				if f.width == run {
+					continue
+				}
+			}
+			if c == '_' && end < len(s) && isWordByte(s[end]) {
+				continue
+			}
+			inner := renderInline(s[open:j])
+			if f.tag == "em><strong" {
+				return end - i, "<em><strong>" + inner + "</strong></em>", true
+			}
+			return end - i, "<" + f.tag + ">" + inner + "</" + f.tag + ">", true
+		}
+	}
+	return 0, "", false
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
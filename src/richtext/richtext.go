@@ -0,0 +1,237 @@
+// Package richtext is the server-side content pipeline for Pages and Posts:
+// Markdown or HTML in, allowlist-sanitized HTML, a plain-text excerpt, the
+// referenced media and save-time warnings out.
+//
+// Media is referenced as media:<public-id> in src/href, with a
+// data-media-id attribute, or by pasting a media library URL. The caller
+// resolves those references (see MediaResolver) so stored HTML only ever
+// carries real URLs.
+package richtext
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+type Format string
+
+const (
+	FormatMarkdown Format = "markdown"
+	FormatHTML     Format = "html"
+)
+
+// ParseFormat accepts "" (markdown), markdown/md and html.
+func ParseFormat(raw string) (Format, bool) {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "", "markdown", "md":
+		return FormatMarkdown, true
+	case "html":
+		return FormatHTML, true
+	}
+	return "", false
+}
+
+const (
+	// MaxInputBytes bounds a single document.
+	MaxInputBytes = 512 << 10
+	// ExcerptRunes is the plain-text excerpt length.
+	ExcerptRunes = 280
+	maxWarnings  = 50
+)
+
+var ErrTooLarge = errors.New("content exceeds 512 KiB")
+
+// Warning codes reported on save. Content is still stored; warnings tell the
+// editor what the sanitizer changed or what looks broken.
+const (
+	WarnRemovedElement   = "removed_element"
+	WarnRemovedAttribute = "removed_attribute"
+	WarnUnsafeURL        = "unsafe_url"
+	WarnInvalidURL       = "invalid_url"
+	WarnInsecureURL      = "insecure_url"
+	WarnRelativeURL      = "relative_url"
+	WarnImageMissingAlt  = "image_missing_alt"
+	WarnEmptyLink        = "empty_link"
+	WarnUnknownMedia     = "unknown_media"
+)
+
+type Warning struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Target  string `json:"target,omitempty"`
+}
+
+// MediaLookup is what a MediaResolver found: URLs by public id, and public ids
+// by URL for media pasted as plain links.
+type MediaLookup struct {
+	ByID  map[uuid.UUID]string
+	ByURL map[string]uuid.UUID
+}
+
+// MediaResolver looks up referenced ids and candidate media URLs in one call.
+type MediaResolver func(ids []uuid.UUID, urls []string) (MediaLookup, error)
+
+type Options struct {
+	Format  Format
+	Policy  Policy
+	Resolve MediaResolver
+}
+
+// Document is a rendered, sanitized document.
+type Document struct {
+	Format  Format
+	HTML    string
+	Text    string
+	Excerpt string
+	// MediaIDs are the resolved media, in order of first reference.
+	MediaIDs []uuid.UUID
+	Warnings []Warning
+}
+
+// Render runs the pipeline: Markdown → HTML (when needed), sanitize, bind
+// media references, extract text.
+func Render(input string, opts Options) (Document, error) {
+	if len(input) > MaxInputBytes {
+		return Document{}, ErrTooLarge
+	}
+	if !utf8.ValidString(input) {
+		input = strings.ToValidUTF8(input, "�")
+	}
+	format := opts.Format
+	if format == "" {
+		format = FormatMarkdown
+	}
+	source := input
+	if format == FormatMarkdown {
+		source = renderMarkdown(input)
+	}
+
+	s := newSanitizer(opts.Policy)
+	root, err := s.sanitize(source)
+	if err != nil {
+		return Document{}, err
+	}
+	ids, err := s.bindMedia(root, opts.Resolve)
+	if err != nil {
+		return Document{}, err
+	}
+	s.checkLinks(root)
+
+	html, err := renderChildren(root)
+	if err != nil {
+		return Document{}, err
+	}
+	text := extractText(root)
+	return Document{
+		Format:   format,
+		HTML:     html,
+		Text:     text,
+		Excerpt:  excerpt(text, ExcerptRunes),
+		MediaIDs: ids,
+		Warnings: s.warnings,
+	}, nil
+}
+
+// excerpt cuts text to n runes at a word boundary.
+func excerpt(text string, n int) string {
+	flat := strings.Join(strings.Fields(text), " ")
+	runes := []rune(flat)
+	if len(runes) <= n {
+		return flat
+	}
+	cut := string(runes[:n])
+	if i := strings.LastIndexByte(cut, ' '); i > n/2 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,.;:-") + "…"
+}
+
+// Policy is the sanitizer allowlist.
+type Policy struct {
+	// Elements maps an allowed tag to the attributes it may carry in addition
+	// to GlobalAttributes. Unlisted tags are unwrapped (children kept).
+	Elements         map[string][]string
+	GlobalAttributes []string
+	// URLSchemes allowed in href/src/cite/poster.
+	URLSchemes []string
+	// EmbedHosts enables <iframe> for https URLs on these hosts (and their
+	// subdomains). Empty means no iframes.
+	EmbedHosts []string
+}
+
+// DefaultPolicy allows typical article markup, https/http/mailto/tel URLs
+// and no embeds.
+func DefaultPolicy() Policy {
+	return Policy{
+		Elements: map[string][]string{
+			"p": nil, "br": nil, "hr": nil, "span": nil, "div": nil,
+			"h1": nil, "h2": nil, "h3": nil, "h4": nil, "h5": nil, "h6": nil,
+			"strong": nil, "b": nil, "em": nil, "i": nil, "u": nil, "s": nil, "del": nil, "ins": nil,
+			"sub": nil, "sup": nil, "mark": nil, "small": nil, "abbr": nil,
+			"blockquote": {"cite"}, "q": {"cite"}, "cite": nil,
+			"code": {"class"}, "pre": {"class"}, "kbd": nil,
+			"ul": nil, "ol": {"start", "reversed"}, "li": nil, "dl": nil, "dt": nil, "dd": nil,
+			"a":          {"href", "target", "data-media-id"},
+			"img":        {"src", "alt", "width", "height", "loading", "data-media-id"},
+			"figure":     nil,
+			"figcaption": nil,
+			"video":      {"src", "poster", "controls", "width", "height", "data-media-id"},
+			"audio":      {"src", "controls", "data-media-id"},
+			"source":     {"src", "type", "data-media-id"},
+			"table":      nil, "thead": nil, "tbody": nil, "tfoot": nil, "tr": nil,
+			"th": {"colspan", "rowspan", "scope"}, "td": {"colspan", "rowspan"},
+		},
+		GlobalAttributes: []string{"title", "lang", "dir"},
+		URLSchemes:       []string{"https", "http", "mailto", "tel"},
+	}
+}
+
+// PolicyFromEnv is DefaultPolicy adjusted by:
+//
+//	CONTENT_ALLOWED_TAGS         replaces the tag list: "p,a:href|title,img:src|alt"
+//	CONTENT_ALLOWED_URL_SCHEMES  e.g. "https,mailto"
+//	CONTENT_EMBED_HOSTS          e.g. "www.youtube.com,player.vimeo.com"
+func PolicyFromEnv() Policy {
+	p := DefaultPolicy()
+	if raw := strings.TrimSpace(os.Getenv("CONTENT_ALLOWED_TAGS")); raw != "" {
+		p.Elements = map[string][]string{}
+		for _, entry := range splitList(raw) {
+			tag, attrs, _ := strings.Cut(entry, ":")
+			tag = strings.ToLower(strings.TrimSpace(tag))
+			if tag == "" {
+				continue
+			}
+			var list []string
+			for _, a := range strings.Split(attrs, "|") {
+				if a = strings.ToLower(strings.TrimSpace(a)); a != "" {
+					list = append(list, a)
+				}
+			}
+			p.Elements[tag] = list
+		}
+	}
+	if raw := strings.TrimSpace(os.Getenv("CONTENT_ALLOWED_URL_SCHEMES")); raw != "" {
+		p.URLSchemes = nil
+		for _, s := range splitList(raw) {
+			p.URLSchemes = append(p.URLSchemes, strings.ToLower(s))
+		}
+	}
+	for _, h := range splitList(os.Getenv("CONTENT_EMBED_HOSTS")) {
+		p.EmbedHosts = append(p.EmbedHosts, strings.ToLower(h))
+	}
+	return p
+}
+
+func splitList(raw string) []string {
+	var out []string
+	for _, v := range strings.Split(raw, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
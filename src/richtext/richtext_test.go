@@ -0,0 +1,175 @@
+package richtext
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func warningCodes(doc Document) []string {
+	var codes []string
+	for _, w := range doc.Warnings {
+		codes = append(codes, w.Code)
+	}
+	return codes
+}
+
+func hasWarning(doc Document, code string) bool {
+	for _, w := range doc.Warnings {
+		if w.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+func TestRenderMarkdownBlocksAndInline(t *testing.T) {
+	src := "# Title\n\nHello **bold**, *em*, `a<b` and [docs](https://example.com \"Docs\").\n\n" +
+		"- one\n- two\n  - nested\n\n3. three\n4. four\n\n> quoted\n\n```go\nx := \"<y>\"\n```\n\nline  \nbreak ~~old~~ snake_case"
+	doc, err := Render(src, Options{Format: FormatMarkdown})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"<h1>Title</h1>",
+		"<strong>bold</strong>",
+		"<em>em</em>",
+		"<code>a&lt;b</code>",
+		`<a href="https://example.com" title="Docs" rel="noopener noreferrer nofollow">docs</a>`,
+		"<ul>\n<li>one</li>\n<li>two\n<ul>\n<li>nested</li>\n</ul></li>\n</ul>",
+		`<ol start="3">`,
+		"<blockquote>\n<p>quoted</p>\n</blockquote>",
+		`<pre><code class="language-go">x := &#34;&lt;y&gt;&#34;`,
+		"line<br/>\nbreak <del>old</del> snake_case",
+	} {
+		if !strings.Contains(doc.HTML, want) {
+			t.Errorf("html missing %q:\n%s", want, doc.HTML)
+		}
+	}
+	if len(doc.Warnings) != 0 {
+		t.Fatalf("unexpected warnings %v", doc.Warnings)
+	}
+}
+
+func TestRenderStripsScriptsAndUnsafeURLs(t *testing.T) {
+	src := `<p onclick="steal()">Hi <a href="javascript:alert(1)">x</a> <a href=" JaVaScRiPt:alert(1)">y</a></p>` +
+		`<script>alert(1)</script><style>p{}</style><img src="data:image/png;base64,AA" alt="d">` +
+		`<iframe src="https://evil.example/"></iframe><svg><script>1</script></svg>` +
+		`<font color="red">kept text</font><p style="color:red">styled</p>`
+	doc, err := Render(src, Options{Format: FormatHTML})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, banned := range []string{"script", "onclick", "javascript", "style=", "<style", "data:", "iframe", "svg", "font"} {
+		if strings.Contains(strings.ToLower(doc.HTML), banned) {
+			t.Errorf("html still contains %q:\n%s", banned, doc.HTML)
+		}
+	}
+	if !strings.Contains(doc.HTML, "kept text") || !strings.Contains(doc.HTML, "<p>styled</p>") {
+		t.Fatalf("unwrapped text lost:\n%s", doc.HTML)
+	}
+	for _, code := range []string{WarnRemovedElement, WarnRemovedAttribute, WarnUnsafeURL} {
+		if !hasWarning(doc, code) {
+			t.Errorf("missing %s warning in %v", code, warningCodes(doc))
+		}
+	}
+
+	// Raw HTML inside Markdown goes through the same sanitizer.
+	doc, _ = Render("hello <img src=x onerror=alert(1)> [a](javascript:alert(1))", Options{})
+	if strings.Contains(doc.HTML, "onerror") || strings.Contains(doc.HTML, "javascript") {
+		t.Fatalf("markdown html not sanitized:\n%s", doc.HTML)
+	}
+}
+
+func TestRenderEmbedsOnlyAllowedHosts(t *testing.T) {
+	policy := DefaultPolicy()
+	policy.EmbedHosts = []string{"youtube.com"}
+	doc, err := Render(`<iframe src="https://www.youtube.com/embed/x" onload="x()"></iframe><iframe src="http://youtube.com/embed/y"></iframe>`,
+		Options{Format: FormatHTML, Policy: policy})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(doc.HTML, "<iframe") != 1 || !strings.Contains(doc.HTML, `sandbox="`) || strings.Contains(doc.HTML, "onload") {
+		t.Fatalf("embed html = %s", doc.HTML)
+	}
+}
+
+func TestRenderBindsMediaReferences(t *testing.T) {
+	known := uuid.MustParse("6f1f9c9e-8c7d-4a5e-9b2f-1a2b3c4d5e6f")
+	pasted := uuid.MustParse("0b6a2f0e-4b1c-4d1e-8f00-123456789abc")
+	missing := uuid.MustParse("11111111-2222-4333-8444-555555555555")
+	var gotIDs []uuid.UUID
+	var gotURLs []string
+	resolve := func(ids []uuid.UUID, urls []string) (MediaLookup, error) {
+		gotIDs, gotURLs = ids, urls
+		return MediaLookup{
+			ByID:  map[uuid.UUID]string{known: "https://cdn.example/a.jpg"},
+			ByURL: map[string]uuid.UUID{"https://cdn.example/b.mp4": pasted},
+		}, nil
+	}
+	src := "![Cover](media:" + known.String() + ")\n\n" +
+		`<video src="https://cdn.example/b.mp4" controls></video>` + "\n\n" +
+		"![Gone](media:" + missing.String() + ")\n\n" +
+		"[download](media:" + known.String() + ")"
+	doc, err := Render(src, Options{Resolve: resolve})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(gotIDs, []uuid.UUID{known, missing}) || !reflect.DeepEqual(gotURLs, []string{"https://cdn.example/b.mp4"}) {
+		t.Fatalf("resolver called with ids=%v urls=%v", gotIDs, gotURLs)
+	}
+	if !reflect.DeepEqual(doc.MediaIDs, []uuid.UUID{known, pasted}) {
+		t.Fatalf("media ids = %v", doc.MediaIDs)
+	}
+	if strings.Contains(doc.HTML, "media:") || strings.Contains(doc.HTML, "Gone") {
+		t.Fatalf("unresolved reference left in html:\n%s", doc.HTML)
+	}
+	if !strings.Contains(doc.HTML, `<img src="https://cdn.example/a.jpg" alt="Cover"/>`) {
+		t.Fatalf("media not bound:\n%s", doc.HTML)
+	}
+	if !hasWarning(doc, WarnUnknownMedia) {
+		t.Fatalf("warnings = %v", warningCodes(doc))
+	}
+}
+
+func TestRenderLinkWarningsAndExcerpt(t *testing.T) {
+	doc, err := Render("![](https://cdn.example/x.png) [](https://example.com) [plain](http://example.com) [rel](/about)", Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{WarnImageMissingAlt, WarnEmptyLink, WarnInsecureURL, WarnRelativeURL}
+	for _, code := range want {
+		if !hasWarning(doc, code) {
+			t.Errorf("missing %s in %v", code, warningCodes(doc))
+		}
+	}
+
+	long := strings.Repeat("word ", 100)
+	doc, _ = Render("# Heading\n\n"+long, Options{})
+	if n := len([]rune(doc.Excerpt)); n > ExcerptRunes+1 || !strings.HasSuffix(doc.Excerpt, "…") || !strings.HasPrefix(doc.Excerpt, "Heading word") {
+		t.Fatalf("excerpt (%d runes) = %q", n, doc.Excerpt)
+	}
+
+	if _, err := Render(strings.Repeat("a", MaxInputBytes+1), Options{}); err != ErrTooLarge {
+		t.Fatalf("oversized input err = %v", err)
+	}
+}
+
+func TestPolicyFromEnv(t *testing.T) {
+	t.Setenv("CONTENT_ALLOWED_TAGS", "p, a:href|title")
+	t.Setenv("CONTENT_ALLOWED_URL_SCHEMES", "https")
+	t.Setenv("CONTENT_EMBED_HOSTS", "player.vimeo.com")
+	p := PolicyFromEnv()
+	if len(p.Elements) != 2 || !reflect.DeepEqual(p.Elements["a"], []string{"href", "title"}) {
+		t.Fatalf("elements = %v", p.Elements)
+	}
+	doc, _ := Render(`<h1>x</h1><p><a href="mailto:a@b.c">m</a></p>`, Options{Format: FormatHTML, Policy: p})
+	if doc.HTML != `x<p><a>m</a></p>` {
+		t.Fatalf("html = %s", doc.HTML)
+	}
+	if !reflect.DeepEqual(p.EmbedHosts, []string{"player.vimeo.com"}) {
+		t.Fatalf("embed hosts = %v", p.EmbedHosts)
+	}
+}
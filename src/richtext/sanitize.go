@@ -0,0 +1,480 @@
+package richtext
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// dropWithContent are removed together with everything inside them.
+var dropWithContent = map[string]bool{
+	"script": true, "style": true, "iframe": true, "object": true, "embed": true,
+	"noscript": true, "template": true, "textarea": true, "select": true, "button": true,
+	"form": true, "input": true, "frame": true, "frameset": true, "applet": true,
+	"base": true, "link": true, "meta": true, "title": true, "head": true, "svg": true, "math": true,
+}
+
+var urlAttributes = map[string]bool{"href": true, "src": true, "cite": true, "poster": true}
+
+// mediaElements may reference a media item by src or data-media-id.
+var mediaElements = map[string]bool{"img": true, "video": true, "audio": true, "source": true}
+
+var codeClassPattern = regexp.MustCompile(`^language-[a-z0-9+#-]{1,32}$`)
+
+const mediaScheme = "media:"
+
+type sanitizer struct {
+	policy   Policy
+	allowed  map[string]map[string]bool
+	global   map[string]bool
+	schemes  map[string]bool
+	warnings []Warning
+	seen     map[string]bool
+}
+
+func newSanitizer(p Policy) *sanitizer {
+	if p.Elements == nil {
+		p = DefaultPolicy()
+	}
+	s := &sanitizer{
+		policy:  p,
+		allowed: map[string]map[string]bool{},
+		global:  map[string]bool{},
+		schemes: map[string]bool{},
+		seen:    map[string]bool{},
+	}
+	for tag, attrs := range p.Elements {
+		set := map[string]bool{}
+		for _, a := range attrs {
+			set[a] = true
+		}
+		s.allowed[tag] = set
+	}
+	for _, a := range p.GlobalAttributes {
+		s.global[a] = true
+	}
+	for _, scheme := range p.URLSchemes {
+		s.schemes[scheme] = true
+	}
+	return s
+}
+
+func (s *sanitizer) warn(code, target, format string, args ...interface{}) {
+	key := code + "\x00" + target
+	if s.seen[key] || len(s.warnings) >= maxWarnings {
+		return
+	}
+	s.seen[key] = true
+	s.warnings = append(s.warnings, Warning{Code: code, Message: fmt.Sprintf(format, args...), Target: target})
+}
+
+// sanitize parses src as a body fragment and cleans it in place; the returned
+// node is a synthetic container whose children are the document.
+func (s *sanitizer) sanitize(src string) (*html.Node, error) {
+	container := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	nodes, err := html.ParseFragment(strings.NewReader(src), container)
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range nodes {
+		container.AppendChild(n)
+	}
+	s.clean(container)
+	return container, nil
+}
+
+func (s *sanitizer) clean(parent *html.Node) {
+	for c := parent.FirstChild; c != nil; {
+		next := c.NextSibling
+		switch c.Type {
+		case html.TextNode:
+		case html.ElementNode:
+			s.cleanElement(parent, c)
+		default:
+			parent.RemoveChild(c)
+		}
+		c = next
+	}
+}
+
+func (s *sanitizer) cleanElement(parent, n *html.Node) {
+	tag := n.Data
+	if tag == "iframe" && s.keepEmbed(n) {
+		return
+	}
+	if dropWithContent[tag] {
+		s.warn(WarnRemovedElement, tag, "Removed <%s> element", tag)
+		parent.RemoveChild(n)
+		return
+	}
+	attrs, ok := s.allowed[tag]
+	if !ok {
+		// Unknown wrappers (font, center, section…) keep their text.
+		s.clean(n)
+		for gc := n.FirstChild; gc != nil; gc = n.FirstChild {
+			n.RemoveChild(gc)
+			parent.InsertBefore(gc, n)
+		}
+		parent.RemoveChild(n)
+		return
+	}
+	s.cleanAttributes(n, attrs)
+	if tag == "img" && attr(n, "src") == "" && attr(n, "data-media-id") == "" {
+		s.warn(WarnRemovedElement, tag, "Removed <%s> without a source", tag)
+		parent.RemoveChild(n)
+		return
+	}
+	if tag == "a" && attr(n, "href") != "" {
+		setAttr(n, "rel", "noopener noreferrer nofollow")
+	}
+	s.clean(n)
+}
+
+func (s *sanitizer) cleanAttributes(n *html.Node, allowed map[string]bool) {
+	kept := n.Attr[:0]
+	for _, a := range n.Attr {
+		key := strings.ToLower(a.Key)
+		if a.Namespace != "" {
+			continue
+		}
+		if strings.HasPrefix(key, "on") || key == "style" || key == "srcdoc" || key == "formaction" {
+			s.warn(WarnRemovedAttribute, key, "Removed %s attribute from <%s>", key, n.Data)
+			continue
+		}
+		if !allowed[key] && !s.global[key] {
+			continue
+		}
+		val := a.Val
+		switch {
+		case urlAttributes[key]:
+			v, ok := s.checkURL(n.Data, key, val)
+			if !ok {
+				continue
+			}
+			val = v
+		case key == "data-media-id":
+			id, err := uuid.Parse(strings.TrimSpace(val))
+			if err != nil {
+				s.warn(WarnUnknownMedia, val, "Ignored invalid media id %q", val)
+				continue
+			}
+			val = id.String()
+		case key == "class":
+			var classes []string
+			for _, cls := range strings.Fields(val) {
+				if codeClassPattern.MatchString(cls) {
+					classes = append(classes, cls)
+				}
+			}
+			if len(classes) == 0 {
+				continue
+			}
+			val = strings.Join(classes, " ")
+		case key == "target":
+			if val != "_blank" {
+				continue
+			}
+		}
+		kept = append(kept, html.Attribute{Key: key, Val: val})
+	}
+	n.Attr = kept
+}
+
+// checkURL validates a URL attribute, returning the value to keep.
+func (s *sanitizer) checkURL(tag, key, raw string) (string, bool) {
+	v := strings.TrimSpace(raw)
+	if v == "" {
+		return "", false
+	}
+	if strings.HasPrefix(strings.ToLower(v), mediaScheme) {
+		id, err := uuid.Parse(strings.TrimPrefix(v[len(mediaScheme):], "//"))
+		if err != nil {
+			s.warn(WarnUnknownMedia, v, "Ignored invalid media reference %q", v)
+			return "", false
+		}
+		return mediaScheme + id.String(), true
+	}
+	if strings.HasPrefix(v, "#") && key == "href" {
+		return v, true
+	}
+	u, err := url.Parse(v)
+	if err != nil {
+		s.warn(WarnInvalidURL, v, "Removed malformed URL in <%s %s>", tag, key)
+		return "", false
+	}
+	scheme := strings.ToLower(u.Scheme)
+	switch {
+	case scheme == "" && strings.HasPrefix(v, "//"):
+		s.warn(WarnRelativeURL, v, "Protocol-relative URL in <%s %s>; prefer https://", tag, key)
+		return v, true
+	case scheme == "":
+		s.warn(WarnRelativeURL, v, "Relative URL in <%s %s> may not resolve in every client", tag, key)
+		return v, true
+	case !s.schemes[scheme]:
+		s.warn(WarnUnsafeURL, v, "Removed %s: URL from <%s %s>", scheme, tag, key)
+		return "", false
+	case (scheme == "http" || scheme == "https") && u.Host == "":
+		s.warn(WarnInvalidURL, v, "Removed URL without a host in <%s %s>", tag, key)
+		return "", false
+	case scheme == "http":
+		s.warn(WarnInsecureURL, v, "Insecure http:// URL in <%s %s>", tag, key)
+	}
+	return v, true
+}
+
+// keepEmbed admits an iframe whose https src is on an allowed embed host and
+// locks it down with sandbox.
+func (s *sanitizer) keepEmbed(n *html.Node) bool {
+	if len(s.policy.EmbedHosts) == 0 {
+		return false
+	}
+	u, err := url.Parse(strings.TrimSpace(attr(n, "src")))
+	if err != nil || u.Scheme != "https" || !s.embedHost(u.Hostname()) {
+		return false
+	}
+	src := u.String()
+	keep := []html.Attribute{{Key: "src", Val: src}}
+	for _, key := range []string{"width", "height", "title", "allowfullscreen"} {
+		if v, ok := lookupAttr(n, key); ok {
+			keep = append(keep, html.Attribute{Key: key, Val: v})
+		}
+	}
+	keep = append(keep,
+		html.Attribute{Key: "sandbox", Val: "allow-scripts allow-same-origin allow-presentation"},
+		html.Attribute{Key: "loading", Val: "lazy"},
+		html.Attribute{Key: "referrerpolicy", Val: "strict-origin-when-cross-origin"},
+	)
+	n.Attr = keep
+	for c := n.FirstChild; c != nil; c = n.FirstChild {
+		n.RemoveChild(c)
+	}
+	return true
+}
+
+func (s *sanitizer) embedHost(host string) bool {
+	host = strings.ToLower(host)
+	for _, allowed := range s.policy.EmbedHosts {
+		if host == allowed || strings.HasSuffix(host, "."+allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// bindMedia resolves media references: media:<id> URLs become real URLs,
+// pasted media URLs are recognised, and unknown references are dropped.
+func (s *sanitizer) bindMedia(root *html.Node, resolve MediaResolver) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	var urls []string
+	seenID := map[uuid.UUID]bool{}
+	seenURL := map[string]bool{}
+	walk(root, func(n *html.Node) {
+		for _, a := range n.Attr {
+			switch {
+			case a.Key == "data-media-id", (a.Key == "src" || a.Key == "href") && strings.HasPrefix(a.Val, mediaScheme):
+				id, _ := uuid.Parse(strings.TrimPrefix(a.Val, mediaScheme))
+				if !seenID[id] {
+					seenID[id] = true
+					ids = append(ids, id)
+				}
+			case a.Key == "src" && mediaElements[n.Data]:
+				if !seenURL[a.Val] {
+					seenURL[a.Val] = true
+					urls = append(urls, a.Val)
+				}
+			}
+		}
+	})
+	if len(ids) == 0 && len(urls) == 0 {
+		return nil, nil
+	}
+
+	var lookup MediaLookup
+	if resolve != nil {
+		var err error
+		if lookup, err = resolve(ids, urls); err != nil {
+			return nil, err
+		}
+	}
+
+	var resolved []uuid.UUID
+	added := map[uuid.UUID]bool{}
+	use := func(id uuid.UUID) {
+		if !added[id] {
+			added[id] = true
+			resolved = append(resolved, id)
+		}
+	}
+	var drop []*html.Node
+	walk(root, func(n *html.Node) {
+		hasSrc := attr(n, "src") != ""
+		kept := n.Attr[:0]
+		fillSrc := ""
+		for _, a := range n.Attr {
+			switch {
+			case a.Key == "data-media-id":
+				id, _ := uuid.Parse(a.Val)
+				u, ok := lookup.ByID[id]
+				if !ok {
+					s.warn(WarnUnknownMedia, a.Val, "Media %s does not exist", a.Val)
+					if n.Data == "img" && !hasSrc {
+						drop = append(drop, n)
+					}
+					continue
+				}
+				use(id)
+				if mediaElements[n.Data] && !hasSrc {
+					fillSrc = u
+				}
+			case (a.Key == "src" || a.Key == "href") && strings.HasPrefix(a.Val, mediaScheme):
+				id, _ := uuid.Parse(strings.TrimPrefix(a.Val, mediaScheme))
+				u, ok := lookup.ByID[id]
+				if !ok {
+					s.warn(WarnUnknownMedia, id.String(), "Media %s does not exist", id)
+					if mediaElements[n.Data] && a.Key == "src" {
+						drop = append(drop, n)
+					}
+					continue
+				}
+				use(id)
+				a.Val = u
+			case a.Key == "src" && mediaElements[n.Data]:
+				if id, ok := lookup.ByURL[a.Val]; ok {
+					use(id)
+				}
+			}
+			kept = append(kept, a)
+		}
+		n.Attr = kept
+		if fillSrc != "" {
+			setAttr(n, "src", fillSrc)
+		}
+	})
+	for _, n := range drop {
+		if parent := n.Parent; parent != nil {
+			parent.RemoveChild(n)
+			if parent.Data == "p" && parent.FirstChild == nil && parent.Parent != nil {
+				parent.Parent.RemoveChild(parent)
+			}
+		}
+	}
+	return resolved, nil
+}
+
+// checkLinks reports images without alt text and links without text.
+func (s *sanitizer) checkLinks(root *html.Node) {
+	walk(root, func(n *html.Node) {
+		switch n.Data {
+		case "img":
+			if strings.TrimSpace(attr(n, "alt")) == "" {
+				s.warn(WarnImageMissingAlt, attr(n, "src"), "Image has no alt text")
+			}
+		case "a":
+			href := attr(n, "href")
+			if href != "" && strings.TrimSpace(extractText(n)) == "" && !hasElement(n, "img") {
+				s.warn(WarnEmptyLink, href, "Link has no text")
+			}
+		}
+	})
+}
+
+func walk(n *html.Node, fn func(*html.Node)) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode {
+			fn(c)
+			walk(c, fn)
+		}
+	}
+}
+
+func hasElement(n *html.Node, tag string) bool {
+	found := false
+	walk(n, func(c *html.Node) {
+		if c.Data == tag {
+			found = true
+		}
+	})
+	return found
+}
+
+func attr(n *html.Node, key string) string {
+	v, _ := lookupAttr(n, key)
+	return v
+}
+
+func lookupAttr(n *html.Node, key string) (string, bool) {
+	for _, a := range n.Attr {
+		if strings.EqualFold(a.Key, key) {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
+
+func setAttr(n *html.Node, key, val string) {
+	for i, a := range n.Attr {
+		if a.Key == key {
+			n.Attr[i].Val = val
+			return
+		}
+	}
+	n.Attr = append(n.Attr, html.Attribute{Key: key, Val: val})
+}
+
+func renderChildren(root *html.Node) (string, error) {
+	var b strings.Builder
+	for c := root.FirstChild; c != nil; c = c.NextSibling {
+		if err := html.Render(&b, c); err != nil {
+			return "", err
+		}
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+var blockElements = map[string]bool{
+	"p": true, "div": true, "br": true, "hr": true, "li": true, "blockquote": true, "pre": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"ul": true, "ol": true, "dl": true, "dt": true, "dd": true, "tr": true, "table": true,
+	"figure": true, "figcaption": true,
+}
+
+// extractText flattens the tree to plain text, one line per block.
+func extractText(root *html.Node) string {
+	var b strings.Builder
+	var visit func(*html.Node)
+	visit = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			switch c.Type {
+			case html.TextNode:
+				b.WriteString(c.Data)
+			case html.ElementNode:
+				block := blockElements[c.Data]
+				if block {
+					b.WriteByte('\n')
+				}
+				if c.Data == "img" {
+					b.WriteString(attr(c, "alt"))
+				}
+				visit(c)
+				if block {
+					b.WriteByte('\n')
+				} else if c.Data == "td" || c.Data == "th" {
+					b.WriteByte(' ')
+				}
+			}
+		}
+	}
+	visit(root)
+	var lines []string
+	for _, line := range strings.Split(b.String(), "\n") {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
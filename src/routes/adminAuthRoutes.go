@@ -19,6 +19,7 @@ func SetupAdminAuthRoutes(router *gin.Engine, db *gorm.DB) {
 
 	adminGroup := router.Group("/admin")
 	adminGroup.Use(utils.AdminAuthMiddleware(db))
+	adminGroup.Use(utils.RateLimitMiddleware("admin.writes"))
 	SetupOperatorRoutes(adminGroup, db)
 	// /me only needs a valid principal (any authenticated user may read their own access).
 	adminGroup.GET("/me", controllers.AdminMe)
@@ -41,6 +42,12 @@ func SetupAdminAuthRoutes(router *gin.Engine, db *gorm.DB) {
 	adminGroup.POST("/ops/commands/pause-all", utils.RequireAdminRole("admin"), controllers.PauseOpsFleet)
 	adminGroup.POST("/ops/commands/resume", utils.RequireAdminRole("admin"), controllers.ResumeOpsCommand)
 
+	// Browser origins admitted per CORS route group for this tenant. Admin only:
+	// an admitted admin origin can drive the console API from a browser.
+	adminGroup.GET("/cors/origins", utils.RequireAdminRole("admin"), controllers.ListTenantCORSOrigins)
+	adminGroup.POST("/cors/origins", utils.RequireAdminRole("admin"), controllers.CreateTenantCORSOrigin)
+	adminGroup.DELETE("/cors/origins/:id", utils.RequireAdminRole("admin"), controllers.DeleteTenantCORSOrigin)
+
 	adminGroup.GET("/sources", perm("source", "read"), controllers.ListContentSources)
 	adminGroup.POST("/sources", perm("source", "write"), controllers.CreateContentSource)
 	adminGroup.POST("/sources/bulk", perm("source", "write"), controllers.BulkCreateContentSources)
@@ -86,6 +93,28 @@ func SetupAdminAuthRoutes(router *gin.Engine, db *gorm.DB) {
 	adminGroup.GET("/content/stats", perm("content", "read"), controllers.GetContentStats)
 	adminGroup.GET("/content/stories", perm("content", "read"), controllers.ListContentTopics)
 	adminGroup.GET("/content/:id", perm("content", "read"), controllers.GetAdminContentItem)
+	adminGroup.GET("/search", perm("content", "read"), controllers.AdminSearchContent)
+
+	// Editorial pages/posts: drafts, revisions and publishing. Create, update
+	// and delete stay on /pages and /posts.
+	adminGroup.GET("/pages", perm("content", "read"), controllers.ListAdminPages)
+	adminGroup.GET("/pages/:id", perm("content", "read"), controllers.GetAdminPage)
+	adminGroup.GET("/pages/:id/revisions", perm("content", "read"), controllers.ListPageRevisions)
+	adminGroup.GET("/pages/:id/revisions/:revision", perm("content", "read"), controllers.GetPageRevision)
+	adminGroup.GET("/pages/:id/diff", perm("content", "read"), controllers.DiffPageRevisions)
+	adminGroup.POST("/pages/:id/revisions/:revision/restore", perm("content", "write"), controllers.RestorePageRevision)
+	adminGroup.POST("/pages/:id/publish", perm("content", "write"), controllers.PublishPage)
+	adminGroup.POST("/pages/:id/unpublish", perm("content", "write"), controllers.UnpublishPage)
+	adminGroup.POST("/pages/:id/archive", perm("content", "write"), controllers.ArchivePage)
+	adminGroup.GET("/posts", perm("content", "read"), controllers.ListAdminPosts)
+	adminGroup.GET("/posts/:id", perm("content", "read"), controllers.GetAdminPost)
+	adminGroup.GET("/posts/:id/revisions", perm("content", "read"), controllers.ListPostRevisions)
+	adminGroup.GET("/posts/:id/revisions/:revision", perm("content", "read"), controllers.GetPostRevision)
+	adminGroup.GET("/posts/:id/diff", perm("content", "read"), controllers.DiffPostRevisions)
+	adminGroup.POST("/posts/:id/revisions/:revision/restore", perm("content", "write"), controllers.RestorePostRevision)
+	adminGroup.POST("/posts/:id/publish", perm("content", "write"), controllers.PublishPost)
+	adminGroup.POST("/posts/:id/unpublish", perm("content", "write"), controllers.UnpublishPost)
+	adminGroup.POST("/posts/:id/archive", perm("content", "write"), controllers.ArchivePost)
 	adminGroup.PATCH("/content/:id/status", perm("content", "write"), controllers.UpdateContentStatus)
 	adminGroup.PATCH("/content/:id/suitability", perm("content", "write"), controllers.UpdateContentSuitability)
 	adminGroup.POST("/content/bulk-delete", perm("content", "delete"), controllers.BulkDeleteContent)
@@ -98,7 +127,10 @@ func SetupAdminAuthRoutes(router *gin.Engine, db *gorm.DB) {
 	adminGroup.GET("/moderation/reports", perm("content", "read"), controllers.AdminListModerationReports)
 	adminGroup.POST("/moderation/reports/:id/resolve", perm("content", "write"), controllers.AdminResolveModerationReport)
 	adminGroup.GET("/moderation/comments/review", perm("content", "read"), controllers.AdminListCommentReviews)
+	adminGroup.GET("/moderation/comments/classifier", perm("content", "read"), controllers.AdminGetCommentClassifier)
+	adminGroup.PUT("/moderation/comments/classifier", perm("content", "write"), controllers.AdminUpdateCommentClassifier)
 	adminGroup.POST("/moderation/comments/:id/review", perm("content", "write"), controllers.AdminResolveCommentReview)
+	adminGroup.GET("/moderation/comments/:id/history", perm("content", "read"), controllers.AdminGetCommentHistory)
 	adminGroup.DELETE("/moderation/comments/:id", perm("content", "write"), controllers.AdminRemoveComment)
 
 	// First-class topics (LLM-labeled) management
@@ -150,6 +182,9 @@ func SetupAdminAuthRoutes(router *gin.Engine, db *gorm.DB) {
 
 	// Saved syndication feeds (RSS/Atom/JSON output)
 	adminGroup.GET("/feeds", perm("feed", "read"), controllers.ListRSSFeeds)
+	adminGroup.GET("/feeds/subscriptions", perm("feed", "read"), controllers.ListWebSubSubscriptions)
+	adminGroup.DELETE("/feeds/subscriptions/:id", perm("feed", "manage"), controllers.DeleteWebSubSubscription)
+	adminGroup.GET("/feeds/:id/subscriptions", perm("feed", "read"), controllers.ListWebSubSubscriptions)
 	adminGroup.POST("/feeds", perm("feed", "manage"), controllers.CreateRSSFeed)
 	adminGroup.PUT("/feeds/:id", perm("feed", "manage"), controllers.UpdateRSSFeed)
 	adminGroup.DELETE("/feeds/:id", perm("feed", "manage"), controllers.DeleteRSSFeed)
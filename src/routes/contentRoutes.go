@@ -2,6 +2,7 @@ package routes
 
 import (
 	"content-management-system/src/controllers"
+	"content-management-system/src/utils"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -12,19 +13,19 @@ func SetupContentRoutes(group *gin.RouterGroup, db *gorm.DB) {
 	// User-submitted content (JWT-authenticated). Registered BEFORE the
 	// /content/:id catch-all so Gin matches the literal segments first.
 	group.GET("/content/mine", controllers.UserAuthMiddleware(), controllers.GetMyContent)
-	group.POST("/content/submit", controllers.UserAuthMiddleware(), controllers.SubmitUserContent)
+	group.POST("/content/submit", controllers.UserAuthMiddleware(), utils.RateLimitMiddleware("content.submit"), controllers.SubmitUserContent)
 
 	// Get a single content item by ID. OptionalUserAuth lets the per-user
 	// interaction flags (is_liked / is_bookmarked) be derived from a verified
 	// JWT rather than a spoofable ?user_id query param.
 	group.GET("/content/:id", controllers.OptionalUserAuthMiddleware(), controllers.GetContentItem)
 
-	// Comments on a content item (paginated, newest first). OptionalUserAuth
+	// Comments on a content item (threaded; newest, top or oldest first). OptionalUserAuth
 	// lets the is_mine flag be derived from the verified token.
 	group.GET("/content/:id/comments", controllers.OptionalUserAuthMiddleware(), controllers.GetContentComments)
 
-	// User-triggered transcription (JWT-authenticated, rate-limited)
-	group.POST("/content/:id/transcribe", controllers.UserAuthMiddleware(), controllers.RequestTranscription)
+	// User-triggered transcription (JWT-authenticated, rate-limited per user)
+	group.POST("/content/:id/transcribe", controllers.UserAuthMiddleware(), utils.RateLimitMiddleware("content.transcribe"), controllers.RequestTranscription)
 
 	// User-triggered restore for archived items (JWT-authenticated, matching the
 	// other user-triggered content actions above).
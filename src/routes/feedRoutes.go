@@ -2,6 +2,7 @@ package routes
 
 import (
 	"content-management-system/src/controllers"
+	"content-management-system/src/utils"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -13,21 +14,34 @@ func SetupFeedRoutes(group *gin.RouterGroup, db *gorm.DB) {
 	// personalized feeds derive interaction flags / seen-filtering from the
 	// token rather than a spoofable ?user_id query param.
 	auth := controllers.OptionalUserAuthMiddleware()
+	// Syndication documents may be opened in a browser; see utils.DocumentCSP.
+	document := utils.DocumentCSP()
 
 	// Pods feed - audio/video content
 	group.GET("/feed/pods", auth, controllers.GetPodsFeed)
 	group.POST("/feed/pods/sessions", auth, controllers.CreatePodsFeedSession)
 	group.GET("/feed/pods/sessions/:id/freshness", auth, controllers.GetPodsFeedSessionFreshness)
 	group.GET("/feed/pods/sessions/:id", auth, controllers.GetPodsFeedSessionPage)
+	// Continue-listening rail built from the caller's progress checkpoints.
+	group.GET("/feed/pods/continue", auth, controllers.GetPodsContinueListening)
 
 	// News feed - magazine-style slides
 	group.GET("/feed/news", auth, controllers.GetNewsFeed)
 	group.GET("/feed/news/months/:month/review", auth, controllers.GetPublicMonthlyReview)
+	// Chronological story timeline; followers also get unread markers.
+	group.GET("/stories/:id/timeline", auth, controllers.GetStoryTimeline)
 
 	// Syndication output — ad-hoc (per-topic) feeds in 3 formats…
-	group.GET("/feed/rss.xml", controllers.GetRSSFeed)
-	group.GET("/feed/atom.xml", controllers.GetAtomFeed)
-	group.GET("/feed/feed.json", controllers.GetJSONFeed)
+	group.GET("/feed/rss.xml", document, controllers.GetRSSFeed)
+	group.GET("/feed/atom.xml", document, controllers.GetAtomFeed)
+	group.GET("/feed/feed.json", document, controllers.GetJSONFeed)
+	group.GET("/feed/podcast.xml", document, controllers.GetPodcastFeed)
+	// Podcasting 2.0 documents referenced by podcast:transcript / podcast:chapters.
+	group.GET("/feed/items/:id/transcript.vtt", document, controllers.GetFeedItemTranscriptVTT)
+	group.GET("/feed/items/:id/transcript.txt", document, controllers.GetFeedItemTranscriptText)
+	group.GET("/feed/items/:id/chapters.json", document, controllers.GetFeedItemChapters)
 	// …and saved, named feeds resolved by slug.
-	group.GET("/feed/saved/:slug", controllers.GetSavedFeed)
+	group.GET("/feed/saved/:slug", document, controllers.GetSavedFeed)
+	// WebSub hub: saved feeds advertise it via rel="hub"; subscribers register here.
+	group.POST("/feed/websub", utils.RateLimitMiddleware("websub.subscribe"), controllers.PostWebSubHub)
 }
@@ -2,6 +2,7 @@ package routes
 
 import (
 	"content-management-system/src/controllers"
+	"content-management-system/src/utils"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -36,4 +37,57 @@ func SetupInteractionRoutes(group *gin.RouterGroup, db *gorm.DB) {
 	// Delete an interaction (unlike, unbookmark)
 	group.DELETE("/interactions", auth, controllers.DeleteInteractionByContext)
 	group.DELETE("/interactions/:id", auth, controllers.DeleteInteraction)
+
+	// Comment edits, edit history and reactions. Comments are created and
+	// deleted through /interactions; these require a verified user.
+	user := controllers.UserAuthMiddleware()
+	// Feed and HTML bodies a browser may render; see utils.DocumentCSP.
+	document := utils.DocumentCSP()
+	group.PATCH("/comments/:id", user, controllers.EditComment)
+	group.GET("/comments/:id/history", user, controllers.GetCommentHistory)
+	group.POST("/comments/:id/reactions", user, controllers.ReactToComment)
+	group.DELETE("/comments/:id/reactions", user, controllers.RemoveCommentReaction)
+
+	// Personal data export: request and poll with the user JWT; the archive is
+	// fetched through the signed link the status response carries.
+	group.POST("/me/data-exports", user, controllers.RequestPersonalDataExport)
+	group.GET("/me/data-exports", user, controllers.ListPersonalDataExports)
+	group.GET("/me/data-exports/:id", user, controllers.GetPersonalDataExport)
+	group.GET("/data-exports/:id/download", controllers.DownloadPersonalDataExport)
+
+	// Bookmark collections: named, ordered playlists owned by a verified user.
+	// A shared collection is readable (and syndicated) by share token alone.
+	group.GET("/me/collections", user, controllers.ListBookmarkCollections)
+	group.POST("/me/collections", user, controllers.CreateBookmarkCollection)
+	group.GET("/me/collections/:id", user, controllers.GetBookmarkCollection)
+	group.PATCH("/me/collections/:id", user, controllers.UpdateBookmarkCollection)
+	group.DELETE("/me/collections/:id", user, controllers.DeleteBookmarkCollection)
+	group.POST("/me/collections/:id/items", user, controllers.AddBookmarkCollectionItem)
+	group.PUT("/me/collections/:id/items/order", user, controllers.ReorderBookmarkCollection)
+	group.PATCH("/me/collections/:id/items/:content_id", user, controllers.UpdateBookmarkCollectionItem)
+	group.DELETE("/me/collections/:id/items/:content_id", user, controllers.RemoveBookmarkCollectionItem)
+	group.POST("/me/collections/:id/share", user, controllers.ShareBookmarkCollection)
+	group.DELETE("/me/collections/:id/share", user, controllers.UnshareBookmarkCollection)
+	group.GET("/collections/shared/:token", controllers.GetSharedBookmarkCollection)
+	group.GET("/collections/shared/:token/feed", document, controllers.GetSharedBookmarkCollectionFeed)
+
+	// Story follows: per-reader subscriptions with unread counts and a digest.
+	group.GET("/me/stories/follows", user, controllers.ListStoryFollows)
+	group.GET("/me/stories/digest", user, controllers.GetStoryDigest)
+	group.POST("/me/stories/digest/seen", user, controllers.MarkStoryDigestSeen)
+	group.POST("/me/stories/:id/follow", user, controllers.FollowStory)
+	group.DELETE("/me/stories/:id/follow", user, controllers.UnfollowStory)
+	group.POST("/me/stories/:id/seen", user, controllers.MarkStorySeen)
+
+	// Scheduled digests: per-reader settings, a preview and the send ledger.
+	// The unsubscribe link in every digest, and the confirmation link mailed
+	// to a new address, work by token alone.
+	group.GET("/me/digest", user, controllers.GetDigestSubscription)
+	group.PUT("/me/digest", user, controllers.UpdateDigestSubscription)
+	group.GET("/me/digest/preview", user, document, controllers.PreviewDigest)
+	group.GET("/me/digest/deliveries", user, controllers.ListDigestDeliveries)
+	group.GET("/digests/unsubscribe/:token", controllers.UnsubscribeDigestPage)
+	group.POST("/digests/unsubscribe/:token", controllers.UnsubscribeDigest)
+	group.GET("/digests/confirm/:token", controllers.ConfirmDigestEmailPage)
+	group.POST("/digests/confirm/:token", controllers.ConfirmDigestEmail)
 }
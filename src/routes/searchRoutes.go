@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"content-management-system/src/controllers"
+	"content-management-system/src/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SetupSearchRoutes registers the public full-text search API
+func SetupSearchRoutes(group *gin.RouterGroup, db *gorm.DB) {
+	group.GET("/search", utils.RateLimitMiddleware("search.query"), controllers.SearchContent)
+}
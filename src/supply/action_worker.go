@@ -9,6 +9,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"content-management-system/src/lifecycle"
 	"content-management-system/src/models"
 
 	"github.com/google/uuid"
@@ -29,15 +30,15 @@ var supplyActionWorkerLastHeartbeat atomic.Int64
 // adapters whose deterministic execution owner is CMS; Aggregation-owned
 // adoption/redelivery remain unclaimable until their own typed handshakes are
 // installed.
-func StartSupplyActionWorker(db *gorm.DB) {
+func StartSupplyActionWorker(ctx context.Context, db *gorm.DB) {
 	runSupplyActionWorkerOnce(db)
-	go func() {
+	lifecycle.Go(ctx, "supply-actions", func(ctx context.Context) {
 		ticker := time.NewTicker(supplyActionWorkerInterval)
 		defer ticker.Stop()
-		for range ticker.C {
+		for lifecycle.Wait(ctx, ticker.C) {
 			runSupplyActionWorkerOnce(db)
 		}
-	}()
+	})
 }
 
 func SupplyActionWorkerHealthy(now time.Time) bool {
@@ -49,6 +50,10 @@ func SupplyActionWorkerHealthy(now time.Time) bool {
 	return !now.UTC().Before(lastAt) && now.UTC().Sub(lastAt) <= supplyActionWorkerLease*2
 }
 
+func SupplyActionWorkerLastHeartbeat() time.Time {
+	return lifecycle.HeartbeatTime(supplyActionWorkerLastHeartbeat.Load())
+}
+
 func runSupplyActionWorkerOnce(db *gorm.DB) {
 	if _, err := RecoverExpiredSupplyActionClaims(db, supplyActionWorkerBatch); err != nil {
 		log.Printf("media supply action recovery failed: %v", err)
@@ -3,6 +3,8 @@ package supply
 import (
 	"sync/atomic"
 	"time"
+
+	"content-management-system/src/lifecycle"
 )
 
 const MediaSupplyEvaluatorHeartbeatGrace = 15 * time.Minute
@@ -26,6 +28,10 @@ func MediaSupplyEvaluatorWorkerHealthy(now time.Time) bool {
 	return MediaSupplyEvaluatorWorkerStatusAt(now).State == "ready"
 }
 
+func MediaSupplyEvaluatorWorkerLastHeartbeat() time.Time {
+	return lifecycle.HeartbeatTime(mediaSupplyEvaluatorLastHeartbeat.Load())
+}
+
 func MediaSupplyEvaluatorWorkerStatusAt(now time.Time) MediaSupplyEvaluatorWorkerStatus {
 	lastNanos := mediaSupplyEvaluatorLastHeartbeat.Load()
 	if lastNanos <= 0 {
@@ -18,6 +18,10 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"content-management-system/src/lifecycle"
+
+	"content-management-system/src/tracing"
 )
 
 const (
@@ -46,20 +50,20 @@ var supplyOwnerReadinessState = struct {
 	snapshot supplyOwnerReadinessSnapshot
 }{snapshot: supplyOwnerReadinessSnapshot{owners: map[string]SupplyOwnerReadiness{}}}
 
-var supplyOwnerReadinessHTTPClient = &http.Client{Timeout: supplyOwnerReadinessTimeout}
+var supplyOwnerReadinessHTTPClient = &http.Client{Transport: tracing.Transport(nil), Timeout: supplyOwnerReadinessTimeout}
 
 // StartSupplyOwnerReadinessObserver is bounded process-local readiness. Its
 // static endpoints are part of the service contract; callers cannot select a
 // URL, route, queue, or owner at runtime.
-func StartSupplyOwnerReadinessObserver() {
+func StartSupplyOwnerReadinessObserver(ctx context.Context) {
 	observeSupplyOwners(time.Now().UTC())
-	go func() {
+	lifecycle.Go(ctx, "supply-owner-readiness", func(ctx context.Context) {
 		ticker := time.NewTicker(supplyOwnerReadinessInterval)
 		defer ticker.Stop()
-		for now := range ticker.C {
-			observeSupplyOwners(now.UTC())
+		for lifecycle.Wait(ctx, ticker.C) {
+			observeSupplyOwners(time.Now().UTC())
 		}
-	}()
+	})
 }
 
 func observeSupplyOwners(now time.Time) {
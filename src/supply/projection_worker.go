@@ -1,10 +1,13 @@
 package supply
 
 import (
+	"context"
 	"log"
 	"sync/atomic"
 	"time"
 
+	"content-management-system/src/lifecycle"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
@@ -18,16 +21,16 @@ var projectionWorkerLastHeartbeat atomic.Int64
 
 // StartProjectionWorker starts CMS-only current-state reduction. It owns no
 // provider effects and does not dispatch or inspect an Aggregation queue.
-func StartProjectionWorker(db *gorm.DB) {
+func StartProjectionWorker(ctx context.Context, db *gorm.DB) {
 	owner := "cms-source-run-projection-" + uuid.NewString()
 	runProjectionWorkerOnce(db, owner)
-	go func() {
+	lifecycle.Go(ctx, "source-run-projection", func(ctx context.Context) {
 		ticker := time.NewTicker(projectionWorkerInterval)
 		defer ticker.Stop()
-		for range ticker.C {
+		for lifecycle.Wait(ctx, ticker.C) {
 			runProjectionWorkerOnce(db, owner)
 		}
-	}()
+	})
 }
 
 func ProjectionWorkerHealthy(now time.Time) bool {
@@ -35,6 +38,10 @@ func ProjectionWorkerHealthy(now time.Time) bool {
 	return last > 0 && now.UTC().Sub(time.Unix(0, last)) <= projectionWorkerLease*2
 }
 
+func ProjectionWorkerLastHeartbeat() time.Time {
+	return lifecycle.HeartbeatTime(projectionWorkerLastHeartbeat.Load())
+}
+
 func runProjectionWorkerOnce(db *gorm.DB, owner string) {
 	if err := AdvanceSourceRunManifests(db, 64); err != nil {
 		log.Printf("source-run manifest advancement failed: %v", err)
@@ -1,11 +1,13 @@
 package supply
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"sync/atomic"
 	"time"
 
+	"content-management-system/src/lifecycle"
 	"content-management-system/src/models"
 
 	"gorm.io/gorm"
@@ -24,15 +26,15 @@ var reconcilerWorkerLastHeartbeat atomic.Int64
 // no provider, queue, or executor effect: it only reaps expired leases and
 // ensures that every effect-ambiguous unit has an idempotent verification
 // task. Aggregation remains the independent observer that completes a task.
-func StartReconcilerWorker(db *gorm.DB) {
+func StartReconcilerWorker(ctx context.Context, db *gorm.DB) {
 	runReconcilerWorkerOnce(db)
-	go func() {
+	lifecycle.Go(ctx, "source-run-reconciler", func(ctx context.Context) {
 		ticker := time.NewTicker(reconcilerWorkerInterval)
 		defer ticker.Stop()
-		for range ticker.C {
+		for lifecycle.Wait(ctx, ticker.C) {
 			runReconcilerWorkerOnce(db)
 		}
-	}()
+	})
 }
 
 // ReconcilerWorkerHealthy is deliberately separate from recovery health:
@@ -47,6 +49,10 @@ func ReconcilerWorkerHealthy(now time.Time) bool {
 	return !now.UTC().Before(lastAt) && now.UTC().Sub(lastAt) <= reconcilerWorkerLease*2
 }
 
+func ReconcilerWorkerLastHeartbeat() time.Time {
+	return lifecycle.HeartbeatTime(reconcilerWorkerLastHeartbeat.Load())
+}
+
 func runReconcilerWorkerOnce(db *gorm.DB) {
 	if _, err := ReconcileSourceRunWork(db, reconcilerBatchLimit); err != nil {
 		log.Printf("source-run reconciliation failed: %v", err)
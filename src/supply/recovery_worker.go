@@ -1,11 +1,13 @@
 package supply
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"sync/atomic"
 	"time"
 
+	"content-management-system/src/lifecycle"
 	"content-management-system/src/models"
 
 	"github.com/google/uuid"
@@ -24,16 +26,16 @@ var recoveryWorkerLastHeartbeat atomic.Int64
 // StartRecoveryWorker converges expired source-run ownership without provider
 // I/O. It can release a pre-effect dispatcher claim for redelivery, but every
 // unit that crossed BeginUnitEffect is sent to verification rather than rerun.
-func StartRecoveryWorker(db *gorm.DB) {
+func StartRecoveryWorker(ctx context.Context, db *gorm.DB) {
 	owner := "cms-source-run-recovery-" + uuid.NewString()
 	runRecoveryWorkerOnce(db, owner)
-	go func() {
+	lifecycle.Go(ctx, "source-run-recovery", func(ctx context.Context) {
 		ticker := time.NewTicker(recoveryWorkerInterval)
 		defer ticker.Stop()
-		for range ticker.C {
+		for lifecycle.Wait(ctx, ticker.C) {
 			runRecoveryWorkerOnce(db, owner)
 		}
-	}()
+	})
 }
 
 func RecoveryWorkerHealthy(now time.Time) bool {
@@ -41,6 +43,10 @@ func RecoveryWorkerHealthy(now time.Time) bool {
 	return last > 0 && now.UTC().Sub(time.Unix(0, last)) <= recoveryWorkerLease*2
 }
 
+func RecoveryWorkerLastHeartbeat() time.Time {
+	return lifecycle.HeartbeatTime(recoveryWorkerLastHeartbeat.Load())
+}
+
 func runRecoveryWorkerOnce(db *gorm.DB, _ string) {
 	if err := ReapExpiredSourceRunWork(db, recoveryBatchLimit); err != nil {
 		log.Printf("source-run recovery failed: %v", err)
@@ -1,6 +1,7 @@
 package supply
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
@@ -10,6 +11,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"content-management-system/src/lifecycle"
 	"content-management-system/src/models"
 
 	"github.com/google/uuid"
@@ -31,6 +33,10 @@ func SourceRunSchedulerHealthy(now time.Time) bool {
 	return last > 0 && now.UTC().Sub(time.Unix(0, last).UTC()) <= sourceRunSchedulerHeartbeatGrace
 }
 
+func SourceRunSchedulerLastHeartbeat() time.Time {
+	return lifecycle.HeartbeatTime(sourceRunSchedulerHeartbeat.Load())
+}
+
 func markSourceRunSchedulerHeartbeat(now time.Time) {
 	sourceRunSchedulerHeartbeat.Store(now.UTC().UnixNano())
 }
@@ -253,15 +259,15 @@ func derefSourceURL(value *string) string {
 // StartSourceRunScheduler runs bounded CMS-only admission. It does not make
 // source work executable; that requires the later Aggregation dispatcher
 // cutover and its independently qualified receipt durability contract.
-func StartSourceRunScheduler(db *gorm.DB) {
+func StartSourceRunScheduler(ctx context.Context, db *gorm.DB) {
 	runSourceRunSchedulerOnce(db)
-	go func() {
+	lifecycle.Go(ctx, "source-run-scheduler", func(ctx context.Context) {
 		ticker := time.NewTicker(sourceRunSchedulerInterval)
 		defer ticker.Stop()
-		for range ticker.C {
+		for lifecycle.Wait(ctx, ticker.C) {
 			runSourceRunSchedulerOnce(db)
 		}
-	}()
+	})
 }
 
 func runSourceRunSchedulerOnce(db *gorm.DB) {
@@ -1,10 +1,12 @@
 package supply
 
 import (
+	"context"
 	"log"
 	"sync/atomic"
 	"time"
 
+	"content-management-system/src/lifecycle"
 	"content-management-system/src/models"
 
 	"gorm.io/gorm"
@@ -14,15 +16,15 @@ const upstreamObservationWorkerInterval = time.Minute
 
 var upstreamObservationWorkerHeartbeat atomic.Int64
 
-func StartUpstreamObservationWorker(db *gorm.DB) {
+func StartUpstreamObservationWorker(ctx context.Context, db *gorm.DB) {
 	runUpstreamObservationWorkerOnce(db)
-	go func() {
+	lifecycle.Go(ctx, "upstream-observation", func(ctx context.Context) {
 		ticker := time.NewTicker(upstreamObservationWorkerInterval)
 		defer ticker.Stop()
-		for range ticker.C {
+		for lifecycle.Wait(ctx, ticker.C) {
 			runUpstreamObservationWorkerOnce(db)
 		}
-	}()
+	})
 }
 
 func UpstreamObservationWorkerHealthy(now time.Time) bool {
@@ -30,6 +32,10 @@ func UpstreamObservationWorkerHealthy(now time.Time) bool {
 	return last > 0 && !now.UTC().Before(time.Unix(0, last)) && now.UTC().Sub(time.Unix(0, last)) <= 3*upstreamObservationWorkerInterval
 }
 
+func UpstreamObservationWorkerLastHeartbeat() time.Time {
+	return lifecycle.HeartbeatTime(upstreamObservationWorkerHeartbeat.Load())
+}
+
 func runUpstreamObservationWorkerOnce(db *gorm.DB) {
 	if db == nil {
 		return
@@ -37,6 +37,8 @@ func setup() error {
 	os.Setenv("CMS_AGGREGATION_SERVICE_TOKEN", integrationAggregationToken)
 	os.Setenv("CMS_ENRICHMENT_SERVICE_TOKEN", integrationEnrichmentToken)
 	os.Setenv("CMS_MEDIA_SERVICE_TOKEN", integrationMediaToken)
+	// Public page/post reads serve the configured public tenant.
+	setDefaultEnvIfEmpty("DEFAULT_TENANT_ID", "default")
 
 	// testdb validates before opening a connection and creates a fresh database
 	// whenever the CI/local admin URL is available. It never reads service .env.
@@ -54,6 +56,7 @@ func setup() error {
 		&models.Page{},
 		&models.Media{},
 		&models.Post{},
+		&models.EditorialRevision{},
 		// Wahb Platform models
 		&models.ContentItem{},
 		&models.Transcript{},
@@ -63,6 +66,21 @@ func setup() error {
 		// Phase 13 — story feed
 		&models.RankingConfig{},
 		&models.ContentFlag{},
+		&models.CommentClassifierConfig{},
+		&models.CommentClassifierStat{},
+		&models.UserTasteVector{},
+		&models.InternalRequestNonce{},
+		&models.RateLimitBucket{},
+		&models.CommentRevision{},
+		&models.CommentReaction{},
+		&models.PersonalDataExport{},
+		&models.BookmarkCollection{},
+		&models.BookmarkCollectionItem{},
+		&models.StoryFollow{},
+		&models.StorySummaryRevision{},
+		&models.DigestSubscription{},
+		&models.DigestDelivery{},
+		&models.TenantCORSOrigin{},
 		// Temporary fixture support for internal vector write fencing.
 		&models.EmbeddingCampaign{},
 		&models.Story{},
@@ -122,6 +140,7 @@ func clearTables() {
 	if testDB == nil {
 		return
 	}
+	_ = testDB.Exec("DELETE FROM editorial_revisions").Error
 	_ = testDB.Exec("DELETE FROM post_media").Error
 	_ = testDB.Exec("DELETE FROM posts").Error
 	_ = testDB.Exec("DELETE FROM media").Error
@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"regexp"
 	"testing"
+	"time"
 
 	"content-management-system/src/controllers"
 	"content-management-system/src/models"
@@ -14,11 +15,13 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 func setupMediaRouter(t *testing.T) (*gin.Engine, sqlmock.Sqlmock) {
 	gin.SetMode(gin.TestMode)
 	router, _, mock := utils.SetupRouterAndMockDB(t)
+	withAdminPrincipal(router)
 	router.POST("/media", controllers.CreateMedia)
 	router.GET("/media/:id", controllers.GetMedia)
 	router.GET("/media", controllers.GetMedia)
@@ -74,3 +77,65 @@ func TestDeleteMedia_InvalidID(t *testing.T) {
 		t.Fatalf("expected %d, got %d", http.StatusBadRequest, w.Code)
 	}
 }
+
+func TestGetMedia_DoesNotPreloadPosts(t *testing.T) {
+	router, mock := setupMediaRouter(t)
+	id := uuid.New()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "media" WHERE public_id = $1`)).
+		WithArgs(id, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "public_id", "url", "type", "created_at", "updated_at"}).
+			AddRow(1, id, "https://x", "image", time.Now(), time.Now()))
+
+	req := httptest.NewRequest(http.MethodGet, "/media/"+id.String(), nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if bytes.Contains(w.Body.Bytes(), []byte(`"posts"`)) {
+		t.Fatalf("public media read exposed posts: %s", w.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestDeleteMedia_OtherTenantNotFound(t *testing.T) {
+	router, mock := setupMediaRouter(t)
+	id := uuid.New()
+	mock.ExpectQuery(regexp.QuoteMeta(`((media.tenant_id = $1 OR media.tenant_id IS NULL)) AND public_id = $2`)).
+		WithArgs(unitTenant, id, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	body, _ := json.Marshal(models.Media{URL: "u"})
+	req := httptest.NewRequest(http.MethodDelete, "/media/"+id.String(), bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected %d, got %d", http.StatusNotFound, w.Code)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestDeleteMedia_SharedMediaForbidden(t *testing.T) {
+	router, mock := setupMediaRouter(t)
+	id := uuid.New()
+	mock.ExpectQuery(regexp.QuoteMeta(`((media.tenant_id = $1 OR media.tenant_id IS NULL)) AND public_id = $2`)).
+		WithArgs(unitTenant, id, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "public_id", "url", "tenant_id"}).AddRow(1, id, "https://x", nil))
+
+	body, _ := json.Marshal(models.Media{URL: "u"})
+	req := httptest.NewRequest(http.MethodDelete, "/media/"+id.String(), bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected %d, got %d", http.StatusForbidden, w.Code)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"regexp"
+	"strings"
 	"testing"
 	"time"
 
@@ -16,12 +17,25 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"gorm.io/gorm"
 )
 
+const unitTenant = "tenant-a"
+
+// withAdminPrincipal stands in for AdminAuthMiddleware in handler tests.
+func withAdminPrincipal(router *gin.Engine) {
+	router.Use(func(c *gin.Context) {
+		c.Set(utils.AdminPrincipalContextKey, utils.AdminPrincipal{UserID: "editor-1", TenantID: unitTenant})
+		c.Next()
+	})
+}
+
 func setupPageRouter(t *testing.T) (*gin.Engine, *gorm.DB, sqlmock.Sqlmock) {
 	gin.SetMode(gin.TestMode)
+	t.Setenv("DEFAULT_TENANT_ID", unitTenant)
 	router, db, mock := utils.SetupRouterAndMockDB(t)
+	withAdminPrincipal(router)
 	router.POST("/pages", controllers.CreatePage)
 	router.GET("/pages", controllers.GetPages)
 	router.GET("/pages/:id", controllers.GetPage)
@@ -30,11 +44,25 @@ func setupPageRouter(t *testing.T) (*gin.Engine, *gorm.DB, sqlmock.Sqlmock) {
 	return router, db, mock
 }
 
+var pageCols = []string{"id", "public_id", "title", "content", "tenant_id", "slug", "status", "current_revision", "content_format", "created_at", "updated_at"}
+
+func pageRow(title, content string) *sqlmock.Rows {
+	now := time.Now()
+	return sqlmock.NewRows(pageCols).AddRow(1, uuid.New(), title, content, unitTenant, "slug", "draft", 1, "markdown", now, now)
+}
+
 func TestCreatePage_Success(t *testing.T) {
 	router, _, mock := setupPageRouter(t)
 
 	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "slug" FROM "pages"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"slug"}).AddRow("test-page"))
 	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "pages"`)).
+		// Pages publish on create unless status=draft, as they did before
+		// revisions existed.
+		WithArgs("Test Page", "Hello", unitTenant, "test-page-2", "published", "editor-1", 1, 1, nil, nil, nil, sqlmock.AnyArg(), nil, "markdown", "<p>Hello</p>", "Hello", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "editorial_revisions"`)).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 	mock.ExpectCommit()
 
@@ -54,10 +82,79 @@ func TestCreatePage_Success(t *testing.T) {
 	}
 }
 
+func TestCreatePage_SanitizesContent(t *testing.T) {
+	router, _, mock := setupPageRouter(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "slug" FROM "pages"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"slug"}))
+	// content keeps the source; content_html is what readers get.
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "pages"`)).
+		WithArgs("XSS", `<p onclick="x()">**Hi**</p><script>alert(1)</script>`, unitTenant, "xss", "draft", "editor-1", 1, nil, nil, nil, nil, nil, nil, "html", "<p>**Hi**</p>", "**Hi**", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "editorial_revisions"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	body := `{"title":"XSS","content":"<p onclick=\"x()\">**Hi**</p><script>alert(1)</script>","content_format":"html","status":"draft"}`
+	req := httptest.NewRequest(http.MethodPost, "/pages", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected %d, got %d, body=%s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var resp struct {
+		Warnings []struct {
+			Code string `json:"code"`
+		} `json:"warnings"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || len(resp.Warnings) != 2 {
+		t.Fatalf("expected 2 warnings, body=%s", w.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestCreatePage_InvalidFormat(t *testing.T) {
+	router, _, _ := setupPageRouter(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/pages", bytes.NewBufferString(`{"title":"T","content":"x","content_format":"rtf"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected %d, got %d, body=%s", http.StatusBadRequest, w.Code, w.Body.String())
+	}
+}
+
+func TestCreatePage_SlugConflict(t *testing.T) {
+	router, _, mock := setupPageRouter(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "slug" FROM "pages"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"slug"}).AddRow("about"))
+	mock.ExpectRollback()
+
+	req := httptest.NewRequest(http.MethodPost, "/pages", bytes.NewBufferString(`{"title":"About","content":"x","slug":"About"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected %d, got %d, body=%s", http.StatusConflict, w.Code, w.Body.String())
+	}
+}
+
 func TestCreatePage_DBError(t *testing.T) {
 	router, _, mock := setupPageRouter(t)
 
 	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "slug" FROM "pages"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"slug"}))
 	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "pages"`)).
 		WillReturnError(assertErr())
 	mock.ExpectRollback()
@@ -72,12 +169,36 @@ func TestCreatePage_DBError(t *testing.T) {
 	if w.Code != http.StatusInternalServerError {
 		t.Fatalf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
 	}
+	if strings.Contains(w.Body.String(), "mock error") {
+		t.Fatalf("database error leaked to the client: %s", w.Body.String())
+	}
+}
+
+func TestCreatePage_ConcurrentSlugConflict(t *testing.T) {
+	router, _, mock := setupPageRouter(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "slug" FROM "pages"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"slug"}))
+	// Another writer claimed the slug between the check and the insert.
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "pages"`)).
+		WillReturnError(&pq.Error{Code: "23505", Constraint: "idx_pages_tenant_slug"})
+	mock.ExpectRollback()
+
+	req := httptest.NewRequest(http.MethodPost, "/pages", bytes.NewBufferString(`{"title":"About","content":"x","slug":"about"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected %d, got %d, body=%s", http.StatusConflict, w.Code, w.Body.String())
+	}
 }
 
 func TestGetPage_InvalidID(t *testing.T) {
 	router, _, _ := setupPageRouter(t)
 
-	req := httptest.NewRequest(http.MethodGet, "/pages/abc", nil)
+	req := httptest.NewRequest(http.MethodGet, "/pages/Not_A_Slug", nil)
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 
@@ -89,7 +210,7 @@ func TestGetPage_InvalidID(t *testing.T) {
 func TestGetPage_NotFound(t *testing.T) {
 	router, _, mock := setupPageRouter(t)
 
-	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "pages"`)).
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM "pages" JOIN editorial_revisions r`)).
 		WillReturnError(gorm.ErrRecordNotFound)
 
 	req := httptest.NewRequest(http.MethodGet, "/pages/1", nil)
@@ -104,71 +225,104 @@ func TestGetPage_NotFound(t *testing.T) {
 func TestGetPage_Success(t *testing.T) {
 	router, _, mock := setupPageRouter(t)
 
-	cols := []string{"id", "public_id", "title", "content", "created_at", "updated_at"}
+	cols := []string{"public_id", "slug", "title", "content", "revision", "published_at", "created_at", "updated_at"}
 	now := time.Now()
-	pid := uuid.New()
-	row := sqlmock.NewRows(cols).AddRow(1, pid, "Title", "Body", now, now)
-	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "pages"`)).
-		WillReturnRows(row)
-	// second First call inside handler
-	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "pages"`)).
+	row := sqlmock.NewRows(cols).AddRow(uuid.New(), "about", "Title", "Body", 2, now, now, now)
+	// Only the published revision of a published page in the public tenant.
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM "pages" JOIN editorial_revisions r ON r.entity_type = $1 AND r.entity_id = pages.id AND r.revision = pages.published_revision WHERE (pages.tenant_id = $2 AND pages.status = $3) AND pages.slug = $4`)).
+		WithArgs("page", unitTenant, "published", "about", 1).
 		WillReturnRows(row)
 
-	req := httptest.NewRequest(http.MethodGet, "/pages/1", nil)
+	req := httptest.NewRequest(http.MethodGet, "/pages/about", nil)
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 
 	if w.Code != http.StatusOK {
 		t.Fatalf("expected %d, got %d, body=%s", http.StatusOK, w.Code, w.Body.String())
 	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestGetPage_NoPublicTenant(t *testing.T) {
+	router, _, _ := setupPageRouter(t)
+	t.Setenv("DEFAULT_TENANT_ID", "")
+
+	req := httptest.NewRequest(http.MethodGet, "/pages/about", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected %d, got %d", http.StatusServiceUnavailable, w.Code)
+	}
 }
 
 func TestUpdatePage_Success(t *testing.T) {
 	router, _, mock := setupPageRouter(t)
 
-	// Find existing
-	cols := []string{"id", "public_id", "title", "content", "created_at", "updated_at"}
-	now := time.Now()
-	pid := uuid.New()
-	row := sqlmock.NewRows(cols).AddRow(1, pid, "Old", "Old", now, now)
-	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "pages"`)).
-		WillReturnRows(row)
-
 	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "pages"`) + `.*FOR UPDATE`).
+		WillReturnRows(pageRow("Old", "Old"))
 	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "pages" SET`)).
 		WillReturnResult(sqlmock.NewResult(0, 1))
+	// The edit is recorded as revision 2.
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "editorial_revisions"`)).
+		WithArgs(unitTenant, "page", 1, 2, "New", "New", "markdown", "<p>New</p>", "New", nil, nil, nil, "editor-1", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
 	mock.ExpectCommit()
 
 	payload := models.Page{Title: "New", Content: "New"}
 	body, _ := json.Marshal(payload)
-	req := httptest.NewRequest(http.MethodPut, "/pages/1", bytes.NewReader(body))
+	req := httptest.NewRequest(http.MethodPut, "/pages/"+uuid.NewString(), bytes.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 
 	if w.Code != http.StatusOK {
-		t.Fatalf("expected %d, got %d", http.StatusOK, w.Code)
+		t.Fatalf("expected %d, got %d, body=%s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
 	}
 }
 
-func TestUpdatePage_SaveError(t *testing.T) {
+func TestUpdatePage_UnchangedSkipsRevision(t *testing.T) {
 	router, _, mock := setupPageRouter(t)
 
-	cols := []string{"id", "public_id", "title", "content", "created_at", "updated_at"}
-	now := time.Now()
-	pid := uuid.New()
-	row := sqlmock.NewRows(cols).AddRow(1, pid, "Old", "Old", now, now)
+	mock.ExpectBegin()
 	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "pages"`)).
-		WillReturnRows(row)
+		WillReturnRows(pageRow("Same", "Same"))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "pages" SET`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	req := httptest.NewRequest(http.MethodPut, "/pages/"+uuid.NewString(), bytes.NewBufferString(`{"title":"Same","content":"Same"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d, body=%s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestUpdatePage_SaveError(t *testing.T) {
+	router, _, mock := setupPageRouter(t)
 
 	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "pages"`)).
+		WillReturnRows(pageRow("Old", "Old"))
 	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "pages" SET`)).
 		WillReturnError(assertErr())
 	mock.ExpectRollback()
 
 	payload := models.Page{Title: "New", Content: "New"}
 	body, _ := json.Marshal(payload)
-	req := httptest.NewRequest(http.MethodPut, "/pages/1", bytes.NewReader(body))
+	req := httptest.NewRequest(http.MethodPut, "/pages/"+uuid.NewString(), bytes.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
@@ -181,21 +335,19 @@ func TestUpdatePage_SaveError(t *testing.T) {
 func TestDeletePage_Success(t *testing.T) {
 	router, _, mock := setupPageRouter(t)
 
-	cols := []string{"id", "public_id", "title", "content", "created_at", "updated_at"}
-	now := time.Now()
-	pid := uuid.New()
-	row := sqlmock.NewRows(cols).AddRow(1, pid, "T", "C", now, now)
-	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "pages"`)).
-		WillReturnRows(row)
-
 	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "pages"`)).
+		WillReturnRows(pageRow("T", "C"))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "editorial_revisions"`)).
+		WithArgs("page", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
 	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "pages"`)).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 	mock.ExpectCommit()
 
 	payload := models.Page{Title: "T", Content: "C"}
 	body, _ := json.Marshal(payload)
-	req := httptest.NewRequest(http.MethodDelete, "/pages/1", bytes.NewReader(body))
+	req := httptest.NewRequest(http.MethodDelete, "/pages/"+uuid.NewString(), bytes.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
@@ -208,12 +360,14 @@ func TestDeletePage_Success(t *testing.T) {
 func TestDeletePage_NotFound(t *testing.T) {
 	router, _, mock := setupPageRouter(t)
 
+	mock.ExpectBegin()
 	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "pages"`)).
 		WillReturnError(gorm.ErrRecordNotFound)
+	mock.ExpectRollback()
 
 	payload := models.Page{Title: "T", Content: "C"}
 	body, _ := json.Marshal(payload)
-	req := httptest.NewRequest(http.MethodDelete, "/pages/1", bytes.NewReader(body))
+	req := httptest.NewRequest(http.MethodDelete, "/pages/"+uuid.NewString(), bytes.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
@@ -225,7 +379,7 @@ func TestDeletePage_NotFound(t *testing.T) {
 
 func TestDeletePage_InvalidJSON(t *testing.T) {
 	router, _, _ := setupPageRouter(t)
-	req := httptest.NewRequest(http.MethodDelete, "/pages/1", bytes.NewBufferString("{"))
+	req := httptest.NewRequest(http.MethodDelete, "/pages/"+uuid.NewString(), bytes.NewBufferString("{"))
 	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"regexp"
+	"strings"
 	"testing"
 
 	"content-management-system/src/controllers"
@@ -14,11 +15,14 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 func setupPostRouter(t *testing.T) (*gin.Engine, sqlmock.Sqlmock) {
 	gin.SetMode(gin.TestMode)
+	t.Setenv("DEFAULT_TENANT_ID", unitTenant)
 	router, _, mock := utils.SetupRouterAndMockDB(t)
+	withAdminPrincipal(router)
 	router.POST("/posts", controllers.CreatePost)
 	router.GET("/posts", controllers.GetPosts)
 	router.GET("/posts/:id", controllers.GetPost)
@@ -31,13 +35,14 @@ func TestCreatePost_Success(t *testing.T) {
 	router, mock := setupPostRouter(t)
 
 	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "slug" FROM "posts"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"slug"}))
 	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "posts"`)).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
-	// Preload after create
-	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "posts"`)).
+	// Revision 1 snapshots title, content and author.
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "editorial_revisions"`)).
+		WithArgs(unitTenant, "post", 1, 1, "T", "C", "markdown", "<p>C</p>", "C", "A", sqlmock.AnyArg(), nil, "editor-1", sqlmock.AnyArg()).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
-	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "post_media"`)).
-		WillReturnRows(sqlmock.NewRows([]string{"post_id", "media_id"}))
 	mock.ExpectCommit()
 
 	payload := models.Post{Title: "T", Content: "C", Author: "A"}
@@ -56,6 +61,8 @@ func TestCreatePost_DBError(t *testing.T) {
 	router, mock := setupPostRouter(t)
 
 	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "slug" FROM "posts"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"slug"}))
 	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "posts"`)).
 		WillReturnError(assertErr())
 	mock.ExpectRollback()
@@ -74,12 +81,15 @@ func TestCreatePost_DBError(t *testing.T) {
 func TestGetPosts_Success(t *testing.T) {
 	router, mock := setupPostRouter(t)
 
-	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "posts"`)).
+	mediaID := uuid.New()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "posts" JOIN editorial_revisions r`)).
 		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
-	mock.ExpectQuery(`SELECT .* FROM "posts"`).
-		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
-	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "post_media"`)).
-		WillReturnRows(sqlmock.NewRows([]string{"post_id", "media_id"}))
+	mock.ExpectQuery(`SELECT posts.public_id, .* FROM "posts" JOIN editorial_revisions r .* WHERE posts.tenant_id = \$2 AND posts.status = \$3 ORDER BY`).
+		WillReturnRows(sqlmock.NewRows([]string{"public_id", "title", "author", "media_ids"}).
+			AddRow(uuid.New(), "T", "A", "{"+mediaID.String()+"}"))
+	// Media comes from the published revision's media_ids.
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "media" WHERE public_id = ANY($1::uuid[])`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "public_id", "url"}).AddRow(7, mediaID, "https://cdn.test/a.jpg"))
 
 	req := httptest.NewRequest(http.MethodGet, "/posts", nil)
 	w := httptest.NewRecorder()
@@ -87,11 +97,17 @@ func TestGetPosts_Success(t *testing.T) {
 	if w.Code != http.StatusOK {
 		t.Fatalf("expected %d, got %d", http.StatusOK, w.Code)
 	}
+	if !strings.Contains(w.Body.String(), "https://cdn.test/a.jpg") {
+		t.Fatalf("expected revision media in body, got %s", w.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
 }
 
 func TestGetPost_InvalidID(t *testing.T) {
 	router, _ := setupPostRouter(t)
-	req := httptest.NewRequest(http.MethodGet, "/posts/not-a-uuid", nil)
+	req := httptest.NewRequest(http.MethodGet, "/posts/Not_A_Slug", nil)
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 	if w.Code != http.StatusBadRequest {
@@ -0,0 +1,178 @@
+package toxicity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Rule matches normalized text by phrase (substring) or regular expression.
+// Arabic rules should use phrases: RE2 word boundaries are ASCII-only.
+type Rule struct {
+	ID       string   `json:"id"`
+	Category Category `json:"category"`
+	Score    float64  `json:"score"`
+	Phrases  []string `json:"phrases,omitempty"`
+	Pattern  string   `json:"pattern,omitempty"`
+
+	re *regexp.Regexp
+}
+
+// RulePack is a set of rules for one language; "*" applies to every comment.
+type RulePack struct {
+	Language string `json:"language"`
+	Rules    []Rule `json:"rules"`
+}
+
+// Lexicon is the local, deterministic classifier. It never errors and never
+// sends text anywhere.
+type Lexicon struct {
+	packs []RulePack
+}
+
+// NewLexicon compiles packs, normalizing phrases the same way comments are.
+func NewLexicon(packs ...RulePack) (*Lexicon, error) {
+	l := &Lexicon{}
+	for _, p := range packs {
+		compiled := RulePack{Language: strings.ToLower(strings.TrimSpace(p.Language))}
+		for _, r := range p.Rules {
+			if !ValidCategory(r.Category) {
+				return nil, fmt.Errorf("rule %q: unknown category %q", r.ID, r.Category)
+			}
+			if r.Score <= 0 || r.Score > 1 {
+				return nil, fmt.Errorf("rule %q: score must be in (0, 1]", r.ID)
+			}
+			if r.Pattern != "" {
+				re, err := regexp.Compile(r.Pattern)
+				if err != nil {
+					return nil, fmt.Errorf("rule %q: %w", r.ID, err)
+				}
+				r.re = re
+			}
+			phrases := make([]string, 0, len(r.Phrases))
+			for _, ph := range r.Phrases {
+				if ph = Normalize(ph); ph != "" {
+					phrases = append(phrases, ph)
+				}
+			}
+			r.Phrases = phrases
+			if r.re == nil && len(r.Phrases) == 0 {
+				return nil, fmt.Errorf("rule %q: needs phrases or a pattern", r.ID)
+			}
+			compiled.Rules = append(compiled.Rules, r)
+		}
+		l.packs = append(l.packs, compiled)
+	}
+	return l, nil
+}
+
+// LoadRulePacks reads a JSON array of RulePack from path.
+func LoadRulePacks(path string) ([]RulePack, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var packs []RulePack
+	if err := json.Unmarshal(raw, &packs); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return packs, nil
+}
+
+func (l *Lexicon) Name() string { return "lexicon" }
+
+func (l *Lexicon) Classify(_ context.Context, in Input) ([]Signal, error) {
+	text := in.Normalized
+	if text == "" {
+		text = Normalize(in.Text)
+	}
+	languages := in.Languages
+	if languages == nil {
+		languages = Languages(text)
+	}
+	var out []Signal
+	for _, p := range l.packs {
+		if p.Language != "*" && !contains(languages, p.Language) {
+			continue
+		}
+		for _, r := range p.Rules {
+			if r.matches(text) {
+				out = append(out, Signal{Category: r.Category, Score: r.Score, Source: l.Name(), Rule: p.Language + ":" + r.ID})
+			}
+		}
+	}
+	for _, s := range mechanicalSignals(text) {
+		s.Source = l.Name()
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+func (r Rule) matches(text string) bool {
+	for _, ph := range r.Phrases {
+		if strings.Contains(text, ph) {
+			return true
+		}
+	}
+	return r.re != nil && r.re.MatchString(text)
+}
+
+func contains(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// mechanicalSignals scores spam shapes that no phrase list catches.
+func mechanicalSignals(text string) []Signal {
+	var out []Signal
+	if links := strings.Count(text, "http://") + strings.Count(text, "https://") + strings.Count(text, "www."); links > 0 {
+		score := 0.3
+		if links > 1 {
+			score = 0.7
+		}
+		out = append(out, Signal{Category: CategorySpam, Score: score, Rule: "*:links"})
+	}
+	if strings.Count(text, "@") >= 5 {
+		out = append(out, Signal{Category: CategorySpam, Score: 0.5, Rule: "*:mass_mentions"})
+	}
+	return out
+}
+
+// DefaultRulePacks are the built-in English and Arabic packs. They err
+// towards review: single insults score below the default hold threshold,
+// several together cross it.
+func DefaultRulePacks() []RulePack {
+	return []RulePack{
+		{Language: "en", Rules: []Rule{
+			{ID: "threat_kill", Category: CategoryThreat, Score: 0.95, Phrases: []string{"i will kill you", "i'm going to kill you", "you will die", "i will find you"}},
+			{ID: "threat_hurt", Category: CategoryThreat, Score: 0.7, Pattern: `\b(?:i will|i'll|gonna) (?:hurt|beat|shoot|stab) (?:you|u)\b`},
+			{ID: "self_harm_incite", Category: CategorySelfHarm, Score: 0.95, Pattern: `\bkill yourself\b|\bkys\b`},
+			{ID: "self_harm", Category: CategorySelfHarm, Score: 0.6, Phrases: []string{"kill myself", "want to die", "end my life"}},
+			{ID: "insult", Category: CategoryHarassment, Score: 0.4, Pattern: `\b(?:idiots?|morons?|stupid|loser|pathetic|dumb|clown)\b`},
+			{ID: "insult_direct", Category: CategoryHarassment, Score: 0.5, Pattern: `\byou(?:'re| are) (?:an? )?(?:idiot|moron|stupid|loser|pathetic|trash|garbage)\b`},
+			{ID: "hostile", Category: CategoryHarassment, Score: 0.35, Phrases: []string{"shut up", "nobody asked", "get lost"}},
+			{ID: "dehumanize", Category: CategoryHate, Score: 0.7, Pattern: `\b(?:subhuman|vermin|cockroaches|animals)\b.*\b(?:they|them|those people)\b|\b(?:they|those people) are (?:subhuman|vermin|cockroaches|animals)\b`},
+			{ID: "sexual", Category: CategorySexual, Score: 0.6, Phrases: []string{"porn", "xxx", "explicit sex", "nudes"}},
+			{ID: "drug_sale", Category: CategoryIllicit, Score: 0.7, Pattern: `\b(?:buy|selling|for sale|dm for)\b.*\b(?:cocaine|heroin|meth|weed|pills)\b`},
+			{ID: "promo", Category: CategorySpam, Score: 0.4, Phrases: []string{"click here", "free money", "dm me", "follow me", "check my profile"}},
+		}},
+		{Language: "ar", Rules: []Rule{
+			{ID: "threat_kill", Category: CategoryThreat, Score: 0.95, Phrases: []string{"سأقتلك", "ساقتلك", "راح اقتلك", "بقتلك"}},
+			{ID: "self_harm_incite", Category: CategorySelfHarm, Score: 0.95, Phrases: []string{"اقتل نفسك", "انتحر"}},
+			{ID: "self_harm", Category: CategorySelfHarm, Score: 0.6, Phrases: []string{"ابي اموت", "أريد أن أموت", "اريد ان اموت"}},
+			{ID: "insult", Category: CategoryHarassment, Score: 0.4, Phrases: []string{"غبي", "تافه", "حمار"}},
+			{ID: "insult_demeaning", Category: CategoryHarassment, Score: 0.45, Phrases: []string{"حقير", "حثالة", "وسخ"}},
+			{ID: "hostile", Category: CategoryHarassment, Score: 0.35, Phrases: []string{"اسكت", "انقلع"}},
+			{ID: "sexual", Category: CategorySexual, Score: 0.6, Phrases: []string{"إباحية", "اباحيه", "اباحية", "سكس"}},
+			{ID: "drug_sale", Category: CategoryIllicit, Score: 0.7, Phrases: []string{"مخدرات للبيع", "حبوب للبيع"}},
+			{ID: "promo", Category: CategorySpam, Score: 0.4, Phrases: []string{"تابعني", "اضغط هنا", "ربح سريع"}},
+		}},
+	}
+}
@@ -0,0 +1,91 @@
+package toxicity
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// HTTPScorer asks a remote model for category scores:
+//
+//	POST {BaseURL}/v1/moderation/toxicity {"text": "...", "languages": ["ar"]}
+//	→ {"scores": {"harassment": 0.82, ...}}
+//
+// Unknown categories in the response are ignored.
+type HTTPScorer struct {
+	BaseURL string
+	Token   string
+	Client  *http.Client
+}
+
+func (s *HTTPScorer) Name() string { return "http_scorer" }
+
+func (s *HTTPScorer) Classify(ctx context.Context, in Input) ([]Signal, error) {
+	body, err := json.Marshal(map[string]interface{}{"text": in.Text, "languages": in.Languages})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(s.BaseURL, "/")+"/v1/moderation/toxicity", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.Token)
+	}
+	client := s.Client
+	if client == nil {
+		// Comments wait on this call; a slow scorer degrades to the lexicon.
+		client = &http.Client{Timeout: 2 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("toxicity scorer returned %d", resp.StatusCode)
+	}
+	var parsed struct {
+		Scores map[string]float64 `json:"scores"`
+	}
+	if err := json.Unmarshal(raw, &parsed); err != nil {
+		return nil, fmt.Errorf("decode toxicity scores: %w", err)
+	}
+	return scoresToSignals(parsed.Scores, s.Name()), nil
+}
+
+// StubScorer stands in for the HTTP scorer locally and in tests: it returns
+// Scores for every comment, or Err.
+type StubScorer struct {
+	Scores map[string]float64
+	Err    error
+}
+
+func (s *StubScorer) Name() string { return "stub_scorer" }
+
+func (s *StubScorer) Classify(context.Context, Input) ([]Signal, error) {
+	if s.Err != nil {
+		return nil, s.Err
+	}
+	return scoresToSignals(s.Scores, s.Name()), nil
+}
+
+func scoresToSignals(scores map[string]float64, source string) []Signal {
+	var out []Signal
+	for _, cat := range Categories {
+		if score, ok := scores[string(cat)]; ok && score > 0 {
+			out = append(out, Signal{Category: cat, Score: score, Source: source})
+		}
+	}
+	return out
+}
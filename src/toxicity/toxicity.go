@@ -0,0 +1,267 @@
+// Package toxicity scores comment text by category (threat, harassment,
+// hate, sexual, self_harm, illicit, spam) and turns the scores into an
+// allow/review/reject decision against per-tenant thresholds.
+//
+// Scores come from pluggable Classifiers: the local Lexicon (per-language
+// phrase and regex rule packs) always runs; an HTTPScorer (or the local
+// StubScorer) can be added. A classifier that fails is reported as
+// unavailable and the decision falls back to the others.
+package toxicity
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+type Category string
+
+const (
+	CategoryThreat     Category = "threat"
+	CategoryHarassment Category = "harassment"
+	CategoryHate       Category = "hate"
+	CategorySexual     Category = "sexual"
+	CategorySelfHarm   Category = "self_harm"
+	CategoryIllicit    Category = "illicit"
+	CategorySpam       Category = "spam"
+)
+
+// Categories lists every category in report order.
+var Categories = []Category{
+	CategoryThreat, CategoryHarassment, CategoryHate, CategorySexual,
+	CategorySelfHarm, CategoryIllicit, CategorySpam,
+}
+
+func ValidCategory(c Category) bool {
+	for _, known := range Categories {
+		if c == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Input is one comment. Normalized and Languages are filled by Run.
+type Input struct {
+	Text       string
+	Normalized string
+	Languages  []string
+}
+
+// Signal is one piece of evidence: a classifier's score for a category and,
+// for rule-based classifiers, the rule that fired.
+type Signal struct {
+	Category Category `json:"category"`
+	Score    float64  `json:"score"`
+	Source   string   `json:"source"`
+	Rule     string   `json:"rule,omitempty"`
+}
+
+type Classifier interface {
+	Name() string
+	Classify(ctx context.Context, in Input) ([]Signal, error)
+}
+
+// Result combines every classifier's signals. Scores per category are the
+// noisy-or of the signals, so independent weak hits add up.
+type Result struct {
+	Scores      map[Category]float64 `json:"scores"`
+	Signals     []Signal             `json:"signals,omitempty"`
+	Classifiers []string             `json:"classifiers"`
+	Unavailable []string             `json:"unavailable,omitempty"`
+}
+
+// Max returns the highest category score.
+func (r Result) Max() float64 {
+	best := 0.0
+	for _, s := range r.Scores {
+		best = math.Max(best, s)
+	}
+	return best
+}
+
+// Run normalizes text and runs the classifiers in order.
+func Run(ctx context.Context, text string, classifiers ...Classifier) Result {
+	normalized := Normalize(text)
+	in := Input{Text: text, Normalized: normalized, Languages: Languages(normalized)}
+	out := Result{Scores: map[Category]float64{}}
+	miss := map[Category]float64{}
+	for _, c := range classifiers {
+		if c == nil {
+			continue
+		}
+		signals, err := c.Classify(ctx, in)
+		if err != nil {
+			out.Unavailable = append(out.Unavailable, c.Name())
+			continue
+		}
+		out.Classifiers = append(out.Classifiers, c.Name())
+		for _, s := range signals {
+			if !ValidCategory(s.Category) || s.Score <= 0 {
+				continue
+			}
+			s.Score = math.Min(s.Score, 1)
+			if s.Source == "" {
+				s.Source = c.Name()
+			}
+			out.Signals = append(out.Signals, s)
+			if _, ok := miss[s.Category]; !ok {
+				miss[s.Category] = 1
+			}
+			miss[s.Category] *= 1 - s.Score
+		}
+	}
+	for cat, m := range miss {
+		out.Scores[cat] = round3(1 - m)
+	}
+	return out
+}
+
+func round3(v float64) float64 { return math.Round(v*1000) / 1000 }
+
+// Normalize makes rules resilient to harmless Unicode presentation variation:
+// NFKC, lower case, no Arabic tashkil/tatweel, collapsed whitespace.
+func Normalize(value string) string {
+	value = strings.ToLower(norm.NFKC.String(value))
+	value = strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Mn, r) || r == 'ـ' {
+			return -1
+		}
+		return r
+	}, value)
+	return strings.Join(strings.Fields(value), " ")
+}
+
+// Languages reports which rule-pack languages the text is written in, by
+// script: "ar" for Arabic letters, "en" for Latin. Mixed comments get both.
+func Languages(text string) []string {
+	var arabic, latin int
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Arabic, r) && unicode.IsLetter(r):
+			arabic++
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		}
+	}
+	var out []string
+	if arabic > 0 {
+		out = append(out, "ar")
+	}
+	if latin > 0 || arabic == 0 {
+		out = append(out, "en")
+	}
+	return out
+}
+
+type Outcome string
+
+const (
+	OutcomeAllow  Outcome = "allow"
+	OutcomeReview Outcome = "review"
+	OutcomeReject Outcome = "reject"
+)
+
+// Thresholds are a tenant's cut-offs. A category at or above Reject is
+// refused, at or above Hold goes to human review. A category missing from
+// Reject is never auto-rejected.
+type Thresholds struct {
+	Hold   map[Category]float64 `json:"hold"`
+	Reject map[Category]float64 `json:"reject"`
+}
+
+// DefaultThresholds holds anything fairly likely to be abusive and only
+// auto-rejects near-certain threats and hate.
+func DefaultThresholds() Thresholds {
+	hold := map[Category]float64{}
+	for _, c := range Categories {
+		hold[c] = 0.6
+	}
+	hold[CategorySelfHarm] = 0.5
+	return Thresholds{
+		Hold:   hold,
+		Reject: map[Category]float64{CategoryThreat: 0.9, CategoryHate: 0.95, CategorySpam: 0.95},
+	}
+}
+
+// Reason explains why a category moved the decision.
+type Reason struct {
+	Category  Category `json:"category"`
+	Score     float64  `json:"score"`
+	Threshold float64  `json:"threshold"`
+	Outcome   Outcome  `json:"outcome"`
+	Rules     []string `json:"rules,omitempty"`
+}
+
+type Decision struct {
+	Outcome Outcome  `json:"outcome"`
+	Reasons []Reason `json:"reasons,omitempty"`
+}
+
+// Primary is the highest-scoring reason behind the outcome, if any.
+func (d Decision) Primary() (Reason, bool) {
+	for _, r := range d.Reasons {
+		if r.Outcome == d.Outcome {
+			return r, true
+		}
+	}
+	return Reason{}, false
+}
+
+// Decide applies thresholds to a result. Reasons are ordered by score.
+func Decide(result Result, t Thresholds) Decision {
+	d := Decision{Outcome: OutcomeAllow}
+	for cat, score := range result.Scores {
+		var r Reason
+		if limit, ok := t.Reject[cat]; ok && limit > 0 && score >= limit {
+			r = Reason{Category: cat, Score: score, Threshold: limit, Outcome: OutcomeReject}
+			d.Outcome = OutcomeReject
+		} else if limit, ok := t.Hold[cat]; ok && limit > 0 && score >= limit {
+			r = Reason{Category: cat, Score: score, Threshold: limit, Outcome: OutcomeReview}
+			if d.Outcome == OutcomeAllow {
+				d.Outcome = OutcomeReview
+			}
+		} else {
+			continue
+		}
+		for _, s := range result.Signals {
+			if s.Category == cat && s.Rule != "" {
+				r.Rules = append(r.Rules, s.Rule)
+			}
+		}
+		d.Reasons = append(d.Reasons, r)
+	}
+	sort.Slice(d.Reasons, func(i, j int) bool {
+		if d.Reasons[i].Score != d.Reasons[j].Score {
+			return d.Reasons[i].Score > d.Reasons[j].Score
+		}
+		return d.Reasons[i].Category < d.Reasons[j].Category
+	})
+	return d
+}
+
+const (
+	// MinLearningSamples is how many resolved reviews a category needs before
+	// its hold threshold moves.
+	MinLearningSamples = 20
+	// TargetFalsePositiveRate is the share of held comments moderators may
+	// release before the hold threshold rises.
+	TargetFalsePositiveRate = 0.25
+)
+
+// LearnedHold adjusts a hold threshold from review outcomes: overturned
+// holds (released by a moderator) are false positives. Above the target rate
+// the threshold rises, below it it falls, by at most +0.2/-0.1.
+func LearnedHold(base float64, overturned, confirmed int64) float64 {
+	n := overturned + confirmed
+	if n < MinLearningSamples || base <= 0 {
+		return base
+	}
+	fp := float64(overturned) / float64(n)
+	shift := math.Max(-0.1, math.Min(0.2, (fp-TargetFalsePositiveRate)*0.4))
+	return round3(math.Max(0.3, math.Min(0.95, base+shift)))
+}
@@ -0,0 +1,138 @@
+package toxicity
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func defaultLexicon(t *testing.T) *Lexicon {
+	t.Helper()
+	l, err := NewLexicon(DefaultRulePacks()...)
+	if err != nil {
+		t.Fatalf("NewLexicon: %v", err)
+	}
+	return l
+}
+
+func TestLexiconDecisions(t *testing.T) {
+	lexicon := defaultLexicon(t)
+	cases := []struct {
+		name     string
+		text     string
+		outcome  Outcome
+		category Category
+	}{
+		{"clean English", "A clear and useful explainer.", OutcomeAllow, ""},
+		{"clean Arabic", "تحليل هادئ ومفيد للخبر.", OutcomeAllow, ""},
+		{"single insult stays below hold", "That take is stupid.", OutcomeAllow, ""},
+		{"stacked insults hold", "You are an idiot, a pathetic loser. Shut up.", OutcomeReview, CategoryHarassment},
+		{"Arabic insults with tashkil hold", "غَبِيّ وحقير وتافه", OutcomeReview, CategoryHarassment},
+		{"code-switched threat rejects", "والله i will kill you", OutcomeReject, CategoryThreat},
+		{"self harm holds", "some days I want to die", OutcomeReview, CategorySelfHarm},
+		{"drug sale holds", "selling cheap pills, dm me", OutcomeReview, CategoryIllicit},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := Decide(Run(context.Background(), tc.text, lexicon), DefaultThresholds())
+			if d.Outcome != tc.outcome {
+				t.Fatalf("outcome = %s, want %s (%+v)", d.Outcome, tc.outcome, d)
+			}
+			if tc.category != "" {
+				if primary, ok := d.Primary(); !ok || primary.Category != tc.category || len(primary.Rules) == 0 {
+					t.Fatalf("primary = %+v, want %s with rules", primary, tc.category)
+				}
+			}
+		})
+	}
+}
+
+func TestRunCombinesClassifiersAndDegrades(t *testing.T) {
+	lexicon := defaultLexicon(t)
+	scorer := &StubScorer{Scores: map[string]float64{"harassment": 0.5, "unknown": 0.9}}
+	r := Run(context.Background(), "stupid", lexicon, scorer)
+	// noisy-or of 0.4 (lexicon) and 0.5 (scorer)
+	if got := r.Scores[CategoryHarassment]; got != 0.7 {
+		t.Fatalf("harassment = %v, want 0.7", got)
+	}
+	if len(r.Classifiers) != 2 || len(r.Unavailable) != 0 {
+		t.Fatalf("classifiers = %v unavailable = %v", r.Classifiers, r.Unavailable)
+	}
+
+	r = Run(context.Background(), "stupid", lexicon, &StubScorer{Err: errors.New("down")})
+	if len(r.Unavailable) != 1 || r.Unavailable[0] != "stub_scorer" || r.Scores[CategoryHarassment] != 0.4 {
+		t.Fatalf("degraded result = %+v", r)
+	}
+}
+
+func TestHTTPScorer(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/moderation/toxicity" || r.Header.Get("Authorization") != "Bearer tok" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"scores":{"hate":0.97,"spam":0}}`))
+	}))
+	defer srv.Close()
+
+	signals, err := (&HTTPScorer{BaseURL: srv.URL + "/", Token: "tok"}).Classify(context.Background(), Input{Text: "x"})
+	if err != nil || len(signals) != 1 || signals[0].Category != CategoryHate || signals[0].Score != 0.97 {
+		t.Fatalf("signals = %+v, err = %v", signals, err)
+	}
+	if _, err := (&HTTPScorer{BaseURL: srv.URL}).Classify(context.Background(), Input{Text: "x"}); err == nil {
+		t.Fatal("expected error on 401")
+	}
+}
+
+func TestLoadRulePacks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	raw := `[{"language":"en","rules":[{"id":"brand","category":"spam","score":0.8,"phrases":["Cheap Watches"]}]}]`
+	if err := os.WriteFile(path, []byte(raw), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	packs, err := LoadRulePacks(path)
+	if err != nil {
+		t.Fatalf("LoadRulePacks: %v", err)
+	}
+	l, err := NewLexicon(packs...)
+	if err != nil {
+		t.Fatalf("NewLexicon: %v", err)
+	}
+	signals, _ := l.Classify(context.Background(), Input{Text: "cheap   watches here"})
+	if len(signals) != 1 || signals[0].Rule != "en:brand" {
+		t.Fatalf("signals = %+v", signals)
+	}
+
+	for _, bad := range []RulePack{
+		{Language: "en", Rules: []Rule{{ID: "x", Category: "gore", Score: 0.5, Phrases: []string{"a"}}}},
+		{Language: "en", Rules: []Rule{{ID: "x", Category: CategorySpam, Score: 1.5, Phrases: []string{"a"}}}},
+		{Language: "en", Rules: []Rule{{ID: "x", Category: CategorySpam, Score: 0.5, Pattern: "("}}},
+		{Language: "en", Rules: []Rule{{ID: "x", Category: CategorySpam, Score: 0.5}}},
+	} {
+		if _, err := NewLexicon(bad); err == nil {
+			t.Fatalf("expected error for %+v", bad.Rules[0])
+		}
+	}
+}
+
+func TestLearnedHold(t *testing.T) {
+	cases := []struct {
+		name                  string
+		overturned, confirmed int64
+		want                  float64
+	}{
+		{"too few samples", 19, 0, 0.6},
+		{"on target", 5, 15, 0.6},
+		{"mostly overturned raises", 20, 0, 0.8},
+		{"all confirmed lowers", 0, 40, 0.5},
+	}
+	for _, tc := range cases {
+		if got := LearnedHold(0.6, tc.overturned, tc.confirmed); got != tc.want {
+			t.Errorf("%s: LearnedHold = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
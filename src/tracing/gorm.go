@@ -0,0 +1,90 @@
+package tracing
+
+import (
+	"errors"
+	"regexp"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const (
+	gormSpanKey    = "tracing:span"
+	maxTracedQuery = 2048
+)
+
+// sqlLiteral matches what sanitizeSQL rewrites: quoted strings and bare
+// numbers. Bind placeholders ($1) are matched so they can be kept.
+var sqlLiteral = regexp.MustCompile(`\$\d+|'(?:[^']|'')*'|\b\d+(?:\.\d+)?\b`)
+
+// sanitizeSQL replaces literals in a statement with ?. GORM binds values as
+// placeholders, but Raw/Exec callers sometimes inline them.
+func sanitizeSQL(sql string) string {
+	sql = sqlLiteral.ReplaceAllStringFunc(sql, func(match string) string {
+		if match[0] == '$' {
+			return match
+		}
+		return "?"
+	})
+	if len(sql) > maxTracedQuery {
+		sql = sql[:maxTracedQuery] + "…"
+	}
+	return sql
+}
+
+// InstallGORMCallbacks records a span per statement. Only statements issued
+// under a sampled parent are recorded: background workers run without one and
+// would otherwise emit a root trace per query.
+func InstallGORMCallbacks(db *gorm.DB) {
+	tracer := otel.Tracer(instrumentationName)
+	before := func(operation string) func(*gorm.DB) {
+		return func(tx *gorm.DB) {
+			ctx := tx.Statement.Context
+			if ctx == nil || !trace.SpanFromContext(ctx).SpanContext().IsValid() {
+				return
+			}
+			name := "gorm." + operation
+			if tx.Statement.Table != "" {
+				name += " " + tx.Statement.Table
+			}
+			ctx, span := tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient))
+			tx.Statement.Context = ctx
+			tx.InstanceSet(gormSpanKey, span)
+		}
+	}
+	after := func(tx *gorm.DB) {
+		value, ok := tx.InstanceGet(gormSpanKey)
+		if !ok {
+			return
+		}
+		span := value.(trace.Span)
+		span.SetAttributes(
+			attribute.String("db.system.name", "postgresql"),
+			attribute.String("db.collection.name", tx.Statement.Table),
+			attribute.String("db.query.text", sanitizeSQL(tx.Statement.SQL.String())),
+			attribute.Int64("db.rows_affected", tx.Statement.RowsAffected),
+		)
+		if err := tx.Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+
+	callbacks := db.Callback()
+	callbacks.Create().Before("gorm:create").Register("tracing:before_create", before("create"))
+	callbacks.Create().After("gorm:create").Register("tracing:after_create", after)
+	callbacks.Query().Before("gorm:query").Register("tracing:before_query", before("query"))
+	callbacks.Query().After("gorm:query").Register("tracing:after_query", after)
+	callbacks.Update().Before("gorm:update").Register("tracing:before_update", before("update"))
+	callbacks.Update().After("gorm:update").Register("tracing:after_update", after)
+	callbacks.Delete().Before("gorm:delete").Register("tracing:before_delete", before("delete"))
+	callbacks.Delete().After("gorm:delete").Register("tracing:after_delete", after)
+	callbacks.Row().Before("gorm:row").Register("tracing:before_row", before("row"))
+	callbacks.Row().After("gorm:row").Register("tracing:after_row", after)
+	callbacks.Raw().Before("gorm:raw").Register("tracing:before_raw", before("raw"))
+	callbacks.Raw().After("gorm:raw").Register("tracing:after_raw", after)
+}
@@ -0,0 +1,114 @@
+// Package tracing wires OpenTelemetry: spans for Gin handlers, GORM
+// statements issued under a request, and the outbound clients that call the
+// owner services, with W3C trace-context propagated both ways. Exporting is
+// off unless OTEL_TRACES_EXPORTER selects otlp or console; the standard
+// OTEL_* variables configure the endpoint, headers, sampler and resource.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultServiceName  = "content-management-system"
+	instrumentationName = "content-management-system"
+)
+
+var enabled atomic.Bool
+
+// Setup installs the tracer provider and the W3C propagators selected by
+// OTEL_TRACES_EXPORTER (otlp, console/stdout, or none — the default). The
+// returned shutdown flushes buffered spans; it is a no-op when tracing is off.
+func Setup(ctx context.Context, attrs ...attribute.KeyValue) (func(context.Context) error, error) {
+	noop := func(context.Context) error { return nil }
+	var exporter sdktrace.SpanExporter
+	switch kind := strings.ToLower(strings.TrimSpace(os.Getenv("OTEL_TRACES_EXPORTER"))); kind {
+	case "", "none":
+		return noop, nil
+	case "otlp":
+		var err error
+		if exporter, err = otlptracehttp.New(ctx); err != nil {
+			return noop, fmt.Errorf("otlp trace exporter: %w", err)
+		}
+	case "console", "stdout":
+		var err error
+		if exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint()); err != nil {
+			return noop, fmt.Errorf("stdout trace exporter: %w", err)
+		}
+	default:
+		return noop, fmt.Errorf("OTEL_TRACES_EXPORTER must be otlp, console or none, not %q", kind)
+	}
+
+	// OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES override the defaults.
+	res, err := resource.Merge(
+		resource.NewSchemaless(append([]attribute.KeyValue{attribute.String("service.name", defaultServiceName)}, attrs...)...),
+		resource.Environment(),
+	)
+	if err != nil {
+		return noop, fmt.Errorf("trace resource: %w", err)
+	}
+	// The sampler defaults to parentbased_always_on and follows
+	// OTEL_TRACES_SAMPLER / OTEL_TRACES_SAMPLER_ARG.
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	enabled.Store(true)
+	return provider.Shutdown, nil
+}
+
+// Enabled reports whether Setup installed an exporting provider.
+func Enabled() bool {
+	return enabled.Load()
+}
+
+// untracedPaths are probe and scrape routes that would otherwise dominate
+// every trace backend.
+var untracedPaths = map[string]bool{"/live": true, "/health": true, "/metrics": true}
+
+// Middleware starts a server span per request, named by method and Gin
+// route template and continuing any traceparent the caller sent.
+func Middleware() gin.HandlerFunc {
+	return otelgin.Middleware(defaultServiceName, otelgin.WithFilter(func(r *http.Request) bool {
+		return !untracedPaths[r.URL.Path]
+	}))
+}
+
+// RequestDB binds db to the request's trace so handler statements become
+// child spans. Cancellation is detached: handlers hand the db to goroutines
+// that outlive the response, and those must not fail when it is written.
+func RequestDB(c *gin.Context, db *gorm.DB) *gorm.DB {
+	if !Enabled() {
+		return db
+	}
+	return db.WithContext(context.WithoutCancel(c.Request.Context()))
+}
+
+// Transport wraps base (http.DefaultTransport when nil) with client spans
+// and traceparent injection. Use it only for clients that call CMS's own
+// owner services; third-party callbacks (WebSub subscribers, webhooks) must
+// not receive our trace context.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return otelhttp.NewTransport(base, otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+		return r.Method + " " + r.URL.Host
+	}))
+}
@@ -0,0 +1,144 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+const incomingTraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+
+func installRecorder(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	enabled.Store(true)
+	t.Cleanup(func() {
+		enabled.Store(false)
+		_ = provider.Shutdown(context.Background())
+	})
+	return exporter
+}
+
+func TestRequestSpansCarryTraceToDBAndOwners(t *testing.T) {
+	exporter := installRecorder(t)
+	gin.SetMode(gin.TestMode)
+
+	var outbound string
+	owner := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		outbound = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer owner.Close()
+	client := &http.Client{Transport: Transport(nil)}
+
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sqlDB.Close()
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	InstallGORMCallbacks(db)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "content_items" WHERE status = 'PUBLISHED'`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	router := gin.New()
+	router.Use(Middleware())
+	router.GET("/api/v1/content/:id", func(c *gin.Context) {
+		var count int64
+		RequestDB(c, db).Table("content_items").Where("status = 'PUBLISHED'").Count(&count)
+		req, _ := http.NewRequestWithContext(c.Request.Context(), http.MethodGet, owner.URL, nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Error(err)
+		} else {
+			resp.Body.Close()
+		}
+		c.Status(http.StatusOK)
+	})
+	router.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/content/abc", nil)
+	req.Header.Set("traceparent", "00-"+incomingTraceID+"-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(outbound, incomingTraceID) {
+		t.Fatalf("owner call traceparent = %q, want trace %s", outbound, incomingTraceID)
+	}
+
+	names := map[string]string{}
+	for _, span := range exporter.GetSpans() {
+		if span.SpanContext.TraceID().String() != incomingTraceID {
+			t.Fatalf("span %q left the incoming trace", span.Name)
+		}
+		names[span.Name] = ""
+		for _, attr := range span.Attributes {
+			if attr.Key == "db.query.text" {
+				names[span.Name] = attr.Value.AsString()
+			}
+		}
+	}
+	if _, ok := names["GET /api/v1/content/:id"]; !ok {
+		t.Fatalf("missing handler span: %v", names)
+	}
+	if _, ok := names["GET "+strings.TrimPrefix(owner.URL, "http://")]; !ok {
+		t.Fatalf("missing owner client span: %v", names)
+	}
+	if got := names["gorm.query content_items"]; got != `SELECT count(*) FROM "content_items" WHERE status = ?` {
+		t.Fatalf("db span statement = %q (spans %v)", got, names)
+	}
+	if _, ok := names["GET /health"]; ok {
+		t.Fatal("health probes must not be traced")
+	}
+}
+
+func TestGORMSkipsStatementsWithoutParent(t *testing.T) {
+	exporter := installRecorder(t)
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sqlDB.Close()
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	InstallGORMCallbacks(db)
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM feed_sessions`)).WillReturnResult(sqlmock.NewResult(0, 2))
+
+	if err := db.Exec(`DELETE FROM feed_sessions`).Error; err != nil {
+		t.Fatal(err)
+	}
+	if spans := exporter.GetSpans(); len(spans) != 0 {
+		t.Fatalf("worker statements must not start root traces, got %d spans", len(spans))
+	}
+}
+
+func TestSanitizeSQL(t *testing.T) {
+	got := sanitizeSQL(`SELECT * FROM "t1" WHERE email = 'a''b@x.io' AND score > 0.75 AND id = $1 LIMIT 20`)
+	want := `SELECT * FROM "t1" WHERE email = ? AND score > ? AND id = $1 LIMIT ?`
+	if got != want {
+		t.Fatalf("sanitizeSQL = %q, want %q", got, want)
+	}
+}
@@ -37,22 +37,20 @@ func AdminAuthMiddleware(_ *gorm.DB) gin.HandlerFunc {
 			return
 		}
 
-		secret, err := GetJWTSecret()
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"message": "Internal server error", "code": "INTERNAL_SERVER_ERROR"})
-			c.Abort()
-			return
-		}
-
-		claims, err := ParseJWT(tokenString, secret)
+		claims, err := VerifyJWT(c.Request.Context(), tokenString)
 		if err != nil {
 			switch err {
+			case ErrJWKSUnavailable:
+				c.JSON(http.StatusServiceUnavailable, gin.H{"message": "Token verification keys unavailable", "code": "JWKS_UNAVAILABLE"})
 			case ErrTokenExpired:
 				c.JSON(http.StatusUnauthorized, gin.H{"message": "Token has expired", "code": "TOKEN_EXPIRED"})
 			case ErrTokenSignatureInvalid:
 				c.JSON(http.StatusUnauthorized, gin.H{"message": "Invalid token signature", "code": "INVALID_SIGNATURE"})
-			default:
+			case ErrTokenInvalid:
 				c.JSON(http.StatusUnauthorized, gin.H{"message": "Invalid authentication token", "code": "INVALID_TOKEN"})
+			default:
+				// Verification is misconfigured (no secret / no key source).
+				c.JSON(http.StatusInternalServerError, gin.H{"message": "Internal server error", "code": "INTERNAL_SERVER_ERROR"})
 			}
 			c.Abort()
 			return
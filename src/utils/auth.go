@@ -37,13 +37,15 @@ type FeedRecoveryReauthClaims struct {
 }
 
 func ParseFeedRecoveryReauthProof(tokenString string, secret []byte) (*FeedRecoveryReauthClaims, error) {
+	return parseFeedRecoveryReauthProof(tokenString, hmacKeyfunc(secret), hmacJWTMethods)
+}
+
+func parseFeedRecoveryReauthProof(tokenString string, keyfunc jwt.Keyfunc, methods []string) (*FeedRecoveryReauthClaims, error) {
 	claims := &FeedRecoveryReauthClaims{}
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, ErrTokenInvalid
-		}
-		return secret, nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, claims, keyfunc, jwt.WithValidMethods(methods))
+	if err != nil && errors.Is(err, ErrJWKSUnavailable) {
+		return nil, ErrJWKSUnavailable
+	}
 	if err != nil || !token.Valid || !isAllowedIssuer(claims.Issuer) || claims.Subject == "" || claims.ID == "" || claims.Purpose != "feed_recovery" || claims.AuthTime == 0 {
 		return nil, ErrTokenInvalid
 	}
@@ -143,16 +145,20 @@ func GetConfiguredPublicTenantID() (string, error) {
 	return tenantID, nil
 }
 
+// ParseJWT validates an HS256 token against an explicit shared secret.
+// Request paths use VerifyJWT, which honours JWT_VERIFICATION_MODE.
 func ParseJWT(tokenString string, secret []byte) (*JWTClaims, error) {
+	return parseJWTClaims(tokenString, hmacKeyfunc(secret), hmacJWTMethods)
+}
+
+func parseJWTClaims(tokenString string, keyfunc jwt.Keyfunc, methods []string) (*JWTClaims, error) {
 	claims := &JWTClaims{}
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return secret, nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, claims, keyfunc, jwt.WithValidMethods(methods))
 
 	if err != nil {
+		if errors.Is(err, ErrJWKSUnavailable) {
+			return nil, ErrJWKSUnavailable
+		}
 		if errors.Is(err, jwt.ErrTokenExpired) {
 			return nil, ErrTokenExpired
 		}
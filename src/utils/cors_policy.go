@@ -0,0 +1,261 @@
+package utils
+
+import (
+	"content-management-system/src/models"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CORSRouteGroup is the browser-facing surface a path belongs to. Each group
+// has its own origin allowlist.
+type CORSRouteGroup string
+
+const (
+	CORSGroupPublic   CORSRouteGroup = "public"   // public feeds, syndication, probes
+	CORSGroupConsumer CORSRouteGroup = "consumer" // the rest of /api/v1 (reader apps)
+	CORSGroupAdmin    CORSRouteGroup = "admin"    // /admin (Platform-Console)
+	CORSGroupInternal CORSRouteGroup = "internal" // /internal, /metrics: never browser-facing
+)
+
+// CORSRouteGroups are the groups an origin can be registered for.
+var CORSRouteGroups = []CORSRouteGroup{CORSGroupPublic, CORSGroupConsumer, CORSGroupAdmin}
+
+// corsPublicPrefixes are the /api/v1 paths served to anonymous readers and
+// feed readers rather than to signed-in apps.
+var corsPublicPrefixes = []string{"/api/v1/feed/", "/api/v1/stories/", "/api/v1/collections/shared/"}
+
+// CORSGroupForPath classifies a request path.
+func CORSGroupForPath(path string) CORSRouteGroup {
+	switch {
+	case path == "/admin" || strings.HasPrefix(path, "/admin/"):
+		return CORSGroupAdmin
+	case path == "/internal" || strings.HasPrefix(path, "/internal/") || path == "/metrics":
+		return CORSGroupInternal
+	case strings.HasPrefix(path, "/api/v1/"):
+		for _, prefix := range corsPublicPrefixes {
+			if strings.HasPrefix(path, prefix) {
+				return CORSGroupPublic
+			}
+		}
+		return CORSGroupConsumer
+	}
+	return CORSGroupPublic
+}
+
+// CORSGroupPolicy is one group's allowlist. AllowAll answers any origin with
+// "*"; otherwise Origins plus the origins the request's tenant registered for
+// the group are echoed back.
+type CORSGroupPolicy struct {
+	AllowAll bool
+	Origins  []string
+	// Credentials sends Access-Control-Allow-Credentials so a console on an
+	// allowed origin can use cookies. It requires an explicit allowlist.
+	Credentials bool
+}
+
+// CORSPolicy is the per-group configuration read from CORS_*_ORIGINS and
+// CORS_*_CREDENTIALS.
+type CORSPolicy map[CORSRouteGroup]CORSGroupPolicy
+
+// LoadCORSPolicy reads CORS_PUBLIC_ORIGINS, CORS_CONSUMER_ORIGINS and
+// CORS_ADMIN_ORIGINS (comma-separated origins or "*"). An unset variable keeps
+// the historical allow-all behavior; a set but empty one admits only origins
+// registered in tenant_cors_origins. CORS_CONSUMER_CREDENTIALS and
+// CORS_ADMIN_CREDENTIALS enable credentialed requests for an explicit list.
+func LoadCORSPolicy() (CORSPolicy, error) {
+	policy := CORSPolicy{}
+	for _, group := range CORSRouteGroups {
+		name := "CORS_" + strings.ToUpper(string(group))
+		raw, set := os.LookupEnv(name + "_ORIGINS")
+		if !set {
+			raw = "*"
+		}
+		var groupPolicy CORSGroupPolicy
+		for _, value := range strings.Split(raw, ",") {
+			value = strings.TrimSpace(value)
+			if value == "" {
+				continue
+			}
+			if value == "*" {
+				groupPolicy.AllowAll = true
+				continue
+			}
+			origin, err := NormalizeCORSOrigin(value)
+			if err != nil {
+				return nil, fmt.Errorf("%s_ORIGINS: %w", name, err)
+			}
+			groupPolicy.Origins = append(groupPolicy.Origins, origin)
+		}
+		if groupPolicy.AllowAll && len(groupPolicy.Origins) > 0 {
+			return nil, fmt.Errorf("%s_ORIGINS: \"*\" cannot be combined with explicit origins", name)
+		}
+		if group != CORSGroupPublic {
+			if raw := strings.TrimSpace(os.Getenv(name + "_CREDENTIALS")); raw != "" {
+				credentials, err := strconv.ParseBool(raw)
+				if err != nil {
+					return nil, fmt.Errorf("%s_CREDENTIALS must be true or false", name)
+				}
+				groupPolicy.Credentials = credentials
+			}
+		}
+		if groupPolicy.Credentials && groupPolicy.AllowAll {
+			return nil, fmt.Errorf("%s_CREDENTIALS requires an explicit %s_ORIGINS allowlist, not \"*\"", name, name)
+		}
+		policy[group] = groupPolicy
+	}
+	return policy, nil
+}
+
+// NormalizeCORSOrigin accepts scheme://host[:port] and returns it lower-cased
+// as browsers send it in the Origin header.
+func NormalizeCORSOrigin(value string) (string, error) {
+	parsed, err := url.Parse(strings.TrimSpace(value))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return "", fmt.Errorf("origin %q must be http(s)://host[:port]", value)
+	}
+	if (parsed.Path != "" && parsed.Path != "/") || parsed.RawQuery != "" || parsed.Fragment != "" || parsed.User != nil {
+		return "", fmt.Errorf("origin %q must not carry a path, query or credentials", value)
+	}
+	return strings.ToLower(parsed.Scheme + "://" + parsed.Host), nil
+}
+
+// Describe summarizes the policy for the startup log.
+func (p CORSPolicy) Describe() string {
+	parts := []string{}
+	for _, group := range CORSRouteGroups {
+		groupPolicy := p[group]
+		if groupPolicy.AllowAll {
+			parts = append(parts, string(group)+"=*")
+			continue
+		}
+		part := fmt.Sprintf("%s=[%s]+tenant origins", group, strings.Join(groupPolicy.Origins, " "))
+		if groupPolicy.Credentials {
+			part += " with credentials"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ", ")
+}
+
+var (
+	corsAllowMethods  = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
+	corsAllowHeaders  = []string{"Origin", "Content-Length", "Content-Type", "Authorization", "Idempotency-Key"}
+	corsExposeHeaders = []string{"Content-Length"}
+)
+
+// CORSMiddleware applies the policy of the request's route group. It runs
+// before routing (preflights match no route), and before authentication:
+// preflights carry no credentials, so a registered origin is only admitted
+// for the tenant the request resolves to without one (DEFAULT_TENANT_ID, the
+// tenant this deployment serves). Another tenant's origins never apply.
+// Internal routes get no CORS headers at all.
+func CORSMiddleware(db *gorm.DB, policy CORSPolicy) gin.HandlerFunc {
+	origins := &tenantCORSOrigins{db: db}
+	handlers := map[CORSRouteGroup]gin.HandlerFunc{}
+	for _, group := range CORSRouteGroups {
+		handlers[group] = cors.New(corsConfig(group, policy[group], origins))
+	}
+	return func(c *gin.Context) {
+		handler, ok := handlers[CORSGroupForPath(c.Request.URL.Path)]
+		if !ok {
+			c.Next()
+			return
+		}
+		handler(c)
+	}
+}
+
+func corsConfig(group CORSRouteGroup, policy CORSGroupPolicy, tenants *tenantCORSOrigins) cors.Config {
+	config := cors.Config{
+		AllowMethods:     corsAllowMethods,
+		AllowHeaders:     corsAllowHeaders,
+		ExposeHeaders:    corsExposeHeaders,
+		AllowCredentials: policy.Credentials,
+		MaxAge:           12 * time.Hour,
+	}
+	if policy.AllowAll {
+		config.AllowAllOrigins = true
+		return config
+	}
+	config.AllowOrigins = policy.Origins
+	config.AllowOriginWithContextFunc = func(c *gin.Context, origin string) bool {
+		return tenants.allowed(requestTenant(c), group, origin)
+	}
+	return config
+}
+
+// tenantCORSOriginsTTL bounds how long an origin added or removed through
+// the admin API takes to reach other replicas.
+const tenantCORSOriginsTTL = time.Minute
+
+// tenantCORSOrigins caches tenant_cors_origins by tenant and group. A failed
+// refresh keeps the previous set.
+type tenantCORSOrigins struct {
+	db *gorm.DB
+
+	mu         sync.Mutex
+	loadedAt   time.Time
+	generation uint64
+	byTenant   map[string]map[CORSRouteGroup]map[string]bool
+}
+
+// tenantCORSGeneration is bumped by InvalidateTenantCORSOrigins so this
+// replica sees admin writes immediately.
+var tenantCORSGeneration atomic.Uint64
+
+// InvalidateTenantCORSOrigins drops this process's cached tenant origins.
+func InvalidateTenantCORSOrigins() {
+	tenantCORSGeneration.Add(1)
+}
+
+func (t *tenantCORSOrigins) allowed(tenant string, group CORSRouteGroup, origin string) bool {
+	if t == nil || t.db == nil {
+		return false
+	}
+	origin = strings.ToLower(origin)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.refresh(time.Now())
+	return t.byTenant[tenant][group][origin]
+}
+
+func (t *tenantCORSOrigins) refresh(now time.Time) {
+	generation := tenantCORSGeneration.Load()
+	if t.byTenant != nil && now.Sub(t.loadedAt) < tenantCORSOriginsTTL && t.generation == generation {
+		return
+	}
+	var rows []models.TenantCORSOrigin
+	if err := t.db.Select("tenant_id", "route_group", "origin").Find(&rows).Error; err != nil {
+		log.Printf("cors: tenant origins unavailable, keeping %d cached tenants: %v", len(t.byTenant), err)
+		if t.byTenant == nil {
+			t.byTenant = map[string]map[CORSRouteGroup]map[string]bool{}
+		}
+		t.loadedAt = now
+		return
+	}
+	byTenant := map[string]map[CORSRouteGroup]map[string]bool{}
+	for _, row := range rows {
+		group := CORSRouteGroup(row.RouteGroup)
+		if byTenant[row.TenantID] == nil {
+			byTenant[row.TenantID] = map[CORSRouteGroup]map[string]bool{}
+		}
+		if byTenant[row.TenantID][group] == nil {
+			byTenant[row.TenantID][group] = map[string]bool{}
+		}
+		byTenant[row.TenantID][group][row.Origin] = true
+	}
+	t.byTenant = byTenant
+	t.loadedAt = now
+	t.generation = generation
+}
@@ -0,0 +1,168 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestCORSGroupForPath(t *testing.T) {
+	for path, want := range map[string]CORSRouteGroup{
+		"/admin/sources":                    CORSGroupAdmin,
+		"/internal/jobs/user-content":       CORSGroupInternal,
+		"/metrics":                          CORSGroupInternal,
+		"/api/v1/feed/rss.xml":              CORSGroupPublic,
+		"/api/v1/collections/shared/x/feed": CORSGroupPublic,
+		"/api/v1/me/digest":                 CORSGroupConsumer,
+		"/api/v1/interactions":              CORSGroupConsumer,
+		"/health":                           CORSGroupPublic,
+		"/administrator":                    CORSGroupPublic,
+	} {
+		if got := CORSGroupForPath(path); got != want {
+			t.Errorf("CORSGroupForPath(%q) = %s, want %s", path, got, want)
+		}
+	}
+}
+
+func TestLoadCORSPolicy(t *testing.T) {
+	t.Setenv("CORS_ADMIN_ORIGINS", "https://Console.example.com/, https://ops.example.com:8443")
+	t.Setenv("CORS_ADMIN_CREDENTIALS", "true")
+	t.Setenv("CORS_CONSUMER_ORIGINS", "")
+	policy, err := LoadCORSPolicy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !policy[CORSGroupPublic].AllowAll {
+		t.Fatal("an unset group keeps allow-all")
+	}
+	if consumer := policy[CORSGroupConsumer]; consumer.AllowAll || len(consumer.Origins) != 0 {
+		t.Fatalf("an empty group admits tenant origins only: %+v", consumer)
+	}
+	admin := policy[CORSGroupAdmin]
+	if !admin.Credentials || len(admin.Origins) != 2 || admin.Origins[0] != "https://console.example.com" {
+		t.Fatalf("admin policy = %+v", admin)
+	}
+
+	t.Setenv("CORS_ADMIN_ORIGINS", "*")
+	if _, err := LoadCORSPolicy(); err == nil {
+		t.Fatal("credentials with \"*\" must be refused")
+	}
+	t.Setenv("CORS_ADMIN_ORIGINS", "https://console.example.com/app")
+	if _, err := LoadCORSPolicy(); err == nil {
+		t.Fatal("origins with a path must be refused")
+	}
+}
+
+func TestCORSMiddlewareAppliesGroupPolicies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sqlDB.Close()
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("DEFAULT_TENANT_ID", "default")
+	// Loaded once and cached across the requests below.
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "tenant_id","route_group","origin" FROM "tenant_cors_origins"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"tenant_id", "route_group", "origin"}).
+			AddRow("default", "admin", "https://tenant-console.example.com").
+			AddRow("default", "consumer", "https://reader.example.com").
+			AddRow("tenant-b", "admin", "https://tenant-b-console.example.com"))
+
+	policy := CORSPolicy{
+		CORSGroupPublic: {AllowAll: true},
+		CORSGroupAdmin:  {Origins: []string{"https://console.example.com"}, Credentials: true},
+	}
+	router := gin.New()
+	router.Use(CORSMiddleware(db, policy))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/admin/me", ok)
+	router.GET("/api/v1/feed/rss.xml", ok)
+	router.GET("/internal/queues", ok)
+
+	request := func(method, path, origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Origin", origin)
+		if method == http.MethodOptions {
+			req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := request(http.MethodOptions, "/admin/me", "https://console.example.com")
+	if rec.Code != http.StatusNoContent || rec.Header().Get("Access-Control-Allow-Origin") != "https://console.example.com" ||
+		rec.Header().Get("Access-Control-Allow-Credentials") != "true" {
+		t.Fatalf("console preflight: %d %v", rec.Code, rec.Header())
+	}
+	if rec := request(http.MethodGet, "/admin/me", "https://tenant-console.example.com"); rec.Header().Get("Access-Control-Allow-Origin") != "https://tenant-console.example.com" {
+		t.Fatalf("tenant-registered origin: %d %v", rec.Code, rec.Header())
+	}
+	if rec := request(http.MethodGet, "/admin/me", "https://reader.example.com"); rec.Code != http.StatusForbidden {
+		t.Fatalf("a consumer origin must not reach admin: %d", rec.Code)
+	}
+	if rec := request(http.MethodOptions, "/admin/me", "https://tenant-b-console.example.com"); rec.Code != http.StatusForbidden {
+		t.Fatalf("another tenant's origin must not reach this deployment's tenant: %d %v", rec.Code, rec.Header())
+	}
+	if rec := request(http.MethodGet, "/api/v1/feed/rss.xml", "https://anywhere.example"); rec.Header().Get("Access-Control-Allow-Origin") != "*" {
+		t.Fatalf("public feeds: %v", rec.Header())
+	}
+	if rec := request(http.MethodGet, "/internal/queues", "https://console.example.com"); rec.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Fatalf("internal routes must not answer CORS: %v", rec.Header())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTenantCORSOriginsAreScopedToTheirTenant(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sqlDB.Close()
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "tenant_id","route_group","origin" FROM "tenant_cors_origins"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"tenant_id", "route_group", "origin"}).
+			AddRow("tenant-a", "consumer", "https://a.example.com"))
+
+	router := gin.New()
+	// Stands in for whatever resolved the request's tenant before CORS runs.
+	router.Use(func(c *gin.Context) {
+		c.Set("tenant_id", c.GetHeader("X-Test-Tenant"))
+	})
+	router.Use(CORSMiddleware(db, CORSPolicy{CORSGroupConsumer: {}}))
+	router.GET("/api/v1/me", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	request := func(tenant string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/me", nil)
+		req.Header.Set("Origin", "https://a.example.com")
+		req.Header.Set("X-Test-Tenant", tenant)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+	if rec := request("tenant-a"); rec.Header().Get("Access-Control-Allow-Origin") != "https://a.example.com" {
+		t.Fatalf("tenant A's own origin: %d %v", rec.Code, rec.Header())
+	}
+	if rec := request("tenant-b"); rec.Code != http.StatusForbidden || rec.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Fatalf("tenant A's origin must be refused for tenant B: %d %v", rec.Code, rec.Header())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
@@ -88,3 +89,47 @@ func ParseCursorParams(cursorStr string, limitStr string) (*CursorPagination, er
 
 	return pagination, nil
 }
+
+// keysetCursorPayload is the decoded form of a keyset cursor: the sort
+// signature it was minted for and the last row's sort tuple (nil = NULL).
+type keysetCursorPayload struct {
+	Sort   string    `json:"s"`
+	Values []*string `json:"v"`
+}
+
+// EncodeKeysetCursor generalizes EncodeCursor to an arbitrary sort tuple. The
+// sort signature is embedded so a cursor minted under one ordering is rejected
+// under another. Like EncodeCursor, an empty tuple yields "" (no next page).
+func EncodeKeysetCursor(signature string, values []*string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	raw, err := json.Marshal(keysetCursorPayload{Sort: signature, Values: values})
+	if err != nil {
+		return ""
+	}
+	return base64.URLEncoding.EncodeToString(raw)
+}
+
+// DecodeKeysetCursor parses a cursor produced by EncodeKeysetCursor, checking
+// it belongs to the given sort signature and carries one value per column.
+func DecodeKeysetCursor(cursor, signature string, columns int) ([]*string, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	decoded, err := base64.URLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor encoding: %w", err)
+	}
+	var payload keysetCursorPayload
+	if err := json.Unmarshal(decoded, &payload); err != nil {
+		return nil, fmt.Errorf("invalid cursor format")
+	}
+	if payload.Sort != signature {
+		return nil, fmt.Errorf("cursor does not match the requested sort")
+	}
+	if len(payload.Values) != columns {
+		return nil, fmt.Errorf("invalid cursor format")
+	}
+	return payload.Values, nil
+}
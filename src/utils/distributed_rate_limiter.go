@@ -0,0 +1,150 @@
+package utils
+
+import (
+	"content-management-system/src/models"
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RateLimiter is a token bucket shared by every caller of a key: capacity
+// tokens, refilled continuously at capacity per window. Unlike
+// LoginRateLimiter, implementations may be backed by shared storage so limits
+// survive restarts and hold across replicas.
+type RateLimiter interface {
+	Take(ctx context.Context, key string, capacity int, window time.Duration, cost int) (RateLimitDecision, error)
+}
+
+// RateLimitDecision is the outcome of one Take, with what the RateLimit-*
+// headers report.
+type RateLimitDecision struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is how long until the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is how long until cost tokens are available (0 when allowed).
+	RetryAfter time.Duration
+}
+
+func newRateLimitDecision(allowed bool, tokens float64, capacity int, window time.Duration, cost int) RateLimitDecision {
+	rate := float64(capacity) / window.Seconds()
+	d := RateLimitDecision{
+		Allowed:   allowed,
+		Limit:     capacity,
+		Remaining: int(math.Max(0, math.Floor(tokens))),
+		Reset:     time.Duration((float64(capacity) - tokens) / rate * float64(time.Second)),
+	}
+	if !allowed {
+		d.RetryAfter = time.Duration((float64(cost) - tokens) / rate * float64(time.Second))
+	}
+	return d
+}
+
+// MemoryRateLimiter is the process-local implementation, for tests and
+// single-replica development.
+type MemoryRateLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*memoryBucket
+	now     func() time.Time
+}
+
+type memoryBucket struct {
+	tokens    float64
+	updatedAt time.Time
+	expiresAt time.Time
+}
+
+func NewMemoryRateLimiter() *MemoryRateLimiter {
+	return &MemoryRateLimiter{buckets: map[string]*memoryBucket{}, now: time.Now}
+}
+
+func (l *MemoryRateLimiter) Take(_ context.Context, key string, capacity int, window time.Duration, cost int) (RateLimitDecision, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	if len(l.buckets) > 10000 {
+		// Full buckets carry no state; dropping them bounds memory.
+		for k, b := range l.buckets {
+			if now.After(b.expiresAt) {
+				delete(l.buckets, k)
+			}
+		}
+	}
+	if cost > capacity {
+		return newRateLimitDecision(false, float64(capacity), capacity, window, cost), nil
+	}
+	rate := float64(capacity) / window.Seconds()
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &memoryBucket{tokens: float64(capacity), updatedAt: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(float64(capacity), b.tokens+now.Sub(b.updatedAt).Seconds()*rate)
+	b.updatedAt = now
+	b.expiresAt = now.Add(window)
+	allowed := b.tokens >= float64(cost)
+	if allowed {
+		b.tokens -= float64(cost)
+	}
+	return newRateLimitDecision(allowed, b.tokens, capacity, window, cost), nil
+}
+
+// PostgresRateLimiter keeps buckets in rate_limit_buckets. Refill, spend and
+// the admit decision happen in one upsert, timed by the database clock so
+// replicas with skewed clocks agree.
+type PostgresRateLimiter struct {
+	DB *gorm.DB
+}
+
+var rateLimitPurge struct {
+	sync.Mutex
+	last time.Time
+}
+
+const postgresTakeSQL = `
+INSERT INTO rate_limit_buckets AS b (bucket_key, tokens, allowed, updated_at, expires_at)
+VALUES (@key, CAST(@capacity AS double precision) - @cost, TRUE, now(), now() + make_interval(secs => @window))
+ON CONFLICT (bucket_key) DO UPDATE SET
+  allowed = LEAST(@capacity, b.tokens + EXTRACT(EPOCH FROM (now() - b.updated_at)) * @rate) >= @cost,
+  tokens = LEAST(@capacity, b.tokens + EXTRACT(EPOCH FROM (now() - b.updated_at)) * @rate)
+    - CASE WHEN LEAST(@capacity, b.tokens + EXTRACT(EPOCH FROM (now() - b.updated_at)) * @rate) >= @cost THEN CAST(@cost AS double precision) ELSE 0 END,
+  updated_at = now(),
+  expires_at = now() + make_interval(secs => @window)
+RETURNING tokens, allowed`
+
+func (l PostgresRateLimiter) Take(ctx context.Context, key string, capacity int, window time.Duration, cost int) (RateLimitDecision, error) {
+	if cost > capacity {
+		return newRateLimitDecision(false, float64(capacity), capacity, window, cost), nil
+	}
+	db := l.DB.WithContext(ctx)
+	now := time.Now()
+	rateLimitPurge.Lock()
+	purge := now.Sub(rateLimitPurge.last) >= time.Minute
+	if purge {
+		rateLimitPurge.last = now
+	}
+	rateLimitPurge.Unlock()
+	if purge {
+		db.Where("expires_at < now()").Delete(&models.RateLimitBucket{})
+	}
+
+	var row struct {
+		Tokens  float64
+		Allowed bool
+	}
+	err := db.Raw(postgresTakeSQL, map[string]interface{}{
+		"key":      key,
+		"capacity": float64(capacity),
+		"cost":     float64(cost),
+		"rate":     float64(capacity) / window.Seconds(),
+		"window":   window.Seconds(),
+	}).Scan(&row).Error
+	if err != nil {
+		return RateLimitDecision{}, err
+	}
+	return newRateLimitDecision(row.Allowed, row.Tokens, capacity, window, cost), nil
+}
@@ -0,0 +1,180 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestMemoryRateLimiterTokenBucket(t *testing.T) {
+	l := NewMemoryRateLimiter()
+	now := time.Unix(1_700_000_000, 0)
+	l.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if d, _ := l.Take(ctx, "k", 3, time.Minute, 1); !d.Allowed || d.Remaining != 2-i {
+			t.Fatalf("take %d: %+v", i, d)
+		}
+	}
+	d, _ := l.Take(ctx, "k", 3, time.Minute, 1)
+	if d.Allowed || d.RetryAfter != 20*time.Second || d.Reset != time.Minute {
+		t.Fatalf("empty bucket: %+v", d)
+	}
+	// One token refills every window/limit.
+	now = now.Add(20 * time.Second)
+	if d, _ := l.Take(ctx, "k", 3, time.Minute, 1); !d.Allowed || d.Remaining != 0 {
+		t.Fatalf("after refill: %+v", d)
+	}
+	if d, _ := l.Take(ctx, "other", 3, time.Minute, 1); !d.Allowed {
+		t.Fatalf("keys must be independent: %+v", d)
+	}
+	if d, _ := l.Take(ctx, "batch", 3, time.Minute, 4); d.Allowed {
+		t.Fatalf("cost above capacity must be denied: %+v", d)
+	}
+}
+
+func TestPostgresRateLimiterTake(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sqlDB.Close()
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rateLimitPurge.Lock()
+	rateLimitPurge.last = time.Now()
+	rateLimitPurge.Unlock()
+
+	take := regexp.QuoteMeta(`INSERT INTO rate_limit_buckets AS b`) + `(?s).*ON CONFLICT \(bucket_key\) DO UPDATE.*RETURNING tokens, allowed`
+	mock.ExpectQuery(take).
+		WithArgs("p:abc", 10.0, 1.0, 60.0, 10.0, sqlmock.AnyArg(), 1.0, 10.0, sqlmock.AnyArg(), 10.0, sqlmock.AnyArg(), 1.0, 1.0, 60.0).
+		WillReturnRows(sqlmock.NewRows([]string{"tokens", "allowed"}).AddRow(0.5, false))
+
+	d, err := PostgresRateLimiter{DB: db}.Take(context.Background(), "p:abc", 10, time.Minute, 1)
+	if err != nil {
+		t.Fatalf("Take: %v", err)
+	}
+	if d.Allowed || d.Remaining != 0 || d.RetryAfter != 3*time.Second {
+		t.Fatalf("decision = %+v", d)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRateLimitMiddlewareHeadersAndKeys(t *testing.T) {
+	limiter := NewMemoryRateLimiter()
+	previous := rateLimiterFor
+	rateLimiterFor = func(*gin.Context) RateLimiter { return limiter }
+	defer func() { rateLimiterFor = previous }()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/submit", func(c *gin.Context) {
+		if user := c.GetHeader("X-Test-User"); user != "" {
+			c.Set("user_id", user)
+		}
+		c.Next()
+	}, RateLimitMiddleware("content.transcribe"), func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	send := func(user string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/submit", nil)
+		req.Header.Set("X-Test-User", user)
+		router.ServeHTTP(w, req)
+		return w
+	}
+	for i := 0; i < 5; i++ {
+		if w := send("u1"); w.Code != http.StatusNoContent {
+			t.Fatalf("request %d: %d", i, w.Code)
+		}
+	}
+	w := send("u1")
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "720" {
+		t.Fatalf("over limit: %d retry-after=%q", w.Code, w.Header().Get("Retry-After"))
+	}
+	if w.Header().Get("RateLimit-Limit") != "5" || w.Header().Get("RateLimit-Remaining") != "0" || w.Header().Get("RateLimit-Policy") != "5;w=3600" {
+		t.Fatalf("headers = %v", w.Header())
+	}
+	if w := send("u2"); w.Code != http.StatusNoContent || w.Header().Get("RateLimit-Remaining") != "4" {
+		t.Fatalf("second user: %d %v", w.Code, w.Header())
+	}
+	// A user-keyed policy does not limit requests without a user.
+	if w := send(""); w.Code != http.StatusNoContent || w.Header().Get("RateLimit-Limit") != "" {
+		t.Fatalf("anonymous: %d %v", w.Code, w.Header())
+	}
+}
+
+func TestRateLimitAnonymousSessionsShareTheIPBucket(t *testing.T) {
+	limiter := NewMemoryRateLimiter()
+	previous := rateLimiterFor
+	rateLimiterFor = func(*gin.Context) RateLimiter { return limiter }
+	defer func() { rateLimiterFor = previous }()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/report", RateLimitMiddleware("moderation.reports.create"), func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	send := func(session, ip string) int {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/report", nil)
+		req.Header.Set("X-Session-ID", session)
+		req.RemoteAddr = ip + ":1234"
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+	limit := MustRateLimitPolicy("moderation.reports.create").Limit
+	for i := 0; i < limit; i++ {
+		if code := send(fmt.Sprintf("s%d", i), "203.0.113.7"); code != http.StatusNoContent {
+			t.Fatalf("request %d: %d", i, code)
+		}
+	}
+	if code := send("fresh-session", "203.0.113.7"); code != http.StatusTooManyRequests {
+		t.Fatalf("rotated session on an exhausted IP = %d, want 429", code)
+	}
+	if code := send("fresh-session", "198.51.100.2"); code != http.StatusNoContent {
+		t.Fatalf("other IP = %d", code)
+	}
+}
+
+func TestTakeRateLimitKeyLeavesTheBodyToTheCaller(t *testing.T) {
+	limiter := NewMemoryRateLimiter()
+	previous := rateLimiterFor
+	rateLimiterFor = func(*gin.Context) RateLimiter { return limiter }
+	defer func() { rateLimiterFor = previous }()
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/ingest", nil)
+	if !TakeRateLimitKey(c, "telemetry.ingest", "bff-key", 600) {
+		t.Fatal("first batch must be allowed")
+	}
+	if TakeRateLimitKey(c, "telemetry.ingest", "bff-key", 1) {
+		t.Fatal("empty bucket must deny")
+	}
+	if c.IsAborted() || w.Body.Len() != 0 || c.Writer.Header().Get("Retry-After") == "" {
+		t.Fatalf("aborted=%v body=%q headers=%v", c.IsAborted(), w.Body.String(), c.Writer.Header())
+	}
+}
+
+func TestRateLimitPoliciesAreComplete(t *testing.T) {
+	seen := map[string]bool{}
+	for _, p := range RateLimitPolicies() {
+		if p.Name == "" || p.Limit <= 0 || p.Window <= 0 || seen[p.Name] {
+			t.Fatalf("bad or duplicate policy: %+v", p)
+		}
+		seen[p.Name] = true
+	}
+}
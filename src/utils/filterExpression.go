@@ -0,0 +1,502 @@
+package utils
+
+/*
+*
+* Filter Expression parses the structured `filter=` query param into a small
+* boolean AST and compiles it into a single parameterized WHERE clause. It
+* complements the per-field `?status=in:A,B` filters (which are ANDed) with OR
+* groups, negation, ranges, array membership and JSONB paths:
+*
+*   expr      := or
+*   or        := and ("OR" and)*
+*   and       := unary ("AND" unary)*
+*   unary     := "NOT" unary | "(" expr ")" | predicate
+*   predicate := field ":" operator [":" value]
+*   value     := word | "quoted string" | "[" value ("," value)* "]"
+*
+* e.g. (status:eq:READY OR status:eq:PROCESSING) AND NOT type:eq:NEWS AND
+*      published_at:between:[now-7d,now] AND topic_tags:has:economy AND
+*      metadata.source.lang:eq:ar
+*
+* Fields resolve only through the QueryConfig allowlists (FilterableFields,
+* ArrayFields, JSONFields + dotted path); operators are checked per field kind;
+* values on TimeFields accept relative expressions (now, now-7d, today) and
+* are resolved once at parse time. Every value and JSON path is a bound
+* parameter — only allowlisted column names are interpolated.
+*
+ */
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	maxFilterExpressionLength = 2048
+	maxFilterPredicates       = 32
+	maxFilterDepth            = 8
+)
+
+// FilterExprKind tags a FilterExpr node.
+type FilterExprKind string
+
+const (
+	FilterAnd       FilterExprKind = "and"
+	FilterOr        FilterExprKind = "or"
+	FilterNot       FilterExprKind = "not"
+	FilterPredicate FilterExprKind = "predicate"
+)
+
+// FilterExpr is a node of a parsed filter= expression.
+type FilterExpr struct {
+	Kind      FilterExprKind
+	Children  []*FilterExpr
+	Predicate *FilterCondition
+}
+
+// FilterCondition is one field:operator[:value] leaf. Path is set for JSONB
+// fields addressed with a dotted path; Values are already normalized (time
+// fields hold RFC3339 instants).
+type FilterCondition struct {
+	Field    string
+	Path     []string
+	Operator string
+	Values   []string
+}
+
+type filterFieldKind int
+
+const (
+	filterFieldScalar filterFieldKind = iota
+	filterFieldArray
+	filterFieldJSON
+)
+
+var filterOperatorsByKind = map[filterFieldKind]map[string]struct{}{
+	filterFieldScalar: {
+		"eq": {}, "ne": {}, "gt": {}, "gte": {}, "lt": {}, "lte": {},
+		"in": {}, "nin": {}, "contains": {}, "starts": {}, "ends": {},
+		"null": {}, "notnull": {}, "between": {},
+	},
+	filterFieldArray: {
+		"has": {}, "hasany": {}, "hasall": {}, "null": {}, "notnull": {},
+	},
+	// JSON path values compare as text, so ordering operators are excluded.
+	filterFieldJSON: {
+		"eq": {}, "ne": {}, "in": {}, "nin": {}, "contains": {}, "starts": {},
+		"ends": {}, "null": {}, "notnull": {},
+	},
+}
+
+var (
+	filterJSONPathSegment = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+	filterRelativeTime    = regexp.MustCompile(`^now(?:([+-])(\d+)([smhdw]))?$`)
+)
+
+// ─── Lexer ───────────────────────────────────────────────────
+
+type filterToken struct {
+	kind byte // 'w' word, 's' quoted string, or one of ( ) [ ] , :
+	text string
+	pos  int
+	end  int
+}
+
+func lexFilterExpression(input string) ([]filterToken, error) {
+	var tokens []filterToken
+	for i := 0; i < len(input); {
+		ch := input[i]
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			i++
+		case strings.IndexByte("()[],:", ch) >= 0:
+			tokens = append(tokens, filterToken{kind: ch, text: string(ch), pos: i, end: i + 1})
+			i++
+		case ch == '"':
+			var b strings.Builder
+			j := i + 1
+			closed := false
+			for j < len(input) {
+				if input[j] == '\\' && j+1 < len(input) {
+					b.WriteByte(input[j+1])
+					j += 2
+					continue
+				}
+				if input[j] == '"' {
+					closed = true
+					j++
+					break
+				}
+				b.WriteByte(input[j])
+				j++
+			}
+			if !closed {
+				return nil, fmt.Errorf("unterminated string at position %d", i)
+			}
+			tokens = append(tokens, filterToken{kind: 's', text: b.String(), pos: i, end: j})
+			i = j
+		default:
+			j := i
+			for j < len(input) && strings.IndexByte(" \t\n\r()[],:\"", input[j]) < 0 {
+				j++
+			}
+			tokens = append(tokens, filterToken{kind: 'w', text: input[i:j], pos: i, end: j})
+			i = j
+		}
+	}
+	return tokens, nil
+}
+
+// ─── Parser ──────────────────────────────────────────────────
+
+type filterParser struct {
+	tokens     []filterToken
+	i          int
+	predicates int
+	cfg        QueryConfig
+	now        time.Time
+}
+
+// ParseFilterExpression parses and validates a filter= expression against
+// cfg. now anchors relative time values.
+func ParseFilterExpression(input string, cfg QueryConfig, now time.Time) (*FilterExpr, error) {
+	if len(input) > maxFilterExpressionLength {
+		return nil, fmt.Errorf("filter expression exceeds %d characters", maxFilterExpressionLength)
+	}
+	tokens, err := lexFilterExpression(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty filter expression")
+	}
+	p := &filterParser{tokens: tokens, cfg: cfg, now: now}
+	expr, err := p.parseOr(0)
+	if err != nil {
+		return nil, err
+	}
+	if tok, ok := p.peek(); ok {
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+	}
+	return expr, nil
+}
+
+func (p *filterParser) peek() (filterToken, bool) {
+	if p.i >= len(p.tokens) {
+		return filterToken{}, false
+	}
+	return p.tokens[p.i], true
+}
+
+func (p *filterParser) keyword(word string) bool {
+	tok, ok := p.peek()
+	if ok && tok.kind == 'w' && strings.EqualFold(tok.text, word) {
+		p.i++
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) expect(kind byte) (filterToken, error) {
+	tok, ok := p.peek()
+	if !ok {
+		return tok, fmt.Errorf("unexpected end of filter, expected %q", string(kind))
+	}
+	if tok.kind != kind {
+		return tok, fmt.Errorf("unexpected %q at position %d, expected %q", tok.text, tok.pos, string(kind))
+	}
+	p.i++
+	return tok, nil
+}
+
+func (p *filterParser) parseOr(depth int) (*FilterExpr, error) {
+	return p.parseJoined(depth, "OR", FilterOr, p.parseAnd)
+}
+
+func (p *filterParser) parseAnd(depth int) (*FilterExpr, error) {
+	return p.parseJoined(depth, "AND", FilterAnd, p.parseUnary)
+}
+
+func (p *filterParser) parseJoined(depth int, word string, kind FilterExprKind, next func(int) (*FilterExpr, error)) (*FilterExpr, error) {
+	first, err := next(depth)
+	if err != nil {
+		return nil, err
+	}
+	children := []*FilterExpr{first}
+	for p.keyword(word) {
+		child, err := next(depth)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+	}
+	if len(children) == 1 {
+		return first, nil
+	}
+	return &FilterExpr{Kind: kind, Children: children}, nil
+}
+
+func (p *filterParser) parseUnary(depth int) (*FilterExpr, error) {
+	if depth > maxFilterDepth {
+		return nil, fmt.Errorf("filter expression nests deeper than %d levels", maxFilterDepth)
+	}
+	if p.keyword("NOT") {
+		child, err := p.parseUnary(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return &FilterExpr{Kind: FilterNot, Children: []*FilterExpr{child}}, nil
+	}
+	if tok, ok := p.peek(); ok && tok.kind == '(' {
+		p.i++
+		expr, err := p.parseOr(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(')'); err != nil {
+			return nil, err
+		}
+		return expr, nil
+	}
+	cond, err := p.parseCondition()
+	if err != nil {
+		return nil, err
+	}
+	return &FilterExpr{Kind: FilterPredicate, Predicate: cond}, nil
+}
+
+func (p *filterParser) parseCondition() (*FilterCondition, error) {
+	fieldTok, err := p.expect('w')
+	if err != nil {
+		return nil, err
+	}
+	if p.predicates++; p.predicates > maxFilterPredicates {
+		return nil, fmt.Errorf("filter expression has more than %d conditions", maxFilterPredicates)
+	}
+	if _, err := p.expect(':'); err != nil {
+		return nil, err
+	}
+	opTok, err := p.expect('w')
+	if err != nil {
+		return nil, err
+	}
+	cond := &FilterCondition{Operator: strings.ToLower(opTok.text)}
+	kind, err := p.resolveField(fieldTok.text, cond)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := filterOperatorsByKind[kind][cond.Operator]; !ok {
+		return nil, fmt.Errorf("operator '%s' is not supported for field '%s'", cond.Operator, fieldTok.text)
+	}
+
+	if cond.Operator == "null" || cond.Operator == "notnull" {
+		return cond, nil
+	}
+	if _, err := p.expect(':'); err != nil {
+		return nil, fmt.Errorf("operator '%s' on '%s' requires a value", cond.Operator, fieldTok.text)
+	}
+	values, err := p.parseValues()
+	if err != nil {
+		return nil, err
+	}
+	if err := checkFilterArity(cond.Operator, len(values)); err != nil {
+		return nil, fmt.Errorf("'%s:%s': %w", fieldTok.text, cond.Operator, err)
+	}
+	if p.cfg.TimeFields[cond.Field] {
+		for i, v := range values {
+			resolved, err := resolveFilterTime(v, p.now)
+			if err != nil {
+				return nil, fmt.Errorf("'%s': %w", fieldTok.text, err)
+			}
+			values[i] = resolved
+		}
+	}
+	cond.Values = values
+	return cond, nil
+}
+
+// resolveField maps field (optionally a dotted JSONB path) onto the config
+// allowlists, filling cond.Field/cond.Path.
+func (p *filterParser) resolveField(field string, cond *FilterCondition) (filterFieldKind, error) {
+	parts := strings.Split(field, ".")
+	cond.Field = parts[0]
+	if len(parts) > 1 {
+		if _, ok := p.cfg.JSONFields[cond.Field]; !ok {
+			return 0, fmt.Errorf("unknown filter field '%s'", field)
+		}
+		for _, seg := range parts[1:] {
+			if !filterJSONPathSegment.MatchString(seg) {
+				return 0, fmt.Errorf("invalid path segment '%s' in '%s'", seg, field)
+			}
+		}
+		cond.Path = parts[1:]
+		return filterFieldJSON, nil
+	}
+	if _, ok := p.cfg.ArrayFields[cond.Field]; ok {
+		return filterFieldArray, nil
+	}
+	if _, ok := p.cfg.FilterableFields[cond.Field]; ok {
+		return filterFieldScalar, nil
+	}
+	return 0, fmt.Errorf("unknown filter field '%s'", field)
+}
+
+func (p *filterParser) parseValues() ([]string, error) {
+	if tok, ok := p.peek(); ok && tok.kind == '[' {
+		p.i++
+		var values []string
+		for {
+			v, err := p.parseScalar()
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, v)
+			if tok, ok := p.peek(); ok && tok.kind == ',' {
+				p.i++
+				continue
+			}
+			if _, err := p.expect(']'); err != nil {
+				return nil, err
+			}
+			return values, nil
+		}
+	}
+	v, err := p.parseScalar()
+	if err != nil {
+		return nil, err
+	}
+	return []string{v}, nil
+}
+
+// parseScalar reads a quoted string, or a bare word glued to adjacent ':'
+// and word tokens so timestamps like 2026-08-01T10:00:00Z need no quoting.
+func (p *filterParser) parseScalar() (string, error) {
+	tok, ok := p.peek()
+	if !ok {
+		return "", fmt.Errorf("unexpected end of filter, expected a value")
+	}
+	if tok.kind == 's' {
+		p.i++
+		return tok.text, nil
+	}
+	if tok.kind != 'w' {
+		return "", fmt.Errorf("unexpected %q at position %d, expected a value", tok.text, tok.pos)
+	}
+	p.i++
+	var b strings.Builder
+	b.WriteString(tok.text)
+	end := tok.end
+	for {
+		next, ok := p.peek()
+		if !ok || next.pos != end || (next.kind != 'w' && next.kind != ':') {
+			break
+		}
+		b.WriteString(next.text)
+		end = next.end
+		p.i++
+	}
+	return b.String(), nil
+}
+
+func checkFilterArity(op string, n int) error {
+	switch op {
+	case "in", "nin", "hasany", "hasall":
+		if n == 0 {
+			return fmt.Errorf("requires at least one value")
+		}
+	case "between":
+		if n != 2 {
+			return fmt.Errorf("requires exactly two values")
+		}
+	default:
+		if n != 1 {
+			return fmt.Errorf("requires exactly one value")
+		}
+	}
+	return nil
+}
+
+// resolveFilterTime accepts now, now±N{s,m,h,d,w}, today (UTC midnight),
+// RFC3339 or YYYY-MM-DD and returns an RFC3339 instant.
+func resolveFilterTime(value string, now time.Time) (string, error) {
+	v := strings.ToLower(strings.TrimSpace(value))
+	if v == "today" {
+		y, m, d := now.UTC().Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Format(time.RFC3339Nano), nil
+	}
+	if match := filterRelativeTime.FindStringSubmatch(v); match != nil {
+		t := now
+		if match[1] != "" {
+			n, err := strconv.Atoi(match[2])
+			if err != nil {
+				return "", fmt.Errorf("invalid relative time '%s'", value)
+			}
+			unit := map[string]time.Duration{
+				"s": time.Second, "m": time.Minute, "h": time.Hour,
+				"d": 24 * time.Hour, "w": 7 * 24 * time.Hour,
+			}[match[3]]
+			delta := time.Duration(n) * unit
+			if match[1] == "-" {
+				delta = -delta
+			}
+			t = t.Add(delta)
+		}
+		return t.UTC().Format(time.RFC3339Nano), nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t.UTC().Format(time.RFC3339Nano), nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t.Format(time.RFC3339Nano), nil
+	}
+	return "", fmt.Errorf("invalid time '%s' (use RFC3339, YYYY-MM-DD, today, now or now-7d)", value)
+}
+
+// ─── Compiler ────────────────────────────────────────────────
+
+// compileFilterExpr renders a validated expression as one WHERE fragment.
+func compileFilterExpr(expr *FilterExpr, cfg QueryConfig) (string, []interface{}) {
+	switch expr.Kind {
+	case FilterAnd, FilterOr:
+		parts := make([]string, 0, len(expr.Children))
+		var args []interface{}
+		for _, child := range expr.Children {
+			sql, childArgs := compileFilterExpr(child, cfg)
+			parts = append(parts, sql)
+			args = append(args, childArgs...)
+		}
+		return "(" + strings.Join(parts, " "+strings.ToUpper(string(expr.Kind))+" ") + ")", args
+	case FilterNot:
+		sql, args := compileFilterExpr(expr.Children[0], cfg)
+		return "NOT " + sql, args
+	}
+	return compileFilterCondition(expr.Predicate, cfg)
+}
+
+func compileFilterCondition(cond *FilterCondition, cfg QueryConfig) (string, []interface{}) {
+	if column, ok := cfg.JSONFields[cond.Field]; ok && len(cond.Path) > 0 {
+		// The path is bound as text[]; #>> yields text so existing operators apply.
+		sql, args, _ := filterClause(fmt.Sprintf("(%s #>> ?::text[])", column), FilterParam{Operator: cond.Operator, Values: cond.Values})
+		return "(" + sql + ")", append([]interface{}{pq.StringArray(cond.Path)}, args...)
+	}
+	if column, ok := cfg.ArrayFields[cond.Field]; ok {
+		switch cond.Operator {
+		case "has", "hasall":
+			return fmt.Sprintf("(%s @> ?::text[])", column), []interface{}{pq.StringArray(cond.Values)}
+		case "hasany":
+			return fmt.Sprintf("(%s && ?::text[])", column), []interface{}{pq.StringArray(cond.Values)}
+		case "null":
+			return fmt.Sprintf("(COALESCE(cardinality(%s), 0) = 0)", column), nil
+		default: // notnull
+			return fmt.Sprintf("(cardinality(%s) > 0)", column), nil
+		}
+	}
+	column := cfg.FilterableFields[cond.Field]
+	sql, args, _ := filterClause(column, FilterParam{Field: cond.Field, Operator: cond.Operator, Values: cond.Values})
+	return "(" + sql + ")", args
+}
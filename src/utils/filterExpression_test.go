@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+var filterTestConfig = QueryConfig{
+	FilterableFields: map[string]string{
+		"status":       "items.status",
+		"type":         "items.type",
+		"title":        "items.title",
+		"published_at": "items.published_at",
+	},
+	TimeFields:  map[string]bool{"published_at": true},
+	ArrayFields: map[string]string{"topic_tags": "items.topic_tags"},
+	JSONFields:  map[string]string{"metadata": "items.metadata"},
+}
+
+var filterTestNow = time.Date(2026, 8, 20, 12, 30, 0, 0, time.UTC)
+
+func compileFilterForTest(t *testing.T, input string) (string, []interface{}) {
+	t.Helper()
+	expr, err := ParseFilterExpression(input, filterTestConfig, filterTestNow)
+	if err != nil {
+		t.Fatalf("parse %q: %v", input, err)
+	}
+	return compileFilterExpr(expr, filterTestConfig)
+}
+
+func TestFilterExpressionPrecedenceAndNegation(t *testing.T) {
+	sql, args := compileFilterForTest(t, `(status:eq:READY OR status:eq:PROCESSING) and not type:in:[NEWS,"VIDEO"]`)
+	want := "(((items.status = ?) OR (items.status = ?)) AND NOT (items.type IN ?))"
+	if sql != want {
+		t.Fatalf("sql = %q, want %q", sql, want)
+	}
+	if !reflect.DeepEqual(args, []interface{}{"READY", "PROCESSING", []string{"NEWS", "VIDEO"}}) {
+		t.Fatalf("args = %#v", args)
+	}
+
+	// AND binds tighter than OR.
+	sql, _ = compileFilterForTest(t, `status:eq:A OR status:eq:B AND type:null`)
+	if sql != "((items.status = ?) OR ((items.status = ?) AND (items.type IS NULL)))" {
+		t.Fatalf("precedence sql = %q", sql)
+	}
+}
+
+func TestFilterExpressionResolvesTimes(t *testing.T) {
+	sql, args := compileFilterForTest(t, `published_at:between:[now-7d,now] AND published_at:lt:2026-08-19T10:00:00Z`)
+	if sql != "((items.published_at BETWEEN ? AND ?) AND (items.published_at < ?))" {
+		t.Fatalf("sql = %q", sql)
+	}
+	want := []interface{}{"2026-08-13T12:30:00Z", "2026-08-20T12:30:00Z", "2026-08-19T10:00:00Z"}
+	if !reflect.DeepEqual(args, want) {
+		t.Fatalf("args = %v, want %v", args, want)
+	}
+
+	_, args = compileFilterForTest(t, `published_at:gte:today`)
+	if args[0] != "2026-08-20T00:00:00Z" {
+		t.Fatalf("today = %v", args[0])
+	}
+}
+
+func TestFilterExpressionArraysAndJSONPaths(t *testing.T) {
+	sql, args := compileFilterForTest(t, `topic_tags:hasany:[economy,oil] AND metadata.source.lang:eq:ar`)
+	if sql != "((items.topic_tags && ?::text[]) AND ((items.metadata #>> ?::text[]) = ?))" {
+		t.Fatalf("sql = %q", sql)
+	}
+	want := []interface{}{pq.StringArray{"economy", "oil"}, pq.StringArray{"source", "lang"}, "ar"}
+	if !reflect.DeepEqual(args, want) {
+		t.Fatalf("args = %#v", args)
+	}
+}
+
+func TestFilterExpressionRejectsUnsafeInput(t *testing.T) {
+	cases := map[string]string{
+		`secret:eq:x`:                            "unknown filter field",
+		`status:has:x`:                           "not supported",
+		`topic_tags:gt:x`:                        "not supported",
+		`metadata.a;drop:eq:x`:                   "invalid path segment",
+		`metadata.a-b:eq:x`:                      "invalid path segment",
+		`status.x:eq:y`:                          "unknown filter field",
+		`status:eq`:                              "requires a value",
+		`status:between:[a]`:                     "exactly two values",
+		`published_at:gt:last tuesday`:           "invalid time",
+		`(status:eq:A`:                           "expected \")\"",
+		`status:eq:"open`:                        "unterminated string",
+		`status:eq:A status:eq:B`:                "unexpected",
+		strings.Repeat("NOT ", 12) + "type:null": "nests deeper",
+	}
+	for input, wantErr := range cases {
+		_, err := ParseFilterExpression(input, filterTestConfig, filterTestNow)
+		if err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("%q: err = %v, want containing %q", input, err, wantErr)
+		}
+	}
+}
+
+func TestParseQueryParamsAcceptsFilterExpression(t *testing.T) {
+	params, err := ParseQueryParams(keysetTestContext("filter=status:eq:READY%20OR%20status:eq:FAILED&status=eq:READY"), filterTestConfig)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if params.Expression == nil || params.Expression.Kind != FilterOr || len(params.Filters) != 1 {
+		t.Fatalf("params = %+v", params)
+	}
+	if _, err := ParseQueryParams(keysetTestContext("filter=nope:eq:1"), filterTestConfig); err == nil {
+		t.Fatal("invalid expression must be rejected")
+	}
+}
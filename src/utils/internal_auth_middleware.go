@@ -17,17 +17,44 @@ type machineCredential struct {
 	secret       string
 }
 
-// InternalAuthMiddleware authenticates a named machine principal. It never
-// grants access by itself: each route applies its capability policy below.
+// InternalAuthMiddleware authenticates a named machine principal, from a
+// signed request or (in dual mode) a static bearer token. It never grants
+// access by itself: each route applies its capability policy below.
 func InternalAuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		principal, credentialID, ok := authenticateMachineToken(c.GetHeader("Authorization"))
-		if !ok {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid service credential"})
-			return
+		header := c.GetHeader("Authorization")
+		var (
+			principal    MachinePrincipal
+			credentialID string
+			scheme       string
+		)
+		if isSignedInternalAuthorization(header) {
+			var err error
+			principal, credentialID, err = verifySignedInternalRequest(c, time.Now())
+			if err != nil {
+				status, message := internalAuthFailureStatus(err)
+				log.Printf("internal authentication result=denied auth=signed credential_id=%s reason=%q route=%s %s", credentialID, err, c.Request.Method, c.FullPath())
+				c.AbortWithStatusJSON(status, gin.H{"error": message})
+				return
+			}
+			scheme = "signed"
+		} else {
+			if InternalAuthMode() == InternalAuthModeSigned {
+				status, message := internalAuthFailureStatus(errInternalSignedRequestNeeded)
+				c.AbortWithStatusJSON(status, gin.H{"error": message})
+				return
+			}
+			var ok bool
+			principal, credentialID, ok = authenticateMachineToken(header)
+			if !ok {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid service credential"})
+				return
+			}
+			scheme = "bearer"
 		}
 		c.Set(InternalPrincipalContextKey, principal)
 		c.Set(InternalCredentialIDContextKey, credentialID)
+		c.Set(InternalAuthSchemeContextKey, scheme)
 		c.Next()
 	}
 }
@@ -94,6 +121,7 @@ func configuredMachineCredentials() []machineCredential {
 	add(MachinePrincipalMedia, "CMS_MEDIA_SERVICE_TOKEN_NEXT", "media/next")
 	add(MachinePrincipalIAM, "CMS_IAM_SERVICE_TOKEN", "iam/current")
 	add(MachinePrincipalMigrationCoordinator, "CMS_MIGRATION_COORDINATOR_SERVICE_TOKEN", "migration-coordinator/current")
+	add(MachinePrincipalMetrics, "CMS_METRICS_SERVICE_TOKEN", "metrics/current")
 
 	// The old broad token is a migration bridge only. Production requires an
 	// explicit, future removal time; development remains convenient for the
@@ -123,5 +151,8 @@ func logInternalAuthorization(c *gin.Context, policy InternalRoutePolicy, princi
 	if tenantID == "" {
 		tenantID = c.Query("tenant_id")
 	}
-	log.Printf("internal authorization result=%s principal=%s credential_id=%s capability=%s route=%s tenant=%s request_id=%s", result, principal, credentialID, policy.Capability, policy.Method+" "+policy.Path, tenantID, requestID)
+	// auth/auth_mode show how far the signing migration has got: bearer
+	// results under auth_mode=dual are the callers still to move.
+	scheme := c.GetString(InternalAuthSchemeContextKey)
+	log.Printf("internal authorization result=%s principal=%s credential_id=%s auth=%s auth_mode=%s capability=%s route=%s tenant=%s request_id=%s", result, principal, credentialID, scheme, InternalAuthMode(), policy.Capability, policy.Method+" "+policy.Path, tenantID, requestID)
 }
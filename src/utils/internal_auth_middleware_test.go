@@ -1,8 +1,13 @@
 package utils
 
 import (
+	"context"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -102,3 +107,122 @@ func TestPipelineRepairTerminalReceiptIsAggregationOnly(t *testing.T) {
 		t.Fatalf("pipeline terminal receipt must be Aggregation-only: %#v", policy.Principals)
 	}
 }
+
+type memoryNonceStore struct {
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+func (s *memoryNonceStore) Claim(_ context.Context, credentialID, nonce string, expiresAt time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := credentialID + "|" + nonce
+	if exp, ok := s.seen[key]; ok && time.Now().Before(exp) {
+		return false, nil
+	}
+	s.seen[key] = expiresAt
+	return true, nil
+}
+
+func TestSignedInternalRequests(t *testing.T) {
+	t.Setenv("ENV", "test")
+	t.Setenv("CMS_AGGREGATION_SERVICE_TOKEN", "aggregation-current")
+	t.Setenv("CMS_ENRICHMENT_SERVICE_TOKEN", "enrichment-current")
+	t.Setenv("CMS_SERVICE_TOKEN", "")
+	t.Setenv("CMS_INTERNAL_AUTH_MODE", "")
+	t.Setenv("CMS_INTERNAL_SIGNING_MAX_SKEW", "")
+
+	store := &memoryNonceStore{seen: map[string]time.Time{}}
+	storeAvailable := true
+	previous := internalNonceStoreFor
+	internalNonceStoreFor = func(*gin.Context) (InternalNonceStore, bool) { return store, storeAvailable }
+	defer func() { internalNonceStoreFor = previous }()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(InternalAuthMiddleware())
+	policy := InternalRoutePolicy{Method: http.MethodPost, Path: "/test", Capability: "circulation.write", Principals: []MachinePrincipal{MachinePrincipalAggregation}}
+	router.POST("/internal/test", RequireInternalRoutePolicy(policy), func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, c.GetString(InternalAuthSchemeContextKey)+":"+string(body))
+	})
+
+	const body = `{"source_id":42}`
+	nonce := 0
+	signed := func(credentialID, secret, sentBody string, at time.Time) *http.Request {
+		nonce++
+		req := httptest.NewRequest(http.MethodPost, "/internal/test?tenant_id=t1", strings.NewReader(sentBody))
+		req.Header.Set("X-Tenant-ID", "t1")
+		if err := SignInternalRequest(req, credentialID, secret, []byte(body), at, fmt.Sprintf("nonce-%016d", nonce)); err != nil {
+			t.Fatal(err)
+		}
+		return req
+	}
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	ok := signed("aggregation/current", "aggregation-current", body, time.Now())
+	replay := ok.Clone(context.Background())
+	if w := serve(ok); w.Code != http.StatusOK || w.Body.String() != "signed:"+body {
+		t.Fatalf("signed request: %d %s", w.Code, w.Body.String())
+	}
+	replay.Body = io.NopCloser(strings.NewReader(body))
+	if w := serve(replay); w.Code != http.StatusUnauthorized || !strings.Contains(w.Body.String(), "Replayed") {
+		t.Fatalf("replay: %d %s", w.Code, w.Body.String())
+	}
+
+	rejected := map[string]*http.Request{
+		"tampered body":      signed("aggregation/current", "aggregation-current", `{"source_id":43}`, time.Now()),
+		"wrong secret":       signed("aggregation/current", "guess", body, time.Now()),
+		"unknown credential": signed("aggregation/next", "aggregation-current", body, time.Now()),
+		"outside skew":       signed("aggregation/current", "aggregation-current", body, time.Now().Add(-10*time.Minute)),
+	}
+	tenantSwap := signed("aggregation/current", "aggregation-current", body, time.Now())
+	tenantSwap.Header.Set("X-Tenant-ID", "t2")
+	rejected["re-targeted tenant"] = tenantSwap
+	for name, req := range rejected {
+		if w := serve(req); w.Code != http.StatusUnauthorized {
+			t.Fatalf("%s: got %d, want 401", name, w.Code)
+		}
+	}
+
+	// A valid signature from the wrong service is authenticated, then denied.
+	if w := serve(signed("enrichment/current", "enrichment-current", body, time.Now())); w.Code != http.StatusForbidden {
+		t.Fatalf("wrong principal: got %d, want 403", w.Code)
+	}
+
+	storeAvailable = false
+	if w := serve(signed("aggregation/current", "aggregation-current", body, time.Now())); w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("replay cache down: got %d, want 503", w.Code)
+	}
+	storeAvailable = true
+
+	bearer := func() int {
+		req := httptest.NewRequest(http.MethodPost, "/internal/test", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer aggregation-current")
+		return serve(req).Code
+	}
+	if got := bearer(); got != http.StatusOK {
+		t.Fatalf("dual mode bearer: got %d, want 200", got)
+	}
+	t.Setenv("CMS_INTERNAL_AUTH_MODE", "signed")
+	if got := bearer(); got != http.StatusUnauthorized {
+		t.Fatalf("signed mode bearer: got %d, want 401", got)
+	}
+	if w := serve(signed("aggregation/current", "aggregation-current", body, time.Now())); w.Code != http.StatusOK {
+		t.Fatalf("signed mode signed request: got %d", w.Code)
+	}
+}
+
+func TestMetricsRoutePolicyIsScrapeOnly(t *testing.T) {
+	policy := MetricsRoutePolicy()
+	if _, ok := FindInternalRoutePolicy(policy.Method, policy.Path); ok {
+		t.Fatal("/metrics must not be part of the /internal route inventory")
+	}
+	if !policy.Allows(MachinePrincipalMetrics) || policy.Allows(MachinePrincipalAggregation) {
+		t.Fatalf("metrics policy principals = %v", policy.Principals)
+	}
+}
@@ -17,6 +17,7 @@ const (
 	MachinePrincipalMedia                MachinePrincipal = "media"
 	MachinePrincipalIAM                  MachinePrincipal = "iam"
 	MachinePrincipalMigrationCoordinator MachinePrincipal = "migration-coordinator"
+	MachinePrincipalMetrics              MachinePrincipal = "metrics"
 	MachinePrincipalLegacy               MachinePrincipal = "legacy-shared"
 )
 
@@ -164,6 +165,13 @@ func InternalRoutePolicies() []InternalRoutePolicy {
 	}
 }
 
+// MetricsRoutePolicy guards GET /metrics. The scrape endpoint sits outside
+// /internal so every process role serves it, but it uses the same machine
+// credentials; Prometheus sends CMS_METRICS_SERVICE_TOKEN as a bearer token.
+func MetricsRoutePolicy() InternalRoutePolicy {
+	return InternalRoutePolicy{http.MethodGet, "/metrics", "metrics.read", []MachinePrincipal{MachinePrincipalMetrics}, true}
+}
+
 func FindInternalRoutePolicy(method, path string) (InternalRoutePolicy, bool) {
 	for _, policy := range InternalRoutePolicies() {
 		if policy.Method == method && policy.Path == path {
@@ -0,0 +1,301 @@
+package utils
+
+import (
+	"bytes"
+	"content-management-system/src/models"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Signed service requests. A caller proves possession of its machine
+// credential without sending it:
+//
+//	Authorization: CMS-HMAC-SHA256 Credential=<credential id>, Timestamp=<unix seconds>, Nonce=<nonce>, Signature=<hex>
+//
+// Signature is hex(HMAC-SHA256(key, canonical)) where key is
+// HMAC-SHA256(credential secret, "cms-internal-request-v1") and canonical is
+// the newline-joined scheme, method, escaped path, sorted query,
+// "x-tenant-id:<header>", timestamp, nonce and hex SHA-256 of the body. The
+// credential id is one of configuredMachineCredentials (e.g.
+// "aggregation/current"), so rotation works exactly as it does for bearer
+// tokens.
+const InternalSignatureScheme = "CMS-HMAC-SHA256"
+
+// Internal auth modes (CMS_INTERNAL_AUTH_MODE).
+//
+//	dual   — signed requests are verified, static bearer tokens still accepted
+//	         (migration; every request logs which scheme it used)
+//	signed — bearer tokens are rejected
+const (
+	InternalAuthModeDual   = "dual"
+	InternalAuthModeSigned = "signed"
+)
+
+const InternalAuthSchemeContextKey = "internal_auth_scheme"
+
+const (
+	internalSigningKeyLabel       = "cms-internal-request-v1"
+	defaultInternalSigningMaxSkew = 5 * time.Minute
+	internalSignedBodyLimit       = 32 << 20
+	internalNoncePurgeInterval    = time.Minute
+)
+
+var (
+	errInternalSignatureInvalid    = errors.New("invalid request signature")
+	errInternalRequestStale        = errors.New("request timestamp outside allowed skew")
+	errInternalRequestReplayed     = errors.New("request nonce already used")
+	errInternalNonceStoreFailed    = errors.New("replay cache unavailable")
+	errInternalSignedBodyTooLarge  = errors.New("signed request body too large")
+	errInternalSignedRequestNeeded = errors.New("signed service request required")
+)
+
+// InternalAuthMode reads CMS_INTERNAL_AUTH_MODE; anything but "signed" is dual.
+func InternalAuthMode() string {
+	if strings.ToLower(strings.TrimSpace(os.Getenv("CMS_INTERNAL_AUTH_MODE"))) == InternalAuthModeSigned {
+		return InternalAuthModeSigned
+	}
+	return InternalAuthModeDual
+}
+
+func internalSigningMaxSkew() time.Duration {
+	return envDuration("CMS_INTERNAL_SIGNING_MAX_SKEW", defaultInternalSigningMaxSkew)
+}
+
+// InternalNonceStore claims a nonce once per credential. Claim reports false
+// when the nonce is still remembered from an earlier request.
+type InternalNonceStore interface {
+	Claim(ctx context.Context, credentialID, nonce string, expiresAt time.Time) (bool, error)
+}
+
+// internalNonceStoreFor resolves the replay cache for a request; the Postgres
+// store shares the request's database handle.
+var internalNonceStoreFor = func(c *gin.Context) (InternalNonceStore, bool) {
+	value, ok := c.Get("db")
+	if !ok {
+		return nil, false
+	}
+	db, ok := value.(*gorm.DB)
+	if !ok || db == nil {
+		return nil, false
+	}
+	return PostgresNonceStore{DB: db}, true
+}
+
+// PostgresNonceStore keeps nonces in internal_request_nonces, shared by every
+// CMS replica.
+type PostgresNonceStore struct {
+	DB *gorm.DB
+}
+
+var internalNoncePurge struct {
+	sync.Mutex
+	last time.Time
+}
+
+func (s PostgresNonceStore) Claim(ctx context.Context, credentialID, nonce string, expiresAt time.Time) (bool, error) {
+	now := time.Now().UTC()
+	db := s.DB.WithContext(ctx)
+	internalNoncePurge.Lock()
+	purge := now.Sub(internalNoncePurge.last) >= internalNoncePurgeInterval
+	if purge {
+		internalNoncePurge.last = now
+	}
+	internalNoncePurge.Unlock()
+	if purge {
+		db.Where("expires_at < ?", now).Delete(&models.InternalRequestNonce{})
+	}
+
+	// An expired row that has not been purged yet may be reclaimed.
+	result := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "credential_id"}, {Name: "nonce"}},
+		DoUpdates: clause.AssignmentColumns([]string{"expires_at", "created_at"}),
+		Where:     clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "internal_request_nonces.expires_at < ?", Vars: []interface{}{now}}}},
+	}).Create(&models.InternalRequestNonce{CredentialID: credentialID, Nonce: nonce, ExpiresAt: expiresAt.UTC(), CreatedAt: now})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func isSignedInternalAuthorization(header string) bool {
+	return len(header) > len(InternalSignatureScheme) &&
+		strings.EqualFold(header[:len(InternalSignatureScheme)], InternalSignatureScheme) &&
+		header[len(InternalSignatureScheme)] == ' '
+}
+
+type internalSignatureParams struct {
+	credentialID string
+	timestamp    int64
+	nonce        string
+	signature    []byte
+}
+
+func parseInternalSignatureHeader(header string) (internalSignatureParams, bool) {
+	var params internalSignatureParams
+	var haveTimestamp bool
+	for _, part := range strings.Split(header[len(InternalSignatureScheme)+1:], ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return params, false
+		}
+		switch strings.ToLower(key) {
+		case "credential":
+			params.credentialID = value
+		case "timestamp":
+			ts, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return params, false
+			}
+			params.timestamp, haveTimestamp = ts, true
+		case "nonce":
+			params.nonce = value
+		case "signature":
+			sig, err := hex.DecodeString(value)
+			if err != nil {
+				return params, false
+			}
+			params.signature = sig
+		}
+	}
+	return params, params.credentialID != "" && haveTimestamp && validInternalNonce(params.nonce) && len(params.signature) == sha256.Size
+}
+
+// validInternalNonce accepts 16–128 URL-safe characters: enough entropy to be
+// unique, bounded to the column width.
+func validInternalNonce(nonce string) bool {
+	if len(nonce) < 16 || len(nonce) > 128 {
+		return false
+	}
+	for _, r := range nonce {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.' || r == '~') {
+			return false
+		}
+	}
+	return true
+}
+
+func internalCanonicalRequest(req *http.Request, timestamp int64, nonce string, body []byte) string {
+	digest := sha256.Sum256(body)
+	return strings.Join([]string{
+		InternalSignatureScheme,
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.Query().Encode(),
+		"x-tenant-id:" + strings.TrimSpace(req.Header.Get("X-Tenant-ID")),
+		strconv.FormatInt(timestamp, 10),
+		nonce,
+		hex.EncodeToString(digest[:]),
+	}, "\n")
+}
+
+func internalRequestSignature(secret, canonical string) []byte {
+	keyMAC := hmac.New(sha256.New, []byte(secret))
+	keyMAC.Write([]byte(internalSigningKeyLabel))
+	mac := hmac.New(sha256.New, keyMAC.Sum(nil))
+	mac.Write([]byte(canonical))
+	return mac.Sum(nil)
+}
+
+// SignInternalRequest sets the Authorization header for a signed internal
+// request. body must be exactly the bytes the request will send.
+func SignInternalRequest(req *http.Request, credentialID, secret string, body []byte, now time.Time, nonce string) error {
+	if !validInternalNonce(nonce) {
+		return fmt.Errorf("nonce must be 16-128 URL-safe characters")
+	}
+	timestamp := now.Unix()
+	signature := internalRequestSignature(secret, internalCanonicalRequest(req, timestamp, nonce, body))
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s, Timestamp=%d, Nonce=%s, Signature=%s",
+		InternalSignatureScheme, credentialID, timestamp, nonce, hex.EncodeToString(signature)))
+	return nil
+}
+
+// verifySignedInternalRequest authenticates a signed request: known
+// credential, timestamp within skew, matching signature, then a first use of
+// the nonce. The nonce is claimed only after the signature checks out, so
+// unauthenticated traffic cannot fill the replay cache. The body is restored
+// for the handler.
+func verifySignedInternalRequest(c *gin.Context, now time.Time) (MachinePrincipal, string, error) {
+	params, ok := parseInternalSignatureHeader(c.GetHeader("Authorization"))
+	if !ok {
+		return "", "", errInternalSignatureInvalid
+	}
+	var credential *machineCredential
+	for _, candidate := range configuredMachineCredentials() {
+		if candidate.credentialID == params.credentialID && candidate.secret != "" {
+			candidate := candidate
+			credential = &candidate
+			break
+		}
+	}
+	if credential == nil {
+		return "", params.credentialID, errInternalSignatureInvalid
+	}
+	skew := internalSigningMaxSkew()
+	signedAt := time.Unix(params.timestamp, 0)
+	if signedAt.Before(now.Add(-skew)) || signedAt.After(now.Add(skew)) {
+		return "", credential.credentialID, errInternalRequestStale
+	}
+
+	var body []byte
+	if c.Request.Body != nil {
+		var err error
+		body, err = io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, internalSignedBodyLimit))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				return "", credential.credentialID, errInternalSignedBodyTooLarge
+			}
+			return "", credential.credentialID, errInternalSignatureInvalid
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	expected := internalRequestSignature(credential.secret, internalCanonicalRequest(c.Request, params.timestamp, params.nonce, body))
+	if !hmac.Equal(expected, params.signature) {
+		return "", credential.credentialID, errInternalSignatureInvalid
+	}
+
+	store, ok := internalNonceStoreFor(c)
+	if !ok {
+		return "", credential.credentialID, errInternalNonceStoreFailed
+	}
+	fresh, err := store.Claim(c.Request.Context(), credential.credentialID, params.nonce, signedAt.Add(skew))
+	if err != nil {
+		return "", credential.credentialID, errInternalNonceStoreFailed
+	}
+	if !fresh {
+		return "", credential.credentialID, errInternalRequestReplayed
+	}
+	return credential.principal, credential.credentialID, nil
+}
+
+func internalAuthFailureStatus(err error) (int, string) {
+	switch {
+	case errors.Is(err, errInternalNonceStoreFailed):
+		return http.StatusServiceUnavailable, "Replay protection unavailable"
+	case errors.Is(err, errInternalSignedBodyTooLarge):
+		return http.StatusRequestEntityTooLarge, "Signed request body too large"
+	case errors.Is(err, errInternalRequestStale):
+		return http.StatusUnauthorized, "Request timestamp outside allowed clock skew"
+	case errors.Is(err, errInternalRequestReplayed):
+		return http.StatusUnauthorized, "Replayed service request"
+	case errors.Is(err, errInternalSignedRequestNeeded):
+		return http.StatusUnauthorized, "Signed service request required"
+	default:
+		return http.StatusUnauthorized, "Invalid service credential"
+	}
+}
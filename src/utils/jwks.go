@@ -0,0 +1,542 @@
+package utils
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// JWT verification modes (JWT_VERIFICATION_MODE).
+//
+//	hs256  — shared JWT_SECRET only (default, legacy)
+//	jwks   — RS256/ES256/EdDSA against IAM's published keys only; CMS holds
+//	         no signing material
+//	hybrid — both, for the migration window while IAM switches signers
+const (
+	JWTModeHS256  = "hs256"
+	JWTModeJWKS   = "jwks"
+	JWTModeHybrid = "hybrid"
+)
+
+var (
+	// asymmetricJWTMethods are the only algorithms accepted from a JWKS.
+	asymmetricJWTMethods = []string{"RS256", "ES256", "EdDSA"}
+	// hmacJWTMethods are accepted with the shared JWT_SECRET.
+	hmacJWTMethods = []string{"HS256", "HS384", "HS512"}
+)
+
+var (
+	// ErrJWKSUnavailable means no verification key set could be loaded. It is
+	// an infrastructure failure, not a bad token.
+	ErrJWKSUnavailable = errors.New("jwks unavailable")
+	errJWKSUnknownKey  = errors.New("no jwks key matches token kid")
+)
+
+const (
+	defaultJWKSCacheTTL = 10 * time.Minute
+	// defaultJWKSRotationGrace keeps a key that disappeared from the document
+	// valid for this long, so tokens signed just before a rotation still
+	// verify until they expire.
+	defaultJWKSRotationGrace = 15 * time.Minute
+	// jwksMinRefreshInterval rate-limits refreshes triggered by unknown kids,
+	// so a stream of forged kids cannot hammer IAM.
+	jwksMinRefreshInterval = 30 * time.Second
+	// jwksMaxStale bounds how long the last good key set keeps serving while
+	// the source is unreachable.
+	jwksMaxStale = 24 * time.Hour
+	// jwksFetchTimeout bounds one fetch of the document.
+	jwksFetchTimeout     = 5 * time.Second
+	jwksMaxDocumentBytes = 1 << 20
+	minRSAKeyBits        = 2048
+)
+
+// GetJWTVerificationMode reads JWT_VERIFICATION_MODE. Unknown values are
+// reported by ValidateJWTVerificationConfig; here they fall back to hs256.
+func GetJWTVerificationMode() string {
+	switch mode := strings.ToLower(strings.TrimSpace(os.Getenv("JWT_VERIFICATION_MODE"))); mode {
+	case JWTModeJWKS, JWTModeHybrid:
+		return mode
+	default:
+		return JWTModeHS256
+	}
+}
+
+// ValidateJWTVerificationConfig checks the configured mode has what it needs.
+// A JWKS file must parse at boot; a JWKS URL that is unreachable at boot is
+// only logged by the caller, since the cache retries on first use.
+func ValidateJWTVerificationConfig() (warning string, err error) {
+	raw := strings.ToLower(strings.TrimSpace(os.Getenv("JWT_VERIFICATION_MODE")))
+	if raw != "" && raw != JWTModeHS256 && raw != JWTModeJWKS && raw != JWTModeHybrid {
+		return "", fmt.Errorf("JWT_VERIFICATION_MODE must be hs256, jwks or hybrid (got %q)", raw)
+	}
+	mode := GetJWTVerificationMode()
+	if mode != JWTModeJWKS {
+		if _, err := GetJWTSecret(); err != nil {
+			return "", err
+		}
+	}
+	if mode == JWTModeHS256 {
+		return "", nil
+	}
+	cache, err := configuredJWKS()
+	if err != nil {
+		return "", err
+	}
+	if err := cache.Refresh(context.Background()); err != nil {
+		if cache.file != "" {
+			return "", err
+		}
+		return fmt.Sprintf("initial JWKS fetch failed (will retry on first request): %v", err), nil
+	}
+	return "", nil
+}
+
+// VerifyJWT validates a human (IAM) token according to the configured mode.
+// Use it instead of ParseJWT wherever a request token is checked; ctx bounds
+// how long it may wait for a JWKS refresh.
+func VerifyJWT(ctx context.Context, tokenString string) (*JWTClaims, error) {
+	keyfunc, methods, err := configuredJWTKeyfunc(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return parseJWTClaims(tokenString, keyfunc, methods)
+}
+
+// VerifyFeedRecoveryReauthProof validates IAM's re-auth proof with the same
+// key material as VerifyJWT.
+func VerifyFeedRecoveryReauthProof(ctx context.Context, tokenString string) (*FeedRecoveryReauthClaims, error) {
+	keyfunc, methods, err := configuredJWTKeyfunc(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return parseFeedRecoveryReauthProof(tokenString, keyfunc, methods)
+}
+
+func configuredJWTKeyfunc(ctx context.Context) (jwt.Keyfunc, []string, error) {
+	mode := GetJWTVerificationMode()
+	var secret []byte
+	if mode != JWTModeJWKS {
+		s, err := GetJWTSecret()
+		if err != nil {
+			return nil, nil, err
+		}
+		secret = s
+	}
+	if mode == JWTModeHS256 {
+		return hmacKeyfunc(secret), hmacJWTMethods, nil
+	}
+	cache, err := configuredJWKS()
+	if err != nil {
+		return nil, nil, err
+	}
+	methods := append([]string(nil), asymmetricJWTMethods...)
+	if mode == JWTModeHybrid {
+		methods = append(methods, hmacJWTMethods...)
+	}
+	return func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+			if secret == nil {
+				return nil, ErrTokenInvalid
+			}
+			return secret, nil
+		}
+		kid, _ := token.Header["kid"].(string)
+		return cache.Key(ctx, kid, token.Method.Alg())
+	}, methods, nil
+}
+
+func hmacKeyfunc(secret []byte) jwt.Keyfunc {
+	return func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return secret, nil
+	}
+}
+
+var (
+	jwksCacheMu sync.Mutex
+	jwksCache   *JWKSCache
+)
+
+// configuredJWKS returns the process-wide cache for JWT_JWKS_URL or
+// JWT_JWKS_FILE, rebuilding it when the source changes.
+func configuredJWKS() (*JWKSCache, error) {
+	source := strings.TrimSpace(os.Getenv("JWT_JWKS_URL"))
+	file := strings.TrimSpace(os.Getenv("JWT_JWKS_FILE"))
+	if (source == "") == (file == "") {
+		return nil, fmt.Errorf("set exactly one of JWT_JWKS_URL or JWT_JWKS_FILE for JWT_VERIFICATION_MODE=%s", GetJWTVerificationMode())
+	}
+	if source != "" {
+		u, err := url.Parse(source)
+		if err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+			return nil, fmt.Errorf("JWT_JWKS_URL is not a valid http(s) URL")
+		}
+		// Plain http is for a local IAM stand-in only.
+		if u.Scheme == "http" && strings.ToLower(strings.TrimSpace(os.Getenv("ENV"))) == "production" {
+			return nil, fmt.Errorf("JWT_JWKS_URL must use https in production")
+		}
+	}
+	ttl := envDuration("JWT_JWKS_CACHE_TTL", defaultJWKSCacheTTL)
+	grace := envDuration("JWT_JWKS_ROTATION_GRACE", defaultJWKSRotationGrace)
+
+	jwksCacheMu.Lock()
+	defer jwksCacheMu.Unlock()
+	if jwksCache == nil || jwksCache.url != source || jwksCache.file != file || jwksCache.ttl != ttl || jwksCache.grace != grace {
+		jwksCache = NewJWKSCache(source, file, ttl, grace)
+	}
+	return jwksCache, nil
+}
+
+func envDuration(name string, fallback time.Duration) time.Duration {
+	if d, err := time.ParseDuration(strings.TrimSpace(os.Getenv(name))); err == nil && d > 0 {
+		return d
+	}
+	return fallback
+}
+
+type jwksKey struct {
+	key crypto.PublicKey
+	alg string // the JWK's declared alg; "" means any alg valid for its type
+	// retiredAt is when the key left the document; zero while published.
+	retiredAt time.Time
+}
+
+// JWKSCache serves verification keys from a JWKS URL or file. Keys are
+// selected by kid; an unknown kid triggers a (rate-limited) refresh so a
+// newly published key works immediately, and a key removed from the document
+// stays valid for the rotation grace window.
+//
+// The document is fetched on a refresh goroutine, never under the cache lock:
+// while it runs the cached keys keep serving, and only callers with nothing
+// usable (a cold cache, a stale set past jwksMaxStale, an unknown kid) wait
+// for it, each bounded by its own context.
+type JWKSCache struct {
+	url   string
+	file  string
+	ttl   time.Duration
+	grace time.Duration
+
+	client *http.Client
+	now    func() time.Time
+
+	mu          sync.Mutex
+	keys        map[string]*jwksKey
+	loadedAt    time.Time
+	attemptedAt time.Time
+	// refreshing is closed when the refresh in flight finishes; nil when
+	// none runs. refreshErr is the outcome of the last refresh.
+	refreshing chan struct{}
+	refreshErr error
+}
+
+func NewJWKSCache(source, file string, ttl, grace time.Duration) *JWKSCache {
+	return &JWKSCache{
+		url:    source,
+		file:   file,
+		ttl:    ttl,
+		grace:  grace,
+		client: &http.Client{Timeout: jwksFetchTimeout},
+		now:    time.Now,
+		keys:   map[string]*jwksKey{},
+	}
+}
+
+// Key returns the public key for kid that may verify alg. A token without a
+// kid is accepted only when exactly one key is published.
+func (k *JWKSCache) Key(ctx context.Context, kid, alg string) (crypto.PublicKey, error) {
+	k.mu.Lock()
+	now := k.now()
+	if k.loadedAt.IsZero() || now.Sub(k.loadedAt) > k.ttl {
+		// While the source is down, retry at most once per interval and keep
+		// serving the last good set instead of blocking every request on it.
+		done := k.startRefreshLocked(ctx, now, false)
+		if !k.servableLocked(now) {
+			k.mu.Unlock()
+			err := k.await(ctx, done)
+			k.mu.Lock()
+			now = k.now()
+			if !k.servableLocked(now) {
+				k.mu.Unlock()
+				if err == nil {
+					err = errors.New("no key set loaded within the refresh interval")
+				}
+				return nil, fmt.Errorf("%w: %v", ErrJWKSUnavailable, err)
+			}
+		}
+	}
+	key, err := k.lookupLocked(kid, alg, now)
+	if errors.Is(err, errJWKSUnknownKey) {
+		if done := k.startRefreshLocked(ctx, now, false); done != nil {
+			k.mu.Unlock()
+			if k.await(ctx, done) != nil {
+				return nil, err
+			}
+			k.mu.Lock()
+			key, err = k.lookupLocked(kid, alg, k.now())
+		}
+	}
+	k.mu.Unlock()
+	return key, err
+}
+
+// servableLocked reports whether the loaded set may still verify tokens.
+func (k *JWKSCache) servableLocked(now time.Time) bool {
+	return !k.loadedAt.IsZero() && now.Sub(k.loadedAt) <= jwksMaxStale
+}
+
+func (k *JWKSCache) lookupLocked(kid, alg string, now time.Time) (crypto.PublicKey, error) {
+	var entry *jwksKey
+	if kid != "" {
+		entry = k.keys[kid]
+	} else {
+		for _, candidate := range k.keys {
+			if !candidate.retiredAt.IsZero() {
+				continue
+			}
+			if entry != nil {
+				return nil, errJWKSUnknownKey
+			}
+			entry = candidate
+		}
+	}
+	if entry == nil || (!entry.retiredAt.IsZero() && now.Sub(entry.retiredAt) > k.grace) {
+		return nil, errJWKSUnknownKey
+	}
+	if entry.alg != "" && entry.alg != alg {
+		return nil, fmt.Errorf("jwks key %q is for %s, token uses %s", kid, entry.alg, alg)
+	}
+	if !keyMatchesAlg(entry.key, alg) {
+		return nil, fmt.Errorf("jwks key %q cannot verify %s", kid, alg)
+	}
+	return entry.key, nil
+}
+
+// Refresh reloads the document now, waiting for it up to ctx.
+func (k *JWKSCache) Refresh(ctx context.Context) error {
+	k.mu.Lock()
+	done := k.startRefreshLocked(ctx, k.now(), true)
+	k.mu.Unlock()
+	return k.await(ctx, done)
+}
+
+// startRefreshLocked returns the channel of the refresh in flight, starting
+// one unless the last attempt was within jwksMinRefreshInterval (and force is
+// unset); nil means no refresh will run. The fetch is shared by every waiter,
+// so it keeps ctx's values but not its cancellation and is bounded by
+// jwksFetchTimeout instead.
+func (k *JWKSCache) startRefreshLocked(ctx context.Context, now time.Time, force bool) chan struct{} {
+	if k.refreshing != nil {
+		return k.refreshing
+	}
+	if !force && now.Sub(k.attemptedAt) < jwksMinRefreshInterval {
+		return nil
+	}
+	k.attemptedAt = now
+	done := make(chan struct{})
+	k.refreshing = done
+	go func() {
+		fetchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), jwksFetchTimeout)
+		defer cancel()
+		fresh, err := k.load(fetchCtx)
+		k.mu.Lock()
+		if err == nil {
+			k.applyLocked(fresh, now)
+		}
+		k.refreshErr = err
+		k.refreshing = nil
+		k.mu.Unlock()
+		close(done)
+	}()
+	return done
+}
+
+// await waits for the refresh done signals and returns its outcome.
+func (k *JWKSCache) await(ctx context.Context, done chan struct{}) error {
+	if done == nil {
+		return nil
+	}
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.refreshErr
+}
+
+func (k *JWKSCache) load(ctx context.Context) (map[string]*jwksKey, error) {
+	raw, err := k.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return parseJWKS(raw)
+}
+
+func (k *JWKSCache) applyLocked(fresh map[string]*jwksKey, now time.Time) {
+	next := make(map[string]*jwksKey, len(fresh))
+	for kid, key := range fresh {
+		next[kid] = key
+	}
+	for kid, old := range k.keys {
+		if _, still := next[kid]; still {
+			continue
+		}
+		if old.retiredAt.IsZero() {
+			old.retiredAt = now
+		}
+		if now.Sub(old.retiredAt) <= k.grace {
+			next[kid] = old
+		}
+	}
+	k.keys = next
+	k.loadedAt = now
+}
+
+func (k *JWKSCache) fetch(ctx context.Context) ([]byte, error) {
+	if k.file != "" {
+		return os.ReadFile(k.file)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, k.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("jwks fetch: status %d", resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, jwksMaxDocumentBytes))
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// parseJWKS decodes the signature keys of a JWKS document, keyed by kid.
+// Encryption keys and key types CMS does not verify with are skipped; a
+// malformed signature key fails the whole document so a bad publish never
+// half-applies.
+func parseJWKS(raw []byte) (map[string]*jwksKey, error) {
+	var doc struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("jwks: %w", err)
+	}
+	out := map[string]*jwksKey{}
+	for i, jwk := range doc.Keys {
+		if jwk.Use == "enc" || jwk.Kty == "oct" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("jwks key %d (%q): %w", i, jwk.Kid, err)
+		}
+		if jwk.Alg != "" && !keyMatchesAlg(key, jwk.Alg) {
+			return nil, fmt.Errorf("jwks key %d (%q): alg %s does not fit kty %s", i, jwk.Kid, jwk.Alg, jwk.Kty)
+		}
+		if _, dup := out[jwk.Kid]; dup {
+			return nil, fmt.Errorf("jwks: duplicate kid %q", jwk.Kid)
+		}
+		out[jwk.Kid] = &jwksKey{key: key, alg: jwk.Alg}
+	}
+	if len(out) == 0 {
+		return nil, errors.New("jwks: no signature keys")
+	}
+	return out, nil
+}
+
+func (jwk jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeJWKInt(jwk.N)
+		if err != nil {
+			return nil, fmt.Errorf("n: %w", err)
+		}
+		e, err := decodeJWKInt(jwk.E)
+		if err != nil || !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid e")
+		}
+		if n.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("rsa key shorter than %d bits", minRSAKeyBits)
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		if jwk.Crv != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+		}
+		x, errX := decodeJWKInt(jwk.X)
+		y, errY := decodeJWKInt(jwk.Y)
+		if errX != nil || errY != nil {
+			return nil, errors.New("invalid coordinates")
+		}
+		key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}
+		if _, err := key.ECDH(); err != nil {
+			return nil, errors.New("point is not on the curve")
+		}
+		return key, nil
+	case "OKP":
+		if jwk.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid x")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported kty %q", jwk.Kty)
+	}
+}
+
+func decodeJWKInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) == 0 {
+		return nil, errors.New("invalid base64url integer")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+func keyMatchesAlg(key crypto.PublicKey, alg string) bool {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		return alg == "RS256"
+	case *ecdsa.PublicKey:
+		return alg == "ES256" && k.Curve == elliptic.P256()
+	case ed25519.PublicKey:
+		return alg == "EdDSA"
+	default:
+		return false
+	}
+}
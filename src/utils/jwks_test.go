@@ -0,0 +1,327 @@
+package utils
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func b64(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+
+func testJWK(t *testing.T, kid string, pub crypto.PublicKey) map[string]string {
+	t.Helper()
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		return map[string]string{"kty": "RSA", "kid": kid, "use": "sig", "alg": "RS256", "n": b64(k.N.Bytes()), "e": b64(big.NewInt(int64(k.E)).Bytes())}
+	case *ecdsa.PublicKey:
+		return map[string]string{"kty": "EC", "kid": kid, "crv": "P-256", "x": b64(k.X.FillBytes(make([]byte, 32))), "y": b64(k.Y.FillBytes(make([]byte, 32)))}
+	case ed25519.PublicKey:
+		return map[string]string{"kty": "OKP", "kid": kid, "crv": "Ed25519", "x": b64(k)}
+	}
+	t.Fatalf("unsupported key %T", pub)
+	return nil
+}
+
+func testJWKS(t *testing.T, keys ...map[string]string) []byte {
+	t.Helper()
+	raw, err := json.Marshal(map[string]interface{}{"keys": keys})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+func signTestToken(t *testing.T, method jwt.SigningMethod, kid string, key interface{}) string {
+	t.Helper()
+	now := time.Now()
+	token := jwt.NewWithClaims(method, JWTClaims{
+		Email: "editor@example.com",
+		Role:  "editor",
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "7b0c6f0e-8f3a-4f63-9d43-0a5f3f6f2f11",
+			Issuer:    "iam-authorization-service",
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		},
+	})
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func TestVerifyJWTWithJWKSFile(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	edPub, edPriv, _ := ed25519.GenerateKey(rand.Reader)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	doc := testJWKS(t, testJWK(t, "rsa-1", &rsaKey.PublicKey), testJWK(t, "ec-1", &ecKey.PublicKey), testJWK(t, "ed-1", edPub))
+	if err := os.WriteFile(path, doc, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("JWT_VERIFICATION_MODE", "jwks")
+	t.Setenv("JWT_JWKS_FILE", path)
+	t.Setenv("JWT_JWKS_URL", "")
+	t.Setenv("JWT_SECRET", "")
+	t.Setenv("JWT_ALLOWED_AUDIENCES", "")
+	if _, err := ValidateJWTVerificationConfig(); err != nil {
+		t.Fatalf("config: %v", err)
+	}
+
+	for name, token := range map[string]string{
+		"RS256": signTestToken(t, jwt.SigningMethodRS256, "rsa-1", rsaKey),
+		"ES256": signTestToken(t, jwt.SigningMethodES256, "ec-1", ecKey),
+		"EdDSA": signTestToken(t, jwt.SigningMethodEdDSA, "ed-1", edPriv),
+	} {
+		claims, err := VerifyJWT(context.Background(), token)
+		if err != nil || claims.Email != "editor@example.com" {
+			t.Fatalf("%s: claims = %+v, err = %v", name, claims, err)
+		}
+	}
+
+	other, _ := rsa.GenerateKey(rand.Reader, 2048)
+	rejected := map[string]string{
+		"wrong key":           signTestToken(t, jwt.SigningMethodRS256, "rsa-1", other),
+		"unknown kid":         signTestToken(t, jwt.SigningMethodRS256, "rsa-9", rsaKey),
+		"kid of another type": signTestToken(t, jwt.SigningMethodRS256, "ec-1", rsaKey),
+		"shared secret":       signTestToken(t, jwt.SigningMethodHS256, "", []byte("secret")),
+	}
+	for name, token := range rejected {
+		if _, err := VerifyJWT(context.Background(), token); err == nil {
+			t.Fatalf("%s: expected rejection", name)
+		}
+	}
+}
+
+func TestVerifyJWTHybridAcceptsBoth(t *testing.T) {
+	edPub, edPriv, _ := ed25519.GenerateKey(rand.Reader)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, testJWKS(t, testJWK(t, "ed-1", edPub)), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("JWT_VERIFICATION_MODE", "hybrid")
+	t.Setenv("JWT_JWKS_FILE", path)
+	t.Setenv("JWT_JWKS_URL", "")
+	t.Setenv("JWT_SECRET", "shared")
+	t.Setenv("JWT_ALLOWED_AUDIENCES", "")
+
+	// A single published key also verifies tokens that carry no kid.
+	if _, err := VerifyJWT(context.Background(), signTestToken(t, jwt.SigningMethodEdDSA, "", edPriv)); err != nil {
+		t.Fatalf("EdDSA: %v", err)
+	}
+	if _, err := VerifyJWT(context.Background(), signTestToken(t, jwt.SigningMethodHS256, "", []byte("shared"))); err != nil {
+		t.Fatalf("HS256: %v", err)
+	}
+}
+
+func TestJWKSCacheRotation(t *testing.T) {
+	oldPub, oldPriv, _ := ed25519.GenerateKey(rand.Reader)
+	newPub, _, _ := ed25519.GenerateKey(rand.Reader)
+
+	var mu sync.Mutex
+	doc := testJWKS(t, testJWK(t, "k1", oldPub))
+	fetches := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		fetches++
+		_, _ = w.Write(doc)
+	}))
+	defer srv.Close()
+
+	now := time.Unix(1_700_000_000, 0)
+	cache := NewJWKSCache(srv.URL, "", time.Hour, 15*time.Minute)
+	cache.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if _, err := cache.Key(ctx, "k1", "EdDSA"); err != nil {
+		t.Fatalf("k1: %v", err)
+	}
+
+	// IAM publishes k2 and drops k1.
+	mu.Lock()
+	doc = testJWKS(t, testJWK(t, "k2", newPub))
+	mu.Unlock()
+
+	// An unknown kid refreshes at most once per interval.
+	if _, err := cache.Key(ctx, "k2", "EdDSA"); err == nil {
+		t.Fatal("refresh must be rate limited right after a fetch")
+	}
+	now = now.Add(jwksMinRefreshInterval)
+	if _, err := cache.Key(ctx, "k2", "EdDSA"); err != nil {
+		t.Fatalf("k2 after refresh: %v", err)
+	}
+	if n := fetchCount(&mu, &fetches); n != 2 {
+		t.Fatalf("fetches = %d, want 2", n)
+	}
+
+	// k1 stays valid through the rotation grace window, then expires.
+	now = now.Add(10 * time.Minute)
+	key, err := cache.Key(ctx, "k1", "EdDSA")
+	if err != nil || !key.(ed25519.PublicKey).Equal(oldPriv.Public()) {
+		t.Fatalf("k1 during grace: %v", err)
+	}
+	now = now.Add(10 * time.Minute)
+	if _, err := cache.Key(ctx, "k1", "EdDSA"); !errors.Is(err, errJWKSUnknownKey) {
+		t.Fatalf("k1 after grace: %v", err)
+	}
+
+	// The source going away keeps the last good set serving.
+	srv.Close()
+	now = now.Add(2 * time.Hour)
+	if _, err := cache.Key(ctx, "k2", "EdDSA"); err != nil {
+		t.Fatalf("stale k2: %v", err)
+	}
+	now = now.Add(jwksMaxStale)
+	if _, err := cache.Key(ctx, "k2", "EdDSA"); !errors.Is(err, ErrJWKSUnavailable) {
+		t.Fatalf("expired stale set: %v", err)
+	}
+}
+
+func TestJWKSCacheThrottlesRefreshWhileSourceIsDown(t *testing.T) {
+	pub, _, _ := ed25519.GenerateKey(rand.Reader)
+	doc := testJWKS(t, testJWK(t, "k1", pub))
+	var mu sync.Mutex
+	down, fetches := false, 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		fetches++
+		if down {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write(doc)
+	}))
+	defer srv.Close()
+
+	now := time.Unix(1_700_000_000, 0)
+	cache := NewJWKSCache(srv.URL, "", time.Minute, 15*time.Minute)
+	cache.now = func() time.Time { return now }
+	ctx := context.Background()
+	if _, err := cache.Key(ctx, "k1", "EdDSA"); err != nil {
+		t.Fatalf("k1: %v", err)
+	}
+
+	mu.Lock()
+	down = true
+	mu.Unlock()
+	now = now.Add(2 * time.Minute)
+	for i := 0; i < 5; i++ {
+		if _, err := cache.Key(ctx, "k1", "EdDSA"); err != nil {
+			t.Fatalf("stale k1: %v", err)
+		}
+	}
+	waitJWKSRefresh(cache)
+	if n := fetchCount(&mu, &fetches); n != 2 {
+		t.Fatalf("fetches = %d, want one retry per interval", n)
+	}
+	now = now.Add(jwksMinRefreshInterval)
+	_, err := cache.Key(ctx, "k1", "EdDSA")
+	waitJWKSRefresh(cache)
+	if n := fetchCount(&mu, &fetches); err != nil || n != 3 {
+		t.Fatalf("retry after interval: err=%v fetches=%d", err, n)
+	}
+}
+
+func TestJWKSCacheServesCachedKeysWhileRefreshing(t *testing.T) {
+	pub, _, _ := ed25519.GenerateKey(rand.Reader)
+	doc := testJWKS(t, testJWK(t, "k1", pub))
+	release := make(chan struct{})
+	var mu sync.Mutex
+	fetches := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		fetches++
+		n := fetches
+		mu.Unlock()
+		if n > 1 {
+			<-release
+		}
+		_, _ = w.Write(doc)
+	}))
+	defer srv.Close()
+
+	now := time.Unix(1_700_000_000, 0)
+	cache := NewJWKSCache(srv.URL, "", time.Minute, 15*time.Minute)
+	cache.now = func() time.Time { return now }
+	if _, err := cache.Key(context.Background(), "k1", "EdDSA"); err != nil {
+		t.Fatalf("k1: %v", err)
+	}
+
+	// The TTL lapses while IAM hangs: known kids keep verifying, and a caller
+	// waiting on an unknown kid gives up with its own context.
+	now = now.Add(2 * time.Minute)
+	if _, err := cache.Key(context.Background(), "k1", "EdDSA"); err != nil {
+		t.Fatalf("k1 during refresh: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := cache.Key(ctx, "k2", "EdDSA"); !errors.Is(err, errJWKSUnknownKey) {
+		t.Fatalf("unknown kid during refresh: %v", err)
+	}
+	close(release)
+	waitJWKSRefresh(cache)
+	if n := fetchCount(&mu, &fetches); n != 2 {
+		t.Fatalf("fetches = %d, want 2", n)
+	}
+}
+
+// waitJWKSRefresh blocks until no refresh is in flight.
+func waitJWKSRefresh(cache *JWKSCache) {
+	cache.mu.Lock()
+	done := cache.refreshing
+	cache.mu.Unlock()
+	if done != nil {
+		<-done
+	}
+}
+
+func fetchCount(mu *sync.Mutex, fetches *int) int {
+	mu.Lock()
+	defer mu.Unlock()
+	return *fetches
+}
+
+func TestParseJWKSRejectsWeakOrMalformedKeys(t *testing.T) {
+	weak, _ := rsa.GenerateKey(rand.Reader, 1024)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	mislabelled := testJWK(t, "ec", &ecKey.PublicKey)
+	mislabelled["alg"] = "RS256"
+	offCurve := testJWK(t, "ec", &ecKey.PublicKey)
+	offCurve["y"] = b64([]byte{1})
+
+	for name, doc := range map[string][]byte{
+		"short rsa":      testJWKS(t, testJWK(t, "r", &weak.PublicKey)),
+		"alg mismatch":   testJWKS(t, mislabelled),
+		"off curve":      testJWKS(t, offCurve),
+		"no signing key": testJWKS(t, map[string]string{"kty": "oct", "k": "c2VjcmV0"}),
+		"not json":       []byte("{"),
+	} {
+		if _, err := parseJWKS(doc); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+var keysetTestConfig = QueryConfig{
+	DefaultSort: []SortParam{{Field: "published_at", Direction: "desc"}},
+	SortableFields: map[string]string{
+		"published_at": "items.published_at",
+		"title":        "items.title",
+		"id":           "items.public_id",
+	},
+	KeysetFields:     map[string]string{"published_at": "timestamptz", "id": "uuid"},
+	KeysetTieBreaker: "id",
+}
+
+func keysetTestContext(rawQuery string) *gin.Context {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/items?"+rawQuery, nil)
+	return c
+}
+
+func TestKeysetCursorRoundTrip(t *testing.T) {
+	ts, id := "2026-08-01T10:00:00.123456Z", "2b0d8e5a-0d4e-4c57-9d0f-8f7c1b9d2a11"
+	cursor := EncodeKeysetCursor("published_at:desc,id:desc", []*string{&ts, nil, &id})
+	if strings.ContainsAny(cursor, "+/") {
+		t.Fatalf("cursor %q is not URL-safe", cursor)
+	}
+	values, err := DecodeKeysetCursor(cursor, "published_at:desc,id:desc", 3)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if *values[0] != ts || values[1] != nil || *values[2] != id {
+		t.Fatalf("values = %v", values)
+	}
+	if _, err := DecodeKeysetCursor(cursor, "published_at:asc,id:asc", 3); err == nil {
+		t.Fatal("cursor must be rejected under a different sort")
+	}
+	if _, err := DecodeKeysetCursor("not-base64!", "published_at:desc,id:desc", 3); err == nil {
+		t.Fatal("garbage cursor must be rejected")
+	}
+	if EncodeKeysetCursor("x", nil) != "" {
+		t.Fatal("empty tuple must encode to an empty cursor")
+	}
+}
+
+func TestResolveKeysetPlanAppendsTieBreaker(t *testing.T) {
+	plan, ok := resolveKeysetPlan([]SortParam{{Field: "published_at", Direction: "desc"}}, keysetTestConfig)
+	if !ok || plan.signature() != "published_at:desc,id:desc" {
+		t.Fatalf("plan = %+v ok=%v", plan, ok)
+	}
+	if _, ok := resolveKeysetPlan([]SortParam{{Field: "title", Direction: "asc"}}, keysetTestConfig); ok {
+		t.Fatal("a non-keyset sort field must fall back to offset pagination")
+	}
+	plan, _ = resolveKeysetPlan([]SortParam{{Field: "id", Direction: "asc"}, {Field: "published_at", Direction: "desc"}}, keysetTestConfig)
+	if plan.signature() != "id:asc" {
+		t.Fatalf("sorts after the unique tie-breaker must be dropped, got %q", plan.signature())
+	}
+}
+
+func TestKeysetPredicateHandlesDirectionsAndNulls(t *testing.T) {
+	plan, _ := resolveKeysetPlan([]SortParam{{Field: "published_at", Direction: "desc"}}, keysetTestConfig)
+	ts, id := "2026-08-01T10:00:00Z", "2b0d8e5a-0d4e-4c57-9d0f-8f7c1b9d2a11"
+
+	sql, args := plan.predicate([]*string{&ts, &id})
+	want := "((items.published_at < ?::timestamptz) OR (items.published_at = ?::timestamptz AND items.public_id < ?::uuid))"
+	if sql != want || !reflect.DeepEqual(args, []interface{}{ts, ts, id}) {
+		t.Fatalf("predicate = %q %v", sql, args)
+	}
+
+	// DESC sorts NULLs first, so rows after a NULL timestamp are the dated ones.
+	sql, args = plan.predicate([]*string{nil, &id})
+	want = "((items.published_at IS NOT NULL) OR (items.published_at IS NULL AND items.public_id < ?::uuid))"
+	if sql != want || !reflect.DeepEqual(args, []interface{}{id}) {
+		t.Fatalf("null predicate = %q %v", sql, args)
+	}
+
+	asc, _ := resolveKeysetPlan([]SortParam{{Field: "published_at", Direction: "asc"}}, keysetTestConfig)
+	sql, _ = asc.predicate([]*string{&ts, &id})
+	if !strings.HasPrefix(sql, "(((items.published_at > ?::timestamptz OR items.published_at IS NULL))") {
+		t.Fatalf("ascending predicate must let NULLs follow, got %q", sql)
+	}
+}
+
+func TestParseQueryParamsCursorAndCountMode(t *testing.T) {
+	ts, id := "2026-08-01T10:00:00Z", "2b0d8e5a-0d4e-4c57-9d0f-8f7c1b9d2a11"
+	cursor := EncodeKeysetCursor("published_at:desc,id:desc", []*string{&ts, &id})
+
+	params, err := ParseQueryParams(keysetTestContext("page=4&count=none&cursor="+cursor), keysetTestConfig)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if params.CountMode != CountNone || len(params.CursorValues) != 2 || params.Pagination.Offset != 0 {
+		t.Fatalf("params = %+v", params)
+	}
+
+	if _, err := ParseQueryParams(keysetTestContext("sort=title&cursor="+cursor), keysetTestConfig); err == nil {
+		t.Fatal("cursor with a non-keyset sort must be rejected")
+	}
+	if _, err := ParseQueryParams(keysetTestContext("count=sometimes"), keysetTestConfig); err == nil {
+		t.Fatal("unknown count mode must be rejected")
+	}
+	params, _ = ParseQueryParams(keysetTestContext(""), keysetTestConfig)
+	if params.CountMode != CountExact {
+		t.Fatalf("default count mode = %q, want exact", params.CountMode)
+	}
+}
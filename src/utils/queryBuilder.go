@@ -4,11 +4,15 @@ package utils
 *
 * Query Builder consumes the structured QueryParams and turns them into GORM
 * clauses plus response metadata. Its flow is:
-*   1. ApplyQuery walks each FilterParam/Search/Sort entry and stitches WHERE/
-*      ORDER BY fragments using the configured column mappings.
-*   2. FetchWithPagination clones the prepared query, counts the total rows,
-*      executes the limited page query, and calculates QueryMeta (page/limit/
-*      offset/total/hasPrev/hasNext).
+*   1. ApplyQuery walks each FilterParam/Search/Sort entry (plus the compiled
+*      filter= expression) and stitches WHERE/ORDER BY fragments using the
+*      configured column mappings.
+*      When every sort field is keyset-capable the ORDER BY also gets the
+*      config's unique tie-breaker and the keyset plan rides on the statement.
+*   2. FetchWithPagination clones the prepared query, counts the total rows
+*      per the requested CountMode, executes the page query (OFFSET, or a
+*      keyset predicate after the cursor tuple) with a one-row lookahead, and
+*      calculates QueryMeta (page/limit/offset/total/hasPrev/hasNext/cursor).
 *   3. BuildPaginationLinks mirrors the original URL + query string to emit
 *      self/next/prev/first/last HATEOAS links that clients can follow.
 * The helpers encapsulate per-operator SQL (value filters, LIKE patterns, IN/
@@ -20,8 +24,10 @@ import (
 	"fmt"
 	"math"
 	"net/url"
+	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -35,6 +41,181 @@ type QueryMeta struct {
 	TotalPages int   `json:"total_pages"`
 	HasNext    bool  `json:"has_next"`
 	HasPrev    bool  `json:"has_prev"`
+	// CountMode reports how Total was computed; TotalEstimated is set when it
+	// is an approximation (CountEstimated beyond the exact-count cap).
+	CountMode      CountMode `json:"count_mode"`
+	TotalEstimated bool      `json:"total_estimated,omitempty"`
+	// NextCursor continues a keyset-capable sort after the last row returned.
+	NextCursor *string `json:"next_cursor,omitempty"`
+}
+
+// estimatedCountCap bounds the exact part of CountEstimated.
+const estimatedCountCap = 10000
+
+// queryPlanSetting carries ApplyQuery's decisions to FetchWithPagination on
+// the GORM statement (Settings survive Session clones).
+const queryPlanSetting = "utils:query_plan"
+
+type queryPlan struct {
+	keyset        *keysetPlan
+	estimateTable string
+}
+
+// keysetColumn is one ORDER BY term of a keyset-capable sort.
+type keysetColumn struct {
+	field  string
+	column string
+	cast   string
+	desc   bool
+}
+
+type keysetPlan struct {
+	columns []keysetColumn
+}
+
+// resolveKeysetPlan maps sorts onto keyset columns, appending the configured
+// tie-breaker. ok=false when any sort field is not keyset-capable.
+func resolveKeysetPlan(sorts []SortParam, cfg QueryConfig) (keysetPlan, bool) {
+	tie := cfg.KeysetTieBreaker
+	if len(sorts) == 0 || tie == "" {
+		return keysetPlan{}, false
+	}
+	tieCast, ok := cfg.KeysetFields[tie]
+	if !ok {
+		return keysetPlan{}, false
+	}
+	tieColumn, ok := cfg.SortableFields[tie]
+	if !ok {
+		return keysetPlan{}, false
+	}
+
+	var plan keysetPlan
+	for _, sort := range sorts {
+		column, sortable := cfg.SortableFields[sort.Field]
+		cast, keyset := cfg.KeysetFields[sort.Field]
+		if !sortable || !keyset {
+			return keysetPlan{}, false
+		}
+		plan.columns = append(plan.columns, keysetColumn{
+			field:  sort.Field,
+			column: column,
+			cast:   cast,
+			desc:   strings.EqualFold(sort.Direction, "desc"),
+		})
+		if sort.Field == tie {
+			// Terms after a unique column can never break a tie.
+			return plan, true
+		}
+	}
+	plan.columns = append(plan.columns, keysetColumn{
+		field:  tie,
+		column: tieColumn,
+		cast:   tieCast,
+		desc:   plan.columns[len(plan.columns)-1].desc,
+	})
+	return plan, true
+}
+
+// signature identifies the ordering a cursor was minted for.
+func (p keysetPlan) signature() string {
+	parts := make([]string, len(p.columns))
+	for i, col := range p.columns {
+		dir := "asc"
+		if col.desc {
+			dir = "desc"
+		}
+		parts[i] = col.field + ":" + dir
+	}
+	return strings.Join(parts, ",")
+}
+
+// predicate selects rows strictly after the cursor tuple in plan order, as the
+// expanded (a > x) OR (a = x AND b > y) form so mixed directions work. NULLs
+// follow Postgres defaults: last for ASC, first for DESC.
+func (p keysetPlan) predicate(values []*string) (string, []interface{}) {
+	var disjuncts []string
+	var args []interface{}
+	for i, col := range p.columns {
+		var terms []string
+		var termArgs []interface{}
+		for j := 0; j < i; j++ {
+			prev := p.columns[j]
+			if values[j] == nil {
+				terms = append(terms, prev.column+" IS NULL")
+				continue
+			}
+			terms = append(terms, fmt.Sprintf("%s = ?::%s", prev.column, prev.cast))
+			termArgs = append(termArgs, *values[j])
+		}
+		switch {
+		case values[i] == nil && col.desc:
+			terms = append(terms, col.column+" IS NOT NULL")
+		case values[i] == nil:
+			continue // nothing sorts after NULL in ascending order
+		case col.desc:
+			terms = append(terms, fmt.Sprintf("%s < ?::%s", col.column, col.cast))
+			termArgs = append(termArgs, *values[i])
+		default:
+			terms = append(terms, fmt.Sprintf("(%s > ?::%s OR %s IS NULL)", col.column, col.cast, col.column))
+			termArgs = append(termArgs, *values[i])
+		}
+		disjuncts = append(disjuncts, "("+strings.Join(terms, " AND ")+")")
+		args = append(args, termArgs...)
+	}
+	if len(disjuncts) == 0 {
+		return "FALSE", nil
+	}
+	return "(" + strings.Join(disjuncts, " OR ") + ")", args
+}
+
+// cursorAfter encodes the sort tuple of the last element of dest, resolving
+// columns through the GORM schema of the executed statement.
+func (p keysetPlan) cursorAfter(tx *gorm.DB, dest interface{}) string {
+	rv := reflect.Indirect(reflect.ValueOf(dest))
+	if rv.Kind() != reflect.Slice || rv.Len() == 0 || tx.Statement.Schema == nil {
+		return ""
+	}
+	last := reflect.Indirect(rv.Index(rv.Len() - 1))
+	if last.Type() != tx.Statement.Schema.ModelType {
+		return ""
+	}
+	values := make([]*string, 0, len(p.columns))
+	for _, col := range p.columns {
+		name := col.column
+		if idx := strings.LastIndex(name, "."); idx >= 0 {
+			name = name[idx+1:]
+		}
+		field := tx.Statement.Schema.LookUpField(strings.Trim(name, `"`))
+		if field == nil {
+			return ""
+		}
+		value, _ := field.ValueOf(tx.Statement.Context, last)
+		values = append(values, keysetValueString(value))
+	}
+	return EncodeKeysetCursor(p.signature(), values)
+}
+
+func keysetValueString(value interface{}) *string {
+	rv := reflect.ValueOf(value)
+	for rv.IsValid() && rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return nil
+	}
+	var s string
+	switch v := rv.Interface().(type) {
+	case time.Time:
+		s = v.Format(time.RFC3339Nano)
+	case fmt.Stringer:
+		s = v.String()
+	default:
+		s = fmt.Sprint(v)
+	}
+	return &s
 }
 
 type QueryLinks struct {
@@ -61,23 +242,40 @@ func ApplyQuery(db *gorm.DB, params QueryParams, cfg QueryConfig) *gorm.DB {
 		query = applyFilter(query, column, filter)
 	}
 
+	if params.Expression != nil {
+		clause, args := compileFilterExpr(params.Expression, cfg)
+		query = query.Where(clause, args...)
+	}
+
 	if params.Search != "" {
 		query = applySearch(query, params, cfg)
 	}
 
-	for _, sort := range params.Sort {
-		column, ok := cfg.SortableFields[sort.Field]
-		if !ok {
-			continue
+	plan := queryPlan{estimateTable: cfg.EstimateTable}
+	if keyset, ok := resolveKeysetPlan(params.Sort, cfg); ok {
+		for _, col := range keyset.columns {
+			dir := "ASC"
+			if col.desc {
+				dir = "DESC"
+			}
+			query = query.Order(fmt.Sprintf("%s %s", col.column, dir))
 		}
-		dir := strings.ToUpper(sort.Direction)
-		if dir != "DESC" {
-			dir = "ASC"
+		plan.keyset = &keyset
+	} else {
+		for _, sort := range params.Sort {
+			column, ok := cfg.SortableFields[sort.Field]
+			if !ok {
+				continue
+			}
+			dir := strings.ToUpper(sort.Direction)
+			if dir != "DESC" {
+				dir = "ASC"
+			}
+			query = query.Order(fmt.Sprintf("%s %s", column, dir))
 		}
-		query = query.Order(fmt.Sprintf("%s %s", column, dir))
 	}
 
-	return query
+	return query.Set(queryPlanSetting, plan)
 }
 
 // FetchWithPagination executes the query, returning paginated results alongside metadata.
@@ -104,37 +302,117 @@ func FetchWithPagination(query *gorm.DB, params QueryParams, dest interface{}) (
 		page = 1
 	}
 
-	var total int64
-	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
-		return QueryMeta{}, err
+	var plan queryPlan
+	if stored, ok := query.Get(queryPlanSetting); ok {
+		plan, _ = stored.(queryPlan)
 	}
 
-	dataQuery := query.Session(&gorm.Session{}).
-		Offset(offset).
-		Limit(limit)
-
-	if err := dataQuery.Find(dest).Error; err != nil {
+	mode := params.CountMode
+	if mode == "" {
+		mode = CountExact
+	}
+	total, estimated, err := countTotal(query, params, mode, plan.estimateTable)
+	if err != nil {
 		return QueryMeta{}, err
 	}
 
+	// Fetch one extra row so has_next is known without relying on the count.
+	dataQuery := query.Session(&gorm.Session{})
+	keysetPage := plan.keyset != nil && len(params.CursorValues) == len(plan.keyset.columns)
+	if keysetPage {
+		predicate, args := plan.keyset.predicate(params.CursorValues)
+		dataQuery = dataQuery.Where(predicate, args...)
+		offset, page = 0, 1
+	} else {
+		dataQuery = dataQuery.Offset(offset)
+	}
+	result := dataQuery.Limit(limit + 1).Find(dest)
+	if result.Error != nil {
+		return QueryMeta{}, result.Error
+	}
+	hasMore := truncateSlice(dest, limit)
+
 	var totalPages int
 	if limit > 0 && total > 0 {
 		totalPages = int(math.Ceil(float64(total) / float64(limit)))
 	}
 
 	meta := QueryMeta{
-		Page:       page,
-		Limit:      limit,
-		Offset:     offset,
-		Total:      total,
-		TotalPages: totalPages,
-		HasPrev:    page > 1 && total > 0,
-		HasNext:    totalPages > 0 && page < totalPages,
+		Page:           page,
+		Limit:          limit,
+		Offset:         offset,
+		Total:          total,
+		TotalPages:     totalPages,
+		HasPrev:        page > 1 && (total > 0 || mode != CountExact),
+		HasNext:        hasMore,
+		CountMode:      mode,
+		TotalEstimated: estimated,
+	}
+	if keysetPage {
+		meta.HasPrev = true
+	}
+	if plan.keyset != nil && hasMore {
+		if next := plan.keyset.cursorAfter(result, dest); next != "" {
+			meta.NextCursor = &next
+		}
 	}
 
 	return meta, nil
 }
 
+// countTotal computes QueryMeta.Total for the filtered query (cursor excluded).
+func countTotal(query *gorm.DB, params QueryParams, mode CountMode, estimateTable string) (int64, bool, error) {
+	switch mode {
+	case CountNone:
+		return 0, false, nil
+	case CountEstimated:
+		// Count at most estimatedCountCap rows; ordering is irrelevant here.
+		capped := query.Session(&gorm.Session{}).Select("1").Limit(estimatedCountCap)
+		delete(capped.Statement.Clauses, "ORDER BY")
+		var n int64
+		if err := query.Session(&gorm.Session{NewDB: true}).Table("(?) AS capped", capped).Count(&n).Error; err != nil {
+			return 0, false, err
+		}
+		if n < estimatedCountCap {
+			return n, false, nil
+		}
+		// pg_class only describes the whole table, so it is used for
+		// unfiltered listings; otherwise the cap is a lower bound.
+		if estimateTable != "" && len(params.Filters) == 0 && params.Expression == nil && params.Search == "" {
+			var reltuples int64
+			if err := query.Session(&gorm.Session{NewDB: true}).
+				Raw("SELECT GREATEST(reltuples, 0)::bigint FROM pg_class WHERE oid = to_regclass(?)", estimateTable).
+				Scan(&reltuples).Error; err != nil {
+				return 0, false, err
+			}
+			if reltuples > n {
+				n = reltuples
+			}
+		}
+		return n, true, nil
+	default:
+		var total int64
+		if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+			return 0, false, err
+		}
+		return total, false, nil
+	}
+}
+
+// truncateSlice trims *dest to n elements, reporting whether it was longer.
+func truncateSlice(dest interface{}, n int) bool {
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() != reflect.Ptr {
+		return false
+	}
+	rv = rv.Elem()
+	if rv.Kind() != reflect.Slice || rv.Len() <= n {
+		return false
+	}
+	rv.Set(rv.Slice(0, n))
+	return true
+}
+
 // BuildPaginationLinks generates standard pagination links preserving the existing query string.
 func BuildPaginationLinks(c *gin.Context, meta QueryMeta) QueryLinks {
 	links := QueryLinks{
@@ -148,6 +426,18 @@ func BuildPaginationLinks(c *gin.Context, meta QueryMeta) QueryLinks {
 	basePath := c.Request.URL.Path
 	original := cloneValues(c.Request.URL.Query())
 
+	// Keyset pages are forward-only: first restarts without a cursor, next
+	// carries the new one, and prev/last are not addressable.
+	if original.Get("cursor") != "" {
+		links.Self = c.Request.URL.RequestURI()
+		original.Del("cursor")
+		links.First = buildLink(basePath, original, 1, meta.Limit)
+		if meta.NextCursor != nil {
+			links.Next = buildCursorLink(basePath, original, *meta.NextCursor, meta.Limit)
+		}
+		return links
+	}
+
 	if self := buildLink(basePath, original, meta.Page, meta.Limit); self != nil {
 		links.Self = *self
 	}
@@ -157,7 +447,9 @@ func BuildPaginationLinks(c *gin.Context, meta QueryMeta) QueryLinks {
 		links.Last = buildLink(basePath, original, meta.TotalPages, meta.Limit)
 	} else {
 		links.First = buildLink(basePath, original, 1, meta.Limit)
-		links.Last = buildLink(basePath, original, 1, meta.Limit)
+		if meta.CountMode != CountNone {
+			links.Last = buildLink(basePath, original, 1, meta.Limit)
+		}
 	}
 
 	if meta.HasPrev {
@@ -171,57 +463,73 @@ func BuildPaginationLinks(c *gin.Context, meta QueryMeta) QueryLinks {
 }
 
 func applyFilter(query *gorm.DB, column string, filter FilterParam) *gorm.DB {
+	clause, args, ok := filterClause(column, filter)
+	if !ok {
+		return query
+	}
+	return query.Where(clause, args...)
+}
+
+// filterClause renders one operator against column as a WHERE fragment;
+// ok=false when the operator is unknown or lacks the values it needs. Shared
+// by the per-field filters and the filter= expression compiler.
+func filterClause(column string, filter FilterParam) (string, []interface{}, bool) {
 	switch filter.Operator {
 	case "eq":
-		return applyValueFilter(query, column, "=", filter.Values)
+		return valueFilterClause(column, "=", filter.Values)
 	case "ne":
-		return applyValueFilter(query, column, "<>", filter.Values)
+		return valueFilterClause(column, "<>", filter.Values)
 	case "gt":
-		return applyValueFilter(query, column, ">", filter.Values)
+		return valueFilterClause(column, ">", filter.Values)
 	case "gte":
-		return applyValueFilter(query, column, ">=", filter.Values)
+		return valueFilterClause(column, ">=", filter.Values)
 	case "lt":
-		return applyValueFilter(query, column, "<", filter.Values)
+		return valueFilterClause(column, "<", filter.Values)
 	case "lte":
-		return applyValueFilter(query, column, "<=", filter.Values)
+		return valueFilterClause(column, "<=", filter.Values)
 	case "contains":
-		return applyLikeFilter(query, column, "%", "%", filter.Values)
+		return likeFilterClause(column, "%", "%", filter.Values)
 	case "starts":
-		return applyLikeFilter(query, column, "", "%", filter.Values)
+		return likeFilterClause(column, "", "%", filter.Values)
 	case "ends":
-		return applyLikeFilter(query, column, "%", "", filter.Values)
+		return likeFilterClause(column, "%", "", filter.Values)
 	case "in":
 		if len(filter.Values) == 0 {
-			return query
+			return "", nil, false
 		}
-		return query.Where(fmt.Sprintf("%s IN ?", column), filter.Values)
+		return fmt.Sprintf("%s IN ?", column), []interface{}{filter.Values}, true
 	case "nin":
 		if len(filter.Values) == 0 {
-			return query
+			return "", nil, false
+		}
+		return fmt.Sprintf("%s NOT IN ?", column), []interface{}{filter.Values}, true
+	case "between":
+		if len(filter.Values) != 2 {
+			return "", nil, false
 		}
-		return query.Where(fmt.Sprintf("%s NOT IN ?", column), filter.Values)
+		return fmt.Sprintf("%s BETWEEN ? AND ?", column), []interface{}{filter.Values[0], filter.Values[1]}, true
 	case "null":
-		return query.Where(fmt.Sprintf("%s IS NULL", column))
+		return fmt.Sprintf("%s IS NULL", column), nil, true
 	case "notnull":
-		return query.Where(fmt.Sprintf("%s IS NOT NULL", column))
+		return fmt.Sprintf("%s IS NOT NULL", column), nil, true
 	default:
-		return query
+		return "", nil, false
 	}
 }
 
-func applyValueFilter(query *gorm.DB, column, operator string, values []string) *gorm.DB {
+func valueFilterClause(column, operator string, values []string) (string, []interface{}, bool) {
 	if len(values) == 0 {
-		return query
+		return "", nil, false
 	}
-	return query.Where(fmt.Sprintf("%s %s ?", column, operator), values[0])
+	return fmt.Sprintf("%s %s ?", column, operator), []interface{}{values[0]}, true
 }
 
-func applyLikeFilter(query *gorm.DB, column, prefix, suffix string, values []string) *gorm.DB {
+func likeFilterClause(column, prefix, suffix string, values []string) (string, []interface{}, bool) {
 	if len(values) == 0 {
-		return query
+		return "", nil, false
 	}
 	pattern := fmt.Sprintf("%s%s%s", prefix, values[0], suffix)
-	return query.Where(fmt.Sprintf("%s ILIKE ?", column), pattern)
+	return fmt.Sprintf("%s ILIKE ?", column), []interface{}{pattern}, true
 }
 
 func applySearch(query *gorm.DB, params QueryParams, cfg QueryConfig) *gorm.DB {
@@ -264,6 +572,16 @@ func buildLink(path string, values url.Values, page, limit int) *string {
 	return &link
 }
 
+func buildCursorLink(path string, values url.Values, cursor string, limit int) *string {
+	clone := cloneValues(values)
+	clone.Del("page")
+	clone.Del("offset")
+	clone.Set("cursor", cursor)
+	clone.Set("limit", strconv.Itoa(limit))
+	link := fmt.Sprintf("%s?%s", path, clone.Encode())
+	return &link
+}
+
 func cloneValues(values url.Values) url.Values {
 	clone := url.Values{}
 	for key, vals := range values {
@@ -11,8 +11,11 @@ package utils
 *   2. parse comma-delimited sort/order sequences and map each to sortable columns,
 *   3. sanitize search text/fields so only whitelisted columns participate,
 *   4. iterate remaining query keys, interpret operator prefixes (eq/gt/in/etc),
-*      and emit structured FilterParams,
-*   5. return QueryParams so downstream builders can apply GORM clauses safely.
+*      and emit structured FilterParams; parse the filter= expression grammar
+*      (see filterExpression.go) into FilterExpr,
+*   5. validate an opaque keyset cursor against the resolved sort and pick the
+*      total-count strategy (exact / estimated / none),
+*   6. return QueryParams so downstream builders can apply GORM clauses safely.
 *
  */
 
@@ -21,6 +24,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -41,6 +45,19 @@ var supportedFilterOperators = map[string]struct{}{
 	"notnull":  {},
 }
 
+// CountMode selects how FetchWithPagination computes QueryMeta.Total.
+type CountMode string
+
+const (
+	// CountExact runs COUNT(*) over the filtered query.
+	CountExact CountMode = "exact"
+	// CountEstimated counts up to estimatedCountCap rows exactly and falls back
+	// to the pg_class row estimate (unfiltered listings) or the cap beyond it.
+	CountEstimated CountMode = "estimated"
+	// CountNone skips counting; has_next comes from a one-row lookahead.
+	CountNone CountMode = "none"
+)
+
 // QueryConfig describes how query params should be parsed for a resource.
 type QueryConfig struct {
 	DefaultLimit          int
@@ -51,6 +68,27 @@ type QueryConfig struct {
 	SearchableFields      map[string]string
 	DefaultSearchFields   []string
 	FieldDefaultOperators map[string]string
+
+	// KeysetFields marks sortable fields that can seed a keyset cursor, mapped
+	// to the Postgres type cursor values are cast to (e.g. "timestamptz").
+	// Sorting on any other field falls back to OFFSET pagination.
+	KeysetFields map[string]string
+	// KeysetTieBreaker names a unique, NOT NULL keyset field appended to every
+	// keyset sort so rows with equal sort values page deterministically.
+	KeysetTieBreaker string
+	// DefaultCountMode applies when the request has no count= param (exact if empty).
+	DefaultCountMode CountMode
+	// EstimateTable is the relation whose pg_class.reltuples backs CountEstimated.
+	EstimateTable string
+
+	// TimeFields marks filterable fields whose filter= values accept relative
+	// times (now, now-7d, today).
+	TimeFields map[string]bool
+	// ArrayFields maps filter= fields onto text[] columns (has/hasany/hasall).
+	ArrayFields map[string]string
+	// JSONFields maps filter= fields onto jsonb columns addressed by dotted
+	// path (metadata.source.lang).
+	JSONFields map[string]string
 }
 
 type PaginationParams struct {
@@ -76,6 +114,12 @@ type QueryParams struct {
 	Filters      []FilterParam
 	Search       string
 	SearchFields []string
+	// Expression is the parsed filter= expression, ANDed with Filters.
+	Expression *FilterExpr
+	// Cursor is the raw keyset cursor; CursorValues its decoded sort tuple.
+	Cursor       string
+	CursorValues []*string
+	CountMode    CountMode
 }
 
 // ParseQueryParams converts incoming query parameters into structured QueryParams
@@ -159,6 +203,43 @@ func ParseQueryParams(c *gin.Context, cfg QueryConfig) (QueryParams, error) {
 	}
 	params.Filters = filters
 
+	if raw := strings.TrimSpace(c.Query("filter")); raw != "" {
+		expr, err := ParseFilterExpression(raw, cfg, time.Now())
+		if err != nil {
+			return QueryParams{}, fmt.Errorf("invalid filter expression: %w", err)
+		}
+		params.Expression = expr
+	}
+
+	params.CountMode = cfg.DefaultCountMode
+	if params.CountMode == "" {
+		params.CountMode = CountExact
+	}
+	if countStr := strings.ToLower(strings.TrimSpace(c.Query("count"))); countStr != "" {
+		switch mode := CountMode(countStr); mode {
+		case CountExact, CountEstimated, CountNone:
+			params.CountMode = mode
+		default:
+			return QueryParams{}, fmt.Errorf("invalid count parameter")
+		}
+	}
+
+	if cursor := strings.TrimSpace(c.Query("cursor")); cursor != "" {
+		plan, ok := resolveKeysetPlan(params.Sort, cfg)
+		if !ok {
+			return QueryParams{}, fmt.Errorf("cursor pagination is not supported for the requested sort")
+		}
+		values, err := DecodeKeysetCursor(cursor, plan.signature(), len(plan.columns))
+		if err != nil {
+			return QueryParams{}, err
+		}
+		// A cursor replaces page/offset: the next page starts after its tuple.
+		params.Cursor = cursor
+		params.CursorValues = values
+		params.Pagination.Page = 1
+		params.Pagination.Offset = 0
+	}
+
 	return params, nil
 }
 
@@ -199,6 +280,9 @@ func parseFilters(c *gin.Context, cfg QueryConfig) ([]FilterParam, error) {
 		"order":         {},
 		"search":        {},
 		"search_fields": {},
+		"cursor":        {},
+		"count":         {},
+		"filter":        {},
 	}
 
 	var filters []FilterParam
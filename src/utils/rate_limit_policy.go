@@ -0,0 +1,253 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RateLimitKey names what a policy counts against.
+type RateLimitKey string
+
+const (
+	RateLimitKeyUser    RateLimitKey = "user"    // verified user (JWT or admin principal)
+	RateLimitKeySession RateLimitKey = "session" // anonymous session / app installation
+	RateLimitKeyIP      RateLimitKey = "ip"      // client IP, as resolved by gin's trusted proxies
+	RateLimitKeyTenant  RateLimitKey = "tenant"  // whole-tenant budget
+)
+
+// RateLimitSessionContextKey lets a handler that reads the session from its
+// body hand it to EnforceRateLimit.
+const RateLimitSessionContextKey = "rate_limit_session"
+
+// RateLimitPolicy is one declarative limit: Limit requests (the burst), refilled
+// evenly over Window.
+type RateLimitPolicy struct {
+	Name   string
+	Limit  int
+	Window time.Duration
+	// Keys are tried in order; the first one the request carries identifies
+	// the caller. A request carrying none of them is not limited by this
+	// policy (its route's authentication decides instead). Session ids are
+	// client-chosen, so a caller identified by session also spends the IP
+	// bucket when the policy lists IP.
+	Keys []RateLimitKey
+	// WritesOnly exempts GET, HEAD and OPTIONS.
+	WritesOnly bool
+}
+
+// RateLimitPolicies is the checked-in table of consumer and admin limits.
+// Routes and handlers refer to policies by name.
+func RateLimitPolicies() []RateLimitPolicy {
+	caller := []RateLimitKey{RateLimitKeyUser, RateLimitKeySession, RateLimitKeyIP}
+	user := []RateLimitKey{RateLimitKeyUser}
+	return []RateLimitPolicy{
+		{Name: "interactions.create", Limit: 120, Window: time.Minute, Keys: caller},
+		// Each search runs several full-text queries and, in hybrid mode, an
+		// embedding call.
+		{Name: "search.query", Limit: 30, Window: time.Minute, Keys: caller},
+		{Name: "comments.create", Limit: 5, Window: time.Minute, Keys: user},
+		{Name: "comments.react", Limit: 60, Window: time.Minute, Keys: user},
+		{Name: "moderation.reports.create", Limit: 30, Window: time.Hour, Keys: caller},
+		{Name: "content.submit", Limit: 10, Window: time.Hour, Keys: user},
+		{Name: "content.transcribe", Limit: 5, Window: time.Hour, Keys: user},
+		{Name: "data_exports.create", Limit: 3, Window: 24 * time.Hour, Keys: user},
+		{Name: "digests.preview", Limit: 20, Window: time.Hour, Keys: user},
+		// Each new or re-saved unconfirmed address mails a confirmation link.
+		{Name: "digests.confirm", Limit: 5, Window: time.Hour, Keys: user},
+		// Public and unauthenticated: every intent costs the hub a verification
+		// request to the callback.
+		{Name: "websub.subscribe", Limit: 30, Window: time.Hour, Keys: []RateLimitKey{RateLimitKeyIP}},
+		// Charged per event, keyed by the BFF-supplied rate key: batches of
+		// ~20 events allow ~30 flushes a minute.
+		{Name: "telemetry.ingest", Limit: 600, Window: time.Minute},
+		{Name: "admin.writes", Limit: 300, Window: time.Minute, Keys: user, WritesOnly: true},
+	}
+}
+
+func FindRateLimitPolicy(name string) (RateLimitPolicy, bool) {
+	for _, policy := range RateLimitPolicies() {
+		if policy.Name == name {
+			return policy, true
+		}
+	}
+	return RateLimitPolicy{}, false
+}
+
+func MustRateLimitPolicy(name string) RateLimitPolicy {
+	policy, ok := FindRateLimitPolicy(name)
+	if !ok {
+		panic(fmt.Sprintf("rate limit policy %q is not declared", name))
+	}
+	return policy
+}
+
+var memoryRateLimiter = NewMemoryRateLimiter()
+
+// rateLimiterFor picks the backend: Postgres (shared across replicas) when
+// the request has a database handle, unless RATE_LIMIT_BACKEND=memory.
+var rateLimiterFor = func(c *gin.Context) RateLimiter {
+	if strings.ToLower(strings.TrimSpace(os.Getenv("RATE_LIMIT_BACKEND"))) != "memory" {
+		if value, ok := c.Get("db"); ok {
+			if db, ok := value.(*gorm.DB); ok && db != nil {
+				return PostgresRateLimiter{DB: db}
+			}
+		}
+	}
+	return memoryRateLimiter
+}
+
+// RateLimitMiddleware applies a named policy before the handler.
+func RateLimitMiddleware(name string) gin.HandlerFunc {
+	policy := MustRateLimitPolicy(name)
+	return func(c *gin.Context) {
+		if policy.WritesOnly && (c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead || c.Request.Method == http.MethodOptions) {
+			c.Next()
+			return
+		}
+		if !EnforceRateLimit(c, policy.Name, 1) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// EnforceRateLimit spends cost tokens of the named policy for the caller. It
+// sets the RateLimit-* headers and, when the bucket is empty, writes 429 with
+// Retry-After, aborts, and returns false. Handlers use it directly when the
+// policy depends on the request body (a comment vs. a like).
+func EnforceRateLimit(c *gin.Context, name string, cost int) bool {
+	policy := MustRateLimitPolicy(name)
+	kind, value := rateLimitCaller(c, policy.Keys)
+	if kind == "" {
+		return true
+	}
+	if kind == RateLimitKeySession && rateLimitPolicyHasKey(policy, RateLimitKeyIP) {
+		// Rotating session ids must not reset an anonymous caller's budget.
+		if ip := c.ClientIP(); ip != "" && !enforceRateLimit(c, policy, string(RateLimitKeyIP)+"\x00"+ip, cost) {
+			return false
+		}
+	}
+	return enforceRateLimit(c, policy, string(kind)+"\x00"+value, cost)
+}
+
+func rateLimitPolicyHasKey(policy RateLimitPolicy, key RateLimitKey) bool {
+	for _, kind := range policy.Keys {
+		if kind == key {
+			return true
+		}
+	}
+	return false
+}
+
+// TakeRateLimitKey spends cost tokens of the named policy for a
+// caller-supplied key, for callers identified by something other than user,
+// session or IP. It sets the RateLimit-* headers (and Retry-After when the
+// bucket is empty) but leaves the 429 body to the caller, whose wire format
+// may predate the shared policies.
+func TakeRateLimitKey(c *gin.Context, name, key string, cost int) bool {
+	policy := MustRateLimitPolicy(name)
+	if key == "" {
+		return true
+	}
+	return takeRateLimit(c, policy, "key\x00"+key, cost)
+}
+
+func enforceRateLimit(c *gin.Context, policy RateLimitPolicy, caller string, cost int) bool {
+	if takeRateLimit(c, policy, caller, cost) {
+		return true
+	}
+	c.AbortWithStatusJSON(http.StatusTooManyRequests, HTTPError{Code: http.StatusTooManyRequests, Message: "Rate limit exceeded; retry later"})
+	return false
+}
+
+func takeRateLimit(c *gin.Context, policy RateLimitPolicy, caller string, cost int) bool {
+	// Identities, sessions and IPs are stored only as a digest.
+	digest := sha256.Sum256([]byte(requestTenant(c) + "\x00" + caller))
+	key := policy.Name + ":" + hex.EncodeToString(digest[:20])
+	decision, err := rateLimiterFor(c).Take(c.Request.Context(), key, policy.Limit, policy.Window, cost)
+	if err != nil {
+		// Fail open: a limiter outage must not take writes down with it.
+		log.Printf("rate limit policy=%s unavailable, allowing: %v", policy.Name, err)
+		return true
+	}
+	setRateLimitHeaders(c, policy, decision)
+	if decision.Allowed {
+		return true
+	}
+	c.Header("Retry-After", strconv.Itoa(ceilSeconds(decision.RetryAfter)))
+	return false
+}
+
+// setRateLimitHeaders writes the IETF RateLimit-* fields. When several
+// policies apply to one request the most constrained one is reported.
+func setRateLimitHeaders(c *gin.Context, policy RateLimitPolicy, d RateLimitDecision) {
+	if current, err := strconv.Atoi(c.Writer.Header().Get("RateLimit-Remaining")); err == nil && current < d.Remaining {
+		return
+	}
+	c.Header("RateLimit-Limit", strconv.Itoa(d.Limit))
+	c.Header("RateLimit-Remaining", strconv.Itoa(d.Remaining))
+	c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(d.Reset)))
+	c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", policy.Limit, ceilSeconds(policy.Window)))
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Max(0, math.Ceil(d.Seconds())))
+}
+
+func rateLimitCaller(c *gin.Context, keys []RateLimitKey) (RateLimitKey, string) {
+	for _, kind := range keys {
+		var value string
+		switch kind {
+		case RateLimitKeyUser:
+			value = strings.TrimSpace(c.GetString("user_id"))
+			if value == "" {
+				if principal, ok := GetAdminPrincipal(c); ok {
+					value = principal.UserID
+					if value == "" {
+						value = principal.Email
+					}
+				}
+			}
+		case RateLimitKeySession:
+			value = strings.TrimSpace(c.GetString(RateLimitSessionContextKey))
+			if value == "" {
+				value = strings.TrimSpace(c.GetHeader("X-Session-ID"))
+			}
+			if value == "" {
+				value = strings.TrimSpace(c.Query("session_id"))
+			}
+		case RateLimitKeyIP:
+			value = c.ClientIP()
+		case RateLimitKeyTenant:
+			value = requestTenant(c)
+		}
+		if value != "" {
+			return kind, value
+		}
+	}
+	return "", ""
+}
+
+// requestTenant is the tenant a request acts for: the tenant_id set by the
+// auth middleware, the admin principal's tenant, or DEFAULT_TENANT_ID for
+// requests that have not been authenticated (yet).
+func requestTenant(c *gin.Context) string {
+	if tenant := strings.TrimSpace(c.GetString("tenant_id")); tenant != "" {
+		return tenant
+	}
+	if principal, ok := GetAdminPrincipal(c); ok && principal.TenantID != "" {
+		return principal.TenantID
+	}
+	return GetDefaultTenantID()
+}
@@ -6,6 +6,9 @@ type ResponseMessage struct {
 	Links   any    `json:"links,omitempty"`
 	Code    int    `json:"code"`
 	Message string `json:"message"`
+	// Warnings are non-fatal notes about the request, e.g. content the
+	// sanitizer changed on save.
+	Warnings any `json:"warnings,omitempty"`
 }
 
 type HTTPError struct {
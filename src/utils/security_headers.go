@@ -0,0 +1,105 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// apiContentSecurityPolicy is sent with every response: API bodies are
+	// data, never documents, so nothing may load or frame them.
+	apiContentSecurityPolicy = "default-src 'none'; frame-ancestors 'none'"
+	// documentContentSecurityPolicy covers syndication and HTML a browser may
+	// render (RSS/Atom, feed JSON, transcripts, digest previews): item images
+	// and enclosures load, inline styles apply, scripts and forms never run.
+	documentContentSecurityPolicy = "default-src 'none'; img-src https: data:; media-src https:; style-src 'unsafe-inline'; base-uri 'none'; form-action 'none'; frame-ancestors 'none'; sandbox"
+)
+
+// SecurityHeaderPolicy is the header set SecurityHeadersMiddleware writes.
+type SecurityHeaderPolicy struct {
+	ReferrerPolicy string
+	// HSTSMaxAge is in seconds; 0 disables Strict-Transport-Security.
+	HSTSMaxAge            int
+	HSTSIncludeSubdomains bool
+	APICSP                string
+	DocumentCSP           string
+}
+
+// LoadSecurityHeaderPolicy reads SECURITY_REFERRER_POLICY,
+// SECURITY_HSTS_MAX_AGE, SECURITY_HSTS_INCLUDE_SUBDOMAINS, SECURITY_API_CSP
+// and SECURITY_DOCUMENT_CSP.
+func LoadSecurityHeaderPolicy() (SecurityHeaderPolicy, error) {
+	policy := SecurityHeaderPolicy{
+		ReferrerPolicy: "strict-origin-when-cross-origin",
+		HSTSMaxAge:     31536000,
+		APICSP:         apiContentSecurityPolicy,
+		DocumentCSP:    documentContentSecurityPolicy,
+	}
+	if value := strings.TrimSpace(os.Getenv("SECURITY_REFERRER_POLICY")); value != "" {
+		policy.ReferrerPolicy = value
+	}
+	if value := strings.TrimSpace(os.Getenv("SECURITY_HSTS_MAX_AGE")); value != "" {
+		maxAge, err := strconv.Atoi(value)
+		if err != nil || maxAge < 0 {
+			return policy, fmt.Errorf("SECURITY_HSTS_MAX_AGE must be a non-negative number of seconds")
+		}
+		policy.HSTSMaxAge = maxAge
+	}
+	if value := strings.TrimSpace(os.Getenv("SECURITY_HSTS_INCLUDE_SUBDOMAINS")); value != "" {
+		include, err := strconv.ParseBool(value)
+		if err != nil {
+			return policy, fmt.Errorf("SECURITY_HSTS_INCLUDE_SUBDOMAINS must be true or false")
+		}
+		policy.HSTSIncludeSubdomains = include
+	}
+	if value := strings.TrimSpace(os.Getenv("SECURITY_API_CSP")); value != "" {
+		policy.APICSP = value
+	}
+	if value := strings.TrimSpace(os.Getenv("SECURITY_DOCUMENT_CSP")); value != "" {
+		policy.DocumentCSP = value
+	}
+	return policy, nil
+}
+
+const documentCSPContextKey = "security_document_csp"
+
+// SecurityHeadersMiddleware writes the standard headers on every response.
+// HSTS is only sent over HTTPS (directly or via X-Forwarded-Proto from the
+// load balancer); browsers ignore it on plain HTTP anyway.
+func SecurityHeadersMiddleware(policy SecurityHeaderPolicy) gin.HandlerFunc {
+	hsts := ""
+	if policy.HSTSMaxAge > 0 {
+		hsts = "max-age=" + strconv.Itoa(policy.HSTSMaxAge)
+		if policy.HSTSIncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+	}
+	return func(c *gin.Context) {
+		c.Set(documentCSPContextKey, policy.DocumentCSP)
+		header := c.Writer.Header()
+		header.Set("X-Content-Type-Options", "nosniff")
+		header.Set("Referrer-Policy", policy.ReferrerPolicy)
+		header.Set("Content-Security-Policy", policy.APICSP)
+		if hsts != "" && (c.Request.TLS != nil || strings.EqualFold(c.GetHeader("X-Forwarded-Proto"), "https")) {
+			header.Set("Strict-Transport-Security", hsts)
+		}
+		c.Next()
+	}
+}
+
+// DocumentCSP swaps in the document Content-Security-Policy for routes whose
+// bodies a browser may render.
+func DocumentCSP() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		csp := c.GetString(documentCSPContextKey)
+		if csp == "" {
+			csp = documentContentSecurityPolicy
+		}
+		c.Header("Content-Security-Policy", csp)
+		c.Next()
+	}
+}
@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestSecurityHeadersMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("SECURITY_HSTS_INCLUDE_SUBDOMAINS", "true")
+	policy, err := LoadSecurityHeaderPolicy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := gin.New()
+	router.Use(SecurityHeadersMiddleware(policy))
+	router.GET("/api/v1/content/:id", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{}) })
+	router.GET("/api/v1/feed/rss.xml", DocumentCSP(), func(c *gin.Context) { c.Data(http.StatusOK, "application/rss+xml", []byte("<rss/>")) })
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/content/a", nil))
+	for header, want := range map[string]string{
+		"X-Content-Type-Options":    "nosniff",
+		"Referrer-Policy":           "strict-origin-when-cross-origin",
+		"Content-Security-Policy":   apiContentSecurityPolicy,
+		"Strict-Transport-Security": "",
+	} {
+		if got := rec.Header().Get(header); got != want {
+			t.Errorf("%s = %q, want %q", header, got, want)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/feed/rss.xml", nil)
+	req.Header.Set("X-Forwarded-Proto", "https")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if got := rec.Header().Get("Content-Security-Policy"); got != documentContentSecurityPolicy {
+		t.Errorf("syndication CSP = %q", got)
+	}
+	if got := rec.Header().Get("Strict-Transport-Security"); got != "max-age=31536000; includeSubDomains" {
+		t.Errorf("HSTS over https = %q", got)
+	}
+
+	t.Setenv("SECURITY_HSTS_MAX_AGE", "-1")
+	if _, err := LoadSecurityHeaderPolicy(); err == nil {
+		t.Fatal("a negative HSTS max-age must be refused")
+	}
+}
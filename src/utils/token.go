@@ -0,0 +1,17 @@
+package utils
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// NewOpaqueToken returns 24 random bytes, hex encoded, for bearer-style URL
+// tokens (share links, unsubscribe and confirmation links). The token is the
+// credential, so it carries no structure.
+func NewOpaqueToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"content-management-system/src/contentstage"
+	"content-management-system/src/controllers"
+	"content-management-system/src/intelligence"
+	"content-management-system/src/lifecycle"
+	"content-management-system/src/pipeline"
+	"content-management-system/src/supply"
+
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// backgroundWorkers is every worker a CMS process can run, by the name
+// CMS_WORKERS and CMS_WORKERS_DISABLED select it with. The order is the
+// start order.
+func backgroundWorkers(db *gorm.DB) []lifecycle.Worker {
+	return []lifecycle.Worker{
+		// Self-heal classification drift: classify any embedded-but-unclassified
+		// NEWS items (LLM outages, bulk re-embeds, taxonomy wipes) and rebuild the
+		// precompute News snapshot when done. Runs in the background.
+		{Name: "classification-backfill", Start: func(ctx context.Context) { controllers.StartClassificationBackfill(ctx, db) }},
+		// Preferences Autopilot scheduler (stage 7) — REPLACES the bare topics
+		// heartbeat. Disabled tenants get the incumbent catalog maintenance exactly;
+		// enabled tenants get the bounded, ledgered runner with a health verdict.
+		{Name: "preference-autopilot", Start: func(ctx context.Context) { controllers.StartPreferenceAutopilotHeartbeat(ctx, db) }},
+		// Precompute missing topics.related_ids (stories predating the write-time
+		// related feature) so feed reads never fall back to per-slide centroid kNN.
+		{Name: "related-backfill", Start: func(ctx context.Context) { controllers.StartRelatedBackfill(ctx, db) }},
+		// News Circulation automation heartbeat — periodically recompute source
+		// cadence recommendations (and auto-apply inside guardrails) for tenants that
+		// opted in, so the news pipeline self-tunes without manual admin triggers.
+		{Name: "circulation-automation", Start: func(ctx context.Context) { controllers.StartCirculationAutomation(ctx, db) }},
+		// Ranking/Intelligence refresh heartbeat — recomputes stale/nudged media
+		// value scores in bounded batches (stage 4; scheduled + event-nudged
+		// triggers in one pass, on-demand scoring happens inside circulation).
+		{Name: "intelligence-refresh", Start: func(ctx context.Context) { intelligence.StartRefreshLoop(ctx, db) }},
+		// Media Circulation Autopilot heartbeat (stage 5) — fires deterministic
+		// runs for tenants whose autopilot interval has elapsed; Observe tenants
+		// get shadow (dry-run) ledgers, Safe Auto tenants get bounded execution.
+		{Name: "media-circulation-autopilot", Start: func(ctx context.Context) { controllers.StartMediaCirculationAutopilotHeartbeat(ctx, db) }},
+		{Name: "redundancy-hygiene", Start: func(ctx context.Context) { controllers.StartRedundancyHygieneHeartbeat(ctx, db) }},
+		{Name: "enrichment-autopilot", Start: func(ctx context.Context) { controllers.StartEnrichmentAutopilotHeartbeat(ctx, db) }},
+		{Name: "pipeline-autopilot", Start: func(ctx context.Context) { controllers.StartPipelineAutopilotHeartbeat(ctx, db) }},
+		// Media Studio Clearance Autopilot (stage 6) — chain-first heartbeat: fires
+		// after the lead executes atomize_now, plus a slower interval sweep-up.
+		{Name: "media-studio-autopilot", Start: func(ctx context.Context) { controllers.StartMediaStudioAutopilotHeartbeat(ctx, db) }},
+		// System Health / Incident Autopilot — CMS-owned probes + incident ledger.
+		{Name: "system-health-autopilot", Start: func(ctx context.Context) { controllers.StartSystemHealthAutopilotHeartbeat(ctx, db) }},
+		// Feed Integrity base system — deterministic CMS-edge verification, not an Autopilot.
+		{Name: "feed-integrity", Start: func(ctx context.Context) { controllers.StartFeedIntegrityHeartbeat(ctx, db) }},
+		// WebSub hub for saved feeds — verifies subscriber intents, expires leases
+		// and pushes signed content deltas from the READY-transition outbox.
+		{Name: "websub-hub", Start: func(ctx context.Context) { controllers.StartWebSubHeartbeat(ctx, db) }, Health: "websub_hub_ready", Healthy: controllers.WebSubWorkerHealthy, LastHeartbeat: controllers.WebSubWorkerLastHeartbeat},
+		// Personal data exports — builds queued archives and clears expired ones.
+		{Name: "personal-data-exports", Start: func(ctx context.Context) { controllers.StartPersonalDataExportWorker(ctx, db) }, Health: "personal_data_exports_ready", Healthy: controllers.PersonalDataExportWorkerHealthy, LastHeartbeat: controllers.PersonalDataExportWorkerLastHeartbeat},
+		// Scheduled digests — sends due daily/weekly digests through DIGEST_SENDER.
+		{Name: "digests", Start: func(ctx context.Context) { controllers.StartDigestWorker(ctx, db) }, Health: "digests_ready", Healthy: controllers.DigestWorkerHealthy, LastHeartbeat: controllers.DigestWorkerLastHeartbeat},
+		// Editorial scheduler — flips pages/posts at publish_at / unpublish_at.
+		{Name: "editorial-scheduler", Start: func(ctx context.Context) { controllers.StartEditorialScheduler(ctx, db) }, Health: "editorial_scheduler_ready", Healthy: controllers.EditorialSchedulerHealthy, LastHeartbeat: controllers.EditorialSchedulerLastHeartbeat},
+		// Retention Autopilot — persisted database-pressure sampling and shadow
+		// compact-News proposals. V1 cannot mutate canonical content.
+		{Name: "retention", Start: func(ctx context.Context) { controllers.StartRetentionHeartbeat(ctx, db) }},
+		// Real User Experience — Observe scheduler: rolls up closed telemetry buckets
+		// and evaluates deterministic surface verdicts for tenants that enabled it.
+		{Name: "experience", Start: func(ctx context.Context) { controllers.StartExperienceHeartbeat(ctx, db) }},
+		// Embedding & Model Lifecycle (stage 10) — vector-space audit scheduler.
+		// Observation only; disabled by default until an admin enables it.
+		{Name: "embedding-lifecycle", Start: func(ctx context.Context) { controllers.StartEmbeddingLifecycleHeartbeat(ctx, db) }},
+		{Name: "ai-spend-governor", Start: func(ctx context.Context) { controllers.StartAISpendGovernorHeartbeat(ctx, db) }},
+		// Wahb Operator — resumes only expired, persisted read investigations after
+		// a fresh IAM access snapshot; no browser session or user bearer is replayed.
+		{Name: "operator-investigations", Start: func(ctx context.Context) { controllers.StartOperatorInvestigationHeartbeat(ctx, db) }},
+		// Read-only Operator schedules are separately leased and re-authorized on
+		// every tick. They never carry an approved action or browser credential.
+		{Name: "operator-schedules", Start: func(ctx context.Context) { controllers.StartOperatorScheduleHeartbeat(ctx, db) }},
+		// Approved Operator plans enter the CMS-owned durable work ledger. The
+		// worker rechecks IAM and tenant policy before it claims an immutable plan.
+		{Name: "operator-plans", Start: func(ctx context.Context) { controllers.StartOperatorPlanWorker(ctx, db) }},
+		// Source-run evidence reduction is CMS-owned and read-only with respect to
+		// providers and queues. It replays immutable receipts after commit; it does
+		// not enable source dispatch or any new provider effect.
+		{Name: "source-run-projection", Start: func(ctx context.Context) { supply.StartProjectionWorker(ctx, db) }, Health: "source_run_projection_ready", Healthy: supply.ProjectionWorkerHealthy, LastHeartbeat: supply.ProjectionWorkerLastHeartbeat},
+		// Deferred upstream identities have their own immutable disposition and
+		// expiry projection. This worker never materializes provider content.
+		{Name: "upstream-observation", Start: func(ctx context.Context) { supply.StartUpstreamObservationWorker(ctx, db) }, Health: "upstream_observation_ready", Healthy: supply.UpstreamObservationWorkerHealthy, LastHeartbeat: supply.UpstreamObservationWorkerLastHeartbeat},
+		// Expired claims converge without provider I/O: pre-effect dispatch can be
+		// redelivered while started units enter verification, never blind retry.
+		{Name: "source-run-recovery", Start: func(ctx context.Context) { supply.StartRecoveryWorker(ctx, db) }, Health: "source_run_recovery_ready", Healthy: supply.RecoveryWorkerHealthy, LastHeartbeat: supply.RecoveryWorkerLastHeartbeat},
+		// Reconciliation independently repairs interrupted verification-task
+		// creation. It cannot dispatch or repeat a source/provider effect.
+		{Name: "source-run-reconciler", Start: func(ctx context.Context) { supply.StartReconcilerWorker(ctx, db) }, Health: "source_run_reconciler_ready", Healthy: supply.ReconcilerWorkerHealthy, LastHeartbeat: supply.ReconcilerWorkerLastHeartbeat},
+		// Only CMS-owned, static Supply actions are claimable here. Owner-service
+		// handoffs remain unavailable until their typed capability protocols exist.
+		{Name: "supply-actions", Start: func(ctx context.Context) { supply.StartSupplyActionWorker(ctx, db) }, Health: "media_supply_action_ready", Healthy: supply.SupplyActionWorkerHealthy, LastHeartbeat: supply.SupplyActionWorkerLastHeartbeat},
+		// Static owner readiness is cached outside request paths. It can deny only
+		// new external handoffs; recovery evidence, cancellation, and verification
+		// retain authority when an owner is unavailable. Internal claim/begin
+		// endpoints read it too, so API-only processes observe owners as well.
+		{Name: "supply-owner-readiness", API: true, Start: func(ctx context.Context) { supply.StartSupplyOwnerReadinessObserver(ctx) }},
+		// Pipeline repair is verification/recovery only; Aggregation remains the
+		// sole owner of its declared stage effect.
+		{Name: "pipeline-repair", Start: func(ctx context.Context) { pipeline.StartWorker(ctx, db) }, Health: "pipeline_repair_ready", Healthy: pipeline.WorkerHealthy, LastHeartbeat: pipeline.WorkerLastHeartbeat},
+		{Name: "content-stage", Start: func(ctx context.Context) { contentstage.StartWorker(ctx, db, controllers.ClassifyContentStage) }, Health: "content_stage_ready", Healthy: contentstage.WorkerHealthy, LastHeartbeat: contentstage.WorkerLastHeartbeat},
+		{Name: "artifact-coverage", Start: func(ctx context.Context) { controllers.StartArtifactCoverageWorker(ctx, db) }, Health: "artifact_coverage_ready", Healthy: controllers.ArtifactCoverageWorkerHealthy, LastHeartbeat: controllers.ArtifactCoverageWorkerLastHeartbeat},
+		{Name: "atomization-work-verifier", Start: func(ctx context.Context) { controllers.StartAtomizationWorkVerifier(ctx, db) }, Health: "atomization_work_ready", Healthy: controllers.AtomizationWorkVerifierHealthy, LastHeartbeat: controllers.AtomizationWorkVerifierLastHeartbeat},
+		{Name: "studio-clearance", Start: func(ctx context.Context) { controllers.StartStudioClearanceWorker(ctx, db) }, Health: "studio_clearance_ready", Healthy: controllers.StudioClearanceWorkerHealthy, LastHeartbeat: controllers.StudioClearanceWorkerLastHeartbeat},
+		// Admission records due work in CMS only. Aggregation later claims the
+		// CMS-issued unit; this scheduler never selects a queue or provider itself.
+		{Name: "source-run-scheduler", Start: func(ctx context.Context) { supply.StartSourceRunScheduler(ctx, db) }, Health: "source_run_scheduler_ready", Healthy: supply.SourceRunSchedulerHealthy, LastHeartbeat: supply.SourceRunSchedulerLastHeartbeat},
+		// Supply Continuity records CMS-derived attention episodes for explicitly
+		// owned Media-source tenants. It has no source admission, dispatch, queue,
+		// provider, retry, or Operator-plan authority; its only possible effect is
+		// one separately promotion-gated native Supply action.
+		{Name: "media-supply-evaluation", Start: func(ctx context.Context) { controllers.StartMediaSupplyEvaluationHeartbeat(ctx, db) }, Health: "media_supply_evaluator_ready", Healthy: supply.MediaSupplyEvaluatorWorkerHealthy, LastHeartbeat: supply.MediaSupplyEvaluatorWorkerLastHeartbeat},
+		// Shadow qualification is a CMS-only read loop. It cannot render a Console
+		// surface, call a model, build a plan, or promote the launch state.
+		{Name: "operator-shadow", Start: func(ctx context.Context) { controllers.StartOperatorShadowHeartbeat(ctx, db) }},
+	}
+}